@@ -2,7 +2,7 @@ HOSTNAME="$(cat /proc/sys/kernel/hostname)"
 INDEX="${HOSTNAME##*-}"
 MASTER="$(redis-cli -h {{.ServiceName}} -p 26379 sentinel get-master-addr-by-name argocd | grep -E '[0-9]{1,3}\.[0-9]{1,3}\.[0-9]{1,3}\.[0-9]{1,3}')"
 MASTER_GROUP="argocd"
-QUORUM="2"
+QUORUM="{{.Quorum}}"
 REDIS_CONF=/data/conf/redis.conf
 REDIS_PORT=6379
 SENTINEL_CONF=/data/conf/sentinel.conf
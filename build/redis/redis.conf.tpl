@@ -1,11 +1,15 @@
 dir "/data"
 port 6379
-maxmemory 0
-maxmemory-policy volatile-lru
+maxmemory {{.MaxMemory}}
+maxmemory-policy {{.MaxMemoryPolicy}}
 min-replicas-max-lag 5
 min-replicas-to-write 1
 rdbchecksum yes
 rdbcompression yes
 repl-diskless-sync yes
+{{- range .Save}}
+save {{.Seconds}} {{.Changes}}
+{{- else}}
 save ""
+{{- end}}
 protected-mode no
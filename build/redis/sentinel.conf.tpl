@@ -1,5 +1,5 @@
 dir "/data"
-    sentinel down-after-milliseconds argocd 10000
-    sentinel failover-timeout argocd 180000
+    sentinel down-after-milliseconds argocd {{.DownAfterMilliseconds}}
+    sentinel failover-timeout argocd {{.FailoverTimeout}}
     maxclients 10000
     sentinel parallel-syncs argocd 5
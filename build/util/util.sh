@@ -151,8 +151,47 @@ load_backup () {
     argocd-util import - < ${BACKUP_EXPORT_LOCATION}
 }
 
+scan_known_hosts () {
+    echo "scanning ssh known hosts"
+    KNOWN_HOSTS_CONFIGMAP=$1
+    shift
+    KNOWN_HOSTS_LOCATION=/tmp/ssh_known_hosts
+    : > ${KNOWN_HOSTS_LOCATION}
+    for host in "$@"; do
+        case ${host} in
+            \[*\]:*|*:*:*)
+                # [ipv6]:port or bare ipv6
+                name=${host%]:*}; name=${name#[}; port=${host##*]:}
+                [ "${name}" = "${host}" ] && port=22
+                ;;
+            *:*)
+                name=${host%:*}; port=${host##*:}
+                ;;
+            *)
+                name=${host}; port=22
+                ;;
+        esac
+        ssh-keyscan -T 10 -p ${port} ${name} >> ${KNOWN_HOSTS_LOCATION} || echo "unable to scan ${host}"
+    done
+    push_known_hosts
+    echo "ssh known hosts scan complete"
+}
+
+push_known_hosts () {
+    echo "pushing ssh known hosts to configmap ${KNOWN_HOSTS_CONFIGMAP}"
+    SERVICE_ACCOUNT_PATH=/var/run/secrets/kubernetes.io/serviceaccount
+    NAMESPACE=`cat ${SERVICE_ACCOUNT_PATH}/namespace`
+    TOKEN=`cat ${SERVICE_ACCOUNT_PATH}/token`
+    KNOWN_HOSTS=$(sed -e 's/\\/\\\\/g' -e 's/"/\\"/g' ${KNOWN_HOSTS_LOCATION} | awk '{printf "%s\\n", $0}')
+    curl -sSf --cacert ${SERVICE_ACCOUNT_PATH}/ca.crt -X PATCH \
+        -H "Authorization: Bearer ${TOKEN}" \
+        -H "Content-Type: application/merge-patch+json" \
+        --data "{\"data\":{\"ssh_known_hosts\":\"${KNOWN_HOSTS}\"}}" \
+        https://kubernetes.default.svc/api/v1/namespaces/${NAMESPACE}/configmaps/${KNOWN_HOSTS_CONFIGMAP} > /dev/null
+}
+
 usage () {
-    echo "usage: ${BACKUP_SCRIPT} export|import"
+    echo "usage: ${BACKUP_SCRIPT} export|import|scan-known-hosts"
 }
 
 case  ${BACKUP_ACTION} in
@@ -162,6 +201,10 @@ case  ${BACKUP_ACTION} in
     "import")
         import_argocd
         ;;
+    "scan-known-hosts")
+        shift
+        scan_known_hosts "$@"
+        ;;
     # TODO: Implement finalize action to clean up cloud resources!
     *)
     usage
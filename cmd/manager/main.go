@@ -22,6 +22,7 @@ import (
 	"os"
 	"runtime"
 	"strings"
+	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
@@ -31,10 +32,12 @@ import (
 	"github.com/argoproj-labs/argocd-operator/pkg/common"
 	"github.com/argoproj-labs/argocd-operator/pkg/controller"
 	"github.com/argoproj-labs/argocd-operator/pkg/controller/argocd"
+	"github.com/argoproj-labs/argocd-operator/pkg/reconciler/webhook"
 	"github.com/argoproj-labs/argocd-operator/version"
 
 	monitoringv1 "github.com/coreos/prometheus-operator/pkg/apis/monitoring/v1"
 	appsv1 "github.com/openshift/api/apps/v1"
+	consolev1 "github.com/openshift/api/console/v1"
 	oauthv1 "github.com/openshift/api/oauth/v1"
 	routev1 "github.com/openshift/api/route/v1"
 	templatev1 "github.com/openshift/api/template/v1"
@@ -49,6 +52,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client/config"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/manager/signals"
@@ -70,6 +74,14 @@ func printVersion() {
 }
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == renderCommand {
+		if err := runRender(os.Args[2:], os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	// Add the zap logger flag set to the CLI. The flag set must
 	// be added before calling pflag.Parse().
 	pflag.CommandLine.AddFlagSet(zap.FlagSet())
@@ -78,6 +90,33 @@ func main() {
 	// controller-runtime)
 	pflag.CommandLine.AddGoFlagSet(flag.CommandLine)
 
+	healthProbeBindAddress := pflag.String("health-probe-bind-address", ":8081",
+		"The address the healthz and readyz endpoints of the operator bind to.")
+	pprofBindAddress := pflag.String("pprof-bind-address", "",
+		"The address the pprof endpoints of the operator bind to, e.g. localhost:6060. Disabled when empty.")
+	tracingOTLPEndpoint := pflag.String("tracing-otlp-endpoint", "",
+		"The OTLP gRPC endpoint the reconciliation traces of the operator are exported to, e.g. otel-collector:4317. Disabled when empty.")
+	tracingOTLPInsecure := pflag.Bool("tracing-otlp-insecure", false,
+		"Export the reconciliation traces without TLS.")
+	reconcilerWebhookURL := pflag.String("reconciler-webhook-url", "",
+		"The https URL of a webhook the Deployments, StatefulSets and core Roles generated by the operator are posted to, so that they can be changed before they are applied. Disabled when empty.")
+	reconcilerWebhookCAFile := pflag.String("reconciler-webhook-ca-file", "",
+		"The file holding the CA certificates used to verify the certificate of the reconciler webhook, the system CA certificates are used when empty.")
+	reconcilerWebhookTimeout := pflag.Duration("reconciler-webhook-timeout", webhook.DefaultTimeout,
+		"The time the reconciler webhook is given to answer for each object, at most 1m.")
+	syncPeriod := pflag.Duration("sync-period", 10*time.Hour,
+		"The period at which all ArgoCD clusters are reconciled again, unless set otherwise by spec.reconcileInterval.")
+
+	rateLimiter := argocd.DefaultRateLimiterOptions()
+	pflag.DurationVar(&rateLimiter.BaseDelay, "rate-limiter-base-delay", rateLimiter.BaseDelay,
+		"The delay before retrying a failed reconciliation of an ArgoCD cluster, doubled with each consecutive failure.")
+	pflag.DurationVar(&rateLimiter.MaxDelay, "rate-limiter-max-delay", rateLimiter.MaxDelay,
+		"The maximum delay before retrying a failed reconciliation of an ArgoCD cluster.")
+	pflag.Float64Var(&rateLimiter.QPS, "rate-limiter-qps", rateLimiter.QPS,
+		"The overall number of retried reconciliations per second across all ArgoCD clusters.")
+	pflag.IntVar(&rateLimiter.Burst, "rate-limiter-burst", rateLimiter.Burst,
+		"The overall number of retried reconciliations allowed to exceed the rate-limiter-qps at once.")
+
 	pflag.Parse()
 
 	// Use a zap logr.Logger implementation. If none of the zap
@@ -92,6 +131,11 @@ func main() {
 
 	printVersion()
 
+	if err := argocd.SetRateLimiterOptions(rateLimiter); err != nil {
+		log.Error(err, "Invalid rate limiter options")
+		os.Exit(1)
+	}
+
 	// Inspect cluster to verify availability of extra features
 	if err := argocd.InspectCluster(); err != nil {
 		log.Info("unable to inspect cluster")
@@ -120,8 +164,10 @@ func main() {
 
 	// Set default manager options
 	options := manager.Options{
-		Namespace:          namespace,
-		MetricsBindAddress: fmt.Sprintf("%s:%d", metricsHost, metricsPort),
+		Namespace:              namespace,
+		MetricsBindAddress:     fmt.Sprintf("%s:%d", metricsHost, metricsPort),
+		HealthProbeBindAddress: *healthProbeBindAddress,
+		SyncPeriod:             syncPeriod,
 	}
 
 	// Add support for MultiNamespace set in WATCH_NAMESPACE (e.g ns1,ns2)
@@ -164,6 +210,14 @@ func main() {
 		}
 	}
 
+	// Setup Scheme for OpenShift ConsoleLinks if available.
+	if argocd.IsConsoleAPIAvailable() {
+		if err := consolev1.AddToScheme(mgr.GetScheme()); err != nil {
+			log.Error(err, "")
+			os.Exit(1)
+		}
+	}
+
 	// Setup Schemes for SSO if template instance is available.
 	if argocd.IsTemplateAPIAvailable() {
 		if err := templatev1.AddToScheme(mgr.GetScheme()); err != nil {
@@ -180,12 +234,48 @@ func main() {
 		}
 	}
 
+	// Send the generated objects to the reconciler webhook if enabled
+	if *reconcilerWebhookURL != "" {
+		hook, err := webhook.NewHook(*reconcilerWebhookURL, *reconcilerWebhookCAFile, *reconcilerWebhookTimeout)
+		if err != nil {
+			log.Error(err, "")
+			os.Exit(1)
+		}
+		argocd.Register(hook)
+	}
+
 	// Setup all Controllers
 	if err := controller.AddToManager(mgr); err != nil {
 		log.Error(err, "")
 		os.Exit(1)
 	}
 
+	// Add the health and readiness checks
+	if err := mgr.AddHealthzCheck("ping", healthz.Ping); err != nil {
+		log.Error(err, "")
+		os.Exit(1)
+	}
+	if err := mgr.AddReadyzCheck("ping", healthz.Ping); err != nil {
+		log.Error(err, "")
+		os.Exit(1)
+	}
+
+	// Serve the pprof endpoints if enabled
+	if *pprofBindAddress != "" {
+		if err := addPprof(mgr, *pprofBindAddress); err != nil {
+			log.Error(err, "")
+			os.Exit(1)
+		}
+	}
+
+	// Export the reconciliation traces if enabled
+	if *tracingOTLPEndpoint != "" {
+		if err := addTracing(mgr, *tracingOTLPEndpoint, *tracingOTLPInsecure); err != nil {
+			log.Error(err, "")
+			os.Exit(1)
+		}
+	}
+
 	// Add the Metrics Service
 	addMetrics(ctx, cfg)
 
@@ -0,0 +1,93 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/runtime/serializer/json"
+
+	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/pkg/controller/argocd"
+)
+
+// renderCommand is the name of the subcommand used to render the manifests for an ArgoCD.
+const renderCommand = "render"
+
+// runRender will output the manifests the operator would create for the ArgoCD in the given file,
+// without contacting a cluster.
+func runRender(args []string, out io.Writer) error {
+	flags := pflag.NewFlagSet(renderCommand, pflag.ContinueOnError)
+	file := flags.StringP("filename", "f", "", "The file containing the ArgoCD resource to render, or - for stdin.")
+	namespace := flags.StringP("namespace", "n", "", "The namespace to render into when the ArgoCD resource does not specify one.")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *file == "" {
+		return fmt.Errorf("the filename of an ArgoCD resource must be provided with -f")
+	}
+
+	var data []byte
+	var err error
+	if *file == "-" {
+		data, err = ioutil.ReadAll(os.Stdin)
+	} else {
+		data, err = ioutil.ReadFile(*file)
+	}
+	if err != nil {
+		return err
+	}
+
+	s, err := argocd.NewRenderScheme()
+	if err != nil {
+		return err
+	}
+
+	obj, _, err := serializer.NewCodecFactory(s).UniversalDeserializer().Decode(data, nil, nil)
+	if err != nil {
+		return err
+	}
+	cr, ok := obj.(*argoprojv1a1.ArgoCD)
+	if !ok {
+		return fmt.Errorf("%s does not contain an ArgoCD resource", *file)
+	}
+	if cr.Namespace == "" {
+		cr.Namespace = *namespace
+	}
+	if cr.Namespace == "" {
+		cr.Namespace = "default"
+	}
+
+	objs, err := argocd.Render(cr, s)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewYAMLSerializer(json.DefaultMetaFactory, s, s)
+	for _, o := range objs {
+		if _, err := fmt.Fprintln(out, "---"); err != nil {
+			return err
+		}
+		if err := encoder.Encode(o, out); err != nil {
+			return err
+		}
+	}
+	return nil
+}
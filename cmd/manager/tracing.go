@@ -0,0 +1,57 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpgrpc"
+	sdkresource "go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/semconv"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"github.com/argoproj-labs/argocd-operator/version"
+)
+
+// addTracing will export the spans recorded by the operator to the OTLP gRPC collector at the given endpoint, and
+// flush the remaining spans when the manager stops.
+func addTracing(mgr manager.Manager, endpoint string, insecure bool) error {
+	opts := []otlpgrpc.Option{otlpgrpc.WithEndpoint(endpoint)}
+	if insecure {
+		opts = append(opts, otlpgrpc.WithInsecure())
+	}
+
+	// The exporter connects in the background, the operator is not held up by an unavailable collector.
+	exporter, err := otlp.NewExporter(context.Background(), otlpgrpc.NewDriver(opts...))
+	if err != nil {
+		return err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(sdkresource.NewWithAttributes(
+			semconv.ServiceNameKey.String("argocd-operator"),
+			semconv.ServiceVersionKey.String(version.Version))))
+	otel.SetTracerProvider(provider)
+	log.Info("Exporting traces", "endpoint", endpoint)
+
+	return mgr.Add(manager.RunnableFunc(func(stop <-chan struct{}) error {
+		<-stop
+		return provider.Shutdown(context.Background())
+	}))
+}
@@ -55,6 +55,8 @@ kubectl delete sa -n ${ARGOCD_OPERATOR_NAMESPACE} \
 kubectl delete crd \
     applications.argoproj.io \
     appprojects.argoproj.io \
+    argocdagents.argoproj.io \
+    argocddefaults.argoproj.io \
     argocdexports.argoproj.io \
     argocds.argoproj.io
 
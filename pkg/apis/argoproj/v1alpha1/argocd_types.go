@@ -21,6 +21,7 @@ import (
 	autoscaling "k8s.io/api/autoscaling/v1"
 	corev1 "k8s.io/api/core/v1"
 	extv1beta1 "k8s.io/api/extensions/v1beta1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -36,6 +37,11 @@ func init() {
 // ArgoCD is the Schema for the argocds API
 // +k8s:openapi-gen=true
 // +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=".status.phase",description="The phase of the ArgoCD"
+// +kubebuilder:printcolumn:name="Host",type=string,JSONPath=".status.host",description="The host of the Argo CD server"
+// +kubebuilder:printcolumn:name="Version",type=string,JSONPath=".status.version",description="The version of Argo CD"
+// +kubebuilder:printcolumn:name="Apps",type=integer,JSONPath=".status.applicationCount",description="The number of Applications"
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=".metadata.creationTimestamp"
 type ArgoCD struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
@@ -44,6 +50,63 @@ type ArgoCD struct {
 	Status ArgoCDStatus `json:"status,omitempty"`
 }
 
+// ArgoCDApplicationControllerClusterCacheSpec defines the options for tuning the Application Controller cluster cache.
+type ArgoCDApplicationControllerClusterCacheSpec struct {
+	// ListPageSize is the number of resources to request per page when listing resources in a managed cluster.
+	ListPageSize int32 `json:"listPageSize,omitempty"`
+
+	// ListSemaphore is the maximum number of concurrent list requests made against a managed cluster.
+	ListSemaphore int32 `json:"listSemaphore,omitempty"`
+
+	// ResyncDuration is the interval at which the cluster cache is fully invalidated and rebuilt.
+	ResyncDuration *metav1.Duration `json:"resyncDuration,omitempty"`
+
+	// WatchResyncDuration is the interval at which resource watches are restarted.
+	WatchResyncDuration *metav1.Duration `json:"watchResyncDuration,omitempty"`
+}
+
+// ArgoCDApplicationControllerMetricsSpec defines the options for the metrics endpoint of the Application Controller.
+type ArgoCDApplicationControllerMetricsSpec struct {
+	// Port is the port of the metrics endpoint. Defaults to 8082, or 8443 when TLS is enabled.
+	Port int32 `json:"port,omitempty"`
+
+	// TLS enables TLS for the metrics endpoint.
+	TLS *ArgoCDApplicationControllerMetricsTLSSpec `json:"tls,omitempty"`
+}
+
+// ArgoCDApplicationControllerMetricsTLSSpec defines the TLS options for the metrics endpoint of the Application
+// Controller.
+type ArgoCDApplicationControllerMetricsTLSSpec struct {
+	// SecretName is the name of a Secret with the tls.crt, tls.key and ca.crt keys. The certificate is served on the
+	// metrics endpoint, which only accepts clients with a certificate signed by the CA. The ServiceMonitor uses the
+	// same certificate to scrape the metrics.
+	SecretName string `json:"secretName"`
+}
+
+// ArgoCDApplicationControllerProcessorsAutosizeSpec defines the options for sizing the Application Controller
+// processors from the number of Applications and managed clusters.
+type ArgoCDApplicationControllerProcessorsAutosizeSpec struct {
+	// Enabled will toggle the sizing of the processors by the operator. The numbers of processors in Processors are
+	// used as the lower bounds.
+	Enabled bool `json:"enabled"`
+
+	// MaxOperation is the upper bound of the number of application operation processors.
+	MaxOperation int32 `json:"maxOperation,omitempty"`
+
+	// MaxStatus is the upper bound of the number of application status processors.
+	MaxStatus int32 `json:"maxStatus,omitempty"`
+}
+
+// ArgoCDApplicationControllerProcessorsStatus contains the number of Application Controller processors sized by the
+// operator.
+type ArgoCDApplicationControllerProcessorsStatus struct {
+	// Operation is the number of application operation processors.
+	Operation int32 `json:"operation,omitempty"`
+
+	// Status is the number of application status processors.
+	Status int32 `json:"status,omitempty"`
+}
+
 // ArgoCDApplicationControllerProcessorsSpec defines the options for the ArgoCD Application Controller processors.
 type ArgoCDApplicationControllerProcessorsSpec struct {
 	// Operation is the number of application operation processors.
@@ -55,12 +118,53 @@ type ArgoCDApplicationControllerProcessorsSpec struct {
 
 // ArgoCDApplicationControllerSpec defines the options for the ArgoCD Application Controller component.
 type ArgoCDApplicationControllerSpec struct {
+	// AutomountServiceAccountToken defines whether a service account token is mounted in the Application Controller pods.
+	AutomountServiceAccountToken *bool `json:"automountServiceAccountToken,omitempty"`
+
+	// CommandOverride replaces the command and the arguments of the Application Controller container.
+	CommandOverride ArgoCDCommandOverrideSpec `json:"commandOverride,omitempty"`
+
+	// DNSConfig defines the DNS parameters of the Application Controller pods.
+	DNSConfig *corev1.PodDNSConfig `json:"dnsConfig,omitempty"`
+
+	// DNSPolicy defines the DNS policy of the Application Controller pods.
+	DNSPolicy corev1.DNSPolicy `json:"dnsPolicy,omitempty"`
+
+	// HostAliases defines additional entries for the hosts file of the Application Controller pods.
+	HostAliases []corev1.HostAlias `json:"hostAliases,omitempty"`
+
+	// InitContainerResources defines the Compute Resources required by the Application Controller import init container.
+	InitContainerResources *corev1.ResourceRequirements `json:"initContainerResources,omitempty"`
+
+	// Metrics contains the options for the metrics endpoint of the Application Controller.
+	Metrics ArgoCDApplicationControllerMetricsSpec `json:"metrics,omitempty"`
+
 	// Processors contains the options for the Application Controller processors.
 	Processors ArgoCDApplicationControllerProcessorsSpec `json:"processors,omitempty"`
 
+	// ProcessorsAutosize contains the options for sizing the processors from the number of Applications and managed
+	// clusters.
+	ProcessorsAutosize *ArgoCDApplicationControllerProcessorsAutosizeSpec `json:"processorsAutosize,omitempty"`
+
 	// Resources defines the Compute Resources required by the container for the Application Controller.
 	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
 
+	// SelfHealTimeout is the delay before the Application Controller re-attempts to self-heal an Application that
+	// has drifted from its desired state, e.g. 5s or 1m.
+	SelfHealTimeout *metav1.Duration `json:"selfHealTimeout,omitempty"`
+
+	// ServiceAccountName is the name of an existing ServiceAccount to use for the Application Controller pods instead
+	// of one created by the operator.
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+
+	// StartupProbe defines a startup probe for the Application Controller container, allowing a long initial cache
+	// warm-up to complete before liveness checks begin.
+	StartupProbe *corev1.Probe `json:"startupProbe,omitempty"`
+
+	// SyncTimeout is the duration after which a sync operation is terminated by the Application Controller, e.g.
+	// 30m. A value of 0 disables the timeout.
+	SyncTimeout *metav1.Duration `json:"syncTimeout,omitempty"`
+
 	// AppSync is used to control the sync frequency, by default the ArgoCD
 	// controller polls Git every 3m by default.
 	//
@@ -68,6 +172,28 @@ type ArgoCDApplicationControllerSpec struct {
 	// frequency.
 	// +optional
 	AppSync *metav1.Duration `json:"appSync,omitempty"`
+
+	// ClusterCache contains the options for tuning the Application Controller cluster cache.
+	ClusterCache ArgoCDApplicationControllerClusterCacheSpec `json:"clusterCache,omitempty"`
+
+	// Sharding contains the options for the Application Controller sharding configuration.
+	Sharding ArgoCDApplicationControllerShardingSpec `json:"sharding,omitempty"`
+}
+
+// ArgoCDApplicationControllerShardingSpec defines the options available for sharding the Application Controller component.
+type ArgoCDApplicationControllerShardingSpec struct {
+	// Algorithm is the algorithm used to distribute clusters across the Application Controller shards.
+	// The value specified here can currently be:
+	// - legacy - Assign clusters to shards based on a hash of the cluster secret UID
+	// - round-robin - Distribute clusters evenly across all shards
+	// - consistent-hashing - Use consistent hashing with bounded loads to distribute clusters
+	Algorithm string `json:"algorithm,omitempty"`
+}
+
+// ArgoCDApplicationHealthSpec defines the summary of the health and sync status of the Applications of an ArgoCD.
+type ArgoCDApplicationHealthSpec struct {
+	// Enabled will toggle the summary of the Applications in the namespace of the ArgoCD into the status.
+	Enabled bool `json:"enabled"`
 }
 
 // ArgoCDApplicationSet defines whether the Argo CD ApplicationSet controller should be installed.
@@ -81,6 +207,40 @@ type ArgoCDApplicationSet struct {
 
 	// Resources defines the Compute Resources required by the container for ApplicationSet.
 	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// ServiceAccountName is the name of an existing ServiceAccount to use for the ApplicationSet controller pods
+	// instead of one created by the operator.
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+
+	// SourceNamespaces defines the namespaces, other than the namespace of the ArgoCD instance, in which
+	// ApplicationSet resources are reconciled. Requires the ApplicationSet controller of Argo CD v2.8.0 or later. The
+	// namespaces must be allowed to be managed by the ArgoCD instance.
+	SourceNamespaces []string `json:"sourceNamespaces,omitempty"`
+
+	// CommandOverride replaces the command and the arguments of the ApplicationSet controller container.
+	CommandOverride ArgoCDCommandOverrideSpec `json:"commandOverride,omitempty"`
+
+	// EnableProgressiveSyncs enables the progressive syncs of ApplicationSets that define a rollout strategy.
+	EnableProgressiveSyncs bool `json:"enableProgressiveSyncs,omitempty"`
+
+	// Env defines additional environment variables for the ApplicationSet controller.
+	Env []corev1.EnvVar `json:"env,omitempty"`
+
+	// ExtraCommandArgs defines additional arguments for the ApplicationSet controller command, e.g. to use
+	// upstream flags that have no dedicated field.
+	ExtraCommandArgs []string `json:"extraCommandArgs,omitempty"`
+
+	// LogLevel is the log level of the ApplicationSet controller, one of debug, info, warn or error.
+	LogLevel string `json:"logLevel,omitempty"`
+}
+
+// ArgoCDApplicationsStatus summarizes the health and sync status of the Applications of an ArgoCD.
+type ArgoCDApplicationsStatus struct {
+	// Health is the number of Applications for each health status, e.g. Healthy, Progressing or Degraded.
+	Health map[string]int32 `json:"health,omitempty"`
+
+	// Sync is the number of Applications for each sync status, e.g. Synced or OutOfSync.
+	Sync map[string]int32 `json:"sync,omitempty"`
 }
 
 // ArgoCDCASpec defines the CA options for ArgCD.
@@ -98,35 +258,291 @@ type ArgoCDCertificateSpec struct {
 	SecretName string `json:"secretName"`
 }
 
+const (
+	// ArgoCDConditionTypeApplicationsHealthy indicates whether all of the Applications of the ArgoCD are healthy and
+	// in sync, when the summary of the Applications is enabled.
+	ArgoCDConditionTypeApplicationsHealthy = "ApplicationsHealthy"
+
+	// ArgoCDConditionTypeCommandOverridden indicates that the command of at least one component container is
+	// overridden, so that the operator no longer manages the flags of that container.
+	ArgoCDConditionTypeCommandOverridden = "CommandOverridden"
+
+	// ArgoCDConditionTypeDegraded indicates that at least one Argo CD component has failed to roll out.
+	ArgoCDConditionTypeDegraded = "Degraded"
+
+	// ArgoCDConditionTypeDeletionBlocked indicates that the deletion of the ArgoCD is still waiting for Applications to
+	// be finalized by the Application Controller after the deletion timeout.
+	ArgoCDConditionTypeDeletionBlocked = "DeletionBlocked"
+
+	// ArgoCDConditionTypeDexDisabled indicates that Dex is not installed although the deprecated .spec.dex field is
+	// set, so that its settings are ignored.
+	ArgoCDConditionTypeDexDisabled = "DexDisabled"
+
+	// ArgoCDConditionTypePodSecurityCompliant indicates whether the pods of all Argo CD workloads comply with the Pod
+	// Security Standard of the ComplianceMode.
+	ArgoCDConditionTypePodSecurityCompliant = "PodSecurityCompliant"
+
+	// ArgoCDConditionTypeRedisHAMigrating indicates that the migration between a single Redis server and Redis HA is
+	// in progress.
+	ArgoCDConditionTypeRedisHAMigrating = "RedisHAMigrating"
+
+	// ArgoCDConditionTypeResourcePressure indicates that an Argo CD component is close to its resource limits, or
+	// that the Application Controller has too few processors for the number of Applications.
+	ArgoCDConditionTypeResourcePressure = "ResourcePressure"
+
+	// ArgoCDConditionTypeServerTLSSecretValid indicates whether the existing TLS Secret named in .spec.server.tls.secretName
+	// is found and holds a certificate and private key.
+	ArgoCDConditionTypeServerTLSSecretValid = "ServerTLSSecretValid"
+
+	// ArgoCDConditionTypeSpecValid indicates whether the ArgoCD spec passed validation. The resources of the ArgoCD
+	// are not reconciled while the spec is invalid.
+	ArgoCDConditionTypeSpecValid = "SpecValid"
+)
+
+// ArgoCDCondition describes the state of an aspect of an ArgoCD at a certain point.
+type ArgoCDCondition struct {
+	// Type of the condition, one of ApplicationsHealthy, CommandOverridden, Degraded, DeletionBlocked, DexDisabled,
+	// PodSecurityCompliant, RedisHAMigrating, ResourcePressure, ServerTLSSecretValid or SpecValid.
+	Type string `json:"type"`
+
+	// Status of the condition, one of True, False or Unknown.
+	Status corev1.ConditionStatus `json:"status"`
+
+	// LastTransitionTime is the last time the condition changed from one status to another.
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+
+	// Reason is a brief, machine readable explanation for the condition's last transition.
+	Reason string `json:"reason,omitempty"`
+
+	// Message is a human readable description of the details of the condition's last transition.
+	Message string `json:"message,omitempty"`
+}
+
+// ArgoCDCommandOverrideSpec defines the command and the arguments that replace those the operator sets for a
+// component container, e.g. to run a wrapper entrypoint. They are used verbatim, the operator no longer manages the
+// flags of the container when they are set.
+type ArgoCDCommandOverrideSpec struct {
+	// Command replaces the entrypoint of the container when set.
+	Command []string `json:"command,omitempty"`
+
+	// Args replaces the arguments of the container when set.
+	Args []string `json:"args,omitempty"`
+}
+
+// ArgoCDConfigDriftStatus describes a key of an Argo CD ConfigMap that was changed outside of the operator and
+// overwritten with the desired state of the ArgoCD.
+type ArgoCDConfigDriftStatus struct {
+	// ConfigMap is the name of the ConfigMap holding the key.
+	ConfigMap string `json:"configMap"`
+
+	// Key is the overwritten key.
+	Key string `json:"key"`
+
+	// Field is the field of the ArgoCD spec that manages the key.
+	Field string `json:"field,omitempty"`
+
+	// Overwrites is the number of times the key was overwritten.
+	Overwrites int32 `json:"overwrites"`
+
+	// LastOverwriteTime is the last time the key was overwritten.
+	LastOverwriteTime metav1.Time `json:"lastOverwriteTime,omitempty"`
+}
+
+// ArgoCDConsoleLinkSpec defines the options for the OpenShift ConsoleLink pointing at the Argo CD Server Route.
+type ArgoCDConsoleLinkSpec struct {
+	// ImageURL is the URL of the icon shown in front of the link in the application menu.
+	ImageURL string `json:"imageURL,omitempty"`
+
+	// Section is the section of the application menu in which the link appears.
+	Section string `json:"section,omitempty"`
+
+	// Text is the display text for the link.
+	Text string `json:"text,omitempty"`
+}
+
+// ArgoCDCLIConfigSpec defines the options for publishing the connection details of the Argo CD server for the argocd
+// CLI in a ConfigMap or a Secret.
+type ArgoCDCLIConfigSpec struct {
+	// Enabled will toggle the ConfigMap or the Secret holding the address and the CA certificate of the Argo CD server.
+	Enabled bool `json:"enabled"`
+
+	// Kind is the kind of the resource holding the connection details, ConfigMap or Secret. Defaults to ConfigMap.
+	Kind string `json:"kind,omitempty"`
+
+	// Namespace is the namespace of the ConfigMap or the Secret. Defaults to the namespace of the ArgoCD.
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// ArgoCDClusterDiscoverySpec defines the options for registering clusters automatically from the Secrets holding a
+// kubeconfig in the namespace of the ArgoCD.
+type ArgoCDClusterDiscoverySpec struct {
+	// SecretSelector selects the Secrets with a "kubeconfig" key that are transformed into Argo CD cluster Secrets.
+	SecretSelector *metav1.LabelSelector `json:"secretSelector,omitempty"`
+
+	// TokenRotationInterval is how often the token of the ServiceAccount named by the
+	// "argocds.argoproj.io/cluster-service-account" annotation of a kubeconfig Secret is regenerated on its cluster,
+	// e.g. "720h". The tokens are not rotated when not set.
+	TokenRotationInterval *metav1.Duration `json:"tokenRotationInterval,omitempty"`
+}
+
+// ArgoCDClusterTokenRotationStatus defines the observed state of the rotation of the ServiceAccount token of a cluster
+// registered by the cluster discovery.
+type ArgoCDClusterTokenRotationStatus struct {
+	// LastRotation is the time the token was last regenerated.
+	LastRotation *metav1.Time `json:"lastRotation,omitempty"`
+
+	// NextRotation is the time the token will next be regenerated.
+	NextRotation *metav1.Time `json:"nextRotation,omitempty"`
+
+	// Revocation is the time the previous token is revoked, while a token rotation is in progress.
+	Revocation *metav1.Time `json:"revocation,omitempty"`
+
+	// SecretName is the name of the kubeconfig Secret of the cluster.
+	SecretName string `json:"secretName"`
+}
+
+// ArgoCDCredentialSecretsSpec defines the label selectors for pre-existing Secrets holding credentials for Argo CD,
+// e.g. Secrets created by the External Secrets Operator or the Secrets Store CSI driver.
+type ArgoCDCredentialSecretsSpec struct {
+	// Clusters selects the Secrets holding cluster credentials.
+	Clusters *metav1.LabelSelector `json:"clusters,omitempty"`
+
+	// Repositories selects the Secrets holding repository credentials.
+	Repositories *metav1.LabelSelector `json:"repositories,omitempty"`
+
+	// RepositoryTemplates selects the Secrets holding repository credential templates.
+	RepositoryTemplates *metav1.LabelSelector `json:"repositoryTemplates,omitempty"`
+}
+
 // ArgoCDDexSpec defines the desired state for the Dex server component.
 type ArgoCDDexSpec struct {
+	// AutomountServiceAccountToken defines whether a service account token is mounted in the Dex pods.
+	AutomountServiceAccountToken *bool `json:"automountServiceAccountToken,omitempty"`
+
+	// CommandOverride replaces the command and the arguments of the Dex container.
+	CommandOverride ArgoCDCommandOverrideSpec `json:"commandOverride,omitempty"`
+
 	//Config is the dex connector configuration.
 	Config string `json:"config,omitempty"`
 
+	// DNSConfig defines the DNS parameters of the Dex pods.
+	DNSConfig *corev1.PodDNSConfig `json:"dnsConfig,omitempty"`
+
+	// DNSPolicy defines the DNS policy of the Dex pods.
+	DNSPolicy corev1.DNSPolicy `json:"dnsPolicy,omitempty"`
+
+	// HostAliases defines additional entries for the hosts file of the Dex pods.
+	HostAliases []corev1.HostAlias `json:"hostAliases,omitempty"`
+
 	// Image is the Dex container image.
 	Image string `json:"image,omitempty"`
 
+	// InitContainerResources defines the Compute Resources required by the Dex copyutil init container.
+	InitContainerResources *corev1.ResourceRequirements `json:"initContainerResources,omitempty"`
+
+	// LivenessProbe overrides the default liveness probe of the Dex container.
+	LivenessProbe *corev1.Probe `json:"livenessProbe,omitempty"`
+
 	// OpenShiftOAuth enables OpenShift OAuth authentication for the Dex server.
 	OpenShiftOAuth bool `json:"openShiftOAuth,omitempty"`
 
+	// ReadinessProbe overrides the default readiness probe of the Dex container.
+	ReadinessProbe *corev1.Probe `json:"readinessProbe,omitempty"`
+
 	// Resources defines the Compute Resources required by the container for Dex.
 	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
 
+	// ServiceAccountName is the name of an existing ServiceAccount to use for the Dex pods instead of one created by
+	// the operator.
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+
+	// StandaloneImage indicates that the Dex image contains the argocd-dex binary, so that Dex is run directly instead
+	// of copying the binary from the Argo CD image with an init container.
+	StandaloneImage bool `json:"standaloneImage,omitempty"`
+
+	// StaticClients defines additional OAuth2 clients to register with Dex.
+	StaticClients []ArgoCDDexStaticClientSpec `json:"staticClients,omitempty"`
+
 	// Version is the Dex container image tag.
 	Version string `json:"version,omitempty"`
 }
 
+// ArgoCDDexStaticClientSpec defines an additional OAuth2 client to register with Dex.
+type ArgoCDDexStaticClientSpec struct {
+	// ID is the OAuth2 client ID.
+	ID string `json:"id"`
+
+	// Name is the display name of the client.
+	Name string `json:"name,omitempty"`
+
+	// Public marks the client as public, in which case no client secret is generated.
+	Public bool `json:"public,omitempty"`
+
+	// RedirectURIs are the allowed redirect URIs for the client.
+	RedirectURIs []string `json:"redirectURIs,omitempty"`
+}
+
 // ArgoCDDexOAuthSpec defines the desired state for the Dex OAuth configuration.
 type ArgoCDDexOAuthSpec struct {
 	// Enabled will toggle OAuth support for the Dex server.
 	Enabled bool `json:"enabled"`
 }
 
+// ArgoCDDisasterRecoverySpec defines the options for mirroring the critical Secrets of Argo CD into a sealed bundle
+// in a disaster recovery namespace.
+type ArgoCDDisasterRecoverySpec struct {
+	// KeySecretName is the name of the Secret holding the "backup.key" used to seal the bundle. A key is generated
+	// when the Secret does not exist in the mirror mode. Defaults to "<argocd>-disaster-recovery-key".
+	KeySecretName string `json:"keySecretName,omitempty"`
+
+	// KeySecretNamespace is the namespace of the Secret holding the key used to seal the bundle. It must be neither the
+	// ArgoCD namespace nor the disaster recovery namespace, so that the key is not lost with the Secrets it protects
+	// and is not stored with the bundle.
+	KeySecretNamespace string `json:"keySecretNamespace"`
+
+	// Mode is either "mirror" (the default), to keep the sealed bundle in the disaster recovery namespace up to date,
+	// or "restore", to recreate the missing Secrets from the sealed bundle when recovering Argo CD.
+	Mode string `json:"mode,omitempty"`
+
+	// Namespace is the disaster recovery namespace holding the sealed bundle.
+	Namespace string `json:"namespace"`
+}
+
+// ArgoCDExtraRoleRulesSpec defines additional policy rules for the Roles and ClusterRoles of the Argo CD components.
+type ArgoCDExtraRoleRulesSpec struct {
+	// ApplicationController defines additional policy rules for the Application Controller, e.g. to manage custom
+	// resources in the managed namespaces.
+	ApplicationController []rbacv1.PolicyRule `json:"applicationController,omitempty"`
+
+	// ApplicationSet defines additional policy rules for the Role of the ApplicationSet controller, in its namespace
+	// and in its source namespaces.
+	ApplicationSet []rbacv1.PolicyRule `json:"applicationSet,omitempty"`
+
+	// Dex defines additional policy rules for the Role of Dex.
+	Dex []rbacv1.PolicyRule `json:"dex,omitempty"`
+
+	// HA defines additional policy rules for the Role of Redis HA.
+	HA []rbacv1.PolicyRule `json:"ha,omitempty"`
+
+	// Server defines additional policy rules for the Argo CD Server.
+	Server []rbacv1.PolicyRule `json:"server,omitempty"`
+}
+
+// ArgoCDGrafanaExternalSpec defines an existing Grafana instance used in place of the Grafana managed by the operator.
+type ArgoCDGrafanaExternalSpec struct {
+	// URL is the address of the existing Grafana instance, used to link the Applications to the Argo CD dashboard.
+	URL string `json:"url"`
+}
+
 // ArgoCDGrafanaSpec defines the desired state for the Grafana component.
 type ArgoCDGrafanaSpec struct {
 	// Enabled will toggle Grafana support globally for ArgoCD.
 	Enabled bool `json:"enabled"`
 
+	// External defines an existing Grafana instance that is used in place of the Grafana managed by the operator.
+	// The dashboards and datasource are provisioned as ConfigMaps for the sidecar of the existing Grafana.
+	External *ArgoCDGrafanaExternalSpec `json:"external,omitempty"`
+
 	// Host is the hostname to use for Ingress/Route resources.
 	Host string `json:"host,omitempty"`
 
@@ -151,9 +567,21 @@ type ArgoCDGrafanaSpec struct {
 
 // ArgoCDHASpec defines the desired state for High Availability support for Argo CD.
 type ArgoCDHASpec struct {
+	// AutomountServiceAccountToken defines whether a service account token is mounted in the Redis HA and Redis HA Proxy pods. Defaults to false.
+	AutomountServiceAccountToken *bool `json:"automountServiceAccountToken,omitempty"`
+
 	// Enabled will toggle HA support globally for Argo CD.
 	Enabled bool `json:"enabled"`
 
+	// HAProxy defines the timeouts and health checks of the Redis HAProxy.
+	HAProxy ArgoCDHAProxySpec `json:"haproxy,omitempty"`
+
+	// InitContainerResources defines the Compute Resources required by the Redis HAProxy config-init container.
+	InitContainerResources *corev1.ResourceRequirements `json:"initContainerResources,omitempty"`
+
+	// RedisConfig defines the Redis Sentinel options for HA.
+	RedisConfig ArgoCDHARedisConfigSpec `json:"redisConfig,omitempty"`
+
 	// RedisProxyImage is the Redis HAProxy container image.
 	RedisProxyImage string `json:"redisProxyImage,omitempty"`
 
@@ -162,6 +590,111 @@ type ArgoCDHASpec struct {
 
 	// Resources defines the Compute Resources required by the container for HA.
 	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// ServiceAccountName is the name of an existing ServiceAccount to use for the Redis HA and Redis HA Proxy pods
+	// instead of one created by the operator.
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+}
+
+// ArgoCDHAProxySpec defines the timeouts and health checks of the Redis HAProxy for High Availability support for Argo
+// CD.
+type ArgoCDHAProxySpec struct {
+	// CheckInterval is the interval between two health checks of a Redis server. Defaults to 3s.
+	CheckInterval *metav1.Duration `json:"checkInterval,omitempty"`
+
+	// TimeoutCheck is the time allowed for a health check of a Redis server, once connected. Defaults to 2s.
+	TimeoutCheck *metav1.Duration `json:"timeoutCheck,omitempty"`
+
+	// TimeoutClient is the time a connection from an Argo CD component may stay idle before it is closed. Defaults to
+	// 6m.
+	TimeoutClient *metav1.Duration `json:"timeoutClient,omitempty"`
+
+	// TimeoutConnect is the time allowed to connect to a Redis server. Defaults to 4s.
+	TimeoutConnect *metav1.Duration `json:"timeoutConnect,omitempty"`
+
+	// TimeoutServer is the time a connection to a Redis server may stay idle before it is closed. Defaults to 6m.
+	TimeoutServer *metav1.Duration `json:"timeoutServer,omitempty"`
+}
+
+// ArgoCDHARedisConfigSpec defines the Redis Sentinel options for High Availability support for Argo CD.
+type ArgoCDHARedisConfigSpec struct {
+	// AnnounceServices is the number of Redis servers, each exposed through an announce Service. Defaults to 3.
+	AnnounceServices int32 `json:"announceServices,omitempty"`
+
+	// DownAfterMilliseconds is the time in milliseconds a Redis server must be unreachable before the sentinels
+	// consider it down. Defaults to 10000.
+	DownAfterMilliseconds int32 `json:"downAfterMilliseconds,omitempty"`
+
+	// FailoverTimeout is the time in milliseconds the sentinels allow for a failover before retrying it. Defaults to
+	// 180000.
+	FailoverTimeout int32 `json:"failoverTimeout,omitempty"`
+}
+
+// ArgoCDHelmOCIRegistrySpec defines the credentials for an OCI registry hosting Helm charts.
+type ArgoCDHelmOCIRegistrySpec struct {
+	// CredentialsSecret is the name of the Secret holding the username and password keys for the registry.
+	CredentialsSecret string `json:"credentialsSecret"`
+
+	// Registry is the host of the OCI registry, e.g. registry.example.com/charts.
+	Registry string `json:"registry"`
+}
+
+// ArgoCDImageUpdaterSpec defines the desired state for the Argo CD Image Updater component.
+type ArgoCDImageUpdaterSpec struct {
+	// CommandOverride replaces the command and the arguments of the Image Updater container.
+	CommandOverride ArgoCDCommandOverrideSpec `json:"commandOverride,omitempty"`
+
+	// Enabled will toggle the installation of the Argo CD Image Updater.
+	Enabled bool `json:"enabled"`
+
+	// Image is the Argo CD Image Updater container image.
+	Image string `json:"image,omitempty"`
+
+	// Interval is the time to wait between checks for updated images.
+	Interval *metav1.Duration `json:"interval,omitempty"`
+
+	// Registries is the contents of the registries.conf used to configure the container registries.
+	Registries string `json:"registries,omitempty"`
+
+	// Resources defines the Compute Resources required by the container for the Argo CD Image Updater.
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// ServiceAccountName is the name of an existing ServiceAccount to use for the Image Updater pods instead of one
+	// created by the operator.
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+
+	// Version is the Argo CD Image Updater container image tag.
+	Version string `json:"version,omitempty"`
+}
+
+// ArgoCDImagesStatus defines the container images resolved by the operator for the Argo CD components.
+type ArgoCDImagesStatus struct {
+	// ApplicationSet is the container image for the ApplicationSet controller.
+	ApplicationSet string `json:"applicationSet,omitempty"`
+
+	// ArgoCD is the container image for the Argo CD application controller, repo server and server.
+	ArgoCD string `json:"argocd,omitempty"`
+
+	// Dex is the container image for Dex.
+	Dex string `json:"dex,omitempty"`
+
+	// Grafana is the container image for Grafana.
+	Grafana string `json:"grafana,omitempty"`
+
+	// ImageUpdater is the container image for the Argo CD Image Updater.
+	ImageUpdater string `json:"imageUpdater,omitempty"`
+
+	// Keycloak is the container image for Keycloak.
+	Keycloak string `json:"keycloak,omitempty"`
+
+	// Redis is the container image for Redis.
+	Redis string `json:"redis,omitempty"`
+
+	// RedisHA is the container image for Redis in HA mode.
+	RedisHA string `json:"redisHA,omitempty"`
+
+	// RedisHAProxy is the container image for the Redis HA Proxy.
+	RedisHAProxy string `json:"redisHAProxy,omitempty"`
 }
 
 // ArgoCDImportSpec defines the desired state for the ArgoCD import/restore process.
@@ -173,6 +706,19 @@ type ArgoCDImportSpec struct {
 	Namespace *string `json:"namespace,omitempty"`
 }
 
+// ArgoCDExternalDNSSpec defines the DNS records of the Argo CD Server published by external-dns.
+type ArgoCDExternalDNSSpec struct {
+	// Enabled will add the external-dns annotations to the Ingresses and the Route of the Argo CD Server, and to its
+	// Service when it is of type LoadBalancer. Each hostname must only be published for one of these endpoints.
+	Enabled bool `json:"enabled"`
+
+	// Hostnames are the hostnames to publish for the Argo CD Server, in addition to the host of the server.
+	Hostnames []string `json:"hostnames,omitempty"`
+
+	// TTL is the time to live of the DNS records in seconds. Defaults to the time to live of the DNS provider.
+	TTL int32 `json:"ttl,omitempty"`
+}
+
 // ArgoCDIngressSpec defines the desired state for the Ingress resources.
 type ArgoCDIngressSpec struct {
 	// Annotations is the map of annotations to apply to the Ingress.
@@ -181,6 +727,9 @@ type ArgoCDIngressSpec struct {
 	// Enabled will toggle the creation of the Ingress.
 	Enabled bool `json:"enabled"`
 
+	// Labels is the map of labels to add to the Ingress.
+	Labels map[string]string `json:"labels,omitempty"`
+
 	// Path used for the Ingress resource.
 	Path string `json:"path,omitempty"`
 
@@ -195,6 +744,43 @@ type ArgoCDIngressSpec struct {
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
+// ArgoCDKeycloakDatabaseSpec defines the external database used by Keycloak.
+type ArgoCDKeycloakDatabaseSpec struct {
+	// CredentialsSecret is the name of the Secret holding the connection details for the external PostgreSQL
+	// database, in the host, port, database, username and password keys.
+	CredentialsSecret string `json:"credentialsSecret"`
+}
+
+// ArgoCDKnownHostsAutoScanSpec defines the scanning of the SSH host keys of Git hosts into the SSH known hosts.
+type ArgoCDKnownHostsAutoScanSpec struct {
+	// Hosts are the Git hosts to scan, as a hostname or as a hostname and port, e.g. git.example.com:2222.
+	Hosts []string `json:"hosts,omitempty"`
+
+	// Schedule is the Cron schedule of the scans. Defaults to every six hours.
+	Schedule string `json:"schedule,omitempty"`
+}
+
+// ArgoCDKeycloakSpec defines the desired state for the Keycloak SSO provider.
+type ArgoCDKeycloakSpec struct {
+	// Database configures Keycloak to use an external PostgreSQL database instead of the embedded one.
+	Database *ArgoCDKeycloakDatabaseSpec `json:"database,omitempty"`
+
+	// Host is the hostname to use for the Keycloak Route.
+	Host string `json:"host,omitempty"`
+
+	// Image is the Keycloak container image.
+	Image string `json:"image,omitempty"`
+
+	// Resources defines the Compute Resources required by the container for Keycloak.
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// RootCA is the PEM encoded root CA certificate trusted when communicating with Keycloak through its Route.
+	RootCA string `json:"rootCA,omitempty"`
+
+	// Version is the Keycloak container image tag.
+	Version string `json:"version,omitempty"`
+}
+
 // ArgoCDList contains a list of ArgoCD
 type ArgoCDList struct {
 	metav1.TypeMeta `json:",inline"`
@@ -202,6 +788,16 @@ type ArgoCDList struct {
 	Items           []ArgoCD `json:"items"`
 }
 
+// ArgoCDPodSecurityViolationStatus describes the violations of a Pod Security Standard by the pods of an Argo CD
+// workload.
+type ArgoCDPodSecurityViolationStatus struct {
+	// Workload is the kind and name of the workload, e.g. Deployment/argocd-server.
+	Workload string `json:"workload"`
+
+	// Violations are the checks of the Pod Security Standard that the pods of the workload fail.
+	Violations []string `json:"violations"`
+}
+
 // ArgoCDPrometheusSpec defines the desired state for the Prometheus component.
 type ArgoCDPrometheusSpec struct {
 	// Enabled will toggle Prometheus support globally for ArgoCD.
@@ -213,6 +809,10 @@ type ArgoCDPrometheusSpec struct {
 	// Ingress defines the desired state for an Ingress for the Prometheus component.
 	Ingress ArgoCDIngressSpec `json:"ingress,omitempty"`
 
+	// Labels is the map of labels to add to the metrics Services and the ServiceMonitors, e.g. to match the
+	// selectors of an existing Prometheus.
+	Labels map[string]string `json:"labels,omitempty"`
+
 	// Route defines the desired state for an OpenShift Route for the Prometheus component.
 	Route ArgoCDRouteSpec `json:"route,omitempty"`
 
@@ -220,6 +820,18 @@ type ArgoCDPrometheusSpec struct {
 	Size *int32 `json:"size,omitempty"`
 }
 
+// ArgoCDProxySpec defines the proxy used by the Argo CD components to reach external services.
+type ArgoCDProxySpec struct {
+	// HTTPProxy is the proxy used for HTTP requests, set as HTTP_PROXY.
+	HTTPProxy string `json:"httpProxy,omitempty"`
+
+	// HTTPSProxy is the proxy used for HTTPS requests, set as HTTPS_PROXY.
+	HTTPSProxy string `json:"httpsProxy,omitempty"`
+
+	// NoProxy is the comma-separated list of hosts reached without the proxy, set as NO_PROXY.
+	NoProxy string `json:"noProxy,omitempty"`
+}
+
 // ArgoCDRBACSpec defines the desired state for the Argo CD RBAC configuration.
 type ArgoCDRBACSpec struct {
 	// DefaultPolicy is the name of the default role which Argo CD will falls back to, when
@@ -240,36 +852,226 @@ type ArgoCDRBACSpec struct {
 	Scopes *string `json:"scopes,omitempty"`
 }
 
+// ArgoCDRedisConfigSpec defines the memory and persistence configuration of the Redis server.
+type ArgoCDRedisConfigSpec struct {
+	// MaxMemory is the memory limit of the Redis server, in bytes or with a unit such as 512mb or 2gb. Defaults to
+	// no limit.
+	MaxMemory string `json:"maxMemory,omitempty"`
+
+	// MaxMemoryPolicy is the policy used to evict keys when the memory limit is reached. Defaults to volatile-lru.
+	// The noeviction policy is not supported.
+	MaxMemoryPolicy string `json:"maxMemoryPolicy,omitempty"`
+
+	// Save defines the points at which Redis saves a snapshot of its data to disk. Defaults to no snapshots.
+	Save []ArgoCDRedisSaveSpec `json:"save,omitempty"`
+}
+
+// ArgoCDRedisSaveSpec defines a point at which Redis saves a snapshot of its data to disk.
+type ArgoCDRedisSaveSpec struct {
+	// Seconds is the number of seconds after which a snapshot is saved when enough keys changed.
+	Seconds int32 `json:"seconds"`
+
+	// Changes is the number of keys that must change for a snapshot to be saved.
+	Changes int32 `json:"changes"`
+}
+
 // ArgoCDRedisSpec defines the desired state for the Redis server component.
 type ArgoCDRedisSpec struct {
+	// AutomountServiceAccountToken defines whether a service account token is mounted in the Redis pods. Defaults to false.
+	AutomountServiceAccountToken *bool `json:"automountServiceAccountToken,omitempty"`
+
+	// CommandOverride replaces the command and the arguments of the Redis server container.
+	CommandOverride ArgoCDCommandOverrideSpec `json:"commandOverride,omitempty"`
+
+	// Config defines the memory and persistence configuration of the Redis server, in both HA and non-HA modes.
+	Config ArgoCDRedisConfigSpec `json:"config,omitempty"`
+
+	// Enabled defines whether the operator manages a Redis server for Argo CD. Defaults to true. A Remote Redis is
+	// required when disabled.
+	Enabled *bool `json:"enabled,omitempty"`
+
 	// Image is the Redis container image.
 	Image string `json:"image,omitempty"`
 
+	// InitContainerResources defines the Compute Resources required by the Redis HA config-init container.
+	InitContainerResources *corev1.ResourceRequirements `json:"initContainerResources,omitempty"`
+
 	// Resources defines the Compute Resources required by the container for Redis.
 	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
 
+	// Remote is the address, in the host:port form, of a Redis server that is not managed by the operator. It is used
+	// by the Argo CD components when the managed Redis is disabled.
+	Remote string `json:"remote,omitempty"`
+
+	// ServiceAccountName is the name of an existing ServiceAccount to use for the Redis pods instead of one created by
+	// the operator.
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+
 	// Version is the Redis container image tag.
 	Version string `json:"version,omitempty"`
 }
 
+// ArgoCDRepoGitRetrySpec defines the options for retrying failed Git requests made by the Repo server.
+type ArgoCDRepoGitRetrySpec struct {
+	// Attempts is the number of times a failed Git request is attempted before giving up.
+	Attempts int32 `json:"attempts,omitempty"`
+
+	// Duration is the initial delay before retrying a failed Git request.
+	Duration *metav1.Duration `json:"duration,omitempty"`
+
+	// Factor is the multiplier applied to the delay after each failed Git request.
+	Factor int32 `json:"factor,omitempty"`
+
+	// MaxDuration is the maximum delay between retries of a failed Git request.
+	MaxDuration *metav1.Duration `json:"maxDuration,omitempty"`
+}
+
+// ArgoCDRepoSOPSSpec defines the options for decrypting manifests with KSOPS in the Repo server.
+type ArgoCDRepoSOPSSpec struct {
+	// AgeKeySecret is the name of the Secret holding the age keys used for decryption in the keys.txt key.
+	AgeKeySecret string `json:"ageKeySecret,omitempty"`
+
+	// Enabled will toggle the installation of KSOPS in the Repo server.
+	Enabled bool `json:"enabled"`
+
+	// GPGKeySecret is the name of the Secret holding the GPG private keys used for decryption.
+	GPGKeySecret string `json:"gpgKeySecret,omitempty"`
+
+	// Image is the KSOPS container image.
+	Image string `json:"image,omitempty"`
+
+	// Version is the KSOPS container image tag.
+	Version string `json:"version,omitempty"`
+}
+
 // ArgoCDRepoSpec defines the desired state for the Argo CD repo server component.
 type ArgoCDRepoSpec struct {
+	// CacheExpiration is the duration for which repository data, such as generated manifests, is cached by the Repo server.
+	CacheExpiration *metav1.Duration `json:"cacheExpiration,omitempty"`
+
+	// CommandOverride replaces the command and the arguments of the Repo server container.
+	CommandOverride ArgoCDCommandOverrideSpec `json:"commandOverride,omitempty"`
+
+	// GitRetry defines the options for retrying failed Git requests made by the Repo server.
+	GitRetry ArgoCDRepoGitRetrySpec `json:"gitRetry,omitempty"`
+
+	// DNSConfig defines the DNS parameters of the Repo server pods.
+	DNSConfig *corev1.PodDNSConfig `json:"dnsConfig,omitempty"`
+
+	// DNSPolicy defines the DNS policy of the Repo server pods.
+	DNSPolicy corev1.DNSPolicy `json:"dnsPolicy,omitempty"`
+
+	// FixVolumePermissions sets the FSGroup of the Repo server pods, defaulting to the argocd group, so that the group
+	// of the volumes backed by VolumeClaims is changed when their root does not match it.
+	FixVolumePermissions bool `json:"fixVolumePermissions,omitempty"`
+
+	// FSGroup is the group that owns the volumes of the Repo server pods, and a supplemental group of its containers.
+	FSGroup *int64 `json:"fsGroup,omitempty"`
+
+	// HostAliases defines additional entries for the hosts file of the Repo server pods.
+	HostAliases []corev1.HostAlias `json:"hostAliases,omitempty"`
+
+	// InitContainerResources defines the Compute Resources required by the Repo server init containers.
+	InitContainerResources *corev1.ResourceRequirements `json:"initContainerResources,omitempty"`
+
 	// MountSAToken describes whether you would like to have the Repo server mount the service account token
 	MountSAToken bool `json:"mountsatoken,omitempty"`
 
+	// Replicas is the replica count for the Repo server Deployment. Defaults to 1, or 2 when HA is enabled.
+	Replicas *int32 `json:"replicas,omitempty"`
+
 	// Resources defines the Compute Resources required by the container for Redis.
 	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
 
+	// RunAsUser is the UID to run the containers of the Repo server pods as, unless set on a container.
+	RunAsUser *int64 `json:"runAsUser,omitempty"`
+
+	// SOPS defines the options for decrypting manifests with KSOPS in the Repo server.
+	SOPS ArgoCDRepoSOPSSpec `json:"sops,omitempty"`
+
 	// ServiceAccount defines the ServiceAccount user that you would like the Repo server to use
 	ServiceAccount string `json:"serviceaccount,omitempty"`
 
+	// VaultPlugin defines the options for the argocd-vault-plugin integration of the Repo server.
+	VaultPlugin ArgoCDRepoVaultPluginSpec `json:"vaultPlugin,omitempty"`
+
 	// VerifyTLS defines whether repo server API should be accessed using strict TLS validation
 	VerifyTLS bool `json:"verifytls,omitempty"`
 
+	// VolumeClaims replaces emptyDir volumes of the Repo server with existing PersistentVolumeClaims, so that their
+	// content survives restarts of the Repo server pods.
+	VolumeClaims []ArgoCDRepoVolumeClaimSpec `json:"volumeClaims,omitempty"`
+
 	// AutoTLS specifies the method to use for automatic TLS configuration for the repo server
 	// The value specified here can currently be:
 	// - openshift - Use the OpenShift service CA to request TLS config
+	// - operator - Use a certificate signed by the ArgoCD CA that is generated and rotated by the operator
 	AutoTLS string `json:"autotls,omitempty"`
+
+	// AutoTLSCertificate defines the validity of the certificate generated when AutoTLS is set to operator.
+	AutoTLSCertificate ArgoCDAutoTLSCertificateSpec `json:"autotlsCertificate,omitempty"`
+}
+
+// ArgoCDRepoVolumeClaimSpec defines a PersistentVolumeClaim that backs a volume of the Repo server.
+type ArgoCDRepoVolumeClaimSpec struct {
+	// ClaimName is the name of an existing PersistentVolumeClaim in the namespace of the ArgoCD.
+	ClaimName string `json:"claimName"`
+
+	// Volume is the name of the Repo server volume that is backed by the claim, one of gpg-keyring, custom-tools or
+	// helm-cache.
+	Volume string `json:"volume"`
+}
+
+// ArgoCDAutoTLSCertificateSpec defines the validity of a certificate that is generated and rotated by the operator.
+type ArgoCDAutoTLSCertificateSpec struct {
+	// RenewBefore is how long before the certificate expires that it is renewed. Defaults to 30 days.
+	RenewBefore *metav1.Duration `json:"renewBefore,omitempty"`
+
+	// Validity is the duration for which a newly generated certificate is valid. Defaults to 365 days.
+	Validity *metav1.Duration `json:"validity,omitempty"`
+}
+
+// ArgoCDRepoVaultPluginSpec defines the options for the argocd-vault-plugin integration of the Repo server.
+type ArgoCDRepoVaultPluginSpec struct {
+	// CredentialsSecret is the name of the Secret holding the plugin configuration, e.g. AVP_TYPE and VAULT_ADDR,
+	// which is exposed to the plugin as environment variables.
+	CredentialsSecret string `json:"credentialsSecret,omitempty"`
+
+	// Enabled will toggle the argocd-vault-plugin sidecar for the Repo server.
+	Enabled bool `json:"enabled"`
+
+	// Image is the container image used to download the plugin.
+	Image string `json:"image,omitempty"`
+
+	// Version is the argocd-vault-plugin release to install.
+	Version string `json:"version,omitempty"`
+}
+
+// ArgoCDResourceUsageStatus is a snapshot of the CPU and memory used by the pods of an Argo CD component, as
+// reported by the metrics-server.
+type ArgoCDResourceUsageStatus struct {
+	// Component is the name of the Argo CD component, e.g. application-controller or repo-server.
+	Component string `json:"component"`
+
+	// CPU is the highest CPU usage of the component container in a single pod.
+	CPU string `json:"cpu,omitempty"`
+
+	// CPUUtilization is the CPU usage as a percentage of the CPU limit of the component. It is omitted when no limit
+	// is set.
+	CPUUtilization int32 `json:"cpuUtilization,omitempty"`
+
+	// Memory is the highest memory usage of the component container in a single pod.
+	Memory string `json:"memory,omitempty"`
+
+	// MemoryUtilization is the memory usage as a percentage of the memory limit of the component. It is omitted when
+	// no limit is set.
+	MemoryUtilization int32 `json:"memoryUtilization,omitempty"`
+
+	// Pods is the number of pods of the component reported by the metrics-server.
+	Pods int32 `json:"pods,omitempty"`
+
+	// SampleTime is the time at which the snapshot was taken.
+	SampleTime metav1.Time `json:"sampleTime"`
 }
 
 // ArgoCDRouteSpec defines the desired state for an OpenShift Route.
@@ -280,6 +1082,9 @@ type ArgoCDRouteSpec struct {
 	// Enabled will toggle the creation of the OpenShift Route.
 	Enabled bool `json:"enabled"`
 
+	// Labels is the map of labels to add to the Route.
+	Labels map[string]string `json:"labels,omitempty"`
+
 	// Path the router watches for, to route traffic for to the service.
 	Path string `json:"path,omitempty"`
 
@@ -290,6 +1095,19 @@ type ArgoCDRouteSpec struct {
 	WildcardPolicy *routev1.WildcardPolicyType `json:"wildcardPolicy,omitempty"`
 }
 
+// ArgoCDSecretKeySpec defines a key of the argocd-secret Secret whose value is sourced from another Secret.
+type ArgoCDSecretKeySpec struct {
+	// Key is the key of the argocd-secret Secret, e.g. "webhook.github.secret" or "admin.password". The value of the
+	// "admin.password" key must be a bcrypt hash.
+	Key string `json:"key"`
+
+	// SecretName is the name of the Secret in the namespace of the ArgoCD holding the value.
+	SecretName string `json:"secretName"`
+
+	// SecretKey is the key of the value in the Secret. Defaults to Key.
+	SecretKey string `json:"secretKey,omitempty"`
+}
+
 // ArgoCDServerAutoscaleSpec defines the desired state for autoscaling the Argo CD Server component.
 type ArgoCDServerAutoscaleSpec struct {
 	// Enabled will toggle autoscaling support for the Argo CD Server component.
@@ -299,6 +1117,21 @@ type ArgoCDServerAutoscaleSpec struct {
 	HPA *autoscaling.HorizontalPodAutoscalerSpec `json:"hpa,omitempty"`
 }
 
+// ArgoCDServerExtensionSpec defines a UI extension to install in the Argo CD server.
+type ArgoCDServerExtensionSpec struct {
+	// ChecksumURL is the URL of a file with the SHA256 checksum used to verify the extension archive.
+	ChecksumURL string `json:"checksumURL,omitempty"`
+
+	// Image is the container image of the extension installer.
+	Image string `json:"image,omitempty"`
+
+	// Name is the name of the extension.
+	Name string `json:"name"`
+
+	// URL is the URL of the extension archive.
+	URL string `json:"url"`
+}
+
 // ArgoCDServerGRPCSpec defines the desired state for the Argo CD Server GRPC options.
 type ArgoCDServerGRPCSpec struct {
 	// Host is the hostname to use for Ingress/Route resources.
@@ -308,31 +1141,131 @@ type ArgoCDServerGRPCSpec struct {
 	Ingress ArgoCDIngressSpec `json:"ingress,omitempty"`
 }
 
+// ArgoCDServerIngressSpec defines an additional Ingress for the Argo CD Server component, e.g. to expose the server
+// through several ingress controllers.
+type ArgoCDServerIngressSpec struct {
+	// Name is appended to the name of the Ingress, which is named <argocd>-server-<name>.
+	Name string `json:"name"`
+
+	// Annotations is the map of annotations to apply to the Ingress.
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// Host is the hostname of the Ingress. Defaults to the host of the Argo CD Server.
+	Host string `json:"host,omitempty"`
+
+	// IngressClassName is the name of the IngressClass of the ingress controller that implements the Ingress.
+	IngressClassName string `json:"ingressClassName,omitempty"`
+
+	// Labels is the map of labels to add to the Ingress.
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Path used for the Ingress resource.
+	Path string `json:"path,omitempty"`
+
+	// TLS configuration of the Ingress. Defaults to the host of the Ingress with the argocd-secret Secret.
+	TLS []extv1beta1.IngressTLS `json:"tls,omitempty"`
+}
+
 // ArgoCDServerSpec defines the options for the ArgoCD Server component.
 type ArgoCDServerSpec struct {
+	// AutomountServiceAccountToken defines whether a service account token is mounted in the Argo CD server pods.
+	AutomountServiceAccountToken *bool `json:"automountServiceAccountToken,omitempty"`
+
 	// Autoscale defines the autoscale options for the Argo CD Server component.
 	Autoscale ArgoCDServerAutoscaleSpec `json:"autoscale,omitempty"`
 
+	// CommandOverride replaces the command and the arguments of the Argo CD Server container.
+	CommandOverride ArgoCDCommandOverrideSpec `json:"commandOverride,omitempty"`
+
+	// ConsoleLink defines the OpenShift ConsoleLink for the Argo CD Server Route.
+	ConsoleLink ArgoCDConsoleLinkSpec `json:"consoleLink,omitempty"`
+
+	// DisableHTTPSRedirect disables the redirect of plain HTTP requests to HTTPS by the Ingress and Route of the Argo
+	// CD Server.
+	DisableHTTPSRedirect bool `json:"disableHTTPSRedirect,omitempty"`
+
 	// GRPC defines the state for the Argo CD Server GRPC options.
 	GRPC ArgoCDServerGRPCSpec `json:"grpc,omitempty"`
 
+	// GRPCWebRootPath is the path under which gRPC-Web requests are served by the Argo CD Server, for a reverse proxy
+	// that serves Argo CD under a sub-path.
+	GRPCWebRootPath string `json:"grpcWebRootPath,omitempty"`
+
+	// DNSConfig defines the DNS parameters of the Argo CD server pods.
+	DNSConfig *corev1.PodDNSConfig `json:"dnsConfig,omitempty"`
+
+	// DNSPolicy defines the DNS policy of the Argo CD server pods.
+	DNSPolicy corev1.DNSPolicy `json:"dnsPolicy,omitempty"`
+
+	// EnableGZip enables the gzip compression of the responses of the Argo CD Server.
+	EnableGZip bool `json:"enableGZip,omitempty"`
+
+	// ExternalDNS defines the DNS records of the Argo CD Server published by external-dns.
+	ExternalDNS ArgoCDExternalDNSSpec `json:"externalDNS,omitempty"`
+
+	// Extensions defines the UI extensions to install in the Argo CD server.
+	Extensions []ArgoCDServerExtensionSpec `json:"extensions,omitempty"`
+
+	// HostAliases defines additional entries for the hosts file of the Argo CD server pods.
+	HostAliases []corev1.HostAlias `json:"hostAliases,omitempty"`
+
 	// Host is the hostname to use for Ingress/Route resources.
 	Host string `json:"host,omitempty"`
 
 	// Ingress defines the desired state for an Ingress for the Argo CD Server component.
 	Ingress ArgoCDIngressSpec `json:"ingress,omitempty"`
 
+	// Ingresses defines additional Ingresses for the Argo CD Server component, each with its own ingress class, host
+	// and annotations.
+	Ingresses []ArgoCDServerIngressSpec `json:"ingresses,omitempty"`
+
 	// Insecure toggles the insecure flag.
 	Insecure bool `json:"insecure,omitempty"`
 
+	// ProxyExtension defines the options of the proxy extensions of the Argo CD server.
+	ProxyExtension ArgoCDServerProxyExtensionSpec `json:"proxyExtension,omitempty"`
+
 	// Resources defines the Compute Resources required by the container for the Argo CD server component.
 	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
 
 	// Route defines the desired state for an OpenShift Route for the Argo CD Server component.
 	Route ArgoCDRouteSpec `json:"route,omitempty"`
 
+	// ServiceAccountName is the name of an existing ServiceAccount to use for the Argo CD server pods instead of one
+	// created by the operator.
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+
 	// Service defines the options for the Service backing the ArgoCD Server component.
 	Service ArgoCDServerServiceSpec `json:"service,omitempty"`
+
+	// Session defines the lifetime of the user sessions of the Argo CD Server.
+	Session ArgoCDServerSessionSpec `json:"session,omitempty"`
+
+	// StartupProbe defines a startup probe for the Argo CD Server container, holding off the liveness and readiness
+	// probes until the server has finished starting up.
+	StartupProbe *corev1.Probe `json:"startupProbe,omitempty"`
+
+	// TLS defines the TLS options for the Argo CD Server component.
+	TLS ArgoCDServerTLSSpec `json:"tls,omitempty"`
+}
+
+// ArgoCDServerProxyExtensionSpec defines the options of the proxy extensions of the Argo CD server.
+type ArgoCDServerProxyExtensionSpec struct {
+	// Config is the YAML configuration of the backends of the proxy extensions, stored in the argocd-cm ConfigMap.
+	Config string `json:"config,omitempty"`
+
+	// Enabled will toggle the proxy extension feature of the Argo CD server, which requires Argo CD v2.7.0 or later.
+	Enabled bool `json:"enabled,omitempty"`
+}
+
+// ArgoCDServerSessionSpec defines the lifetime of the user sessions of the Argo CD Server.
+type ArgoCDServerSessionSpec struct {
+	// Duration is the duration of the sessions of the local users, after which their JWT session tokens expire.
+	Duration *metav1.Duration `json:"duration,omitempty"`
+
+	// IDTokenExpiration is the expiration of the JWT ID tokens issued by the managed Dex, which are used as the
+	// session tokens of the users logged in through Dex.
+	IDTokenExpiration *metav1.Duration `json:"idTokenExpiration,omitempty"`
 }
 
 // ArgoCDServerServiceSpec defines the Service options for Argo CD Server component.
@@ -341,6 +1274,19 @@ type ArgoCDServerServiceSpec struct {
 	Type corev1.ServiceType `json:"type"`
 }
 
+// ArgoCDServerTLSSpec defines the TLS options for the Argo CD Server component.
+type ArgoCDServerTLSSpec struct {
+	// Ciphers is the list of TLS cipher suites that the Argo CD Server accepts, using the IANA names.
+	Ciphers []string `json:"ciphers,omitempty"`
+
+	// MinVersion is the minimum TLS version that the Argo CD Server accepts, one of 1.0, 1.1, 1.2 or 1.3.
+	MinVersion string `json:"minVersion,omitempty"`
+
+	// SecretName is the name of an existing Secret of type kubernetes.io/tls holding the certificate for the Argo CD
+	// Server, used instead of the certificate generated by the operator.
+	SecretName string `json:"secretName,omitempty"`
+}
+
 // SSOProviderType string defines the type of SSO provider.
 type SSOProviderType string
 
@@ -348,16 +1294,72 @@ const (
 	// SSOProviderTypeKeycloak means keycloak will be Installed and Integrated with Argo CD. A new realm with name argocd
 	// will be created in this keycloak. This realm will have a client with name argocd that uses OpenShift v4 as Identity Provider.
 	SSOProviderTypeKeycloak SSOProviderType = "keycloak"
+
+	// SSOProviderTypeDex means Dex will be installed by the operator and integrated with Argo CD, using the options
+	// in .spec.sso.dex.
+	SSOProviderTypeDex SSOProviderType = "dex"
 )
 
 // ArgoCDSSOSpec defines SSO provider.
 type ArgoCDSSOSpec struct {
+	// Dex defines the options for a Dex SSO provider.
+	Dex *ArgoCDSSODexSpec `json:"dex,omitempty"`
+
+	// Keycloak defines the options for the Keycloak SSO provider.
+	Keycloak *ArgoCDKeycloakSpec `json:"keycloak,omitempty"`
+
 	// Provider installs and configures the given SSO Provider with Argo CD.
 	Provider SSOProviderType `json:"provider,omitempty"`
 	// VerifyTLS set to false disables strict TLS validation.
 	VerifyTLS *bool `json:"verifyTLS,omitempty"`
 }
 
+// ArgoCDSSODexSpec defines the options for a Dex SSO provider.
+type ArgoCDSSODexSpec struct {
+	// ArgoCDDexSpec defines the options of the Dex installed by the operator with the dex provider.
+	ArgoCDDexSpec `json:",inline"`
+
+	// External defines a Dex server that is managed outside of the operator, e.g. a central Dex of the organization.
+	// The managed Dex is not installed when it is set.
+	External *ArgoCDDexExternalSpec `json:"external,omitempty"`
+
+	// Groups defines the mapping of the groups of the users authenticated with the managed Dex into the Argo CD RBAC.
+	Groups *ArgoCDDexGroupsSpec `json:"groups,omitempty"`
+}
+
+// ArgoCDDexGroupsSpec defines the mapping of the groups of the users authenticated with Dex, e.g. OpenShift or LDAP
+// groups, into the Argo CD RBAC.
+type ArgoCDDexGroupsSpec struct {
+	// Allowed restricts the login with the OpenShift connector to the members of the given OpenShift groups.
+	Allowed []string `json:"allowed,omitempty"`
+
+	// Bindings binds the groups to Argo CD RBAC roles.
+	Bindings []ArgoCDDexGroupBindingSpec `json:"bindings,omitempty"`
+
+	// Scope is the OIDC scope holding the groups of the users, which is added to the Argo CD RBAC scopes. Defaults to
+	// groups.
+	Scope string `json:"scope,omitempty"`
+}
+
+// ArgoCDDexGroupBindingSpec binds a group to an Argo CD RBAC role.
+type ArgoCDDexGroupBindingSpec struct {
+	// Group is the name of the group, as returned by the Dex connector.
+	Group string `json:"group"`
+
+	// Role is the Argo CD RBAC role granted to the members of the group, e.g. role:admin.
+	Role string `json:"role"`
+}
+
+// ArgoCDDexExternalSpec defines a Dex server that is managed outside of the operator.
+type ArgoCDDexExternalSpec struct {
+	// ClientSecret is the name of a Secret with the clientID and clientSecret keys of the OAuth2 client registered
+	// for Argo CD with the external Dex server.
+	ClientSecret string `json:"clientSecret"`
+
+	// IssuerURL is the issuer URL of the external Dex server.
+	IssuerURL string `json:"issuerURL"`
+}
+
 // ArgoCDSpec defines the desired state of ArgoCD
 // +k8s:openapi-gen=true
 type ArgoCDSpec struct {
@@ -365,21 +1367,52 @@ type ArgoCDSpec struct {
 	// ArgoCDApplicationSet defines whether the Argo CD ApplicationSet controller should be installed.
 	ApplicationSet *ArgoCDApplicationSet `json:"applicationSet,omitempty"`
 
+	// ApplicationHealth defines the summary of the health and sync status of the Applications into the status.
+	ApplicationHealth ArgoCDApplicationHealthSpec `json:"applicationHealth,omitempty"`
+
 	// ApplicationInstanceLabelKey is the key name where Argo CD injects the app name as a tracking label.
 	ApplicationInstanceLabelKey string `json:"applicationInstanceLabelKey,omitempty"`
 
+	// CLIConfig defines the options for publishing the connection details of the Argo CD server for the argocd CLI.
+	CLIConfig ArgoCDCLIConfigSpec `json:"cliConfig,omitempty"`
+
+	// ClusterDiscovery defines the options for registering clusters automatically from kubeconfig Secrets.
+	ClusterDiscovery ArgoCDClusterDiscoverySpec `json:"clusterDiscovery,omitempty"`
+
+	// ComplianceMode is the Pod Security Standard the pods of Argo CD are verified against before their workloads are
+	// created or updated. The only supported value is restricted. Violations are reported in the status.
+	ComplianceMode string `json:"complianceMode,omitempty"`
+
 	// ConfigManagementPlugins is used to specify additional config management plugins.
 	ConfigManagementPlugins string `json:"configManagementPlugins,omitempty"`
 
 	// Controller defines the Application Controller options for ArgoCD.
 	Controller ArgoCDApplicationControllerSpec `json:"controller,omitempty"`
 
+	// CredentialSecrets defines the label selectors for pre-existing Secrets holding credentials for ArgoCD.
+	CredentialSecrets ArgoCDCredentialSecretsSpec `json:"credentialSecrets,omitempty"`
+
+	// DefaultClusterScopedRole is the name of an existing ClusterRole that is bound to the Application Controller
+	// instead of the ClusterRole generated by the operator.
+	DefaultClusterScopedRole string `json:"defaultClusterScopedRole,omitempty"`
+
+	// DeletionPolicy defines what happens to the Applications and AppProjects in the namespace of the ArgoCD when it is
+	// deleted. Valid values are Orphan, Cascade and StripFinalizers. Orphan is used when not set.
+	DeletionPolicy string `json:"deletionPolicy,omitempty"`
+
 	// Dex defines the Dex server options for ArgoCD.
+	// Deprecated: use .spec.sso.dex with the dex SSO provider instead.
 	Dex ArgoCDDexSpec `json:"dex,omitempty"`
 
 	// DisableAdmin will disable the admin user.
 	DisableAdmin bool `json:"disableAdmin,omitempty"`
 
+	// DisasterRecovery defines the options for mirroring the critical Secrets into a disaster recovery namespace.
+	DisasterRecovery *ArgoCDDisasterRecoverySpec `json:"disasterRecovery,omitempty"`
+
+	// ExtraRoleRules defines additional policy rules that are added to the Roles and ClusterRoles of the components.
+	ExtraRoleRules ArgoCDExtraRoleRulesSpec `json:"extraRoleRules,omitempty"`
+
 	// GATrackingID is the google analytics tracking ID to use.
 	GATrackingID string `json:"gaTrackingID,omitempty"`
 
@@ -392,6 +1425,13 @@ type ArgoCDSpec struct {
 	// HA options for High Availability support for the Redis component.
 	HA ArgoCDHASpec `json:"ha,omitempty"`
 
+	// HelmOCIRegistries defines the OCI registries hosting Helm charts, for which repository credentials are created.
+	HelmOCIRegistries []ArgoCDHelmOCIRegistrySpec `json:"helmOCIRegistries,omitempty"`
+
+	// Hibernate will scale all Argo CD workloads down to zero replicas when set to true. The previous replica
+	// counts are restored when Hibernate is set back to false.
+	Hibernate bool `json:"hibernate,omitempty"`
+
 	// HelpChatURL is the URL for getting chat help, this will typically be your Slack channel for support.
 	HelpChatURL string `json:"helpChatURL,omitempty"`
 
@@ -401,6 +1441,13 @@ type ArgoCDSpec struct {
 	// Image is the ArgoCD container image for all ArgoCD components.
 	Image string `json:"image,omitempty"`
 
+	// ImageRegistry is the registry used in place of the registry of the default images for all ArgoCD components,
+	// e.g. a mirror for disconnected installs. Images set explicitly on the ArgoCD are not affected.
+	ImageRegistry string `json:"imageRegistry,omitempty"`
+
+	// ImageUpdater defines the Argo CD Image Updater options for ArgoCD.
+	ImageUpdater ArgoCDImageUpdaterSpec `json:"imageUpdater,omitempty"`
+
 	// Import is the import/restore options for ArgoCD.
 	Import *ArgoCDImportSpec `json:"import,omitempty"`
 
@@ -410,18 +1457,44 @@ type ArgoCDSpec struct {
 	// InitialSSHKnownHosts defines the SSH known hosts data upon creation of the cluster for connecting Git repositories via SSH.
 	InitialSSHKnownHosts SSHHostsSpec `json:"initialSSHKnownHosts,omitempty"`
 
+	// IPFamilies are the IP families of the Services created by the operator, in order of preference. Valid values
+	// are IPv4 and IPv6. The cluster default is used when not set.
+	IPFamilies []string `json:"ipFamilies,omitempty"`
+
+	// IPFamilyPolicy is the dual-stack policy of the Services created by the operator. Valid values are SingleStack,
+	// PreferDualStack and RequireDualStack. The cluster default is used when not set.
+	IPFamilyPolicy string `json:"ipFamilyPolicy,omitempty"`
+
+	// KnownHostsAutoScan defines the Git hosts whose SSH host keys are scanned on a schedule and kept up to date in
+	// the SSH known hosts.
+	KnownHostsAutoScan ArgoCDKnownHostsAutoScanSpec `json:"knownHostsAutoScan,omitempty"`
+
 	// KustomizeBuildOptions is used to specify build options/parameters to use with `kustomize build`.
 	KustomizeBuildOptions string `json:"kustomizeBuildOptions,omitempty"`
 
 	// OIDCConfig is the OIDC configuration as an alternative to dex.
 	OIDCConfig string `json:"oidcConfig,omitempty"`
 
+	// OIDCInsecureSkipVerify disables the verification of the TLS certificate of the OIDC provider by Argo CD.
+	OIDCInsecureSkipVerify bool `json:"oidcInsecureSkipVerify,omitempty"`
+
+	// Profile is the name of a sizing profile, one of small, medium or large, setting coherent default resources,
+	// replicas and processor counts across the Argo CD components. Properties set explicitly take precedence.
+	Profile string `json:"profile,omitempty"`
+
 	// Prometheus defines the Prometheus server options for ArgoCD.
 	Prometheus ArgoCDPrometheusSpec `json:"prometheus,omitempty"`
 
+	// Proxy defines the proxy used by the Argo CD components, instead of the proxy in the environment of the operator.
+	Proxy *ArgoCDProxySpec `json:"proxy,omitempty"`
+
 	// RBAC defines the RBAC configuration for Argo CD.
 	RBAC ArgoCDRBACSpec `json:"rbac,omitempty"`
 
+	// ReconcileInterval is the interval at which the ArgoCD is reconciled to correct any drift of its resources, in
+	// addition to the reconciles triggered by changes. Defaults to the resync period of the operator.
+	ReconcileInterval *metav1.Duration `json:"reconcileInterval,omitempty"`
+
 	// Redis defines the Redis server options for ArgoCD.
 	Redis ArgoCDRedisSpec `json:"redis,omitempty"`
 
@@ -434,6 +1507,10 @@ type ArgoCDSpec struct {
 	// ResourceCustomizations customizes resource behavior. Keys are in the form: group/Kind.
 	ResourceCustomizations string `json:"resourceCustomizations,omitempty"`
 
+	// ResourceHealthChecksFrom references ConfigMaps in the namespace of the ArgoCD with Lua health checks to add to
+	// the resource customizations. Each key is a resource in the form group_Kind, or Kind for the core group.
+	ResourceHealthChecksFrom []ConfigMapRef `json:"resourceHealthChecksFrom,omitempty"`
+
 	// ResourceExclusions is used to completely ignore entire classes of resource group/kinds.
 	ResourceExclusions string `json:"resourceExclusions,omitempty"`
 
@@ -441,6 +1518,10 @@ type ArgoCDSpec struct {
 	// reconciliation process.
 	ResourceInclusions string `json:"resourceInclusions,omitempty"`
 
+	// SecretKeys are the keys of the argocd-secret Secret whose values are sourced from other Secrets instead of
+	// being managed by the operator. Other keys that are not managed by the operator are left untouched.
+	SecretKeys []ArgoCDSecretKeySpec `json:"secretKeys,omitempty"`
+
 	// Server defines the options for the ArgoCD Server component.
 	Server ArgoCDServerSpec `json:"server,omitempty"`
 
@@ -453,6 +1534,9 @@ type ArgoCDSpec struct {
 	// TLS defines the TLS options for ArgoCD.
 	TLS ArgoCDTLSSpec `json:"tls,omitempty"`
 
+	// UpgradeStrategy defines the strategy used to roll out a new Argo CD version to the components.
+	UpgradeStrategy ArgoCDUpgradeStrategySpec `json:"upgradeStrategy,omitempty"`
+
 	// UsersAnonymousEnabled toggles anonymous user access.
 	// The anonymous users get default role permissions specified argocd-rbac-cm.
 	UsersAnonymousEnabled bool `json:"usersAnonymousEnabled,omitempty"`
@@ -472,6 +1556,20 @@ type ArgoCDStatus struct {
 	// Unknown: For some reason the state of the Argo CD application controller component could not be obtained.
 	ApplicationController string `json:"applicationController,omitempty"`
 
+	// ApplicationCount is the number of Applications observed in the namespace of the ArgoCD.
+	ApplicationCount int32 `json:"applicationCount,omitempty"`
+
+	// Applications summarizes the health and sync status of the Applications in the namespace of the ArgoCD, when
+	// enabled in the ApplicationHealth spec.
+	Applications *ArgoCDApplicationsStatus `json:"applications,omitempty"`
+
+	// AppProjectCount is the number of AppProjects observed in the namespace of the ArgoCD.
+	AppProjectCount int32 `json:"appProjectCount,omitempty"`
+
+	// ClusterTokenRotations is the state of the rotation of the ServiceAccount tokens of the clusters registered by the
+	// cluster discovery, when token rotation is enabled.
+	ClusterTokenRotations []ArgoCDClusterTokenRotationStatus `json:"clusterTokenRotations,omitempty"`
+
 	// Dex is a simple, high-level summary of where the Argo CD Dex component is in its lifecycle.
 	// There are five possible dex values:
 	// Pending: The Argo CD Dex component has been accepted by the Kubernetes system, but one or more of the required resources have not been created.
@@ -480,6 +1578,31 @@ type ArgoCDStatus struct {
 	// Unknown: For some reason the state of the Argo CD Dex component could not be obtained.
 	Dex string `json:"dex,omitempty"`
 
+	// ControllerProcessors contains the number of Application Controller processors sized by the operator when
+	// autosizing of the processors is enabled.
+	ControllerProcessors *ArgoCDApplicationControllerProcessorsStatus `json:"controllerProcessors,omitempty"`
+
+	// Conditions contains the latest observations of the state of the ArgoCD, such as whether a component has
+	// failed to roll out.
+	Conditions []ArgoCDCondition `json:"conditions,omitempty"`
+
+	// ConfigDrift lists the keys of the argocd-cm and argocd-rbac-cm ConfigMaps that were changed outside of the
+	// operator and overwritten with the desired state of the ArgoCD.
+	ConfigDrift []ArgoCDConfigDriftStatus `json:"configDrift,omitempty"`
+
+	// Host is the hostname of the Argo CD server, from its Route, Ingress, external host or Service in that order.
+	Host string `json:"host,omitempty"`
+
+	// Images contains the container images resolved by the operator for each of the Argo CD components.
+	Images ArgoCDImagesStatus `json:"images,omitempty"`
+
+	// ManagedNamespaces are the namespaces managed by the ArgoCD, including its own namespace.
+	ManagedNamespaces []string `json:"managedNamespaces,omitempty"`
+
+	// MigratedSSO contains the .spec.sso equivalent of the deprecated .spec.dex field and DISABLE_DEX environment
+	// variable while the ArgoCD still relies on them.
+	MigratedSSO *ArgoCDSSOSpec `json:"migratedSSO,omitempty"`
+
 	// Phase is a simple, high-level summary of where the ArgoCD is in its lifecycle.
 	// There are five possible phase values:
 	// Pending: The ArgoCD has been accepted by the Kubernetes system, but one or more of the required resources have not been created.
@@ -488,12 +1611,17 @@ type ArgoCDStatus struct {
 	// Unknown: For some reason the state of the ArgoCD phase could not be obtained.
 	Phase string `json:"phase,omitempty"`
 
+	// PodSecurityViolations lists the violations of the Pod Security Standard of the ComplianceMode by the pods of
+	// each Argo CD workload.
+	PodSecurityViolations []ArgoCDPodSecurityViolationStatus `json:"podSecurityViolations,omitempty"`
+
 	// Redis is a simple, high-level summary of where the Argo CD Redis component is in its lifecycle.
 	// There are five possible redis values:
 	// Pending: The Argo CD Redis component has been accepted by the Kubernetes system, but one or more of the required resources have not been created.
 	// Running: All of the required Pods for the Argo CD Redis component are in a Ready state.
 	// Failed: At least one of the  Argo CD Redis component Pods had a failure.
 	// Unknown: For some reason the state of the Argo CD Redis component could not be obtained.
+	// Remote: The Argo CD components use a remote Redis that is not managed by the operator.
 	Redis string `json:"redis,omitempty"`
 
 	// Repo is a simple, high-level summary of where the Argo CD Repo component is in its lifecycle.
@@ -504,6 +1632,10 @@ type ArgoCDStatus struct {
 	// Unknown: For some reason the state of the Argo CD Repo component could not be obtained.
 	Repo string `json:"repo,omitempty"`
 
+	// ResourceUsage contains the latest snapshots of the resource usage of the Application Controller and Repo
+	// Server, sampled periodically from the metrics-server when it is installed.
+	ResourceUsage []ArgoCDResourceUsageStatus `json:"resourceUsage,omitempty"`
+
 	// Server is a simple, high-level summary of where the Argo CD server component is in its lifecycle.
 	// There are five possible server values:
 	// Pending: The Argo CD server component has been accepted by the Kubernetes system, but one or more of the required resources have not been created.
@@ -514,6 +1646,10 @@ type ArgoCDStatus struct {
 
 	// RepoTLSChecksum contains the SHA256 checksum of the latest known state of tls.crt and tls.key in the argocd-repo-server-tls secret.
 	RepoTLSChecksum string `json:"repoTLSChecksum,omitempty"`
+
+	// Version is the tag or digest of the Argo CD container image used by the application controller, repo server
+	// and server.
+	Version string `json:"version,omitempty"`
 }
 
 // ArgoCDTLSSpec defines the TLS options for ArgCD.
@@ -521,10 +1657,35 @@ type ArgoCDTLSSpec struct {
 	// CA defines the CA options.
 	CA ArgoCDCASpec `json:"ca,omitempty"`
 
-	// InitialCerts defines custom TLS certificates upon creation of the cluster for connecting Git repositories via HTTPS.
+	// CertsFrom references ConfigMaps in the namespace of the ArgoCD with TLS certificates to add to the
+	// argocd-tls-certs-cm ConfigMap. Each key is the name of a Git server.
+	CertsFrom []ConfigMapRef `json:"certsFrom,omitempty"`
+
+	// ExtraSANs are additional DNS names or IP addresses to include in the certificates generated by the operator, e.g.
+	// the names by which the components are reached from outside of the cluster.
+	ExtraSANs []string `json:"extraSANs,omitempty"`
+
+	// InitialCerts defines custom TLS certificates for connecting Git repositories via HTTPS, keyed by the name of the
+	// Git server. The certificates are kept in the argocd-tls-certs-cm ConfigMap along with the certificates added by
+	// other means, and take precedence over the certificates in CertsFrom.
 	InitialCerts map[string]string `json:"initialCerts,omitempty"`
 }
 
+// ArgoCDUpgradeStrategySpec defines the strategy used to roll out a new Argo CD version to the components.
+type ArgoCDUpgradeStrategySpec struct {
+	// Type is the upgrade strategy to use. The value specified here can currently be:
+	// - All - Upgrade all of the components at the same time (default).
+	// - Staged - Upgrade the repo server, application controller and server in that order, waiting for each
+	// component to become ready before upgrading the next.
+	Type string `json:"type,omitempty"`
+}
+
+// ConfigMapRef references a ConfigMap in the namespace of the ArgoCD.
+type ConfigMapRef struct {
+	// Name of the ConfigMap.
+	Name string `json:"name"`
+}
+
 type SSHHostsSpec struct {
 	// ExcludeDefaultHosts describes whether you would like to include the default
 	// list of SSH Known Hosts provided by ArgoCD.
@@ -0,0 +1,142 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
+)
+
+// NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
+// Important: Run "operator-sdk generate k8s" to regenerate code after modifying this file
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ArgoCDAgent is the Schema for the argocdagents API
+// +k8s:openapi-gen=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:path=argocdagents,scope=Namespaced
+type ArgoCDAgent struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ArgoCDAgentSpec   `json:"spec,omitempty"`
+	Status ArgoCDAgentStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ArgoCDAgentList contains a list of ArgoCDAgent
+type ArgoCDAgentList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ArgoCDAgent `json:"items"`
+}
+
+// ArgoCDAgentControllerSpec defines the options for the Application Controller of an ArgoCDAgent.
+type ArgoCDAgentControllerSpec struct {
+	// Processors contains the options for the Application Controller processors.
+	Processors ArgoCDApplicationControllerProcessorsSpec `json:"processors,omitempty"`
+
+	// Resources defines the Compute Resources required by the container for the Application Controller.
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+}
+
+// ArgoCDAgentHubSpec defines the central Argo CD instance that an ArgoCDAgent registers with.
+type ArgoCDAgentHubSpec struct {
+	// ClusterName is the name used for this cluster in the central Argo CD instance. Defaults to the name of the ArgoCDAgent.
+	ClusterName string `json:"clusterName,omitempty"`
+
+	// KubeconfigSecret is the name of a Secret with a "kubeconfig" key that grants access to the namespace of the central Argo CD instance.
+	KubeconfigSecret string `json:"kubeconfigSecret"`
+
+	// Namespace is the namespace of the central Argo CD instance on the hub cluster.
+	Namespace string `json:"namespace"`
+
+	// RepoServer is the address of the Repo Server of the central Argo CD instance, as reachable from this cluster.
+	RepoServer string `json:"repoServer"`
+
+	// RepoServerCASecret is the name of a Secret with a "ca.crt" key that holds the CA certificate used to verify
+	// the TLS certificate of the Repo Server of the central Argo CD instance.
+	RepoServerCASecret string `json:"repoServerCASecret"`
+
+	// Server is the URL of the API server of this cluster, as reachable from the hub cluster.
+	Server string `json:"server"`
+
+	// TokenRotationInterval is how often the ServiceAccount token used by the hub to access this cluster is
+	// regenerated, e.g. "720h". The token is not rotated when not set.
+	TokenRotationInterval *metav1.Duration `json:"tokenRotationInterval,omitempty"`
+}
+
+// ArgoCDAgentSpec defines the desired state of ArgoCDAgent
+// +k8s:openapi-gen=true
+type ArgoCDAgentSpec struct {
+	// Controller defines the Application Controller options for the agent.
+	Controller ArgoCDAgentControllerSpec `json:"controller,omitempty"`
+
+	// Hub defines the central Argo CD instance that this cluster is registered with.
+	Hub ArgoCDAgentHubSpec `json:"hub"`
+
+	// Image is the Argo CD container image for the Application Controller.
+	Image string `json:"image,omitempty"`
+
+	// Redis defines the Redis server options for the agent.
+	Redis ArgoCDRedisSpec `json:"redis,omitempty"`
+
+	// Version is the tag to use with the Argo CD container image. Argo CD v2.1.0 or later is required.
+	Version string `json:"version,omitempty"`
+}
+
+// ArgoCDAgentStatus defines the observed state of ArgoCDAgent
+// +k8s:openapi-gen=true
+type ArgoCDAgentStatus struct {
+	// LastTokenRotation is the time the ServiceAccount token used by the hub was last regenerated, when token
+	// rotation is enabled.
+	LastTokenRotation *metav1.Time `json:"lastTokenRotation,omitempty"`
+
+	// NextTokenRotation is the time the ServiceAccount token used by the hub will next be regenerated, when token
+	// rotation is enabled.
+	NextTokenRotation *metav1.Time `json:"nextTokenRotation,omitempty"`
+
+	// Phase is a simple, high-level summary of where the ArgoCDAgent is in its lifecycle.
+	// There are three possible phase values:
+	// Pending: The ArgoCDAgent has been accepted by the Kubernetes system, but one or more of the required resources have not been created or are not ready.
+	// Available: The Application Controller is running and the cluster has been registered with the hub.
+	// Failed: The cluster could not be registered with the hub.
+	Phase string `json:"phase,omitempty"`
+
+	// Registered is true when the cluster Secret for this cluster is present in the central Argo CD instance.
+	Registered bool `json:"registered,omitempty"`
+
+	// TokenRevocation is the time the previous ServiceAccount token used by the hub is revoked, while a token rotation
+	// is in progress.
+	TokenRevocation *metav1.Time `json:"tokenRevocation,omitempty"`
+}
+
+// IsDeletionFinalizerPresent checks if the agent has the deletion finalizer
+func (agent *ArgoCDAgent) IsDeletionFinalizerPresent() bool {
+	for _, finalizer := range agent.GetFinalizers() {
+		if finalizer == common.ArgoCDDeletionFinalizer {
+			return true
+		}
+	}
+	return false
+}
+
+func init() {
+	SchemeBuilder.Register(&ArgoCDAgent{}, &ArgoCDAgentList{})
+}
@@ -0,0 +1,70 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
+// Important: Run "operator-sdk generate k8s" to regenerate code after modifying this file
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ArgoCDDefault is the Schema for the argocddefaults API
+// +k8s:openapi-gen=true
+// +kubebuilder:resource:path=argocddefaults,scope=Cluster
+type ArgoCDDefault struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ArgoCDDefaultSpec `json:"spec,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ArgoCDDefaultList contains a list of ArgoCDDefault
+type ArgoCDDefaultList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ArgoCDDefault `json:"items"`
+}
+
+// ArgoCDDefaultSpec defines the organization defaults inherited by every ArgoCD of the cluster. Each property only
+// applies to the ArgoCDs that do not set it.
+// +k8s:openapi-gen=true
+type ArgoCDDefaultSpec struct {
+	// ComplianceMode is the default Pod Security Standard the pods of Argo CD are verified against.
+	ComplianceMode string `json:"complianceMode,omitempty"`
+
+	// Image is the default container image for Argo CD.
+	Image string `json:"image,omitempty"`
+
+	// Profile is the default sizing profile, one of small, medium or large.
+	Profile string `json:"profile,omitempty"`
+
+	// Proxy is the default proxy used by the Argo CD components.
+	Proxy *ArgoCDProxySpec `json:"proxy,omitempty"`
+
+	// TLSMinVersion is the default minimum TLS version accepted by the Argo CD Server, one of 1.0, 1.1, 1.2 or 1.3.
+	TLSMinVersion string `json:"tlsMinVersion,omitempty"`
+
+	// Version is the default tag to use with the Argo CD container image.
+	Version string `json:"version,omitempty"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ArgoCDDefault{}, &ArgoCDDefaultList{})
+}
@@ -57,6 +57,9 @@ type ArgoCDExportSpec struct {
 	// Schedule in Cron format, see https://en.wikipedia.org/wiki/Cron.
 	Schedule *string `json:"schedule,omitempty"`
 
+	// Snapshot defines the options to take CSI VolumeSnapshots of the PersistentVolumeClaims of the export.
+	Snapshot *ArgoCDExportSnapshotSpec `json:"snapshot,omitempty"`
+
 	// Storage defines the storage configuration options.
 	Storage *ArgoCDExportStorageSpec `json:"storage,omitempty"`
 
@@ -75,6 +78,35 @@ type ArgoCDExportStatus struct {
 	// Failed: At least one container has terminated in failure, either exited with non-zero status or was terminated by the system.
 	// Unknown: For some reason the state of the ArgoCDExport could not be obtained.
 	Phase string `json:"phase"`
+
+	// Snapshots is the state of the VolumeSnapshots taken for the export.
+	Snapshots []ArgoCDExportSnapshotStatus `json:"snapshots,omitempty"`
+}
+
+// ArgoCDExportSnapshotSpec defines the desired state for the VolumeSnapshots of an ArgoCDExport.
+type ArgoCDExportSnapshotSpec struct {
+	// Claims are the names of additional PersistentVolumeClaims to snapshot with the export, such as the Keycloak
+	// database volume.
+	Claims []string `json:"claims,omitempty"`
+
+	// VolumeSnapshotClassName is the name of the VolumeSnapshotClass to use, the default class of the cluster is used
+	// when not set.
+	VolumeSnapshotClassName string `json:"volumeSnapshotClassName,omitempty"`
+}
+
+// ArgoCDExportSnapshotStatus defines the observed state of a VolumeSnapshot of an ArgoCDExport.
+type ArgoCDExportSnapshotStatus struct {
+	// ClaimName is the name of the PersistentVolumeClaim of the VolumeSnapshot.
+	ClaimName string `json:"claimName"`
+
+	// Error is the error reported by the VolumeSnapshot, if any.
+	Error string `json:"error,omitempty"`
+
+	// Name is the name of the VolumeSnapshot.
+	Name string `json:"name"`
+
+	// ReadyToUse indicates if the VolumeSnapshot is ready to be used to restore a volume.
+	ReadyToUse bool `json:"readyToUse"`
 }
 
 // ArgoCDExportStorageSpec defines the desired state for ArgoCDExport storage options.
@@ -87,6 +119,11 @@ type ArgoCDExportStorageSpec struct {
 
 	// SecretName is the name of a Secret with encryption key, credentials, etc.
 	SecretName string `json:"secretName,omitempty"`
+
+	// SecretNamespace is the namespace of the Secret named by SecretName, defaults to the namespace of the
+	// ArgoCDExport. A Secret in another namespace must list the ArgoCDExport namespace in its
+	// "argocds.argoproj.io/export-namespaces" annotation and is copied into the namespace of the ArgoCDExport.
+	SecretNamespace string `json:"secretNamespace,omitempty"`
 }
 
 func init() {
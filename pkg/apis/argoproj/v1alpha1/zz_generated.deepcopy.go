@@ -1,3 +1,4 @@
+//go:build !ignore_autogenerated
 // +build !ignore_autogenerated
 
 // Copyright 2021 ArgoCD Operator Developers
@@ -23,6 +24,7 @@ import (
 	autoscalingv1 "k8s.io/api/autoscaling/v1"
 	v1 "k8s.io/api/core/v1"
 	v1beta1 "k8s.io/api/extensions/v1beta1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
@@ -33,7 +35,7 @@ func (in *ArgoCD) DeepCopyInto(out *ArgoCD) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
@@ -56,23 +58,35 @@ func (in *ArgoCD) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ArgoCDApplicationControllerProcessorsSpec) DeepCopyInto(out *ArgoCDApplicationControllerProcessorsSpec) {
+func (in *ArgoCDAgent) DeepCopyInto(out *ArgoCDAgent) {
 	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDApplicationControllerProcessorsSpec.
-func (in *ArgoCDApplicationControllerProcessorsSpec) DeepCopy() *ArgoCDApplicationControllerProcessorsSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDAgent.
+func (in *ArgoCDAgent) DeepCopy() *ArgoCDAgent {
 	if in == nil {
 		return nil
 	}
-	out := new(ArgoCDApplicationControllerProcessorsSpec)
+	out := new(ArgoCDAgent)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ArgoCDAgent) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ArgoCDApplicationControllerSpec) DeepCopyInto(out *ArgoCDApplicationControllerSpec) {
+func (in *ArgoCDAgentControllerSpec) DeepCopyInto(out *ArgoCDAgentControllerSpec) {
 	*out = *in
 	out.Processors = in.Processors
 	if in.Resources != nil {
@@ -80,364 +94,1481 @@ func (in *ArgoCDApplicationControllerSpec) DeepCopyInto(out *ArgoCDApplicationCo
 		*out = new(v1.ResourceRequirements)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.AppSync != nil {
-		in, out := &in.AppSync, &out.AppSync
-		*out = new(metav1.Duration)
-		**out = **in
-	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDApplicationControllerSpec.
-func (in *ArgoCDApplicationControllerSpec) DeepCopy() *ArgoCDApplicationControllerSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDAgentControllerSpec.
+func (in *ArgoCDAgentControllerSpec) DeepCopy() *ArgoCDAgentControllerSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ArgoCDApplicationControllerSpec)
+	out := new(ArgoCDAgentControllerSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ArgoCDApplicationSet) DeepCopyInto(out *ArgoCDApplicationSet) {
+func (in *ArgoCDAgentHubSpec) DeepCopyInto(out *ArgoCDAgentHubSpec) {
 	*out = *in
-	if in.Resources != nil {
-		in, out := &in.Resources, &out.Resources
-		*out = new(v1.ResourceRequirements)
-		(*in).DeepCopyInto(*out)
+	if in.TokenRotationInterval != nil {
+		in, out := &in.TokenRotationInterval, &out.TokenRotationInterval
+		*out = new(metav1.Duration)
+		**out = **in
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDApplicationSet.
-func (in *ArgoCDApplicationSet) DeepCopy() *ArgoCDApplicationSet {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDAgentHubSpec.
+func (in *ArgoCDAgentHubSpec) DeepCopy() *ArgoCDAgentHubSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ArgoCDApplicationSet)
+	out := new(ArgoCDAgentHubSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ArgoCDCASpec) DeepCopyInto(out *ArgoCDCASpec) {
+func (in *ArgoCDAgentList) DeepCopyInto(out *ArgoCDAgentList) {
 	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ArgoCDAgent, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDCASpec.
-func (in *ArgoCDCASpec) DeepCopy() *ArgoCDCASpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDAgentList.
+func (in *ArgoCDAgentList) DeepCopy() *ArgoCDAgentList {
 	if in == nil {
 		return nil
 	}
-	out := new(ArgoCDCASpec)
+	out := new(ArgoCDAgentList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ArgoCDAgentList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ArgoCDCertificateSpec) DeepCopyInto(out *ArgoCDCertificateSpec) {
+func (in *ArgoCDAgentSpec) DeepCopyInto(out *ArgoCDAgentSpec) {
 	*out = *in
+	in.Controller.DeepCopyInto(&out.Controller)
+	in.Hub.DeepCopyInto(&out.Hub)
+	in.Redis.DeepCopyInto(&out.Redis)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDCertificateSpec.
-func (in *ArgoCDCertificateSpec) DeepCopy() *ArgoCDCertificateSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDAgentSpec.
+func (in *ArgoCDAgentSpec) DeepCopy() *ArgoCDAgentSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ArgoCDCertificateSpec)
+	out := new(ArgoCDAgentSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ArgoCDDexOAuthSpec) DeepCopyInto(out *ArgoCDDexOAuthSpec) {
+func (in *ArgoCDAgentStatus) DeepCopyInto(out *ArgoCDAgentStatus) {
 	*out = *in
+	if in.LastTokenRotation != nil {
+		in, out := &in.LastTokenRotation, &out.LastTokenRotation
+		*out = (*in).DeepCopy()
+	}
+	if in.NextTokenRotation != nil {
+		in, out := &in.NextTokenRotation, &out.NextTokenRotation
+		*out = (*in).DeepCopy()
+	}
+	if in.TokenRevocation != nil {
+		in, out := &in.TokenRevocation, &out.TokenRevocation
+		*out = (*in).DeepCopy()
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDDexOAuthSpec.
-func (in *ArgoCDDexOAuthSpec) DeepCopy() *ArgoCDDexOAuthSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDAgentStatus.
+func (in *ArgoCDAgentStatus) DeepCopy() *ArgoCDAgentStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ArgoCDDexOAuthSpec)
+	out := new(ArgoCDAgentStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ArgoCDDexSpec) DeepCopyInto(out *ArgoCDDexSpec) {
+func (in *ArgoCDApplicationControllerClusterCacheSpec) DeepCopyInto(out *ArgoCDApplicationControllerClusterCacheSpec) {
 	*out = *in
-	if in.Resources != nil {
-		in, out := &in.Resources, &out.Resources
-		*out = new(v1.ResourceRequirements)
-		(*in).DeepCopyInto(*out)
+	if in.ResyncDuration != nil {
+		in, out := &in.ResyncDuration, &out.ResyncDuration
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.WatchResyncDuration != nil {
+		in, out := &in.WatchResyncDuration, &out.WatchResyncDuration
+		*out = new(metav1.Duration)
+		**out = **in
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDDexSpec.
-func (in *ArgoCDDexSpec) DeepCopy() *ArgoCDDexSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDApplicationControllerClusterCacheSpec.
+func (in *ArgoCDApplicationControllerClusterCacheSpec) DeepCopy() *ArgoCDApplicationControllerClusterCacheSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ArgoCDDexSpec)
+	out := new(ArgoCDApplicationControllerClusterCacheSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ArgoCDExport) DeepCopyInto(out *ArgoCDExport) {
+func (in *ArgoCDApplicationControllerMetricsSpec) DeepCopyInto(out *ArgoCDApplicationControllerMetricsSpec) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(ArgoCDApplicationControllerMetricsTLSSpec)
+		**out = **in
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDExport.
-func (in *ArgoCDExport) DeepCopy() *ArgoCDExport {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDApplicationControllerMetricsSpec.
+func (in *ArgoCDApplicationControllerMetricsSpec) DeepCopy() *ArgoCDApplicationControllerMetricsSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ArgoCDExport)
+	out := new(ArgoCDApplicationControllerMetricsSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ArgoCDExport) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ArgoCDExportList) DeepCopyInto(out *ArgoCDExportList) {
+func (in *ArgoCDApplicationControllerMetricsTLSSpec) DeepCopyInto(out *ArgoCDApplicationControllerMetricsTLSSpec) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]ArgoCDExport, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDExportList.
-func (in *ArgoCDExportList) DeepCopy() *ArgoCDExportList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDApplicationControllerMetricsTLSSpec.
+func (in *ArgoCDApplicationControllerMetricsTLSSpec) DeepCopy() *ArgoCDApplicationControllerMetricsTLSSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ArgoCDExportList)
+	out := new(ArgoCDApplicationControllerMetricsTLSSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ArgoCDExportList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArgoCDApplicationControllerProcessorsAutosizeSpec) DeepCopyInto(out *ArgoCDApplicationControllerProcessorsAutosizeSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDApplicationControllerProcessorsAutosizeSpec.
+func (in *ArgoCDApplicationControllerProcessorsAutosizeSpec) DeepCopy() *ArgoCDApplicationControllerProcessorsAutosizeSpec {
+	if in == nil {
+		return nil
 	}
-	return nil
+	out := new(ArgoCDApplicationControllerProcessorsAutosizeSpec)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ArgoCDExportSpec) DeepCopyInto(out *ArgoCDExportSpec) {
+func (in *ArgoCDApplicationControllerProcessorsSpec) DeepCopyInto(out *ArgoCDApplicationControllerProcessorsSpec) {
 	*out = *in
-	if in.Schedule != nil {
-		in, out := &in.Schedule, &out.Schedule
-		*out = new(string)
-		**out = **in
-	}
-	if in.Storage != nil {
-		in, out := &in.Storage, &out.Storage
-		*out = new(ArgoCDExportStorageSpec)
-		(*in).DeepCopyInto(*out)
-	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDExportSpec.
-func (in *ArgoCDExportSpec) DeepCopy() *ArgoCDExportSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDApplicationControllerProcessorsSpec.
+func (in *ArgoCDApplicationControllerProcessorsSpec) DeepCopy() *ArgoCDApplicationControllerProcessorsSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ArgoCDExportSpec)
+	out := new(ArgoCDApplicationControllerProcessorsSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ArgoCDExportStatus) DeepCopyInto(out *ArgoCDExportStatus) {
+func (in *ArgoCDApplicationControllerProcessorsStatus) DeepCopyInto(out *ArgoCDApplicationControllerProcessorsStatus) {
 	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDExportStatus.
-func (in *ArgoCDExportStatus) DeepCopy() *ArgoCDExportStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDApplicationControllerProcessorsStatus.
+func (in *ArgoCDApplicationControllerProcessorsStatus) DeepCopy() *ArgoCDApplicationControllerProcessorsStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ArgoCDExportStatus)
+	out := new(ArgoCDApplicationControllerProcessorsStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ArgoCDExportStorageSpec) DeepCopyInto(out *ArgoCDExportStorageSpec) {
+func (in *ArgoCDApplicationControllerShardingSpec) DeepCopyInto(out *ArgoCDApplicationControllerShardingSpec) {
 	*out = *in
-	if in.PVC != nil {
-		in, out := &in.PVC, &out.PVC
-		*out = new(v1.PersistentVolumeClaimSpec)
-		(*in).DeepCopyInto(*out)
-	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDExportStorageSpec.
-func (in *ArgoCDExportStorageSpec) DeepCopy() *ArgoCDExportStorageSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDApplicationControllerShardingSpec.
+func (in *ArgoCDApplicationControllerShardingSpec) DeepCopy() *ArgoCDApplicationControllerShardingSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ArgoCDExportStorageSpec)
+	out := new(ArgoCDApplicationControllerShardingSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ArgoCDGrafanaSpec) DeepCopyInto(out *ArgoCDGrafanaSpec) {
+func (in *ArgoCDApplicationControllerSpec) DeepCopyInto(out *ArgoCDApplicationControllerSpec) {
 	*out = *in
-	in.Ingress.DeepCopyInto(&out.Ingress)
+	if in.AutomountServiceAccountToken != nil {
+		in, out := &in.AutomountServiceAccountToken, &out.AutomountServiceAccountToken
+		*out = new(bool)
+		**out = **in
+	}
+	in.CommandOverride.DeepCopyInto(&out.CommandOverride)
+	if in.DNSConfig != nil {
+		in, out := &in.DNSConfig, &out.DNSConfig
+		*out = new(v1.PodDNSConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HostAliases != nil {
+		in, out := &in.HostAliases, &out.HostAliases
+		*out = make([]v1.HostAlias, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.InitContainerResources != nil {
+		in, out := &in.InitContainerResources, &out.InitContainerResources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	in.Metrics.DeepCopyInto(&out.Metrics)
+	out.Processors = in.Processors
+	if in.ProcessorsAutosize != nil {
+		in, out := &in.ProcessorsAutosize, &out.ProcessorsAutosize
+		*out = new(ArgoCDApplicationControllerProcessorsAutosizeSpec)
+		**out = **in
+	}
 	if in.Resources != nil {
 		in, out := &in.Resources, &out.Resources
 		*out = new(v1.ResourceRequirements)
 		(*in).DeepCopyInto(*out)
 	}
-	in.Route.DeepCopyInto(&out.Route)
-	if in.Size != nil {
-		in, out := &in.Size, &out.Size
-		*out = new(int32)
+	if in.SelfHealTimeout != nil {
+		in, out := &in.SelfHealTimeout, &out.SelfHealTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.StartupProbe != nil {
+		in, out := &in.StartupProbe, &out.StartupProbe
+		*out = new(v1.Probe)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SyncTimeout != nil {
+		in, out := &in.SyncTimeout, &out.SyncTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.AppSync != nil {
+		in, out := &in.AppSync, &out.AppSync
+		*out = new(metav1.Duration)
 		**out = **in
 	}
+	in.ClusterCache.DeepCopyInto(&out.ClusterCache)
+	out.Sharding = in.Sharding
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDGrafanaSpec.
-func (in *ArgoCDGrafanaSpec) DeepCopy() *ArgoCDGrafanaSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDApplicationControllerSpec.
+func (in *ArgoCDApplicationControllerSpec) DeepCopy() *ArgoCDApplicationControllerSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ArgoCDGrafanaSpec)
+	out := new(ArgoCDApplicationControllerSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ArgoCDHASpec) DeepCopyInto(out *ArgoCDHASpec) {
+func (in *ArgoCDApplicationHealthSpec) DeepCopyInto(out *ArgoCDApplicationHealthSpec) {
 	*out = *in
-	if in.Resources != nil {
-		in, out := &in.Resources, &out.Resources
-		*out = new(v1.ResourceRequirements)
-		(*in).DeepCopyInto(*out)
-	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDHASpec.
-func (in *ArgoCDHASpec) DeepCopy() *ArgoCDHASpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDApplicationHealthSpec.
+func (in *ArgoCDApplicationHealthSpec) DeepCopy() *ArgoCDApplicationHealthSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ArgoCDHASpec)
+	out := new(ArgoCDApplicationHealthSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ArgoCDImportSpec) DeepCopyInto(out *ArgoCDImportSpec) {
+func (in *ArgoCDApplicationSet) DeepCopyInto(out *ArgoCDApplicationSet) {
 	*out = *in
-	if in.Namespace != nil {
-		in, out := &in.Namespace, &out.Namespace
-		*out = new(string)
-		**out = **in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SourceNamespaces != nil {
+		in, out := &in.SourceNamespaces, &out.SourceNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.CommandOverride.DeepCopyInto(&out.CommandOverride)
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]v1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ExtraCommandArgs != nil {
+		in, out := &in.ExtraCommandArgs, &out.ExtraCommandArgs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDImportSpec.
-func (in *ArgoCDImportSpec) DeepCopy() *ArgoCDImportSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDApplicationSet.
+func (in *ArgoCDApplicationSet) DeepCopy() *ArgoCDApplicationSet {
 	if in == nil {
 		return nil
 	}
-	out := new(ArgoCDImportSpec)
+	out := new(ArgoCDApplicationSet)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ArgoCDIngressSpec) DeepCopyInto(out *ArgoCDIngressSpec) {
+func (in *ArgoCDApplicationsStatus) DeepCopyInto(out *ArgoCDApplicationsStatus) {
 	*out = *in
-	if in.Annotations != nil {
-		in, out := &in.Annotations, &out.Annotations
-		*out = make(map[string]string, len(*in))
+	if in.Health != nil {
+		in, out := &in.Health, &out.Health
+		*out = make(map[string]int32, len(*in))
 		for key, val := range *in {
 			(*out)[key] = val
 		}
 	}
-	if in.TLS != nil {
-		in, out := &in.TLS, &out.TLS
-		*out = make([]v1beta1.IngressTLS, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
+	if in.Sync != nil {
+		in, out := &in.Sync, &out.Sync
+		*out = make(map[string]int32, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
 		}
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDIngressSpec.
-func (in *ArgoCDIngressSpec) DeepCopy() *ArgoCDIngressSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDApplicationsStatus.
+func (in *ArgoCDApplicationsStatus) DeepCopy() *ArgoCDApplicationsStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ArgoCDIngressSpec)
+	out := new(ArgoCDApplicationsStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ArgoCDList) DeepCopyInto(out *ArgoCDList) {
+func (in *ArgoCDAutoTLSCertificateSpec) DeepCopyInto(out *ArgoCDAutoTLSCertificateSpec) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]ArgoCD, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.RenewBefore != nil {
+		in, out := &in.RenewBefore, &out.RenewBefore
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Validity != nil {
+		in, out := &in.Validity, &out.Validity
+		*out = new(metav1.Duration)
+		**out = **in
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDList.
-func (in *ArgoCDList) DeepCopy() *ArgoCDList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDAutoTLSCertificateSpec.
+func (in *ArgoCDAutoTLSCertificateSpec) DeepCopy() *ArgoCDAutoTLSCertificateSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ArgoCDList)
+	out := new(ArgoCDAutoTLSCertificateSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArgoCDCASpec) DeepCopyInto(out *ArgoCDCASpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDCASpec.
+func (in *ArgoCDCASpec) DeepCopy() *ArgoCDCASpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ArgoCDCASpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArgoCDCLIConfigSpec) DeepCopyInto(out *ArgoCDCLIConfigSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDCLIConfigSpec.
+func (in *ArgoCDCLIConfigSpec) DeepCopy() *ArgoCDCLIConfigSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ArgoCDCLIConfigSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArgoCDCertificateSpec) DeepCopyInto(out *ArgoCDCertificateSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDCertificateSpec.
+func (in *ArgoCDCertificateSpec) DeepCopy() *ArgoCDCertificateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ArgoCDCertificateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArgoCDClusterDiscoverySpec) DeepCopyInto(out *ArgoCDClusterDiscoverySpec) {
+	*out = *in
+	if in.SecretSelector != nil {
+		in, out := &in.SecretSelector, &out.SecretSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TokenRotationInterval != nil {
+		in, out := &in.TokenRotationInterval, &out.TokenRotationInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDClusterDiscoverySpec.
+func (in *ArgoCDClusterDiscoverySpec) DeepCopy() *ArgoCDClusterDiscoverySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ArgoCDClusterDiscoverySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArgoCDClusterTokenRotationStatus) DeepCopyInto(out *ArgoCDClusterTokenRotationStatus) {
+	*out = *in
+	if in.LastRotation != nil {
+		in, out := &in.LastRotation, &out.LastRotation
+		*out = (*in).DeepCopy()
+	}
+	if in.NextRotation != nil {
+		in, out := &in.NextRotation, &out.NextRotation
+		*out = (*in).DeepCopy()
+	}
+	if in.Revocation != nil {
+		in, out := &in.Revocation, &out.Revocation
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDClusterTokenRotationStatus.
+func (in *ArgoCDClusterTokenRotationStatus) DeepCopy() *ArgoCDClusterTokenRotationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ArgoCDClusterTokenRotationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArgoCDCommandOverrideSpec) DeepCopyInto(out *ArgoCDCommandOverrideSpec) {
+	*out = *in
+	if in.Command != nil {
+		in, out := &in.Command, &out.Command
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Args != nil {
+		in, out := &in.Args, &out.Args
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDCommandOverrideSpec.
+func (in *ArgoCDCommandOverrideSpec) DeepCopy() *ArgoCDCommandOverrideSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ArgoCDCommandOverrideSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArgoCDCondition) DeepCopyInto(out *ArgoCDCondition) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDCondition.
+func (in *ArgoCDCondition) DeepCopy() *ArgoCDCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(ArgoCDCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArgoCDConfigDriftStatus) DeepCopyInto(out *ArgoCDConfigDriftStatus) {
+	*out = *in
+	in.LastOverwriteTime.DeepCopyInto(&out.LastOverwriteTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDConfigDriftStatus.
+func (in *ArgoCDConfigDriftStatus) DeepCopy() *ArgoCDConfigDriftStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ArgoCDConfigDriftStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArgoCDConsoleLinkSpec) DeepCopyInto(out *ArgoCDConsoleLinkSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDConsoleLinkSpec.
+func (in *ArgoCDConsoleLinkSpec) DeepCopy() *ArgoCDConsoleLinkSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ArgoCDConsoleLinkSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArgoCDCredentialSecretsSpec) DeepCopyInto(out *ArgoCDCredentialSecretsSpec) {
+	*out = *in
+	if in.Clusters != nil {
+		in, out := &in.Clusters, &out.Clusters
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Repositories != nil {
+		in, out := &in.Repositories, &out.Repositories
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RepositoryTemplates != nil {
+		in, out := &in.RepositoryTemplates, &out.RepositoryTemplates
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDCredentialSecretsSpec.
+func (in *ArgoCDCredentialSecretsSpec) DeepCopy() *ArgoCDCredentialSecretsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ArgoCDCredentialSecretsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArgoCDDefault) DeepCopyInto(out *ArgoCDDefault) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDDefault.
+func (in *ArgoCDDefault) DeepCopy() *ArgoCDDefault {
+	if in == nil {
+		return nil
+	}
+	out := new(ArgoCDDefault)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ArgoCDDefault) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArgoCDDefaultList) DeepCopyInto(out *ArgoCDDefaultList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ArgoCDDefault, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDDefaultList.
+func (in *ArgoCDDefaultList) DeepCopy() *ArgoCDDefaultList {
+	if in == nil {
+		return nil
+	}
+	out := new(ArgoCDDefaultList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ArgoCDDefaultList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArgoCDDefaultSpec) DeepCopyInto(out *ArgoCDDefaultSpec) {
+	*out = *in
+	if in.Proxy != nil {
+		in, out := &in.Proxy, &out.Proxy
+		*out = new(ArgoCDProxySpec)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDDefaultSpec.
+func (in *ArgoCDDefaultSpec) DeepCopy() *ArgoCDDefaultSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ArgoCDDefaultSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArgoCDDexExternalSpec) DeepCopyInto(out *ArgoCDDexExternalSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDDexExternalSpec.
+func (in *ArgoCDDexExternalSpec) DeepCopy() *ArgoCDDexExternalSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ArgoCDDexExternalSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArgoCDDexGroupBindingSpec) DeepCopyInto(out *ArgoCDDexGroupBindingSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDDexGroupBindingSpec.
+func (in *ArgoCDDexGroupBindingSpec) DeepCopy() *ArgoCDDexGroupBindingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ArgoCDDexGroupBindingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArgoCDDexGroupsSpec) DeepCopyInto(out *ArgoCDDexGroupsSpec) {
+	*out = *in
+	if in.Allowed != nil {
+		in, out := &in.Allowed, &out.Allowed
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Bindings != nil {
+		in, out := &in.Bindings, &out.Bindings
+		*out = make([]ArgoCDDexGroupBindingSpec, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDDexGroupsSpec.
+func (in *ArgoCDDexGroupsSpec) DeepCopy() *ArgoCDDexGroupsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ArgoCDDexGroupsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArgoCDDexOAuthSpec) DeepCopyInto(out *ArgoCDDexOAuthSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDDexOAuthSpec.
+func (in *ArgoCDDexOAuthSpec) DeepCopy() *ArgoCDDexOAuthSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ArgoCDDexOAuthSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArgoCDDexSpec) DeepCopyInto(out *ArgoCDDexSpec) {
+	*out = *in
+	if in.AutomountServiceAccountToken != nil {
+		in, out := &in.AutomountServiceAccountToken, &out.AutomountServiceAccountToken
+		*out = new(bool)
+		**out = **in
+	}
+	in.CommandOverride.DeepCopyInto(&out.CommandOverride)
+	if in.DNSConfig != nil {
+		in, out := &in.DNSConfig, &out.DNSConfig
+		*out = new(v1.PodDNSConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HostAliases != nil {
+		in, out := &in.HostAliases, &out.HostAliases
+		*out = make([]v1.HostAlias, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.InitContainerResources != nil {
+		in, out := &in.InitContainerResources, &out.InitContainerResources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LivenessProbe != nil {
+		in, out := &in.LivenessProbe, &out.LivenessProbe
+		*out = new(v1.Probe)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ReadinessProbe != nil {
+		in, out := &in.ReadinessProbe, &out.ReadinessProbe
+		*out = new(v1.Probe)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StaticClients != nil {
+		in, out := &in.StaticClients, &out.StaticClients
+		*out = make([]ArgoCDDexStaticClientSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDDexSpec.
+func (in *ArgoCDDexSpec) DeepCopy() *ArgoCDDexSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ArgoCDDexSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArgoCDDexStaticClientSpec) DeepCopyInto(out *ArgoCDDexStaticClientSpec) {
+	*out = *in
+	if in.RedirectURIs != nil {
+		in, out := &in.RedirectURIs, &out.RedirectURIs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDDexStaticClientSpec.
+func (in *ArgoCDDexStaticClientSpec) DeepCopy() *ArgoCDDexStaticClientSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ArgoCDDexStaticClientSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArgoCDDisasterRecoverySpec) DeepCopyInto(out *ArgoCDDisasterRecoverySpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDDisasterRecoverySpec.
+func (in *ArgoCDDisasterRecoverySpec) DeepCopy() *ArgoCDDisasterRecoverySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ArgoCDDisasterRecoverySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArgoCDExport) DeepCopyInto(out *ArgoCDExport) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDExport.
+func (in *ArgoCDExport) DeepCopy() *ArgoCDExport {
+	if in == nil {
+		return nil
+	}
+	out := new(ArgoCDExport)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ArgoCDExport) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArgoCDExportList) DeepCopyInto(out *ArgoCDExportList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ArgoCDExport, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDExportList.
+func (in *ArgoCDExportList) DeepCopy() *ArgoCDExportList {
+	if in == nil {
+		return nil
+	}
+	out := new(ArgoCDExportList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ArgoCDExportList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArgoCDExportSnapshotSpec) DeepCopyInto(out *ArgoCDExportSnapshotSpec) {
+	*out = *in
+	if in.Claims != nil {
+		in, out := &in.Claims, &out.Claims
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDExportSnapshotSpec.
+func (in *ArgoCDExportSnapshotSpec) DeepCopy() *ArgoCDExportSnapshotSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ArgoCDExportSnapshotSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArgoCDExportSnapshotStatus) DeepCopyInto(out *ArgoCDExportSnapshotStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDExportSnapshotStatus.
+func (in *ArgoCDExportSnapshotStatus) DeepCopy() *ArgoCDExportSnapshotStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ArgoCDExportSnapshotStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArgoCDExportSpec) DeepCopyInto(out *ArgoCDExportSpec) {
+	*out = *in
+	if in.Schedule != nil {
+		in, out := &in.Schedule, &out.Schedule
+		*out = new(string)
+		**out = **in
+	}
+	if in.Snapshot != nil {
+		in, out := &in.Snapshot, &out.Snapshot
+		*out = new(ArgoCDExportSnapshotSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Storage != nil {
+		in, out := &in.Storage, &out.Storage
+		*out = new(ArgoCDExportStorageSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDExportSpec.
+func (in *ArgoCDExportSpec) DeepCopy() *ArgoCDExportSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ArgoCDExportSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArgoCDExportStatus) DeepCopyInto(out *ArgoCDExportStatus) {
+	*out = *in
+	if in.Snapshots != nil {
+		in, out := &in.Snapshots, &out.Snapshots
+		*out = make([]ArgoCDExportSnapshotStatus, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDExportStatus.
+func (in *ArgoCDExportStatus) DeepCopy() *ArgoCDExportStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ArgoCDExportStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArgoCDExportStorageSpec) DeepCopyInto(out *ArgoCDExportStorageSpec) {
+	*out = *in
+	if in.PVC != nil {
+		in, out := &in.PVC, &out.PVC
+		*out = new(v1.PersistentVolumeClaimSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDExportStorageSpec.
+func (in *ArgoCDExportStorageSpec) DeepCopy() *ArgoCDExportStorageSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ArgoCDExportStorageSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArgoCDExternalDNSSpec) DeepCopyInto(out *ArgoCDExternalDNSSpec) {
+	*out = *in
+	if in.Hostnames != nil {
+		in, out := &in.Hostnames, &out.Hostnames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDExternalDNSSpec.
+func (in *ArgoCDExternalDNSSpec) DeepCopy() *ArgoCDExternalDNSSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ArgoCDExternalDNSSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArgoCDExtraRoleRulesSpec) DeepCopyInto(out *ArgoCDExtraRoleRulesSpec) {
+	*out = *in
+	if in.ApplicationController != nil {
+		in, out := &in.ApplicationController, &out.ApplicationController
+		*out = make([]rbacv1.PolicyRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ApplicationSet != nil {
+		in, out := &in.ApplicationSet, &out.ApplicationSet
+		*out = make([]rbacv1.PolicyRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Dex != nil {
+		in, out := &in.Dex, &out.Dex
+		*out = make([]rbacv1.PolicyRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.HA != nil {
+		in, out := &in.HA, &out.HA
+		*out = make([]rbacv1.PolicyRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Server != nil {
+		in, out := &in.Server, &out.Server
+		*out = make([]rbacv1.PolicyRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDExtraRoleRulesSpec.
+func (in *ArgoCDExtraRoleRulesSpec) DeepCopy() *ArgoCDExtraRoleRulesSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ArgoCDExtraRoleRulesSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArgoCDGrafanaExternalSpec) DeepCopyInto(out *ArgoCDGrafanaExternalSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDGrafanaExternalSpec.
+func (in *ArgoCDGrafanaExternalSpec) DeepCopy() *ArgoCDGrafanaExternalSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ArgoCDGrafanaExternalSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArgoCDGrafanaSpec) DeepCopyInto(out *ArgoCDGrafanaSpec) {
+	*out = *in
+	if in.External != nil {
+		in, out := &in.External, &out.External
+		*out = new(ArgoCDGrafanaExternalSpec)
+		**out = **in
+	}
+	in.Ingress.DeepCopyInto(&out.Ingress)
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	in.Route.DeepCopyInto(&out.Route)
+	if in.Size != nil {
+		in, out := &in.Size, &out.Size
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDGrafanaSpec.
+func (in *ArgoCDGrafanaSpec) DeepCopy() *ArgoCDGrafanaSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ArgoCDGrafanaSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArgoCDHAProxySpec) DeepCopyInto(out *ArgoCDHAProxySpec) {
+	*out = *in
+	if in.CheckInterval != nil {
+		in, out := &in.CheckInterval, &out.CheckInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.TimeoutCheck != nil {
+		in, out := &in.TimeoutCheck, &out.TimeoutCheck
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.TimeoutClient != nil {
+		in, out := &in.TimeoutClient, &out.TimeoutClient
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.TimeoutConnect != nil {
+		in, out := &in.TimeoutConnect, &out.TimeoutConnect
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.TimeoutServer != nil {
+		in, out := &in.TimeoutServer, &out.TimeoutServer
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDHAProxySpec.
+func (in *ArgoCDHAProxySpec) DeepCopy() *ArgoCDHAProxySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ArgoCDHAProxySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArgoCDHARedisConfigSpec) DeepCopyInto(out *ArgoCDHARedisConfigSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDHARedisConfigSpec.
+func (in *ArgoCDHARedisConfigSpec) DeepCopy() *ArgoCDHARedisConfigSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ArgoCDHARedisConfigSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArgoCDHASpec) DeepCopyInto(out *ArgoCDHASpec) {
+	*out = *in
+	if in.AutomountServiceAccountToken != nil {
+		in, out := &in.AutomountServiceAccountToken, &out.AutomountServiceAccountToken
+		*out = new(bool)
+		**out = **in
+	}
+	in.HAProxy.DeepCopyInto(&out.HAProxy)
+	if in.InitContainerResources != nil {
+		in, out := &in.InitContainerResources, &out.InitContainerResources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	out.RedisConfig = in.RedisConfig
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDHASpec.
+func (in *ArgoCDHASpec) DeepCopy() *ArgoCDHASpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ArgoCDHASpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArgoCDHelmOCIRegistrySpec) DeepCopyInto(out *ArgoCDHelmOCIRegistrySpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDHelmOCIRegistrySpec.
+func (in *ArgoCDHelmOCIRegistrySpec) DeepCopy() *ArgoCDHelmOCIRegistrySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ArgoCDHelmOCIRegistrySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArgoCDImageUpdaterSpec) DeepCopyInto(out *ArgoCDImageUpdaterSpec) {
+	*out = *in
+	in.CommandOverride.DeepCopyInto(&out.CommandOverride)
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDImageUpdaterSpec.
+func (in *ArgoCDImageUpdaterSpec) DeepCopy() *ArgoCDImageUpdaterSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ArgoCDImageUpdaterSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArgoCDImagesStatus) DeepCopyInto(out *ArgoCDImagesStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDImagesStatus.
+func (in *ArgoCDImagesStatus) DeepCopy() *ArgoCDImagesStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ArgoCDImagesStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArgoCDImportSpec) DeepCopyInto(out *ArgoCDImportSpec) {
+	*out = *in
+	if in.Namespace != nil {
+		in, out := &in.Namespace, &out.Namespace
+		*out = new(string)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDImportSpec.
+func (in *ArgoCDImportSpec) DeepCopy() *ArgoCDImportSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ArgoCDImportSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArgoCDIngressSpec) DeepCopyInto(out *ArgoCDIngressSpec) {
+	*out = *in
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = make([]v1beta1.IngressTLS, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDIngressSpec.
+func (in *ArgoCDIngressSpec) DeepCopy() *ArgoCDIngressSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ArgoCDIngressSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArgoCDKeycloakDatabaseSpec) DeepCopyInto(out *ArgoCDKeycloakDatabaseSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDKeycloakDatabaseSpec.
+func (in *ArgoCDKeycloakDatabaseSpec) DeepCopy() *ArgoCDKeycloakDatabaseSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ArgoCDKeycloakDatabaseSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArgoCDKeycloakSpec) DeepCopyInto(out *ArgoCDKeycloakSpec) {
+	*out = *in
+	if in.Database != nil {
+		in, out := &in.Database, &out.Database
+		*out = new(ArgoCDKeycloakDatabaseSpec)
+		**out = **in
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDKeycloakSpec.
+func (in *ArgoCDKeycloakSpec) DeepCopy() *ArgoCDKeycloakSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ArgoCDKeycloakSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArgoCDKnownHostsAutoScanSpec) DeepCopyInto(out *ArgoCDKnownHostsAutoScanSpec) {
+	*out = *in
+	if in.Hosts != nil {
+		in, out := &in.Hosts, &out.Hosts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDKnownHostsAutoScanSpec.
+func (in *ArgoCDKnownHostsAutoScanSpec) DeepCopy() *ArgoCDKnownHostsAutoScanSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ArgoCDKnownHostsAutoScanSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArgoCDList) DeepCopyInto(out *ArgoCDList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ArgoCD, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDList.
+func (in *ArgoCDList) DeepCopy() *ArgoCDList {
+	if in == nil {
+		return nil
+	}
+	out := new(ArgoCDList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
 func (in *ArgoCDList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
@@ -445,10 +1576,38 @@ func (in *ArgoCDList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArgoCDPodSecurityViolationStatus) DeepCopyInto(out *ArgoCDPodSecurityViolationStatus) {
+	*out = *in
+	if in.Violations != nil {
+		in, out := &in.Violations, &out.Violations
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDPodSecurityViolationStatus.
+func (in *ArgoCDPodSecurityViolationStatus) DeepCopy() *ArgoCDPodSecurityViolationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ArgoCDPodSecurityViolationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ArgoCDPrometheusSpec) DeepCopyInto(out *ArgoCDPrometheusSpec) {
 	*out = *in
 	in.Ingress.DeepCopyInto(&out.Ingress)
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	in.Route.DeepCopyInto(&out.Route)
 	if in.Size != nil {
 		in, out := &in.Size, &out.Size
@@ -458,85 +1617,293 @@ func (in *ArgoCDPrometheusSpec) DeepCopyInto(out *ArgoCDPrometheusSpec) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDPrometheusSpec.
-func (in *ArgoCDPrometheusSpec) DeepCopy() *ArgoCDPrometheusSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDPrometheusSpec.
+func (in *ArgoCDPrometheusSpec) DeepCopy() *ArgoCDPrometheusSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ArgoCDPrometheusSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArgoCDProxySpec) DeepCopyInto(out *ArgoCDProxySpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDProxySpec.
+func (in *ArgoCDProxySpec) DeepCopy() *ArgoCDProxySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ArgoCDProxySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArgoCDRBACSpec) DeepCopyInto(out *ArgoCDRBACSpec) {
+	*out = *in
+	if in.DefaultPolicy != nil {
+		in, out := &in.DefaultPolicy, &out.DefaultPolicy
+		*out = new(string)
+		**out = **in
+	}
+	if in.Policy != nil {
+		in, out := &in.Policy, &out.Policy
+		*out = new(string)
+		**out = **in
+	}
+	if in.Scopes != nil {
+		in, out := &in.Scopes, &out.Scopes
+		*out = new(string)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDRBACSpec.
+func (in *ArgoCDRBACSpec) DeepCopy() *ArgoCDRBACSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ArgoCDRBACSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArgoCDRedisConfigSpec) DeepCopyInto(out *ArgoCDRedisConfigSpec) {
+	*out = *in
+	if in.Save != nil {
+		in, out := &in.Save, &out.Save
+		*out = make([]ArgoCDRedisSaveSpec, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDRedisConfigSpec.
+func (in *ArgoCDRedisConfigSpec) DeepCopy() *ArgoCDRedisConfigSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ArgoCDRedisConfigSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArgoCDRedisSaveSpec) DeepCopyInto(out *ArgoCDRedisSaveSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDRedisSaveSpec.
+func (in *ArgoCDRedisSaveSpec) DeepCopy() *ArgoCDRedisSaveSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ArgoCDRedisSaveSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArgoCDRedisSpec) DeepCopyInto(out *ArgoCDRedisSpec) {
+	*out = *in
+	if in.AutomountServiceAccountToken != nil {
+		in, out := &in.AutomountServiceAccountToken, &out.AutomountServiceAccountToken
+		*out = new(bool)
+		**out = **in
+	}
+	in.CommandOverride.DeepCopyInto(&out.CommandOverride)
+	in.Config.DeepCopyInto(&out.Config)
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.InitContainerResources != nil {
+		in, out := &in.InitContainerResources, &out.InitContainerResources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDRedisSpec.
+func (in *ArgoCDRedisSpec) DeepCopy() *ArgoCDRedisSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ArgoCDRedisSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArgoCDRepoGitRetrySpec) DeepCopyInto(out *ArgoCDRepoGitRetrySpec) {
+	*out = *in
+	if in.Duration != nil {
+		in, out := &in.Duration, &out.Duration
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.MaxDuration != nil {
+		in, out := &in.MaxDuration, &out.MaxDuration
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDRepoGitRetrySpec.
+func (in *ArgoCDRepoGitRetrySpec) DeepCopy() *ArgoCDRepoGitRetrySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ArgoCDRepoGitRetrySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArgoCDRepoSOPSSpec) DeepCopyInto(out *ArgoCDRepoSOPSSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDRepoSOPSSpec.
+func (in *ArgoCDRepoSOPSSpec) DeepCopy() *ArgoCDRepoSOPSSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ArgoCDRepoSOPSSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArgoCDRepoSpec) DeepCopyInto(out *ArgoCDRepoSpec) {
+	*out = *in
+	if in.CacheExpiration != nil {
+		in, out := &in.CacheExpiration, &out.CacheExpiration
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	in.CommandOverride.DeepCopyInto(&out.CommandOverride)
+	in.GitRetry.DeepCopyInto(&out.GitRetry)
+	if in.DNSConfig != nil {
+		in, out := &in.DNSConfig, &out.DNSConfig
+		*out = new(v1.PodDNSConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.FSGroup != nil {
+		in, out := &in.FSGroup, &out.FSGroup
+		*out = new(int64)
+		**out = **in
+	}
+	if in.HostAliases != nil {
+		in, out := &in.HostAliases, &out.HostAliases
+		*out = make([]v1.HostAlias, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.InitContainerResources != nil {
+		in, out := &in.InitContainerResources, &out.InitContainerResources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RunAsUser != nil {
+		in, out := &in.RunAsUser, &out.RunAsUser
+		*out = new(int64)
+		**out = **in
+	}
+	out.SOPS = in.SOPS
+	out.VaultPlugin = in.VaultPlugin
+	if in.VolumeClaims != nil {
+		in, out := &in.VolumeClaims, &out.VolumeClaims
+		*out = make([]ArgoCDRepoVolumeClaimSpec, len(*in))
+		copy(*out, *in)
+	}
+	in.AutoTLSCertificate.DeepCopyInto(&out.AutoTLSCertificate)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDRepoSpec.
+func (in *ArgoCDRepoSpec) DeepCopy() *ArgoCDRepoSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ArgoCDPrometheusSpec)
+	out := new(ArgoCDRepoSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ArgoCDRBACSpec) DeepCopyInto(out *ArgoCDRBACSpec) {
+func (in *ArgoCDRepoVaultPluginSpec) DeepCopyInto(out *ArgoCDRepoVaultPluginSpec) {
 	*out = *in
-	if in.DefaultPolicy != nil {
-		in, out := &in.DefaultPolicy, &out.DefaultPolicy
-		*out = new(string)
-		**out = **in
-	}
-	if in.Policy != nil {
-		in, out := &in.Policy, &out.Policy
-		*out = new(string)
-		**out = **in
-	}
-	if in.Scopes != nil {
-		in, out := &in.Scopes, &out.Scopes
-		*out = new(string)
-		**out = **in
-	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDRBACSpec.
-func (in *ArgoCDRBACSpec) DeepCopy() *ArgoCDRBACSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDRepoVaultPluginSpec.
+func (in *ArgoCDRepoVaultPluginSpec) DeepCopy() *ArgoCDRepoVaultPluginSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ArgoCDRBACSpec)
+	out := new(ArgoCDRepoVaultPluginSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ArgoCDRedisSpec) DeepCopyInto(out *ArgoCDRedisSpec) {
+func (in *ArgoCDRepoVolumeClaimSpec) DeepCopyInto(out *ArgoCDRepoVolumeClaimSpec) {
 	*out = *in
-	if in.Resources != nil {
-		in, out := &in.Resources, &out.Resources
-		*out = new(v1.ResourceRequirements)
-		(*in).DeepCopyInto(*out)
-	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDRedisSpec.
-func (in *ArgoCDRedisSpec) DeepCopy() *ArgoCDRedisSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDRepoVolumeClaimSpec.
+func (in *ArgoCDRepoVolumeClaimSpec) DeepCopy() *ArgoCDRepoVolumeClaimSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ArgoCDRedisSpec)
+	out := new(ArgoCDRepoVolumeClaimSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ArgoCDRepoSpec) DeepCopyInto(out *ArgoCDRepoSpec) {
+func (in *ArgoCDResourceUsageStatus) DeepCopyInto(out *ArgoCDResourceUsageStatus) {
 	*out = *in
-	if in.Resources != nil {
-		in, out := &in.Resources, &out.Resources
-		*out = new(v1.ResourceRequirements)
-		(*in).DeepCopyInto(*out)
-	}
+	in.SampleTime.DeepCopyInto(&out.SampleTime)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDRepoSpec.
-func (in *ArgoCDRepoSpec) DeepCopy() *ArgoCDRepoSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDResourceUsageStatus.
+func (in *ArgoCDResourceUsageStatus) DeepCopy() *ArgoCDResourceUsageStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ArgoCDRepoSpec)
+	out := new(ArgoCDResourceUsageStatus)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -551,6 +1918,13 @@ func (in *ArgoCDRouteSpec) DeepCopyInto(out *ArgoCDRouteSpec) {
 			(*out)[key] = val
 		}
 	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	if in.TLS != nil {
 		in, out := &in.TLS, &out.TLS
 		*out = new(routev1.TLSConfig)
@@ -574,9 +1948,51 @@ func (in *ArgoCDRouteSpec) DeepCopy() *ArgoCDRouteSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArgoCDSSODexSpec) DeepCopyInto(out *ArgoCDSSODexSpec) {
+	*out = *in
+	in.ArgoCDDexSpec.DeepCopyInto(&out.ArgoCDDexSpec)
+	if in.External != nil {
+		in, out := &in.External, &out.External
+		*out = new(ArgoCDDexExternalSpec)
+		**out = **in
+	}
+	if in.Groups != nil {
+		in, out := &in.Groups, &out.Groups
+		*out = new(ArgoCDDexGroupsSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDSSODexSpec.
+func (in *ArgoCDSSODexSpec) DeepCopy() *ArgoCDSSODexSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ArgoCDSSODexSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ArgoCDSSOSpec) DeepCopyInto(out *ArgoCDSSOSpec) {
 	*out = *in
+	if in.Dex != nil {
+		in, out := &in.Dex, &out.Dex
+		*out = new(ArgoCDSSODexSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Keycloak != nil {
+		in, out := &in.Keycloak, &out.Keycloak
+		*out = new(ArgoCDKeycloakSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.VerifyTLS != nil {
+		in, out := &in.VerifyTLS, &out.VerifyTLS
+		*out = new(bool)
+		**out = **in
+	}
 	return
 }
 
@@ -590,6 +2006,22 @@ func (in *ArgoCDSSOSpec) DeepCopy() *ArgoCDSSOSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArgoCDSecretKeySpec) DeepCopyInto(out *ArgoCDSecretKeySpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDSecretKeySpec.
+func (in *ArgoCDSecretKeySpec) DeepCopy() *ArgoCDSecretKeySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ArgoCDSecretKeySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ArgoCDServerAutoscaleSpec) DeepCopyInto(out *ArgoCDServerAutoscaleSpec) {
 	*out = *in
@@ -611,6 +2043,22 @@ func (in *ArgoCDServerAutoscaleSpec) DeepCopy() *ArgoCDServerAutoscaleSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArgoCDServerExtensionSpec) DeepCopyInto(out *ArgoCDServerExtensionSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDServerExtensionSpec.
+func (in *ArgoCDServerExtensionSpec) DeepCopy() *ArgoCDServerExtensionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ArgoCDServerExtensionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ArgoCDServerGRPCSpec) DeepCopyInto(out *ArgoCDServerGRPCSpec) {
 	*out = *in
@@ -628,6 +2076,59 @@ func (in *ArgoCDServerGRPCSpec) DeepCopy() *ArgoCDServerGRPCSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArgoCDServerIngressSpec) DeepCopyInto(out *ArgoCDServerIngressSpec) {
+	*out = *in
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = make([]v1beta1.IngressTLS, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDServerIngressSpec.
+func (in *ArgoCDServerIngressSpec) DeepCopy() *ArgoCDServerIngressSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ArgoCDServerIngressSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArgoCDServerProxyExtensionSpec) DeepCopyInto(out *ArgoCDServerProxyExtensionSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDServerProxyExtensionSpec.
+func (in *ArgoCDServerProxyExtensionSpec) DeepCopy() *ArgoCDServerProxyExtensionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ArgoCDServerProxyExtensionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ArgoCDServerServiceSpec) DeepCopyInto(out *ArgoCDServerServiceSpec) {
 	*out = *in
@@ -644,12 +2145,71 @@ func (in *ArgoCDServerServiceSpec) DeepCopy() *ArgoCDServerServiceSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArgoCDServerSessionSpec) DeepCopyInto(out *ArgoCDServerSessionSpec) {
+	*out = *in
+	if in.Duration != nil {
+		in, out := &in.Duration, &out.Duration
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.IDTokenExpiration != nil {
+		in, out := &in.IDTokenExpiration, &out.IDTokenExpiration
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDServerSessionSpec.
+func (in *ArgoCDServerSessionSpec) DeepCopy() *ArgoCDServerSessionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ArgoCDServerSessionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ArgoCDServerSpec) DeepCopyInto(out *ArgoCDServerSpec) {
 	*out = *in
+	if in.AutomountServiceAccountToken != nil {
+		in, out := &in.AutomountServiceAccountToken, &out.AutomountServiceAccountToken
+		*out = new(bool)
+		**out = **in
+	}
 	in.Autoscale.DeepCopyInto(&out.Autoscale)
+	in.CommandOverride.DeepCopyInto(&out.CommandOverride)
+	out.ConsoleLink = in.ConsoleLink
 	in.GRPC.DeepCopyInto(&out.GRPC)
+	if in.DNSConfig != nil {
+		in, out := &in.DNSConfig, &out.DNSConfig
+		*out = new(v1.PodDNSConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	in.ExternalDNS.DeepCopyInto(&out.ExternalDNS)
+	if in.Extensions != nil {
+		in, out := &in.Extensions, &out.Extensions
+		*out = make([]ArgoCDServerExtensionSpec, len(*in))
+		copy(*out, *in)
+	}
+	if in.HostAliases != nil {
+		in, out := &in.HostAliases, &out.HostAliases
+		*out = make([]v1.HostAlias, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	in.Ingress.DeepCopyInto(&out.Ingress)
+	if in.Ingresses != nil {
+		in, out := &in.Ingresses, &out.Ingresses
+		*out = make([]ArgoCDServerIngressSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	out.ProxyExtension = in.ProxyExtension
 	if in.Resources != nil {
 		in, out := &in.Resources, &out.Resources
 		*out = new(v1.ResourceRequirements)
@@ -657,6 +2217,13 @@ func (in *ArgoCDServerSpec) DeepCopyInto(out *ArgoCDServerSpec) {
 	}
 	in.Route.DeepCopyInto(&out.Route)
 	out.Service = in.Service
+	in.Session.DeepCopyInto(&out.Session)
+	if in.StartupProbe != nil {
+		in, out := &in.StartupProbe, &out.StartupProbe
+		*out = new(v1.Probe)
+		(*in).DeepCopyInto(*out)
+	}
+	in.TLS.DeepCopyInto(&out.TLS)
 	return
 }
 
@@ -670,6 +2237,27 @@ func (in *ArgoCDServerSpec) DeepCopy() *ArgoCDServerSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArgoCDServerTLSSpec) DeepCopyInto(out *ArgoCDServerTLSSpec) {
+	*out = *in
+	if in.Ciphers != nil {
+		in, out := &in.Ciphers, &out.Ciphers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDServerTLSSpec.
+func (in *ArgoCDServerTLSSpec) DeepCopy() *ArgoCDServerTLSSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ArgoCDServerTLSSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ArgoCDSpec) DeepCopyInto(out *ArgoCDSpec) {
 	*out = *in
@@ -678,27 +2266,69 @@ func (in *ArgoCDSpec) DeepCopyInto(out *ArgoCDSpec) {
 		*out = new(ArgoCDApplicationSet)
 		(*in).DeepCopyInto(*out)
 	}
+	out.CLIConfig = in.CLIConfig
+	in.ClusterDiscovery.DeepCopyInto(&out.ClusterDiscovery)
 	in.Controller.DeepCopyInto(&out.Controller)
+	in.CredentialSecrets.DeepCopyInto(&out.CredentialSecrets)
 	in.Dex.DeepCopyInto(&out.Dex)
+	if in.DisasterRecovery != nil {
+		in, out := &in.DisasterRecovery, &out.DisasterRecovery
+		*out = new(ArgoCDDisasterRecoverySpec)
+		**out = **in
+	}
+	in.ExtraRoleRules.DeepCopyInto(&out.ExtraRoleRules)
 	in.Grafana.DeepCopyInto(&out.Grafana)
 	in.HA.DeepCopyInto(&out.HA)
+	if in.HelmOCIRegistries != nil {
+		in, out := &in.HelmOCIRegistries, &out.HelmOCIRegistries
+		*out = make([]ArgoCDHelmOCIRegistrySpec, len(*in))
+		copy(*out, *in)
+	}
+	in.ImageUpdater.DeepCopyInto(&out.ImageUpdater)
 	if in.Import != nil {
 		in, out := &in.Import, &out.Import
 		*out = new(ArgoCDImportSpec)
 		(*in).DeepCopyInto(*out)
 	}
 	out.InitialSSHKnownHosts = in.InitialSSHKnownHosts
+	if in.IPFamilies != nil {
+		in, out := &in.IPFamilies, &out.IPFamilies
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.KnownHostsAutoScan.DeepCopyInto(&out.KnownHostsAutoScan)
 	in.Prometheus.DeepCopyInto(&out.Prometheus)
+	if in.Proxy != nil {
+		in, out := &in.Proxy, &out.Proxy
+		*out = new(ArgoCDProxySpec)
+		**out = **in
+	}
 	in.RBAC.DeepCopyInto(&out.RBAC)
+	if in.ReconcileInterval != nil {
+		in, out := &in.ReconcileInterval, &out.ReconcileInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
 	in.Redis.DeepCopyInto(&out.Redis)
 	in.Repo.DeepCopyInto(&out.Repo)
+	if in.ResourceHealthChecksFrom != nil {
+		in, out := &in.ResourceHealthChecksFrom, &out.ResourceHealthChecksFrom
+		*out = make([]ConfigMapRef, len(*in))
+		copy(*out, *in)
+	}
+	if in.SecretKeys != nil {
+		in, out := &in.SecretKeys, &out.SecretKeys
+		*out = make([]ArgoCDSecretKeySpec, len(*in))
+		copy(*out, *in)
+	}
 	in.Server.DeepCopyInto(&out.Server)
 	if in.SSO != nil {
 		in, out := &in.SSO, &out.SSO
 		*out = new(ArgoCDSSOSpec)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	in.TLS.DeepCopyInto(&out.TLS)
+	out.UpgradeStrategy = in.UpgradeStrategy
 	return
 }
 
@@ -715,6 +2345,62 @@ func (in *ArgoCDSpec) DeepCopy() *ArgoCDSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ArgoCDStatus) DeepCopyInto(out *ArgoCDStatus) {
 	*out = *in
+	if in.Applications != nil {
+		in, out := &in.Applications, &out.Applications
+		*out = new(ArgoCDApplicationsStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ClusterTokenRotations != nil {
+		in, out := &in.ClusterTokenRotations, &out.ClusterTokenRotations
+		*out = make([]ArgoCDClusterTokenRotationStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]ArgoCDCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ControllerProcessors != nil {
+		in, out := &in.ControllerProcessors, &out.ControllerProcessors
+		*out = new(ArgoCDApplicationControllerProcessorsStatus)
+		**out = **in
+	}
+	if in.ConfigDrift != nil {
+		in, out := &in.ConfigDrift, &out.ConfigDrift
+		*out = make([]ArgoCDConfigDriftStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	out.Images = in.Images
+	if in.ManagedNamespaces != nil {
+		in, out := &in.ManagedNamespaces, &out.ManagedNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.MigratedSSO != nil {
+		in, out := &in.MigratedSSO, &out.MigratedSSO
+		*out = new(ArgoCDSSOSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodSecurityViolations != nil {
+		in, out := &in.PodSecurityViolations, &out.PodSecurityViolations
+		*out = make([]ArgoCDPodSecurityViolationStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ResourceUsage != nil {
+		in, out := &in.ResourceUsage, &out.ResourceUsage
+		*out = make([]ArgoCDResourceUsageStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -732,6 +2418,16 @@ func (in *ArgoCDStatus) DeepCopy() *ArgoCDStatus {
 func (in *ArgoCDTLSSpec) DeepCopyInto(out *ArgoCDTLSSpec) {
 	*out = *in
 	out.CA = in.CA
+	if in.CertsFrom != nil {
+		in, out := &in.CertsFrom, &out.CertsFrom
+		*out = make([]ConfigMapRef, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExtraSANs != nil {
+		in, out := &in.ExtraSANs, &out.ExtraSANs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.InitialCerts != nil {
 		in, out := &in.InitialCerts, &out.InitialCerts
 		*out = make(map[string]string, len(*in))
@@ -752,6 +2448,38 @@ func (in *ArgoCDTLSSpec) DeepCopy() *ArgoCDTLSSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArgoCDUpgradeStrategySpec) DeepCopyInto(out *ArgoCDUpgradeStrategySpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDUpgradeStrategySpec.
+func (in *ArgoCDUpgradeStrategySpec) DeepCopy() *ArgoCDUpgradeStrategySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ArgoCDUpgradeStrategySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigMapRef) DeepCopyInto(out *ConfigMapRef) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigMapRef.
+func (in *ConfigMapRef) DeepCopy() *ConfigMapRef {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigMapRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SSHHostsSpec) DeepCopyInto(out *SSHHostsSpec) {
 	*out = *in
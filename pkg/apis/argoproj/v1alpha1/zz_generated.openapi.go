@@ -1,3 +1,4 @@
+//go:build !ignore_autogenerated
 // +build !ignore_autogenerated
 
 // Copyright 2021 ArgoCD Operator Developers
@@ -28,6 +29,11 @@ import (
 func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenAPIDefinition {
 	return map[string]common.OpenAPIDefinition{
 		"./pkg/apis/argoproj/v1alpha1.ArgoCD":             schema_pkg_apis_argoproj_v1alpha1_ArgoCD(ref),
+		"./pkg/apis/argoproj/v1alpha1.ArgoCDAgent":        schema_pkg_apis_argoproj_v1alpha1_ArgoCDAgent(ref),
+		"./pkg/apis/argoproj/v1alpha1.ArgoCDAgentSpec":    schema_pkg_apis_argoproj_v1alpha1_ArgoCDAgentSpec(ref),
+		"./pkg/apis/argoproj/v1alpha1.ArgoCDAgentStatus":  schema_pkg_apis_argoproj_v1alpha1_ArgoCDAgentStatus(ref),
+		"./pkg/apis/argoproj/v1alpha1.ArgoCDDefault":      schema_pkg_apis_argoproj_v1alpha1_ArgoCDDefault(ref),
+		"./pkg/apis/argoproj/v1alpha1.ArgoCDDefaultSpec":  schema_pkg_apis_argoproj_v1alpha1_ArgoCDDefaultSpec(ref),
 		"./pkg/apis/argoproj/v1alpha1.ArgoCDExport":       schema_pkg_apis_argoproj_v1alpha1_ArgoCDExport(ref),
 		"./pkg/apis/argoproj/v1alpha1.ArgoCDExportSpec":   schema_pkg_apis_argoproj_v1alpha1_ArgoCDExportSpec(ref),
 		"./pkg/apis/argoproj/v1alpha1.ArgoCDExportStatus": schema_pkg_apis_argoproj_v1alpha1_ArgoCDExportStatus(ref),
@@ -80,6 +86,240 @@ func schema_pkg_apis_argoproj_v1alpha1_ArgoCD(ref common.ReferenceCallback) comm
 	}
 }
 
+func schema_pkg_apis_argoproj_v1alpha1_ArgoCDAgent(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ArgoCDAgent is the Schema for the argocdagents API",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("./pkg/apis/argoproj/v1alpha1.ArgoCDAgentSpec"),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("./pkg/apis/argoproj/v1alpha1.ArgoCDAgentStatus"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"./pkg/apis/argoproj/v1alpha1.ArgoCDAgentSpec", "./pkg/apis/argoproj/v1alpha1.ArgoCDAgentStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
+func schema_pkg_apis_argoproj_v1alpha1_ArgoCDAgentSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ArgoCDAgentSpec defines the desired state of ArgoCDAgent",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"controller": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Controller defines the Application Controller options for the agent.",
+							Ref:         ref("./pkg/apis/argoproj/v1alpha1.ArgoCDAgentControllerSpec"),
+						},
+					},
+					"hub": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Hub defines the central Argo CD instance that this cluster is registered with.",
+							Ref:         ref("./pkg/apis/argoproj/v1alpha1.ArgoCDAgentHubSpec"),
+						},
+					},
+					"image": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Image is the Argo CD container image for the Application Controller.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"redis": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Redis defines the Redis server options for the agent.",
+							Ref:         ref("./pkg/apis/argoproj/v1alpha1.ArgoCDRedisSpec"),
+						},
+					},
+					"version": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Version is the tag to use with the Argo CD container image. Argo CD v2.1.0 or later is required.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"hub"},
+			},
+		},
+		Dependencies: []string{
+			"./pkg/apis/argoproj/v1alpha1.ArgoCDAgentControllerSpec", "./pkg/apis/argoproj/v1alpha1.ArgoCDAgentHubSpec", "./pkg/apis/argoproj/v1alpha1.ArgoCDRedisSpec"},
+	}
+}
+
+func schema_pkg_apis_argoproj_v1alpha1_ArgoCDAgentStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ArgoCDAgentStatus defines the observed state of ArgoCDAgent",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"lastTokenRotation": {
+						SchemaProps: spec.SchemaProps{
+							Description: "LastTokenRotation is the time the ServiceAccount token used by the hub was last regenerated, when token rotation is enabled.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"nextTokenRotation": {
+						SchemaProps: spec.SchemaProps{
+							Description: "NextTokenRotation is the time the ServiceAccount token used by the hub will next be regenerated, when token rotation is enabled.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"phase": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Phase is a simple, high-level summary of where the ArgoCDAgent is in its lifecycle. There are three possible phase values: Pending: The ArgoCDAgent has been accepted by the Kubernetes system, but one or more of the required resources have not been created or are not ready. Available: The Application Controller is running and the cluster has been registered with the hub. Failed: The cluster could not be registered with the hub.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"registered": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Registered is true when the cluster Secret for this cluster is present in the central Argo CD instance.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"tokenRevocation": {
+						SchemaProps: spec.SchemaProps{
+							Description: "TokenRevocation is the time the previous ServiceAccount token used by the hub is revoked, while a token rotation is in progress.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+	}
+}
+
+func schema_pkg_apis_argoproj_v1alpha1_ArgoCDDefault(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ArgoCDDefault is the Schema for the argocddefaults API",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("./pkg/apis/argoproj/v1alpha1.ArgoCDDefaultSpec"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"./pkg/apis/argoproj/v1alpha1.ArgoCDDefaultSpec", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
+func schema_pkg_apis_argoproj_v1alpha1_ArgoCDDefaultSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ArgoCDDefaultSpec defines the organization defaults inherited by every ArgoCD of the cluster. Each property only applies to the ArgoCDs that do not set it.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"complianceMode": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ComplianceMode is the default Pod Security Standard the pods of Argo CD are verified against.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"image": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Image is the default container image for Argo CD.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"profile": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Profile is the default sizing profile, one of small, medium or large.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"proxy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Proxy is the default proxy used by the Argo CD components.",
+							Ref:         ref("./pkg/apis/argoproj/v1alpha1.ArgoCDProxySpec"),
+						},
+					},
+					"tlsMinVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "TLSMinVersion is the default minimum TLS version accepted by the Argo CD Server, one of 1.0, 1.1, 1.2 or 1.3.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"version": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Version is the default tag to use with the Argo CD container image.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"./pkg/apis/argoproj/v1alpha1.ArgoCDProxySpec"},
+	}
+}
+
 func schema_pkg_apis_argoproj_v1alpha1_ArgoCDExport(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -152,6 +392,12 @@ func schema_pkg_apis_argoproj_v1alpha1_ArgoCDExportSpec(ref common.ReferenceCall
 							Format:      "",
 						},
 					},
+					"snapshot": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Snapshot defines the options to take CSI VolumeSnapshots of the PersistentVolumeClaims of the export.",
+							Ref:         ref("./pkg/apis/argoproj/v1alpha1.ArgoCDExportSnapshotSpec"),
+						},
+					},
 					"storage": {
 						SchemaProps: spec.SchemaProps{
 							Description: "Storage defines the storage configuration options.",
@@ -170,7 +416,7 @@ func schema_pkg_apis_argoproj_v1alpha1_ArgoCDExportSpec(ref common.ReferenceCall
 			},
 		},
 		Dependencies: []string{
-			"./pkg/apis/argoproj/v1alpha1.ArgoCDExportStorageSpec"},
+			"./pkg/apis/argoproj/v1alpha1.ArgoCDExportSnapshotSpec", "./pkg/apis/argoproj/v1alpha1.ArgoCDExportStorageSpec"},
 	}
 }
 
@@ -188,10 +434,25 @@ func schema_pkg_apis_argoproj_v1alpha1_ArgoCDExportStatus(ref common.ReferenceCa
 							Format:      "",
 						},
 					},
+					"snapshots": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Snapshots is the state of the VolumeSnapshots taken for the export.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("./pkg/apis/argoproj/v1alpha1.ArgoCDExportSnapshotStatus"),
+									},
+								},
+							},
+						},
+					},
 				},
 				Required: []string{"phase"},
 			},
 		},
+		Dependencies: []string{
+			"./pkg/apis/argoproj/v1alpha1.ArgoCDExportSnapshotStatus"},
 	}
 }
 
@@ -208,6 +469,12 @@ func schema_pkg_apis_argoproj_v1alpha1_ArgoCDSpec(ref common.ReferenceCallback)
 							Ref:         ref("./pkg/apis/argoproj/v1alpha1.ArgoCDApplicationSet"),
 						},
 					},
+					"applicationHealth": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ApplicationHealth defines the summary of the health and sync status of the Applications into the status.",
+							Ref:         ref("./pkg/apis/argoproj/v1alpha1.ArgoCDApplicationHealthSpec"),
+						},
+					},
 					"applicationInstanceLabelKey": {
 						SchemaProps: spec.SchemaProps{
 							Description: "ApplicationInstanceLabelKey is the key name where Argo CD injects the app name as a tracking label.",
@@ -215,6 +482,25 @@ func schema_pkg_apis_argoproj_v1alpha1_ArgoCDSpec(ref common.ReferenceCallback)
 							Format:      "",
 						},
 					},
+					"cliConfig": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CLIConfig defines the options for publishing the connection details of the Argo CD server for the argocd CLI.",
+							Ref:         ref("./pkg/apis/argoproj/v1alpha1.ArgoCDCLIConfigSpec"),
+						},
+					},
+					"clusterDiscovery": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ClusterDiscovery defines the options for registering clusters automatically from kubeconfig Secrets.",
+							Ref:         ref("./pkg/apis/argoproj/v1alpha1.ArgoCDClusterDiscoverySpec"),
+						},
+					},
+					"complianceMode": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ComplianceMode is the Pod Security Standard the pods of Argo CD are verified against before their workloads are created or updated. The only supported value is restricted. Violations are reported in the status.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 					"configManagementPlugins": {
 						SchemaProps: spec.SchemaProps{
 							Description: "ConfigManagementPlugins is used to specify additional config management plugins.",
@@ -228,9 +514,29 @@ func schema_pkg_apis_argoproj_v1alpha1_ArgoCDSpec(ref common.ReferenceCallback)
 							Ref:         ref("./pkg/apis/argoproj/v1alpha1.ArgoCDApplicationControllerSpec"),
 						},
 					},
+					"credentialSecrets": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CredentialSecrets defines the label selectors for pre-existing Secrets holding credentials for ArgoCD.",
+							Ref:         ref("./pkg/apis/argoproj/v1alpha1.ArgoCDCredentialSecretsSpec"),
+						},
+					},
+					"defaultClusterScopedRole": {
+						SchemaProps: spec.SchemaProps{
+							Description: "DefaultClusterScopedRole is the name of an existing ClusterRole that is bound to the Application Controller instead of the ClusterRole generated by the operator.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"deletionPolicy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "DeletionPolicy defines what happens to the Applications and AppProjects in the namespace of the ArgoCD when it is deleted. Valid values are Orphan, Cascade and StripFinalizers. Orphan is used when not set.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 					"dex": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Dex defines the Dex server options for ArgoCD.",
+							Description: "Dex defines the Dex server options for ArgoCD. Deprecated: use .spec.sso.dex with the dex SSO provider instead.",
 							Ref:         ref("./pkg/apis/argoproj/v1alpha1.ArgoCDDexSpec"),
 						},
 					},
@@ -241,6 +547,18 @@ func schema_pkg_apis_argoproj_v1alpha1_ArgoCDSpec(ref common.ReferenceCallback)
 							Format:      "",
 						},
 					},
+					"disasterRecovery": {
+						SchemaProps: spec.SchemaProps{
+							Description: "DisasterRecovery defines the options for mirroring the critical Secrets into a disaster recovery namespace.",
+							Ref:         ref("./pkg/apis/argoproj/v1alpha1.ArgoCDDisasterRecoverySpec"),
+						},
+					},
+					"extraRoleRules": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ExtraRoleRules defines additional policy rules that are added to the Roles and ClusterRoles of the components.",
+							Ref:         ref("./pkg/apis/argoproj/v1alpha1.ArgoCDExtraRoleRulesSpec"),
+						},
+					},
 					"gaTrackingID": {
 						SchemaProps: spec.SchemaProps{
 							Description: "GATrackingID is the google analytics tracking ID to use.",
@@ -267,6 +585,26 @@ func schema_pkg_apis_argoproj_v1alpha1_ArgoCDSpec(ref common.ReferenceCallback)
 							Ref:         ref("./pkg/apis/argoproj/v1alpha1.ArgoCDHASpec"),
 						},
 					},
+					"helmOCIRegistries": {
+						SchemaProps: spec.SchemaProps{
+							Description: "HelmOCIRegistries defines the OCI registries hosting Helm charts, for which repository credentials are created.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("./pkg/apis/argoproj/v1alpha1.ArgoCDHelmOCIRegistrySpec"),
+									},
+								},
+							},
+						},
+					},
+					"hibernate": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Hibernate will scale all Argo CD workloads down to zero replicas when set to true. The previous replica counts are restored when Hibernate is set back to false.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
 					"helpChatURL": {
 						SchemaProps: spec.SchemaProps{
 							Description: "HelpChatURL is the URL for getting chat help, this will typically be your Slack channel for support.",
@@ -288,6 +626,19 @@ func schema_pkg_apis_argoproj_v1alpha1_ArgoCDSpec(ref common.ReferenceCallback)
 							Format:      "",
 						},
 					},
+					"imageRegistry": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ImageRegistry is the registry used in place of the registry of the default images for all ArgoCD components, e.g. a mirror for disconnected installs. Images set explicitly on the ArgoCD are not affected.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"imageUpdater": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ImageUpdater defines the Argo CD Image Updater options for ArgoCD.",
+							Ref:         ref("./pkg/apis/argoproj/v1alpha1.ArgoCDImageUpdaterSpec"),
+						},
+					},
 					"import": {
 						SchemaProps: spec.SchemaProps{
 							Description: "Import is the import/restore options for ArgoCD.",
@@ -307,6 +658,33 @@ func schema_pkg_apis_argoproj_v1alpha1_ArgoCDSpec(ref common.ReferenceCallback)
 							Ref:         ref("./pkg/apis/argoproj/v1alpha1.SSHHostsSpec"),
 						},
 					},
+					"ipFamilies": {
+						SchemaProps: spec.SchemaProps{
+							Description: "IPFamilies are the IP families of the Services created by the operator, in order of preference. Valid values are IPv4 and IPv6. The cluster default is used when not set.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"ipFamilyPolicy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "IPFamilyPolicy is the dual-stack policy of the Services created by the operator. Valid values are SingleStack, PreferDualStack and RequireDualStack. The cluster default is used when not set.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"knownHostsAutoScan": {
+						SchemaProps: spec.SchemaProps{
+							Description: "KnownHostsAutoScan defines the Git hosts whose SSH host keys are scanned on a schedule and kept up to date in the SSH known hosts.",
+							Ref:         ref("./pkg/apis/argoproj/v1alpha1.ArgoCDKnownHostsAutoScanSpec"),
+						},
+					},
 					"kustomizeBuildOptions": {
 						SchemaProps: spec.SchemaProps{
 							Description: "KustomizeBuildOptions is used to specify build options/parameters to use with `kustomize build`.",
@@ -321,18 +699,44 @@ func schema_pkg_apis_argoproj_v1alpha1_ArgoCDSpec(ref common.ReferenceCallback)
 							Format:      "",
 						},
 					},
+					"oidcInsecureSkipVerify": {
+						SchemaProps: spec.SchemaProps{
+							Description: "OIDCInsecureSkipVerify disables the verification of the TLS certificate of the OIDC provider by Argo CD.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"profile": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Profile is the name of a sizing profile, one of small, medium or large, setting coherent default resources, replicas and processor counts across the Argo CD components. Properties set explicitly take precedence.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 					"prometheus": {
 						SchemaProps: spec.SchemaProps{
 							Description: "Prometheus defines the Prometheus server options for ArgoCD.",
 							Ref:         ref("./pkg/apis/argoproj/v1alpha1.ArgoCDPrometheusSpec"),
 						},
 					},
+					"proxy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Proxy defines the proxy used by the Argo CD components, instead of the proxy in the environment of the operator.",
+							Ref:         ref("./pkg/apis/argoproj/v1alpha1.ArgoCDProxySpec"),
+						},
+					},
 					"rbac": {
 						SchemaProps: spec.SchemaProps{
 							Description: "RBAC defines the RBAC configuration for Argo CD.",
 							Ref:         ref("./pkg/apis/argoproj/v1alpha1.ArgoCDRBACSpec"),
 						},
 					},
+					"reconcileInterval": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ReconcileInterval is the interval at which the ArgoCD is reconciled to correct any drift of its resources, in addition to the reconciles triggered by changes. Defaults to the resync period of the operator.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
 					"redis": {
 						SchemaProps: spec.SchemaProps{
 							Description: "Redis defines the Redis server options for ArgoCD.",
@@ -359,6 +763,19 @@ func schema_pkg_apis_argoproj_v1alpha1_ArgoCDSpec(ref common.ReferenceCallback)
 							Format:      "",
 						},
 					},
+					"resourceHealthChecksFrom": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ResourceHealthChecksFrom references ConfigMaps in the namespace of the ArgoCD with Lua health checks to add to the resource customizations. Each key is a resource in the form group_Kind, or Kind for the core group.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("./pkg/apis/argoproj/v1alpha1.ConfigMapRef"),
+									},
+								},
+							},
+						},
+					},
 					"resourceExclusions": {
 						SchemaProps: spec.SchemaProps{
 							Description: "ResourceExclusions is used to completely ignore entire classes of resource group/kinds.",
@@ -373,6 +790,19 @@ func schema_pkg_apis_argoproj_v1alpha1_ArgoCDSpec(ref common.ReferenceCallback)
 							Format:      "",
 						},
 					},
+					"secretKeys": {
+						SchemaProps: spec.SchemaProps{
+							Description: "SecretKeys are the keys of the argocd-secret Secret whose values are sourced from other Secrets instead of being managed by the operator. Other keys that are not managed by the operator are left untouched.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("./pkg/apis/argoproj/v1alpha1.ArgoCDSecretKeySpec"),
+									},
+								},
+							},
+						},
+					},
 					"server": {
 						SchemaProps: spec.SchemaProps{
 							Description: "Server defines the options for the ArgoCD Server component.",
@@ -398,6 +828,12 @@ func schema_pkg_apis_argoproj_v1alpha1_ArgoCDSpec(ref common.ReferenceCallback)
 							Ref:         ref("./pkg/apis/argoproj/v1alpha1.ArgoCDTLSSpec"),
 						},
 					},
+					"upgradeStrategy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "UpgradeStrategy defines the strategy used to roll out a new Argo CD version to the components.",
+							Ref:         ref("./pkg/apis/argoproj/v1alpha1.ArgoCDUpgradeStrategySpec"),
+						},
+					},
 					"usersAnonymousEnabled": {
 						SchemaProps: spec.SchemaProps{
 							Description: "UsersAnonymousEnabled toggles anonymous user access. The anonymous users get default role permissions specified argocd-rbac-cm.",
@@ -416,7 +852,7 @@ func schema_pkg_apis_argoproj_v1alpha1_ArgoCDSpec(ref common.ReferenceCallback)
 			},
 		},
 		Dependencies: []string{
-			"./pkg/apis/argoproj/v1alpha1.ArgoCDApplicationControllerSpec", "./pkg/apis/argoproj/v1alpha1.ArgoCDApplicationSet", "./pkg/apis/argoproj/v1alpha1.ArgoCDDexSpec", "./pkg/apis/argoproj/v1alpha1.ArgoCDGrafanaSpec", "./pkg/apis/argoproj/v1alpha1.ArgoCDHASpec", "./pkg/apis/argoproj/v1alpha1.ArgoCDImportSpec", "./pkg/apis/argoproj/v1alpha1.ArgoCDPrometheusSpec", "./pkg/apis/argoproj/v1alpha1.ArgoCDRBACSpec", "./pkg/apis/argoproj/v1alpha1.ArgoCDRedisSpec", "./pkg/apis/argoproj/v1alpha1.ArgoCDRepoSpec", "./pkg/apis/argoproj/v1alpha1.ArgoCDSSOSpec", "./pkg/apis/argoproj/v1alpha1.ArgoCDServerSpec", "./pkg/apis/argoproj/v1alpha1.ArgoCDTLSSpec", "./pkg/apis/argoproj/v1alpha1.SSHHostsSpec"},
+			"./pkg/apis/argoproj/v1alpha1.ArgoCDApplicationControllerSpec", "./pkg/apis/argoproj/v1alpha1.ArgoCDApplicationHealthSpec", "./pkg/apis/argoproj/v1alpha1.ArgoCDApplicationSet", "./pkg/apis/argoproj/v1alpha1.ArgoCDCLIConfigSpec", "./pkg/apis/argoproj/v1alpha1.ArgoCDClusterDiscoverySpec", "./pkg/apis/argoproj/v1alpha1.ArgoCDCredentialSecretsSpec", "./pkg/apis/argoproj/v1alpha1.ArgoCDDexSpec", "./pkg/apis/argoproj/v1alpha1.ArgoCDDisasterRecoverySpec", "./pkg/apis/argoproj/v1alpha1.ArgoCDExtraRoleRulesSpec", "./pkg/apis/argoproj/v1alpha1.ArgoCDGrafanaSpec", "./pkg/apis/argoproj/v1alpha1.ArgoCDHASpec", "./pkg/apis/argoproj/v1alpha1.ArgoCDHelmOCIRegistrySpec", "./pkg/apis/argoproj/v1alpha1.ArgoCDImageUpdaterSpec", "./pkg/apis/argoproj/v1alpha1.ArgoCDImportSpec", "./pkg/apis/argoproj/v1alpha1.ArgoCDKnownHostsAutoScanSpec", "./pkg/apis/argoproj/v1alpha1.ArgoCDPrometheusSpec", "./pkg/apis/argoproj/v1alpha1.ArgoCDProxySpec", "./pkg/apis/argoproj/v1alpha1.ArgoCDRBACSpec", "./pkg/apis/argoproj/v1alpha1.ArgoCDRedisSpec", "./pkg/apis/argoproj/v1alpha1.ArgoCDRepoSpec", "./pkg/apis/argoproj/v1alpha1.ArgoCDSSOSpec", "./pkg/apis/argoproj/v1alpha1.ArgoCDSecretKeySpec", "./pkg/apis/argoproj/v1alpha1.ArgoCDServerSpec", "./pkg/apis/argoproj/v1alpha1.ArgoCDTLSSpec", "./pkg/apis/argoproj/v1alpha1.ArgoCDUpgradeStrategySpec", "./pkg/apis/argoproj/v1alpha1.ConfigMapRef", "./pkg/apis/argoproj/v1alpha1.SSHHostsSpec", "k8s.io/apimachinery/pkg/apis/meta/v1.Duration"},
 	}
 }
 
@@ -434,6 +870,39 @@ func schema_pkg_apis_argoproj_v1alpha1_ArgoCDStatus(ref common.ReferenceCallback
 							Format:      "",
 						},
 					},
+					"applicationCount": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ApplicationCount is the number of Applications observed in the namespace of the ArgoCD.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"applications": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Applications summarizes the health and sync status of the Applications in the namespace of the ArgoCD, when enabled in the ApplicationHealth spec.",
+							Ref:         ref("./pkg/apis/argoproj/v1alpha1.ArgoCDApplicationsStatus"),
+						},
+					},
+					"appProjectCount": {
+						SchemaProps: spec.SchemaProps{
+							Description: "AppProjectCount is the number of AppProjects observed in the namespace of the ArgoCD.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"clusterTokenRotations": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ClusterTokenRotations is the state of the rotation of the ServiceAccount tokens of the clusters registered by the cluster discovery, when token rotation is enabled.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("./pkg/apis/argoproj/v1alpha1.ArgoCDClusterTokenRotationStatus"),
+									},
+								},
+							},
+						},
+					},
 					"dex": {
 						SchemaProps: spec.SchemaProps{
 							Description: "Dex is a simple, high-level summary of where the Argo CD Dex component is in its lifecycle. There are five possible dex values: Pending: The Argo CD Dex component has been accepted by the Kubernetes system, but one or more of the required resources have not been created. Running: All of the required Pods for the Argo CD Dex component are in a Ready state. Failed: At least one of the  Argo CD Dex component Pods had a failure. Unknown: For some reason the state of the Argo CD Dex component could not be obtained.",
@@ -441,6 +910,71 @@ func schema_pkg_apis_argoproj_v1alpha1_ArgoCDStatus(ref common.ReferenceCallback
 							Format:      "",
 						},
 					},
+					"controllerProcessors": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ControllerProcessors contains the number of Application Controller processors sized by the operator when autosizing of the processors is enabled.",
+							Ref:         ref("./pkg/apis/argoproj/v1alpha1.ArgoCDApplicationControllerProcessorsStatus"),
+						},
+					},
+					"conditions": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Conditions contains the latest observations of the state of the ArgoCD, such as whether a component has failed to roll out.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("./pkg/apis/argoproj/v1alpha1.ArgoCDCondition"),
+									},
+								},
+							},
+						},
+					},
+					"configDrift": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ConfigDrift lists the keys of the argocd-cm and argocd-rbac-cm ConfigMaps that were changed outside of the operator and overwritten with the desired state of the ArgoCD.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("./pkg/apis/argoproj/v1alpha1.ArgoCDConfigDriftStatus"),
+									},
+								},
+							},
+						},
+					},
+					"host": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Host is the hostname of the Argo CD server, from its Route, Ingress, external host or Service in that order.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"images": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Images contains the container images resolved by the operator for each of the Argo CD components.",
+							Ref:         ref("./pkg/apis/argoproj/v1alpha1.ArgoCDImagesStatus"),
+						},
+					},
+					"managedNamespaces": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ManagedNamespaces are the namespaces managed by the ArgoCD, including its own namespace.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"migratedSSO": {
+						SchemaProps: spec.SchemaProps{
+							Description: "MigratedSSO contains the .spec.sso equivalent of the deprecated .spec.dex field and DISABLE_DEX environment variable while the ArgoCD still relies on them.",
+							Ref:         ref("./pkg/apis/argoproj/v1alpha1.ArgoCDSSOSpec"),
+						},
+					},
 					"phase": {
 						SchemaProps: spec.SchemaProps{
 							Description: "Phase is a simple, high-level summary of where the ArgoCD is in its lifecycle. There are five possible phase values: Pending: The ArgoCD has been accepted by the Kubernetes system, but one or more of the required resources have not been created. Available: All of the resources for the ArgoCD are ready. Failed: At least one resource has experienced a failure. Unknown: For some reason the state of the ArgoCD phase could not be obtained.",
@@ -448,9 +982,22 @@ func schema_pkg_apis_argoproj_v1alpha1_ArgoCDStatus(ref common.ReferenceCallback
 							Format:      "",
 						},
 					},
+					"podSecurityViolations": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PodSecurityViolations lists the violations of the Pod Security Standard of the ComplianceMode by the pods of each Argo CD workload.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("./pkg/apis/argoproj/v1alpha1.ArgoCDPodSecurityViolationStatus"),
+									},
+								},
+							},
+						},
+					},
 					"redis": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Redis is a simple, high-level summary of where the Argo CD Redis component is in its lifecycle. There are five possible redis values: Pending: The Argo CD Redis component has been accepted by the Kubernetes system, but one or more of the required resources have not been created. Running: All of the required Pods for the Argo CD Redis component are in a Ready state. Failed: At least one of the  Argo CD Redis component Pods had a failure. Unknown: For some reason the state of the Argo CD Redis component could not be obtained.",
+							Description: "Redis is a simple, high-level summary of where the Argo CD Redis component is in its lifecycle. There are five possible redis values: Pending: The Argo CD Redis component has been accepted by the Kubernetes system, but one or more of the required resources have not been created. Running: All of the required Pods for the Argo CD Redis component are in a Ready state. Failed: At least one of the  Argo CD Redis component Pods had a failure. Unknown: For some reason the state of the Argo CD Redis component could not be obtained. Remote: The Argo CD components use a remote Redis that is not managed by the operator.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
@@ -462,6 +1009,19 @@ func schema_pkg_apis_argoproj_v1alpha1_ArgoCDStatus(ref common.ReferenceCallback
 							Format:      "",
 						},
 					},
+					"resourceUsage": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ResourceUsage contains the latest snapshots of the resource usage of the Application Controller and Repo Server, sampled periodically from the metrics-server when it is installed.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("./pkg/apis/argoproj/v1alpha1.ArgoCDResourceUsageStatus"),
+									},
+								},
+							},
+						},
+					},
 					"server": {
 						SchemaProps: spec.SchemaProps{
 							Description: "Server is a simple, high-level summary of where the Argo CD server component is in its lifecycle. There are five possible server values: Pending: The Argo CD server component has been accepted by the Kubernetes system, but one or more of the required resources have not been created. Running: All of the required Pods for the Argo CD server component are in a Ready state. Failed: At least one of the  Argo CD server component Pods had a failure. Unknown: For some reason the state of the Argo CD server component could not be obtained.",
@@ -476,8 +1036,17 @@ func schema_pkg_apis_argoproj_v1alpha1_ArgoCDStatus(ref common.ReferenceCallback
 							Format:      "",
 						},
 					},
+					"version": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Version is the tag or digest of the Argo CD container image used by the application controller, repo server and server.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 				},
 			},
 		},
+		Dependencies: []string{
+			"./pkg/apis/argoproj/v1alpha1.ArgoCDApplicationControllerProcessorsStatus", "./pkg/apis/argoproj/v1alpha1.ArgoCDApplicationsStatus", "./pkg/apis/argoproj/v1alpha1.ArgoCDClusterTokenRotationStatus", "./pkg/apis/argoproj/v1alpha1.ArgoCDCondition", "./pkg/apis/argoproj/v1alpha1.ArgoCDConfigDriftStatus", "./pkg/apis/argoproj/v1alpha1.ArgoCDImagesStatus", "./pkg/apis/argoproj/v1alpha1.ArgoCDPodSecurityViolationStatus", "./pkg/apis/argoproj/v1alpha1.ArgoCDResourceUsageStatus", "./pkg/apis/argoproj/v1alpha1.ArgoCDSSOSpec"},
 	}
 }
@@ -8,4 +8,20 @@ const (
 	// AnnotationNamespace is the annotation on child resources that specifies which ArgoCD instance
 	// namespace a specific object is associated with
 	AnnotationNamespace = "argocds.argoproj.io/namespace"
+
+	// AnnotationRotateSSOClientSecret is the annotation on an ArgoCD instance that requests the rotation of the
+	// OIDC client secret used between Argo CD and its SSO provider
+	AnnotationRotateSSOClientSecret = "argocds.argoproj.io/rotate-sso-client-secret"
+
+	// AnnotationRotateSSOClientSecretServerKey is the value of the AnnotationRotateSSOClientSecret annotation that
+	// opts in to regenerating the server signature key, from which Argo CD derives the Dex client secret
+	AnnotationRotateSSOClientSecretServerKey = "server-secretkey"
+
+	// AnnotationHibernatedReplicas is the annotation on child workloads that records the replica count
+	// to restore when an ArgoCD instance is no longer hibernated
+	AnnotationHibernatedReplicas = "argocds.argoproj.io/hibernated-replicas"
+
+	// AnnotationAppliedGeneration is the annotation on the Argo CD ConfigMaps that records the generation of the
+	// ArgoCD instance the operator last wrote them for
+	AnnotationAppliedGeneration = "argocds.argoproj.io/applied-generation"
 )
@@ -21,9 +21,15 @@ const (
 	// ArgoCDServerComponent is the name of the Dex server control plane component
 	ArgoCDServerComponent = "argocd-server"
 
-	// ArgoCDRedisHAComponent is the name of the Redis control plane component
+	// ArgoCDRedisComponent is the name of the Redis control plane component
+	ArgoCDRedisComponent = "argocd-redis"
+
+	// ArgoCDRedisHAComponent is the name of the Redis HA control plane component
 	ArgoCDRedisHAComponent = "argocd-redis-ha"
 
+	// ArgoCDRedisHAProxyComponent is the name of the Redis HA Proxy control plane component
+	ArgoCDRedisHAProxyComponent = "argocd-redis-ha-haproxy"
+
 	// ArgoCDDexServerComponent is the name of the Dex server control plane component
 	ArgoCDDexServerComponent = "argocd-dex-server"
 
@@ -42,6 +48,14 @@ const (
 	// ArgoCDDefaultApplicationSetVersion is the Argo CD Application Set image tag to use when not specified.
 	ArgoCDDefaultApplicationSetVersion = "v0.1.0"
 
+	// ArgoCDDefaultApplicationControllerMetricsPort is the default listen port for the Argo CD application controller
+	// metrics.
+	ArgoCDDefaultApplicationControllerMetricsPort = 8082
+
+	// ArgoCDDefaultApplicationControllerMetricsTLSPort is the default port for the Argo CD application controller
+	// metrics when TLS is enabled.
+	ArgoCDDefaultApplicationControllerMetricsTLSPort = 8443
+
 	// ArgoCDDefaultApplicationInstanceLabelKey is the default app name as a tracking label.
 	ArgoCDDefaultApplicationInstanceLabelKey = "mycompany.com/appname"
 
@@ -51,6 +65,13 @@ const (
 	// ArgoCDDefaultArgoVersion is the Argo CD container image digest to use when version not specified.
 	ArgoCDDefaultArgoVersion = "sha256:8d1d58ef963f615da97e0b2c54dbe243801d5e7198b98393ab36b7a5768f72a4" // v2.0.0
 
+	// ArgoCDDefaultArgoVersionName is the Argo CD version of the default container image digest.
+	ArgoCDDefaultArgoVersionName = "v2.0.0"
+
+	// ArgoCDDefaultAgentVersion is the Argo CD container image tag to use for an ArgoCDAgent when version not
+	// specified. The agent requires Argo CD v2.1.0 or later to verify the TLS certificate of the hub Repo Server.
+	ArgoCDDefaultAgentVersion = "v2.1.0"
+
 	// ArgoCDDefaultBackupKeyLength is the length of the generated default backup key.
 	ArgoCDDefaultBackupKeyLength = 32
 
@@ -63,6 +84,13 @@ const (
 	// ArgoCDDefaultConfigManagementPlugins is the default configuration value for the config management plugins.
 	ArgoCDDefaultConfigManagementPlugins = ""
 
+	// ArgoCDDefaultConsoleLinkImageURL is the default icon for the OpenShift ConsoleLink of the Argo CD Server.
+	ArgoCDDefaultConsoleLinkImageURL = "https://raw.githubusercontent.com/argoproj/argo-cd/master/docs/assets/argo.png"
+
+	// ArgoCDDefaultConsoleLinkSection is the default application menu section for the OpenShift ConsoleLink of the
+	// Argo CD Server.
+	ArgoCDDefaultConsoleLinkSection = "Argo CD"
+
 	// ArgoCDDefaultControllerResourceLimitCPU is the default CPU limit when not specified for the Argo CD application
 	// controller contianer.
 	ArgoCDDefaultControllerResourceLimitCPU = "1000m"
@@ -94,6 +122,9 @@ const (
 	// ArgoCDDefaultDexHTTPPort is the default HTTP listen port for Dex.
 	ArgoCDDefaultDexHTTPPort = 5556
 
+	// ArgoCDDefaultDexHealthzPath is the path of the health endpoint of Dex, under the issuer path used by Argo CD.
+	ArgoCDDefaultDexHealthzPath = "/api/dex/healthz"
+
 	// ArgoCDDefaultDexServiceAccountName is the default Service Account name for the Dex server.
 	ArgoCDDefaultDexServiceAccountName = "argocd-dex-server"
 
@@ -104,11 +135,18 @@ const (
 	ArgoCDDefaultExportJobImage = "quay.io/jmckind/argocd-operator-util"
 
 	// ArgoCDDefaultExportJobVersion is the export job container image tag to use when not specified.
-	ArgoCDDefaultExportJobVersion = "sha256:dd0b52626828629ebf614ec86ed7914119e7f1efcfebcb5da52502582e0797a1" // v0.0.15
+	// The util image of v0.0.16 is the first to ship the scan-known-hosts command of the SSH known hosts scans.
+	ArgoCDDefaultExportJobVersion = "v0.0.16"
 
 	// ArgoCDDefaultExportLocalCapicity is the default capacity to use for local export.
 	ArgoCDDefaultExportLocalCapicity = "2Gi"
 
+	// ArgoCDDefaultExtensionInstallerImage is the extension installer container image to use when not specified.
+	ArgoCDDefaultExtensionInstallerImage = "quay.io/argoprojlabs/argocd-extension-installer"
+
+	// ArgoCDDefaultExtensionInstallerVersion is the extension installer container image tag to use when not specified.
+	ArgoCDDefaultExtensionInstallerVersion = "v0.0.1"
+
 	// ArgoCDDefaultGATrackingID is the default Google Analytics tracking ID.
 	ArgoCDDefaultGATrackingID = ""
 
@@ -154,18 +192,67 @@ const (
 	// ArgoCDDefaultHelpChatText is the default help chat text.
 	ArgoCDDefaultHelpChatText = "Chat now!"
 
+	// ArgoCDDefaultImageUpdaterImage is the Argo CD Image Updater container image to use when not specified.
+	ArgoCDDefaultImageUpdaterImage = "argoprojlabs/argocd-image-updater"
+
+	// ArgoCDDefaultImageUpdaterVersion is the Argo CD Image Updater image tag to use when not specified.
+	ArgoCDDefaultImageUpdaterVersion = "v0.10.1"
+
+	// ArgoCDDefaultImagesPath is the directory the default images ConfigMap is mounted at in the operator.
+	ArgoCDDefaultImagesPath = "/etc/argocd-operator/images"
+
 	// ArgoCDDefaultIngressPath is the path to use for the Ingress when not specified.
 	ArgoCDDefaultIngressPath = "/"
 
+	// ArgoCDDefaultInitContainerResourceLimitCPU is the default CPU limit of an init container when the main container
+	// of the component has resource requirements.
+	ArgoCDDefaultInitContainerResourceLimitCPU = "100m"
+
+	// ArgoCDDefaultInitContainerResourceLimitMemory is the default memory limit of an init container when the main
+	// container of the component has resource requirements.
+	ArgoCDDefaultInitContainerResourceLimitMemory = "128Mi"
+
+	// ArgoCDDefaultInitContainerResourceRequestCPU is the default CPU requested by an init container when the main
+	// container of the component has resource requirements.
+	ArgoCDDefaultInitContainerResourceRequestCPU = "10m"
+
+	// ArgoCDDefaultInitContainerResourceRequestMemory is the default memory requested by an init container when the
+	// main container of the component has resource requirements.
+	ArgoCDDefaultInitContainerResourceRequestMemory = "32Mi"
+
+	// ArgoCDDefaultKSOPSImage is the KSOPS container image to use when not specified.
+	ArgoCDDefaultKSOPSImage = "viaductoss/ksops"
+
+	// ArgoCDDefaultKSOPSVersion is the KSOPS container image tag to use when not specified.
+	ArgoCDDefaultKSOPSVersion = "v3.0.1"
+
+	// ArgoCDDefaultKnownHostsScanSchedule is the default Cron schedule of the SSH known hosts scans.
+	ArgoCDDefaultKnownHostsScanSchedule = "0 */6 * * *"
+
 	// ArgoCDDefaultKustomizeBuildOptions is the default kustomize build options.
 	ArgoCDDefaultKustomizeBuildOptions = ""
 
+	// ArgoCDDefaultMetricsTLSProxyImage is the default image for the proxy that serves metrics over TLS.
+	ArgoCDDefaultMetricsTLSProxyImage = "ghostunnel/ghostunnel"
+
+	// ArgoCDDefaultMetricsTLSProxyVersion is the default image tag for the proxy that serves metrics over TLS.
+	ArgoCDDefaultMetricsTLSProxyVersion = "v1.6.0"
+
 	// ArgoCDKeycloakImageName is the default Keycloak Image used when not specified.
 	ArgoCDKeycloakImageName = "sso74-openshift-rhel8"
 
 	// ArgoCDKeycloakVersion is the default Keycloak version used when not specified.
 	ArgoCDKeycloakVersion = "7.4"
 
+	// ArgoCDDefaultOAuthClientSecretLength is the length of the generated OAuth client secrets.
+	ArgoCDDefaultOAuthClientSecretLength = 32
+
+	// ArgoCDDefaultOAuthClientSecretNumDigits is the number of digits to use for the generated OAuth client secrets.
+	ArgoCDDefaultOAuthClientSecretNumDigits = 8
+
+	// ArgoCDDefaultOAuthClientSecretNumSymbols is the number of symbols to use for the generated OAuth client secrets.
+	ArgoCDDefaultOAuthClientSecretNumSymbols = 0
+
 	// ArgoCDDefaultOIDCConfig is the default OIDC configuration.
 	ArgoCDDefaultOIDCConfig = ""
 
@@ -178,6 +265,9 @@ const (
 	// ArgoCDDefaultRBACDefaultPolicy is the default Argo CD RBAC policy.
 	ArgoCDDefaultRBACDefaultPolicy = "role:readonly"
 
+	// ArgoCDDefaultDexGroupsScope is the default OIDC scope holding the groups of the users authenticated with Dex.
+	ArgoCDDefaultDexGroupsScope = "groups"
+
 	// ArgoCDDefaultRBACScopes is the default Argo CD RBAC scopes.
 	ArgoCDDefaultRBACScopes = "[groups]"
 
@@ -187,6 +277,20 @@ const (
 	// ArgoCDDefaultRedisHAReplicas is the defaul number of replicas for Redis when rinning in HA mode.
 	ArgoCDDefaultRedisHAReplicas = int32(3)
 
+	// ArgoCDDefaultRedisHADownAfterMilliseconds is the default time in milliseconds before a Redis server in HA mode
+	// is considered down by the sentinels.
+	ArgoCDDefaultRedisHADownAfterMilliseconds = int32(10000)
+
+	// ArgoCDDefaultRedisHAFailoverTimeout is the default time in milliseconds allowed for a failover of Redis in HA mode.
+	ArgoCDDefaultRedisHAFailoverTimeout = int32(180000)
+
+	// ArgoCDDefaultRedisHAMaxMemory is the default memory limit of Redis when running in HA mode, 0 being no limit.
+	ArgoCDDefaultRedisHAMaxMemory = "0"
+
+	// ArgoCDDefaultRedisMaxMemoryPolicy is the default eviction policy of Redis, so that the cache evicts keys instead
+	// of rejecting writes when the memory limit is reached.
+	ArgoCDDefaultRedisMaxMemoryPolicy = "volatile-lru"
+
 	// ArgoCDDefaultRedisHAProxyImage is the default Redis HAProxy image to use when not specified.
 	ArgoCDDefaultRedisHAProxyImage = "haproxy"
 
@@ -217,6 +321,13 @@ const (
 	// ArgoCDDefaultRepoServerPort is the default listen port for the Argo CD repo server.
 	ArgoCDDefaultRepoServerPort = 8081
 
+	// ArgoCDDefaultRepoServerReplicas is the default replica count for the Argo CD repo server.
+	ArgoCDDefaultRepoServerReplicas = int32(1)
+
+	// ArgoCDDefaultRepoServerHAReplicas is the default and minimum replica count for the Argo CD repo server when
+	// running in HA mode.
+	ArgoCDDefaultRepoServerHAReplicas = int32(2)
+
 	// ArgoCDDefaultRepositories is the default repositories.
 	ArgoCDDefaultRepositories = ""
 
@@ -235,6 +346,14 @@ const (
 	// ArgoCDDefaultRSAKeySize is the default RSA key size when not specified.
 	ArgoCDDefaultRSAKeySize = 2048
 
+	// ArgoCDDefaultServerMaxOperationProcessors is the upper bound of the ArgoCD Server Operation Processors sized by
+	// the operator when not specified.
+	ArgoCDDefaultServerMaxOperationProcessors = int32(50)
+
+	// ArgoCDDefaultServerMaxStatusProcessors is the upper bound of the ArgoCD Server Status Processors sized by the
+	// operator when not specified.
+	ArgoCDDefaultServerMaxStatusProcessors = int32(100)
+
 	// ArgoCDDefaultServerOperationProcessors is the number of ArgoCD Server Operation Processors to use when not specified.
 	ArgoCDDefaultServerOperationProcessors = int32(10)
 
@@ -271,4 +390,13 @@ gitlab.com ssh-rsa AAAAB3NzaC1yc2EAAAADAQABAAABAQCsj2bNKTBSpIYDEGk9KxsGh3mySTRgM
 ssh.dev.azure.com ssh-rsa AAAAB3NzaC1yc2EAAAADAQABAAABAQC7Hr1oTWqNqOlzGJOfGJ4NakVyIzf1rXYd4d7wo6jBlkLvCA4odBlL0mDUyZ0/QUfTTqeu+tm22gOsv+VrVTMk6vwRU75gY/y9ut5Mb3bR5BV58dKXyq9A9UeB5Cakehn5Zgm6x1mKoVyf+FFn26iYqXJRgzIZZcZ5V6hrE0Qg39kZm4az48o0AUbf6Sp4SLdvnuMa2sVNwHBboS7EJkm57XQPVU3/QpyNLHbWDdzwtrlS+ez30S3AdYhLKEOxAG8weOnyrtLJAUen9mTkol8oII1edf7mWWbWVf0nBmly21+nZcmCTISQBtdcyPaEno7fFQMDD26/s0lfKob4Kw8H
 vs-ssh.visualstudio.com ssh-rsa AAAAB3NzaC1yc2EAAAADAQABAAABAQC7Hr1oTWqNqOlzGJOfGJ4NakVyIzf1rXYd4d7wo6jBlkLvCA4odBlL0mDUyZ0/QUfTTqeu+tm22gOsv+VrVTMk6vwRU75gY/y9ut5Mb3bR5BV58dKXyq9A9UeB5Cakehn5Zgm6x1mKoVyf+FFn26iYqXJRgzIZZcZ5V6hrE0Qg39kZm4az48o0AUbf6Sp4SLdvnuMa2sVNwHBboS7EJkm57XQPVU3/QpyNLHbWDdzwtrlS+ez30S3AdYhLKEOxAG8weOnyrtLJAUen9mTkol8oII1edf7mWWbWVf0nBmly21+nZcmCTISQBtdcyPaEno7fFQMDD26/s0lfKob4Kw8H
 `
+
+	// ArgoCDDefaultVaultPluginCredentialsSecret is the name of the Secret holding the argocd-vault-plugin configuration when not specified.
+	ArgoCDDefaultVaultPluginCredentialsSecret = "argocd-vault-plugin-credentials"
+
+	// ArgoCDDefaultVaultPluginImage is the container image used to download the argocd-vault-plugin when not specified.
+	ArgoCDDefaultVaultPluginImage = "alpine:3.14"
+
+	// ArgoCDDefaultVaultPluginVersion is the argocd-vault-plugin release to install when not specified.
+	ArgoCDDefaultVaultPluginVersion = "1.7.0"
 )
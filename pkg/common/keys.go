@@ -23,9 +23,19 @@ const (
 	// ArgoCDKeyAdminEnabled is the configuration key for the admin enabled setting..
 	ArgoCDKeyAdminEnabled = "admin.enabled"
 
+	// ArgoCDKeyAgentKubeconfig is the key for the hub kubeconfig in the Secret referenced by an ArgoCDAgent.
+	ArgoCDKeyAgentKubeconfig = "kubeconfig"
+
+	// ArgoCDKeyApplicationLinks is the configuration key for the deep links shown for Applications.
+	ArgoCDKeyApplicationLinks = "application.links"
+
 	// ArgoCDKeyApplicationInstanceLabelKey is the configuration key for the application instance label.
 	ArgoCDKeyApplicationInstanceLabelKey = "application.instanceLabelKey"
 
+	// ArgoCDKeyAutoCreateTemplate is the key for the ArgoCD manifest in a template ConfigMap used to create ArgoCD
+	// instances in labeled namespaces.
+	ArgoCDKeyAutoCreateTemplate = "argocd.yaml"
+
 	// ArgoCDKeyAdminPassword is the admin password key for labels.
 	ArgoCDKeyAdminPassword = "admin.password"
 
@@ -35,18 +45,43 @@ const (
 	// ArgoCDKeyBackupKey is the "backup key" key for ConfigMaps.
 	ArgoCDKeyBackupKey = "backup.key"
 
+	// ArgoCDKeyCLIServer is the key for the address of the Argo CD server in the CLI ConfigMap.
+	ArgoCDKeyCLIServer = "server"
+
 	// ArgoCDKeyConfigManagementPlugins is the configuration key for config management plugins.
 	ArgoCDKeyConfigManagementPlugins = "configManagementPlugins"
 
+	// ArgoCDKeyClusterDiscoveryKubeconfig is the key for the kubeconfig in the Secrets selected for cluster discovery.
+	ArgoCDKeyClusterDiscoveryKubeconfig = "kubeconfig"
+
 	// ArgoCDKeyComponent is the resource component key for labels.
 	ArgoCDKeyComponent = "app.kubernetes.io/component"
 
+	// ArgoCDKeyDiscoveredClusterSource is the annotation with the name of the Secret a discovered cluster Secret was
+	// generated from.
+	ArgoCDKeyDiscoveredClusterSource = "argocds.argoproj.io/cluster-discovery-source"
+
+	// ArgoCDKeyDisasterRecoveryBundle is the key for the sealed bundle in the disaster recovery Secret.
+	ArgoCDKeyDisasterRecoveryBundle = "bundle"
+
 	// ArgoCDKeyDexOAuthRedirectURI is the key for the OAuth Redirect URI annotation.
 	ArgoCDKeyDexOAuthRedirectURI = "serviceaccounts.openshift.io/oauth-redirecturi.argocd"
 
 	// ArgoCDKeyDexConfig is the key for dex configuration.
 	ArgoCDKeyDexConfig = "dex.config"
 
+	// ArgoCDKeyDexExternalClientID is the key for the client ID in the client Secret of an external Dex.
+	ArgoCDKeyDexExternalClientID = "clientID"
+
+	// ArgoCDKeyDexExternalClientSecret is the key for the client secret in the client Secret of an external Dex.
+	ArgoCDKeyDexExternalClientSecret = "clientSecret"
+
+	// ArgoCDKeyDexStaticClientSecret is the key for the client secret of a Dex static client.
+	ArgoCDKeyDexStaticClientSecret = "clientSecret"
+
+	// ArgoCDKeyExtensionConfig is the configuration key for the backends of the proxy extensions.
+	ArgoCDKeyExtensionConfig = "extension.config"
+
 	// ArgoCDKeyFailureDomainZone is the failure-domain zone key for labels.
 	ArgoCDKeyFailureDomainZone = "failure-domain.beta.kubernetes.io/zone"
 
@@ -65,12 +100,28 @@ const (
 	// ArgoCDKeyGrafanaSecretKey is the "secret key" key for labels.
 	ArgoCDKeyGrafanaSecretKey = "secret.key"
 
+	// ArgoCDKeyGrafanaDashboard is the label used by the Grafana sidecar to discover dashboard ConfigMaps.
+	ArgoCDKeyGrafanaDashboard = "grafana_dashboard"
+
+	// ArgoCDKeyGrafanaDatasource is the label used by the Grafana sidecar to discover datasource ConfigMaps.
+	ArgoCDKeyGrafanaDatasource = "grafana_datasource"
+
 	// ArgoCDKeyHelpChatURL is the congifuration key for the help chat URL.
 	ArgoCDKeyHelpChatURL = "help.chatUrl"
 
 	// ArgoCDKeyHelpChatText is the congifuration key for the help chat text.
 	ArgoCDKeyHelpChatText = "help.chatText"
 
+	// ArgoCDKeyImageUpdaterRegistries is the configuration key for the Argo CD Image Updater registries.
+	ArgoCDKeyImageUpdaterRegistries = "registries.conf"
+
+	// ArgoCDKeyExternalDNSHostname is the external-dns annotation key for the hostnames of the DNS records of a
+	// Service, an Ingress or a Route.
+	ArgoCDKeyExternalDNSHostname = "external-dns.alpha.kubernetes.io/hostname"
+
+	// ArgoCDKeyExternalDNSTTL is the external-dns annotation key for the time to live of the DNS records.
+	ArgoCDKeyExternalDNSTTL = "external-dns.alpha.kubernetes.io/ttl"
+
 	// ArgoCDKeyHostname is the resource hostname key for labels.
 	ArgoCDKeyHostname = "kubernetes.io/hostname"
 
@@ -98,6 +149,12 @@ const (
 	// ArgoCDKeyOIDCConfig is the configuration key for the OIDC configuration.
 	ArgoCDKeyOIDCConfig = "oidc.config"
 
+	// ArgoCDKeyOIDCInsecureSkipVerify is the configuration key for skipping the TLS verification of the OIDC provider.
+	ArgoCDKeyOIDCInsecureSkipVerify = "oidc.tls.insecure.skip.verify"
+
+	// ArgoCDKeyOIDCDexClientSecret is the key in the Argo CD Secret for the client secret of an external Dex.
+	ArgoCDKeyOIDCDexClientSecret = "oidc.dex.clientSecret"
+
 	// ArgoCDKeyPartOf is the resource part-of key for labels.
 	ArgoCDKeyPartOf = "app.kubernetes.io/part-of"
 
@@ -125,6 +182,9 @@ const (
 	// ArgoCDKeyResourceCustomizations is the configuration key for resource customizations.
 	ArgoCDKeyResourceCustomizations = "resource.customizations"
 
+	// ArgoCDKeyResourceHealthLua is the resource customization key for Lua health checks.
+	ArgoCDKeyResourceHealthLua = "health.lua"
+
 	// ArgoCDKeyResourceExclusions is the configuration key for resource exclusions.
 	ArgoCDKeyResourceExclusions = "resource.exclusions"
 
@@ -143,6 +203,9 @@ const (
 	// ArgoCDKeyServerURL is the key for server url.
 	ArgoCDKeyServerURL = "url"
 
+	// ArgoCDKeySOPSAgeKeys is the secret key for the age keys used by SOPS.
+	ArgoCDKeySOPSAgeKeys = "keys.txt"
+
 	// ArgoCDKeySSHKnownHosts is the resource ssh_known_hosts key for labels.
 	ArgoCDKeySSHKnownHosts = "ssh_known_hosts"
 
@@ -164,18 +227,164 @@ const (
 	// ArgoCDKeyUsersAnonymousEnabled is the configuration key for anonymous user access.
 	ArgoCDKeyUsersAnonymousEnabled = "users.anonymous.enabled"
 
+	// ArgoCDKeyUsersSessionDuration is the configuration key for the duration of the sessions of the local users.
+	ArgoCDKeyUsersSessionDuration = "users.session.duration"
+
+	// ArgoCDKeyVaultPluginConfig is the configuration key for the argocd-vault-plugin config management plugin.
+	ArgoCDKeyVaultPluginConfig = "plugin.yaml"
+
 	// ArgoCDApplicationSetEnvName is the environment variable used to get the image
 	// for the ApplicationSet controller
 	ArgoCDApplicationSetEnvName = "ARGOCD_APPLICATIONSET_IMAGE"
 
+	// ArgoCDAutoCreateTemplateEnvName is the environment variable used to set the
+	// namespace/name of the default template ConfigMap for ArgoCD instances created in labeled namespaces.
+	ArgoCDAutoCreateTemplateEnvName = "ARGOCD_AUTOCREATE_TEMPLATE"
+
+	// ArgoCDControllerClusterCacheListPageSizeEnvName is the environment variable used to set the
+	// page size used by the application controller when listing resources in a managed cluster.
+	ArgoCDControllerClusterCacheListPageSizeEnvName = "ARGOCD_CLUSTER_CACHE_LIST_PAGE_SIZE"
+
+	// ArgoCDControllerClusterCacheListSemaphoreEnvName is the environment variable used to set the
+	// maximum number of concurrent list requests made by the application controller.
+	ArgoCDControllerClusterCacheListSemaphoreEnvName = "ARGOCD_CLUSTER_CACHE_LIST_SEMAPHORE"
+
+	// ArgoCDControllerClusterCacheResyncDurationEnvName is the environment variable used to set the
+	// interval at which the application controller cluster cache is rebuilt.
+	ArgoCDControllerClusterCacheResyncDurationEnvName = "ARGOCD_CLUSTER_CACHE_RESYNC_DURATION"
+
+	// ArgoCDControllerClusterCacheWatchResyncDurationEnvName is the environment variable used to set the
+	// interval at which the application controller restarts resource watches.
+	ArgoCDControllerClusterCacheWatchResyncDurationEnvName = "ARGOCD_CLUSTER_CACHE_WATCH_RESYNC_DURATION"
+
+	// ArgoCDControllerShardingAlgorithmEnvName is the environment variable used to set the
+	// sharding algorithm for the application controller.
+	ArgoCDControllerShardingAlgorithmEnvName = "ARGOCD_CONTROLLER_SHARDING_ALGORITHM"
+
+	// ArgoCDDefaultControllerOperationProcessorsEnvName is the environment variable used to set the
+	// default number of operation processors for the application controller.
+	ArgoCDDefaultControllerOperationProcessorsEnvName = "ARGOCD_DEFAULT_CONTROLLER_OPERATION_PROCESSORS"
+
+	// ArgoCDDefaultControllerResourcesEnvName is the environment variable used to set the
+	// default resource requirements, as JSON, for the application controller container.
+	ArgoCDDefaultControllerResourcesEnvName = "ARGOCD_DEFAULT_CONTROLLER_RESOURCES"
+
+	// ArgoCDDefaultControllerStatusProcessorsEnvName is the environment variable used to set the
+	// default number of status processors for the application controller.
+	ArgoCDDefaultControllerStatusProcessorsEnvName = "ARGOCD_DEFAULT_CONTROLLER_STATUS_PROCESSORS"
+
+	// ArgoCDDefaultImagesPathEnvName is the environment variable used to set the
+	// directory the default images ConfigMap is mounted at in the operator.
+	ArgoCDDefaultImagesPathEnvName = "ARGOCD_DEFAULT_IMAGES_PATH"
+
+	// ArgoCDDefaultInitContainerResourcesEnvName is the environment variable used to set the
+	// default resource requirements, as JSON, for the init containers of the Argo CD components.
+	ArgoCDDefaultInitContainerResourcesEnvName = "ARGOCD_DEFAULT_INIT_CONTAINER_RESOURCES"
+
+	// ArgoCDDefaultLogLevelEnvName is the environment variable used to set the
+	// default log level for the application controller, repo server and server.
+	ArgoCDDefaultLogLevelEnvName = "ARGOCD_DEFAULT_LOG_LEVEL"
+
+	// ArgoCDDefaultProfileEnvName is the environment variable used to set the
+	// sizing profile for instances that do not select one.
+	ArgoCDDefaultProfileEnvName = "ARGOCD_DEFAULT_PROFILE"
+
+	// ArgoCDDefaultRepoResourcesEnvName is the environment variable used to set the
+	// default resource requirements, as JSON, for the repo server container.
+	ArgoCDDefaultRepoResourcesEnvName = "ARGOCD_DEFAULT_REPO_RESOURCES"
+
+	// ArgoCDDefaultServerResourcesEnvName is the environment variable used to set the
+	// default resource requirements, as JSON, for the server container.
+	ArgoCDDefaultServerResourcesEnvName = "ARGOCD_DEFAULT_SERVER_RESOURCES"
+
 	// ArgoCDDexImageEnvName is the environment variable used to get the image
 	// to used for the Dex container.
 	ArgoCDDexImageEnvName = "ARGOCD_DEX_IMAGE"
 
+	// ArgoCDExportJobImageEnvName is the environment variable used to get the image
+	// to use for the export, import and SSH known hosts scan containers.
+	ArgoCDExportJobImageEnvName = "ARGOCD_EXPORT_JOB_IMAGE"
+
+	// ArgoCDExtensionInstallerImageEnvName is the environment variable used to get the image
+	// to use for the UI extension installer containers.
+	ArgoCDExtensionInstallerImageEnvName = "ARGOCD_EXTENSION_INSTALLER_IMAGE"
+
+	// ArgoCDGitAttemptsCountEnvName is the environment variable used to set the
+	// number of attempts made by the repo server for a failed Git request.
+	ArgoCDGitAttemptsCountEnvName = "ARGOCD_GIT_ATTEMPTS_COUNT"
+
+	// ArgoCDGitRetryDurationEnvName is the environment variable used to set the
+	// initial delay before the repo server retries a failed Git request.
+	ArgoCDGitRetryDurationEnvName = "ARGOCD_GIT_RETRY_DURATION"
+
+	// ArgoCDGitRetryFactorEnvName is the environment variable used to set the
+	// backoff factor applied between retries of a failed Git request.
+	ArgoCDGitRetryFactorEnvName = "ARGOCD_GIT_RETRY_FACTOR"
+
+	// ArgoCDGitRetryMaxDurationEnvName is the environment variable used to set the
+	// maximum delay between retries of a failed Git request.
+	ArgoCDGitRetryMaxDurationEnvName = "ARGOCD_GIT_RETRY_MAX_DURATION"
+
 	// ArgoCDImageEnvName is the environment variable used to get the image
 	// to used for the argocd container.
 	ArgoCDImageEnvName = "ARGOCD_IMAGE"
 
+	// ArgoCDImageUpdaterImageEnvName is the environment variable used to get the image
+	// to used for the Argo CD Image Updater container.
+	ArgoCDImageUpdaterImageEnvName = "ARGOCD_IMAGE_UPDATER_IMAGE"
+
+	// ArgoCDKSOPSImageEnvName is the environment variable used to get the image
+	// to use for the KSOPS init container.
+	ArgoCDKSOPSImageEnvName = "ARGOCD_KSOPS_IMAGE"
+
+	// ArgoCDManagedNamespacesAllowlistEnvName is the environment variable used to restrict the namespaces that can
+	// be managed by the ArgoCD instances in the given namespaces, e.g. "argocd=team-a,team-b-*;gitops=*".
+	ArgoCDManagedNamespacesAllowlistEnvName = "ARGOCD_MANAGED_NAMESPACES_ALLOWLIST"
+
+	// ArgoCDManagedNamespacesDenylistEnvName is the environment variable used to list the namespaces that can not
+	// be managed by any ArgoCD instance, e.g. "kube-*,openshift-*".
+	ArgoCDManagedNamespacesDenylistEnvName = "ARGOCD_MANAGED_NAMESPACES_DENYLIST"
+
+	// ArgoCDMetricsTLSProxyImageEnvName is the environment variable used to get the image
+	// to used for the proxy that serves metrics over TLS.
+	ArgoCDMetricsTLSProxyImageEnvName = "ARGOCD_METRICS_TLS_PROXY_IMAGE"
+
+	// ArgoCDRelatedImageApplicationSetEnvName is the environment variable used to get the image
+	// for the ApplicationSet controller container, taking precedence over all other defaults.
+	ArgoCDRelatedImageApplicationSetEnvName = "RELATED_IMAGE_APPLICATIONSET"
+
+	// ArgoCDRelatedImageArgoCDEnvName is the environment variable used to get the image
+	// for the Argo CD container, taking precedence over all other defaults.
+	ArgoCDRelatedImageArgoCDEnvName = "RELATED_IMAGE_ARGOCD"
+
+	// ArgoCDRelatedImageDexEnvName is the environment variable used to get the image
+	// for the Dex container, taking precedence over all other defaults.
+	ArgoCDRelatedImageDexEnvName = "RELATED_IMAGE_DEX"
+
+	// ArgoCDRelatedImageGrafanaEnvName is the environment variable used to get the image
+	// for the Grafana container, taking precedence over all other defaults.
+	ArgoCDRelatedImageGrafanaEnvName = "RELATED_IMAGE_GRAFANA"
+
+	// ArgoCDRelatedImageImageUpdaterEnvName is the environment variable used to get the image
+	// for the Argo CD Image Updater container, taking precedence over all other defaults.
+	ArgoCDRelatedImageImageUpdaterEnvName = "RELATED_IMAGE_ARGOCD_IMAGE_UPDATER"
+
+	// ArgoCDRelatedImageKeycloakEnvName is the environment variable used to get the image
+	// for the Keycloak container, taking precedence over all other defaults.
+	ArgoCDRelatedImageKeycloakEnvName = "RELATED_IMAGE_KEYCLOAK"
+
+	// ArgoCDRelatedImageRedisEnvName is the environment variable used to get the image
+	// for the Redis container, taking precedence over all other defaults.
+	ArgoCDRelatedImageRedisEnvName = "RELATED_IMAGE_REDIS"
+
+	// ArgoCDRelatedImageRedisHAEnvName is the environment variable used to get the image
+	// for the Redis HA container, taking precedence over all other defaults.
+	ArgoCDRelatedImageRedisHAEnvName = "RELATED_IMAGE_REDIS_HA"
+
+	// ArgoCDRelatedImageRedisHAProxyEnvName is the environment variable used to get the image
+	// for the Redis HA Proxy container, taking precedence over all other defaults.
+	ArgoCDRelatedImageRedisHAProxyEnvName = "RELATED_IMAGE_REDIS_HA_PROXY"
+
 	// ArgoCDRedisHAProxyImageEnvName is the environment variable used to get the image
 	// to used for the Redis HA Proxy container.
 	ArgoCDRedisHAProxyImageEnvName = "ARGOCD_REDIS_HA_PROXY_IMAGE"
@@ -192,15 +401,85 @@ const (
 	// to used for the Grafana container.
 	ArgoCDGrafanaImageEnvName = "ARGOCD_GRAFANA_IMAGE"
 
+	// ArgoCDVaultPluginImageEnvName is the environment variable used to get the image
+	// to use for downloading the argocd-vault-plugin.
+	ArgoCDVaultPluginImageEnvName = "ARGOCD_VAULT_PLUGIN_IMAGE"
+
 	// ArgoCDDeletionFinalizer is a finalizer to implement pre-delete hooks
 	ArgoCDDeletionFinalizer = "argoproj.io/finalizer"
 
+	// ArgoCDDefaultResourceName is the name of the cluster-scoped ArgoCDDefault inherited by every ArgoCD.
+	ArgoCDDefaultResourceName = "cluster"
+
 	// ArgoCDDefaultServer is the default server address
 	ArgoCDDefaultServer = "https://kubernetes.default.svc"
 
+	// ArgoCDCommandOverrideAnnotation marks the pod template of a component whose container command is overridden, to
+	// restore the command and the arguments managed by the operator when the override is removed.
+	ArgoCDCommandOverrideAnnotation = "argocds.argoproj.io/command-override"
+
+	// ArgoCDDisasterRecoveryChecksumAnnotation is the checksum of the unsealed content of a disaster recovery bundle,
+	// used to only reseal the bundle when the mirrored Secrets change.
+	ArgoCDDisasterRecoveryChecksumAnnotation = "argocds.argoproj.io/disaster-recovery-checksum"
+
+	// ArgoCDExportNamespacesAnnotation lists the namespaces of the ArgoCDExports allowed to use a storage Secret
+	// from another namespace.
+	ArgoCDExportNamespacesAnnotation = "argocds.argoproj.io/export-namespaces"
+
+	// ArgoCDKeycloakTemplateChecksumAnnotation is the checksum of the template of the Keycloak TemplateInstance, used
+	// to recreate the TemplateInstance when the Keycloak options change.
+	ArgoCDKeycloakTemplateChecksumAnnotation = "argocds.argoproj.io/keycloak-template-checksum"
+
+	// ArgoCDKnownHostsScanHostsAnnotation lists the hosts scanned by the initial SSH known hosts scan Job, used to
+	// rerun the scan when the hosts change.
+	ArgoCDKnownHostsScanHostsAnnotation = "argocds.argoproj.io/ssh-known-hosts-scan-hosts"
+
+	// ArgoCDManagedAnnotationsAnnotation records the keys of the annotations added from the spec to a Route, an
+	// Ingress or a Service, to remove the annotations that are no longer configured while keeping the ones added by
+	// others.
+	ArgoCDManagedAnnotationsAnnotation = "argocds.argoproj.io/managed-annotations"
+
+	// ArgoCDManagedLabelsAnnotation records the keys of the labels added from the spec to a Route, an Ingress or a
+	// Service.
+	ArgoCDManagedLabelsAnnotation = "argocds.argoproj.io/managed-labels"
+
+	// ArgoCDMetricsLabelsAnnotation records the keys of the labels added to the metrics Services and ServiceMonitors,
+	// to remove the labels that are no longer configured.
+	ArgoCDMetricsLabelsAnnotation = "argocds.argoproj.io/metrics-labels"
+
+	// ArgoCDTLSCertsAnnotation records the keys of the argocd-tls-certs-cm ConfigMap managed by the operator, to remove
+	// the certificates that are no longer configured while keeping the certificates added by other means.
+	ArgoCDTLSCertsAnnotation = "argocds.argoproj.io/tls-certs"
+
+	// ArgoCDTokenPropagatedAnnotation records when the token of a rotated ServiceAccount token Secret was propagated to
+	// its consumers, the previous tokens are revoked once the revocation delay has elapsed after that time.
+	ArgoCDTokenPropagatedAnnotation = "argocds.argoproj.io/token-propagated-at"
+
+	// ArgoCDClusterServiceAccountAnnotation is the "<namespace>/<name>" of the ServiceAccount on the remote cluster that
+	// a kubeconfig Secret selected for cluster discovery authenticates as, whose token is rotated by the operator.
+	ArgoCDClusterServiceAccountAnnotation = "argocds.argoproj.io/cluster-service-account"
+
+	// ArgoCDCredentialsForLabel is used to identify pre-existing credential secrets used by an instance of ArgoCD
+	ArgoCDCredentialsForLabel = "argocds.argoproj.io/credentials-for"
+
 	// ArgoCDSecretTypeLabel is needed for cluster secrets
 	ArgoCDSecretTypeLabel = "argocd.argoproj.io/secret-type"
 
+	// ArgoCDApplicationSetManagedByLabel identifies the ApplicationSet resources in a source namespace and the
+	// namespace of the ArgoCD instance that manages them.
+	ArgoCDApplicationSetManagedByLabel = "argocd.argoproj.io/applicationset-managed-by"
+
+	// ArgoCDCLIConfigManagedByLabel identifies the CLI ConfigMap or Secret of an ArgoCD instance by the namespace of
+	// the instance, as they may be published to another namespace.
+	ArgoCDCLIConfigManagedByLabel = "argocd.argoproj.io/cli-config-managed-by"
+
 	// ArgoCDManagedByLabel is needed to identify namespace managed by an instance on ArgoCD
 	ArgoCDManagedByLabel = "argocd.argoproj.io/managed-by"
+
+	// ArgoCDAutoCreateLabel is the label on a namespace in which an ArgoCD instance is created from a template.
+	ArgoCDAutoCreateLabel = "argocd.argoproj.io/autocreate"
+
+	// ArgoCDAutoCreateTemplateAnnotation is the name of the template ConfigMap on a labeled namespace, overriding the
+	// default template, and on the ArgoCD instances created from it.
+	ArgoCDAutoCreateTemplateAnnotation = "argocd.argoproj.io/autocreate-template"
 )
@@ -23,12 +23,66 @@ const (
 	// ArgoCDCASuffix is the name suffix for ArgoCD CA resources.
 	ArgoCDCASuffix = "ca"
 
+	// ArgoCDCLIConfigKindConfigMap is the CLI config kind publishing the connection details in a ConfigMap.
+	ArgoCDCLIConfigKindConfigMap = "ConfigMap"
+
+	// ArgoCDCLIConfigKindSecret is the CLI config kind publishing the connection details in a Secret.
+	ArgoCDCLIConfigKindSecret = "Secret"
+
+	// ArgoCDCLIConfigSuffix is the name suffix for the ConfigMap or the Secret holding the connection details for the
+	// argocd CLI.
+	ArgoCDCLIConfigSuffix = "cli-config"
+
+	// ArgoCDComplianceModeRestricted is the compliance mode that verifies pods against the restricted Pod Security
+	// Standard.
+	ArgoCDComplianceModeRestricted = "restricted"
+
 	// ArgoCDConfigMapName is the upstream hard-coded ArgoCD ConfigMap name.
 	ArgoCDConfigMapName = "argocd-cm"
 
 	// ArgoCDGPGKeysConfigMapName is the upstream hard-coded ArgoCD gpg-keys ConfigMap name.
 	ArgoCDGPGKeysConfigMapName = "argocd-gpg-keys-cm"
 
+	// ArgoCDDefaultAutoTLSRenewBefore is the default time before expiry at which a generated certificate is renewed.
+	ArgoCDDefaultAutoTLSRenewBefore = time.Hour * 24 * 30
+
+	// ArgoCDDefaultRedisHAProxyCheckInterval is the default interval between two health checks of a Redis HA server by
+	// the Redis HAProxy.
+	ArgoCDDefaultRedisHAProxyCheckInterval = time.Second * 3
+
+	// ArgoCDDefaultRedisHAProxyTimeoutCheck is the default time the Redis HAProxy allows for a health check.
+	ArgoCDDefaultRedisHAProxyTimeoutCheck = time.Second * 2
+
+	// ArgoCDDefaultRedisHAProxyTimeoutClient is the default time a client connection to the Redis HAProxy may stay
+	// idle.
+	ArgoCDDefaultRedisHAProxyTimeoutClient = time.Minute * 6
+
+	// ArgoCDDefaultRedisHAProxyTimeoutConnect is the default time the Redis HAProxy allows to connect to a Redis HA
+	// server.
+	ArgoCDDefaultRedisHAProxyTimeoutConnect = time.Second * 4
+
+	// ArgoCDDefaultRedisHAProxyTimeoutServer is the default time a connection from the Redis HAProxy to a Redis HA
+	// server may stay idle.
+	ArgoCDDefaultRedisHAProxyTimeoutServer = time.Minute * 6
+
+	// ArgoCDDeletionPolicyCascade is the deletion policy that deletes the Applications and AppProjects of an ArgoCD,
+	// letting the Application Controller process their finalizers, before the ArgoCD is removed.
+	ArgoCDDeletionPolicyCascade = "Cascade"
+
+	// ArgoCDDeletionPolicyOrphan is the deletion policy that leaves the Applications and AppProjects of an ArgoCD in
+	// place when the ArgoCD is removed.
+	ArgoCDDeletionPolicyOrphan = "Orphan"
+
+	// ArgoCDDeletionPolicyStripFinalizers is the deletion policy that removes the finalizers from the Applications and
+	// AppProjects of an ArgoCD when the ArgoCD is removed, without deleting the resources they deployed.
+	ArgoCDDeletionPolicyStripFinalizers = "StripFinalizers"
+
+	// ArgoCDDisasterRecoveryModeMirror is the disaster recovery mode that keeps the sealed bundle up to date.
+	ArgoCDDisasterRecoveryModeMirror = "mirror"
+
+	// ArgoCDDisasterRecoveryModeRestore is the disaster recovery mode that restores the Secrets from the sealed bundle.
+	ArgoCDDisasterRecoveryModeRestore = "restore"
+
 	// ArgoCDDuration365Days is a duration representing 365 days.
 	ArgoCDDuration365Days = time.Hour * 24 * 365
 
@@ -53,6 +107,26 @@ const (
 	// ArgoCDGrafanaDashboardConfigMapSuffix is the default suffix for the Grafana dashboards ConfigMap.
 	ArgoCDGrafanaDashboardConfigMapSuffix = "grafana-dashboards"
 
+	// ArgoCDGrafanaDashboardUID is the UID of the Argo CD Grafana dashboard.
+	ArgoCDGrafanaDashboardUID = "BjWwX3jik"
+
+	// ArgoCDGrafanaDatasourceConfigMapSuffix is the default suffix for the Grafana datasource ConfigMap provisioned
+	// for an external Grafana.
+	ArgoCDGrafanaDatasourceConfigMapSuffix = "grafana-datasource"
+
+	// ArgoCDImageUpdaterConfigMapName is the upstream hard-coded Argo CD Image Updater ConfigMap name.
+	ArgoCDImageUpdaterConfigMapName = "argocd-image-updater-config"
+
+	// ArgoCDIPFamilyPolicyPreferDualStack is the IP family policy that assigns both IP families to a Service when the
+	// cluster supports dual-stack.
+	ArgoCDIPFamilyPolicyPreferDualStack = "PreferDualStack"
+
+	// ArgoCDIPFamilyPolicyRequireDualStack is the IP family policy that requires both IP families for a Service.
+	ArgoCDIPFamilyPolicyRequireDualStack = "RequireDualStack"
+
+	// ArgoCDIPFamilyPolicySingleStack is the IP family policy that assigns a single IP family to a Service.
+	ArgoCDIPFamilyPolicySingleStack = "SingleStack"
+
 	// ArgoCDKnownHostsConfigMapName is the upstream hard-coded SSH known hosts data ConfigMap name.
 	ArgoCDKnownHostsConfigMapName = "argocd-ssh-known-hosts-cm"
 
@@ -68,12 +142,48 @@ const (
 	// ArgoCDSecretName is the upstream hard-coded ArgoCD Secret name.
 	ArgoCDSecretName = "argocd-secret"
 
+	// ArgoCDSecretTypeCluster is the secret type label value for cluster credentials.
+	ArgoCDSecretTypeCluster = "cluster"
+
+	// ArgoCDSecretTypeRepoCreds is the secret type label value for repository credential templates.
+	ArgoCDSecretTypeRepoCreds = "repo-creds"
+
+	// ArgoCDSecretTypeRepository is the secret type label value for repository credentials.
+	ArgoCDSecretTypeRepository = "repository"
+
 	// ArgoCDStatusCompleted is the completed status value.
 	ArgoCDStatusCompleted = "Completed"
 
+	// ArgoCDStatusFailed is the failed status value.
+	ArgoCDStatusFailed = "Failed"
+
 	// ArgoCDTLSCertsConfigMapName is the upstream hard-coded TLS certificate data ConfigMap name.
 	ArgoCDTLSCertsConfigMapName = "argocd-tls-certs-cm"
 
 	// ArgoCDRepoServerTLSSecretName is the name of the TLS secret for the repo-server
 	ArgoCDRepoServerTLSSecretName = "argocd-repo-server-tls"
+
+	// ArgoCDProfileLarge is the sizing profile for instances managing thousands of Applications.
+	ArgoCDProfileLarge = "large"
+
+	// ArgoCDProfileMedium is the sizing profile for instances managing hundreds of Applications.
+	ArgoCDProfileMedium = "medium"
+
+	// ArgoCDProfileSmall is the sizing profile for instances managing tens of Applications.
+	ArgoCDProfileSmall = "small"
+
+	// ArgoCDRepoServerAutoTLSOpenShift is the AutoTLS provider that uses the OpenShift service CA.
+	ArgoCDRepoServerAutoTLSOpenShift = "openshift"
+
+	// ArgoCDRepoServerAutoTLSOperator is the AutoTLS provider that uses a certificate generated by the operator.
+	ArgoCDRepoServerAutoTLSOperator = "operator"
+
+	// ArgoCDUpgradeStrategyAll is the upgrade strategy that upgrades all components at the same time.
+	ArgoCDUpgradeStrategyAll = "All"
+
+	// ArgoCDUpgradeStrategyStaged is the upgrade strategy that upgrades the components one at a time.
+	ArgoCDUpgradeStrategyStaged = "Staged"
+
+	// ArgoCDVaultPluginConfigMapName is the name of the ConfigMap holding the argocd-vault-plugin configuration.
+	ArgoCDVaultPluginConfigMapName = "argocd-vault-plugin-cmp"
 )
@@ -0,0 +1,191 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocd
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/pkg/controller/argoutil"
+)
+
+const (
+	// applicationHealthHealthy is the health status of an Application whose resources are all healthy.
+	applicationHealthHealthy = "Healthy"
+
+	// applicationSyncSynced is the sync status of an Application whose resources match the desired state.
+	applicationSyncSynced = "Synced"
+
+	// applicationStatusUnknown is used for an Application that does not report a health or sync status yet.
+	applicationStatusUnknown = "Unknown"
+)
+
+var applicationAPIFound = false
+
+// IsApplicationAPIAvailable returns true if the Application API is present.
+func IsApplicationAPIAvailable() bool {
+	return applicationAPIFound
+}
+
+// verifyApplicationAPI will verify that the Application API is present.
+func verifyApplicationAPI() error {
+	found, err := argoutil.VerifyAPIKind(applicationGVK)
+	if err != nil {
+		return err
+	}
+	applicationAPIFound = found
+	return nil
+}
+
+// isApplicationHealthEnabled will return true if the health and sync status of the Applications of the given ArgoCD
+// are summarized in its status.
+func isApplicationHealthEnabled(cr *argoprojv1a1.ArgoCD) bool {
+	return cr.Spec.ApplicationHealth.Enabled
+}
+
+// reconcileStatusApplications will ensure that the ApplicationCount and AppProjectCount Status are updated with the
+// number of Applications and AppProjects in the namespace of the given ArgoCD. When enabled, the Applications Status
+// and the ApplicationsHealthy condition also summarize the health and sync status of the Applications, and are removed
+// otherwise. The Applications are read once from the cache for both.
+func (r *ReconcileArgoCD) reconcileStatusApplications(cr *argoprojv1a1.ArgoCD) error {
+	apps, err := r.listCachedArgoObjects(cr, applicationGVK)
+	if err != nil {
+		return err
+	}
+	projects, err := r.listCachedArgoObjects(cr, appProjectGVK)
+	if err != nil {
+		return err
+	}
+
+	changed := false
+	if cr.Status.ApplicationCount != int32(len(apps)) || cr.Status.AppProjectCount != int32(len(projects)) {
+		cr.Status.ApplicationCount = int32(len(apps))
+		cr.Status.AppProjectCount = int32(len(projects))
+		changed = true
+	}
+
+	var status *argoprojv1a1.ArgoCDApplicationsStatus
+	if isApplicationHealthEnabled(cr) {
+		status = &argoprojv1a1.ArgoCDApplicationsStatus{
+			Health: map[string]int32{},
+			Sync:   map[string]int32{},
+		}
+		for _, app := range apps {
+			health, sync := getApplicationStatus(app)
+			status.Health[health]++
+			status.Sync[sync]++
+		}
+		if setArgoCDCondition(cr, getApplicationsHealthyCondition(status, int32(len(apps)))) {
+			changed = true
+		}
+	} else if removeArgoCDCondition(cr, argoprojv1a1.ArgoCDConditionTypeApplicationsHealthy) {
+		changed = true
+	}
+
+	if !reflect.DeepEqual(cr.Status.Applications, status) {
+		cr.Status.Applications = status
+		changed = true
+	}
+
+	if changed {
+		return r.updateArgoCDStatus(cr)
+	}
+	return nil
+}
+
+// getApplicationStatus will return the health and sync status reported by the given Application.
+func getApplicationStatus(app unstructured.Unstructured) (string, string) {
+	health, _, _ := unstructured.NestedString(app.Object, "status", "health", "status")
+	if health == "" {
+		health = applicationStatusUnknown
+	}
+	sync, _, _ := unstructured.NestedString(app.Object, "status", "sync", "status")
+	if sync == "" {
+		sync = applicationStatusUnknown
+	}
+	return health, sync
+}
+
+// getApplicationsHealthyCondition will return the ApplicationsHealthy condition for the given summary of the given
+// number of Applications. The condition is true when every Application is healthy and in sync.
+func getApplicationsHealthyCondition(status *argoprojv1a1.ArgoCDApplicationsStatus, applications int32) argoprojv1a1.ArgoCDCondition {
+	groups := make([]string, 0)
+	for _, summary := range []struct {
+		name   string
+		counts map[string]int32
+		ok     string
+	}{
+		{"health", status.Health, applicationHealthHealthy},
+		{"sync", status.Sync, applicationSyncSynced},
+	} {
+		keys := make([]string, 0, len(summary.counts))
+		for key := range summary.counts {
+			if key != summary.ok {
+				keys = append(keys, key)
+			}
+		}
+		if len(keys) == 0 {
+			continue
+		}
+		sort.Strings(keys)
+		counts := make([]string, 0, len(keys))
+		for _, key := range keys {
+			counts = append(counts, fmt.Sprintf("%d %s", summary.counts[key], key))
+		}
+		groups = append(groups, fmt.Sprintf("%s: %s", summary.name, strings.Join(counts, ", ")))
+	}
+
+	if len(groups) == 0 {
+		return argoprojv1a1.ArgoCDCondition{
+			Type:   argoprojv1a1.ArgoCDConditionTypeApplicationsHealthy,
+			Status: corev1.ConditionTrue,
+			Reason: "ApplicationsHealthy",
+		}
+	}
+	return argoprojv1a1.ArgoCDCondition{
+		Type:    argoprojv1a1.ArgoCDConditionTypeApplicationsHealthy,
+		Status:  corev1.ConditionFalse,
+		Reason:  "ApplicationsUnhealthy",
+		Message: fmt.Sprintf("%d Applications, %s", applications, strings.Join(groups, "; ")),
+	}
+}
+
+// applicationStatusPredicate filters the Application events down to the ones that change the summary of the
+// Applications, as the status of an Application is updated on every refresh.
+func applicationStatusPredicate() predicate.Predicate {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldApp, ok := e.ObjectOld.(*unstructured.Unstructured)
+			if !ok {
+				return false
+			}
+			newApp, ok := e.ObjectNew.(*unstructured.Unstructured)
+			if !ok {
+				return false
+			}
+			oldHealth, oldSync := getApplicationStatus(*oldApp)
+			newHealth, newSync := getApplicationStatus(*newApp)
+			return oldHealth != newHealth || oldSync != newSync
+		},
+	}
+}
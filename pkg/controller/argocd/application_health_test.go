@@ -0,0 +1,92 @@
+package argocd
+
+import (
+	"context"
+	"testing"
+
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+
+	argoprojv1alpha1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+)
+
+func makeTestApplication(name, health, sync string) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{}
+	u.SetGroupVersionKind(applicationGVK)
+	u.SetNamespace(testNamespace)
+	u.SetName(name)
+	status := map[string]interface{}{}
+	if health != "" {
+		status["health"] = map[string]interface{}{"status": health}
+	}
+	if sync != "" {
+		status["sync"] = map[string]interface{}{"status": sync}
+	}
+	u.Object["status"] = status
+	return u
+}
+
+func TestReconcileArgoCD_reconcileStatusApplications(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.ApplicationHealth.Enabled = true
+	})
+	r := makeTestReconciler(t, a)
+
+	assert.NilError(t, r.reconcileStatusApplications(a))
+	assert.Assert(t, isArgoCDConditionTrue(a, argoprojv1alpha1.ArgoCDConditionTypeApplicationsHealthy))
+
+	for _, app := range []*unstructured.Unstructured{
+		makeTestApplication("guestbook", "Healthy", "Synced"),
+		makeTestApplication("helm-guestbook", "Degraded", "OutOfSync"),
+		makeTestApplication("kustomize-guestbook", "Healthy", "OutOfSync"),
+		makeTestApplication("new-guestbook", "", ""),
+	} {
+		assert.NilError(t, r.client.Create(context.TODO(), app))
+	}
+
+	assert.NilError(t, r.reconcileStatusApplications(a))
+	assert.DeepEqual(t, a.Status.Applications.Health, map[string]int32{"Healthy": 2, "Degraded": 1, "Unknown": 1})
+	assert.DeepEqual(t, a.Status.Applications.Sync, map[string]int32{"Synced": 1, "OutOfSync": 2, "Unknown": 1})
+	assert.Equal(t, len(a.Status.Conditions), 1)
+	c := a.Status.Conditions[0]
+	assert.Equal(t, c.Status, corev1.ConditionFalse)
+	assert.Equal(t, c.Reason, "ApplicationsUnhealthy")
+	assert.Equal(t, c.Message, "4 Applications, health: 1 Degraded, 1 Unknown; sync: 2 OutOfSync, 1 Unknown")
+
+	// The summary is removed once disabled.
+	a.Spec.ApplicationHealth.Enabled = false
+	assert.NilError(t, r.reconcileStatusApplications(a))
+	assert.Assert(t, a.Status.Applications == nil)
+	assert.Equal(t, len(a.Status.Conditions), 0)
+}
+
+func TestReconcileArgoCD_applicationMapper(t *testing.T) {
+	a := makeTestArgoCD()
+	r := makeTestReconciler(t, a)
+	app := makeTestApplication("guestbook", "Healthy", "Synced")
+
+	assert.Equal(t, len(r.applicationMapper(handler.MapObject{Meta: app, Object: app})), 0)
+
+	a.Spec.ApplicationHealth.Enabled = true
+	assert.NilError(t, r.client.Update(context.TODO(), a))
+	reqs := r.applicationMapper(handler.MapObject{Meta: app, Object: app})
+	assert.Equal(t, len(reqs), 1)
+	assert.Equal(t, reqs[0].Name, a.Name)
+}
+
+func TestApplicationStatusPredicate(t *testing.T) {
+	p := applicationStatusPredicate()
+	oldApp := makeTestApplication("guestbook", "Healthy", "Synced")
+
+	newApp := makeTestApplication("guestbook", "Healthy", "Synced")
+	newApp.Object["status"].(map[string]interface{})["reconciledAt"] = "2021-06-01T10:00:00Z"
+	assert.Assert(t, !p.Update(event.UpdateEvent{MetaOld: oldApp, ObjectOld: oldApp, MetaNew: newApp, ObjectNew: newApp}))
+
+	newApp = makeTestApplication("guestbook", "Progressing", "Synced")
+	assert.Assert(t, p.Update(event.UpdateEvent{MetaOld: oldApp, ObjectOld: oldApp, MetaNew: newApp, ObjectNew: newApp}))
+}
@@ -17,8 +17,8 @@ package argocd
 import (
 	"context"
 	"fmt"
-	"os"
 	"reflect"
+	"strings"
 
 	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
 	"github.com/argoproj-labs/argocd-operator/pkg/common"
@@ -28,6 +28,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
@@ -50,6 +51,11 @@ func (r *ReconcileArgoCD) reconcileApplicationSetController(cr *argoprojv1a1.Arg
 		return err
 	}
 
+	log.Info("reconciling applicationset source namespaces")
+	if err := r.reconcileApplicationSetSourceNamespaces(cr, sa); err != nil {
+		return err
+	}
+
 	log.Info("reconciling applicationset deployments")
 	if err := r.reconcileApplicationSetDeployment(cr, sa); err != nil {
 		return err
@@ -117,15 +123,8 @@ func (r *ReconcileArgoCD) reconcileApplicationSetDeployment(cr *argoprojv1a1.Arg
 	}
 
 	podSpec.Containers = []corev1.Container{{
-		Command: []string{"applicationset-controller", "--argocd-repo-server", getRepoServerAddress(cr)},
-		Env: []corev1.EnvVar{{
-			Name: "NAMESPACE",
-			ValueFrom: &corev1.EnvVarSource{
-				FieldRef: &corev1.ObjectFieldSelector{
-					FieldPath: "metadata.namespace",
-				},
-			},
-		}},
+		Command:         getApplicationSetCommand(cr),
+		Env:             getApplicationSetEnv(cr),
 		Image:           getApplicationSetContainerImage(cr),
 		ImagePullPolicy: corev1.PullAlways,
 		Name:            "argocd-applicationset-controller",
@@ -153,13 +152,29 @@ func (r *ReconcileArgoCD) reconcileApplicationSetDeployment(cr *argoprojv1a1.Arg
 			},
 		},
 	}}
+	if err := applyReconcilerHook(cr, deploy, ""); err != nil {
+		return err
+	}
+	if cr.Spec.ApplicationSet != nil {
+		setContainerCommand(&deploy.Spec.Template, cr.Spec.ApplicationSet.CommandOverride)
+	}
+
+	if err := r.reconcilePodSecurityCompliance(cr, "Deployment/"+deploy.Name, &deploy.Spec.Template); err != nil {
+		return err
+	}
 
 	if existing := newDeploymentWithSuffix("applicationset-controller", "controller", cr); argoutil.IsObjectFound(r.client, cr.Namespace, existing.Name, existing) {
 
-		// If the Deployment already exists, make sure the containers are up-to-date
-		actualContainers := existing.Spec.Template.Spec.Containers[0]
-		if !reflect.DeepEqual(actualContainers, podSpec.Containers) {
+		// If the Deployment already exists, make sure the containers and the service account are up-to-date
+		changed := false
+		if !reflect.DeepEqual(existing.Spec.Template.Spec.Containers, podSpec.Containers) {
 			existing.Spec.Template.Spec.Containers = podSpec.Containers
+			changed = true
+		}
+		if updatePodServiceAccount(&existing.Spec.Template.Spec, podSpec) {
+			changed = true
+		}
+		if changed {
 			return r.client.Update(context.TODO(), existing)
 		}
 		return nil // Deployment found with nothing to do, move along...
@@ -173,6 +188,9 @@ func (r *ReconcileArgoCD) reconcileApplicationSetDeployment(cr *argoprojv1a1.Arg
 }
 
 func (r *ReconcileArgoCD) reconcileApplicationSetServiceAccount(cr *argoprojv1a1.ArgoCD) (*corev1.ServiceAccount, error) {
+	if cr.Spec.ApplicationSet != nil && cr.Spec.ApplicationSet.ServiceAccountName != "" {
+		return newExternalServiceAccount(cr.Spec.ApplicationSet.ServiceAccountName, cr), nil // ServiceAccount managed outside of the operator
+	}
 
 	sa := newServiceAccountWithName("applicationset-controller", cr)
 	setAppSetLabels(&sa.ObjectMeta)
@@ -202,85 +220,7 @@ func (r *ReconcileArgoCD) reconcileApplicationSetServiceAccount(cr *argoprojv1a1
 }
 
 func (r *ReconcileArgoCD) reconcileApplicationSetRole(cr *argoprojv1a1.ArgoCD) (*v1.Role, error) {
-
-	policyRules := []v1.PolicyRule{
-
-		// ApplicationSet
-		{
-			APIGroups: []string{"argoproj.io"},
-			Resources: []string{
-				"applications",
-				"applicationsets",
-				"appprojects",
-				"applicationsets/finalizers",
-			},
-			Verbs: []string{
-				"create",
-				"delete",
-				"get",
-				"list",
-				"patch",
-				"update",
-				"watch",
-			},
-		},
-		// ApplicationSet Status
-		{
-			APIGroups: []string{"argoproj.io"},
-			Resources: []string{
-				"applicationsets/status",
-			},
-			Verbs: []string{
-				"get",
-				"patch",
-				"update",
-			},
-		},
-
-		// Events
-		{
-			APIGroups: []string{""},
-			Resources: []string{
-				"events",
-			},
-			Verbs: []string{
-				"create",
-				"delete",
-				"get",
-				"list",
-				"patch",
-				"update",
-				"watch",
-			},
-		},
-
-		// Read Secrets/ConfigMaps
-		{
-			APIGroups: []string{""},
-			Resources: []string{
-				"secrets",
-				"configmaps",
-			},
-			Verbs: []string{
-				"get",
-				"list",
-				"watch",
-			},
-		},
-
-		// Read Deployments
-		{
-			APIGroups: []string{"apps", "extensions"},
-			Resources: []string{
-				"deployments",
-			},
-			Verbs: []string{
-				"get",
-				"list",
-				"watch",
-			},
-		},
-	}
+	policyRules := getApplicationSetRoleRules(cr)
 
 	role := newRole("applicationset-controller", policyRules, cr)
 	setAppSetLabels(&role.ObjectMeta)
@@ -342,6 +282,196 @@ func (r *ReconcileArgoCD) reconcileApplicationSetRoleBinding(cr *argoprojv1a1.Ar
 	return r.client.Create(context.TODO(), roleBinding)
 }
 
+// applicationSetNamespacesMinimumVersion is the first version of the ApplicationSet controller, shipped with Argo CD,
+// that reconciles ApplicationSets in namespaces other than its own.
+const applicationSetNamespacesMinimumVersion = "v2.8.0"
+
+// reconcileApplicationSetSourceNamespaces will ensure the Roles and RoleBindings needed by the ApplicationSet
+// controller are present in each of the source namespaces, and removed from namespaces that are no longer listed.
+func (r *ReconcileArgoCD) reconcileApplicationSetSourceNamespaces(cr *argoprojv1a1.ArgoCD, sa *corev1.ServiceAccount) error {
+	namespaces := getApplicationSetSourceNamespaces(cr)
+	if !isApplicationSetNamespacesSupported(cr) {
+		namespaces = nil
+	}
+	for _, namespace := range namespaces {
+		if !argoutil.IsObjectFound(r.client, "", namespace, &corev1.Namespace{}) {
+			log.Info(fmt.Sprintf("skipping ApplicationSet source namespace %s, namespace not found", namespace))
+			continue
+		}
+		if err := r.reconcileApplicationSetSourceNamespaceRBAC(cr, namespace, sa); err != nil {
+			return err
+		}
+	}
+	return r.deleteApplicationSetSourceNamespaceResources(cr, namespaces)
+}
+
+// reconcileApplicationSetSourceNamespaceRBAC will ensure the Role and RoleBinding for the ApplicationSet controller
+// are present in the given source namespace.
+func (r *ReconcileArgoCD) reconcileApplicationSetSourceNamespaceRBAC(cr *argoprojv1a1.ArgoCD, namespace string, sa *corev1.ServiceAccount) error {
+	name := GenerateUniqueResourceName("applicationset-controller", cr)
+
+	role := newRole("applicationset-controller", getApplicationSetRoleRules(cr), cr)
+	role.Name = name
+	role.Namespace = namespace
+	setAppSetLabels(&role.ObjectMeta)
+	role.Labels[common.ArgoCDApplicationSetManagedByLabel] = cr.Namespace
+
+	existingRole := &v1.Role{}
+	if err := argoutil.FetchObject(r.client, namespace, name, existingRole); err != nil {
+		if !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to get the ApplicationSet role in namespace %s : %w", namespace, err)
+		}
+		if err := r.client.Create(context.TODO(), role); err != nil {
+			return err
+		}
+	} else if !reflect.DeepEqual(existingRole.Rules, role.Rules) {
+		existingRole.Rules = role.Rules
+		if err := r.client.Update(context.TODO(), existingRole); err != nil {
+			return err
+		}
+	}
+
+	roleBinding := newRoleBinding(cr)
+	roleBinding.Name = name
+	roleBinding.Namespace = namespace
+	setAppSetLabels(&roleBinding.ObjectMeta)
+	roleBinding.Labels[common.ArgoCDApplicationSetManagedByLabel] = cr.Namespace
+	roleBinding.RoleRef = v1.RoleRef{
+		APIGroup: v1.GroupName,
+		Kind:     "Role",
+		Name:     name,
+	}
+	roleBinding.Subjects = []v1.Subject{
+		{
+			Kind:      v1.ServiceAccountKind,
+			Name:      sa.Name,
+			Namespace: sa.Namespace,
+		},
+	}
+
+	existingRoleBinding := &v1.RoleBinding{}
+	if err := argoutil.FetchObject(r.client, namespace, name, existingRoleBinding); err != nil {
+		if !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to get the ApplicationSet rolebinding in namespace %s : %w", namespace, err)
+		}
+		return r.client.Create(context.TODO(), roleBinding)
+	}
+
+	if !reflect.DeepEqual(existingRoleBinding.Subjects, roleBinding.Subjects) {
+		existingRoleBinding.Subjects = roleBinding.Subjects
+		return r.client.Update(context.TODO(), existingRoleBinding)
+	}
+	return nil
+}
+
+// deleteApplicationSetSourceNamespaceResources will remove the ApplicationSet Roles and RoleBindings of the given
+// ArgoCD from all source namespaces that are not in the given list. The label only identifies the namespace of the
+// ArgoCD, so the resources of other ArgoCD instances in the same namespace are told apart by their name.
+func (r *ReconcileArgoCD) deleteApplicationSetSourceNamespaceResources(cr *argoprojv1a1.ArgoCD, namespaces []string) error {
+	name := GenerateUniqueResourceName("applicationset-controller", cr)
+	selector := client.MatchingLabels{
+		common.ArgoCDApplicationSetManagedByLabel: cr.Namespace,
+	}
+
+	roleBindings := &v1.RoleBindingList{}
+	if err := r.client.List(context.TODO(), roleBindings, selector); err != nil {
+		return fmt.Errorf("failed to list ApplicationSet RoleBindings for %s: %w", cr.Name, err)
+	}
+	for i := range roleBindings.Items {
+		roleBinding := &roleBindings.Items[i]
+		if roleBinding.Name != name || containsString(namespaces, roleBinding.Namespace) {
+			continue
+		}
+		if err := r.client.Delete(context.TODO(), roleBinding); err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete ApplicationSet RoleBinding in namespace %s: %w", roleBinding.Namespace, err)
+		}
+	}
+
+	roles := &v1.RoleList{}
+	if err := r.client.List(context.TODO(), roles, selector); err != nil {
+		return fmt.Errorf("failed to list ApplicationSet Roles for %s: %w", cr.Name, err)
+	}
+	for i := range roles.Items {
+		role := &roles.Items[i]
+		if role.Name != name || containsString(namespaces, role.Namespace) {
+			continue
+		}
+		if err := r.client.Delete(context.TODO(), role); err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete ApplicationSet Role in namespace %s: %w", role.Namespace, err)
+		}
+	}
+	return nil
+}
+
+// getApplicationSetCommand will return the command for the ApplicationSet controller.
+func getApplicationSetCommand(cr *argoprojv1a1.ArgoCD) []string {
+	cmd := []string{"applicationset-controller", "--argocd-repo-server", getRepoServerAddress(cr)}
+	if namespaces := getApplicationSetSourceNamespaces(cr); len(namespaces) > 0 && isApplicationSetNamespacesSupported(cr) {
+		cmd = append(cmd, "--applicationset-namespaces", strings.Join(namespaces, ","))
+	}
+	if cr.Spec.ApplicationSet != nil && cr.Spec.ApplicationSet.EnableProgressiveSyncs {
+		cmd = append(cmd, "--enable-progressive-syncs")
+	}
+	if level := getApplicationSetLogLevel(cr); level != "" {
+		cmd = append(cmd, "--loglevel", level)
+	}
+	if cr.Spec.ApplicationSet != nil {
+		cmd = append(cmd, cr.Spec.ApplicationSet.ExtraCommandArgs...)
+	}
+	return cmd
+}
+
+// getApplicationSetLogLevel will return the log level of the ApplicationSet controller for the given ArgoCD, falling
+// back to the default log level of the operator.
+func getApplicationSetLogLevel(cr *argoprojv1a1.ArgoCD) string {
+	if cr.Spec.ApplicationSet != nil && cr.Spec.ApplicationSet.LogLevel != "" {
+		return cr.Spec.ApplicationSet.LogLevel
+	}
+	return getDefaultLogLevel()
+}
+
+// getApplicationSetEnv will return the environment variables of the ApplicationSet controller for the given ArgoCD.
+func getApplicationSetEnv(cr *argoprojv1a1.ArgoCD) []corev1.EnvVar {
+	env := []corev1.EnvVar{{
+		Name: "NAMESPACE",
+		ValueFrom: &corev1.EnvVarSource{
+			FieldRef: &corev1.ObjectFieldSelector{
+				FieldPath: "metadata.namespace",
+			},
+		},
+	}}
+	if cr.Spec.ApplicationSet != nil {
+		env = append(env, cr.Spec.ApplicationSet.Env...)
+	}
+	return env
+}
+
+// getApplicationSetSourceNamespaces will return the namespaces, other than the namespace of the given ArgoCD,
+// in which ApplicationSet resources are reconciled. The namespaces the ArgoCD is not allowed to manage are left out,
+// as the ApplicationSet controller is given access to the secrets of its source namespaces.
+func getApplicationSetSourceNamespaces(cr *argoprojv1a1.ArgoCD) []string {
+	namespaces := make([]string, 0)
+	if cr.Spec.ApplicationSet == nil {
+		return namespaces
+	}
+	for _, namespace := range cr.Spec.ApplicationSet.SourceNamespaces {
+		if namespace == "" || namespace == cr.Namespace || containsString(namespaces, namespace) {
+			continue
+		}
+		if !isManagedNamespaceAllowed(cr.Namespace, namespace) {
+			continue
+		}
+		namespaces = append(namespaces, namespace)
+	}
+	return namespaces
+}
+
+// isApplicationSetNamespacesSupported will return true when the ApplicationSet controller of the given ArgoCD can
+// reconcile ApplicationSets in namespaces other than its own.
+func isApplicationSetNamespacesSupported(cr *argoprojv1a1.ArgoCD) bool {
+	return argoutil.IsArgoCDVersionAtLeast(getApplicationSetContainerImage(cr), applicationSetNamespacesMinimumVersion)
+}
+
 func getApplicationSetContainerImage(cr *argoprojv1a1.ArgoCD) string {
 	defaultImg, defaultTag := false, false
 
@@ -356,7 +486,7 @@ func getApplicationSetContainerImage(cr *argoprojv1a1.ArgoCD) string {
 
 	// If spec is empty, use the defaults
 	if img == "" {
-		img = common.ArgoCDDefaultApplicationSetImage
+		img = argoutil.ReplaceImageRegistry(common.ArgoCDDefaultApplicationSetImage, cr.Spec.ImageRegistry)
 		defaultImg = true
 	}
 	if tag == "" {
@@ -365,7 +495,7 @@ func getApplicationSetContainerImage(cr *argoprojv1a1.ArgoCD) string {
 	}
 
 	// If an env var is specified then use that, but don't override the spec values (if they are present)
-	if e := os.Getenv(common.ArgoCDApplicationSetEnvName); e != "" && (defaultTag && defaultImg) {
+	if e := argoutil.GetDefaultImage(common.ArgoCDRelatedImageApplicationSetEnvName, common.ArgoCDApplicationSetEnvName); e != "" && (defaultTag && defaultImg) {
 		return e
 	}
 	return argoutil.CombineImageTag(img, tag)
@@ -375,6 +505,11 @@ func getApplicationSetContainerImage(cr *argoprojv1a1.ArgoCD) string {
 func getApplicationSetResources(cr *argoprojv1a1.ArgoCD) corev1.ResourceRequirements {
 	resources := corev1.ResourceRequirements{}
 
+	// Allow override of the default resource requirements from the sizing profile
+	if profile := getSizingProfile(cr); profile != nil {
+		resources = *profile.applicationSetResources.DeepCopy()
+	}
+
 	// Allow override of resource requirements from CR
 	if cr.Spec.ApplicationSet.Resources != nil {
 		resources = *cr.Spec.ApplicationSet.Resources
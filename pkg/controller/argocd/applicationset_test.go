@@ -78,6 +78,34 @@ func TestReconcileApplicationSet_Deployments(t *testing.T) {
 	}
 }
 
+func TestReconcileApplicationSet_Deployments_serviceAccount(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD()
+	a.Spec.ApplicationSet = &v1alpha1.ArgoCDApplicationSet{}
+	r := makeTestReconciler(t, a)
+
+	sa := &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "argocd-applicationset-controller"}}
+	assert.NilError(t, r.reconcileApplicationSetDeployment(a, sa))
+
+	// The ServiceAccount and the automount setting of an existing Deployment are updated.
+	deployment := &appsv1.Deployment{}
+	key := types.NamespacedName{Name: "argocd-applicationset-controller", Namespace: a.Namespace}
+	assert.NilError(t, r.client.Get(context.TODO(), key, deployment))
+	automount := false
+	deployment.Spec.Template.Spec.AutomountServiceAccountToken = &automount
+	assert.NilError(t, r.client.Update(context.TODO(), deployment))
+
+	a.Spec.ApplicationSet.ServiceAccountName = "platform-applicationset"
+	sa, err := r.reconcileApplicationSetServiceAccount(a)
+	assert.NilError(t, err)
+	assert.NilError(t, r.reconcileApplicationSetDeployment(a, sa))
+
+	deployment = &appsv1.Deployment{}
+	assert.NilError(t, r.client.Get(context.TODO(), key, deployment))
+	assert.Equal(t, deployment.Spec.Template.Spec.ServiceAccountName, "platform-applicationset")
+	assert.Assert(t, deployment.Spec.Template.Spec.AutomountServiceAccountToken == nil)
+}
+
 func TestReconcileApplicationSet_Deployments_resourceRequirements(t *testing.T) {
 	logf.SetLogger(logf.ZapLogger(true))
 	a := makeTestArgoCDWithResources()
@@ -189,7 +217,11 @@ func TestReconcileApplicationSet_Deployments_SpecOverride(t *testing.T) {
 		t.Run(test.name, func(t *testing.T) {
 
 			for testEnvName, testEnvValue := range test.envVars {
-				os.Setenv(testEnvName, testEnvValue)
+				name := testEnvName
+				os.Setenv(name, testEnvValue)
+				t.Cleanup(func() {
+					os.Unsetenv(name)
+				})
 			}
 
 			a := makeTestArgoCD()
@@ -283,6 +315,16 @@ func TestReconcileApplicationSet_Role(t *testing.T) {
 	sort.Strings(foundResources)
 
 	assert.DeepEqual(t, expectedResources, foundResources)
+
+	// The extra rules of the spec are added to the Role.
+	extraRules := []rbacv1.PolicyRule{{APIGroups: []string{"example.com"}, Resources: []string{"widgets"}, Verbs: []string{"get"}}}
+	a.Spec.ExtraRoleRules.ApplicationSet = extraRules
+	_, err = r.reconcileApplicationSetRole(a)
+	assert.NilError(t, err)
+
+	role = &rbacv1.Role{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-applicationset-controller", Namespace: a.Namespace}, role))
+	assert.DeepEqual(t, role.Rules, append(policyRuleForApplicationSetController(), extraRules...))
 }
 
 func TestReconcileApplicationSet_RoleBinding(t *testing.T) {
@@ -312,6 +354,159 @@ func TestReconcileApplicationSet_RoleBinding(t *testing.T) {
 
 }
 
+func TestReconcileApplicationSet_SourceNamespaces(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD(func(a *v1alpha1.ArgoCD) {
+		a.Spec.ApplicationSet = &v1alpha1.ArgoCDApplicationSet{
+			Image:            "quay.io/argoproj/argocd",
+			Version:          "v2.8.0",
+			SourceNamespaces: []string{"appset-1", "appset-2", testNamespace, "missing"},
+		}
+	})
+	r := makeTestReconciler(t, a)
+	assert.NilError(t, createNamespace(r, "appset-1", ""))
+	assert.NilError(t, createNamespace(r, "appset-2", ""))
+
+	sa := &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "sa-name", Namespace: testNamespace}}
+	assert.NilError(t, r.reconcileApplicationSetSourceNamespaces(a, sa))
+
+	name := "argocd-argocd-applicationset-controller"
+	for _, ns := range []string{"appset-1", "appset-2"} {
+		role := &rbacv1.Role{}
+		assert.NilError(t, argoutil.FetchObject(r.client, ns, name, role))
+		assert.DeepEqual(t, role.Rules, policyRuleForApplicationSetController())
+		assert.Equal(t, role.Labels[common.ArgoCDApplicationSetManagedByLabel], testNamespace)
+
+		roleBinding := &rbacv1.RoleBinding{}
+		assert.NilError(t, argoutil.FetchObject(r.client, ns, name, roleBinding))
+		assert.Equal(t, roleBinding.RoleRef.Name, name)
+		assert.Equal(t, roleBinding.Subjects[0].Name, sa.Name)
+		assert.Equal(t, roleBinding.Subjects[0].Namespace, testNamespace)
+	}
+	assert.Assert(t, !argoutil.IsObjectFound(r.client, testNamespace, name, &rbacv1.Role{}))
+
+	// Removing a namespace from the list removes the resources from that namespace.
+	a.Spec.ApplicationSet.SourceNamespaces = []string{"appset-1"}
+	assert.NilError(t, r.reconcileApplicationSetSourceNamespaces(a, sa))
+	assert.Assert(t, argoutil.IsObjectFound(r.client, "appset-1", name, &rbacv1.Role{}))
+	assert.Assert(t, !argoutil.IsObjectFound(r.client, "appset-2", name, &rbacv1.Role{}))
+	assert.Assert(t, !argoutil.IsObjectFound(r.client, "appset-2", name, &rbacv1.RoleBinding{}))
+
+	// The resources of another ArgoCD instance in the same namespace are kept.
+	other := makeTestArgoCD(func(o *v1alpha1.ArgoCD) {
+		o.Name = "other"
+	})
+	otherRole := newRole("applicationset-controller", policyRuleForApplicationSetController(), other)
+	otherRole.Name = GenerateUniqueResourceName("applicationset-controller", other)
+	otherRole.Namespace = "appset-2"
+	otherRole.Labels[common.ArgoCDApplicationSetManagedByLabel] = testNamespace
+	assert.NilError(t, r.client.Create(context.TODO(), otherRole))
+	assert.NilError(t, r.reconcileApplicationSetSourceNamespaces(a, sa))
+	assert.Assert(t, argoutil.IsObjectFound(r.client, "appset-2", otherRole.Name, &rbacv1.Role{}))
+
+	// Disabling the ApplicationSet controller removes the resources from all source namespaces.
+	assert.NilError(t, r.deleteApplicationSetSourceNamespaceResources(a, nil))
+	assert.Assert(t, !argoutil.IsObjectFound(r.client, "appset-1", name, &rbacv1.Role{}))
+	assert.Assert(t, !argoutil.IsObjectFound(r.client, "appset-1", name, &rbacv1.RoleBinding{}))
+}
+
+func TestReconcileApplicationSet_SourceNamespaces_notAllowed(t *testing.T) {
+	t.Cleanup(func() {
+		os.Unsetenv(common.ArgoCDManagedNamespacesDenylistEnvName)
+	})
+	os.Setenv(common.ArgoCDManagedNamespacesDenylistEnvName, "kube-*")
+
+	a := makeTestArgoCD(func(a *v1alpha1.ArgoCD) {
+		a.Spec.ApplicationSet = &v1alpha1.ArgoCDApplicationSet{
+			Image:            "quay.io/argoproj/argocd",
+			Version:          "v2.8.0",
+			SourceNamespaces: []string{"appset-1", "kube-system"},
+		}
+	})
+	r := makeTestReconciler(t, a)
+	assert.NilError(t, createNamespace(r, "appset-1", ""))
+	assert.NilError(t, createNamespace(r, "kube-system", ""))
+
+	// The namespaces the ArgoCD is not allowed to manage are rejected, and get no access to their secrets.
+	errs := validateArgoCDSpec(a)
+	assert.Equal(t, len(errs), 1)
+	assert.Equal(t, errs[0].Field, "spec.applicationSet.sourceNamespaces[1]")
+	assert.DeepEqual(t, getApplicationSetSourceNamespaces(a), []string{"appset-1"})
+
+	sa := &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "sa-name", Namespace: testNamespace}}
+	assert.NilError(t, r.reconcileApplicationSetSourceNamespaces(a, sa))
+	name := "argocd-argocd-applicationset-controller"
+	assert.Assert(t, argoutil.IsObjectFound(r.client, "appset-1", name, &rbacv1.Role{}))
+	assert.Assert(t, !argoutil.IsObjectFound(r.client, "kube-system", name, &rbacv1.Role{}))
+	assert.Assert(t, !argoutil.IsObjectFound(r.client, "kube-system", name, &rbacv1.RoleBinding{}))
+}
+
+func TestGetApplicationSetCommand_sourceNamespaces(t *testing.T) {
+	a := makeTestArgoCD(func(a *v1alpha1.ArgoCD) {
+		a.Spec.ApplicationSet = &v1alpha1.ArgoCDApplicationSet{
+			SourceNamespaces: []string{"appset-1", "appset-2", "appset-1"},
+		}
+	})
+
+	// The flag is not passed to an ApplicationSet controller that does not support it.
+	want := []string{
+		"applicationset-controller",
+		"--argocd-repo-server", getRepoServerAddress(a),
+	}
+	assert.DeepEqual(t, getApplicationSetCommand(a), want)
+
+	a.Spec.ApplicationSet.Image = "quay.io/argoproj/argocd"
+	a.Spec.ApplicationSet.Version = "v2.8.0"
+	want = append(want, "--applicationset-namespaces", "appset-1,appset-2")
+	assert.DeepEqual(t, getApplicationSetCommand(a), want)
+}
+
+func TestGetApplicationSetCommand_extraArgsAndLogLevel(t *testing.T) {
+	a := makeTestArgoCD(func(a *v1alpha1.ArgoCD) {
+		a.Spec.ApplicationSet = &v1alpha1.ArgoCDApplicationSet{
+			ExtraCommandArgs: []string{"--enable-progressive-syncs", "--allowed-scm-providers", "https://git.example.com/"},
+			LogLevel:         "debug",
+		}
+	})
+
+	want := []string{
+		"applicationset-controller",
+		"--argocd-repo-server", getRepoServerAddress(a),
+		"--loglevel", "debug",
+		"--enable-progressive-syncs",
+		"--allowed-scm-providers", "https://git.example.com/",
+	}
+	assert.DeepEqual(t, getApplicationSetCommand(a), want)
+}
+
+func TestGetApplicationSetCommand_progressiveSyncs(t *testing.T) {
+	a := makeTestArgoCD(func(a *v1alpha1.ArgoCD) {
+		a.Spec.ApplicationSet = &v1alpha1.ArgoCDApplicationSet{
+			EnableProgressiveSyncs: true,
+		}
+	})
+
+	want := []string{
+		"applicationset-controller",
+		"--argocd-repo-server", getRepoServerAddress(a),
+		"--enable-progressive-syncs",
+	}
+	assert.DeepEqual(t, getApplicationSetCommand(a), want)
+}
+
+func TestGetApplicationSetEnv(t *testing.T) {
+	a := makeTestArgoCD(func(a *v1alpha1.ArgoCD) {
+		a.Spec.ApplicationSet = &v1alpha1.ArgoCDApplicationSet{
+			Env: []corev1.EnvVar{{Name: "ARGOCD_APPLICATIONSET_CONTROLLER_POLICY", Value: "create-only"}},
+		}
+	})
+
+	env := getApplicationSetEnv(a)
+	assert.Equal(t, len(env), 2)
+	assert.Equal(t, env[0].Name, "NAMESPACE")
+	assert.DeepEqual(t, env[1], corev1.EnvVar{Name: "ARGOCD_APPLICATIONSET_CONTROLLER_POLICY", Value: "create-only"})
+}
+
 func appsetAssertExpectedLabels(t *testing.T, meta *metav1.ObjectMeta) {
 	assert.Equal(t, meta.Labels["app.kubernetes.io/name"], "argocd-applicationset-controller")
 	assert.Equal(t, meta.Labels["app.kubernetes.io/part-of"], "argocd-applicationset")
@@ -17,19 +17,27 @@ package argocd
 import (
 	"context"
 	"fmt"
+	"time"
 
 	argoproj "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
 	"github.com/argoproj-labs/argocd-operator/pkg/common"
+	"github.com/argoproj-labs/argocd-operator/pkg/controller/argoutil"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
 // blank assignment to verify that ReconcileArgoCD implements reconcile.Reconciler
@@ -40,7 +48,16 @@ type ReconcileArgoCD struct {
 	// This client, initialized using mgr.Client() above, is a split client
 	// that reads objects from the cache and writes to the apiserver
 	client client.Client
+	// This reader, initialized using mgr.GetAPIReader() above, reads objects directly from the apiserver
+	// and is used where the cache may not hold the object, e.g. outside of the watched namespaces.
+	reader client.Reader
+	// This cache, initialized using mgr.GetCache() above, reads objects from the informers of the manager. The split
+	// client reads unstructured objects, e.g. the Argo CD Applications, directly from the apiserver instead.
+	cache  client.Reader
 	scheme *runtime.Scheme
+	// remoteClient returns a client for the cluster of the given kubeconfig, e.g. to rotate the tokens of the
+	// discovered clusters.
+	remoteClient func(kubeconfig []byte) (client.Client, error)
 }
 
 var log = logf.Log.WithName("controller_argocd")
@@ -54,24 +71,40 @@ func Add(mgr manager.Manager) error {
 // add adds a new Controller to mgr with r as the reconcile.Reconciler
 func add(mgr manager.Manager, r *ReconcileArgoCD) error {
 	// Create a new controller
-	c, err := controller.New("argocd-controller", mgr, controller.Options{Reconciler: r})
+	c, err := controller.New("argocd-controller", mgr, controller.Options{
+		Reconciler:  r,
+		RateLimiter: newRateLimiter(rateLimiterOptions),
+	})
 	if err != nil {
 		return err
 	}
 
 	// Register watches for all controller resources
-	if err := watchResources(c, r.clusterResourceMapper, r.tlsSecretMapper, r.namespaceResourceMapper); err != nil {
+	if err := watchResources(c, r.clusterResourceMapper, r.tlsSecretMapper, r.dexTokenSecretMapper, r.dexConfigSecretMapper, r.serverTLSSecretMapper, r.secretKeysMapper, r.resourceHealthChecksMapper, r.tlsCertsMapper, r.disasterRecoverySecretMapper, r.clusterDiscoverySecretMapper, r.helmOCIRegistrySecretMapper, r.namespaceResourceMapper, r.argoCDDefaultMapper, r.applicationMapper); err != nil {
 		return err
 	}
 
-	return nil
+	// Reconcile all ArgoCD clusters when the default images mounted in the operator change
+	defaultImages := make(chan event.GenericEvent)
+	if err := mgr.Add(manager.RunnableFunc(func(stop <-chan struct{}) error {
+		pollDefaultImages(defaultImages, defaultImagesPollInterval, stop)
+		return nil
+	})); err != nil {
+		return err
+	}
+	return c.Watch(&source.Channel{Source: defaultImages}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(r.defaultImagesMapper),
+	})
 }
 
 // newReconciler returns a new reconcile.Reconciler
 func newReconciler(mgr manager.Manager) *ReconcileArgoCD {
 	return &ReconcileArgoCD{
-		client: mgr.GetClient(),
-		scheme: mgr.GetScheme(),
+		client:       mgr.GetClient(),
+		reader:       mgr.GetAPIReader(),
+		cache:        mgr.GetCache(),
+		scheme:       mgr.GetScheme(),
+		remoteClient: argoutil.NewClientFromKubeconfig,
 	}
 }
 
@@ -84,14 +117,19 @@ func (r *ReconcileArgoCD) Reconcile(request reconcile.Request) (reconcile.Result
 	reqLogger := log.WithValues("namespace", request.Namespace, "name", request.Name)
 	reqLogger.Info("Reconciling ArgoCD")
 
+	ctx, span := tracer.Start(context.Background(), "Reconcile", trace.WithAttributes(
+		attribute.String("namespace", request.Namespace),
+		attribute.String("name", request.Name)))
+	defer span.End()
+
 	argocd := &argoproj.ArgoCD{}
 	err := r.client.Get(context.TODO(), request.NamespacedName, argocd)
 	if err != nil {
 		if errors.IsNotFound(err) {
 			// Request object not found, could have been deleted after reconcile request.
-			// Owned objects are automatically garbage collected. For additional cleanup logic use finalizers.
-			// Return and don't requeue
-			return reconcile.Result{}, nil
+			// Owned objects are automatically garbage collected, cluster resources are not as they can not be owned
+			// by a namespaced object. Remove any that were left behind and don't requeue.
+			return reconcile.Result{}, r.reconcileOrphanedClusterResources()
 		}
 		// Error reading the object - requeue the request.
 		return reconcile.Result{}, err
@@ -99,6 +137,11 @@ func (r *ReconcileArgoCD) Reconcile(request reconcile.Request) (reconcile.Result
 
 	if argocd.GetDeletionTimestamp() != nil {
 		if argocd.IsDeletionFinalizerPresent() {
+			if delay, err := r.deleteComponentsInOrder(argocd); err != nil || delay > 0 {
+				// Wait for the component being deleted to be gone before removing the next one.
+				return reconcile.Result{RequeueAfter: delay}, err
+			}
+
 			if err := r.deleteClusterResources(argocd); err != nil {
 				return reconcile.Result{}, fmt.Errorf("failed to delete ClusterResources: %w", err)
 			}
@@ -140,11 +183,59 @@ func (r *ReconcileArgoCD) Reconcile(request reconcile.Request) (reconcile.Result
 		return reconcile.Result{}, err
 	}
 
-	if err := r.reconcileResources(argocd); err != nil {
+	if err := r.applyArgoCDDefault(argocd); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	valid, err := r.reconcileSpecValidation(argocd)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if !valid {
+		// The spec is reported in the SpecValid condition, wait for the ArgoCD to be updated.
+		return reconcile.Result{}, nil
+	}
+
+	if err := r.reconcileResources(ctx, argocd); err != nil {
 		// Error reconciling ArgoCD sub-resources - requeue the request.
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
 		return reconcile.Result{}, err
 	}
 
+	if delay := r.getRequeueDelay(argocd); delay > 0 {
+		// Requeue to correct drift at the reconcile interval, or to renew the repo-server certificate generated by the
+		// operator before it expires.
+		return reconcile.Result{RequeueAfter: delay}, nil
+	}
+
 	// Return and don't requeue
 	return reconcile.Result{}, nil
 }
+
+// getRequeueDelay will return the delay after which the given ArgoCD is reconciled again, which is the earliest of
+// its reconcile interval, the renewal of the repo-server certificate, the rotation of the CA, the next resource
+// usage snapshot and the next token rotation or revocation of the discovered clusters. Zero is returned when the
+// ArgoCD is only reconciled again at the resync period of the operator.
+func (r *ReconcileArgoCD) getRequeueDelay(cr *argoproj.ArgoCD) time.Duration {
+	delay := r.getRepoServerTLSRenewalDelay(cr)
+	if rotation := r.getClusterCARenewalDelay(cr); rotation > 0 && (delay == 0 || rotation < delay) {
+		delay = rotation
+	}
+	if interval := cr.Spec.ReconcileInterval; interval != nil && interval.Duration > 0 {
+		if delay == 0 || interval.Duration < delay {
+			delay = interval.Duration
+		}
+	}
+	if len(cr.Status.ResourceUsage) > 0 {
+		// Sampling is due at the end of the delay, wait at least a second to not requeue right away.
+		sample := getResourceUsageSampleDelay(cr, time.Now()) + time.Second
+		if delay == 0 || sample < delay {
+			delay = sample
+		}
+	}
+	if rotation := getDiscoveredClusterTokenRotationDelay(cr, time.Now()); rotation > 0 && (delay == 0 || rotation < delay) {
+		delay = rotation
+	}
+	return delay
+}
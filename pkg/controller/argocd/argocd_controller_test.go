@@ -107,6 +107,50 @@ func TestReconcileArgoCD_Reconcile(t *testing.T) {
 	}
 }
 
+func TestReconcileArgoCD_Reconcile_reconcileInterval(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD(func(a *argov1alpha1.ArgoCD) {
+		a.Spec.ReconcileInterval = &metav1.Duration{Duration: 5 * time.Minute}
+	})
+
+	r := makeTestReconciler(t, a)
+	assert.NilError(t, createNamespace(r, a.Namespace, ""))
+
+	req := reconcile.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      a.Name,
+			Namespace: a.Namespace,
+		},
+	}
+
+	res, err := r.Reconcile(req)
+	assert.NilError(t, err)
+	assert.Equal(t, res.RequeueAfter, 5*time.Minute)
+}
+
+func TestReconcileArgoCD_getRequeueDelay(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD(func(a *argov1alpha1.ArgoCD) {
+		a.Spec.Repo.AutoTLS = common.ArgoCDRepoServerAutoTLSOperator
+	})
+	r := makeTestReconciler(t, a)
+	assert.Equal(t, r.getRequeueDelay(a), time.Duration(0))
+
+	assert.NilError(t, r.reconcileClusterCASecret(a))
+	assert.NilError(t, r.reconcileRepoServerTLSSecret(a))
+	renewal := r.getRepoServerTLSRenewalDelay(a)
+	assert.Assert(t, renewal > 0)
+	assert.Assert(t, r.getRequeueDelay(a) <= renewal)
+	assert.Assert(t, r.getRequeueDelay(a) > renewal-time.Minute)
+
+	// The earliest of the reconcile interval and the certificate renewal is used.
+	a.Spec.ReconcileInterval = &metav1.Duration{Duration: time.Hour}
+	assert.Equal(t, r.getRequeueDelay(a), time.Hour)
+
+	a.Spec.ReconcileInterval = &metav1.Duration{Duration: renewal + time.Hour}
+	assert.Assert(t, r.getRequeueDelay(a) <= renewal)
+}
+
 func deletedAt(now time.Time) argoCDOpt {
 	return func(a *argov1alpha1.ArgoCD) {
 		wrapped := metav1.NewTime(now)
@@ -0,0 +1,89 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocd
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
+)
+
+// applyArgoCDDefault will set the properties of the given ArgoCD that are not set to the organization defaults of the
+// ArgoCDDefault of the cluster, when present. The defaults are only applied in memory and never written to the ArgoCD,
+// so that changes to the ArgoCDDefault are inherited by every ArgoCD on its next reconcile. An invalid ArgoCDDefault
+// is logged and ignored, so that it does not invalidate every ArgoCD of the cluster.
+func (r *ReconcileArgoCD) applyArgoCDDefault(cr *argoprojv1a1.ArgoCD) error {
+	defaults := &argoprojv1a1.ArgoCDDefault{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: common.ArgoCDDefaultResourceName}, defaults); err != nil {
+		if errors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to get the ArgoCDDefault %s: %w", common.ArgoCDDefaultResourceName, err)
+	}
+	if errs := validateArgoCDDefaultSpec(&defaults.Spec); len(errs) > 0 {
+		log.Error(errs.ToAggregate(), fmt.Sprintf("ignoring the invalid ArgoCDDefault %s for ArgoCD %s/%s",
+			defaults.Name, cr.Namespace, cr.Name))
+		return nil
+	}
+	mergeArgoCDDefault(cr, &defaults.Spec)
+	return nil
+}
+
+// validateArgoCDDefaultSpec will check the given ArgoCDDefault spec for the unsupported values that would invalidate
+// the spec of the ArgoCDs inheriting them.
+func validateArgoCDDefaultSpec(defaults *argoprojv1a1.ArgoCDDefaultSpec) field.ErrorList {
+	allErrs := field.ErrorList{}
+	spec := field.NewPath("spec")
+
+	if m := defaults.ComplianceMode; m != "" && !containsString(argoCDComplianceModes, m) {
+		allErrs = append(allErrs, field.NotSupported(spec.Child("complianceMode"), m, argoCDComplianceModes))
+	}
+	if p := defaults.Profile; p != "" && !containsString(argoCDProfiles, p) {
+		allErrs = append(allErrs, field.NotSupported(spec.Child("profile"), p, argoCDProfiles))
+	}
+	if v := defaults.TLSMinVersion; v != "" && !containsString(tlsMinVersions, v) {
+		allErrs = append(allErrs, field.NotSupported(spec.Child("tlsMinVersion"), v, tlsMinVersions))
+	}
+	return allErrs
+}
+
+// mergeArgoCDDefault will set the properties of the given ArgoCD that are not set to the given defaults.
+func mergeArgoCDDefault(cr *argoprojv1a1.ArgoCD, defaults *argoprojv1a1.ArgoCDDefaultSpec) {
+	if cr.Spec.ComplianceMode == "" {
+		cr.Spec.ComplianceMode = defaults.ComplianceMode
+	}
+	if cr.Spec.Image == "" {
+		cr.Spec.Image = defaults.Image
+	}
+	if cr.Spec.Profile == "" {
+		cr.Spec.Profile = defaults.Profile
+	}
+	if cr.Spec.Proxy == nil && defaults.Proxy != nil {
+		cr.Spec.Proxy = defaults.Proxy.DeepCopy()
+	}
+	if cr.Spec.Server.TLS.MinVersion == "" {
+		cr.Spec.Server.TLS.MinVersion = defaults.TLSMinVersion
+	}
+	if cr.Spec.Version == "" {
+		cr.Spec.Version = defaults.Version
+	}
+}
@@ -0,0 +1,105 @@
+package argocd
+
+import (
+	"context"
+	"testing"
+
+	"gotest.tools/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+
+	argoprojv1alpha1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
+)
+
+func makeTestArgoCDDefault(name string) *argoprojv1alpha1.ArgoCDDefault {
+	return &argoprojv1alpha1.ArgoCDDefault{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: argoprojv1alpha1.ArgoCDDefaultSpec{
+			Image:         "registry.example.com/argocd",
+			Profile:       "large",
+			Proxy:         &argoprojv1alpha1.ArgoCDProxySpec{HTTPSProxy: "proxy.example.com:3128"},
+			TLSMinVersion: "1.2",
+			Version:       "v2.0.5",
+		},
+	}
+}
+
+func TestReconcileArgoCD_applyArgoCDDefault(t *testing.T) {
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.Version = "v2.1.0"
+	})
+	r := makeTestReconciler(t, a)
+
+	// Nothing is inherited without an ArgoCDDefault.
+	assert.NilError(t, r.applyArgoCDDefault(a))
+	assert.Equal(t, a.Spec.Image, "")
+
+	assert.NilError(t, r.client.Create(context.TODO(), makeTestArgoCDDefault(common.ArgoCDDefaultResourceName)))
+	assert.NilError(t, r.applyArgoCDDefault(a))
+	assert.Equal(t, a.Spec.Image, "registry.example.com/argocd")
+	assert.Equal(t, a.Spec.Version, "v2.1.0")
+	assert.Equal(t, a.Spec.Profile, "large")
+	assert.Equal(t, a.Spec.Proxy.HTTPSProxy, "proxy.example.com:3128")
+	assert.Equal(t, a.Spec.Server.TLS.MinVersion, "1.2")
+	assert.Equal(t, a.Spec.ComplianceMode, "")
+}
+
+func TestReconcileArgoCD_applyArgoCDDefault_invalid(t *testing.T) {
+	a := makeTestArgoCD()
+	r := makeTestReconciler(t, a)
+
+	// An invalid ArgoCDDefault is ignored, so that the ArgoCDs inheriting it remain valid.
+	d := makeTestArgoCDDefault(common.ArgoCDDefaultResourceName)
+	d.Spec.Profile = "huge"
+	assert.NilError(t, r.client.Create(context.TODO(), d))
+	assert.NilError(t, r.applyArgoCDDefault(a))
+	assert.Equal(t, a.Spec.Image, "")
+	assert.Equal(t, a.Spec.Profile, "")
+
+	valid, err := r.reconcileSpecValidation(a)
+	assert.NilError(t, err)
+	assert.Assert(t, valid)
+}
+
+func TestReconcileArgoCD_applyArgoCDDefault_statusUpdate(t *testing.T) {
+	a := makeTestArgoCD()
+	r := makeTestReconciler(t, a)
+	assert.NilError(t, r.client.Create(context.TODO(), makeTestArgoCDDefault(common.ArgoCDDefaultResourceName)))
+	assert.NilError(t, r.applyArgoCDDefault(a))
+
+	// Writing the status keeps the inherited defaults for the rest of the reconcile.
+	_, err := r.reconcileSpecValidation(a)
+	assert.NilError(t, err)
+	assert.Equal(t, a.Spec.Image, "registry.example.com/argocd")
+	assert.Equal(t, a.Spec.Profile, "large")
+}
+
+func TestValidateArgoCDDefaultSpec(t *testing.T) {
+	d := makeTestArgoCDDefault(common.ArgoCDDefaultResourceName)
+	assert.Equal(t, len(validateArgoCDDefaultSpec(&d.Spec)), 0)
+
+	d.Spec.ComplianceMode = "baseline"
+	d.Spec.Profile = "huge"
+	d.Spec.TLSMinVersion = "1.4"
+	errs := validateArgoCDDefaultSpec(&d.Spec)
+	assert.Equal(t, len(errs), 3)
+	assert.Equal(t, errs[0].Field, "spec.complianceMode")
+	assert.Equal(t, errs[1].Field, "spec.profile")
+	assert.Equal(t, errs[2].Field, "spec.tlsMinVersion")
+}
+
+func TestReconcileArgoCD_argoCDDefaultMapper(t *testing.T) {
+	a := makeTestArgoCD()
+	r := makeTestReconciler(t, a)
+
+	d := makeTestArgoCDDefault(common.ArgoCDDefaultResourceName)
+	reqs := r.argoCDDefaultMapper(handler.MapObject{Meta: d, Object: d})
+	assert.Equal(t, len(reqs), 1)
+	assert.Equal(t, reqs[0].Name, a.Name)
+	assert.Equal(t, reqs[0].Namespace, a.Namespace)
+
+	// Only the ArgoCDDefault of the cluster is inherited.
+	d = makeTestArgoCDDefault("team-a")
+	assert.Equal(t, len(r.argoCDDefaultMapper(handler.MapObject{Meta: d, Object: d})), 0)
+}
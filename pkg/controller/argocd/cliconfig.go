@@ -0,0 +1,262 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocd
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	routev1 "github.com/openshift/api/route/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
+	"github.com/argoproj-labs/argocd-operator/pkg/controller/argoutil"
+)
+
+// getCLIConfigNamespace will return the namespace of the CLI ConfigMap or Secret for the given ArgoCD.
+func getCLIConfigNamespace(cr *argoprojv1a1.ArgoCD) string {
+	if cr.Spec.CLIConfig.Namespace != "" {
+		return cr.Spec.CLIConfig.Namespace
+	}
+	return cr.Namespace
+}
+
+// getCLIConfigName will return the name of the CLI ConfigMap or Secret for the given ArgoCD. When published to another
+// namespace, the name includes the namespace of the ArgoCD to keep the connection details of several instances apart.
+func getCLIConfigName(cr *argoprojv1a1.ArgoCD) string {
+	if getCLIConfigNamespace(cr) != cr.Namespace {
+		return GenerateUniqueResourceName(common.ArgoCDCLIConfigSuffix, cr)
+	}
+	return nameWithSuffix(common.ArgoCDCLIConfigSuffix, cr)
+}
+
+// newCLIConfigMap returns a new ConfigMap instance for the connection details of the argocd CLI.
+func newCLIConfigMap(cr *argoprojv1a1.ArgoCD) *corev1.ConfigMap {
+	cm := newConfigMapWithName(getCLIConfigName(cr), cr)
+	cm.Namespace = getCLIConfigNamespace(cr)
+	cm.Labels[common.ArgoCDCLIConfigManagedByLabel] = cr.Namespace
+	return cm
+}
+
+// newCLIConfigSecret returns a new Secret instance for the connection details of the argocd CLI.
+func newCLIConfigSecret(cr *argoprojv1a1.ArgoCD) *corev1.Secret {
+	secret := argoutil.NewSecretWithName(cr.ObjectMeta, getCLIConfigName(cr))
+	secret.Namespace = getCLIConfigNamespace(cr)
+	secret.Labels[common.ArgoCDCLIConfigManagedByLabel] = cr.Namespace
+	return secret
+}
+
+// getCLIConfigData will return the address of the Argo CD server and the certificate of the CA that signed the
+// certificate it presents, in that order of precedence: the host of the Route, the host of the Ingress, the
+// Server.Host property and the in-cluster address of the server Service. Routes that are not passthrough Routes
+// present the certificate of the Route, or the default certificate of the router, so the CA of the operator is left
+// out for them.
+func (r *ReconcileArgoCD) getCLIConfigData(cr *argoprojv1a1.ArgoCD, caSecret *corev1.Secret) map[string]string {
+	server := fmt.Sprintf("%s.%s.svc", nameWithSuffix("server", cr), cr.Namespace)
+	ca := string(caSecret.Data[common.ArgoCDKeyTLSCert])
+
+	if cr.Spec.Server.Host != "" {
+		server = cr.Spec.Server.Host
+	}
+
+	if cr.Spec.Server.Ingress.Enabled {
+		ing := newIngressWithSuffix("server", cr)
+		if argoutil.IsObjectFound(r.client, cr.Namespace, ing.Name, ing) && len(ing.Spec.Rules) > 0 {
+			server = ing.Spec.Rules[0].Host
+		}
+	}
+
+	if IsRouteAPIAvailable() {
+		route := newRouteWithSuffix("server", cr)
+		if argoutil.IsObjectFound(r.client, cr.Namespace, route.Name, route) {
+			server = route.Spec.Host
+			if route.Spec.TLS != nil && route.Spec.TLS.Termination != routev1.TLSTerminationPassthrough {
+				ca = route.Spec.TLS.CACertificate
+			}
+		}
+	}
+
+	data := map[string]string{
+		common.ArgoCDKeyCLIServer: server,
+	}
+	if ca != "" {
+		data[common.ArgoCDKeyTLSCACert] = ca
+	}
+	return data
+}
+
+// isCLIConfigFound will return true if the given CLI ConfigMap or Secret exists. Objects in another namespace than the
+// given ArgoCD are read from the API server, as the cache of the operator may only hold the watched namespaces.
+func (r *ReconcileArgoCD) isCLIConfigFound(cr *argoprojv1a1.ArgoCD, namespace string, name string, obj runtime.Object) (bool, error) {
+	if namespace == cr.Namespace {
+		return argoutil.IsObjectFound(r.client, namespace, name, obj), nil
+	}
+	return r.isLiveObjectFound(namespace, name, obj)
+}
+
+// reconcileCLIConfig will ensure that the ConfigMap or the Secret holding the address and the CA certificate of the
+// Argo CD server is present in the configured namespace when enabled, so that the argocd CLI can connect without
+// looking up the Route or Ingress of the server.
+func (r *ReconcileArgoCD) reconcileCLIConfig(cr *argoprojv1a1.ArgoCD) error {
+	if !cr.Spec.CLIConfig.Enabled {
+		if err := r.deleteCLIConfigMaps(cr, nil); err != nil {
+			return err
+		}
+		return r.deleteCLIConfigSecrets(cr, nil)
+	}
+
+	caSecret := argoutil.NewSecretWithSuffix(cr.ObjectMeta, common.ArgoCDCASuffix)
+	if !argoutil.IsObjectFound(r.client, cr.Namespace, caSecret.Name, caSecret) {
+		log.Info(fmt.Sprintf("ca secret [%s] not found, waiting to reconcile cli config", caSecret.Name))
+		return nil
+	}
+	data := r.getCLIConfigData(cr, caSecret)
+
+	if cr.Spec.CLIConfig.Kind == common.ArgoCDCLIConfigKindSecret {
+		secret, err := r.reconcileCLIConfigSecret(cr, data)
+		if err != nil {
+			return err
+		}
+		if err := r.deleteCLIConfigMaps(cr, nil); err != nil {
+			return err
+		}
+		return r.deleteCLIConfigSecrets(cr, secret)
+	}
+
+	cm, err := r.reconcileCLIConfigMap(cr, data)
+	if err != nil {
+		return err
+	}
+	if err := r.deleteCLIConfigSecrets(cr, nil); err != nil {
+		return err
+	}
+	return r.deleteCLIConfigMaps(cr, cm)
+}
+
+// reconcileCLIConfigMap will ensure that the CLI ConfigMap of the given ArgoCD holds the given connection details and
+// return it.
+func (r *ReconcileArgoCD) reconcileCLIConfigMap(cr *argoprojv1a1.ArgoCD, data map[string]string) (*corev1.ConfigMap, error) {
+	cm := newCLIConfigMap(cr)
+	existing := &corev1.ConfigMap{}
+	found, err := r.isCLIConfigFound(cr, cm.Namespace, cm.Name, existing)
+	if err != nil {
+		return nil, err
+	}
+	if found {
+		if !reflect.DeepEqual(existing.Data, data) {
+			existing.Data = data
+			if err := r.client.Update(context.TODO(), existing); err != nil {
+				return nil, err
+			}
+		}
+		return cm, nil
+	}
+
+	cm.Data = data
+	// Owner references can not cross namespaces, a ConfigMap in another namespace is removed by the finalizer.
+	if cm.Namespace == cr.Namespace {
+		if err := controllerutil.SetControllerReference(cr, cm, r.scheme); err != nil {
+			return nil, err
+		}
+	}
+	return cm, r.client.Create(context.TODO(), cm)
+}
+
+// reconcileCLIConfigSecret will ensure that the CLI Secret of the given ArgoCD holds the given connection details and
+// return it.
+func (r *ReconcileArgoCD) reconcileCLIConfigSecret(cr *argoprojv1a1.ArgoCD, data map[string]string) (*corev1.Secret, error) {
+	secret := newCLIConfigSecret(cr)
+	secretData := make(map[string][]byte, len(data))
+	for k, v := range data {
+		secretData[k] = []byte(v)
+	}
+
+	existing := &corev1.Secret{}
+	found, err := r.isCLIConfigFound(cr, secret.Namespace, secret.Name, existing)
+	if err != nil {
+		return nil, err
+	}
+	if found {
+		if !reflect.DeepEqual(existing.Data, secretData) {
+			existing.Data = secretData
+			if err := r.client.Update(context.TODO(), existing); err != nil {
+				return nil, err
+			}
+		}
+		return secret, nil
+	}
+
+	secret.Data = secretData
+	// Owner references can not cross namespaces, a Secret in another namespace is removed by the finalizer.
+	if secret.Namespace == cr.Namespace {
+		if err := controllerutil.SetControllerReference(cr, secret, r.scheme); err != nil {
+			return nil, err
+		}
+	}
+	return secret, r.client.Create(context.TODO(), secret)
+}
+
+// deleteCLIConfigMaps will remove the CLI ConfigMaps of the given ArgoCD from all namespaces, except the given
+// ConfigMap. The ConfigMaps are listed from the API server, as the cache of the operator may only hold the watched
+// namespaces.
+func (r *ReconcileArgoCD) deleteCLIConfigMaps(cr *argoprojv1a1.ArgoCD, keep *corev1.ConfigMap) error {
+	configMaps := &corev1.ConfigMapList{}
+	if err := r.reader.List(context.TODO(), configMaps, client.MatchingLabels{
+		common.ArgoCDCLIConfigManagedByLabel: cr.Namespace,
+	}); err != nil {
+		return fmt.Errorf("failed to list CLI ConfigMaps for %s: %w", cr.Name, err)
+	}
+
+	for i := range configMaps.Items {
+		cm := &configMaps.Items[i]
+		if keep != nil && cm.Namespace == keep.Namespace && cm.Name == keep.Name {
+			continue
+		}
+		log.Info(fmt.Sprintf("deleting cli configmap [%s] in namespace [%s]", cm.Name, cm.Namespace))
+		if err := r.client.Delete(context.TODO(), cm); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteCLIConfigSecrets will remove the CLI Secrets of the given ArgoCD from all namespaces, except the given Secret.
+// The Secrets are listed from the API server, as the cache of the operator may only hold the watched namespaces.
+func (r *ReconcileArgoCD) deleteCLIConfigSecrets(cr *argoprojv1a1.ArgoCD, keep *corev1.Secret) error {
+	secrets := &corev1.SecretList{}
+	if err := r.reader.List(context.TODO(), secrets, client.MatchingLabels{
+		common.ArgoCDCLIConfigManagedByLabel: cr.Namespace,
+	}); err != nil {
+		return fmt.Errorf("failed to list CLI Secrets for %s: %w", cr.Name, err)
+	}
+
+	for i := range secrets.Items {
+		secret := &secrets.Items[i]
+		if keep != nil && secret.Namespace == keep.Namespace && secret.Name == keep.Name {
+			continue
+		}
+		log.Info(fmt.Sprintf("deleting cli secret [%s] in namespace [%s]", secret.Name, secret.Namespace))
+		if err := r.client.Delete(context.TODO(), secret); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
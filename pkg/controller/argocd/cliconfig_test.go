@@ -0,0 +1,160 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocd
+
+import (
+	"context"
+	"testing"
+
+	routev1 "github.com/openshift/api/route/v1"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	argoprojv1alpha1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
+	"github.com/argoproj-labs/argocd-operator/pkg/controller/argoutil"
+)
+
+func makeTestCASecret(a *argoprojv1alpha1.ArgoCD) *corev1.Secret {
+	secret := argoutil.NewSecretWithSuffix(a.ObjectMeta, common.ArgoCDCASuffix)
+	secret.Data = map[string][]byte{
+		common.ArgoCDKeyTLSCert: []byte("test-ca"),
+	}
+	return secret
+}
+
+func TestReconcileArgoCD_reconcileCLIConfig(t *testing.T) {
+	restoreEnv(t)
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.CLIConfig.Enabled = true
+	})
+	r := makeTestReconciler(t, a, makeTestCASecret(a))
+
+	assert.NilError(t, r.reconcileCLIConfig(a))
+
+	cm := &corev1.ConfigMap{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-cli-config", Namespace: testNamespace}, cm))
+	assert.DeepEqual(t, cm.Data, map[string]string{
+		common.ArgoCDKeyCLIServer: "argocd-server.argocd.svc",
+		common.ArgoCDKeyTLSCACert: "test-ca",
+	})
+
+	// The ConfigMap follows the host of the server.
+	a.Spec.Server.Host = "argocd.example.com"
+	assert.NilError(t, r.reconcileCLIConfig(a))
+
+	cm = &corev1.ConfigMap{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-cli-config", Namespace: testNamespace}, cm))
+	assert.Equal(t, cm.Data[common.ArgoCDKeyCLIServer], "argocd.example.com")
+
+	a.Spec.CLIConfig.Enabled = false
+	assert.NilError(t, r.reconcileCLIConfig(a))
+
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-cli-config", Namespace: testNamespace}, &corev1.ConfigMap{})
+	assert.Assert(t, errors.IsNotFound(err))
+}
+
+func TestReconcileArgoCD_reconcileCLIConfig_withNamespace(t *testing.T) {
+	restoreEnv(t)
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.CLIConfig.Enabled = true
+		a.Spec.CLIConfig.Namespace = "ci"
+	})
+	r := makeTestReconciler(t, a, makeTestCASecret(a))
+
+	assert.NilError(t, r.reconcileCLIConfig(a))
+
+	cm := &corev1.ConfigMap{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-argocd-cli-config", Namespace: "ci"}, cm))
+	assert.Equal(t, cm.Labels[common.ArgoCDCLIConfigManagedByLabel], testNamespace)
+	assert.Equal(t, len(cm.OwnerReferences), 0)
+
+	// Moving the ConfigMap removes it from the previous namespace.
+	a.Spec.CLIConfig.Namespace = "ci-pipelines"
+	assert.NilError(t, r.reconcileCLIConfig(a))
+
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-argocd-cli-config", Namespace: "ci-pipelines"}, &corev1.ConfigMap{}))
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-argocd-cli-config", Namespace: "ci"}, &corev1.ConfigMap{})
+	assert.Assert(t, errors.IsNotFound(err))
+
+	// The ConfigMap in another namespace is removed with the cluster resources.
+	assert.NilError(t, r.deleteCLIConfigMaps(a, nil))
+	err = r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-argocd-cli-config", Namespace: "ci-pipelines"}, &corev1.ConfigMap{})
+	assert.Assert(t, errors.IsNotFound(err))
+}
+
+func TestReconcileArgoCD_reconcileCLIConfig_withSecret(t *testing.T) {
+	restoreEnv(t)
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.CLIConfig.Enabled = true
+		a.Spec.CLIConfig.Namespace = "ci"
+	})
+	r := makeTestReconciler(t, a, makeTestCASecret(a))
+
+	assert.NilError(t, r.reconcileCLIConfig(a))
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-argocd-cli-config", Namespace: "ci"}, &corev1.ConfigMap{}))
+
+	// Switching to a Secret replaces the ConfigMap.
+	a.Spec.CLIConfig.Kind = common.ArgoCDCLIConfigKindSecret
+	assert.NilError(t, r.reconcileCLIConfig(a))
+
+	secret := &corev1.Secret{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-argocd-cli-config", Namespace: "ci"}, secret))
+	assert.Equal(t, secret.Labels[common.ArgoCDCLIConfigManagedByLabel], testNamespace)
+	assert.DeepEqual(t, secret.Data, map[string][]byte{
+		common.ArgoCDKeyCLIServer: []byte("argocd-server.argocd.svc"),
+		common.ArgoCDKeyTLSCACert: []byte("test-ca"),
+	})
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-argocd-cli-config", Namespace: "ci"}, &corev1.ConfigMap{})
+	assert.Assert(t, errors.IsNotFound(err))
+
+	// The Secret in another namespace is removed with the cluster resources.
+	assert.NilError(t, r.deleteCLIConfigSecrets(a, nil))
+	err = r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-argocd-cli-config", Namespace: "ci"}, &corev1.Secret{})
+	assert.Assert(t, errors.IsNotFound(err))
+}
+
+func TestReconcileArgoCD_getCLIConfigData_withRoute(t *testing.T) {
+	restoreEnv(t)
+	setRouteAPIFound(t, true)
+	a := makeTestArgoCD()
+	route := newRouteWithSuffix("server", a)
+	route.Spec.Host = "argocd.apps.example.com"
+	route.Spec.TLS = &routev1.TLSConfig{Termination: routev1.TLSTerminationPassthrough}
+	r := makeReconciler(t, a, a, route)
+	caSecret := makeTestCASecret(a)
+
+	// A passthrough Route presents the certificate of the server, signed by the CA of the operator.
+	assert.DeepEqual(t, r.getCLIConfigData(a, caSecret), map[string]string{
+		common.ArgoCDKeyCLIServer: "argocd.apps.example.com",
+		common.ArgoCDKeyTLSCACert: "test-ca",
+	})
+
+	// An edge Route presents the certificate of the router.
+	route.Spec.TLS = &routev1.TLSConfig{Termination: routev1.TLSTerminationEdge}
+	assert.NilError(t, r.client.Update(context.TODO(), route))
+	assert.DeepEqual(t, r.getCLIConfigData(a, caSecret), map[string]string{
+		common.ArgoCDKeyCLIServer: "argocd.apps.example.com",
+	})
+
+	route.Spec.TLS.CACertificate = "route-ca"
+	assert.NilError(t, r.client.Update(context.TODO(), route))
+	assert.DeepEqual(t, r.getCLIConfigData(a, caSecret), map[string]string{
+		common.ArgoCDKeyCLIServer: "argocd.apps.example.com",
+		common.ArgoCDKeyTLSCACert: "route-ca",
+	})
+}
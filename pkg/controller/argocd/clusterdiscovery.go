@@ -0,0 +1,377 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
+	"github.com/argoproj-labs/argocd-operator/pkg/controller/argoutil"
+)
+
+// clusterDiscoveryComponent is the component label value of the cluster Secrets generated by the cluster discovery.
+const clusterDiscoveryComponent = "cluster-discovery"
+
+// discoveredClusterConfig is the connection configuration of an Argo CD cluster Secret.
+type discoveredClusterConfig struct {
+	BearerToken        string                               `json:"bearerToken,omitempty"`
+	ExecProviderConfig *discoveredClusterExecProviderConfig `json:"execProviderConfig,omitempty"`
+	Password           string                               `json:"password,omitempty"`
+	TLSClientConfig    discoveredClusterTLSClientConfig     `json:"tlsClientConfig"`
+	Username           string                               `json:"username,omitempty"`
+}
+
+// discoveredClusterExecProviderConfig is the exec credentials plugin configuration of an Argo CD cluster Secret.
+type discoveredClusterExecProviderConfig struct {
+	APIVersion string            `json:"apiVersion,omitempty"`
+	Args       []string          `json:"args,omitempty"`
+	Command    string            `json:"command,omitempty"`
+	Env        map[string]string `json:"env,omitempty"`
+}
+
+// discoveredClusterTLSClientConfig is the TLS configuration of an Argo CD cluster Secret.
+type discoveredClusterTLSClientConfig struct {
+	CAData     []byte `json:"caData,omitempty"`
+	CertData   []byte `json:"certData,omitempty"`
+	Insecure   bool   `json:"insecure"`
+	KeyData    []byte `json:"keyData,omitempty"`
+	ServerName string `json:"serverName,omitempty"`
+}
+
+// getDiscoveredClusterSecretName will return the name of the cluster Secret generated from the given Secret.
+func getDiscoveredClusterSecretName(source string, cr *argoprojv1a1.ArgoCD) string {
+	return nameWithSuffix("discovered-"+source, cr)
+}
+
+// getDiscoveredClusterData will return the data of the Argo CD cluster Secret for the current context of the given
+// kubeconfig. Only credentials embedded in the kubeconfig are supported, as referenced files are not available.
+func getDiscoveredClusterData(name string, kubeconfig []byte) (map[string][]byte, error) {
+	cfg, err := clientcmd.Load(kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+
+	current := cfg.CurrentContext
+	if current == "" && len(cfg.Contexts) == 1 {
+		for contextName := range cfg.Contexts {
+			current = contextName
+		}
+	}
+	kubeContext, ok := cfg.Contexts[current]
+	if !ok {
+		return nil, fmt.Errorf("kubeconfig has no current context")
+	}
+	cluster, ok := cfg.Clusters[kubeContext.Cluster]
+	if !ok || cluster.Server == "" {
+		return nil, fmt.Errorf("kubeconfig has no server for cluster %q", kubeContext.Cluster)
+	}
+	if cluster.CertificateAuthority != "" {
+		return nil, fmt.Errorf("kubeconfig references the certificate authority file %s, embed it with certificate-authority-data instead", cluster.CertificateAuthority)
+	}
+
+	config := discoveredClusterConfig{
+		TLSClientConfig: discoveredClusterTLSClientConfig{
+			CAData:     cluster.CertificateAuthorityData,
+			Insecure:   cluster.InsecureSkipTLSVerify,
+			ServerName: cluster.TLSServerName,
+		},
+	}
+
+	if auth, ok := cfg.AuthInfos[kubeContext.AuthInfo]; ok {
+		if auth.TokenFile != "" || auth.ClientCertificate != "" || auth.ClientKey != "" {
+			return nil, fmt.Errorf("kubeconfig references credential files for user %q, embed the credentials instead", kubeContext.AuthInfo)
+		}
+		config.BearerToken = auth.Token
+		config.Username = auth.Username
+		config.Password = auth.Password
+		config.TLSClientConfig.CertData = auth.ClientCertificateData
+		config.TLSClientConfig.KeyData = auth.ClientKeyData
+		if auth.Exec != nil {
+			config.ExecProviderConfig = &discoveredClusterExecProviderConfig{
+				APIVersion: auth.Exec.APIVersion,
+				Args:       auth.Exec.Args,
+				Command:    auth.Exec.Command,
+			}
+			if len(auth.Exec.Env) > 0 {
+				config.ExecProviderConfig.Env = make(map[string]string, len(auth.Exec.Env))
+				for _, env := range auth.Exec.Env {
+					config.ExecProviderConfig.Env[env.Name] = env.Value
+				}
+			}
+		}
+	}
+
+	data, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+	return map[string][]byte{
+		"config": data,
+		"name":   []byte(name),
+		"server": []byte(cluster.Server),
+	}, nil
+}
+
+// setDiscoveredClusterToken will return a copy of the given cluster Secret data that authenticates with the given
+// bearer token instead of the credentials of the kubeconfig.
+func setDiscoveredClusterToken(data map[string][]byte, token []byte) (map[string][]byte, error) {
+	config := discoveredClusterConfig{}
+	if err := json.Unmarshal(data["config"], &config); err != nil {
+		return nil, err
+	}
+	config.BearerToken = string(token)
+	config.ExecProviderConfig = nil
+	config.Password = ""
+	config.Username = ""
+	config.TLSClientConfig.CertData = nil
+	config.TLSClientConfig.KeyData = nil
+
+	raw, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string][]byte, len(data))
+	for k, v := range data {
+		result[k] = v
+	}
+	result["config"] = raw
+	return result, nil
+}
+
+// getDiscoveredClusterTokenRotationInterval will return the interval at which the ServiceAccount tokens of the
+// discovered clusters are regenerated, zero when they are not rotated.
+func getDiscoveredClusterTokenRotationInterval(cr *argoprojv1a1.ArgoCD) time.Duration {
+	if cr.Spec.ClusterDiscovery.TokenRotationInterval == nil || cr.Spec.ClusterDiscovery.TokenRotationInterval.Duration < 0 {
+		return 0
+	}
+	return cr.Spec.ClusterDiscovery.TokenRotationInterval.Duration
+}
+
+// newDiscoveredClusterTokenRotation will return the rotation of the token of the ServiceAccount named by the given
+// kubeconfig Secret on its cluster, nil when the token of the cluster is not rotated.
+func (r *ReconcileArgoCD) newDiscoveredClusterTokenRotation(cr *argoprojv1a1.ArgoCD, source *corev1.Secret) (*argoutil.TokenRotation, error) {
+	interval := getDiscoveredClusterTokenRotationInterval(cr)
+	account, ok := source.Annotations[common.ArgoCDClusterServiceAccountAnnotation]
+	if interval == 0 || !ok {
+		return nil, nil
+	}
+	parts := strings.Split(account, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf("invalid service account %q, expected <namespace>/<name>", account)
+	}
+
+	newClient := r.remoteClient
+	if newClient == nil {
+		newClient = argoutil.NewClientFromKubeconfig
+	}
+	remote, err := newClient(source.Data[common.ArgoCDKeyClusterDiscoveryKubeconfig])
+	if err != nil {
+		return nil, err
+	}
+
+	name := nameWithSuffix("cluster-token", cr)
+	labels := argoutil.DefaultLabels(name)
+	labels[common.ArgoCDKeyComponent] = clusterDiscoveryComponent
+	return &argoutil.TokenRotation{
+		Client:          remote,
+		Interval:        interval,
+		Labels:          labels,
+		Name:            name,
+		Namespace:       parts[0],
+		RevocationDelay: argoutil.DefaultTokenRevocationDelay,
+		ServiceAccount:  parts[1],
+	}, nil
+}
+
+// getDiscoveredClusterTokenRotationStatus will return the token rotation status of the given kubeconfig Secret
+// recorded on the given ArgoCD, nil when none is recorded.
+func getDiscoveredClusterTokenRotationStatus(cr *argoprojv1a1.ArgoCD, source string) *argoprojv1a1.ArgoCDClusterTokenRotationStatus {
+	for i := range cr.Status.ClusterTokenRotations {
+		if cr.Status.ClusterTokenRotations[i].SecretName == source {
+			return &cr.Status.ClusterTokenRotations[i]
+		}
+	}
+	return nil
+}
+
+// getDiscoveredClusterTokenRotationDelay will return the delay until the next token rotation or revocation of the
+// discovered clusters of the given ArgoCD, zero when none is scheduled.
+func getDiscoveredClusterTokenRotationDelay(cr *argoprojv1a1.ArgoCD, now time.Time) time.Duration {
+	var delay time.Duration
+	for _, status := range cr.Status.ClusterTokenRotations {
+		for _, at := range []*metav1.Time{status.NextRotation, status.Revocation} {
+			if at == nil {
+				continue
+			}
+			// Wait at least a second to not requeue right away.
+			d := at.Sub(now)
+			if d < time.Second {
+				d = time.Second
+			}
+			if delay == 0 || d < delay {
+				delay = d
+			}
+		}
+	}
+	return delay
+}
+
+// writeDiscoveredClusterSecret will create the given cluster Secret of the given kubeconfig Secret with the given data,
+// or update it if it already exists.
+func (r *ReconcileArgoCD) writeDiscoveredClusterSecret(cr *argoprojv1a1.ArgoCD, source string, secret *corev1.Secret, data map[string][]byte) error {
+	existing := secret.DeepCopy()
+	if argoutil.IsObjectFound(r.client, cr.Namespace, secret.Name, existing) {
+		if !reflect.DeepEqual(existing.Data, data) {
+			existing.Data = data
+			return r.client.Update(context.TODO(), existing)
+		}
+		return nil
+	}
+
+	secret.Annotations = map[string]string{
+		common.ArgoCDKeyDiscoveredClusterSource: source,
+	}
+	secret.Data = data
+	if err := controllerutil.SetControllerReference(cr, secret, r.scheme); err != nil {
+		return err
+	}
+	log.Info(fmt.Sprintf("registering cluster from secret %s", source))
+	return r.client.Create(context.TODO(), secret)
+}
+
+// reconcileDiscoveredClusterSecrets will ensure that an Argo CD cluster Secret is present for each of the kubeconfig
+// Secrets selected for cluster discovery, and that the cluster Secrets of Secrets that are no longer selected or have
+// been removed are deleted. When token rotation is enabled, the clusters authenticate with a token of the ServiceAccount
+// named by the kubeconfig Secret, which is regenerated at the rotation interval.
+func (r *ReconcileArgoCD) reconcileDiscoveredClusterSecrets(cr *argoprojv1a1.ArgoCD) error {
+	desired := make(map[string]bool)
+	rotations := []argoprojv1a1.ArgoCDClusterTokenRotationStatus{}
+	if cr.Spec.ClusterDiscovery.SecretSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(cr.Spec.ClusterDiscovery.SecretSelector)
+		if err != nil {
+			return fmt.Errorf("invalid cluster discovery secret selector: %w", err)
+		}
+
+		sources := &corev1.SecretList{}
+		if err := r.client.List(context.TODO(), sources, client.InNamespace(cr.Namespace), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+			return err
+		}
+
+		for _, source := range sources.Items {
+			kubeconfig, ok := source.Data[common.ArgoCDKeyClusterDiscoveryKubeconfig]
+			if !ok {
+				continue // Not a kubeconfig Secret, e.g. a cluster Secret matching the selector
+			}
+
+			secret := argoutil.NewSecretWithName(cr.ObjectMeta, getDiscoveredClusterSecretName(source.Name, cr))
+			secret.Labels[common.ArgoCDKeyComponent] = clusterDiscoveryComponent
+			secret.Labels[common.ArgoCDSecretTypeLabel] = common.ArgoCDSecretTypeCluster
+			desired[secret.Name] = true
+
+			data, err := getDiscoveredClusterData(source.Name, kubeconfig)
+			if err != nil {
+				// Keep any existing cluster Secret, so that a bad update of the kubeconfig does not remove the cluster.
+				log.Error(err, fmt.Sprintf("unable to register cluster from secret %s", source.Name))
+				continue
+			}
+
+			rotation, err := r.newDiscoveredClusterTokenRotation(cr, &source)
+			if err == nil && rotation != nil {
+				// The new token is written to the cluster Secret before the previous token is revoked.
+				propagated := false
+				var status *argoutil.TokenRotationStatus
+				status, err = rotation.Reconcile(func(token *corev1.Secret) error {
+					tokenData, err := setDiscoveredClusterToken(data, token.Data[corev1.ServiceAccountTokenKey])
+					if err != nil {
+						return err
+					}
+					propagated = true
+					return r.writeDiscoveredClusterSecret(cr, source.Name, secret, tokenData)
+				})
+				if err == nil {
+					rotations = append(rotations, argoprojv1a1.ArgoCDClusterTokenRotationStatus{
+						LastRotation: status.Last,
+						NextRotation: status.Next,
+						Revocation:   status.Revocation,
+						SecretName:   source.Name,
+					})
+					if propagated {
+						continue
+					}
+				}
+			}
+			if err != nil {
+				// Keep the cluster Secret with the current token, the rotation is retried at the next reconcile.
+				log.Error(err, fmt.Sprintf("unable to rotate the token of the cluster from secret %s", source.Name))
+				if previous := getDiscoveredClusterTokenRotationStatus(cr, source.Name); previous != nil {
+					rotations = append(rotations, *previous)
+				}
+				if argoutil.IsObjectFound(r.client, cr.Namespace, secret.Name, secret.DeepCopy()) {
+					continue
+				}
+			}
+
+			if err := r.writeDiscoveredClusterSecret(cr, source.Name, secret, data); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(rotations) == 0 {
+		rotations = nil
+	}
+	if !reflect.DeepEqual(cr.Status.ClusterTokenRotations, rotations) {
+		cr.Status.ClusterTokenRotations = rotations
+		if err := r.updateArgoCDStatus(cr); err != nil {
+			return err
+		}
+	}
+
+	secrets := &corev1.SecretList{}
+	opts := []client.ListOption{
+		client.InNamespace(cr.Namespace),
+		client.MatchingLabels{
+			common.ArgoCDKeyComponent:    clusterDiscoveryComponent,
+			common.ArgoCDKeyManagedBy:    cr.Name,
+			common.ArgoCDKeyPartOf:       common.ArgoCDAppName,
+			common.ArgoCDSecretTypeLabel: common.ArgoCDSecretTypeCluster,
+		},
+	}
+	if err := r.client.List(context.TODO(), secrets, opts...); err != nil {
+		return err
+	}
+	for i := range secrets.Items {
+		if desired[secrets.Items[i].Name] {
+			continue
+		}
+		log.Info(fmt.Sprintf("removing cluster secret %s of a secret that is no longer selected", secrets.Items[i].Name))
+		if err := r.client.Delete(context.TODO(), &secrets.Items[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,175 @@
+package argocd
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+
+	argoprojv1alpha1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
+	"github.com/argoproj-labs/argocd-operator/pkg/controller/argoutil"
+)
+
+const testKubeconfig = `apiVersion: v1
+kind: Config
+current-context: prod
+clusters:
+- name: prod
+  cluster:
+    server: https://prod.example.com
+    certificate-authority-data: Y2E=
+contexts:
+- name: prod
+  context:
+    cluster: prod
+    user: admin
+users:
+- name: admin
+  user:
+    token: secret-token
+`
+
+func makeTestKubeconfigSecret(name string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: testNamespace,
+			Labels:    map[string]string{"example.com/cluster": "true"},
+		},
+		Data: map[string][]byte{common.ArgoCDKeyClusterDiscoveryKubeconfig: []byte(testKubeconfig)},
+	}
+}
+
+func TestReconcileArgoCD_reconcileDiscoveredClusterSecrets(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.ClusterDiscovery.SecretSelector = &metav1.LabelSelector{
+			MatchLabels: map[string]string{"example.com/cluster": "true"},
+		}
+	})
+	source := makeTestKubeconfigSecret("prod")
+	r := makeTestReconciler(t, a, source)
+
+	assert.NilError(t, r.reconcileDiscoveredClusterSecrets(a))
+
+	secret := &corev1.Secret{}
+	key := types.NamespacedName{Name: "argocd-discovered-prod", Namespace: testNamespace}
+	assert.NilError(t, r.client.Get(context.TODO(), key, secret))
+	assert.Equal(t, secret.Labels[common.ArgoCDSecretTypeLabel], common.ArgoCDSecretTypeCluster)
+	assert.Equal(t, string(secret.Data["name"]), "prod")
+	assert.Equal(t, string(secret.Data["server"]), "https://prod.example.com")
+
+	config := discoveredClusterConfig{}
+	assert.NilError(t, json.Unmarshal(secret.Data["config"], &config))
+	assert.Equal(t, config.BearerToken, "secret-token")
+	assert.Equal(t, string(config.TLSClientConfig.CAData), "ca")
+
+	// An invalid kubeconfig keeps the registered cluster.
+	source.Data[common.ArgoCDKeyClusterDiscoveryKubeconfig] = []byte("clusters: [")
+	assert.NilError(t, r.client.Update(context.TODO(), source))
+	assert.NilError(t, r.reconcileDiscoveredClusterSecrets(a))
+	assert.NilError(t, r.client.Get(context.TODO(), key, secret))
+
+	// Removing the source Secret removes the cluster.
+	assert.NilError(t, r.client.Delete(context.TODO(), source))
+	assert.NilError(t, r.reconcileDiscoveredClusterSecrets(a))
+	err := r.client.Get(context.TODO(), key, secret)
+	assert.Assert(t, errors.IsNotFound(err))
+}
+
+func TestReconcileArgoCD_reconcileDiscoveredClusterSecrets_notSelected(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD()
+	r := makeTestReconciler(t, a, makeTestKubeconfigSecret("prod"))
+
+	assert.NilError(t, r.reconcileDiscoveredClusterSecrets(a))
+
+	secret := &corev1.Secret{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-discovered-prod", Namespace: testNamespace}, secret)
+	assert.Assert(t, errors.IsNotFound(err))
+}
+
+func TestReconcileArgoCD_reconcileDiscoveredClusterSecrets_tokenRotation(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.ClusterDiscovery.SecretSelector = &metav1.LabelSelector{
+			MatchLabels: map[string]string{"example.com/cluster": "true"},
+		}
+		a.Spec.ClusterDiscovery.TokenRotationInterval = &metav1.Duration{Duration: time.Hour}
+	})
+	source := makeTestKubeconfigSecret("prod")
+	source.Annotations = map[string]string{common.ArgoCDClusterServiceAccountAnnotation: "kube-system/argocd-manager"}
+	r := makeTestReconciler(t, a, source)
+	remote := fake.NewFakeClientWithScheme(r.scheme)
+	r.remoteClient = func(kubeconfig []byte) (client.Client, error) {
+		return remote, nil
+	}
+
+	getBearerToken := func() string {
+		secret := &corev1.Secret{}
+		key := types.NamespacedName{Name: "argocd-discovered-prod", Namespace: testNamespace}
+		assert.NilError(t, r.client.Get(context.TODO(), key, secret))
+		config := discoveredClusterConfig{}
+		assert.NilError(t, json.Unmarshal(secret.Data["config"], &config))
+		return config.BearerToken
+	}
+	populateToken := func(name, token string, created time.Time) {
+		secret := &corev1.Secret{}
+		assert.NilError(t, remote.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: "kube-system"}, secret))
+		assert.Equal(t, secret.Annotations[corev1.ServiceAccountNameKey], "argocd-manager")
+		secret.CreationTimestamp = metav1.NewTime(created)
+		secret.Data = map[string][]byte{corev1.ServiceAccountTokenKey: []byte(token)}
+		assert.NilError(t, remote.Update(context.TODO(), secret))
+	}
+	listTokens := func() []corev1.Secret {
+		list := &corev1.SecretList{}
+		assert.NilError(t, remote.List(context.TODO(), list, client.InNamespace("kube-system")))
+		return list.Items
+	}
+
+	// The kubeconfig credentials are used until the token has been populated.
+	assert.NilError(t, r.reconcileDiscoveredClusterSecrets(a))
+	assert.Equal(t, getBearerToken(), "secret-token")
+	assert.Equal(t, len(listTokens()), 1)
+	assert.Equal(t, len(a.Status.ClusterTokenRotations), 1)
+	assert.Equal(t, a.Status.ClusterTokenRotations[0].SecretName, "prod")
+
+	populateToken("argocd-cluster-token", "token-1", time.Now().Add(-2*time.Hour))
+	assert.NilError(t, r.reconcileDiscoveredClusterSecrets(a))
+	assert.Equal(t, getBearerToken(), "token-1")
+
+	// The rotation creates a new token, the previous token is kept until the new one has been propagated.
+	tokens := listTokens()
+	assert.Equal(t, len(tokens), 2)
+	next := tokens[0].Name
+	if next == "argocd-cluster-token" {
+		next = tokens[1].Name
+	}
+	populateToken(next, "token-2", time.Now())
+	assert.NilError(t, r.reconcileDiscoveredClusterSecrets(a))
+	assert.Equal(t, getBearerToken(), "token-2")
+	assert.Equal(t, len(listTokens()), 2)
+	assert.Assert(t, a.Status.ClusterTokenRotations[0].Revocation != nil)
+	assert.Assert(t, r.getRequeueDelay(a) <= argoutil.DefaultTokenRevocationDelay)
+
+	// The previous token is revoked once the revocation delay has elapsed.
+	secret := &corev1.Secret{}
+	assert.NilError(t, remote.Get(context.TODO(), types.NamespacedName{Name: next, Namespace: "kube-system"}, secret))
+	secret.Annotations[common.ArgoCDTokenPropagatedAnnotation] = time.Now().Add(-10 * time.Minute).UTC().Format(time.RFC3339)
+	assert.NilError(t, remote.Update(context.TODO(), secret))
+	assert.NilError(t, r.reconcileDiscoveredClusterSecrets(a))
+	tokens = listTokens()
+	assert.Equal(t, len(tokens), 1)
+	assert.Equal(t, tokens[0].Name, next)
+	assert.Assert(t, a.Status.ClusterTokenRotations[0].Revocation == nil)
+}
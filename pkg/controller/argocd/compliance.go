@@ -0,0 +1,363 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocd
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1b1 "k8s.io/api/batch/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
+	"github.com/argoproj-labs/argocd-operator/pkg/controller/argoutil"
+)
+
+const (
+	// seccompPodAnnotation is the annotation with the seccomp profile of all containers of a pod.
+	seccompPodAnnotation = "seccomp.security.alpha.kubernetes.io/pod"
+
+	// seccompContainerAnnotationPrefix is the prefix of the annotation with the seccomp profile of a container.
+	seccompContainerAnnotationPrefix = "container.seccomp.security.alpha.kubernetes.io/"
+)
+
+// restrictedSafeSysctls are the sysctls allowed by the Pod Security Standards.
+var restrictedSafeSysctls = []string{
+	"kernel.shm_rmid_forced",
+	"net.ipv4.ip_local_port_range",
+	"net.ipv4.ip_unprivileged_port_start",
+	"net.ipv4.ping_group_range",
+	"net.ipv4.tcp_syncookies",
+}
+
+// podSecurityWorkloadKinds are the kinds of the workloads whose pods are verified by the compliance mode.
+var podSecurityWorkloadKinds = map[string]func() runtime.Object{
+	"CronJob":     func() runtime.Object { return &batchv1b1.CronJob{} },
+	"Deployment":  func() runtime.Object { return &appsv1.Deployment{} },
+	"Job":         func() runtime.Object { return &batchv1.Job{} },
+	"StatefulSet": func() runtime.Object { return &appsv1.StatefulSet{} },
+}
+
+// restrictedSELinuxTypes are the SELinux types allowed by the Pod Security Standards.
+var restrictedSELinuxTypes = []string{"", "container_t", "container_init_t", "container_kvm_t"}
+
+// isRestrictedVolume will return true if the given volume is of a type allowed by the restricted Pod Security
+// Standard.
+func isRestrictedVolume(volume corev1.Volume) bool {
+	allowed := corev1.VolumeSource{
+		ConfigMap:             volume.ConfigMap,
+		CSI:                   volume.CSI,
+		DownwardAPI:           volume.DownwardAPI,
+		EmptyDir:              volume.EmptyDir,
+		PersistentVolumeClaim: volume.PersistentVolumeClaim,
+		Projected:             volume.Projected,
+		Secret:                volume.Secret,
+	}
+	return reflect.DeepEqual(allowed, volume.VolumeSource)
+}
+
+// isRestrictedSeccompProfile will return true if the given seccomp profile annotation value is allowed by the
+// restricted Pod Security Standard.
+func isRestrictedSeccompProfile(profile string) bool {
+	return profile == "runtime/default" || profile == "docker/default" || strings.HasPrefix(profile, "localhost/")
+}
+
+// isRestrictedSeccompProfileType will return true if the given seccompProfile type is allowed by the restricted Pod
+// Security Standard.
+func isRestrictedSeccompProfileType(profileType string) bool {
+	return profileType == "RuntimeDefault" || profileType == "Localhost"
+}
+
+// getSeccompProfileTypes will return the seccompProfile types of the pod template of the given workload, by container
+// name, with the type of the pod under the empty name. The seccompProfile fields are newer than the Kubernetes API the
+// operator is built with, so they are read from the unstructured workload.
+func getSeccompProfileTypes(workload *unstructured.Unstructured) map[string]string {
+	path := []string{"spec", "template", "spec"}
+	if workload.GetKind() == "CronJob" {
+		path = []string{"spec", "jobTemplate", "spec", "template", "spec"}
+	}
+	spec, _, _ := unstructured.NestedMap(workload.Object, path...)
+
+	profileTypes := make(map[string]string)
+	if profileType, _, _ := unstructured.NestedString(spec, "securityContext", "seccompProfile", "type"); profileType != "" {
+		profileTypes[""] = profileType
+	}
+	for _, field := range []string{"initContainers", "containers"} {
+		containers, _, _ := unstructured.NestedSlice(spec, field)
+		for _, c := range containers {
+			container, ok := c.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			name, _, _ := unstructured.NestedString(container, "name")
+			if profileType, _, _ := unstructured.NestedString(container, "securityContext", "seccompProfile", "type"); profileType != "" {
+				profileTypes[name] = profileType
+			}
+		}
+	}
+	return profileTypes
+}
+
+// getWorkloadSeccompProfileTypes will return the seccompProfile types of the pod template of the given existing
+// workload, or nil when the workload does not exist yet.
+func (r *ReconcileArgoCD) getWorkloadSeccompProfileTypes(namespace string, workload string) (map[string]string, error) {
+	parts := strings.SplitN(workload, "/", 2)
+	newObject, ok := podSecurityWorkloadKinds[parts[0]]
+	if !ok || len(parts) != 2 {
+		return nil, nil
+	}
+	gvks, _, err := r.scheme.ObjectKinds(newObject())
+	if err != nil {
+		return nil, err
+	}
+
+	u := &unstructured.Unstructured{}
+	u.SetGroupVersionKind(gvks[0])
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: parts[1]}, u); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return getSeccompProfileTypes(u), nil
+}
+
+// isRestrictedContainerSeccompProfile will return true if the seccomp profile of the given container is allowed by the
+// restricted Pod Security Standard. The seccompProfile field of the container takes precedence over its annotation,
+// which takes precedence over the seccompProfile field of the pod and then the annotation of the pod.
+func isRestrictedContainerSeccompProfile(template *corev1.PodTemplateSpec, profileTypes map[string]string, container string) bool {
+	if profileType, ok := profileTypes[container]; ok {
+		return isRestrictedSeccompProfileType(profileType)
+	}
+	if profile, ok := template.Annotations[seccompContainerAnnotationPrefix+container]; ok {
+		return isRestrictedSeccompProfile(profile)
+	}
+	if profileType, ok := profileTypes[""]; ok {
+		return isRestrictedSeccompProfileType(profileType)
+	}
+	return isRestrictedSeccompProfile(template.Annotations[seccompPodAnnotation])
+}
+
+// getRestrictedSELinuxViolations will return the violations of the given SELinux options of the given subject.
+func getRestrictedSELinuxViolations(options *corev1.SELinuxOptions, subject string) []string {
+	if options == nil {
+		return nil
+	}
+	violations := make([]string, 0)
+	if !containsString(restrictedSELinuxTypes, options.Type) {
+		violations = append(violations, fmt.Sprintf("seLinuxOptions: %s must not set SELinux type %q", subject, options.Type))
+	}
+	if options.User != "" || options.Role != "" {
+		violations = append(violations, fmt.Sprintf("seLinuxOptions: %s must not set the SELinux user or role", subject))
+	}
+	return violations
+}
+
+// getRestrictedPodSecurityViolations will return the checks of the restricted Pod Security Standard that the pods
+// of the given template fail, with the given seccompProfile types of the pod and its containers.
+func getRestrictedPodSecurityViolations(template *corev1.PodTemplateSpec, profileTypes map[string]string) []string {
+	spec := &template.Spec
+	podSC := spec.SecurityContext
+	if podSC == nil {
+		podSC = &corev1.PodSecurityContext{}
+	}
+
+	violations := make([]string, 0)
+	if spec.HostNetwork || spec.HostPID || spec.HostIPC {
+		violations = append(violations, "host namespaces: pod must not set hostNetwork, hostPID or hostIPC")
+	}
+	for _, volume := range spec.Volumes {
+		if !isRestrictedVolume(volume) {
+			violations = append(violations, fmt.Sprintf("restricted volume types: volume %q must be a configMap, csi, downwardAPI, emptyDir, persistentVolumeClaim, projected or secret volume", volume.Name))
+		}
+	}
+	for _, sysctl := range podSC.Sysctls {
+		if !containsString(restrictedSafeSysctls, sysctl.Name) {
+			violations = append(violations, fmt.Sprintf("sysctls: pod must not set sysctl %q", sysctl.Name))
+		}
+	}
+	if podSC.RunAsUser != nil && *podSC.RunAsUser == 0 {
+		violations = append(violations, "runAsUser: pod must not run as user 0")
+	}
+	violations = append(violations, getRestrictedSELinuxViolations(podSC.SELinuxOptions, "pod")...)
+
+	containers := append(append([]corev1.Container{}, spec.InitContainers...), spec.Containers...)
+	for _, c := range containers {
+		container := fmt.Sprintf("container %q", c.Name)
+		sc := c.SecurityContext
+		if sc == nil {
+			sc = &corev1.SecurityContext{}
+		}
+
+		if sc.Privileged != nil && *sc.Privileged {
+			violations = append(violations, fmt.Sprintf("privileged: %s must not be privileged", container))
+		}
+		if sc.AllowPrivilegeEscalation == nil || *sc.AllowPrivilegeEscalation {
+			violations = append(violations, fmt.Sprintf("allowPrivilegeEscalation: %s must set securityContext.allowPrivilegeEscalation=false", container))
+		}
+
+		dropsAll := false
+		if sc.Capabilities != nil {
+			for _, capability := range sc.Capabilities.Drop {
+				dropsAll = dropsAll || capability == "ALL"
+			}
+			for _, capability := range sc.Capabilities.Add {
+				if capability != "NET_BIND_SERVICE" {
+					violations = append(violations, fmt.Sprintf("capabilities: %s must not add capability %s", container, capability))
+				}
+			}
+		}
+		if !dropsAll {
+			violations = append(violations, fmt.Sprintf("capabilities: %s must set securityContext.capabilities.drop=[\"ALL\"]", container))
+		}
+
+		runAsNonRoot := podSC.RunAsNonRoot
+		if sc.RunAsNonRoot != nil {
+			runAsNonRoot = sc.RunAsNonRoot
+		}
+		if runAsNonRoot == nil || !*runAsNonRoot {
+			violations = append(violations, fmt.Sprintf("runAsNonRoot: %s must set securityContext.runAsNonRoot=true", container))
+		}
+		if sc.RunAsUser != nil && *sc.RunAsUser == 0 {
+			violations = append(violations, fmt.Sprintf("runAsUser: %s must not run as user 0", container))
+		}
+
+		if !isRestrictedContainerSeccompProfile(template, profileTypes, c.Name) {
+			violations = append(violations, fmt.Sprintf("seccompProfile: %s must set securityContext.seccompProfile.type to RuntimeDefault or Localhost", container))
+		}
+
+		for _, port := range c.Ports {
+			if port.HostPort != 0 {
+				violations = append(violations, fmt.Sprintf("hostPort: %s must not use host port %d", container, port.HostPort))
+			}
+		}
+		if sc.ProcMount != nil && *sc.ProcMount != corev1.DefaultProcMount {
+			violations = append(violations, fmt.Sprintf("procMount: %s must use the default proc mount", container))
+		}
+		violations = append(violations, getRestrictedSELinuxViolations(sc.SELinuxOptions, container)...)
+	}
+	return violations
+}
+
+// setPodSecurityViolations will set the given violations of the given workload in the Status of the given ArgoCD,
+// returning true if the Status was changed. The workload is removed from the Status when it has no violations.
+func setPodSecurityViolations(cr *argoprojv1a1.ArgoCD, workload string, violations []string) bool {
+	for i, status := range cr.Status.PodSecurityViolations {
+		if status.Workload != workload {
+			continue
+		}
+		if len(violations) == 0 {
+			cr.Status.PodSecurityViolations = append(cr.Status.PodSecurityViolations[:i], cr.Status.PodSecurityViolations[i+1:]...)
+			return true
+		}
+		if reflect.DeepEqual(status.Violations, violations) {
+			return false
+		}
+		cr.Status.PodSecurityViolations[i].Violations = violations
+		return true
+	}
+
+	if len(violations) == 0 {
+		return false
+	}
+	cr.Status.PodSecurityViolations = append(cr.Status.PodSecurityViolations, argoprojv1a1.ArgoCDPodSecurityViolationStatus{
+		Workload:   workload,
+		Violations: violations,
+	})
+	return true
+}
+
+// getPodSecurityCompliantCondition will return the PodSecurityCompliant condition for the violations in the Status of
+// the given ArgoCD.
+func getPodSecurityCompliantCondition(cr *argoprojv1a1.ArgoCD) argoprojv1a1.ArgoCDCondition {
+	condition := argoprojv1a1.ArgoCDCondition{
+		Type:    argoprojv1a1.ArgoCDConditionTypePodSecurityCompliant,
+		Status:  corev1.ConditionTrue,
+		Reason:  "Compliant",
+		Message: fmt.Sprintf("the pods of all workloads comply with the %s Pod Security Standard", cr.Spec.ComplianceMode),
+	}
+	if len(cr.Status.PodSecurityViolations) > 0 {
+		workloads := make([]string, 0, len(cr.Status.PodSecurityViolations))
+		for _, status := range cr.Status.PodSecurityViolations {
+			workloads = append(workloads, status.Workload)
+		}
+		condition.Status = corev1.ConditionFalse
+		condition.Reason = "Violations"
+		condition.Message = fmt.Sprintf("the pods of the following workloads violate the %s Pod Security Standard and may be rejected at admission: %s",
+			cr.Spec.ComplianceMode, strings.Join(workloads, ", "))
+	}
+	return condition
+}
+
+// reconcilePodSecurityCompliance will verify the pod template of the given workload against the Pod Security Standard
+// of the compliance mode of the given ArgoCD, and report the violations in the Status, before the workload is created
+// or updated. A nil template removes the workload from the Status, for workloads that are not deployed. The
+// seccompProfile fields are read from the existing workload, as they can't be set on the given template.
+func (r *ReconcileArgoCD) reconcilePodSecurityCompliance(cr *argoprojv1a1.ArgoCD, workload string, template *corev1.PodTemplateSpec) error {
+	var violations []string
+	if cr.Spec.ComplianceMode == common.ArgoCDComplianceModeRestricted && template != nil {
+		profileTypes, err := r.getWorkloadSeccompProfileTypes(cr.Namespace, workload)
+		if err != nil {
+			return err
+		}
+		violations = getRestrictedPodSecurityViolations(template, profileTypes)
+	}
+
+	changed := setPodSecurityViolations(cr, workload, violations)
+	if cr.Spec.ComplianceMode == "" {
+		changed = removeArgoCDCondition(cr, argoprojv1a1.ArgoCDConditionTypePodSecurityCompliant) || changed
+	} else {
+		changed = setArgoCDCondition(cr, getPodSecurityCompliantCondition(cr)) || changed
+	}
+
+	if !changed {
+		return nil
+	}
+	if len(violations) > 0 {
+		log.Info(fmt.Sprintf("the pods of %s violate the %s pod security standard: %s", workload, cr.Spec.ComplianceMode, strings.Join(violations, "; ")))
+	}
+	return r.updateArgoCDStatus(cr)
+}
+
+// reconcileStatusPodSecurity will ensure that the PodSecurityViolations Status of the given ArgoCD only lists the
+// workloads that still exist, as the workloads of disabled components are removed without being verified.
+func (r *ReconcileArgoCD) reconcileStatusPodSecurity(cr *argoprojv1a1.ArgoCD) error {
+	violations := make([]argoprojv1a1.ArgoCDPodSecurityViolationStatus, 0)
+	for _, status := range cr.Status.PodSecurityViolations {
+		parts := strings.SplitN(status.Workload, "/", 2)
+		newObject, ok := podSecurityWorkloadKinds[parts[0]]
+		if ok && len(parts) == 2 && argoutil.IsObjectFound(r.client, cr.Namespace, parts[1], newObject()) {
+			violations = append(violations, status)
+		}
+	}
+	if len(violations) == len(cr.Status.PodSecurityViolations) {
+		return nil
+	}
+
+	cr.Status.PodSecurityViolations = violations
+	if cr.Spec.ComplianceMode != "" {
+		setArgoCDCondition(cr, getPodSecurityCompliantCondition(cr))
+	}
+	return r.updateArgoCDStatus(cr)
+}
@@ -0,0 +1,225 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocd
+
+import (
+	"context"
+	"testing"
+
+	"gotest.tools/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+
+	argoprojv1alpha1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
+)
+
+func makeRestrictedPodTemplate() *corev1.PodTemplateSpec {
+	return &corev1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{seccompPodAnnotation: "runtime/default"},
+		},
+		Spec: corev1.PodSpec{
+			SecurityContext: &corev1.PodSecurityContext{RunAsNonRoot: boolPtr(true)},
+			Containers: []corev1.Container{{
+				Name: "app",
+				SecurityContext: &corev1.SecurityContext{
+					AllowPrivilegeEscalation: boolPtr(false),
+					Capabilities:             &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}},
+				},
+			}},
+			Volumes: []corev1.Volume{{
+				Name:         "tmp",
+				VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+			}},
+		},
+	}
+}
+
+func TestGetRestrictedPodSecurityViolations(t *testing.T) {
+	tests := []struct {
+		name         string
+		modify       func(*corev1.PodTemplateSpec)
+		profileTypes map[string]string
+		want         []string
+	}{
+		{
+			name:   "restricted pod",
+			modify: func(*corev1.PodTemplateSpec) {},
+			want:   []string{},
+		},
+		{
+			name: "host path volume and host network",
+			modify: func(p *corev1.PodTemplateSpec) {
+				p.Spec.HostNetwork = true
+				p.Spec.Volumes = append(p.Spec.Volumes, corev1.Volume{
+					Name:         "host",
+					VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: "/var/run"}},
+				})
+			},
+			want: []string{
+				"host namespaces: pod must not set hostNetwork, hostPID or hostIPC",
+				`restricted volume types: volume "host" must be a configMap, csi, downwardAPI, emptyDir, persistentVolumeClaim, projected or secret volume`,
+			},
+		},
+		{
+			name: "privileged root container",
+			modify: func(p *corev1.PodTemplateSpec) {
+				p.Spec.Containers[0].SecurityContext = &corev1.SecurityContext{
+					Privileged:   boolPtr(true),
+					RunAsNonRoot: boolPtr(false),
+					Capabilities: &corev1.Capabilities{Add: []corev1.Capability{"NET_ADMIN"}},
+				}
+				p.Annotations[seccompContainerAnnotationPrefix+"app"] = "unconfined"
+			},
+			want: []string{
+				`privileged: container "app" must not be privileged`,
+				`allowPrivilegeEscalation: container "app" must set securityContext.allowPrivilegeEscalation=false`,
+				`capabilities: container "app" must not add capability NET_ADMIN`,
+				`capabilities: container "app" must set securityContext.capabilities.drop=["ALL"]`,
+				`runAsNonRoot: container "app" must set securityContext.runAsNonRoot=true`,
+				`seccompProfile: container "app" must set securityContext.seccompProfile.type to RuntimeDefault or Localhost`,
+			},
+		},
+		{
+			name: "seccompProfile field of the pod",
+			modify: func(p *corev1.PodTemplateSpec) {
+				delete(p.Annotations, seccompPodAnnotation)
+			},
+			profileTypes: map[string]string{"": "RuntimeDefault"},
+			want:         []string{},
+		},
+		{
+			name:         "unconfined seccompProfile field of the container",
+			modify:       func(*corev1.PodTemplateSpec) {},
+			profileTypes: map[string]string{"": "RuntimeDefault", "app": "Unconfined"},
+			want: []string{
+				`seccompProfile: container "app" must set securityContext.seccompProfile.type to RuntimeDefault or Localhost`,
+			},
+		},
+		{
+			name: "no seccomp profile",
+			modify: func(p *corev1.PodTemplateSpec) {
+				delete(p.Annotations, seccompPodAnnotation)
+			},
+			want: []string{
+				`seccompProfile: container "app" must set securityContext.seccompProfile.type to RuntimeDefault or Localhost`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			template := makeRestrictedPodTemplate()
+			tt.modify(template)
+			assert.DeepEqual(t, getRestrictedPodSecurityViolations(template, tt.profileTypes), tt.want)
+		})
+	}
+}
+
+func TestGetSeccompProfileTypes(t *testing.T) {
+	deploy := &unstructured.Unstructured{Object: map[string]interface{}{
+		"kind": "Deployment",
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"securityContext": map[string]interface{}{
+						"seccompProfile": map[string]interface{}{"type": "RuntimeDefault"},
+					},
+					"initContainers": []interface{}{
+						map[string]interface{}{"name": "copyutil"},
+					},
+					"containers": []interface{}{
+						map[string]interface{}{
+							"name": "app",
+							"securityContext": map[string]interface{}{
+								"seccompProfile": map[string]interface{}{"type": "Localhost"},
+							},
+						},
+					},
+				},
+			},
+		},
+	}}
+	assert.DeepEqual(t, getSeccompProfileTypes(deploy), map[string]string{"": "RuntimeDefault", "app": "Localhost"})
+
+	cronJob := &unstructured.Unstructured{Object: map[string]interface{}{
+		"kind": "CronJob",
+		"spec": map[string]interface{}{
+			"jobTemplate": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"template": map[string]interface{}{
+						"spec": map[string]interface{}{
+							"securityContext": map[string]interface{}{
+								"seccompProfile": map[string]interface{}{"type": "Unconfined"},
+							},
+						},
+					},
+				},
+			},
+		},
+	}}
+	assert.DeepEqual(t, getSeccompProfileTypes(cronJob), map[string]string{"": "Unconfined"})
+}
+
+func TestReconcileArgoCD_reconcileServerDeployment_withRestrictedComplianceMode(t *testing.T) {
+	restoreEnv(t)
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.ComplianceMode = common.ArgoCDComplianceModeRestricted
+	})
+	r := makeTestReconciler(t, a)
+
+	assert.NilError(t, r.reconcileServerDeployment(a))
+
+	// The violations are reported, the Deployment is still created.
+	deploy := &appsv1.Deployment{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-server", Namespace: testNamespace}, deploy))
+
+	cr := &argoprojv1alpha1.ArgoCD{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: testArgoCDName, Namespace: testNamespace}, cr))
+	assert.Equal(t, len(cr.Status.PodSecurityViolations), 1)
+	assert.Equal(t, cr.Status.PodSecurityViolations[0].Workload, "Deployment/argocd-server")
+	assert.Assert(t, len(cr.Status.PodSecurityViolations[0].Violations) > 0)
+	assert.Assert(t, !isArgoCDConditionTrue(cr, argoprojv1alpha1.ArgoCDConditionTypePodSecurityCompliant))
+
+	// Disabling the compliance mode clears the violations and the condition.
+	a.Spec.ComplianceMode = ""
+	assert.NilError(t, r.reconcileServerDeployment(a))
+
+	cr = &argoprojv1alpha1.ArgoCD{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: testArgoCDName, Namespace: testNamespace}, cr))
+	assert.Equal(t, len(cr.Status.PodSecurityViolations), 0)
+	for _, c := range cr.Status.Conditions {
+		assert.Assert(t, c.Type != argoprojv1alpha1.ArgoCDConditionTypePodSecurityCompliant)
+	}
+}
+
+func TestReconcileArgoCD_reconcileStatusPodSecurity(t *testing.T) {
+	restoreEnv(t)
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.ComplianceMode = common.ArgoCDComplianceModeRestricted
+		a.Status.PodSecurityViolations = []argoprojv1alpha1.ArgoCDPodSecurityViolationStatus{
+			{Workload: "Deployment/argocd-dex-server", Violations: []string{"privileged"}},
+		}
+	})
+	r := makeTestReconciler(t, a)
+
+	assert.NilError(t, r.reconcileStatusPodSecurity(a))
+	assert.Equal(t, len(a.Status.PodSecurityViolations), 0)
+	assert.Assert(t, isArgoCDConditionTrue(a, argoprojv1alpha1.ArgoCDConditionTypePodSecurityCompliant))
+}
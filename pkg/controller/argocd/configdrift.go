@@ -0,0 +1,122 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocd
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
+)
+
+// configDriftFields are the fields of the ArgoCD spec that manage the keys of the Argo CD ConfigMaps.
+var configDriftFields = map[string]map[string]string{
+	common.ArgoCDConfigMapName: {
+		common.ArgoCDKeyAdminEnabled:                "spec.disableAdmin",
+		common.ArgoCDKeyApplicationInstanceLabelKey: "spec.applicationInstanceLabelKey",
+		common.ArgoCDKeyConfigManagementPlugins:     "spec.configManagementPlugins",
+		common.ArgoCDKeyDexConfig:                   "spec.sso.dex",
+		common.ArgoCDKeyExtensionConfig:             "spec.server.proxyExtension",
+		common.ArgoCDKeyGAAnonymizeUsers:            "spec.gaAnonymizeUsers",
+		common.ArgoCDKeyGATrackingID:                "spec.gaTrackingID",
+		common.ArgoCDKeyHelpChatText:                "spec.helpChatText",
+		common.ArgoCDKeyHelpChatURL:                 "spec.helpChatURL",
+		common.ArgoCDKeyKustomizeBuildOptions:       "spec.kustomizeBuildOptions",
+		common.ArgoCDKeyOIDCConfig:                  "spec.oidcConfig",
+		common.ArgoCDKeyOIDCInsecureSkipVerify:      "spec.oidcInsecureSkipVerify",
+		common.ArgoCDKeyRepositoryCredentials:       "spec.repositoryCredentials",
+		common.ArgoCDKeyResourceCustomizations:      "spec.resourceCustomizations",
+		common.ArgoCDKeyResourceExclusions:          "spec.resourceExclusions",
+		common.ArgoCDKeyServerURL:                   "spec.server.host",
+		common.ArgoCDKeyStatusBadgeEnabled:          "spec.statusBadgeEnabled",
+		common.ArgoCDKeyUsersAnonymousEnabled:       "spec.usersAnonymousEnabled",
+		common.ArgoCDKeyUsersSessionDuration:        "spec.server.session.duration",
+	},
+	common.ArgoCDRBACConfigMapName: {
+		common.ArgoCDKeyRBACPolicyCSV:     "spec.rbac.policy",
+		common.ArgoCDKeyRBACPolicyDefault: "spec.rbac.defaultPolicy",
+		common.ArgoCDKeyRBACScopes:        "spec.rbac.scopes",
+	},
+}
+
+// getChangedConfigMapKeys will return the sorted keys that were added, removed or changed between the actual and the
+// desired data of a ConfigMap.
+func getChangedConfigMapKeys(actual, desired map[string]string) []string {
+	keys := make([]string, 0)
+	for key, value := range desired {
+		if actualValue, ok := actual[key]; !ok || actualValue != value {
+			keys = append(keys, key)
+		}
+	}
+	for key := range actual {
+		if _, ok := desired[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// setAppliedGeneration will annotate the given ConfigMap with the generation of the given ArgoCD it is written for.
+func setAppliedGeneration(cm *corev1.ConfigMap, cr *argoprojv1a1.ArgoCD) {
+	if cm.Annotations == nil {
+		cm.Annotations = make(map[string]string)
+	}
+	cm.Annotations[common.AnnotationAppliedGeneration] = strconv.FormatInt(cr.Generation, 10)
+}
+
+// reconcileConfigDrift will record the keys the operator changed between the existing and the given ConfigMap in the
+// ConfigDrift status of the given ArgoCD. Keys are only reported when the ArgoCD spec has not changed since the
+// operator last wrote the existing ConfigMap, as the overwritten values then hold edits made outside of the operator.
+func (r *ReconcileArgoCD) reconcileConfigDrift(cr *argoprojv1a1.ArgoCD, existing *corev1.ConfigMap, cm *corev1.ConfigMap) error {
+	if existing.Annotations[common.AnnotationAppliedGeneration] != strconv.FormatInt(cr.Generation, 10) {
+		return nil // Spec changed or ConfigMap not written by this operator version, nothing is drift.
+	}
+
+	keys := getChangedConfigMapKeys(existing.Data, cm.Data)
+	if len(keys) == 0 {
+		return nil
+	}
+
+	now := metav1.Now()
+	for _, key := range keys {
+		log.Info(fmt.Sprintf("overwrote key %s of configmap %s that was changed outside of the operator", key, cm.Name))
+		found := false
+		for i := range cr.Status.ConfigDrift {
+			drift := &cr.Status.ConfigDrift[i]
+			if drift.ConfigMap == cm.Name && drift.Key == key {
+				drift.Overwrites++
+				drift.LastOverwriteTime = now
+				found = true
+				break
+			}
+		}
+		if !found {
+			cr.Status.ConfigDrift = append(cr.Status.ConfigDrift, argoprojv1a1.ArgoCDConfigDriftStatus{
+				ConfigMap:         cm.Name,
+				Key:               key,
+				Field:             configDriftFields[cm.Name][key],
+				Overwrites:        1,
+				LastOverwriteTime: now,
+			})
+		}
+	}
+	return r.updateArgoCDStatus(cr)
+}
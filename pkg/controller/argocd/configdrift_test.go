@@ -0,0 +1,120 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocd
+
+import (
+	"context"
+	"testing"
+
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	argoprojv1alpha1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
+)
+
+func editConfigMap(t *testing.T, r *ReconcileArgoCD, name, key, value string) {
+	t.Helper()
+	cm := &corev1.ConfigMap{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: testNamespace}, cm))
+	cm.Data[key] = value
+	assert.NilError(t, r.client.Update(context.TODO(), cm))
+}
+
+func getConfigDrift(t *testing.T, r *ReconcileArgoCD) []argoprojv1alpha1.ArgoCDConfigDriftStatus {
+	t.Helper()
+	a := &argoprojv1alpha1.ArgoCD{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: testArgoCDName, Namespace: testNamespace}, a))
+	return a.Status.ConfigDrift
+}
+
+func TestReconcileArgoCD_reconcileArgoConfigMap_withManualEdit(t *testing.T) {
+	restoreEnv(t)
+	a := makeTestArgoCD()
+	r := makeTestReconciler(t, a)
+	assert.NilError(t, r.reconcileArgoConfigMap(a))
+
+	editConfigMap(t, r, common.ArgoCDConfigMapName, common.ArgoCDKeyGATrackingID, "UA-12345")
+	assert.NilError(t, r.reconcileArgoConfigMap(a))
+
+	drift := getConfigDrift(t, r)
+	assert.Equal(t, len(drift), 1)
+	assert.Equal(t, drift[0].ConfigMap, common.ArgoCDConfigMapName)
+	assert.Equal(t, drift[0].Key, common.ArgoCDKeyGATrackingID)
+	assert.Equal(t, drift[0].Field, "spec.gaTrackingID")
+	assert.Equal(t, drift[0].Overwrites, int32(1))
+
+	editConfigMap(t, r, common.ArgoCDConfigMapName, common.ArgoCDKeyGATrackingID, "UA-12345")
+	assert.NilError(t, r.reconcileArgoConfigMap(a))
+
+	drift = getConfigDrift(t, r)
+	assert.Equal(t, len(drift), 1)
+	assert.Equal(t, drift[0].Overwrites, int32(2))
+
+	cm := &corev1.ConfigMap{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{
+		Name:      common.ArgoCDConfigMapName,
+		Namespace: testNamespace,
+	}, cm))
+	assert.Equal(t, cm.Data[common.ArgoCDKeyGATrackingID], common.ArgoCDDefaultGATrackingID)
+}
+
+func TestReconcileArgoCD_reconcileArgoConfigMap_withSpecChange(t *testing.T) {
+	restoreEnv(t)
+	a := makeTestArgoCD()
+	r := makeTestReconciler(t, a)
+	assert.NilError(t, r.reconcileArgoConfigMap(a))
+
+	a.Generation++
+	a.Spec.GATrackingID = "UA-12345"
+	a.Spec.HelpChatText = "Ask the platform team"
+	assert.NilError(t, r.reconcileArgoConfigMap(a))
+
+	assert.Equal(t, len(getConfigDrift(t, r)), 0)
+
+	cm := &corev1.ConfigMap{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{
+		Name:      common.ArgoCDConfigMapName,
+		Namespace: testNamespace,
+	}, cm))
+	assert.Equal(t, cm.Annotations[common.AnnotationAppliedGeneration], "1")
+}
+
+func TestReconcileArgoCD_reconcileRBAC_withManualEdit(t *testing.T) {
+	restoreEnv(t)
+	defaultPolicy := "role:readonly"
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.RBAC.DefaultPolicy = &defaultPolicy
+	})
+	r := makeTestReconciler(t, a)
+	assert.NilError(t, r.reconcileRBAC(a))
+
+	editConfigMap(t, r, common.ArgoCDRBACConfigMapName, common.ArgoCDKeyRBACPolicyDefault, "role:admin")
+	assert.NilError(t, r.reconcileRBAC(a))
+
+	drift := getConfigDrift(t, r)
+	assert.Equal(t, len(drift), 1)
+	assert.Equal(t, drift[0].ConfigMap, common.ArgoCDRBACConfigMapName)
+	assert.Equal(t, drift[0].Key, common.ArgoCDKeyRBACPolicyDefault)
+	assert.Equal(t, drift[0].Field, "spec.rbac.defaultPolicy")
+}
+
+func TestGetChangedConfigMapKeys(t *testing.T) {
+	actual := map[string]string{"a": "1", "b": "2", "c": "3"}
+	desired := map[string]string{"a": "1", "b": "20", "d": "4"}
+	assert.DeepEqual(t, getChangedConfigMapKeys(actual, desired), []string{"b", "c", "d"})
+	assert.DeepEqual(t, getChangedConfigMapKeys(actual, actual), []string{})
+}
@@ -19,14 +19,17 @@ import (
 	"fmt"
 	"io/ioutil"
 	"path/filepath"
+	"reflect"
+	"sort"
 	"strings"
-	"time"
 
 	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
 	"github.com/argoproj-labs/argocd-operator/pkg/common"
 	"github.com/argoproj-labs/argocd-operator/pkg/controller/argoutil"
+	"gopkg.in/yaml.v2"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
@@ -37,6 +40,7 @@ func (r *ReconcileArgoCD) createRBACConfigMap(cm *corev1.ConfigMap, cr *argoproj
 	data[common.ArgoCDKeyRBACPolicyDefault] = getRBACDefaultPolicy(cr)
 	data[common.ArgoCDKeyRBACScopes] = getRBACScopes(cr)
 	cm.Data = data
+	setAppliedGeneration(cm, cr)
 
 	if err := controllerutil.SetControllerReference(cr, cm, r.scheme); err != nil {
 		return err
@@ -72,8 +76,8 @@ func getConfigManagementPlugins(cr *argoprojv1a1.ArgoCD) string {
 
 func getDexConfig(cr *argoprojv1a1.ArgoCD) string {
 	config := common.ArgoCDDefaultDexConfig
-	if len(cr.Spec.Dex.Config) > 0 {
-		config = cr.Spec.Dex.Config
+	if dex := getDexSpec(cr); len(dex.Config) > 0 {
+		config = dex.Config
 	}
 	return config
 }
@@ -105,6 +109,42 @@ func getHelpChatText(cr *argoprojv1a1.ArgoCD) string {
 	return text
 }
 
+// proxyExtensionMinimumVersion is the first version of Argo CD whose server supports the proxy extensions.
+const proxyExtensionMinimumVersion = "v2.7.0"
+
+// isProxyExtensionEnabled will return true if the proxy extensions are enabled for the given ArgoCD, and the Argo CD
+// server in use supports them.
+func isProxyExtensionEnabled(cr *argoprojv1a1.ArgoCD) bool {
+	return cr.Spec.Server.ProxyExtension.Enabled && isArgoCDVersionAtLeast(cr, proxyExtensionMinimumVersion)
+}
+
+// getProxyExtensionConfig will return the configuration of the backends of the proxy extensions for the given ArgoCD,
+// or an empty string when the proxy extensions are not enabled.
+func getProxyExtensionConfig(cr *argoprojv1a1.ArgoCD) string {
+	if !isProxyExtensionEnabled(cr) {
+		return ""
+	}
+	return cr.Spec.Server.ProxyExtension.Config
+}
+
+// getOIDCInsecureSkipVerify will return the setting for skipping the TLS verification of the OIDC provider for the
+// given ArgoCD, or an empty string when the verification is enabled.
+func getOIDCInsecureSkipVerify(cr *argoprojv1a1.ArgoCD) string {
+	if !cr.Spec.OIDCInsecureSkipVerify {
+		return ""
+	}
+	return "true"
+}
+
+// getUsersSessionDuration will return the duration of the sessions of the local users for the given ArgoCD, or an
+// empty string when the default of Argo CD is used.
+func getUsersSessionDuration(cr *argoprojv1a1.ArgoCD) string {
+	if cr.Spec.Server.Session.Duration == nil {
+		return ""
+	}
+	return cr.Spec.Server.Session.Duration.Duration.String()
+}
+
 // getKustomizeBuildOptions will return the kuztomize build options for the given ArgoCD.
 func getKustomizeBuildOptions(cr *argoprojv1a1.ArgoCD) string {
 	kbo := common.ArgoCDDefaultKustomizeBuildOptions
@@ -123,13 +163,22 @@ func getOIDCConfig(cr *argoprojv1a1.ArgoCD) string {
 	return config
 }
 
+// getArgoOIDCConfig will return the OIDC configuration of Argo CD for the given ArgoCD, which points at the external
+// Dex when one is used, or an empty string when Argo CD has no OIDC provider.
+func (r *ReconcileArgoCD) getArgoOIDCConfig(cr *argoprojv1a1.ArgoCD) (string, error) {
+	if isExternalDex(cr) {
+		return r.getExternalDexOIDCConfig(cr)
+	}
+	return getOIDCConfig(cr), nil
+}
+
 // getRBACPolicy will return the RBAC policy for the given ArgoCD.
 func getRBACPolicy(cr *argoprojv1a1.ArgoCD) string {
 	policy := common.ArgoCDDefaultRBACPolicy
 	if cr.Spec.RBAC.Policy != nil {
 		policy = *cr.Spec.RBAC.Policy
 	}
-	return policy
+	return getRBACPolicyWithDexGroups(policy, cr)
 }
 
 // getRBACDefaultPolicy will retun the RBAC default policy for the given ArgoCD.
@@ -147,7 +196,7 @@ func getRBACScopes(cr *argoprojv1a1.ArgoCD) string {
 	if cr.Spec.RBAC.Scopes != nil {
 		scopes = *cr.Spec.RBAC.Scopes
 	}
-	return scopes
+	return getRBACScopesWithDexGroups(scopes, cr)
 }
 
 // getResourceCustomizations will return the resource customizations for the given ArgoCD.
@@ -159,6 +208,69 @@ func getResourceCustomizations(cr *argoprojv1a1.ArgoCD) string {
 	return rc
 }
 
+// getHealthCheckResource will return the resource, in the form <group>/<Kind> or <Kind> for the core group, of the
+// given key of a resource health checks ConfigMap. ConfigMap keys can't contain a slash, so the group and kind are
+// separated with an underscore instead. The key is only split when the part before the underscore is a valid API
+// group, otherwise the whole key is the kind. An empty string is returned when the key has no kind.
+func getHealthCheckResource(key string) string {
+	i := strings.Index(key, "_")
+	if i < 0 {
+		return key
+	}
+	group, kind := key[:i], key[i+1:]
+	if len(validation.IsDNS1123Subdomain(group)) > 0 {
+		return key
+	}
+	if kind == "" {
+		return ""
+	}
+	return group + "/" + kind
+}
+
+// getResourceCustomizationsWithHealthChecks will return the resource customizations for the given ArgoCD, including
+// the Lua health checks from the ConfigMaps referenced by ResourceHealthChecksFrom. A health check that is defined in
+// the ResourceCustomizations property, or in an earlier ConfigMap, takes precedence.
+func (r *ReconcileArgoCD) getResourceCustomizationsWithHealthChecks(cr *argoprojv1a1.ArgoCD) (string, error) {
+	rc := getResourceCustomizations(cr)
+	if len(cr.Spec.ResourceHealthChecksFrom) == 0 {
+		return rc, nil
+	}
+
+	customizations := make(map[string]map[string]interface{})
+	if err := yaml.Unmarshal([]byte(rc), &customizations); err != nil {
+		return "", fmt.Errorf("failed to parse resource customizations: %w", err)
+	}
+
+	for _, ref := range cr.Spec.ResourceHealthChecksFrom {
+		cm := newConfigMapWithName(ref.Name, cr)
+		if !argoutil.IsObjectFound(r.client, cr.Namespace, cm.Name, cm) {
+			log.Info(fmt.Sprintf("resource health checks configmap [%s] not found", ref.Name))
+			continue
+		}
+
+		for key, script := range cm.Data {
+			resource := getHealthCheckResource(key)
+			if resource == "" {
+				log.Info(fmt.Sprintf("resource health checks configmap [%s] has invalid key [%s]", ref.Name, key))
+				continue
+			}
+			if customizations[resource] == nil {
+				customizations[resource] = make(map[string]interface{})
+			}
+			if _, ok := customizations[resource][common.ArgoCDKeyResourceHealthLua]; ok {
+				continue
+			}
+			customizations[resource][common.ArgoCDKeyResourceHealthLua] = script
+		}
+	}
+
+	if len(customizations) == 0 {
+		return rc, nil
+	}
+	bytes, err := yaml.Marshal(customizations)
+	return string(bytes), err
+}
+
 // getResourceExclusions will return the resource exclusions for the given ArgoCD.
 func getResourceExclusions(cr *argoprojv1a1.ArgoCD) string {
 	re := common.ArgoCDDefaultResourceExclusions
@@ -207,11 +319,25 @@ func getInitialSSHKnownHosts(cr *argoprojv1a1.ArgoCD) string {
 	return skh
 }
 
-// getTLSCerts will return the TLS certs for the given ArgoCD.
-func getInitialTLSCerts(cr *argoprojv1a1.ArgoCD) map[string]string {
+// getTLSCerts will return the TLS certs for the given ArgoCD, including the certs from the ConfigMaps referenced by
+// CertsFrom. A cert that is defined in the InitialCerts property, or in an earlier ConfigMap, takes precedence.
+func (r *ReconcileArgoCD) getTLSCerts(cr *argoprojv1a1.ArgoCD) map[string]string {
 	certs := make(map[string]string)
-	if len(cr.Spec.TLS.InitialCerts) > 0 {
-		certs = cr.Spec.TLS.InitialCerts
+	for server, cert := range cr.Spec.TLS.InitialCerts {
+		certs[server] = cert
+	}
+
+	for _, ref := range cr.Spec.TLS.CertsFrom {
+		cm := newConfigMapWithName(ref.Name, cr)
+		if !argoutil.IsObjectFound(r.client, cr.Namespace, cm.Name, cm) {
+			log.Info(fmt.Sprintf("tls certs configmap [%s] not found", ref.Name))
+			continue
+		}
+		for server, cert := range cm.Data {
+			if _, ok := certs[server]; !ok {
+				certs[server] = cert
+			}
+		}
 	}
 	return certs
 }
@@ -275,15 +401,24 @@ func (r *ReconcileArgoCD) reconcileConfigMaps(cr *argoprojv1a1.ArgoCD) error {
 		return err
 	}
 
+	if err := r.reconcileGrafanaDatasource(cr); err != nil {
+		return err
+	}
+
+	if err := r.reconcileVaultPluginConfigMap(cr); err != nil {
+		return err
+	}
+
 	return r.reconcileGPGKeysConfigMap(cr)
 }
 
 // reconcileCAConfigMap will ensure that the Certificate Authority ConfigMap is present.
-// This ConfigMap holds the CA Certificate data for client use.
+// This ConfigMap holds the CA Certificate data for client use, and is updated when the CA is rotated.
 func (r *ReconcileArgoCD) reconcileCAConfigMap(cr *argoprojv1a1.ArgoCD) error {
 	cm := newConfigMapWithName(getCAConfigMapName(cr), cr)
-	if argoutil.IsObjectFound(r.client, cr.Namespace, cm.Name, cm) {
-		return nil // ConfigMap found, do nothing
+	found := argoutil.IsObjectFound(r.client, cr.Namespace, cm.Name, cm)
+	if found && !metav1.IsControlledBy(cm, cr) {
+		return nil // ConfigMap not created by the operator, do nothing
 	}
 
 	caSecret := argoutil.NewSecretWithSuffix(cr.ObjectMeta, common.ArgoCDCASuffix)
@@ -292,8 +427,20 @@ func (r *ReconcileArgoCD) reconcileCAConfigMap(cr *argoprojv1a1.ArgoCD) error {
 		return nil
 	}
 
+	caCert := string(caSecret.Data[common.ArgoCDKeyTLSCert])
+	if found {
+		if cm.Data[common.ArgoCDKeyTLSCert] == caCert {
+			return nil // ConfigMap found with the current CA, do nothing
+		}
+		if cm.Data == nil {
+			cm.Data = make(map[string]string)
+		}
+		cm.Data[common.ArgoCDKeyTLSCert] = caCert
+		return r.client.Update(context.TODO(), cm)
+	}
+
 	cm.Data = map[string]string{
-		common.ArgoCDKeyTLSCert: string(caSecret.Data[common.ArgoCDKeyTLSCert]),
+		common.ArgoCDKeyTLSCert: caCert,
 	}
 
 	if err := controllerutil.SetControllerReference(cr, cm, r.scheme); err != nil {
@@ -306,10 +453,14 @@ func (r *ReconcileArgoCD) reconcileCAConfigMap(cr *argoprojv1a1.ArgoCD) error {
 func (r *ReconcileArgoCD) reconcileArgoConfigMap(cr *argoprojv1a1.ArgoCD) error {
 	cm := newConfigMapWithName(common.ArgoCDConfigMapName, cr)
 	if argoutil.IsObjectFound(r.client, cr.Namespace, cm.Name, cm) {
+		existing := cm.DeepCopy()
 		if err := r.reconcileDexConfiguration(cm, cr); err != nil {
 			return err
 		}
-		return r.reconcileExistingArgoConfigMap(cm, cr)
+		if err := r.reconcileExistingArgoConfigMap(cm, cr); err != nil {
+			return err
+		}
+		return r.reconcileConfigDrift(cr, existing, cm)
 	}
 
 	if cm.Data == nil {
@@ -317,16 +468,32 @@ func (r *ReconcileArgoCD) reconcileArgoConfigMap(cr *argoprojv1a1.ArgoCD) error
 	}
 
 	cm.Data[common.ArgoCDKeyApplicationInstanceLabelKey] = getApplicationInstanceLabelKey(cr)
+	if links := getGrafanaExternalApplicationLinks(cr); links != "" {
+		cm.Data[common.ArgoCDKeyApplicationLinks] = links
+	}
 	cm.Data[common.ArgoCDKeyConfigManagementPlugins] = getConfigManagementPlugins(cr)
 	cm.Data[common.ArgoCDKeyAdminEnabled] = fmt.Sprintf("%t", !cr.Spec.DisableAdmin)
+	if config := getProxyExtensionConfig(cr); config != "" {
+		cm.Data[common.ArgoCDKeyExtensionConfig] = config
+	}
 	cm.Data[common.ArgoCDKeyGATrackingID] = getGATrackingID(cr)
 	cm.Data[common.ArgoCDKeyGAAnonymizeUsers] = fmt.Sprint(cr.Spec.GAAnonymizeUsers)
 	cm.Data[common.ArgoCDKeyHelpChatURL] = getHelpChatURL(cr)
 	cm.Data[common.ArgoCDKeyHelpChatText] = getHelpChatText(cr)
 	cm.Data[common.ArgoCDKeyKustomizeBuildOptions] = getKustomizeBuildOptions(cr)
-	cm.Data[common.ArgoCDKeyOIDCConfig] = getOIDCConfig(cr)
-	if c := getResourceCustomizations(cr); c != "" {
-		cm.Data[common.ArgoCDKeyResourceCustomizations] = c
+	oidcConfig, err := r.getArgoOIDCConfig(cr)
+	if err != nil {
+		return err
+	}
+	if oidcConfig != "" {
+		cm.Data[common.ArgoCDKeyOIDCConfig] = oidcConfig
+	}
+	customizations, err := r.getResourceCustomizationsWithHealthChecks(cr)
+	if err != nil {
+		return err
+	}
+	if customizations != "" {
+		cm.Data[common.ArgoCDKeyResourceCustomizations] = customizations
 	}
 	cm.Data[common.ArgoCDKeyResourceExclusions] = getResourceExclusions(cr)
 	cm.Data[common.ArgoCDKeyResourceInclusions] = getResourceInclusions(cr)
@@ -335,18 +502,37 @@ func (r *ReconcileArgoCD) reconcileArgoConfigMap(cr *argoprojv1a1.ArgoCD) error
 	cm.Data[common.ArgoCDKeyStatusBadgeEnabled] = fmt.Sprint(cr.Spec.StatusBadgeEnabled)
 	cm.Data[common.ArgoCDKeyServerURL] = r.getArgoServerURI(cr)
 	cm.Data[common.ArgoCDKeyUsersAnonymousEnabled] = fmt.Sprint(cr.Spec.UsersAnonymousEnabled)
+	if duration := getUsersSessionDuration(cr); duration != "" {
+		cm.Data[common.ArgoCDKeyUsersSessionDuration] = duration
+	}
+	if skip := getOIDCInsecureSkipVerify(cr); skip != "" {
+		cm.Data[common.ArgoCDKeyOIDCInsecureSkipVerify] = skip
+	}
 
-	if !isDexDisabled() {
+	if isManagedDexEnabled(cr) {
 		dexConfig := getDexConfig(cr)
-		if dexConfig == "" && cr.Spec.Dex.OpenShiftOAuth {
+		if dexConfig == "" && getDexSpec(cr).OpenShiftOAuth {
 			cfg, err := r.getOpenShiftDexConfig(cr)
 			if err != nil {
 				return err
 			}
 			dexConfig = cfg
 		}
+		dexConfig, err := getDexConfigWithStaticClients(dexConfig, cr)
+		if err != nil {
+			return err
+		}
+		dexConfig, err = getDexConfigWithGroups(dexConfig, cr)
+		if err != nil {
+			return err
+		}
+		dexConfig, err = getDexConfigWithIDTokenExpiration(dexConfig, cr)
+		if err != nil {
+			return err
+		}
 		cm.Data[common.ArgoCDKeyDexConfig] = dexConfig
 	}
+	setAppliedGeneration(cm, cr)
 
 	if err := controllerutil.SetControllerReference(cr, cm, r.scheme); err != nil {
 		return err
@@ -357,31 +543,43 @@ func (r *ReconcileArgoCD) reconcileArgoConfigMap(cr *argoprojv1a1.ArgoCD) error
 // reconcileDexConfiguration will ensure that Dex is configured properly.
 func (r *ReconcileArgoCD) reconcileDexConfiguration(cm *corev1.ConfigMap, cr *argoprojv1a1.ArgoCD) error {
 	actual := cm.Data[common.ArgoCDKeyDexConfig]
+	if cr.Spec.SSO != nil && !isManagedDexEnabled(cr) {
+		// Argo CD is pointed at an external Dex or another SSO provider with the OIDC configuration, which can't be
+		// combined with dex.config.
+		if _, ok := cm.Data[common.ArgoCDKeyDexConfig]; ok {
+			delete(cm.Data, common.ArgoCDKeyDexConfig)
+			setAppliedGeneration(cm, cr)
+			return r.client.Update(context.TODO(), cm)
+		}
+		return nil
+	}
 	desired := getDexConfig(cr)
-	if len(desired) <= 0 && cr.Spec.Dex.OpenShiftOAuth {
+	if len(desired) <= 0 && getDexSpec(cr).OpenShiftOAuth {
 		cfg, err := r.getOpenShiftDexConfig(cr)
 		if err != nil {
 			return err
 		}
 		desired = cfg
 	}
+	desired, err := getDexConfigWithStaticClients(desired, cr)
+	if err != nil {
+		return err
+	}
+	desired, err = getDexConfigWithGroups(desired, cr)
+	if err != nil {
+		return err
+	}
+	desired, err = getDexConfigWithIDTokenExpiration(desired, cr)
+	if err != nil {
+		return err
+	}
 
 	if actual != desired {
-		// Update ConfigMap with desired configuration.
+		// Update ConfigMap with desired configuration, the Dex Deployment is rolled out when the checksum of the
+		// configuration changes.
 		cm.Data[common.ArgoCDKeyDexConfig] = desired
-		if err := r.client.Update(context.TODO(), cm); err != nil {
-			return err
-		}
-
-		// Trigger rollout of Dex Deployment to pick up changes.
-		deploy := newDeploymentWithSuffix("dex-server", "dex-server", cr)
-		if !argoutil.IsObjectFound(r.client, deploy.Namespace, deploy.Name, deploy) {
-			log.Info("unable to locate dex deployment")
-			return nil
-		}
-
-		deploy.Spec.Template.ObjectMeta.Labels["dex.config.changed"] = time.Now().UTC().Format("01022006-150406-MST")
-		return r.client.Update(context.TODO(), deploy)
+		setAppliedGeneration(cm, cr)
+		return r.client.Update(context.TODO(), cm)
 	}
 	return nil
 }
@@ -394,8 +592,8 @@ func (r *ReconcileArgoCD) reconcileExistingArgoConfigMap(cm *corev1.ConfigMap, c
 		changed = true
 	}
 
-	if cm.Data[common.ArgoCDKeyApplicationInstanceLabelKey] != cr.Spec.ApplicationInstanceLabelKey {
-		cm.Data[common.ArgoCDKeyApplicationInstanceLabelKey] = cr.Spec.ApplicationInstanceLabelKey
+	if cm.Data[common.ArgoCDKeyApplicationInstanceLabelKey] != getApplicationInstanceLabelKey(cr) {
+		cm.Data[common.ArgoCDKeyApplicationInstanceLabelKey] = getApplicationInstanceLabelKey(cr)
 		changed = true
 	}
 
@@ -404,8 +602,26 @@ func (r *ReconcileArgoCD) reconcileExistingArgoConfigMap(cm *corev1.ConfigMap, c
 		changed = true
 	}
 
-	if cm.Data[common.ArgoCDKeyGATrackingID] != cr.Spec.GATrackingID {
-		cm.Data[common.ArgoCDKeyGATrackingID] = cr.Spec.GATrackingID
+	if links := getGrafanaExternalApplicationLinks(cr); cm.Data[common.ArgoCDKeyApplicationLinks] != links {
+		if links == "" {
+			delete(cm.Data, common.ArgoCDKeyApplicationLinks)
+		} else {
+			cm.Data[common.ArgoCDKeyApplicationLinks] = links
+		}
+		changed = true
+	}
+
+	if config := getProxyExtensionConfig(cr); cm.Data[common.ArgoCDKeyExtensionConfig] != config {
+		if config == "" {
+			delete(cm.Data, common.ArgoCDKeyExtensionConfig)
+		} else {
+			cm.Data[common.ArgoCDKeyExtensionConfig] = config
+		}
+		changed = true
+	}
+
+	if cm.Data[common.ArgoCDKeyGATrackingID] != getGATrackingID(cr) {
+		cm.Data[common.ArgoCDKeyGATrackingID] = getGATrackingID(cr)
 		changed = true
 	}
 
@@ -414,13 +630,13 @@ func (r *ReconcileArgoCD) reconcileExistingArgoConfigMap(cm *corev1.ConfigMap, c
 		changed = true
 	}
 
-	if cm.Data[common.ArgoCDKeyHelpChatURL] != cr.Spec.HelpChatURL {
-		cm.Data[common.ArgoCDKeyHelpChatURL] = cr.Spec.HelpChatURL
+	if cm.Data[common.ArgoCDKeyHelpChatURL] != getHelpChatURL(cr) {
+		cm.Data[common.ArgoCDKeyHelpChatURL] = getHelpChatURL(cr)
 		changed = true
 	}
 
-	if cm.Data[common.ArgoCDKeyHelpChatText] != cr.Spec.HelpChatText {
-		cm.Data[common.ArgoCDKeyHelpChatText] = cr.Spec.HelpChatText
+	if cm.Data[common.ArgoCDKeyHelpChatText] != getHelpChatText(cr) {
+		cm.Data[common.ArgoCDKeyHelpChatText] = getHelpChatText(cr)
 		changed = true
 	}
 
@@ -429,15 +645,28 @@ func (r *ReconcileArgoCD) reconcileExistingArgoConfigMap(cm *corev1.ConfigMap, c
 		changed = true
 	}
 
-	if cr.Spec.SSO == nil {
-		if cm.Data[common.ArgoCDKeyOIDCConfig] != cr.Spec.OIDCConfig {
-			cm.Data[common.ArgoCDKeyOIDCConfig] = cr.Spec.OIDCConfig
+	// The OIDC configuration of Keycloak is written by the Keycloak reconciler.
+	if cr.Spec.SSO == nil || cr.Spec.SSO.Provider != argoprojv1a1.SSOProviderTypeKeycloak {
+		oidcConfig, err := r.getArgoOIDCConfig(cr)
+		if err != nil {
+			return err
+		}
+		if current, ok := cm.Data[common.ArgoCDKeyOIDCConfig]; current != oidcConfig || (ok && oidcConfig == "") {
+			if oidcConfig == "" {
+				delete(cm.Data, common.ArgoCDKeyOIDCConfig)
+			} else {
+				cm.Data[common.ArgoCDKeyOIDCConfig] = oidcConfig
+			}
 			changed = true
 		}
 	}
 
-	if cm.Data[common.ArgoCDKeyResourceCustomizations] != cr.Spec.ResourceCustomizations {
-		cm.Data[common.ArgoCDKeyResourceCustomizations] = cr.Spec.ResourceCustomizations
+	customizations, err := r.getResourceCustomizationsWithHealthChecks(cr)
+	if err != nil {
+		return err
+	}
+	if cm.Data[common.ArgoCDKeyResourceCustomizations] != customizations {
+		cm.Data[common.ArgoCDKeyResourceCustomizations] = customizations
 		changed = true
 	}
 
@@ -462,12 +691,31 @@ func (r *ReconcileArgoCD) reconcileExistingArgoConfigMap(cm *corev1.ConfigMap, c
 		changed = true
 	}
 
+	if duration := getUsersSessionDuration(cr); cm.Data[common.ArgoCDKeyUsersSessionDuration] != duration {
+		if duration == "" {
+			delete(cm.Data, common.ArgoCDKeyUsersSessionDuration)
+		} else {
+			cm.Data[common.ArgoCDKeyUsersSessionDuration] = duration
+		}
+		changed = true
+	}
+
+	if skip := getOIDCInsecureSkipVerify(cr); cm.Data[common.ArgoCDKeyOIDCInsecureSkipVerify] != skip {
+		if skip == "" {
+			delete(cm.Data, common.ArgoCDKeyOIDCInsecureSkipVerify)
+		} else {
+			cm.Data[common.ArgoCDKeyOIDCInsecureSkipVerify] = skip
+		}
+		changed = true
+	}
+
 	if cm.Data[common.ArgoCDKeyRepositoryCredentials] != cr.Spec.RepositoryCredentials {
 		cm.Data[common.ArgoCDKeyRepositoryCredentials] = cr.Spec.RepositoryCredentials
 		changed = true
 	}
 
 	if changed {
+		setAppliedGeneration(cm, cr)
 		return r.client.Update(context.TODO(), cm) // TODO: Reload Argo CD server after ConfigMap change (which properties)?
 	}
 
@@ -476,7 +724,7 @@ func (r *ReconcileArgoCD) reconcileExistingArgoConfigMap(cm *corev1.ConfigMap, c
 
 // reconcileGrafanaConfiguration will ensure that the Grafana configuration ConfigMap is present.
 func (r *ReconcileArgoCD) reconcileGrafanaConfiguration(cr *argoprojv1a1.ArgoCD) error {
-	if !cr.Spec.Grafana.Enabled {
+	if !isManagedGrafanaEnabled(cr) {
 		return nil // Grafana not enabled, do nothing.
 	}
 
@@ -520,15 +768,18 @@ func (r *ReconcileArgoCD) reconcileGrafanaConfiguration(cr *argoprojv1a1.ArgoCD)
 	return r.client.Create(context.TODO(), cm)
 }
 
-// reconcileGrafanaDashboards will ensure that the Grafana dashboards ConfigMap is present.
+// reconcileGrafanaDashboards will ensure that the Grafana dashboards ConfigMap is present. The ConfigMap is labeled
+// for the Grafana sidecar, and the dashboards use the provisioned datasource, when an external Grafana is used.
 func (r *ReconcileArgoCD) reconcileGrafanaDashboards(cr *argoprojv1a1.ArgoCD) error {
 	if !cr.Spec.Grafana.Enabled {
 		return nil // Grafana not enabled, do nothing.
 	}
 
+	external := isGrafanaExternal(cr)
 	cm := newConfigMapWithSuffix(common.ArgoCDGrafanaDashboardConfigMapSuffix, cr)
-	if argoutil.IsObjectFound(r.client, cr.Namespace, cm.Name, cm) {
-		return nil // ConfigMap found, do nothing
+	exists := argoutil.IsObjectFound(r.client, cr.Namespace, cm.Name, cm)
+	if exists && (cm.Labels[common.ArgoCDKeyGrafanaDashboard] != "") == external {
+		return nil // ConfigMap found for the same Grafana, do nothing
 	}
 
 	pattern := filepath.Join(getGrafanaConfigPath(), "dashboards/*.json")
@@ -547,9 +798,54 @@ func (r *ReconcileArgoCD) reconcileGrafanaDashboards(cr *argoprojv1a1.ArgoCD) er
 		parts := strings.Split(f, "/")
 		filename := parts[len(parts)-1]
 		data[filename] = string(dashboard)
+		if external {
+			data[filename] = strings.ReplaceAll(data[filename], `"datasource": "Prometheus"`,
+				fmt.Sprintf(`"datasource": "%s"`, getGrafanaExternalDatasourceName(cr)))
+		}
 	}
 	cm.Data = data
 
+	if cm.Labels == nil {
+		cm.Labels = make(map[string]string)
+	}
+	if external {
+		cm.Labels[common.ArgoCDKeyGrafanaDashboard] = "1"
+	} else {
+		delete(cm.Labels, common.ArgoCDKeyGrafanaDashboard)
+	}
+
+	if exists {
+		return r.client.Update(context.TODO(), cm)
+	}
+
+	if err := controllerutil.SetControllerReference(cr, cm, r.scheme); err != nil {
+		return err
+	}
+	return r.client.Create(context.TODO(), cm)
+}
+
+// reconcileGrafanaDatasource will ensure that the Grafana datasource ConfigMap is present, labeled for the Grafana
+// sidecar, when an external Grafana is used.
+func (r *ReconcileArgoCD) reconcileGrafanaDatasource(cr *argoprojv1a1.ArgoCD) error {
+	if !isGrafanaExternal(cr) {
+		return nil // External Grafana not used, do nothing.
+	}
+
+	cm := newConfigMapWithSuffix(common.ArgoCDGrafanaDatasourceConfigMapSuffix, cr)
+	data := map[string]string{
+		"datasource.yaml": getGrafanaExternalDatasource(cr),
+	}
+
+	if argoutil.IsObjectFound(r.client, cr.Namespace, cm.Name, cm) {
+		if !reflect.DeepEqual(cm.Data, data) {
+			cm.Data = data
+			return r.client.Update(context.TODO(), cm)
+		}
+		return nil // ConfigMap found with nothing changed, move along...
+	}
+
+	cm.Data = data
+	cm.Labels[common.ArgoCDKeyGrafanaDatasource] = "1"
 	if err := controllerutil.SetControllerReference(cr, cm, r.scheme); err != nil {
 		return err
 	}
@@ -560,7 +856,11 @@ func (r *ReconcileArgoCD) reconcileGrafanaDashboards(cr *argoprojv1a1.ArgoCD) er
 func (r *ReconcileArgoCD) reconcileRBAC(cr *argoprojv1a1.ArgoCD) error {
 	cm := newConfigMapWithName(common.ArgoCDRBACConfigMapName, cr)
 	if argoutil.IsObjectFound(r.client, cr.Namespace, cm.Name, cm) {
-		return r.reconcileRBACConfigMap(cm, cr)
+		existing := cm.DeepCopy()
+		if err := r.reconcileRBACConfigMap(cm, cr); err != nil {
+			return err
+		}
+		return r.reconcileConfigDrift(cr, existing, cm)
 	}
 	return r.createRBACConfigMap(cm, cr)
 }
@@ -568,9 +868,9 @@ func (r *ReconcileArgoCD) reconcileRBAC(cr *argoprojv1a1.ArgoCD) error {
 // reconcileRBACConfigMap will ensure that the RBAC ConfigMap is syncronized with the given ArgoCD.
 func (r *ReconcileArgoCD) reconcileRBACConfigMap(cm *corev1.ConfigMap, cr *argoprojv1a1.ArgoCD) error {
 	changed := false
-	// Policy CSV
-	if cr.Spec.RBAC.Policy != nil && cm.Data[common.ArgoCDKeyRBACPolicyCSV] != *cr.Spec.RBAC.Policy {
-		cm.Data[common.ArgoCDKeyRBACPolicyCSV] = *cr.Spec.RBAC.Policy
+	// Policy CSV, always compared so that the lines of removed Dex group bindings are removed as well.
+	if cm.Data[common.ArgoCDKeyRBACPolicyCSV] != getRBACPolicy(cr) {
+		cm.Data[common.ArgoCDKeyRBACPolicyCSV] = getRBACPolicy(cr)
 		changed = true
 	}
 
@@ -581,13 +881,14 @@ func (r *ReconcileArgoCD) reconcileRBACConfigMap(cm *corev1.ConfigMap, cr *argop
 	}
 
 	// Scopes
-	if cr.Spec.RBAC.Scopes != nil && cm.Data[common.ArgoCDKeyRBACScopes] != *cr.Spec.RBAC.Scopes {
-		cm.Data[common.ArgoCDKeyRBACScopes] = *cr.Spec.RBAC.Scopes
+	if cm.Data[common.ArgoCDKeyRBACScopes] != getRBACScopes(cr) {
+		cm.Data[common.ArgoCDKeyRBACScopes] = getRBACScopes(cr)
 		changed = true
 	}
 
 	if changed {
 		// TODO: Reload server (and dex?) if RBAC settings change?
+		setAppliedGeneration(cm, cr)
 		return r.client.Update(context.TODO(), cm)
 	}
 	return nil // ConfigMap exists and nothing to do, move along...
@@ -601,28 +902,37 @@ func (r *ReconcileArgoCD) reconcileRedisConfiguration(cr *argoprojv1a1.ArgoCD) e
 	return nil
 }
 
+// getRedisHAConfigMapData will return the scripts and configuration of Redis in HA mode for the given ArgoCD.
+func getRedisHAConfigMapData(cr *argoprojv1a1.ArgoCD) map[string]string {
+	return map[string]string{
+		"haproxy.cfg":     getRedisHAProxyConfig(cr),
+		"haproxy_init.sh": getRedisHAProxyScript(cr),
+		"init.sh":         getRedisInitScript(cr),
+		"redis.conf":      getRedisConf(cr),
+		"sentinel.conf":   getRedisSentinelConf(cr),
+	}
+}
+
 // reconcileRedisHAConfigMap will ensure that the Redis HA ConfigMap is present for the given ArgoCD.
 func (r *ReconcileArgoCD) reconcileRedisHAConfigMap(cr *argoprojv1a1.ArgoCD) error {
 	cm := newConfigMapWithName(common.ArgoCDRedisHAConfigMapName, cr)
 	if argoutil.IsObjectFound(r.client, cr.Namespace, cm.Name, cm) {
-		if !cr.Spec.HA.Enabled {
+		if !isRedisHAInUse(cr) {
 			// ConfigMap exists but HA enabled flag has been set to false, delete the ConfigMap
 			return r.client.Delete(context.TODO(), cm)
 		}
+		if data := getRedisHAConfigMapData(cr); !reflect.DeepEqual(cm.Data, data) {
+			cm.Data = data
+			return r.client.Update(context.TODO(), cm)
+		}
 		return nil // ConfigMap found with nothing changed, move along...
 	}
 
-	if !cr.Spec.HA.Enabled {
+	if !isRedisHAInUse(cr) {
 		return nil // HA not enabled, do nothing.
 	}
 
-	cm.Data = map[string]string{
-		"haproxy.cfg":     getRedisHAProxyConfig(cr),
-		"haproxy_init.sh": getRedisHAProxyScript(cr),
-		"init.sh":         getRedisInitScript(cr),
-		"redis.conf":      getRedisConf(cr),
-		"sentinel.conf":   getRedisSentinelConf(cr),
-	}
+	cm.Data = getRedisHAConfigMapData(cr)
 
 	if err := controllerutil.SetControllerReference(cr, cm, r.scheme); err != nil {
 		return err
@@ -647,16 +957,60 @@ func (r *ReconcileArgoCD) reconcileSSHKnownHosts(cr *argoprojv1a1.ArgoCD) error
 	return r.client.Create(context.TODO(), cm)
 }
 
-// reconcileTLSCerts will ensure that the ArgoCD TLS Certs ConfigMap is present.
+// reconcileTLSCerts will ensure that the ArgoCD TLS Certs ConfigMap is present and holds the TLS certs of the given
+// ArgoCD. Certs that were added by other means, such as the argocd CLI, are kept.
 func (r *ReconcileArgoCD) reconcileTLSCerts(cr *argoprojv1a1.ArgoCD) error {
+	certs := r.getTLSCerts(cr)
+
 	cm := newConfigMapWithName(common.ArgoCDTLSCertsConfigMapName, cr)
-	cm.Data = getInitialTLSCerts(cr)
-	if err := controllerutil.SetControllerReference(cr, cm, r.scheme); err != nil {
-		return err
+	found := argoutil.IsObjectFound(r.client, cr.Namespace, cm.Name, cm)
+	if cm.Data == nil {
+		cm.Data = make(map[string]string)
 	}
 
-	if argoutil.IsObjectFound(r.client, cr.Namespace, cm.Name, cm) {
-		return r.client.Update(context.TODO(), cm)
+	changed := false
+	for _, server := range strings.Split(cm.Annotations[common.ArgoCDTLSCertsAnnotation], ",") {
+		if _, ok := certs[server]; ok || server == "" {
+			continue
+		}
+		if _, ok := cm.Data[server]; ok {
+			delete(cm.Data, server)
+			changed = true
+		}
+	}
+
+	servers := make([]string, 0, len(certs))
+	for server, cert := range certs {
+		servers = append(servers, server)
+		if cm.Data[server] != cert {
+			cm.Data[server] = cert
+			changed = true
+		}
+	}
+	sort.Strings(servers)
+
+	managed := strings.Join(servers, ",")
+	if cm.Annotations[common.ArgoCDTLSCertsAnnotation] != managed {
+		if managed == "" {
+			delete(cm.Annotations, common.ArgoCDTLSCertsAnnotation)
+		} else {
+			if cm.Annotations == nil {
+				cm.Annotations = make(map[string]string)
+			}
+			cm.Annotations[common.ArgoCDTLSCertsAnnotation] = managed
+		}
+		changed = true
+	}
+
+	if found {
+		if changed {
+			return r.client.Update(context.TODO(), cm)
+		}
+		return nil
+	}
+
+	if err := controllerutil.SetControllerReference(cr, cm, r.scheme); err != nil {
+		return err
 	}
 	return r.client.Create(context.TODO(), cm)
 }
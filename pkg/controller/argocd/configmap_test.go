@@ -19,7 +19,9 @@ import (
 	"fmt"
 	"os"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	argoprojv1alpha1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
 	"github.com/argoproj-labs/argocd-operator/pkg/common"
@@ -82,6 +84,46 @@ func TestReconcileArgoCD_reconcileTLSCerts_withUpdate(t *testing.T) {
 	}
 }
 
+func TestReconcileArgoCD_reconcileTLSCerts_certsFrom(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.TLS.InitialCerts = map[string]string{"git.example.com": "initial"}
+		a.Spec.TLS.CertsFrom = []argoprojv1alpha1.ConfigMapRef{{Name: "team-certs"}, {Name: "missing"}}
+	})
+	teamCerts := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "team-certs", Namespace: a.Namespace},
+		Data: map[string]string{
+			"git.example.com":  "team",
+			"team.example.com": "team",
+		},
+	}
+	r := makeTestReconciler(t, a, teamCerts)
+
+	assert.NilError(t, r.reconcileTLSCerts(a))
+
+	configMap := &corev1.ConfigMap{}
+	key := types.NamespacedName{Name: common.ArgoCDTLSCertsConfigMapName, Namespace: a.Namespace}
+	assert.NilError(t, r.client.Get(context.TODO(), key, configMap))
+	assert.DeepEqual(t, configMap.Data, map[string]string{
+		"git.example.com":  "initial",
+		"team.example.com": "team",
+	})
+
+	// Certs added by other means are kept, and certs that are no longer configured are removed.
+	configMap.Data["cli.example.com"] = "cli"
+	assert.NilError(t, r.client.Update(context.TODO(), configMap))
+	a.Spec.TLS.CertsFrom = nil
+
+	assert.NilError(t, r.reconcileTLSCerts(a))
+	configMap = &corev1.ConfigMap{}
+	assert.NilError(t, r.client.Get(context.TODO(), key, configMap))
+	assert.DeepEqual(t, configMap.Data, map[string]string{
+		"git.example.com": "initial",
+		"cli.example.com": "cli",
+	})
+	assert.Equal(t, configMap.Annotations[common.ArgoCDTLSCertsAnnotation], "git.example.com")
+}
+
 func TestReconcileArgoCD_reconcileArgoConfigMap(t *testing.T) {
 	logf.SetLogger(logf.ZapLogger(true))
 	a := makeTestArgoCD()
@@ -107,7 +149,6 @@ func TestReconcileArgoCD_reconcileArgoConfigMap(t *testing.T) {
 		"help.chatText":                "Chat now!",
 		"help.chatUrl":                 "https://mycorp.slack.com/argo-cd",
 		"kustomize.buildOptions":       "",
-		"oidc.config":                  "",
 		"repositories":                 "",
 		"repository.credentials":       "",
 		"resource.inclusions":          "",
@@ -122,6 +163,27 @@ func TestReconcileArgoCD_reconcileArgoConfigMap(t *testing.T) {
 	}
 }
 
+func TestReconcileArgoCD_reconcileArgoConfigMap_oidcConfig(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.OIDCConfig = "name: Example\nissuer: https://sso.example.com\n"
+	})
+	r := makeTestReconciler(t, a)
+
+	assert.NilError(t, r.reconcileArgoConfigMap(a))
+	cm := &corev1.ConfigMap{}
+	assert.NilError(t, argoutil.FetchObject(r.client, testNamespace, common.ArgoCDConfigMapName, cm))
+	assert.Equal(t, cm.Data[common.ArgoCDKeyOIDCConfig], a.Spec.OIDCConfig)
+
+	// Clearing the OIDC configuration removes it from the ConfigMap.
+	a.Spec.OIDCConfig = ""
+	assert.NilError(t, r.reconcileArgoConfigMap(a))
+	cm = &corev1.ConfigMap{}
+	assert.NilError(t, argoutil.FetchObject(r.client, testNamespace, common.ArgoCDConfigMapName, cm))
+	_, ok := cm.Data[common.ArgoCDKeyOIDCConfig]
+	assert.Assert(t, !ok)
+}
+
 func TestReconcileArgoCDCM_withRepoCredentials(t *testing.T) {
 	logf.SetLogger(logf.ZapLogger(true))
 	a := makeTestArgoCD()
@@ -290,6 +352,43 @@ func TestReconcileArgoCD_reconcileArgoConfigMap_withResourceInclusions(t *testin
 	}
 }
 
+func TestReconcileArgoCD_reconcileArgoConfigMap_withProxyExtension(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	config := `extensions:
+- name: metrics
+  backend:
+    services:
+    - url: http://metrics-server.monitoring.svc:8080
+`
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.Version = "v2.7.0"
+		a.Spec.Server.ProxyExtension.Enabled = true
+		a.Spec.Server.ProxyExtension.Config = config
+	})
+	r := makeTestReconciler(t, a)
+
+	assert.NilError(t, r.reconcileArgoConfigMap(a))
+
+	cm := &corev1.ConfigMap{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{
+		Name:      common.ArgoCDConfigMapName,
+		Namespace: testNamespace,
+	}, cm))
+	assert.Equal(t, cm.Data[common.ArgoCDKeyExtensionConfig], config)
+
+	// Disabling the proxy extensions removes their configuration
+	a.Spec.Server.ProxyExtension.Enabled = false
+	assert.NilError(t, r.reconcileArgoConfigMap(a))
+
+	cm = &corev1.ConfigMap{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{
+		Name:      common.ArgoCDConfigMapName,
+		Namespace: testNamespace,
+	}, cm))
+	_, ok := cm.Data[common.ArgoCDKeyExtensionConfig]
+	assert.Assert(t, !ok)
+}
+
 func TestReconcileArgoCD_reconcileArgoConfigMap_withResourceCustomizations(t *testing.T) {
 	logf.SetLogger(logf.ZapLogger(true))
 	customizations := "testing: testing"
@@ -312,3 +411,439 @@ func TestReconcileArgoCD_reconcileArgoConfigMap_withResourceCustomizations(t *te
 		t.Fatalf("reconcileArgoConfigMap failed got %q, want %q", c, customizations)
 	}
 }
+
+func TestReconcileArgoCD_reconcileArgoConfigMap_withResourceHealthChecksFrom(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.ResourceCustomizations = "cert-manager.io/Certificate:\n  health.lua: spec\n"
+		a.Spec.ResourceHealthChecksFrom = []argoprojv1alpha1.ConfigMapRef{
+			{Name: "team-a-health"},
+			{Name: "team-b-health"},
+			{Name: "missing-health"},
+		}
+	})
+	teamA := newConfigMapWithName("team-a-health", a)
+	teamA.Data = map[string]string{
+		"cert-manager.io_Certificate": "team-a",
+		"PersistentVolumeClaim":       "team-a",
+	}
+	teamB := newConfigMapWithName("team-b-health", a)
+	teamB.Data = map[string]string{
+		"PersistentVolumeClaim": "team-b",
+		"example.com_Widget":    "team-b",
+	}
+	r := makeTestReconciler(t, a, teamA, teamB)
+
+	assert.NilError(t, r.reconcileArgoConfigMap(a))
+
+	getCustomizations := func() map[string]map[string]string {
+		cm := &corev1.ConfigMap{}
+		assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{
+			Name:      common.ArgoCDConfigMapName,
+			Namespace: testNamespace,
+		}, cm))
+		customizations := make(map[string]map[string]string)
+		assert.NilError(t, yaml.Unmarshal([]byte(cm.Data[common.ArgoCDKeyResourceCustomizations]), &customizations))
+		return customizations
+	}
+
+	// The ResourceCustomizations property and earlier ConfigMaps take precedence.
+	want := map[string]map[string]string{
+		"cert-manager.io/Certificate": {"health.lua": "spec"},
+		"PersistentVolumeClaim":       {"health.lua": "team-a"},
+		"example.com/Widget":          {"health.lua": "team-b"},
+	}
+	assert.DeepEqual(t, getCustomizations(), want)
+
+	// A change to a referenced ConfigMap is picked up.
+	teamB.Data["example.com_Widget"] = "updated"
+	assert.NilError(t, r.client.Update(context.TODO(), teamB))
+	assert.NilError(t, r.reconcileArgoConfigMap(a))
+	want["example.com/Widget"]["health.lua"] = "updated"
+	assert.DeepEqual(t, getCustomizations(), want)
+}
+
+func TestGetHealthCheckResource(t *testing.T) {
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{"PersistentVolumeClaim", "PersistentVolumeClaim"},
+		{"apps_Deployment", "apps/Deployment"},
+		{"cert-manager.io_Certificate", "cert-manager.io/Certificate"},
+		{"example.com_My_Widget", "example.com/My_Widget"},
+		{"My_Widget", "My_Widget"},
+		{"example.com_", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			assert.Equal(t, getHealthCheckResource(tt.key), tt.want)
+		})
+	}
+}
+
+func TestReconcileArgoCD_reconcileArgoConfigMap_withDexStaticClients(t *testing.T) {
+	restoreEnv(t)
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.Dex.Config = "connectors:\n- type: github\n  id: github\n  name: GitHub\n"
+		a.Spec.Dex.StaticClients = []argoprojv1alpha1.ArgoCDDexStaticClientSpec{
+			{ID: "ci-system", Name: "CI", RedirectURIs: []string{"https://ci.example.com/callback"}},
+			{ID: "cli", Public: true},
+		}
+	})
+	r := makeTestReconciler(t, a)
+
+	assert.NilError(t, r.reconcileArgoConfigMap(a))
+
+	cm := &corev1.ConfigMap{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{
+		Name:      common.ArgoCDConfigMapName,
+		Namespace: testNamespace,
+	}, cm))
+
+	m := make(map[string]interface{})
+	assert.NilError(t, yaml.Unmarshal([]byte(cm.Data["dex.config"]), &m))
+	assert.Equal(t, len(m["connectors"].([]interface{})), 1)
+
+	clients := m["staticClients"].([]interface{})
+	assert.Equal(t, len(clients), 2)
+	ci := clients[0].(map[interface{}]interface{})
+	assert.Equal(t, ci["id"], "ci-system")
+	assert.Equal(t, ci["secret"], "$dex.ci-system.clientSecret")
+	cli := clients[1].(map[interface{}]interface{})
+	assert.Equal(t, cli["public"], true)
+	_, ok := cli["secret"]
+	assert.Assert(t, !ok)
+}
+
+func TestReconcileArgoCD_reconcileArgoConfigMap_withRotatedDexToken(t *testing.T) {
+	restoreEnv(t)
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.Dex.OpenShiftOAuth = true
+	})
+	sa := &corev1.ServiceAccount{
+		TypeMeta:   metav1.TypeMeta{Kind: "ServiceAccount", APIVersion: "v1"},
+		ObjectMeta: metav1.ObjectMeta{Name: "argocd-argocd-dex-server", Namespace: testNamespace},
+		Secrets: []corev1.ObjectReference{{
+			Name: "argocd-argocd-dex-server-token-old",
+		}},
+	}
+	oldToken := argoutil.NewSecretWithName(a.ObjectMeta, "argocd-argocd-dex-server-token-old")
+	oldToken.Data = map[string][]byte{"token": []byte("old-token")}
+	r := makeTestReconciler(t, a, sa, oldToken)
+
+	assert.NilError(t, r.reconcileArgoConfigMap(a))
+
+	// Rotate the ServiceAccount token.
+	assert.NilError(t, r.client.Delete(context.TODO(), oldToken))
+	newToken := argoutil.NewSecretWithName(a.ObjectMeta, "argocd-argocd-dex-server-token-new")
+	newToken.Data = map[string][]byte{"token": []byte("new-token")}
+	assert.NilError(t, r.client.Create(context.TODO(), newToken))
+	sa.Secrets = append(sa.Secrets, corev1.ObjectReference{Name: newToken.Name})
+	assert.NilError(t, r.client.Update(context.TODO(), sa))
+
+	assert.NilError(t, r.reconcileArgoConfigMap(a))
+
+	cm := &corev1.ConfigMap{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{
+		Name:      common.ArgoCDConfigMapName,
+		Namespace: testNamespace,
+	}, cm))
+	assert.Assert(t, strings.Contains(cm.Data["dex.config"], "new-token"))
+	assert.Assert(t, !strings.Contains(cm.Data["dex.config"], "old-token"))
+}
+
+func TestReconcileArgoCD_reconcileArgoConfigMap_withExternalDex(t *testing.T) {
+	restoreEnv(t)
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.SSO = &argoprojv1alpha1.ArgoCDSSOSpec{
+			Dex: &argoprojv1alpha1.ArgoCDSSODexSpec{
+				External: &argoprojv1alpha1.ArgoCDDexExternalSpec{
+					ClientSecret: "argocd-dex-client",
+					IssuerURL:    "https://dex.example.com",
+				},
+			},
+		}
+	})
+	clientSecret := argoutil.NewSecretWithName(a.ObjectMeta, "argocd-dex-client")
+	clientSecret.Data = map[string][]byte{
+		common.ArgoCDKeyDexExternalClientID:     []byte("argo-cd"),
+		common.ArgoCDKeyDexExternalClientSecret: []byte("s3cr3t"),
+	}
+	cm := newConfigMapWithName(common.ArgoCDConfigMapName, a)
+	cm.Data = map[string]string{
+		common.ArgoCDKeyDexConfig: common.ArgoCDDefaultDexConfig,
+	}
+	r := makeTestReconciler(t, a, clientSecret, cm)
+
+	assert.NilError(t, r.reconcileArgoConfigMap(a))
+
+	cm = &corev1.ConfigMap{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{
+		Name:      common.ArgoCDConfigMapName,
+		Namespace: testNamespace,
+	}, cm))
+	_, ok := cm.Data[common.ArgoCDKeyDexConfig]
+	assert.Assert(t, !ok)
+
+	oidc := DexExternalOIDCConfig{}
+	assert.NilError(t, yaml.Unmarshal([]byte(cm.Data[common.ArgoCDKeyOIDCConfig]), &oidc))
+	assert.Equal(t, oidc.Issuer, "https://dex.example.com")
+	assert.Equal(t, oidc.ClientID, "argo-cd")
+	assert.Equal(t, oidc.ClientSecret, "$oidc.dex.clientSecret")
+
+	// The managed Dex is not installed.
+	assert.NilError(t, r.reconcileDexDeployment(a))
+	deploy := newDeploymentWithSuffix("dex-server", "dex-server", a)
+	assert.Assert(t, !argoutil.IsObjectFound(r.client, a.Namespace, deploy.Name, deploy))
+}
+
+func TestReconcileArgoCD_reconcileGrafanaDashboards_external(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	os.Setenv("GRAFANA_CONFIG_PATH", "../../../grafana")
+	defer os.Unsetenv("GRAFANA_CONFIG_PATH")
+
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.Grafana.Enabled = true
+		a.Spec.Grafana.External = &argoprojv1alpha1.ArgoCDGrafanaExternalSpec{URL: "https://grafana.example.com"}
+	})
+	r := makeTestReconciler(t, a)
+
+	assert.NilError(t, r.reconcileGrafanaConfiguration(a))
+	assert.Assert(t, !argoutil.IsObjectFound(r.client, testNamespace, "argocd-"+common.ArgoCDGrafanaConfigMapSuffix, &corev1.ConfigMap{}))
+
+	assert.NilError(t, r.reconcileGrafanaDashboards(a))
+	dashboards := &corev1.ConfigMap{}
+	assert.NilError(t, argoutil.FetchObject(r.client, testNamespace, "argocd-"+common.ArgoCDGrafanaDashboardConfigMapSuffix, dashboards))
+	assert.Equal(t, dashboards.Labels[common.ArgoCDKeyGrafanaDashboard], "1")
+	assert.Assert(t, strings.Contains(dashboards.Data["argocd.json"], `"datasource": "argocd-argocd-prometheus"`))
+	assert.Assert(t, !strings.Contains(dashboards.Data["argocd.json"], `"datasource": "Prometheus"`))
+
+	assert.NilError(t, r.reconcileGrafanaDatasource(a))
+	datasource := &corev1.ConfigMap{}
+	assert.NilError(t, argoutil.FetchObject(r.client, testNamespace, "argocd-"+common.ArgoCDGrafanaDatasourceConfigMapSuffix, datasource))
+	assert.Equal(t, datasource.Labels[common.ArgoCDKeyGrafanaDatasource], "1")
+	assert.Assert(t, strings.Contains(datasource.Data["datasource.yaml"], "url: http://prometheus-operated.argocd.svc:9090"))
+
+	// Switching to the managed Grafana restores the default datasource of the dashboards.
+	a.Spec.Grafana.External = nil
+	assert.NilError(t, r.reconcileGrafanaDashboards(a))
+	dashboards = &corev1.ConfigMap{}
+	assert.NilError(t, argoutil.FetchObject(r.client, testNamespace, "argocd-"+common.ArgoCDGrafanaDashboardConfigMapSuffix, dashboards))
+	_, ok := dashboards.Labels[common.ArgoCDKeyGrafanaDashboard]
+	assert.Assert(t, !ok)
+	assert.Assert(t, strings.Contains(dashboards.Data["argocd.json"], `"datasource": "Prometheus"`))
+}
+
+func TestReconcileArgoCD_reconcileArgoConfigMap_withExternalGrafana(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.Version = "v2.6.0"
+		a.Spec.Grafana.Enabled = true
+		a.Spec.Grafana.External = &argoprojv1alpha1.ArgoCDGrafanaExternalSpec{URL: "https://grafana.example.com/"}
+	})
+	r := makeTestReconciler(t, a)
+
+	assert.NilError(t, r.reconcileArgoConfigMap(a))
+	cm := &corev1.ConfigMap{}
+	assert.NilError(t, argoutil.FetchObject(r.client, testNamespace, common.ArgoCDConfigMapName, cm))
+	want := "- title: Grafana\n  url: https://grafana.example.com/d/BjWwX3jik/argocd?var-namespace=argocd\n"
+	assert.Equal(t, cm.Data[common.ArgoCDKeyApplicationLinks], want)
+
+	// The deep links are not supported by older versions of Argo CD.
+	a.Spec.Version = "v2.5.0"
+	assert.NilError(t, r.reconcileArgoConfigMap(a))
+	cm = &corev1.ConfigMap{}
+	assert.NilError(t, argoutil.FetchObject(r.client, testNamespace, common.ArgoCDConfigMapName, cm))
+	_, ok := cm.Data[common.ArgoCDKeyApplicationLinks]
+	assert.Assert(t, !ok)
+}
+
+func TestReconcileArgoCD_reconcileRedisHAConfigMap_withRedisConfig(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	os.Setenv("REDIS_CONFIG_PATH", "../../../build/redis")
+	t.Cleanup(func() {
+		os.Unsetenv("REDIS_CONFIG_PATH")
+	})
+
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.HA.Enabled = true
+	})
+	r := makeTestReconciler(t, a)
+	assert.NilError(t, r.reconcileRedisHAConfigMap(a))
+
+	cm := &corev1.ConfigMap{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: common.ArgoCDRedisHAConfigMapName, Namespace: testNamespace}, cm))
+	assert.Assert(t, strings.Contains(cm.Data["sentinel.conf"], "down-after-milliseconds argocd 10000"))
+	assert.Assert(t, strings.Contains(cm.Data["sentinel.conf"], "failover-timeout argocd 180000"))
+	assert.Assert(t, strings.Contains(cm.Data["init.sh"], `QUORUM="2"`))
+	assert.Assert(t, !strings.Contains(cm.Data["haproxy.cfg"], "announce-3"))
+	assert.Assert(t, strings.Contains(cm.Data["haproxy.cfg"], "timeout server 6m\n    timeout client 6m\n"))
+	assert.Assert(t, strings.Contains(cm.Data["haproxy.cfg"], "announce-0:6379 check inter 3s fall 1 rise 1"))
+	assert.Assert(t, strings.Contains(cm.Data["redis.conf"], "maxmemory 0\nmaxmemory-policy volatile-lru\n"))
+	assert.Assert(t, strings.Contains(cm.Data["redis.conf"], "save \"\"\n"))
+
+	// Tuning the sentinel and adding announce services updates the existing ConfigMap
+	a.Spec.HA.RedisConfig = argoprojv1alpha1.ArgoCDHARedisConfigSpec{
+		AnnounceServices:      5,
+		DownAfterMilliseconds: 5000,
+		FailoverTimeout:       60000,
+	}
+	assert.NilError(t, r.reconcileRedisHAConfigMap(a))
+
+	cm = &corev1.ConfigMap{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: common.ArgoCDRedisHAConfigMapName, Namespace: testNamespace}, cm))
+	assert.Assert(t, strings.Contains(cm.Data["sentinel.conf"], "down-after-milliseconds argocd 5000"))
+	assert.Assert(t, strings.Contains(cm.Data["sentinel.conf"], "failover-timeout argocd 60000"))
+	assert.Assert(t, strings.Contains(cm.Data["init.sh"], `QUORUM="3"`))
+	assert.Assert(t, strings.Contains(cm.Data["haproxy.cfg"], "argocd-redis-ha-announce-4"))
+	assert.Assert(t, strings.Contains(cm.Data["haproxy_init.sh"], "argocd-redis-ha-announce-4"))
+
+	// Tuning the timeouts and health checks of the Redis HAProxy updates the existing ConfigMap
+	a.Spec.HA.HAProxy = argoprojv1alpha1.ArgoCDHAProxySpec{
+		CheckInterval:  &metav1.Duration{Duration: 1500 * time.Millisecond},
+		TimeoutClient:  &metav1.Duration{Duration: time.Hour},
+		TimeoutConnect: &metav1.Duration{Duration: 10 * time.Second},
+		TimeoutServer:  &metav1.Duration{Duration: 90 * time.Minute},
+	}
+	assert.NilError(t, r.reconcileRedisHAConfigMap(a))
+
+	cm = &corev1.ConfigMap{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: common.ArgoCDRedisHAConfigMapName, Namespace: testNamespace}, cm))
+	assert.Assert(t, strings.Contains(cm.Data["haproxy.cfg"], "timeout connect 10s\n    timeout server 90m\n    timeout client 1h\n    timeout check 2s\n"))
+	assert.Assert(t, strings.Contains(cm.Data["haproxy.cfg"], "announce-0:26379 check inter 1500ms\n"))
+	assert.Assert(t, strings.Contains(cm.Data["haproxy.cfg"], "announce-0:6379 check inter 1500ms fall 1 rise 1"))
+
+	// Configuring the memory and snapshots of Redis updates the existing ConfigMap
+	a.Spec.Redis.Config = argoprojv1alpha1.ArgoCDRedisConfigSpec{
+		MaxMemory:       "2gb",
+		MaxMemoryPolicy: "allkeys-lfu",
+		Save:            []argoprojv1alpha1.ArgoCDRedisSaveSpec{{Seconds: 900, Changes: 1}, {Seconds: 60, Changes: 10000}},
+	}
+	assert.NilError(t, r.reconcileRedisHAConfigMap(a))
+
+	cm = &corev1.ConfigMap{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: common.ArgoCDRedisHAConfigMapName, Namespace: testNamespace}, cm))
+	assert.Assert(t, strings.Contains(cm.Data["redis.conf"], "maxmemory 2gb\nmaxmemory-policy allkeys-lfu\n"))
+	assert.Assert(t, strings.Contains(cm.Data["redis.conf"], "save 900 1\nsave 60 10000\nprotected-mode no"))
+	assert.Assert(t, !strings.Contains(cm.Data["redis.conf"], "save \"\""))
+}
+
+func TestReconcileArgoCD_reconcileArgoConfigMap_withDexGroups(t *testing.T) {
+	restoreEnv(t)
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.SSO = &argoprojv1alpha1.ArgoCDSSOSpec{
+			Provider: argoprojv1alpha1.SSOProviderTypeDex,
+			Dex: &argoprojv1alpha1.ArgoCDSSODexSpec{
+				ArgoCDDexSpec: argoprojv1alpha1.ArgoCDDexSpec{
+					Config: "connectors:\n- type: openshift\n  id: openshift\n  name: OpenShift\n  config:\n    clientID: argocd\n- type: github\n  id: github\n  name: GitHub\n",
+				},
+				Groups: &argoprojv1alpha1.ArgoCDDexGroupsSpec{Allowed: []string{"platform-team"}},
+			},
+		}
+	})
+	r := makeTestReconciler(t, a)
+
+	assert.NilError(t, r.reconcileArgoConfigMap(a))
+
+	cm := &corev1.ConfigMap{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{
+		Name:      common.ArgoCDConfigMapName,
+		Namespace: testNamespace,
+	}, cm))
+
+	m := make(map[string]interface{})
+	assert.NilError(t, yaml.Unmarshal([]byte(cm.Data["dex.config"]), &m))
+	connectors := m["connectors"].([]interface{})
+	openshift := connectors[0].(map[interface{}]interface{})["config"].(map[interface{}]interface{})
+	assert.Equal(t, openshift["clientID"], "argocd")
+	assert.DeepEqual(t, openshift["groups"], []interface{}{"platform-team"})
+	_, ok := connectors[1].(map[interface{}]interface{})["config"]
+	assert.Assert(t, !ok)
+}
+
+func TestReconcileArgoCD_reconcileRBAC_withDexGroups(t *testing.T) {
+	restoreEnv(t)
+	logf.SetLogger(logf.ZapLogger(true))
+	policy := "p, role:deployer, applications, sync, */*, allow"
+	scopes := "[email]"
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.RBAC.Policy = &policy
+		a.Spec.RBAC.Scopes = &scopes
+	})
+	r := makeTestReconciler(t, a)
+	assert.NilError(t, r.reconcileRBAC(a))
+
+	a.Spec.SSO = &argoprojv1alpha1.ArgoCDSSOSpec{
+		Provider: argoprojv1alpha1.SSOProviderTypeDex,
+		Dex: &argoprojv1alpha1.ArgoCDSSODexSpec{
+			Groups: &argoprojv1alpha1.ArgoCDDexGroupsSpec{
+				Bindings: []argoprojv1alpha1.ArgoCDDexGroupBindingSpec{
+					{Group: "platform-team", Role: "role:admin"},
+					{Group: "developers", Role: "role:deployer"},
+				},
+			},
+		},
+	}
+	assert.NilError(t, r.reconcileRBAC(a))
+
+	cm := &corev1.ConfigMap{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{
+		Name:      common.ArgoCDRBACConfigMapName,
+		Namespace: testNamespace,
+	}, cm))
+	assert.Equal(t, cm.Data[common.ArgoCDKeyRBACPolicyCSV], policy+"\ng, platform-team, role:admin\ng, developers, role:deployer\n")
+	assert.Equal(t, cm.Data[common.ArgoCDKeyRBACScopes], "[email,groups]")
+
+	// Removing the Dex groups removes their policy lines.
+	a.Spec.SSO = nil
+	a.Spec.RBAC.Policy = nil
+	assert.NilError(t, r.reconcileRBAC(a))
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{
+		Name:      common.ArgoCDRBACConfigMapName,
+		Namespace: testNamespace,
+	}, cm))
+	assert.Equal(t, cm.Data[common.ArgoCDKeyRBACPolicyCSV], common.ArgoCDDefaultRBACPolicy)
+	assert.Equal(t, cm.Data[common.ArgoCDKeyRBACScopes], scopes)
+}
+
+func TestReconcileArgoCD_reconcileArgoConfigMap_withSessionSettings(t *testing.T) {
+	restoreEnv(t)
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.Dex.OpenShiftOAuth = false
+		a.Spec.Dex.Config = "connectors:\n- type: github\n  id: github\n  name: GitHub\n"
+		a.Spec.OIDCInsecureSkipVerify = true
+		a.Spec.Server.Session.Duration = &metav1.Duration{Duration: 4 * time.Hour}
+		a.Spec.Server.Session.IDTokenExpiration = &metav1.Duration{Duration: 30 * time.Minute}
+	})
+	r := makeTestReconciler(t, a)
+
+	assert.NilError(t, r.reconcileArgoConfigMap(a))
+
+	cm := &corev1.ConfigMap{}
+	key := types.NamespacedName{Name: common.ArgoCDConfigMapName, Namespace: testNamespace}
+	assert.NilError(t, r.client.Get(context.TODO(), key, cm))
+	assert.Equal(t, cm.Data[common.ArgoCDKeyUsersSessionDuration], "4h0m0s")
+	assert.Equal(t, cm.Data[common.ArgoCDKeyOIDCInsecureSkipVerify], "true")
+
+	m := make(map[string]interface{})
+	assert.NilError(t, yaml.Unmarshal([]byte(cm.Data[common.ArgoCDKeyDexConfig]), &m))
+	assert.Equal(t, m["expiry"].(map[interface{}]interface{})["idTokens"], "30m0s")
+
+	a.Spec.OIDCInsecureSkipVerify = false
+	a.Spec.Server.Session = argoprojv1alpha1.ArgoCDServerSessionSpec{}
+	assert.NilError(t, r.reconcileArgoConfigMap(a))
+
+	cm = &corev1.ConfigMap{}
+	assert.NilError(t, r.client.Get(context.TODO(), key, cm))
+	_, ok := cm.Data[common.ArgoCDKeyUsersSessionDuration]
+	assert.Assert(t, !ok)
+	_, ok = cm.Data[common.ArgoCDKeyOIDCInsecureSkipVerify]
+	assert.Assert(t, !ok)
+	assert.Assert(t, !strings.Contains(cm.Data[common.ArgoCDKeyDexConfig], "expiry"))
+}
@@ -0,0 +1,144 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocd
+
+import (
+	"context"
+	"fmt"
+
+	consolev1 "github.com/openshift/api/console/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
+	"github.com/argoproj-labs/argocd-operator/pkg/controller/argoutil"
+)
+
+var consoleAPIFound = false
+
+// IsConsoleAPIAvailable returns true if the OpenShift Console API is present.
+func IsConsoleAPIAvailable() bool {
+	return consoleAPIFound
+}
+
+// verifyConsoleAPI will verify that the OpenShift Console API is present.
+func verifyConsoleAPI() error {
+	found, err := argoutil.VerifyAPI(consolev1.GroupName, consolev1.GroupVersion.Version)
+	if err != nil {
+		return err
+	}
+	consoleAPIFound = found
+	return nil
+}
+
+// newConsoleLink returns a new ConsoleLink instance for the given ArgoCD. ConsoleLinks are cluster scoped, so the
+// name includes the namespace of the ArgoCD.
+func newConsoleLink(cr *argoprojv1a1.ArgoCD) *consolev1.ConsoleLink {
+	name := GenerateUniqueResourceName("server", cr)
+	lbls := labelsForCluster(cr)
+	lbls[common.ArgoCDKeyName] = name
+	return &consolev1.ConsoleLink{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: lbls,
+		},
+	}
+}
+
+// getConsoleLinkImageURL will return the URL of the icon for the ConsoleLink of the given ArgoCD.
+func getConsoleLinkImageURL(cr *argoprojv1a1.ArgoCD) string {
+	url := common.ArgoCDDefaultConsoleLinkImageURL
+	if len(cr.Spec.Server.ConsoleLink.ImageURL) > 0 {
+		url = cr.Spec.Server.ConsoleLink.ImageURL
+	}
+	return url
+}
+
+// getConsoleLinkSection will return the application menu section for the ConsoleLink of the given ArgoCD.
+func getConsoleLinkSection(cr *argoprojv1a1.ArgoCD) string {
+	section := common.ArgoCDDefaultConsoleLinkSection
+	if len(cr.Spec.Server.ConsoleLink.Section) > 0 {
+		section = cr.Spec.Server.ConsoleLink.Section
+	}
+	return section
+}
+
+// getConsoleLinkText will return the display text for the ConsoleLink of the given ArgoCD.
+func getConsoleLinkText(cr *argoprojv1a1.ArgoCD) string {
+	text := fmt.Sprintf("%s (%s)", cr.Name, cr.Namespace)
+	if len(cr.Spec.Server.ConsoleLink.Text) > 0 {
+		text = cr.Spec.Server.ConsoleLink.Text
+	}
+	return text
+}
+
+// reconcileConsoleLink will ensure that the ConsoleLink pointing at the Argo CD Server Route is present when the
+// Route is enabled for the given ArgoCD.
+func (r *ReconcileArgoCD) reconcileConsoleLink(cr *argoprojv1a1.ArgoCD) error {
+	host := ""
+	route := newRouteWithSuffix("server", cr)
+	if cr.Spec.Server.Route.Enabled && argoutil.IsObjectFound(r.client, cr.Namespace, route.Name, route) {
+		host = route.Spec.Host
+	}
+
+	link := newConsoleLink(cr)
+	exists := argoutil.IsObjectFound(r.client, "", link.Name, link)
+	if len(host) == 0 {
+		if exists {
+			// ConsoleLink exists but the Route is disabled or not admitted yet, delete the ConsoleLink
+			return r.client.Delete(context.TODO(), link)
+		}
+		return nil // Route not enabled, do nothing.
+	}
+
+	spec := consolev1.ConsoleLinkSpec{
+		Link: consolev1.Link{
+			Href: fmt.Sprintf("https://%s", host),
+			Text: getConsoleLinkText(cr),
+		},
+		Location: consolev1.ApplicationMenu,
+		ApplicationMenu: &consolev1.ApplicationMenuSpec{
+			ImageURL: getConsoleLinkImageURL(cr),
+			Section:  getConsoleLinkSection(cr),
+		},
+	}
+
+	if exists {
+		if link.Spec.Href == spec.Href && link.Spec.Text == spec.Text && link.Spec.Location == spec.Location &&
+			link.Spec.ApplicationMenu != nil && *link.Spec.ApplicationMenu == *spec.ApplicationMenu {
+			return nil // ConsoleLink found with nothing changed, move along...
+		}
+		link.Spec = spec
+		return r.client.Update(context.TODO(), link)
+	}
+
+	link.Spec = spec
+	return r.client.Create(context.TODO(), link)
+}
+
+// deleteConsoleLink will delete the ConsoleLink for the given ArgoCD, if present. ConsoleLinks are cluster scoped
+// and cannot be garbage collected through an owner reference to the ArgoCD.
+func (r *ReconcileArgoCD) deleteConsoleLink(cr *argoprojv1a1.ArgoCD) error {
+	link := newConsoleLink(cr)
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: link.Name}, link); err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	return r.client.Delete(context.TODO(), link)
+}
@@ -0,0 +1,68 @@
+package argocd
+
+import (
+	"context"
+	"testing"
+
+	consolev1 "github.com/openshift/api/console/v1"
+	routev1 "github.com/openshift/api/route/v1"
+	"gotest.tools/assert"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+
+	argoprojv1alpha1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
+)
+
+func TestReconcileArgoCD_reconcileConsoleLink(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	routev1.Install(scheme.Scheme)
+	consolev1.Install(scheme.Scheme)
+
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.Server.Route.Enabled = true
+	})
+	route := newRouteWithSuffix("server", a)
+	route.Spec.Host = "argocd.apps.example.com"
+	r := makeTestReconciler(t, a, route)
+
+	assert.NilError(t, r.reconcileConsoleLink(a))
+
+	link := &consolev1.ConsoleLink{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-argocd-server"}, link))
+	assert.Equal(t, link.Spec.Href, "https://argocd.apps.example.com")
+	assert.Equal(t, link.Spec.Text, "argocd (argocd)")
+	assert.Equal(t, link.Spec.Location, consolev1.ApplicationMenu)
+	assert.DeepEqual(t, link.Spec.ApplicationMenu, &consolev1.ApplicationMenuSpec{
+		ImageURL: common.ArgoCDDefaultConsoleLinkImageURL,
+		Section:  common.ArgoCDDefaultConsoleLinkSection,
+	})
+
+	a.Spec.Server.ConsoleLink.Text = "Team Argo CD"
+	assert.NilError(t, r.reconcileConsoleLink(a))
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-argocd-server"}, link))
+	assert.Equal(t, link.Spec.Text, "Team Argo CD")
+
+	a.Spec.Server.Route.Enabled = false
+	assert.NilError(t, r.reconcileConsoleLink(a))
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-argocd-server"}, link)
+	assert.Assert(t, errors.IsNotFound(err))
+}
+
+func TestReconcileArgoCD_deleteConsoleLink(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	consolev1.Install(scheme.Scheme)
+
+	a := makeTestArgoCD()
+	link := newConsoleLink(a)
+	r := makeTestReconciler(t, a, link)
+
+	assert.NilError(t, r.deleteConsoleLink(a))
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: link.Name}, link)
+	assert.Assert(t, errors.IsNotFound(err))
+
+	// Deleting a missing ConsoleLink is not an error
+	assert.NilError(t, r.deleteConsoleLink(a))
+}
@@ -9,6 +9,8 @@ import (
 	"github.com/argoproj-labs/argocd-operator/pkg/common"
 
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -98,6 +100,237 @@ func (r *ReconcileArgoCD) tlsSecretMapper(o handler.MapObject) []reconcile.Reque
 	return result
 }
 
+// dexTokenSecretMapper maps a watch event on the token secret of the Dex
+// ServiceAccount back to the ArgoCD object that uses it as the OpenShift OAuth
+// client secret.
+func (r *ReconcileArgoCD) dexTokenSecretMapper(o handler.MapObject) []reconcile.Request {
+	var result = []reconcile.Request{}
+
+	secret, ok := o.Object.(*corev1.Secret)
+	if !ok || secret.Type != corev1.SecretTypeServiceAccountToken {
+		return result
+	}
+
+	// The ServiceAccount for Dex is named '<argocd>-argocd-dex-server'
+	saName := secret.Annotations[corev1.ServiceAccountNameKey]
+	suffix := "-" + common.ArgoCDDefaultDexServiceAccountName
+	if !strings.HasSuffix(saName, suffix) {
+		return result
+	}
+
+	namespacedArgoCDObject := client.ObjectKey{
+		Name:      strings.TrimSuffix(saName, suffix),
+		Namespace: o.Meta.GetNamespace(),
+	}
+
+	argocd := &argoprojv1alpha1.ArgoCD{}
+	if err := r.client.Get(context.TODO(), namespacedArgoCDObject, argocd); err != nil {
+		return result
+	}
+
+	if getDexSpec(argocd).OpenShiftOAuth {
+		result = []reconcile.Request{
+			{NamespacedName: namespacedArgoCDObject},
+		}
+	}
+	return result
+}
+
+// dexConfigSecretMapper maps a watch event on a Secret that the Dex configuration may reference, with the
+// "$<name>:<key>" syntax, back to the ArgoCD objects with Dex in its namespace, so that Dex is restarted when the
+// referenced values change.
+func (r *ReconcileArgoCD) dexConfigSecretMapper(o handler.MapObject) []reconcile.Request {
+	var result = []reconcile.Request{}
+
+	argocds := &argoprojv1alpha1.ArgoCDList{}
+	if err := r.client.List(context.TODO(), argocds, &client.ListOptions{Namespace: o.Meta.GetNamespace()}); err != nil {
+		return result
+	}
+
+	for _, argocd := range argocds.Items {
+		if isManagedDexEnabled(&argocd) {
+			result = append(result, reconcile.Request{
+				NamespacedName: client.ObjectKey{Name: argocd.Name, Namespace: argocd.Namespace},
+			})
+		}
+	}
+	return result
+}
+
+// serverTLSSecretMapper maps a watch event on an existing Secret back to the
+// ArgoCD objects that use it as the certificate for the Argo CD Server, so
+// that a rotated certificate is picked up.
+func (r *ReconcileArgoCD) serverTLSSecretMapper(o handler.MapObject) []reconcile.Request {
+	var result = []reconcile.Request{}
+
+	argocds := &argoprojv1alpha1.ArgoCDList{}
+	if err := r.client.List(context.TODO(), argocds, &client.ListOptions{Namespace: o.Meta.GetNamespace()}); err != nil {
+		return result
+	}
+
+	for _, argocd := range argocds.Items {
+		if argocd.Spec.Server.TLS.SecretName == o.Meta.GetName() {
+			result = append(result, reconcile.Request{
+				NamespacedName: client.ObjectKey{Name: argocd.Name, Namespace: argocd.Namespace},
+			})
+		}
+	}
+	return result
+}
+
+// secretKeysMapper maps a watch event on a Secret back to the ArgoCD objects
+// that source keys of the Argo CD Secret from it, so that the keys are updated
+// when the Secret changes.
+func (r *ReconcileArgoCD) secretKeysMapper(o handler.MapObject) []reconcile.Request {
+	var result = []reconcile.Request{}
+
+	argocds := &argoprojv1alpha1.ArgoCDList{}
+	if err := r.client.List(context.TODO(), argocds, &client.ListOptions{Namespace: o.Meta.GetNamespace()}); err != nil {
+		return result
+	}
+
+	for _, argocd := range argocds.Items {
+		for _, ref := range argocd.Spec.SecretKeys {
+			if ref.SecretName == o.Meta.GetName() {
+				result = append(result, reconcile.Request{
+					NamespacedName: client.ObjectKey{Name: argocd.Name, Namespace: argocd.Namespace},
+				})
+				break
+			}
+		}
+	}
+	return result
+}
+
+// resourceHealthChecksMapper maps a watch event on a ConfigMap back to the
+// ArgoCD objects that import Lua health checks from it, so that the resource
+// customizations are updated when the health checks change.
+func (r *ReconcileArgoCD) resourceHealthChecksMapper(o handler.MapObject) []reconcile.Request {
+	var result = []reconcile.Request{}
+
+	argocds := &argoprojv1alpha1.ArgoCDList{}
+	if err := r.client.List(context.TODO(), argocds, &client.ListOptions{Namespace: o.Meta.GetNamespace()}); err != nil {
+		return result
+	}
+
+	for _, argocd := range argocds.Items {
+		for _, ref := range argocd.Spec.ResourceHealthChecksFrom {
+			if ref.Name == o.Meta.GetName() {
+				result = append(result, reconcile.Request{
+					NamespacedName: client.ObjectKey{Name: argocd.Name, Namespace: argocd.Namespace},
+				})
+				break
+			}
+		}
+	}
+	return result
+}
+
+// tlsCertsMapper maps a watch event on a ConfigMap back to the ArgoCD
+// objects that add the TLS certificates in it to the argocd-tls-certs-cm
+// ConfigMap, so that the certificates are updated when the ConfigMap changes.
+func (r *ReconcileArgoCD) tlsCertsMapper(o handler.MapObject) []reconcile.Request {
+	var result = []reconcile.Request{}
+
+	argocds := &argoprojv1alpha1.ArgoCDList{}
+	if err := r.client.List(context.TODO(), argocds, &client.ListOptions{Namespace: o.Meta.GetNamespace()}); err != nil {
+		return result
+	}
+
+	for _, argocd := range argocds.Items {
+		for _, ref := range argocd.Spec.TLS.CertsFrom {
+			if ref.Name == o.Meta.GetName() {
+				result = append(result, reconcile.Request{
+					NamespacedName: client.ObjectKey{Name: argocd.Name, Namespace: argocd.Namespace},
+				})
+				break
+			}
+		}
+	}
+	return result
+}
+
+// disasterRecoverySecretMapper maps a watch event on a Secret back to the ArgoCD objects that mirror it into a
+// disaster recovery bundle, or that own the disaster recovery bundle, so that the bundle is kept up to date.
+func (r *ReconcileArgoCD) disasterRecoverySecretMapper(o handler.MapObject) []reconcile.Request {
+	var result = []reconcile.Request{}
+
+	secret, ok := o.Object.(*corev1.Secret)
+	if !ok {
+		return result
+	}
+
+	if _, ok := secret.Annotations[common.ArgoCDDisasterRecoveryChecksumAnnotation]; ok {
+		return r.clusterResourceMapper(o)
+	}
+
+	argocds := &argoprojv1alpha1.ArgoCDList{}
+	if err := r.client.List(context.TODO(), argocds, &client.ListOptions{Namespace: o.Meta.GetNamespace()}); err != nil {
+		return result
+	}
+
+	for _, argocd := range argocds.Items {
+		if argocd.Spec.DisasterRecovery != nil &&
+			getDisasterRecoveryMode(&argocd) == common.ArgoCDDisasterRecoveryModeMirror &&
+			isDisasterRecoverySecret(&argocd, secret) {
+			result = append(result, reconcile.Request{
+				NamespacedName: client.ObjectKey{Name: argocd.Name, Namespace: argocd.Namespace},
+			})
+		}
+	}
+	return result
+}
+
+// clusterDiscoverySecretMapper maps a watch event on a Secret back to the ArgoCD objects that select it for cluster
+// discovery.
+func (r *ReconcileArgoCD) clusterDiscoverySecretMapper(o handler.MapObject) []reconcile.Request {
+	var result = []reconcile.Request{}
+
+	argocds := &argoprojv1alpha1.ArgoCDList{}
+	if err := r.client.List(context.TODO(), argocds, &client.ListOptions{Namespace: o.Meta.GetNamespace()}); err != nil {
+		return result
+	}
+
+	for _, argocd := range argocds.Items {
+		if argocd.Spec.ClusterDiscovery.SecretSelector == nil {
+			continue
+		}
+		selector, err := metav1.LabelSelectorAsSelector(argocd.Spec.ClusterDiscovery.SecretSelector)
+		if err != nil {
+			continue
+		}
+		if selector.Matches(labels.Set(o.Meta.GetLabels())) {
+			result = append(result, reconcile.Request{
+				NamespacedName: client.ObjectKey{Name: argocd.Name, Namespace: argocd.Namespace},
+			})
+		}
+	}
+	return result
+}
+
+// helmOCIRegistrySecretMapper maps a watch event on a Secret back to the ArgoCD objects that use it as the credentials
+// of a Helm OCI registry, so that the repository credentials are updated when the Secret changes.
+func (r *ReconcileArgoCD) helmOCIRegistrySecretMapper(o handler.MapObject) []reconcile.Request {
+	var result = []reconcile.Request{}
+
+	argocds := &argoprojv1alpha1.ArgoCDList{}
+	if err := r.client.List(context.TODO(), argocds, &client.ListOptions{Namespace: o.Meta.GetNamespace()}); err != nil {
+		return result
+	}
+
+	for _, argocd := range argocds.Items {
+		for _, registry := range argocd.Spec.HelmOCIRegistries {
+			if registry.CredentialsSecret == o.Meta.GetName() {
+				result = append(result, reconcile.Request{
+					NamespacedName: client.ObjectKey{Name: argocd.Name, Namespace: argocd.Namespace},
+				})
+				break
+			}
+		}
+	}
+	return result
+}
+
 // namespaceResourceMapper maps a watch event on a namespace, back to the
 // ArgoCD object that we want to reconcile.
 func (r *ReconcileArgoCD) namespaceResourceMapper(o handler.MapObject) []reconcile.Request {
@@ -126,3 +359,57 @@ func (r *ReconcileArgoCD) namespaceResourceMapper(o handler.MapObject) []reconci
 
 	return result
 }
+
+// argoCDDefaultMapper maps a watch event on the ArgoCDDefault of the cluster back to every ArgoCD object, as they all
+// inherit its defaults.
+func (r *ReconcileArgoCD) argoCDDefaultMapper(o handler.MapObject) []reconcile.Request {
+	var result = []reconcile.Request{}
+
+	if o.Meta.GetName() != common.ArgoCDDefaultResourceName {
+		return result
+	}
+	return r.allArgoCDRequests()
+}
+
+// defaultImagesMapper maps a change of the default images mounted in the operator to all ArgoCD objects, so that they
+// are rolled out with the new default images.
+func (r *ReconcileArgoCD) defaultImagesMapper(o handler.MapObject) []reconcile.Request {
+	return r.allArgoCDRequests()
+}
+
+// allArgoCDRequests will return a reconcile request for each ArgoCD object.
+func (r *ReconcileArgoCD) allArgoCDRequests() []reconcile.Request {
+	var result = []reconcile.Request{}
+
+	argocds := &argoprojv1alpha1.ArgoCDList{}
+	if err := r.client.List(context.TODO(), argocds); err != nil {
+		return result
+	}
+
+	for _, argocd := range argocds.Items {
+		result = append(result, reconcile.Request{
+			NamespacedName: client.ObjectKey{Name: argocd.Name, Namespace: argocd.Namespace},
+		})
+	}
+	return result
+}
+
+// applicationMapper maps a watch event on an Application back to the ArgoCD objects in the same namespace that
+// summarize the health and sync status of their Applications.
+func (r *ReconcileArgoCD) applicationMapper(o handler.MapObject) []reconcile.Request {
+	var result = []reconcile.Request{}
+
+	argocds := &argoprojv1alpha1.ArgoCDList{}
+	if err := r.client.List(context.TODO(), argocds, &client.ListOptions{Namespace: o.Meta.GetNamespace()}); err != nil {
+		return result
+	}
+
+	for _, argocd := range argocds.Items {
+		if isApplicationHealthEnabled(&argocd) {
+			result = append(result, reconcile.Request{
+				NamespacedName: client.ObjectKey{Name: argocd.Name, Namespace: argocd.Namespace},
+			})
+		}
+	}
+	return result
+}
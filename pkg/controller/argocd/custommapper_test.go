@@ -16,7 +16,9 @@ import (
 
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
@@ -416,3 +418,224 @@ func TestReconcileArgoCD_namespaceResourceMapper(t *testing.T) {
 		})
 	}
 }
+
+func TestReconcileArgoCD_dexTokenSecretMapper(t *testing.T) {
+	a := makeTestArgoCD(func(a *v1alpha1.ArgoCD) {
+		a.Spec.Dex.OpenShiftOAuth = true
+	})
+	r := makeTestReconciler(t, a)
+
+	tokenSecret := func(saName string, secretType corev1.SecretType) handler.MapObject {
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      saName + "-token-abcde",
+				Namespace: a.Namespace,
+				Annotations: map[string]string{
+					corev1.ServiceAccountNameKey: saName,
+				},
+			},
+			Type: secretType,
+		}
+		return handler.MapObject{Meta: secret, Object: secret}
+	}
+
+	want := []reconcile.Request{
+		{
+			NamespacedName: types.NamespacedName{
+				Name:      a.Name,
+				Namespace: a.Namespace,
+			},
+		},
+	}
+	got := r.dexTokenSecretMapper(tokenSecret("argocd-argocd-dex-server", corev1.SecretTypeServiceAccountToken))
+	assert.DeepEqual(t, got, want)
+
+	got = r.dexTokenSecretMapper(tokenSecret("argocd-argocd-dex-server", corev1.SecretTypeOpaque))
+	assert.DeepEqual(t, got, []reconcile.Request{})
+
+	got = r.dexTokenSecretMapper(tokenSecret("argocd-argocd-server", corev1.SecretTypeServiceAccountToken))
+	assert.DeepEqual(t, got, []reconcile.Request{})
+
+	a.Spec.Dex.OpenShiftOAuth = false
+	assert.NilError(t, r.client.Update(context.TODO(), a))
+	got = r.dexTokenSecretMapper(tokenSecret("argocd-argocd-dex-server", corev1.SecretTypeServiceAccountToken))
+	assert.DeepEqual(t, got, []reconcile.Request{})
+}
+
+func TestReconcileArgoCD_dexConfigSecretMapper(t *testing.T) {
+	a := makeTestArgoCD(func(a *v1alpha1.ArgoCD) {
+		a.Spec.Dex.OpenShiftOAuth = true
+	})
+	r := makeTestReconciler(t, a)
+
+	secret := func(namespace string) handler.MapObject {
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "github-connector",
+				Namespace: namespace,
+				Labels: map[string]string{
+					common.ArgoCDKeyPartOf: common.ArgoCDAppName,
+				},
+			},
+		}
+		return handler.MapObject{Meta: secret, Object: secret}
+	}
+
+	want := []reconcile.Request{
+		{
+			NamespacedName: types.NamespacedName{
+				Name:      a.Name,
+				Namespace: a.Namespace,
+			},
+		},
+	}
+	got := r.dexConfigSecretMapper(secret(a.Namespace))
+	assert.DeepEqual(t, got, want)
+
+	got = r.dexConfigSecretMapper(secret("other-namespace"))
+	assert.DeepEqual(t, got, []reconcile.Request{})
+
+	a.Spec.SSO = &v1alpha1.ArgoCDSSOSpec{Provider: v1alpha1.SSOProviderTypeKeycloak}
+	assert.NilError(t, r.client.Update(context.TODO(), a))
+	got = r.dexConfigSecretMapper(secret(a.Namespace))
+	assert.DeepEqual(t, got, []reconcile.Request{})
+}
+
+func TestReconcileArgoCD_serverTLSSecretMapper(t *testing.T) {
+	a := makeTestArgoCD(func(a *v1alpha1.ArgoCD) {
+		a.Spec.Server.TLS.SecretName = "server-cert"
+	})
+	r := makeTestReconciler(t, a)
+
+	secret := func(name, namespace string) handler.MapObject {
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+			},
+			Type: corev1.SecretTypeTLS,
+		}
+		return handler.MapObject{Meta: secret, Object: secret}
+	}
+
+	want := []reconcile.Request{
+		{
+			NamespacedName: types.NamespacedName{
+				Name:      a.Name,
+				Namespace: a.Namespace,
+			},
+		},
+	}
+	got := r.serverTLSSecretMapper(secret("server-cert", a.Namespace))
+	assert.DeepEqual(t, got, want)
+
+	got = r.serverTLSSecretMapper(secret("other-cert", a.Namespace))
+	assert.DeepEqual(t, got, []reconcile.Request{})
+
+	got = r.serverTLSSecretMapper(secret("server-cert", "other-namespace"))
+	assert.DeepEqual(t, got, []reconcile.Request{})
+}
+
+func TestReconcileArgoCD_resourceHealthChecksMapper(t *testing.T) {
+	a := makeTestArgoCD(func(a *v1alpha1.ArgoCD) {
+		a.Spec.ResourceHealthChecksFrom = []v1alpha1.ConfigMapRef{{Name: "team-a-health"}, {Name: "team-b-health"}}
+	})
+	r := makeTestReconciler(t, a)
+
+	configMap := func(name, namespace string) handler.MapObject {
+		cm := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+			},
+		}
+		return handler.MapObject{Meta: cm, Object: cm}
+	}
+
+	want := []reconcile.Request{
+		{
+			NamespacedName: types.NamespacedName{
+				Name:      a.Name,
+				Namespace: a.Namespace,
+			},
+		},
+	}
+	got := r.resourceHealthChecksMapper(configMap("team-b-health", a.Namespace))
+	assert.DeepEqual(t, got, want)
+
+	got = r.resourceHealthChecksMapper(configMap("other", a.Namespace))
+	assert.DeepEqual(t, got, []reconcile.Request{})
+
+	got = r.resourceHealthChecksMapper(configMap("team-a-health", "other-namespace"))
+	assert.DeepEqual(t, got, []reconcile.Request{})
+}
+
+func TestReconcileArgoCD_helmOCIRegistrySecretMapper(t *testing.T) {
+	a := makeTestArgoCD(func(a *v1alpha1.ArgoCD) {
+		a.Spec.HelmOCIRegistries = []v1alpha1.ArgoCDHelmOCIRegistrySpec{
+			{Registry: "registry.example.com/charts", CredentialsSecret: "registry-credentials"},
+		}
+	})
+	r := makeTestReconciler(t, a)
+
+	secret := func(name, namespace string) handler.MapObject {
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+			},
+		}
+		return handler.MapObject{Meta: secret, Object: secret}
+	}
+
+	want := []reconcile.Request{
+		{
+			NamespacedName: types.NamespacedName{
+				Name:      a.Name,
+				Namespace: a.Namespace,
+			},
+		},
+	}
+	got := r.helmOCIRegistrySecretMapper(secret("registry-credentials", a.Namespace))
+	assert.DeepEqual(t, got, want)
+
+	got = r.helmOCIRegistrySecretMapper(secret("other", a.Namespace))
+	assert.DeepEqual(t, got, []reconcile.Request{})
+
+	got = r.helmOCIRegistrySecretMapper(secret("registry-credentials", "other-namespace"))
+	assert.DeepEqual(t, got, []reconcile.Request{})
+}
+
+func TestReconcileArgoCD_clusterDiscoverySecretMapper(t *testing.T) {
+	a := makeTestArgoCD(func(a *v1alpha1.ArgoCD) {
+		a.Spec.ClusterDiscovery.SecretSelector = &metav1.LabelSelector{
+			MatchLabels: map[string]string{"example.com/cluster": "true"},
+		}
+	})
+	r := makeTestReconciler(t, a)
+
+	selected := makeTestKubeconfigSecret("prod")
+	deselected := selected.DeepCopy()
+	deselected.Labels = nil
+
+	want := []reconcile.Request{
+		{
+			NamespacedName: types.NamespacedName{
+				Name:      a.Name,
+				Namespace: a.Namespace,
+			},
+		},
+	}
+	got := r.clusterDiscoverySecretMapper(handler.MapObject{Meta: selected, Object: selected})
+	assert.DeepEqual(t, got, want)
+
+	got = r.clusterDiscoverySecretMapper(handler.MapObject{Meta: deselected, Object: deselected})
+	assert.DeepEqual(t, got, []reconcile.Request{})
+
+	// Removing the label of a selected Secret is mapped through the old object.
+	q := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+	defer q.ShutDown()
+	h := &handler.EnqueueRequestsFromMapFunc{ToRequests: handler.ToRequestsFunc(r.clusterDiscoverySecretMapper)}
+	h.Update(event.UpdateEvent{MetaOld: selected, ObjectOld: selected, MetaNew: deselected, ObjectNew: deselected}, q)
+	assert.Equal(t, q.Len(), 1)
+}
@@ -0,0 +1,57 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocd
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	"github.com/argoproj-labs/argocd-operator/pkg/controller/argoutil"
+)
+
+// defaultImagesPollInterval is the interval at which the default images ConfigMap mounted in the operator is checked
+// for changes. The kubelet refreshes the files of a mounted ConfigMap about once a minute.
+const defaultImagesPollInterval = time.Minute
+
+// pollDefaultImages will refresh the cached default images and send an event on the given channel each time the images
+// held by the default images ConfigMap mounted in the operator change, so that all ArgoCD clusters are rolled out with
+// the new default images. It returns once the given stop channel is closed.
+func pollDefaultImages(events chan<- event.GenericEvent, interval time.Duration, stop <-chan struct{}) {
+	argoutil.RefreshDefaultImages()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if !argoutil.RefreshDefaultImages() {
+				continue
+			}
+			log.Info("default images changed, reconciling all ArgoCD clusters")
+
+			cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: argoutil.GetDefaultImagesPath()}}
+			select {
+			case events <- event.GenericEvent{Meta: cm, Object: cm}:
+			case <-stop:
+				return
+			}
+		}
+	}
+}
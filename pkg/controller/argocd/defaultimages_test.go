@@ -0,0 +1,46 @@
+package argocd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
+)
+
+func TestPollDefaultImages(t *testing.T) {
+	dir, err := ioutil.TempDir("", "default-images")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+	restoreEnv(t)
+	os.Setenv(common.ArgoCDDefaultImagesPathEnvName, dir)
+
+	events := make(chan event.GenericEvent)
+	stop := make(chan struct{})
+	defer close(stop)
+	go pollDefaultImages(events, 10*time.Millisecond, stop)
+
+	// Keep changing the image until the change is picked up, the poller may read the ConfigMap after the first write.
+	image := ""
+	timeout := time.After(5 * time.Second)
+	for i := 0; image == ""; i++ {
+		img := fmt.Sprintf("redis:6.2.%d", i)
+		assert.NilError(t, ioutil.WriteFile(filepath.Join(dir, common.ArgoCDRedisImageEnvName), []byte(img), 0644))
+		select {
+		case <-events:
+			image = img
+		case <-time.After(50 * time.Millisecond):
+		case <-timeout:
+			t.Fatal("no event sent for the changed default images")
+		}
+	}
+
+	a := makeTestArgoCD()
+	assert.Equal(t, getRedisContainerImage(a), image)
+}
@@ -0,0 +1,277 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
+	"github.com/argoproj-labs/argocd-operator/pkg/controller/argoutil"
+)
+
+const (
+	// deletionStepInterval is the delay after which the removal of a component is checked again while an ArgoCD is
+	// being deleted.
+	deletionStepInterval = 5 * time.Second
+
+	// deletionTimeout is the time after the deletion of an ArgoCD was requested past which the operator stops waiting
+	// for each component to be gone before removing the next one. The Application Controller is still kept while it
+	// has Applications to finalize.
+	deletionTimeout = 5 * time.Minute
+
+	// deletionBlockedReasonFinalizingApplications is the DeletionBlocked condition reason when Applications still
+	// wait for the Application Controller to process their finalizers.
+	deletionBlockedReasonFinalizingApplications = "FinalizingApplications"
+)
+
+// deletionStep describes an Argo CD component removed while its ArgoCD is being deleted, before the components it
+// depends on.
+type deletionStep struct {
+	name    string
+	objects func(cr *argoprojv1a1.ArgoCD) []runtime.Object
+	// wait returns true while the component must be kept running, the component is kept even once the deletion
+	// timeout has passed.
+	wait func(r *ReconcileArgoCD, cr *argoprojv1a1.ArgoCD) (bool, error)
+}
+
+// deletionSteps are the components removed in order while an ArgoCD is being deleted. The Server goes first so that no
+// new operations are started, and Redis goes last so that the Application Controller and Repo Server never observe it
+// missing. Other resources are garbage collected once the deletion finalizer is removed.
+var deletionSteps = []deletionStep{
+	{
+		name: "server",
+		objects: func(cr *argoprojv1a1.ArgoCD) []runtime.Object {
+			return []runtime.Object{newDeploymentWithSuffix("server", "server", cr)}
+		},
+	},
+	{
+		name: "application-controller",
+		objects: func(cr *argoprojv1a1.ArgoCD) []runtime.Object {
+			return []runtime.Object{newStatefulSetWithSuffix("application-controller", "application-controller", cr)}
+		},
+		wait: (*ReconcileArgoCD).applyDeletionPolicy,
+	},
+	{
+		name: "repo-server",
+		objects: func(cr *argoprojv1a1.ArgoCD) []runtime.Object {
+			return []runtime.Object{newDeploymentWithSuffix("repo-server", "repo-server", cr)}
+		},
+	},
+	{
+		name: "redis",
+		objects: func(cr *argoprojv1a1.ArgoCD) []runtime.Object {
+			return []runtime.Object{
+				newDeploymentWithSuffix("redis-ha-haproxy", "redis", cr),
+				newStatefulSetWithSuffix("redis-ha-server", "redis", cr),
+				newDeploymentWithSuffix("redis", "redis", cr),
+			}
+		},
+	},
+}
+
+// deleteComponentsInOrder will remove the components of the given ArgoCD, which is being deleted, one step at a time.
+// The delay after which the deletion should be checked again is returned while a component is still being removed,
+// zero is returned once all of them are gone. Past the deletion timeout, the components are removed without waiting
+// for the previous ones to be gone, but a component that must be kept running is never removed; the DeletionBlocked
+// condition is set instead.
+func (r *ReconcileArgoCD) deleteComponentsInOrder(cr *argoprojv1a1.ArgoCD) (time.Duration, error) {
+	timedOut := cr.GetDeletionTimestamp() != nil && time.Since(cr.GetDeletionTimestamp().Time) > deletionTimeout
+
+	for _, step := range deletionSteps {
+		if step.wait != nil {
+			wait, err := step.wait(r, cr)
+			if err != nil {
+				return 0, err
+			}
+			if wait {
+				log.Info(fmt.Sprintf("waiting to delete %s for %s", step.name, cr.Name))
+				if timedOut {
+					if err := r.setDeletionBlockedCondition(cr, step.name); err != nil {
+						return 0, err
+					}
+				}
+				return deletionStepInterval, nil
+			}
+		}
+
+		remaining := false
+		for _, obj := range step.objects(cr) {
+			found, err := r.deleteComponentObject(cr, obj)
+			if err != nil {
+				return 0, fmt.Errorf("failed to delete %s for %s: %w", step.name, cr.Name, err)
+			}
+			remaining = remaining || found
+		}
+		if remaining && !timedOut {
+			log.Info(fmt.Sprintf("waiting for %s of %s to be deleted", step.name, cr.Name))
+			return deletionStepInterval, nil
+		}
+	}
+	return 0, nil
+}
+
+// setDeletionBlockedCondition will report that the deletion of the given ArgoCD waits past the deletion timeout for
+// the given component to finalize the Applications of the ArgoCD.
+func (r *ReconcileArgoCD) setDeletionBlockedCondition(cr *argoprojv1a1.ArgoCD, component string) error {
+	changed := setArgoCDCondition(cr, argoprojv1a1.ArgoCDCondition{
+		Type:   argoprojv1a1.ArgoCDConditionTypeDeletionBlocked,
+		Status: corev1.ConditionTrue,
+		Reason: deletionBlockedReasonFinalizingApplications,
+		Message: fmt.Sprintf("the %s is kept until the finalizers of the Applications being deleted are processed, "+
+			"remove the finalizers or set spec.deletionPolicy to %s to proceed", component, common.ArgoCDDeletionPolicyStripFinalizers),
+	})
+	if !changed {
+		return nil
+	}
+	return r.updateArgoCDStatus(cr)
+}
+
+// deleteComponentObject will request the foreground deletion of the given object of the given ArgoCD, so that it is
+// only gone once its pods are. True is returned while the object still exists.
+func (r *ReconcileArgoCD) deleteComponentObject(cr *argoprojv1a1.ArgoCD, obj runtime.Object) (bool, error) {
+	m, err := meta.Accessor(obj)
+	if err != nil {
+		return false, err
+	}
+	if err := argoutil.FetchObject(r.client, cr.Namespace, m.GetName(), obj); err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	if m.GetDeletionTimestamp() != nil {
+		return true, nil
+	}
+
+	log.Info(fmt.Sprintf("deleting %s for %s", m.GetName(), cr.Name))
+	if err := r.client.Delete(context.TODO(), obj, client.PropagationPolicy(metav1.DeletePropagationForeground)); err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// getDeletionPolicy will return the policy applied to the Applications and AppProjects of the given ArgoCD when it is
+// deleted.
+func getDeletionPolicy(cr *argoprojv1a1.ArgoCD) string {
+	if cr.Spec.DeletionPolicy == "" {
+		return common.ArgoCDDeletionPolicyOrphan
+	}
+	return cr.Spec.DeletionPolicy
+}
+
+// applyDeletionPolicy will apply the deletion policy of the given ArgoCD to the Applications and AppProjects in its
+// namespace. True is returned while the Application Controller must be kept running to process their finalizers.
+func (r *ReconcileArgoCD) applyDeletionPolicy(cr *argoprojv1a1.ArgoCD) (bool, error) {
+	switch getDeletionPolicy(cr) {
+	case common.ArgoCDDeletionPolicyCascade:
+		// AppProjects are only deleted once the Applications using them are gone.
+		remaining, err := r.deleteArgoCDObjects(cr, applicationGVK)
+		if err != nil || remaining {
+			return remaining, err
+		}
+		return r.deleteArgoCDObjects(cr, appProjectGVK)
+	case common.ArgoCDDeletionPolicyStripFinalizers:
+		for _, gvk := range []schema.GroupVersionKind{applicationGVK, appProjectGVK} {
+			if err := r.stripArgoCDObjectFinalizers(cr, gvk); err != nil {
+				return false, err
+			}
+		}
+	}
+	return r.hasFinalizingApplications(cr)
+}
+
+// listArgoCDObjects will return the objects of the given Argo CD kind in the namespace of the given ArgoCD, or none when
+// the kind is not installed.
+func (r *ReconcileArgoCD) listArgoCDObjects(cr *argoprojv1a1.ArgoCD, gvk schema.GroupVersionKind) ([]unstructured.Unstructured, error) {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+	if err := r.client.List(context.TODO(), list, client.InNamespace(cr.Namespace)); err != nil {
+		if meta.IsNoMatchError(err) || runtime.IsNotRegisteredError(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to list %s objects for %s: %w", gvk.Kind, cr.Name, err)
+	}
+	return list.Items, nil
+}
+
+// deleteArgoCDObjects will delete the objects of the given Argo CD kind in the namespace of the given ArgoCD. True is
+// returned while any of them still exists.
+func (r *ReconcileArgoCD) deleteArgoCDObjects(cr *argoprojv1a1.ArgoCD, gvk schema.GroupVersionKind) (bool, error) {
+	objs, err := r.listArgoCDObjects(cr, gvk)
+	if err != nil {
+		return false, err
+	}
+	for i := range objs {
+		if objs[i].GetDeletionTimestamp() != nil {
+			continue
+		}
+		log.Info(fmt.Sprintf("deleting %s %s for %s", gvk.Kind, objs[i].GetName(), cr.Name))
+		if err := r.client.Delete(context.TODO(), &objs[i]); err != nil && !errors.IsNotFound(err) {
+			return false, fmt.Errorf("failed to delete %s %s for %s: %w", gvk.Kind, objs[i].GetName(), cr.Name, err)
+		}
+	}
+	return len(objs) > 0, nil
+}
+
+// stripArgoCDObjectFinalizers will remove the finalizers from the objects of the given Argo CD kind in the namespace of
+// the given ArgoCD, so that they do not block the deletion of the namespace once the Application Controller is gone.
+func (r *ReconcileArgoCD) stripArgoCDObjectFinalizers(cr *argoprojv1a1.ArgoCD, gvk schema.GroupVersionKind) error {
+	objs, err := r.listArgoCDObjects(cr, gvk)
+	if err != nil {
+		return err
+	}
+	for i := range objs {
+		if len(objs[i].GetFinalizers()) == 0 {
+			continue
+		}
+		log.Info(fmt.Sprintf("removing finalizers from %s %s for %s", gvk.Kind, objs[i].GetName(), cr.Name))
+		patch := client.MergeFrom(objs[i].DeepCopy())
+		objs[i].SetFinalizers(nil)
+		if err := r.client.Patch(context.TODO(), &objs[i], patch); err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to remove finalizers from %s %s for %s: %w", gvk.Kind, objs[i].GetName(), cr.Name, err)
+		}
+	}
+	return nil
+}
+
+// hasFinalizingApplications will return true while an Application in the namespace of the given ArgoCD is being
+// deleted and still has a finalizer for the Application Controller to process.
+func (r *ReconcileArgoCD) hasFinalizingApplications(cr *argoprojv1a1.ArgoCD) (bool, error) {
+	apps, err := r.listArgoCDObjects(cr, applicationGVK)
+	if err != nil {
+		return false, err
+	}
+	for _, app := range apps {
+		if app.GetDeletionTimestamp() != nil && len(app.GetFinalizers()) > 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,187 @@
+package argocd
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+
+	argoprojv1alpha1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
+	"github.com/argoproj-labs/argocd-operator/pkg/controller/argoutil"
+)
+
+func TestReconcileArgoCD_deleteComponentsInOrder(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD(deletedAt(time.Now()), addFinalizer(common.ArgoCDDeletionFinalizer))
+
+	server := newDeploymentWithSuffix("server", "server", a)
+	controller := newStatefulSetWithSuffix("application-controller", "application-controller", a)
+	repo := newDeploymentWithSuffix("repo-server", "repo-server", a)
+	redis := newDeploymentWithSuffix("redis", "redis", a)
+
+	app := makeTestArgoCDObject(applicationGVK, a.Namespace, "guestbook", "resources-finalizer.argocd.argoproj.io")
+	deleted := metav1.NewTime(time.Now())
+	app.SetDeletionTimestamp(&deleted)
+
+	r := makeTestReconciler(t, a, server, controller, repo, redis, app)
+
+	found := func(obj runtime.Object) bool {
+		m, err := meta.Accessor(obj)
+		assert.NilError(t, err)
+		return argoutil.IsObjectFound(r.client, a.Namespace, m.GetName(), obj)
+	}
+
+	// The Server is removed first.
+	delay, err := r.deleteComponentsInOrder(a)
+	assert.NilError(t, err)
+	assert.Equal(t, delay, deletionStepInterval)
+	assert.Assert(t, !found(server))
+	assert.Assert(t, found(controller))
+
+	// The Application Controller is kept while an Application is being finalized.
+	delay, err = r.deleteComponentsInOrder(a)
+	assert.NilError(t, err)
+	assert.Equal(t, delay, deletionStepInterval)
+	assert.Assert(t, found(controller))
+
+	assert.NilError(t, r.client.Delete(context.TODO(), app))
+	components := []runtime.Object{controller, repo, redis}
+	for i := range components {
+		delay, err = r.deleteComponentsInOrder(a)
+		assert.NilError(t, err)
+		assert.Equal(t, delay, deletionStepInterval)
+		assert.Assert(t, !found(components[i]))
+		for _, later := range components[i+1:] {
+			assert.Assert(t, found(later))
+		}
+	}
+
+	delay, err = r.deleteComponentsInOrder(a)
+	assert.NilError(t, err)
+	assert.Equal(t, delay, time.Duration(0))
+}
+
+func TestReconcileArgoCD_deleteComponentsInOrder_timeout(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD(deletedAt(time.Now().Add(-deletionTimeout-time.Minute)), addFinalizer(common.ArgoCDDeletionFinalizer))
+	server := newDeploymentWithSuffix("server", "server", a)
+	redis := newDeploymentWithSuffix("redis", "redis", a)
+	r := makeTestReconciler(t, a, server, redis)
+
+	// Past the timeout, all components are removed without waiting between the steps.
+	delay, err := r.deleteComponentsInOrder(a)
+	assert.NilError(t, err)
+	assert.Equal(t, delay, time.Duration(0))
+	assert.Assert(t, !argoutil.IsObjectFound(r.client, a.Namespace, server.Name, server))
+	assert.Assert(t, !argoutil.IsObjectFound(r.client, a.Namespace, redis.Name, redis))
+}
+
+func TestReconcileArgoCD_deleteComponentsInOrder_timeoutFinalizingApplications(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD(deletedAt(time.Now().Add(-deletionTimeout-time.Minute)), addFinalizer(common.ArgoCDDeletionFinalizer))
+	server := newDeploymentWithSuffix("server", "server", a)
+	controller := newStatefulSetWithSuffix("application-controller", "application-controller", a)
+	app := makeTestArgoCDObject(applicationGVK, a.Namespace, "guestbook", "resources-finalizer.argocd.argoproj.io")
+	deleted := metav1.NewTime(time.Now())
+	app.SetDeletionTimestamp(&deleted)
+	r := makeTestReconciler(t, a, server, controller, app)
+
+	// Past the timeout, the Application Controller is still kept while an Application is being finalized.
+	delay, err := r.deleteComponentsInOrder(a)
+	assert.NilError(t, err)
+	assert.Equal(t, delay, deletionStepInterval)
+	assert.Assert(t, !argoutil.IsObjectFound(r.client, a.Namespace, server.Name, server))
+	assert.Assert(t, argoutil.IsObjectFound(r.client, a.Namespace, controller.Name, controller))
+
+	condition := getArgoCDCondition(a, argoprojv1alpha1.ArgoCDConditionTypeDeletionBlocked)
+	assert.Assert(t, condition != nil)
+	assert.Equal(t, condition.Status, corev1.ConditionTrue)
+	assert.Equal(t, condition.Reason, deletionBlockedReasonFinalizingApplications)
+}
+
+func makeTestArgoCDObject(gvk schema.GroupVersionKind, namespace, name string, finalizers ...string) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{}
+	u.SetGroupVersionKind(gvk)
+	u.SetNamespace(namespace)
+	u.SetName(name)
+	u.SetFinalizers(finalizers)
+	return u
+}
+
+func TestReconcileArgoCD_applyDeletionPolicy(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	const finalizer = "resources-finalizer.argocd.argoproj.io"
+
+	count := func(r *ReconcileArgoCD, gvk schema.GroupVersionKind) int {
+		objs, err := r.listArgoCDObjects(makeTestArgoCD(), gvk)
+		assert.NilError(t, err)
+		return len(objs)
+	}
+
+	t.Run("Orphan", func(t *testing.T) {
+		a := makeTestArgoCD(deletedAt(time.Now()))
+		r := makeTestReconciler(t, a,
+			makeTestArgoCDObject(applicationGVK, a.Namespace, "guestbook", finalizer),
+			makeTestArgoCDObject(appProjectGVK, a.Namespace, "default"))
+
+		wait, err := r.applyDeletionPolicy(a)
+		assert.NilError(t, err)
+		assert.Assert(t, !wait)
+		assert.Equal(t, count(r, applicationGVK), 1)
+		assert.Equal(t, count(r, appProjectGVK), 1)
+	})
+
+	t.Run("Cascade", func(t *testing.T) {
+		a := makeTestArgoCD(deletedAt(time.Now()), func(a *argoprojv1alpha1.ArgoCD) {
+			a.Spec.DeletionPolicy = common.ArgoCDDeletionPolicyCascade
+		})
+		r := makeTestReconciler(t, a,
+			makeTestArgoCDObject(applicationGVK, a.Namespace, "guestbook", finalizer),
+			makeTestArgoCDObject(appProjectGVK, a.Namespace, "default"))
+
+		// The AppProjects are kept until the Applications are gone.
+		wait, err := r.applyDeletionPolicy(a)
+		assert.NilError(t, err)
+		assert.Assert(t, wait)
+		assert.Equal(t, count(r, applicationGVK), 0)
+		assert.Equal(t, count(r, appProjectGVK), 1)
+
+		wait, err = r.applyDeletionPolicy(a)
+		assert.NilError(t, err)
+		assert.Assert(t, wait)
+		assert.Equal(t, count(r, appProjectGVK), 0)
+
+		wait, err = r.applyDeletionPolicy(a)
+		assert.NilError(t, err)
+		assert.Assert(t, !wait)
+	})
+
+	t.Run("StripFinalizers", func(t *testing.T) {
+		a := makeTestArgoCD(deletedAt(time.Now()), func(a *argoprojv1alpha1.ArgoCD) {
+			a.Spec.DeletionPolicy = common.ArgoCDDeletionPolicyStripFinalizers
+		})
+		app := makeTestArgoCDObject(applicationGVK, a.Namespace, "guestbook", finalizer)
+		deleted := metav1.NewTime(time.Now())
+		app.SetDeletionTimestamp(&deleted)
+		r := makeTestReconciler(t, a, app, makeTestArgoCDObject(appProjectGVK, a.Namespace, "default", "example.com/finalizer"))
+
+		wait, err := r.applyDeletionPolicy(a)
+		assert.NilError(t, err)
+		assert.Assert(t, !wait)
+		for _, gvk := range []schema.GroupVersionKind{applicationGVK, appProjectGVK} {
+			objs, err := r.listArgoCDObjects(a, gvk)
+			assert.NilError(t, err)
+			assert.Equal(t, len(objs), 1)
+			assert.Equal(t, len(objs[0].GetFinalizers()), 0)
+		}
+	})
+}
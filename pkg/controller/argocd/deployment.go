@@ -52,11 +52,7 @@ func (r *ReconcileArgoCD) getArgoCDExport(cr *argoprojv1a1.ArgoCD) *argoprojv1a1
 }
 
 func getArgoExportSecretName(export *argoprojv1a1.ArgoCDExport) string {
-	name := argoutil.NameWithSuffix(export.ObjectMeta, "export")
-	if export.Spec.Storage != nil && len(export.Spec.Storage.SecretName) > 0 {
-		name = export.Spec.Storage.SecretName
-	}
-	return name
+	return argoutil.FetchStorageSecretName(export)
 }
 
 func getArgoImportBackend(client client.Client, cr *argoprojv1a1.ArgoCD) string {
@@ -120,6 +116,10 @@ func getArgoImportContainerEnv(cr *argoprojv1a1.ArgoCDExport) []corev1.EnvVar {
 
 // getArgoImportContainerImage will return the container image for the Argo CD import process.
 func getArgoImportContainerImage(cr *argoprojv1a1.ArgoCDExport) string {
+	if len(cr.Spec.Image) == 0 && len(cr.Spec.Version) == 0 {
+		return getDefaultExportJobImage()
+	}
+
 	img := common.ArgoCDDefaultExportJobImage
 	if len(cr.Spec.Image) > 0 {
 		img = cr.Spec.Image
@@ -133,6 +133,15 @@ func getArgoImportContainerImage(cr *argoprojv1a1.ArgoCDExport) string {
 	return argoutil.CombineImageTag(img, tag)
 }
 
+// getDefaultExportJobImage will return the container image of the export job utilities, used for the import and the
+// SSH known hosts scan, when none is set.
+func getDefaultExportJobImage() string {
+	if e := argoutil.GetDefaultImage(common.ArgoCDExportJobImageEnvName); e != "" {
+		return e
+	}
+	return argoutil.CombineImageTag(common.ArgoCDDefaultExportJobImage, common.ArgoCDDefaultExportJobVersion)
+}
+
 // getArgoImportVolumeMounts will return the VolumneMounts for the given ArgoCDExport.
 func getArgoImportVolumeMounts(cr *argoprojv1a1.ArgoCDExport) []corev1.VolumeMount {
 	mounts := make([]corev1.VolumeMount, 0)
@@ -194,9 +203,56 @@ func getArgoRepoCommand(cr *argoprojv1a1.ArgoCD) []string {
 	cmd = append(cmd, "--redis")
 	cmd = append(cmd, getRedisServerAddress(cr))
 
+	if cr.Spec.Repo.CacheExpiration != nil {
+		cmd = append(cmd, "--repo-cache-expiration")
+		cmd = append(cmd, cr.Spec.Repo.CacheExpiration.Duration.String())
+	}
+
+	if level := getDefaultLogLevel(); level != "" {
+		cmd = append(cmd, "--loglevel")
+		cmd = append(cmd, level)
+	}
+
 	return cmd
 }
 
+// getArgoRepoEnv will return the environment variables for the ArgoCD Repo component.
+func getArgoRepoEnv(cr *argoprojv1a1.ArgoCD) []corev1.EnvVar {
+	env := make([]corev1.EnvVar, 0)
+
+	retry := cr.Spec.Repo.GitRetry
+	if retry.Attempts > 0 {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ArgoCDGitAttemptsCountEnvName,
+			Value: fmt.Sprint(retry.Attempts),
+		})
+	}
+	if retry.Duration != nil {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ArgoCDGitRetryDurationEnvName,
+			Value: retry.Duration.Duration.String(),
+		})
+	}
+	if retry.Factor > 0 {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ArgoCDGitRetryFactorEnvName,
+			Value: fmt.Sprint(retry.Factor),
+		})
+	}
+	if retry.MaxDuration != nil {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ArgoCDGitRetryMaxDurationEnvName,
+			Value: retry.MaxDuration.Duration.String(),
+		})
+	}
+
+	if cr.Spec.Repo.SOPS.Enabled {
+		env = append(env, getSOPSRepoEnv()...)
+	}
+
+	return proxyEnvVars(cr, env...)
+}
+
 // getArgoServerCommand will return the command for the ArgoCD server component.
 func getArgoServerCommand(cr *argoprojv1a1.ArgoCD) []string {
 	cmd := make([]string, 0)
@@ -222,6 +278,34 @@ func getArgoServerCommand(cr *argoprojv1a1.ArgoCD) []string {
 	cmd = append(cmd, "--redis")
 	cmd = append(cmd, getRedisServerAddress(cr))
 
+	if level := getDefaultLogLevel(); level != "" {
+		cmd = append(cmd, "--loglevel")
+		cmd = append(cmd, level)
+	}
+
+	if cr.Spec.Server.GRPCWebRootPath != "" {
+		cmd = append(cmd, "--grpc-web-root-path")
+		cmd = append(cmd, cr.Spec.Server.GRPCWebRootPath)
+	}
+
+	if cr.Spec.Server.EnableGZip {
+		cmd = append(cmd, "--enable-gzip")
+	}
+
+	if isProxyExtensionEnabled(cr) {
+		cmd = append(cmd, "--enable-proxy-extension")
+	}
+
+	if cr.Spec.Server.TLS.MinVersion != "" {
+		cmd = append(cmd, "--tlsminversion")
+		cmd = append(cmd, cr.Spec.Server.TLS.MinVersion)
+	}
+
+	if len(cr.Spec.Server.TLS.Ciphers) > 0 {
+		cmd = append(cmd, "--tlsciphers")
+		cmd = append(cmd, strings.Join(cr.Spec.Server.TLS.Ciphers, ":"))
+	}
+
 	return cmd
 }
 
@@ -324,8 +408,9 @@ func (r *ReconcileArgoCD) reconcileDexDeployment(cr *argoprojv1a1.ArgoCD) error
 		},
 		Image:           getDexContainerImage(cr),
 		ImagePullPolicy: corev1.PullAlways,
+		LivenessProbe:   getDexLivenessProbe(cr),
 		Name:            "dex",
-		Env:             proxyEnvVars(),
+		Env:             proxyEnvVars(cr),
 		Ports: []corev1.ContainerPort{
 			{
 				ContainerPort: common.ArgoCDDefaultDexHTTPPort,
@@ -335,7 +420,8 @@ func (r *ReconcileArgoCD) reconcileDexDeployment(cr *argoprojv1a1.ArgoCD) error
 				Name:          "grpc",
 			},
 		},
-		Resources: getDexResources(cr),
+		ReadinessProbe: getDexReadinessProbe(cr),
+		Resources:      getDexResources(cr),
 		VolumeMounts: []corev1.VolumeMount{{
 			Name:      "static-files",
 			MountPath: "/shared",
@@ -349,27 +435,51 @@ func (r *ReconcileArgoCD) reconcileDexDeployment(cr *argoprojv1a1.ArgoCD) error
 			"/usr/local/bin/argocd",
 			"/shared/argocd-dex",
 		},
-		Env:             proxyEnvVars(),
+		Env:             proxyEnvVars(cr),
 		Image:           getArgoContainerImage(cr),
 		ImagePullPolicy: corev1.PullAlways,
 		Name:            "copyutil",
-		Resources:       getDexResources(cr),
+		Resources:       getInitContainerResources(getDexSpec(cr).InitContainerResources, getDexResources(cr)),
 		VolumeMounts: []corev1.VolumeMount{{
 			Name:      "static-files",
 			MountPath: "/shared",
 		}},
 	}}
 
-	deploy.Spec.Template.Spec.ServiceAccountName = fmt.Sprintf("%s-%s", cr.Name, common.ArgoCDDefaultDexServiceAccountName)
+	deploy.Spec.Template.ObjectMeta.Annotations = map[string]string{
+		dexConfigChecksumAnnotation: r.getDexConfigChecksum(cr),
+	}
+	deploy.Spec.Template.Spec.ServiceAccountName = getServiceAccountName(dexServer, cr)
+	dex := getDexSpec(cr)
+	deploy.Spec.Template.Spec.AutomountServiceAccountToken = dex.AutomountServiceAccountToken
 	deploy.Spec.Template.Spec.Volumes = []corev1.Volume{{
 		Name: "static-files",
 		VolumeSource: corev1.VolumeSource{
 			EmptyDir: &corev1.EmptyDirVolumeSource{},
 		},
 	}}
-	dexDisabled := isDexDisabled()
+	if dex.StandaloneImage {
+		// The Dex image provides the argocd-dex binary, there is nothing to copy from the Argo CD image.
+		deploy.Spec.Template.Spec.Containers[0].Command = []string{"argocd-dex", "rundex"}
+		deploy.Spec.Template.Spec.Containers[0].VolumeMounts = nil
+		deploy.Spec.Template.Spec.InitContainers = nil
+		deploy.Spec.Template.Spec.Volumes = nil
+	}
+	setPodDNS(&deploy.Spec.Template.Spec, dex.HostAliases, dex.DNSConfig, dex.DNSPolicy)
+	if err := applyReconcilerHook(cr, deploy, ""); err != nil {
+		return err
+	}
+	setContainerCommand(&deploy.Spec.Template, dex.CommandOverride)
+
+	dexDisabled := !isManagedDexEnabled(cr)
 	if dexDisabled {
-		log.Info("reconciling for dex, but dex is disabled")
+		log.Info("reconciling for dex, but managed dex is disabled")
+	}
+
+	if !dexDisabled {
+		if err := r.reconcilePodSecurityCompliance(cr, "Deployment/"+deploy.Name, &deploy.Spec.Template); err != nil {
+			return err
+		}
 	}
 
 	existing := newDeploymentWithSuffix("dex-server", "dex-server", cr)
@@ -389,12 +499,32 @@ func (r *ReconcileArgoCD) reconcileDexDeployment(cr *argoprojv1a1.ArgoCD) error
 			changed = true
 		}
 
-		actualImage = existing.Spec.Template.Spec.InitContainers[0].Image
-		desiredImage = getArgoContainerImage(cr)
-		if actualImage != desiredImage {
-			existing.Spec.Template.Spec.InitContainers[0].Image = desiredImage
-			existing.Spec.Template.ObjectMeta.Labels["image.upgraded"] = time.Now().UTC().Format("01022006-150406-MST")
+		if len(existing.Spec.Template.Spec.InitContainers) != len(deploy.Spec.Template.Spec.InitContainers) {
+			// Switching to or from a standalone Dex image adds or removes the copyutil init container and its volume.
+			existing.Spec.Template.Spec.InitContainers = deploy.Spec.Template.Spec.InitContainers
+			existing.Spec.Template.Spec.Volumes = deploy.Spec.Template.Spec.Volumes
+			existing.Spec.Template.Spec.Containers[0].VolumeMounts = deploy.Spec.Template.Spec.Containers[0].VolumeMounts
 			changed = true
+		} else if len(existing.Spec.Template.Spec.InitContainers) > 0 {
+			actualImage = existing.Spec.Template.Spec.InitContainers[0].Image
+			desiredImage = getArgoContainerImage(cr)
+			if actualImage != desiredImage {
+				existing.Spec.Template.Spec.InitContainers[0].Image = desiredImage
+				existing.Spec.Template.ObjectMeta.Labels["image.upgraded"] = time.Now().UTC().Format("01022006-150406-MST")
+				changed = true
+			}
+
+			if !reflect.DeepEqual(existing.Spec.Template.Spec.InitContainers[0].Env,
+				deploy.Spec.Template.Spec.InitContainers[0].Env) {
+				existing.Spec.Template.Spec.InitContainers[0].Env = deploy.Spec.Template.Spec.InitContainers[0].Env
+				changed = true
+			}
+
+			if !reflect.DeepEqual(existing.Spec.Template.Spec.InitContainers[0].Resources,
+				deploy.Spec.Template.Spec.InitContainers[0].Resources) {
+				existing.Spec.Template.Spec.InitContainers[0].Resources = deploy.Spec.Template.Spec.InitContainers[0].Resources
+				changed = true
+			}
 		}
 
 		if !reflect.DeepEqual(existing.Spec.Template.Spec.Containers[0].Env,
@@ -403,9 +533,37 @@ func (r *ReconcileArgoCD) reconcileDexDeployment(cr *argoprojv1a1.ArgoCD) error
 			changed = true
 		}
 
-		if !reflect.DeepEqual(existing.Spec.Template.Spec.InitContainers[0].Env,
-			deploy.Spec.Template.Spec.InitContainers[0].Env) {
-			existing.Spec.Template.Spec.InitContainers[0].Env = deploy.Spec.Template.Spec.InitContainers[0].Env
+		if updateContainerCommand(&existing.Spec.Template, &deploy.Spec.Template) {
+			changed = true
+		}
+
+		if !reflect.DeepEqual(existing.Spec.Template.Spec.Containers[0].LivenessProbe,
+			deploy.Spec.Template.Spec.Containers[0].LivenessProbe) {
+			existing.Spec.Template.Spec.Containers[0].LivenessProbe = deploy.Spec.Template.Spec.Containers[0].LivenessProbe
+			changed = true
+		}
+
+		if !reflect.DeepEqual(existing.Spec.Template.Spec.Containers[0].ReadinessProbe,
+			deploy.Spec.Template.Spec.Containers[0].ReadinessProbe) {
+			existing.Spec.Template.Spec.Containers[0].ReadinessProbe = deploy.Spec.Template.Spec.Containers[0].ReadinessProbe
+			changed = true
+		}
+
+		if updatePodDNS(&existing.Spec.Template.Spec, &deploy.Spec.Template.Spec) {
+			changed = true
+		}
+
+		if updatePodServiceAccount(&existing.Spec.Template.Spec, &deploy.Spec.Template.Spec) {
+			changed = true
+		}
+
+		checksum := deploy.Spec.Template.ObjectMeta.Annotations[dexConfigChecksumAnnotation]
+		if existing.Spec.Template.ObjectMeta.Annotations[dexConfigChecksumAnnotation] != checksum {
+			if existing.Spec.Template.ObjectMeta.Annotations == nil {
+				existing.Spec.Template.ObjectMeta.Annotations = make(map[string]string)
+			}
+			log.Info("dex configuration has changed, rolling out dex deployment")
+			existing.Spec.Template.ObjectMeta.Annotations[dexConfigChecksumAnnotation] = checksum
 			changed = true
 		}
 
@@ -438,7 +596,7 @@ func (r *ReconcileArgoCD) reconcileGrafanaDeployment(cr *argoprojv1a1.ArgoCD) er
 				ContainerPort: 3000,
 			},
 		},
-		Env:       proxyEnvVars(),
+		Env:       proxyEnvVars(cr),
 		Resources: getGrafanaResources(cr),
 		VolumeMounts: []corev1.VolumeMount{
 			{
@@ -509,14 +667,23 @@ func (r *ReconcileArgoCD) reconcileGrafanaDeployment(cr *argoprojv1a1.ArgoCD) er
 		},
 	}
 
+	if err := applyReconcilerHook(cr, deploy, ""); err != nil {
+		return err
+	}
+	if isManagedGrafanaEnabled(cr) {
+		if err := r.reconcilePodSecurityCompliance(cr, "Deployment/"+deploy.Name, &deploy.Spec.Template); err != nil {
+			return err
+		}
+	}
+
 	existing := newDeploymentWithSuffix("grafana", "grafana", cr)
 	if argoutil.IsObjectFound(r.client, cr.Namespace, existing.Name, existing) {
-		if !cr.Spec.Grafana.Enabled {
+		if !isManagedGrafanaEnabled(cr) {
 			// Deployment exists but enabled flag has been set to false, delete the Deployment
 			return r.client.Delete(context.TODO(), existing)
 		}
 		changed := false
-		if hasGrafanaSpecChanged(existing, cr) {
+		if !cr.Spec.Hibernate && hasGrafanaSpecChanged(existing, cr) {
 			existing.Spec.Replicas = cr.Spec.Grafana.Size
 			changed = true
 		}
@@ -532,7 +699,7 @@ func (r *ReconcileArgoCD) reconcileGrafanaDeployment(cr *argoprojv1a1.ArgoCD) er
 		return nil // Deployment found, do nothing
 	}
 
-	if !cr.Spec.Grafana.Enabled {
+	if !isManagedGrafanaEnabled(cr) {
 		return nil // Grafana not enabled, do nothing.
 	}
 	if err := controllerutil.SetControllerReference(cr, deploy, r.scheme); err != nil {
@@ -543,14 +710,13 @@ func (r *ReconcileArgoCD) reconcileGrafanaDeployment(cr *argoprojv1a1.ArgoCD) er
 
 // reconcileRedisDeployment will ensure the Deployment resource is present for the ArgoCD Redis component.
 func (r *ReconcileArgoCD) reconcileRedisDeployment(cr *argoprojv1a1.ArgoCD) error {
+	if !isManagedRedisEnabled(cr) {
+		return nil // Remote Redis, the Deployment is pruned.
+	}
+
 	deploy := newDeploymentWithSuffix("redis", "redis", cr)
 	deploy.Spec.Template.Spec.Containers = []corev1.Container{{
-		Args: []string{
-			"--save",
-			"",
-			"--appendonly",
-			"no",
-		},
+		Args:            getRedisArgs(cr),
 		Image:           getRedisContainerImage(cr),
 		ImagePullPolicy: corev1.PullAlways,
 		Name:            "redis",
@@ -560,18 +726,28 @@ func (r *ReconcileArgoCD) reconcileRedisDeployment(cr *argoprojv1a1.ArgoCD) erro
 			},
 		},
 		Resources: getRedisResources(cr),
-		Env:       proxyEnvVars(),
+		Env:       proxyEnvVars(cr),
 	}}
+	deploy.Spec.Template.Spec.ServiceAccountName = getServiceAccountName(common.ArgoCDRedisComponent, cr)
+	deploy.Spec.Template.Spec.AutomountServiceAccountToken = getRedisAutomountServiceAccountToken(cr)
 
 	if err := applyReconcilerHook(cr, deploy, ""); err != nil {
 		return err
 	}
+	setContainerCommand(&deploy.Spec.Template, cr.Spec.Redis.CommandOverride)
+
+	if !cr.Spec.HA.Enabled {
+		if err := r.reconcilePodSecurityCompliance(cr, "Deployment/"+deploy.Name, &deploy.Spec.Template); err != nil {
+			return err
+		}
+	}
 
 	existing := newDeploymentWithSuffix("redis", "redis", cr)
 	if argoutil.IsObjectFound(r.client, cr.Namespace, existing.Name, existing) {
 		if cr.Spec.HA.Enabled {
-			// Deployment exists but HA enabled flag has been set to true, delete the Deployment
-			return r.client.Delete(context.TODO(), deploy)
+			// Deployment exists but HA enabled flag has been set to true, the Deployment is kept until the
+			// Redis HA migration removes it.
+			return nil
 		}
 		changed := false
 		actualImage := deploy.Spec.Template.Spec.Containers[0].Image
@@ -588,6 +764,20 @@ func (r *ReconcileArgoCD) reconcileRedisDeployment(cr *argoprojv1a1.ArgoCD) erro
 			changed = true
 		}
 
+		if updateContainerCommand(&existing.Spec.Template, &deploy.Spec.Template) {
+			changed = true
+		}
+
+		if !reflect.DeepEqual(existing.Spec.Template.Spec.Containers[0].Args,
+			deploy.Spec.Template.Spec.Containers[0].Args) {
+			existing.Spec.Template.Spec.Containers[0].Args = deploy.Spec.Template.Spec.Containers[0].Args
+			changed = true
+		}
+
+		if updatePodServiceAccount(&existing.Spec.Template.Spec, &deploy.Spec.Template.Spec) {
+			changed = true
+		}
+
 		if changed {
 			return r.client.Update(context.TODO(), existing)
 		}
@@ -606,24 +796,11 @@ func (r *ReconcileArgoCD) reconcileRedisDeployment(cr *argoprojv1a1.ArgoCD) erro
 // reconcileRedisHAProxyDeployment will ensure the Deployment resource is present for the Redis HA Proxy component.
 func (r *ReconcileArgoCD) reconcileRedisHAProxyDeployment(cr *argoprojv1a1.ArgoCD) error {
 	deploy := newDeploymentWithSuffix("redis-ha-haproxy", "redis", cr)
-	if argoutil.IsObjectFound(r.client, cr.Namespace, deploy.Name, deploy) {
-		if !cr.Spec.HA.Enabled {
+	if !isRedisHAInUse(cr) {
+		if argoutil.IsObjectFound(r.client, cr.Namespace, deploy.Name, deploy) {
 			// Deployment exists but HA enabled flag has been set to false, delete the Deployment
 			return r.client.Delete(context.TODO(), deploy)
 		}
-
-		actualImage := deploy.Spec.Template.Spec.Containers[0].Image
-		desiredImage := getRedisHAProxyContainerImage(cr)
-
-		if actualImage != desiredImage {
-			deploy.Spec.Template.Spec.Containers[0].Image = desiredImage
-			deploy.Spec.Template.ObjectMeta.Labels["image.upgraded"] = time.Now().UTC().Format("01022006-150406-MST")
-			return r.client.Update(context.TODO(), deploy)
-		}
-		return nil // Deployment found, do nothing
-	}
-
-	if !cr.Spec.HA.Enabled {
 		return nil // HA not enabled, do nothing.
 	}
 
@@ -659,7 +836,7 @@ func (r *ReconcileArgoCD) reconcileRedisHAProxyDeployment(cr *argoprojv1a1.ArgoC
 		Image:           getRedisHAProxyContainerImage(cr),
 		ImagePullPolicy: corev1.PullIfNotPresent,
 		Name:            "haproxy",
-		Env:             proxyEnvVars(),
+		Env:             proxyEnvVars(cr),
 		LivenessProbe: &corev1.Probe{
 			Handler: corev1.Handler{
 				HTTPGet: &corev1.HTTPGetAction{
@@ -699,8 +876,8 @@ func (r *ReconcileArgoCD) reconcileRedisHAProxyDeployment(cr *argoprojv1a1.ArgoC
 		Image:           getRedisHAProxyContainerImage(cr),
 		ImagePullPolicy: corev1.PullIfNotPresent,
 		Name:            "config-init",
-		Env:             proxyEnvVars(),
-		Resources:       getRedisHAProxyResources(cr),
+		Env:             proxyEnvVars(cr),
+		Resources:       getInitContainerResources(cr.Spec.HA.InitContainerResources, getRedisHAProxyResources(cr)),
 		VolumeMounts: []corev1.VolumeMount{
 			{
 				Name:      "config-volume",
@@ -739,11 +916,64 @@ func (r *ReconcileArgoCD) reconcileRedisHAProxyDeployment(cr *argoprojv1a1.ArgoC
 		},
 	}
 
-	deploy.Spec.Template.Spec.ServiceAccountName = fmt.Sprintf("%s-%s", cr.Name, "argocd-redis-ha")
+	deploy.Spec.Template.ObjectMeta.Annotations = map[string]string{
+		redisHAProxyConfigChecksumAnnotation: getRedisHAProxyConfigChecksum(cr),
+	}
+	deploy.Spec.Template.Spec.ServiceAccountName = getServiceAccountName(common.ArgoCDRedisHAProxyComponent, cr)
+	deploy.Spec.Template.Spec.AutomountServiceAccountToken = getRedisAutomountServiceAccountToken(cr)
 
 	if err := applyReconcilerHook(cr, deploy, ""); err != nil {
 		return err
 	}
+	if err := r.reconcilePodSecurityCompliance(cr, "Deployment/"+deploy.Name, &deploy.Spec.Template); err != nil {
+		return err
+	}
+
+	existing := newDeploymentWithSuffix("redis-ha-haproxy", "redis", cr)
+	if argoutil.IsObjectFound(r.client, cr.Namespace, existing.Name, existing) {
+		actualImage := existing.Spec.Template.Spec.Containers[0].Image
+		desiredImage := getRedisHAProxyContainerImage(cr)
+		changed := false
+
+		if actualImage != desiredImage {
+			existing.Spec.Template.Spec.Containers[0].Image = desiredImage
+			existing.Spec.Template.ObjectMeta.Labels["image.upgraded"] = time.Now().UTC().Format("01022006-150406-MST")
+			changed = true
+		}
+
+		if updatePodServiceAccount(&existing.Spec.Template.Spec, &deploy.Spec.Template.Spec) {
+			changed = true
+		}
+
+		checksum := getRedisHAProxyConfigChecksum(cr)
+		if existing.Spec.Template.ObjectMeta.Annotations[redisHAProxyConfigChecksumAnnotation] != checksum {
+			if existing.Spec.Template.ObjectMeta.Annotations == nil {
+				existing.Spec.Template.ObjectMeta.Annotations = make(map[string]string)
+			}
+			log.Info("redis haproxy configuration has changed, rolling out redis haproxy deployment")
+			existing.Spec.Template.ObjectMeta.Annotations[redisHAProxyConfigChecksumAnnotation] = checksum
+			changed = true
+		}
+
+		initResources := getInitContainerResources(cr.Spec.HA.InitContainerResources, getRedisHAProxyResources(cr))
+		if len(existing.Spec.Template.Spec.InitContainers) > 0 &&
+			!reflect.DeepEqual(existing.Spec.Template.Spec.InitContainers[0].Resources, initResources) {
+			existing.Spec.Template.Spec.InitContainers[0].Resources = initResources
+			changed = true
+		}
+
+		if updatePodSecurityContext(&existing.Spec.Template.Spec, &deploy.Spec.Template.Spec) {
+			changed = true
+		}
+		if updateContainerSecurityContexts(&existing.Spec.Template.Spec, &deploy.Spec.Template.Spec) {
+			changed = true
+		}
+
+		if changed {
+			return r.client.Update(context.TODO(), existing)
+		}
+		return nil // Deployment found, do nothing
+	}
 
 	if err := controllerutil.SetControllerReference(cr, deploy, r.scheme); err != nil {
 		return err
@@ -754,6 +984,7 @@ func (r *ReconcileArgoCD) reconcileRedisHAProxyDeployment(cr *argoprojv1a1.ArgoC
 // reconcileRepoDeployment will ensure the Deployment resource is present for the ArgoCD Repo component.
 func (r *ReconcileArgoCD) reconcileRepoDeployment(cr *argoprojv1a1.ArgoCD) error {
 	deploy := newDeploymentWithSuffix("repo-server", "repo-server", cr)
+	deploy.Spec.Replicas = getArgoRepoReplicas(cr)
 	automountToken := false
 	if cr.Spec.Repo.MountSAToken {
 		automountToken = cr.Spec.Repo.MountSAToken
@@ -778,7 +1009,7 @@ func (r *ReconcileArgoCD) reconcileRepoDeployment(cr *argoprojv1a1.ArgoCD) error
 			InitialDelaySeconds: 5,
 			PeriodSeconds:       10,
 		},
-		Env:  proxyEnvVars(),
+		Env:  getArgoRepoEnv(cr),
 		Name: "argocd-repo-server",
 		Ports: []corev1.ContainerPort{
 			{
@@ -871,6 +1102,38 @@ func (r *ReconcileArgoCD) reconcileRepoDeployment(cr *argoprojv1a1.ArgoCD) error
 		},
 	}
 
+	deploy.Spec.Template.Spec.SecurityContext = getArgoRepoPodSecurityContext(cr)
+
+	if cr.Spec.Repo.SOPS.Enabled {
+		podSpec := &deploy.Spec.Template.Spec
+		podSpec.InitContainers = append(podSpec.InitContainers, getSOPSInitContainers(cr)...)
+		podSpec.Containers[0].VolumeMounts = append(podSpec.Containers[0].VolumeMounts, getSOPSRepoVolumeMounts(cr)...)
+		podSpec.Volumes = append(podSpec.Volumes, getSOPSVolumes(cr)...)
+	}
+
+	if cr.Spec.Repo.VaultPlugin.Enabled && isArgoCDVersionAtLeast(cr, vaultPluginMinimumVersion) {
+		podSpec := &deploy.Spec.Template.Spec
+		podSpec.InitContainers = append(podSpec.InitContainers, getVaultPluginInitContainers(cr)...)
+		podSpec.Containers[0].VolumeMounts = append(podSpec.Containers[0].VolumeMounts, getVaultPluginRepoVolumeMounts()...)
+		podSpec.Containers = append(podSpec.Containers, getVaultPluginSidecar(cr))
+		podSpec.Volumes = append(podSpec.Volumes, getVaultPluginVolumes()...)
+	}
+
+	for i := range deploy.Spec.Template.Spec.InitContainers {
+		deploy.Spec.Template.Spec.InitContainers[i].Resources = getInitContainerResources(cr.Spec.Repo.InitContainerResources, getArgoRepoResources(cr))
+	}
+
+	applyArgoRepoVolumeClaims(cr, &deploy.Spec.Template.Spec)
+	setPodDNS(&deploy.Spec.Template.Spec, cr.Spec.Repo.HostAliases, cr.Spec.Repo.DNSConfig, cr.Spec.Repo.DNSPolicy)
+	if err := applyReconcilerHook(cr, deploy, ""); err != nil {
+		return err
+	}
+	setContainerCommand(&deploy.Spec.Template, cr.Spec.Repo.CommandOverride)
+
+	if err := r.reconcilePodSecurityCompliance(cr, "Deployment/"+deploy.Name, &deploy.Spec.Template); err != nil {
+		return err
+	}
+
 	existing := newDeploymentWithSuffix("repo-server", "repo-server", cr)
 	if argoutil.IsObjectFound(r.client, cr.Namespace, existing.Name, existing) {
 		changed := false
@@ -895,6 +1158,30 @@ func (r *ReconcileArgoCD) reconcileRepoDeployment(cr *argoprojv1a1.ArgoCD) error
 			existing.Spec.Template.Spec.Containers[0].Env = deploy.Spec.Template.Spec.Containers[0].Env
 			changed = true
 		}
+		if updateContainerCommand(&existing.Spec.Template, &deploy.Spec.Template) {
+			changed = true
+		}
+		if initContainersChanged(existing.Spec.Template.Spec.InitContainers, deploy.Spec.Template.Spec.InitContainers) {
+			existing.Spec.Template.Spec.InitContainers = deploy.Spec.Template.Spec.InitContainers
+			changed = true
+		}
+		if !reflect.DeepEqual(deploy.Spec.Template.Spec.Containers[1:], existing.Spec.Template.Spec.Containers[1:]) {
+			existing.Spec.Template.Spec.Containers = append(existing.Spec.Template.Spec.Containers[:1],
+				deploy.Spec.Template.Spec.Containers[1:]...)
+			changed = true
+		}
+		if updatePodDNS(&existing.Spec.Template.Spec, &deploy.Spec.Template.Spec) {
+			changed = true
+		}
+		if updatePodSecurityContext(&existing.Spec.Template.Spec, &deploy.Spec.Template.Spec) {
+			changed = true
+		}
+		// The replica count of a hibernated Deployment is managed by reconcileHibernation.
+		_, hibernated := existing.Annotations[common.AnnotationHibernatedReplicas]
+		if !cr.Spec.Hibernate && !hibernated && !reflect.DeepEqual(deploy.Spec.Replicas, existing.Spec.Replicas) {
+			existing.Spec.Replicas = deploy.Spec.Replicas
+			changed = true
+		}
 
 		if changed {
 			return r.client.Update(context.TODO(), existing)
@@ -911,11 +1198,12 @@ func (r *ReconcileArgoCD) reconcileRepoDeployment(cr *argoprojv1a1.ArgoCD) error
 // reconcileServerDeployment will ensure the Deployment resource is present for the ArgoCD Server component.
 func (r *ReconcileArgoCD) reconcileServerDeployment(cr *argoprojv1a1.ArgoCD) error {
 	deploy := newDeploymentWithSuffix("server", "server", cr)
+	image := r.getArgoContainerImageForComponent(cr, "server")
 	deploy.Spec.Template.Spec.Containers = []corev1.Container{{
 		Command:         getArgoServerCommand(cr),
-		Image:           getArgoContainerImage(cr),
+		Image:           image,
 		ImagePullPolicy: corev1.PullAlways,
-		Env:             proxyEnvVars(),
+		Env:             proxyEnvVars(cr),
 		LivenessProbe: &corev1.Probe{
 			Handler: corev1.Handler{
 				HTTPGet: &corev1.HTTPGetAction{
@@ -944,7 +1232,8 @@ func (r *ReconcileArgoCD) reconcileServerDeployment(cr *argoprojv1a1.ArgoCD) err
 			InitialDelaySeconds: 3,
 			PeriodSeconds:       30,
 		},
-		Resources: getArgoServerResources(cr),
+		Resources:    getArgoServerResources(cr),
+		StartupProbe: getArgoServerStartupProbe(cr),
 		VolumeMounts: []corev1.VolumeMount{
 			{
 				Name:      "ssh-known-hosts",
@@ -959,7 +1248,8 @@ func (r *ReconcileArgoCD) reconcileServerDeployment(cr *argoprojv1a1.ArgoCD) err
 			},
 		},
 	}}
-	deploy.Spec.Template.Spec.ServiceAccountName = fmt.Sprintf("%s-%s", cr.Name, "argocd-server")
+	deploy.Spec.Template.Spec.ServiceAccountName = getServiceAccountName(common.ArgoCDServerComponent, cr)
+	deploy.Spec.Template.Spec.AutomountServiceAccountToken = cr.Spec.Server.AutomountServiceAccountToken
 	deploy.Spec.Template.Spec.Volumes = []corev1.Volume{
 		{
 			Name: "ssh-known-hosts",
@@ -989,11 +1279,21 @@ func (r *ReconcileArgoCD) reconcileServerDeployment(cr *argoprojv1a1.ArgoCD) err
 			},
 		},
 	}
+	applyServerExtensions(cr, &deploy.Spec.Template.Spec)
+	setPodDNS(&deploy.Spec.Template.Spec, cr.Spec.Server.HostAliases, cr.Spec.Server.DNSConfig, cr.Spec.Server.DNSPolicy)
+	if err := applyReconcilerHook(cr, deploy, ""); err != nil {
+		return err
+	}
+	setContainerCommand(&deploy.Spec.Template, cr.Spec.Server.CommandOverride)
+
+	if err := r.reconcilePodSecurityCompliance(cr, "Deployment/"+deploy.Name, &deploy.Spec.Template); err != nil {
+		return err
+	}
 
 	existing := newDeploymentWithSuffix("server", "server", cr)
 	if argoutil.IsObjectFound(r.client, cr.Namespace, existing.Name, existing) {
 		actualImage := existing.Spec.Template.Spec.Containers[0].Image
-		desiredImage := getArgoContainerImage(cr)
+		desiredImage := image
 		changed := false
 		if actualImage != desiredImage {
 			existing.Spec.Template.Spec.Containers[0].Image = desiredImage
@@ -1005,9 +1305,12 @@ func (r *ReconcileArgoCD) reconcileServerDeployment(cr *argoprojv1a1.ArgoCD) err
 			existing.Spec.Template.Spec.Containers[0].Env = deploy.Spec.Template.Spec.Containers[0].Env
 			changed = true
 		}
-		if !reflect.DeepEqual(existing.Spec.Template.Spec.Containers[0].Command,
-			deploy.Spec.Template.Spec.Containers[0].Command) {
-			existing.Spec.Template.Spec.Containers[0].Command = deploy.Spec.Template.Spec.Containers[0].Command
+		if updateContainerCommand(&existing.Spec.Template, &deploy.Spec.Template) {
+			changed = true
+		}
+		if probeChanged(existing.Spec.Template.Spec.Containers[0].StartupProbe,
+			deploy.Spec.Template.Spec.Containers[0].StartupProbe) {
+			existing.Spec.Template.Spec.Containers[0].StartupProbe = deploy.Spec.Template.Spec.Containers[0].StartupProbe
 			changed = true
 		}
 		if !reflect.DeepEqual(deploy.Spec.Template.Spec.Volumes, existing.Spec.Template.Spec.Volumes) {
@@ -1019,6 +1322,16 @@ func (r *ReconcileArgoCD) reconcileServerDeployment(cr *argoprojv1a1.ArgoCD) err
 			existing.Spec.Template.Spec.Containers[0].VolumeMounts = deploy.Spec.Template.Spec.Containers[0].VolumeMounts
 			changed = true
 		}
+		if initContainersChanged(existing.Spec.Template.Spec.InitContainers, deploy.Spec.Template.Spec.InitContainers) {
+			existing.Spec.Template.Spec.InitContainers = deploy.Spec.Template.Spec.InitContainers
+			changed = true
+		}
+		if updatePodDNS(&existing.Spec.Template.Spec, &deploy.Spec.Template.Spec) {
+			changed = true
+		}
+		if updatePodServiceAccount(&existing.Spec.Template.Spec, &deploy.Spec.Template.Spec) {
+			changed = true
+		}
 		if changed {
 			return r.client.Update(context.TODO(), existing)
 		}
@@ -1042,11 +1355,210 @@ func (r *ReconcileArgoCD) triggerDeploymentRollout(deployment *appsv1.Deployment
 	return r.client.Update(context.TODO(), deployment)
 }
 
-func proxyEnvVars(vars ...corev1.EnvVar) []corev1.EnvVar {
+// setPodDNS will set the host aliases and DNS options of the given pod spec.
+func setPodDNS(spec *corev1.PodSpec, hostAliases []corev1.HostAlias, dnsConfig *corev1.PodDNSConfig, dnsPolicy corev1.DNSPolicy) {
+	spec.HostAliases = hostAliases
+	spec.DNSConfig = dnsConfig
+	spec.DNSPolicy = dnsPolicy
+}
+
+// updatePodDNS will copy the host aliases and DNS options of the desired pod spec to the existing pod spec and
+// return true if the existing pod spec was changed.
+func updatePodDNS(existing *corev1.PodSpec, desired *corev1.PodSpec) bool {
+	changed := false
+	if !reflect.DeepEqual(existing.HostAliases, desired.HostAliases) {
+		existing.HostAliases = desired.HostAliases
+		changed = true
+	}
+	if !reflect.DeepEqual(existing.DNSConfig, desired.DNSConfig) {
+		existing.DNSConfig = desired.DNSConfig
+		changed = true
+	}
+
+	// The API server defaults an empty DNS policy to ClusterFirst.
+	desiredPolicy := desired.DNSPolicy
+	if desiredPolicy == "" {
+		desiredPolicy = corev1.DNSClusterFirst
+	}
+	if existing.DNSPolicy != desiredPolicy && (existing.DNSPolicy != "" || desired.DNSPolicy != "") {
+		existing.DNSPolicy = desiredPolicy
+		changed = true
+	}
+	return changed
+}
+
+// updatePodServiceAccount will copy the service account options of the desired pod spec to the existing pod spec and
+// return true if the existing pod spec was changed.
+func updatePodServiceAccount(existing *corev1.PodSpec, desired *corev1.PodSpec) bool {
+	changed := false
+	if existing.ServiceAccountName != desired.ServiceAccountName {
+		existing.ServiceAccountName = desired.ServiceAccountName
+		existing.DeprecatedServiceAccount = desired.ServiceAccountName
+		changed = true
+	}
+	if !reflect.DeepEqual(existing.AutomountServiceAccountToken, desired.AutomountServiceAccountToken) {
+		existing.AutomountServiceAccountToken = desired.AutomountServiceAccountToken
+		changed = true
+	}
+	return changed
+}
+
+// updatePodSecurityContext will copy the security context of the desired pod spec to the existing pod spec and return
+// true if the existing pod spec was changed. A nil security context is equal to an empty one, as the API server
+// defaults it.
+func updatePodSecurityContext(existing *corev1.PodSpec, desired *corev1.PodSpec) bool {
+	existingSC, desiredSC := existing.SecurityContext, desired.SecurityContext
+	if existingSC == nil {
+		existingSC = &corev1.PodSecurityContext{}
+	}
+	if desiredSC == nil {
+		desiredSC = &corev1.PodSecurityContext{}
+	}
+	if reflect.DeepEqual(existingSC, desiredSC) {
+		return false
+	}
+	existing.SecurityContext = desired.SecurityContext
+	return true
+}
+
+// updateContainerSecurityContexts will copy the security contexts of the containers and init containers of the desired
+// pod spec to the containers of the existing pod spec with the same name, and return true if the existing pod spec
+// was changed. A nil security context is equal to an empty one.
+func updateContainerSecurityContexts(existing *corev1.PodSpec, desired *corev1.PodSpec) bool {
+	changed := false
+	update := func(existing, desired []corev1.Container) {
+		for i := range existing {
+			for j := range desired {
+				if existing[i].Name != desired[j].Name {
+					continue
+				}
+				existingSC, desiredSC := existing[i].SecurityContext, desired[j].SecurityContext
+				if existingSC == nil {
+					existingSC = &corev1.SecurityContext{}
+				}
+				if desiredSC == nil {
+					desiredSC = &corev1.SecurityContext{}
+				}
+				if !reflect.DeepEqual(existingSC, desiredSC) {
+					existing[i].SecurityContext = desired[j].SecurityContext
+					changed = true
+				}
+			}
+		}
+	}
+	update(existing.InitContainers, desired.InitContainers)
+	update(existing.Containers, desired.Containers)
+	return changed
+}
+
+// initContainersChanged will return true when the given existing init containers differ from the desired init
+// containers. Only the fields set by the operator are compared, so that the defaults set by the API server, e.g. the
+// termination message path, do not cause an update on every reconcile.
+func initContainersChanged(existing, desired []corev1.Container) bool {
+	if len(existing) != len(desired) {
+		return true
+	}
+	for i := range desired {
+		e, d := existing[i], desired[i]
+		if e.Name != d.Name || e.Image != d.Image || (d.ImagePullPolicy != "" && e.ImagePullPolicy != d.ImagePullPolicy) {
+			return true
+		}
+		if !reflect.DeepEqual(e.Command, d.Command) || !reflect.DeepEqual(e.Args, d.Args) {
+			return true
+		}
+		if (len(e.Env) > 0 || len(d.Env) > 0) && !reflect.DeepEqual(e.Env, d.Env) {
+			return true
+		}
+		if (len(e.VolumeMounts) > 0 || len(d.VolumeMounts) > 0) && !reflect.DeepEqual(e.VolumeMounts, d.VolumeMounts) {
+			return true
+		}
+		if !reflect.DeepEqual(e.Resources, d.Resources) || !reflect.DeepEqual(e.SecurityContext, d.SecurityContext) {
+			return true
+		}
+	}
+	return false
+}
+
+// setContainerCommand will replace the command and the arguments of the first container of the given pod template
+// with those of the given override, when set, and mark the pod template as overridden. The overrides are used verbatim.
+func setContainerCommand(template *corev1.PodTemplateSpec, override argoprojv1a1.ArgoCDCommandOverrideSpec) {
+	if len(override.Command) == 0 && len(override.Args) == 0 {
+		return
+	}
+	container := &template.Spec.Containers[0]
+	if len(override.Command) > 0 {
+		container.Command = override.Command
+	}
+	if len(override.Args) > 0 {
+		container.Args = override.Args
+	}
+	if template.Annotations == nil {
+		template.Annotations = make(map[string]string)
+	}
+	template.Annotations[common.ArgoCDCommandOverrideAnnotation] = "true"
+}
+
+// updateContainerCommand will copy the command of the first container of the desired pod template to the existing
+// pod template, along with the arguments while the command is overridden, or was overridden, and return true if the
+// existing pod template was changed. Otherwise the arguments are left to the comparisons of the component.
+func updateContainerCommand(existing *corev1.PodTemplateSpec, desired *corev1.PodTemplateSpec) bool {
+	changed := false
+	container := &existing.Spec.Containers[0]
+	if !stringSlicesEqual(container.Command, desired.Spec.Containers[0].Command) {
+		container.Command = desired.Spec.Containers[0].Command
+		changed = true
+	}
+
+	_, overridden := desired.Annotations[common.ArgoCDCommandOverrideAnnotation]
+	_, wasOverridden := existing.Annotations[common.ArgoCDCommandOverrideAnnotation]
+	if !overridden && !wasOverridden {
+		return changed
+	}
+	if !stringSlicesEqual(container.Args, desired.Spec.Containers[0].Args) {
+		container.Args = desired.Spec.Containers[0].Args
+		changed = true
+	}
+	if overridden && !wasOverridden {
+		if existing.Annotations == nil {
+			existing.Annotations = make(map[string]string)
+		}
+		existing.Annotations[common.ArgoCDCommandOverrideAnnotation] = "true"
+		changed = true
+	} else if !overridden && wasOverridden {
+		delete(existing.Annotations, common.ArgoCDCommandOverrideAnnotation)
+		changed = true
+	}
+	return changed
+}
+
+// stringSlicesEqual will return true if the given slices hold the same strings in the same order, an empty slice
+// being equal to a nil slice as the API server does not keep empty slices.
+func stringSlicesEqual(a, b []string) bool {
+	if len(a) == 0 && len(b) == 0 {
+		return true
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// proxyEnvVars will return the given environment variables followed by the proxy of the given ArgoCD, or by the proxy
+// in the environment of the operator when the ArgoCD does not set one.
+func proxyEnvVars(cr *argoprojv1a1.ArgoCD, vars ...corev1.EnvVar) []corev1.EnvVar {
 	result := []corev1.EnvVar{}
 	for _, v := range vars {
 		result = append(result, v)
 	}
+	if proxy := cr.Spec.Proxy; proxy != nil {
+		for _, v := range []corev1.EnvVar{
+			{Name: "HTTP_PROXY", Value: proxy.HTTPProxy},
+			{Name: "HTTPS_PROXY", Value: proxy.HTTPSProxy},
+			{Name: "NO_PROXY", Value: proxy.NoProxy},
+		} {
+			if v.Value != "" {
+				result = append(result, v)
+			}
+		}
+		return result
+	}
 	proxyKeys := []string{"HTTP_PROXY", "HTTPS_PROXY", "NO_PROXY"}
 	for _, p := range proxyKeys {
 		if k, v := caseInsensitiveGetenv(p); k != "" {
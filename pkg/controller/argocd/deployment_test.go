@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/argoproj-labs/argocd-operator/pkg/common"
+	"github.com/argoproj-labs/argocd-operator/pkg/controller/argoutil"
 	"gotest.tools/assert"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -155,7 +156,37 @@ func TestReconcileArgoCD_reconcileDeployments_Dex_with_resources(t *testing.T) {
 		},
 	}
 	assert.DeepEqual(t, deployment.Spec.Template.Spec.Containers[0].Resources, testResources)
-	assert.DeepEqual(t, deployment.Spec.Template.Spec.InitContainers[0].Resources, testResources)
+	assert.DeepEqual(t, deployment.Spec.Template.Spec.InitContainers[0].Resources, defaultInitContainerResources())
+}
+
+func TestReconcileArgoCD_reconcileDeployments_Dex_with_initContainerResources(t *testing.T) {
+	restoreEnv(t)
+
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCDWithResources()
+	r := makeTestReconciler(t, a)
+
+	assert.NilError(t, r.reconcileDexDeployment(a))
+
+	initResources := corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{
+			corev1.ResourceMemory: resourcev1.MustParse("16Mi"),
+			corev1.ResourceCPU:    resourcev1.MustParse("5m"),
+		},
+	}
+	a.Spec.Dex.InitContainerResources = &initResources
+	assert.NilError(t, r.reconcileDexDeployment(a))
+
+	deployment := &appsv1.Deployment{}
+	assert.NilError(t, r.client.Get(
+		context.TODO(),
+		types.NamespacedName{
+			Name:      a.Name + "-dex-server",
+			Namespace: a.Namespace,
+		},
+		deployment))
+	assert.DeepEqual(t, deployment.Spec.Template.Spec.InitContainers[0].Resources, initResources)
+	assert.DeepEqual(t, deployment.Spec.Template.Spec.Containers[0].Resources, *a.Spec.Dex.Resources)
 }
 
 // reconcileRepoDeployments creates a Deployment with the proxy settings from the
@@ -265,7 +296,7 @@ func TestReconcileArgoCD_reconcileDeployments_HA_proxy_with_resources(t *testing
 		},
 	}
 	assert.DeepEqual(t, deployment.Spec.Template.Spec.Containers[0].Resources, testResources)
-	assert.DeepEqual(t, deployment.Spec.Template.Spec.InitContainers[0].Resources, testResources)
+	assert.DeepEqual(t, deployment.Spec.Template.Spec.InitContainers[0].Resources, defaultInitContainerResources())
 }
 
 func TestReconcileArgoCD_reconcileRepoDeployment_updatesVolumeMounts(t *testing.T) {
@@ -310,14 +341,318 @@ func TestReconcileArgoCD_reconcileRepoDeployment_updatesVolumeMounts(t *testing.
 	}
 }
 
+func TestReconcileArgoCD_reconcileRepoDeployment_cacheAndGitRetry(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD()
+	r := makeTestReconciler(t, a)
+
+	assert.NilError(t, r.reconcileRepoDeployment(a))
+
+	a.Spec.Repo.CacheExpiration = &metav1.Duration{Duration: 48 * time.Hour}
+	a.Spec.Repo.GitRetry = argoprojv1alpha1.ArgoCDRepoGitRetrySpec{
+		Attempts:    5,
+		Duration:    &metav1.Duration{Duration: 2 * time.Second},
+		Factor:      3,
+		MaxDuration: &metav1.Duration{Duration: time.Minute},
+	}
+	assert.NilError(t, r.reconcileRepoDeployment(a))
+
+	deployment := &appsv1.Deployment{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{
+		Name:      "argocd-repo-server",
+		Namespace: testNamespace,
+	}, deployment))
+
+	wantCmd := []string{
+		"uid_entrypoint.sh",
+		"argocd-repo-server",
+		"--redis",
+		"argocd-redis.argocd.svc.cluster.local:6379",
+		"--repo-cache-expiration",
+		"48h0m0s",
+	}
+	assert.DeepEqual(t, deployment.Spec.Template.Spec.Containers[0].Command, wantCmd)
+
+	wantEnv := []corev1.EnvVar{
+		{Name: common.ArgoCDGitAttemptsCountEnvName, Value: "5"},
+		{Name: common.ArgoCDGitRetryDurationEnvName, Value: "2s"},
+		{Name: common.ArgoCDGitRetryFactorEnvName, Value: "3"},
+		{Name: common.ArgoCDGitRetryMaxDurationEnvName, Value: "1m0s"},
+	}
+	assert.DeepEqual(t, deployment.Spec.Template.Spec.Containers[0].Env, wantEnv)
+}
+
+func TestReconcileArgoCD_reconcileRepoDeployment_hostAliasesAndDNS(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD()
+	r := makeTestReconciler(t, a)
+
+	assert.NilError(t, r.reconcileRepoDeployment(a))
+
+	ndots := "2"
+	a.Spec.Repo.HostAliases = []corev1.HostAlias{{
+		IP:        "10.0.0.10",
+		Hostnames: []string{"git.example.com"},
+	}}
+	a.Spec.Repo.DNSConfig = &corev1.PodDNSConfig{
+		Nameservers: []string{"10.0.0.53"},
+		Options:     []corev1.PodDNSConfigOption{{Name: "ndots", Value: &ndots}},
+	}
+	a.Spec.Repo.DNSPolicy = corev1.DNSNone
+	assert.NilError(t, r.reconcileRepoDeployment(a))
+
+	deployment := &appsv1.Deployment{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{
+		Name:      "argocd-repo-server",
+		Namespace: testNamespace,
+	}, deployment))
+	assert.DeepEqual(t, deployment.Spec.Template.Spec.HostAliases, a.Spec.Repo.HostAliases)
+	assert.DeepEqual(t, deployment.Spec.Template.Spec.DNSConfig, a.Spec.Repo.DNSConfig)
+	assert.Equal(t, deployment.Spec.Template.Spec.DNSPolicy, corev1.DNSNone)
+}
+
+func TestReconcileArgoCD_reconcileRepoDeployment_replicas(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD()
+	r := makeTestReconciler(t, a)
+
+	assert.NilError(t, r.reconcileRepoDeployment(a))
+
+	deployment := &appsv1.Deployment{}
+	key := types.NamespacedName{Name: "argocd-repo-server", Namespace: testNamespace}
+	assert.NilError(t, r.client.Get(context.TODO(), key, deployment))
+	assert.Equal(t, *deployment.Spec.Replicas, common.ArgoCDDefaultRepoServerReplicas)
+
+	a.Spec.HA.Enabled = true
+	assert.NilError(t, r.reconcileRepoDeployment(a))
+	assert.NilError(t, r.client.Get(context.TODO(), key, deployment))
+	assert.Equal(t, *deployment.Spec.Replicas, common.ArgoCDDefaultRepoServerHAReplicas)
+
+	replicas := int32(4)
+	a.Spec.Repo.Replicas = &replicas
+	assert.NilError(t, r.reconcileRepoDeployment(a))
+	assert.NilError(t, r.client.Get(context.TODO(), key, deployment))
+	assert.Equal(t, *deployment.Spec.Replicas, replicas)
+
+	// The replica count of a hibernated Deployment is left to the hibernation.
+	a.Spec.Hibernate = true
+	assert.NilError(t, r.reconcileHibernation(a))
+	assert.NilError(t, r.reconcileRepoDeployment(a))
+	assert.NilError(t, r.client.Get(context.TODO(), key, deployment))
+	assert.Equal(t, *deployment.Spec.Replicas, int32(0))
+}
+
+func Test_updatePodDNS(t *testing.T) {
+	existing := &corev1.PodSpec{DNSPolicy: corev1.DNSClusterFirst}
+	assert.Assert(t, !updatePodDNS(existing, &corev1.PodSpec{}))
+
+	desired := &corev1.PodSpec{
+		HostAliases: []corev1.HostAlias{{IP: "10.0.0.10", Hostnames: []string{"sso.example.com"}}},
+		DNSPolicy:   corev1.DNSDefault,
+	}
+	assert.Assert(t, updatePodDNS(existing, desired))
+	assert.DeepEqual(t, existing.HostAliases, desired.HostAliases)
+	assert.Equal(t, existing.DNSPolicy, corev1.DNSDefault)
+
+	assert.Assert(t, updatePodDNS(existing, &corev1.PodSpec{}))
+	assert.Assert(t, existing.HostAliases == nil)
+	assert.Equal(t, existing.DNSPolicy, corev1.DNSClusterFirst)
+}
+
+func TestReconcileArgoCD_reconcileRepoDeployment_vaultPlugin(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD()
+	r := makeTestReconciler(t, a)
+
+	assert.NilError(t, r.reconcileRepoDeployment(a))
+
+	// The sidecar is not added for a version of Argo CD without config management plugin sidecars.
+	a.Spec.Repo.VaultPlugin.Enabled = true
+	assert.NilError(t, r.reconcileRepoDeployment(a))
+
+	deployment := &appsv1.Deployment{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{
+		Name:      "argocd-repo-server",
+		Namespace: testNamespace,
+	}, deployment))
+	assert.Equal(t, len(deployment.Spec.Template.Spec.Containers), 1)
+
+	a.Spec.Version = "v2.4.0"
+	assert.NilError(t, r.reconcileRepoDeployment(a))
+
+	deployment = &appsv1.Deployment{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{
+		Name:      "argocd-repo-server",
+		Namespace: testNamespace,
+	}, deployment))
+
+	podSpec := deployment.Spec.Template.Spec
+	assert.Equal(t, len(podSpec.Containers), 2)
+	assert.Equal(t, podSpec.Containers[1].Name, "avp")
+	assert.Equal(t, podSpec.Containers[1].EnvFrom[0].SecretRef.Name, common.ArgoCDDefaultVaultPluginCredentialsSecret)
+	assert.Equal(t, len(podSpec.InitContainers), 2)
+	assert.Assert(t, strings.Contains(podSpec.InitContainers[1].Command[2], "sha256sum -c -"))
+	assert.DeepEqual(t, podSpec.Containers[0].VolumeMounts[len(podSpec.Containers[0].VolumeMounts)-2:], getVaultPluginRepoVolumeMounts())
+
+	a.Spec.Repo.VaultPlugin.Enabled = false
+	assert.NilError(t, r.reconcileRepoDeployment(a))
+
+	deployment = &appsv1.Deployment{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{
+		Name:      "argocd-repo-server",
+		Namespace: testNamespace,
+	}, deployment))
+	assert.Equal(t, len(deployment.Spec.Template.Spec.Containers), 1)
+	assert.Equal(t, len(deployment.Spec.Template.Spec.InitContainers), 0)
+}
+
+func TestReconcileArgoCD_reconcileRepoDeployment_volumeClaims(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD()
+	r := makeTestReconciler(t, a)
+
+	assert.NilError(t, r.reconcileRepoDeployment(a))
+
+	a.Spec.Repo.VolumeClaims = []argoprojv1alpha1.ArgoCDRepoVolumeClaimSpec{
+		{Volume: "gpg-keyring", ClaimName: "repo-gpg-keyring"},
+		{Volume: "helm-cache", ClaimName: "repo-helm-cache"},
+	}
+	assert.NilError(t, r.reconcileRepoDeployment(a))
+
+	deployment := &appsv1.Deployment{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{
+		Name:      "argocd-repo-server",
+		Namespace: testNamespace,
+	}, deployment))
+
+	volumes := map[string]corev1.VolumeSource{}
+	for _, v := range deployment.Spec.Template.Spec.Volumes {
+		volumes[v.Name] = v.VolumeSource
+	}
+	assert.Equal(t, volumes["gpg-keyring"].PersistentVolumeClaim.ClaimName, "repo-gpg-keyring")
+	assert.Equal(t, volumes["helm-cache"].PersistentVolumeClaim.ClaimName, "repo-helm-cache")
+	assert.Assert(t, volumes["tls-certs"].ConfigMap != nil)
+	mounts := deployment.Spec.Template.Spec.Containers[0].VolumeMounts
+	assert.DeepEqual(t, mounts[len(mounts)-1], corev1.VolumeMount{Name: "helm-cache", MountPath: "/home/argocd/.cache/helm"})
+
+	// Removing the claims restores the emptyDir volumes.
+	a.Spec.Repo.VolumeClaims = nil
+	assert.NilError(t, r.reconcileRepoDeployment(a))
+
+	deployment = &appsv1.Deployment{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{
+		Name:      "argocd-repo-server",
+		Namespace: testNamespace,
+	}, deployment))
+	for _, v := range deployment.Spec.Template.Spec.Volumes {
+		assert.Assert(t, v.PersistentVolumeClaim == nil)
+		assert.Assert(t, v.Name != "helm-cache")
+	}
+}
+
+func TestReconcileArgoCD_reconcileRepoDeployment_volumePermissions(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD()
+	r := makeTestReconciler(t, a)
+
+	assert.NilError(t, r.reconcileRepoDeployment(a))
+
+	var uid, gid int64 = 1000, 2000
+	a.Spec.Repo.RunAsUser = &uid
+	a.Spec.Repo.FSGroup = &gid
+	a.Spec.Repo.FixVolumePermissions = true
+	a.Spec.Repo.VolumeClaims = []argoprojv1alpha1.ArgoCDRepoVolumeClaimSpec{
+		{Volume: "gpg-keyring", ClaimName: "repo-gpg-keyring"},
+		{Volume: "helm-cache", ClaimName: "repo-helm-cache"},
+	}
+	a.Spec.Repo.SOPS.Enabled = true
+	assert.NilError(t, r.reconcileRepoDeployment(a))
+
+	deployment := &appsv1.Deployment{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{
+		Name:      "argocd-repo-server",
+		Namespace: testNamespace,
+	}, deployment))
+	policy := corev1.FSGroupChangeOnRootMismatch
+	assert.DeepEqual(t, deployment.Spec.Template.Spec.SecurityContext, &corev1.PodSecurityContext{
+		FSGroup:             &gid,
+		FSGroupChangePolicy: &policy,
+		RunAsUser:           &uid,
+	})
+
+	// The kubelet fixes the permissions, so no init container runs as root.
+	for _, c := range deployment.Spec.Template.Spec.InitContainers {
+		assert.Assert(t, c.Name != "volume-permissions")
+	}
+
+	// Without a group, the permissions are fixed for the argocd group.
+	a.Spec.Repo.RunAsUser = nil
+	a.Spec.Repo.FSGroup = nil
+	assert.NilError(t, r.reconcileRepoDeployment(a))
+
+	deployment = &appsv1.Deployment{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{
+		Name:      "argocd-repo-server",
+		Namespace: testNamespace,
+	}, deployment))
+	argocdGID := int64(999)
+	assert.DeepEqual(t, deployment.Spec.Template.Spec.SecurityContext, &corev1.PodSecurityContext{
+		FSGroup:             &argocdGID,
+		FSGroupChangePolicy: &policy,
+	})
+
+	// Without a user and group, the pods run with the defaults of the image again.
+	a.Spec.Repo.RunAsUser = nil
+	a.Spec.Repo.FSGroup = nil
+	a.Spec.Repo.FixVolumePermissions = false
+	assert.NilError(t, r.reconcileRepoDeployment(a))
+
+	deployment = &appsv1.Deployment{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{
+		Name:      "argocd-repo-server",
+		Namespace: testNamespace,
+	}, deployment))
+	assert.Assert(t, deployment.Spec.Template.Spec.SecurityContext == nil)
+}
+
+func TestReconcileArgoCD_reconcileRepoDeployment_sops(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.Repo.SOPS = argoprojv1alpha1.ArgoCDRepoSOPSSpec{
+			Enabled:      true,
+			AgeKeySecret: "sops-age",
+			GPGKeySecret: "sops-gpg",
+		}
+	})
+	r := makeTestReconciler(t, a)
+
+	assert.NilError(t, r.reconcileRepoDeployment(a))
+
+	deployment := &appsv1.Deployment{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{
+		Name:      "argocd-repo-server",
+		Namespace: testNamespace,
+	}, deployment))
+
+	podSpec := deployment.Spec.Template.Spec
+	assert.Equal(t, len(podSpec.InitContainers), 2)
+	assert.Equal(t, podSpec.InitContainers[0].Image,
+		argoutil.CombineImageTag(common.ArgoCDDefaultKSOPSImage, common.ArgoCDDefaultKSOPSVersion))
+	assert.Equal(t, podSpec.InitContainers[1].Name, "import-sops-gpg-keys")
+	assert.DeepEqual(t, podSpec.Containers[0].Env, getSOPSRepoEnv())
+	assert.DeepEqual(t, podSpec.Containers[0].VolumeMounts[len(podSpec.Containers[0].VolumeMounts)-3:], getSOPSRepoVolumeMounts(a))
+	assert.DeepEqual(t, podSpec.Volumes[len(podSpec.Volumes)-3:], getSOPSVolumes(a))
+}
+
 func Test_proxyEnvVars(t *testing.T) {
 	restoreEnv(t)
 	os.Setenv("HTTP_PROXY", testHTTPProxy)
 	os.Setenv("HTTPS_PROXY", testHTTPSProxy)
 	os.Setenv("no_proxy", testNoProxy)
 	envTests := []struct {
-		vars []corev1.EnvVar
-		want []corev1.EnvVar
+		vars  []corev1.EnvVar
+		proxy *argoprojv1alpha1.ArgoCDProxySpec
+		want  []corev1.EnvVar
 	}{
 		{
 			vars: []corev1.EnvVar{},
@@ -338,10 +673,20 @@ func Test_proxyEnvVars(t *testing.T) {
 				{Name: "no_proxy", Value: ".example.com"},
 			},
 		},
+		{
+			vars:  []corev1.EnvVar{},
+			proxy: &argoprojv1alpha1.ArgoCDProxySpec{HTTPSProxy: "proxy.example.com:3128", NoProxy: ".cluster.local"},
+			want: []corev1.EnvVar{
+				{Name: "HTTPS_PROXY", Value: "proxy.example.com:3128"},
+				{Name: "NO_PROXY", Value: ".cluster.local"},
+			},
+		},
 	}
 
 	for _, tt := range envTests {
-		e := proxyEnvVars(tt.vars...)
+		e := proxyEnvVars(makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+			a.Spec.Proxy = tt.proxy
+		}), tt.vars...)
 		if diff := cmp.Diff(tt.want, e); diff != "" {
 			t.Errorf("proxyEnvVars(%#v) diff = \n%s", tt.vars, diff)
 		}
@@ -412,6 +757,8 @@ func TestReconcileArgoCD_reconcileDexDeployment(t *testing.T) {
 				VolumeMounts: []corev1.VolumeMount{
 					{Name: "static-files", MountPath: "/shared"}},
 				ImagePullPolicy: corev1.PullAlways,
+				LivenessProbe:   getDexProbe(),
+				ReadinessProbe:  getDexProbe(),
 			},
 		},
 		ServiceAccountName: "argocd-argocd-dex-server",
@@ -492,6 +839,8 @@ func TestReconcileArgoCD_reconcileDexDeployment_withUpdate(t *testing.T) {
 				VolumeMounts: []corev1.VolumeMount{
 					{Name: "static-files", MountPath: "/shared"}},
 				ImagePullPolicy: corev1.PullAlways,
+				LivenessProbe:   getDexProbe(),
+				ReadinessProbe:  getDexProbe(),
 			},
 		},
 		ServiceAccountName: "argocd-argocd-dex-server",
@@ -502,6 +851,180 @@ func TestReconcileArgoCD_reconcileDexDeployment_withUpdate(t *testing.T) {
 	}
 }
 
+func TestReconcileArgoCD_reconcileDexDeployment_probes(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD()
+	r := makeTestReconciler(t, a)
+
+	assert.NilError(t, r.reconcileDexDeployment(a))
+
+	// Override the liveness probe and rereconcile, the readiness probe keeps the default.
+	liveness := &corev1.Probe{
+		Handler: corev1.Handler{
+			TCPSocket: &corev1.TCPSocketAction{Port: intstr.FromInt(5556)},
+		},
+		InitialDelaySeconds: 30,
+		TimeoutSeconds:      5,
+		PeriodSeconds:       20,
+		SuccessThreshold:    1,
+		FailureThreshold:    5,
+	}
+	a.Spec.Dex.LivenessProbe = liveness
+	assert.NilError(t, r.reconcileDexDeployment(a))
+
+	deployment := &appsv1.Deployment{}
+	assert.NilError(t, r.client.Get(
+		context.TODO(),
+		types.NamespacedName{
+			Name:      "argocd-dex-server",
+			Namespace: a.Namespace,
+		},
+		deployment))
+	assert.DeepEqual(t, deployment.Spec.Template.Spec.Containers[0].LivenessProbe, liveness)
+	assert.DeepEqual(t, deployment.Spec.Template.Spec.Containers[0].ReadinessProbe, getDexProbe())
+}
+
+func TestReconcileArgoCD_reconcileDexDeployment_standaloneImage(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD()
+	r := makeTestReconciler(t, a)
+
+	assert.NilError(t, r.reconcileDexDeployment(a))
+
+	// Switching to a standalone image runs Dex directly, without the copyutil init container.
+	a.Spec.SSO = &argoprojv1alpha1.ArgoCDSSOSpec{
+		Provider: argoprojv1alpha1.SSOProviderTypeDex,
+		Dex: &argoprojv1alpha1.ArgoCDSSODexSpec{
+			ArgoCDDexSpec: argoprojv1alpha1.ArgoCDDexSpec{StandaloneImage: true},
+		},
+	}
+	assert.NilError(t, r.reconcileDexDeployment(a))
+
+	deployment := &appsv1.Deployment{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-dex-server", Namespace: a.Namespace}, deployment))
+	assert.DeepEqual(t, deployment.Spec.Template.Spec.Containers[0].Command, []string{"argocd-dex", "rundex"})
+	assert.Equal(t, len(deployment.Spec.Template.Spec.Containers[0].VolumeMounts), 0)
+	assert.Equal(t, len(deployment.Spec.Template.Spec.InitContainers), 0)
+	assert.Equal(t, len(deployment.Spec.Template.Spec.Volumes), 0)
+
+	// Switching back restores the init container.
+	a.Spec.SSO.Dex.StandaloneImage = false
+	assert.NilError(t, r.reconcileDexDeployment(a))
+
+	deployment = &appsv1.Deployment{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-dex-server", Namespace: a.Namespace}, deployment))
+	assert.DeepEqual(t, deployment.Spec.Template.Spec.Containers[0].Command, []string{"/shared/argocd-dex", "rundex"})
+	assert.Equal(t, len(deployment.Spec.Template.Spec.InitContainers), 1)
+	assert.Equal(t, deployment.Spec.Template.Spec.InitContainers[0].Name, "copyutil")
+	assert.Equal(t, len(deployment.Spec.Template.Spec.Volumes), 1)
+}
+
+func TestReconcileArgoCD_reconcileDexDeployment_configChecksum(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD()
+	cm := newConfigMapWithName(common.ArgoCDConfigMapName, a)
+	cm.Data = map[string]string{
+		common.ArgoCDKeyDexConfig: "connectors:\n- type: github\n  config:\n    clientSecret: $dex.github.clientSecret\n",
+	}
+	secret := argoutil.NewSecretWithName(a.ObjectMeta, common.ArgoCDSecretName)
+	secret.Data = map[string][]byte{
+		"dex.github.clientSecret": []byte("first"),
+		"server.secretkey":        []byte("key"),
+	}
+	r := makeTestReconciler(t, a, cm, secret)
+
+	getChecksum := func() string {
+		deployment := &appsv1.Deployment{}
+		assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-dex-server", Namespace: a.Namespace}, deployment))
+		return deployment.Spec.Template.ObjectMeta.Annotations[dexConfigChecksumAnnotation]
+	}
+
+	assert.NilError(t, r.reconcileDexDeployment(a))
+	checksum := getChecksum()
+	assert.Assert(t, checksum != "")
+
+	// Changing a Secret value that is not referenced keeps the Dex pods running.
+	secret.Data["server.secretkey"] = []byte("changed")
+	assert.NilError(t, r.client.Update(context.TODO(), secret))
+	assert.NilError(t, r.reconcileDexDeployment(a))
+	assert.Equal(t, getChecksum(), checksum)
+
+	// Changing a referenced client secret rolls out the Dex pods.
+	secret.Data["dex.github.clientSecret"] = []byte("second")
+	assert.NilError(t, r.client.Update(context.TODO(), secret))
+	assert.NilError(t, r.reconcileDexDeployment(a))
+	assert.Assert(t, getChecksum() != checksum)
+}
+
+func TestReconcileArgoCD_reconcileServerDeployment_extensions(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD()
+	r := makeTestReconciler(t, a)
+
+	assert.NilError(t, r.reconcileServerDeployment(a))
+
+	a.Spec.Server.Extensions = []argoprojv1alpha1.ArgoCDServerExtensionSpec{
+		{
+			Name:        "rollouts",
+			URL:         "https://example.com/rollouts.tar",
+			ChecksumURL: "https://example.com/rollouts.sha256",
+		},
+		{
+			Name:  "metrics",
+			URL:   "https://example.com/metrics.tar",
+			Image: "registry.example.com/extension-installer:latest",
+		},
+	}
+	assert.NilError(t, r.reconcileServerDeployment(a))
+
+	deployment := &appsv1.Deployment{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{
+		Name:      "argocd-server",
+		Namespace: testNamespace,
+	}, deployment))
+
+	podSpec := deployment.Spec.Template.Spec
+	assert.Equal(t, len(podSpec.InitContainers), 2)
+	assert.Equal(t, podSpec.InitContainers[0].Name, "extension-rollouts")
+	assert.Equal(t, podSpec.InitContainers[0].Image, "quay.io/argoprojlabs/argocd-extension-installer:v0.0.1")
+	assert.DeepEqual(t, podSpec.InitContainers[0].Env, []corev1.EnvVar{
+		{Name: "EXTENSION_NAME", Value: "rollouts"},
+		{Name: "EXTENSION_URL", Value: "https://example.com/rollouts.tar"},
+		{Name: "EXTENSION_CHECKSUM_URL", Value: "https://example.com/rollouts.sha256"},
+	})
+	assert.Equal(t, podSpec.InitContainers[1].Name, "extension-metrics")
+	assert.Equal(t, podSpec.InitContainers[1].Image, "registry.example.com/extension-installer:latest")
+	mounts := podSpec.Containers[0].VolumeMounts
+	assert.DeepEqual(t, mounts[len(mounts)-1], corev1.VolumeMount{Name: "extensions", MountPath: "/tmp/extensions/"})
+	assert.Equal(t, podSpec.Volumes[len(podSpec.Volumes)-1].Name, "extensions")
+
+	// The defaults set by the API server do not cause an update.
+	for i := range deployment.Spec.Template.Spec.InitContainers {
+		deployment.Spec.Template.Spec.InitContainers[i].TerminationMessagePath = corev1.TerminationMessagePathDefault
+		deployment.Spec.Template.Spec.InitContainers[i].TerminationMessagePolicy = corev1.TerminationMessageReadFile
+	}
+	assert.NilError(t, r.client.Update(context.TODO(), deployment))
+	assert.NilError(t, r.reconcileServerDeployment(a))
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{
+		Name:      "argocd-server",
+		Namespace: testNamespace,
+	}, deployment))
+	assert.Equal(t, deployment.Spec.Template.Spec.InitContainers[0].TerminationMessagePath, corev1.TerminationMessagePathDefault)
+
+	a.Spec.Server.Extensions = nil
+	assert.NilError(t, r.reconcileServerDeployment(a))
+
+	deployment = &appsv1.Deployment{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{
+		Name:      "argocd-server",
+		Namespace: testNamespace,
+	}, deployment))
+	assert.Equal(t, len(deployment.Spec.Template.Spec.InitContainers), 0)
+	for _, v := range deployment.Spec.Template.Spec.Volumes {
+		assert.Assert(t, v.Name != "extensions")
+	}
+}
+
 func TestReconcileArgoCD_reconcileServerDeployment(t *testing.T) {
 	logf.SetLogger(logf.ZapLogger(true))
 	a := makeTestArgoCD()
@@ -739,11 +1262,291 @@ func TestReconcileArgoCD_reconcileRedisDeployment_with_error(t *testing.T) {
 	assert.Error(t, r.reconcileRedisDeployment(cr), "this is a test error")
 }
 
+func TestReconcileArgoCD_reconcileRedisDeployment_serviceAccount(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	cr := makeTestArgoCD()
+	r := makeTestReconciler(t, cr)
+
+	assert.NilError(t, r.reconcileRedisDeployment(cr))
+	d := &appsv1.Deployment{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: cr.Name + "-redis", Namespace: cr.Namespace}, d))
+	assert.Equal(t, d.Spec.Template.Spec.ServiceAccountName, "argocd-argocd-redis")
+	assert.DeepEqual(t, d.Spec.Template.Spec.AutomountServiceAccountToken, boolPtr(false))
+
+	cr.Spec.Redis.AutomountServiceAccountToken = boolPtr(true)
+	assert.NilError(t, r.reconcileRedisDeployment(cr))
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: cr.Name + "-redis", Namespace: cr.Namespace}, d))
+	assert.DeepEqual(t, d.Spec.Template.Spec.AutomountServiceAccountToken, boolPtr(true))
+}
+
+func TestReconcileArgoCD_reconcileRedisDeployment_withRedisConfig(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	cr := makeTestArgoCD()
+	r := makeTestReconciler(t, cr)
+
+	assert.NilError(t, r.reconcileRedisDeployment(cr))
+	d := &appsv1.Deployment{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: cr.Name + "-redis", Namespace: cr.Namespace}, d))
+	assert.DeepEqual(t, d.Spec.Template.Spec.Containers[0].Args, []string{"--save", "", "--appendonly", "no"})
+
+	cr.Spec.Redis.Config = argoprojv1alpha1.ArgoCDRedisConfigSpec{
+		MaxMemory:       "512mb",
+		MaxMemoryPolicy: "allkeys-lru",
+		Save:            []argoprojv1alpha1.ArgoCDRedisSaveSpec{{Seconds: 900, Changes: 1}, {Seconds: 300, Changes: 10}},
+	}
+	assert.NilError(t, r.reconcileRedisDeployment(cr))
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: cr.Name + "-redis", Namespace: cr.Namespace}, d))
+	assert.DeepEqual(t, d.Spec.Template.Spec.Containers[0].Args, []string{
+		"--save", "900", "1",
+		"--save", "300", "10",
+		"--appendonly", "no",
+		"--maxmemory", "512mb",
+		"--maxmemory-policy", "allkeys-lru",
+	})
+
+	// A memory limit evicts keys by default instead of rejecting the writes.
+	cr.Spec.Redis.Config = argoprojv1alpha1.ArgoCDRedisConfigSpec{MaxMemory: "512mb"}
+	assert.NilError(t, r.reconcileRedisDeployment(cr))
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: cr.Name + "-redis", Namespace: cr.Namespace}, d))
+	assert.DeepEqual(t, d.Spec.Template.Spec.Containers[0].Args, []string{
+		"--save", "",
+		"--appendonly", "no",
+		"--maxmemory", "512mb",
+		"--maxmemory-policy", common.ArgoCDDefaultRedisMaxMemoryPolicy,
+	})
+}
+
+func TestReconcileArgoCD_reconcileServerDeployment_commandOverride(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD()
+	r := makeTestReconciler(t, a)
+	assert.NilError(t, r.reconcileServerDeployment(a))
+
+	a.Spec.Server.CommandOverride = argoprojv1alpha1.ArgoCDCommandOverrideSpec{
+		Command: []string{"/apm/wrapper"},
+		Args:    []string{"argocd-server", "--insecure"},
+	}
+	assert.NilError(t, r.reconcileServerDeployment(a))
+
+	d := &appsv1.Deployment{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-server", Namespace: a.Namespace}, d))
+	assert.DeepEqual(t, d.Spec.Template.Spec.Containers[0].Command, []string{"/apm/wrapper"})
+	assert.DeepEqual(t, d.Spec.Template.Spec.Containers[0].Args, []string{"argocd-server", "--insecure"})
+
+	// Removing the override restores the command managed by the operator
+	a.Spec.Server.CommandOverride = argoprojv1alpha1.ArgoCDCommandOverrideSpec{}
+	assert.NilError(t, r.reconcileServerDeployment(a))
+
+	d = &appsv1.Deployment{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-server", Namespace: a.Namespace}, d))
+	assert.DeepEqual(t, d.Spec.Template.Spec.Containers[0].Command, getArgoServerCommand(a))
+	assert.Assert(t, d.Spec.Template.Spec.Containers[0].Args == nil)
+}
+
+func TestReconcileArgoCD_reconcileServerDeployment_argsWithoutOverride(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD()
+	r := makeTestReconciler(t, a)
+	assert.NilError(t, r.reconcileServerDeployment(a))
+
+	// Arguments added to the container without an override are left alone
+	d := &appsv1.Deployment{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-server", Namespace: a.Namespace}, d))
+	d.Spec.Template.Spec.Containers[0].Args = []string{"--loglevel", "debug"}
+	assert.NilError(t, r.client.Update(context.TODO(), d))
+	assert.NilError(t, r.reconcileServerDeployment(a))
+
+	d = &appsv1.Deployment{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-server", Namespace: a.Namespace}, d))
+	assert.DeepEqual(t, d.Spec.Template.Spec.Containers[0].Args, []string{"--loglevel", "debug"})
+	_, ok := d.Spec.Template.Annotations[common.ArgoCDCommandOverrideAnnotation]
+	assert.Assert(t, !ok)
+
+	// Until an override is set and removed again
+	a.Spec.Server.CommandOverride.Args = []string{"--insecure"}
+	assert.NilError(t, r.reconcileServerDeployment(a))
+	a.Spec.Server.CommandOverride = argoprojv1alpha1.ArgoCDCommandOverrideSpec{}
+	assert.NilError(t, r.reconcileServerDeployment(a))
+
+	d = &appsv1.Deployment{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-server", Namespace: a.Namespace}, d))
+	assert.Assert(t, d.Spec.Template.Spec.Containers[0].Args == nil)
+	_, ok = d.Spec.Template.Annotations[common.ArgoCDCommandOverrideAnnotation]
+	assert.Assert(t, !ok)
+}
+
+func TestReconcileArgoCD_reconcileRedisDeployment_commandOverride(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.Redis.CommandOverride.Args = []string{"--maxclients", "20000"}
+	})
+	r := makeTestReconciler(t, a)
+	assert.NilError(t, r.reconcileRedisDeployment(a))
+
+	d := &appsv1.Deployment{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-redis", Namespace: a.Namespace}, d))
+	assert.DeepEqual(t, d.Spec.Template.Spec.Containers[0].Args, []string{"--maxclients", "20000"})
+	assert.Assert(t, d.Spec.Template.Spec.Containers[0].Command == nil)
+}
+
+func TestReconcileArgoCD_reconcileRedisHAProxyDeployment_serviceAccount(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	cr := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.HA.Enabled = true
+	})
+	r := makeTestReconciler(t, cr)
+
+	assert.NilError(t, r.reconcileRedisHAProxyDeployment(cr))
+	d := &appsv1.Deployment{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: cr.Name + "-redis-ha-haproxy", Namespace: cr.Namespace}, d))
+	assert.Equal(t, d.Spec.Template.Spec.ServiceAccountName, "argocd-argocd-redis-ha-haproxy")
+	assert.DeepEqual(t, d.Spec.Template.Spec.AutomountServiceAccountToken, boolPtr(false))
+
+	cr.Spec.HA.AutomountServiceAccountToken = boolPtr(true)
+	assert.NilError(t, r.reconcileRedisHAProxyDeployment(cr))
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: cr.Name + "-redis-ha-haproxy", Namespace: cr.Namespace}, d))
+	assert.DeepEqual(t, d.Spec.Template.Spec.AutomountServiceAccountToken, boolPtr(true))
+}
+
+func TestReconcileArgoCD_reconcileRedisHAProxyDeployment_configChecksum(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	os.Setenv("REDIS_CONFIG_PATH", "../../../build/redis")
+	t.Cleanup(func() {
+		os.Unsetenv("REDIS_CONFIG_PATH")
+	})
+
+	cr := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.HA.Enabled = true
+	})
+	r := makeTestReconciler(t, cr)
+	key := types.NamespacedName{Name: cr.Name + "-redis-ha-haproxy", Namespace: cr.Namespace}
+
+	assert.NilError(t, r.reconcileRedisHAProxyDeployment(cr))
+	d := &appsv1.Deployment{}
+	assert.NilError(t, r.client.Get(context.TODO(), key, d))
+	checksum := d.Spec.Template.Annotations[redisHAProxyConfigChecksumAnnotation]
+	assert.Assert(t, checksum != "")
+
+	// Changing the timeouts of the Redis HA Proxy rolls out the Deployment
+	cr.Spec.HA.HAProxy.TimeoutClient = &metav1.Duration{Duration: time.Hour}
+	assert.NilError(t, r.reconcileRedisHAProxyDeployment(cr))
+	d = &appsv1.Deployment{}
+	assert.NilError(t, r.client.Get(context.TODO(), key, d))
+	assert.Assert(t, d.Spec.Template.Annotations[redisHAProxyConfigChecksumAnnotation] != checksum)
+}
+
+func TestReconcileArgoCD_reconcileServerDeployment_automountServiceAccountToken(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	cr := makeTestArgoCD()
+	r := makeTestReconciler(t, cr)
+
+	assert.NilError(t, r.reconcileServerDeployment(cr))
+
+	cr.Spec.Server.AutomountServiceAccountToken = boolPtr(false)
+	assert.NilError(t, r.reconcileServerDeployment(cr))
+
+	d := &appsv1.Deployment{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: cr.Name + "-server", Namespace: cr.Namespace}, d))
+	assert.DeepEqual(t, d.Spec.Template.Spec.AutomountServiceAccountToken, boolPtr(false))
+}
+
+func TestReconcileArgoCD_reconcileServerDeployment_startupProbe(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	cr := makeTestArgoCD()
+	r := makeTestReconciler(t, cr)
+
+	assert.NilError(t, r.reconcileServerDeployment(cr))
+
+	cr.Spec.Server.StartupProbe = &corev1.Probe{
+		FailureThreshold: 60,
+		PeriodSeconds:    10,
+	}
+	assert.NilError(t, r.reconcileServerDeployment(cr))
+
+	d := &appsv1.Deployment{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: cr.Name + "-server", Namespace: cr.Namespace}, d))
+	want := &corev1.Probe{
+		Handler: corev1.Handler{
+			HTTPGet: &corev1.HTTPGetAction{
+				Path: "/healthz",
+				Port: intstr.FromInt(8080),
+			},
+		},
+		FailureThreshold: 60,
+		PeriodSeconds:    10,
+	}
+	assert.DeepEqual(t, d.Spec.Template.Spec.Containers[0].StartupProbe, want)
+
+	// The defaults set by the API server do not cause an update.
+	probe := d.Spec.Template.Spec.Containers[0].StartupProbe
+	probe.HTTPGet.Scheme = corev1.URISchemeHTTP
+	probe.TimeoutSeconds = 1
+	probe.SuccessThreshold = 1
+	assert.NilError(t, r.client.Update(context.TODO(), d))
+	assert.NilError(t, r.reconcileServerDeployment(cr))
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: cr.Name + "-server", Namespace: cr.Namespace}, d))
+	assert.Equal(t, d.Spec.Template.Spec.Containers[0].StartupProbe.HTTPGet.Scheme, corev1.URISchemeHTTP)
+
+	cr.Spec.Server.StartupProbe = nil
+	assert.NilError(t, r.reconcileServerDeployment(cr))
+
+	d = &appsv1.Deployment{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: cr.Name + "-server", Namespace: cr.Namespace}, d))
+	assert.Assert(t, d.Spec.Template.Spec.Containers[0].StartupProbe == nil)
+}
+
+func TestGetArgoServerCommand_tls(t *testing.T) {
+	cr := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.Server.TLS.MinVersion = "1.2"
+		a.Spec.Server.TLS.Ciphers = []string{
+			"TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384",
+			"TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384",
+		}
+	})
+
+	cmd := getArgoServerCommand(cr)
+	assert.DeepEqual(t, cmd[len(cmd)-4:], []string{
+		"--tlsminversion",
+		"1.2",
+		"--tlsciphers",
+		"TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384:TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384",
+	})
+}
+
+func TestGetArgoServerCommand_reverseProxy(t *testing.T) {
+	cr := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.Server.GRPCWebRootPath = "/argocd"
+		a.Spec.Server.EnableGZip = true
+	})
+
+	cmd := getArgoServerCommand(cr)
+	assert.DeepEqual(t, cmd[len(cmd)-3:], []string{
+		"--grpc-web-root-path",
+		"/argocd",
+		"--enable-gzip",
+	})
+}
+
+func TestGetArgoServerCommand_proxyExtension(t *testing.T) {
+	cr := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.Version = "v2.7.0"
+		a.Spec.Server.ProxyExtension.Enabled = true
+	})
+
+	cmd := getArgoServerCommand(cr)
+	assert.Equal(t, cmd[len(cmd)-1], "--enable-proxy-extension")
+
+	// The flag is not supported by earlier versions of the Argo CD server.
+	cr.Spec.Version = "v2.6.0"
+	cmd = getArgoServerCommand(cr)
+	assert.Assert(t, !containsString(cmd, "--enable-proxy-extension"))
+}
+
 func restoreEnv(t *testing.T) {
 	keys := []string{
 		"HTTP_PROXY", "HTTPS_PROXY", "NO_PROXY",
 		"http_proxy", "https_proxy", "no_proxy",
-		"DISABLE_DEX"}
+		"DISABLE_DEX", common.ArgoCDDefaultImagesPathEnvName}
 	env := map[string]string{}
 	for _, v := range keys {
 		env[v] = os.Getenv(v)
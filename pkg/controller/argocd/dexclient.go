@@ -0,0 +1,314 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocd
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
+	"github.com/argoproj-labs/argocd-operator/pkg/controller/argoutil"
+)
+
+const (
+	// dexStaticClientComponent is the component label value for the Secrets holding Dex static client secrets.
+	dexStaticClientComponent = "dex-static-client"
+
+	// dexConfigChecksumAnnotation is the annotation on the Dex pod template with the checksum of the Dex configuration.
+	dexConfigChecksumAnnotation = "checksum/dex-config"
+)
+
+// dexSecretReferencePattern matches the references to Secret values in the Dex configuration, either "$key" for a key
+// of the Argo CD Secret or "$name:key" for a key of another Secret.
+var dexSecretReferencePattern = regexp.MustCompile(`\$[A-Za-z0-9_.\-]+(:[A-Za-z0-9_.\-]+)?`)
+
+// DexStaticClient defines a static OAuth2 client in the Dex configuration.
+type DexStaticClient struct {
+	ID           string   `yaml:"id"`
+	Name         string   `yaml:"name,omitempty"`
+	Public       bool     `yaml:"public,omitempty"`
+	RedirectURIs []string `yaml:"redirectURIs,omitempty"`
+	Secret       string   `yaml:"secret,omitempty"`
+}
+
+// DexExternalOIDCConfig defines the Argo CD OIDC configuration for an external Dex server.
+type DexExternalOIDCConfig struct {
+	Name            string   `yaml:"name"`
+	Issuer          string   `yaml:"issuer"`
+	ClientID        string   `yaml:"clientID"`
+	ClientSecret    string   `yaml:"clientSecret"`
+	RequestedScopes []string `yaml:"requestedScopes"`
+}
+
+// isExternalDex will return true when the given ArgoCD uses a Dex server that is managed outside of the operator.
+func isExternalDex(cr *argoprojv1a1.ArgoCD) bool {
+	return cr.Spec.SSO != nil && cr.Spec.SSO.Dex != nil && cr.Spec.SSO.Dex.External != nil
+}
+
+// isManagedDexEnabled will return true when the operator should install Dex for the given ArgoCD. When .spec.sso is
+// set, Dex is only installed with the dex provider. Otherwise the deprecated DISABLE_DEX environment variable applies.
+func isManagedDexEnabled(cr *argoprojv1a1.ArgoCD) bool {
+	if cr.Spec.SSO != nil {
+		return cr.Spec.SSO.Provider == argoprojv1a1.SSOProviderTypeDex && !isExternalDex(cr)
+	}
+	return !isDexDisabled()
+}
+
+// getDexSpec will return the options of the Dex installed by the operator for the given ArgoCD, which are read from
+// .spec.sso.dex when .spec.sso is set and from the deprecated .spec.dex otherwise.
+func getDexSpec(cr *argoprojv1a1.ArgoCD) *argoprojv1a1.ArgoCDDexSpec {
+	if cr.Spec.SSO == nil {
+		return &cr.Spec.Dex
+	}
+	if cr.Spec.SSO.Dex != nil {
+		return &cr.Spec.SSO.Dex.ArgoCDDexSpec
+	}
+	return &argoprojv1a1.ArgoCDDexSpec{}
+}
+
+// getExternalDexClientSecret will return the client Secret of the external Dex of the given ArgoCD, or nil when
+// the ArgoCD does not use an external Dex or the Secret does not exist.
+func (r *ReconcileArgoCD) getExternalDexClientSecret(cr *argoprojv1a1.ArgoCD) *corev1.Secret {
+	if !isExternalDex(cr) {
+		return nil
+	}
+
+	secret := argoutil.NewSecretWithName(cr.ObjectMeta, cr.Spec.SSO.Dex.External.ClientSecret)
+	if !argoutil.IsObjectFound(r.client, cr.Namespace, secret.Name, secret) {
+		log.Info(fmt.Sprintf("client secret [%s] not found for external dex", secret.Name))
+		return nil
+	}
+	return secret
+}
+
+// getExternalDexOIDCConfig will return the OIDC configuration that points Argo CD at the external Dex of the given
+// ArgoCD. The client secret references the Argo CD Secret, which Argo CD substitutes when loading the configuration.
+func (r *ReconcileArgoCD) getExternalDexOIDCConfig(cr *argoprojv1a1.ArgoCD) (string, error) {
+	secret := r.getExternalDexClientSecret(cr)
+	if secret == nil {
+		return "", fmt.Errorf("client secret [%s] not found for external dex", cr.Spec.SSO.Dex.External.ClientSecret)
+	}
+
+	config := DexExternalOIDCConfig{
+		Name:            "Dex",
+		Issuer:          cr.Spec.SSO.Dex.External.IssuerURL,
+		ClientID:        string(secret.Data[common.ArgoCDKeyDexExternalClientID]),
+		ClientSecret:    "$" + common.ArgoCDKeyOIDCDexClientSecret,
+		RequestedScopes: []string{"openid", "profile", "email", "groups"},
+	}
+
+	bytes, err := yaml.Marshal(config)
+	return string(bytes), err
+}
+
+// getDexStaticClientSecretName will return the name of the Secret holding the client secret for the given client.
+func getDexStaticClientSecretName(c argoprojv1a1.ArgoCDDexStaticClientSpec, cr *argoprojv1a1.ArgoCD) string {
+	return nameWithSuffix("dex-client-"+sanitizeSecretName(c.ID), cr)
+}
+
+// getDexStaticClientSecretKey will return the key of the client secret for the given client in the Argo CD Secret.
+func getDexStaticClientSecretKey(c argoprojv1a1.ArgoCDDexStaticClientSpec) string {
+	return fmt.Sprintf("dex.%s.%s", sanitizeSecretName(c.ID), common.ArgoCDKeyDexStaticClientSecret)
+}
+
+// isDexStaticClientSecretKey will return true if the given key of the Argo CD Secret holds a Dex client secret.
+func isDexStaticClientSecretKey(key string) bool {
+	return strings.HasPrefix(key, "dex.") && strings.HasSuffix(key, "."+common.ArgoCDKeyDexStaticClientSecret)
+}
+
+// getDexConfigWithStaticClients will return the given Dex configuration with the static clients of the given
+// ArgoCD added. Client secrets reference the Argo CD Secret, which Argo CD substitutes when rendering the
+// configuration for Dex.
+func getDexConfigWithStaticClients(config string, cr *argoprojv1a1.ArgoCD) (string, error) {
+	if len(getDexSpec(cr).StaticClients) == 0 {
+		return config, nil
+	}
+
+	dex := make(map[string]interface{})
+	if err := yaml.Unmarshal([]byte(config), &dex); err != nil {
+		return "", fmt.Errorf("failed to parse dex configuration: %w", err)
+	}
+
+	clients := make([]DexStaticClient, 0, len(getDexSpec(cr).StaticClients))
+	for _, c := range getDexSpec(cr).StaticClients {
+		staticClient := DexStaticClient{
+			ID:           c.ID,
+			Name:         c.Name,
+			Public:       c.Public,
+			RedirectURIs: c.RedirectURIs,
+		}
+		if !c.Public {
+			staticClient.Secret = "$" + getDexStaticClientSecretKey(c)
+		}
+		clients = append(clients, staticClient)
+	}
+	dex["staticClients"] = clients
+
+	bytes, err := yaml.Marshal(dex)
+	return string(bytes), err
+}
+
+// getDexConfigWithIDTokenExpiration will return the given Dex configuration with the expiration of the ID tokens set
+// from the session options of the given ArgoCD.
+func getDexConfigWithIDTokenExpiration(config string, cr *argoprojv1a1.ArgoCD) (string, error) {
+	expiration := cr.Spec.Server.Session.IDTokenExpiration
+	if expiration == nil {
+		return config, nil
+	}
+
+	dex := make(map[string]interface{})
+	if err := yaml.Unmarshal([]byte(config), &dex); err != nil {
+		return "", fmt.Errorf("failed to parse dex configuration: %w", err)
+	}
+
+	expiry, ok := dex["expiry"].(map[interface{}]interface{})
+	if !ok {
+		expiry = make(map[interface{}]interface{})
+	}
+	expiry["idTokens"] = expiration.Duration.String()
+	dex["expiry"] = expiry
+
+	bytes, err := yaml.Marshal(dex)
+	return string(bytes), err
+}
+
+// getDexStaticClientSecrets will return the client secrets of the Dex static clients of the given ArgoCD, keyed by
+// their key in the Argo CD Secret.
+func (r *ReconcileArgoCD) getDexStaticClientSecrets(cr *argoprojv1a1.ArgoCD) map[string][]byte {
+	secrets := make(map[string][]byte)
+	for _, c := range getDexSpec(cr).StaticClients {
+		if c.Public {
+			continue
+		}
+
+		secret := argoutil.NewSecretWithName(cr.ObjectMeta, getDexStaticClientSecretName(c, cr))
+		if !argoutil.IsObjectFound(r.client, cr.Namespace, secret.Name, secret) {
+			log.Info(fmt.Sprintf("client secret [%s] not found for dex static client [%s]", secret.Name, c.ID))
+			continue
+		}
+		secrets[getDexStaticClientSecretKey(c)] = secret.Data[common.ArgoCDKeyDexStaticClientSecret]
+	}
+	return secrets
+}
+
+// reconcileDexStaticClientSecrets will ensure that a Secret with a generated client secret is present for each
+// confidential Dex static client of the given ArgoCD, and that Secrets for clients that have been removed are deleted.
+func (r *ReconcileArgoCD) reconcileDexStaticClientSecrets(cr *argoprojv1a1.ArgoCD) error {
+	desired := make(map[string]bool)
+	for _, c := range getDexSpec(cr).StaticClients {
+		if c.Public {
+			continue
+		}
+
+		secret := argoutil.NewSecretWithName(cr.ObjectMeta, getDexStaticClientSecretName(c, cr))
+		secret.Labels[common.ArgoCDKeyComponent] = dexStaticClientComponent
+		desired[secret.Name] = true
+
+		if argoutil.IsObjectFound(r.client, cr.Namespace, secret.Name, secret) {
+			continue // Secret found, keep the existing client secret
+		}
+
+		clientSecret, err := generateOAuthClientSecret()
+		if err != nil {
+			return err
+		}
+
+		secret.Data = map[string][]byte{
+			common.ArgoCDKeyDexStaticClientSecret: clientSecret,
+		}
+
+		if err := controllerutil.SetControllerReference(cr, secret, r.scheme); err != nil {
+			return err
+		}
+		if err := r.client.Create(context.TODO(), secret); err != nil {
+			return err
+		}
+	}
+
+	secrets := &corev1.SecretList{}
+	opts := []client.ListOption{
+		client.InNamespace(cr.Namespace),
+		client.MatchingLabels{
+			common.ArgoCDKeyComponent: dexStaticClientComponent,
+			common.ArgoCDKeyManagedBy: cr.Name,
+			common.ArgoCDKeyPartOf:    common.ArgoCDAppName,
+		},
+	}
+	if err := r.client.List(context.TODO(), secrets, opts...); err != nil {
+		return err
+	}
+	for i := range secrets.Items {
+		if desired[secrets.Items[i].Name] {
+			continue
+		}
+		if err := r.client.Delete(context.TODO(), &secrets.Items[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getDexConfigChecksum will return the checksum of the Dex configuration in the Argo CD ConfigMap and of the Secret
+// values that the configuration references, such as connector and static client secrets. Argo CD only substitutes
+// the Secret values when Dex starts, so Dex is restarted when the checksum changes.
+func (r *ReconcileArgoCD) getDexConfigChecksum(cr *argoprojv1a1.ArgoCD) string {
+	cm := newConfigMapWithName(common.ArgoCDConfigMapName, cr)
+	if !argoutil.IsObjectFound(r.client, cr.Namespace, cm.Name, cm) {
+		return ""
+	}
+	config := cm.Data[common.ArgoCDKeyDexConfig]
+
+	refs := dexSecretReferencePattern.FindAllString(config, -1)
+	sort.Strings(refs)
+
+	sum := sha256.New()
+	sum.Write([]byte(config))
+	secrets := make(map[string]*corev1.Secret)
+	for i, ref := range refs {
+		if i > 0 && refs[i-1] == ref {
+			continue
+		}
+
+		name, key := common.ArgoCDSecretName, strings.TrimPrefix(ref, "$")
+		if parts := strings.SplitN(key, ":", 2); len(parts) == 2 {
+			name, key = parts[0], parts[1]
+		}
+
+		secret, ok := secrets[name]
+		if !ok {
+			secret = argoutil.NewSecretWithName(cr.ObjectMeta, name)
+			if !argoutil.IsObjectFound(r.client, cr.Namespace, name, secret) {
+				secret = nil
+			}
+			secrets[name] = secret
+		}
+
+		sum.Write([]byte(ref))
+		if secret != nil {
+			sum.Write(secret.Data[key])
+		}
+	}
+	return fmt.Sprintf("%x", sum.Sum(nil))
+}
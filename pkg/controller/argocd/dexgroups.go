@@ -0,0 +1,124 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocd
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
+)
+
+// getDexGroups will return the mapping of the Dex groups into the Argo CD RBAC for the given ArgoCD, or nil when it is
+// not set or the managed Dex is not enabled.
+func getDexGroups(cr *argoprojv1a1.ArgoCD) *argoprojv1a1.ArgoCDDexGroupsSpec {
+	if cr.Spec.SSO == nil || cr.Spec.SSO.Dex == nil || !isManagedDexEnabled(cr) {
+		return nil
+	}
+	return cr.Spec.SSO.Dex.Groups
+}
+
+// getDexGroupsScope will return the OIDC scope holding the groups of the users for the given Dex groups.
+func getDexGroupsScope(groups *argoprojv1a1.ArgoCDDexGroupsSpec) string {
+	if groups.Scope != "" {
+		return groups.Scope
+	}
+	return common.ArgoCDDefaultDexGroupsScope
+}
+
+// getDexConfigWithGroups will return the given Dex configuration with the login through the OpenShift connectors
+// restricted to the allowed groups of the given ArgoCD.
+func getDexConfigWithGroups(config string, cr *argoprojv1a1.ArgoCD) (string, error) {
+	groups := getDexGroups(cr)
+	if groups == nil || len(groups.Allowed) == 0 {
+		return config, nil
+	}
+
+	dex := make(map[string]interface{})
+	if err := yaml.Unmarshal([]byte(config), &dex); err != nil {
+		return "", fmt.Errorf("failed to parse dex configuration: %w", err)
+	}
+
+	connectors, _ := dex["connectors"].([]interface{})
+	for _, c := range connectors {
+		connector, ok := c.(map[interface{}]interface{})
+		if !ok || connector["type"] != "openshift" {
+			continue
+		}
+		cfg, ok := connector["config"].(map[interface{}]interface{})
+		if !ok {
+			cfg = make(map[interface{}]interface{})
+			connector["config"] = cfg
+		}
+		cfg["groups"] = groups.Allowed
+	}
+
+	bytes, err := yaml.Marshal(dex)
+	return string(bytes), err
+}
+
+// getRBACPolicyWithDexGroups will return the given RBAC policy with the bindings of the Dex groups of the given
+// ArgoCD to Argo CD roles appended.
+func getRBACPolicyWithDexGroups(policy string, cr *argoprojv1a1.ArgoCD) string {
+	groups := getDexGroups(cr)
+	if groups == nil || len(groups.Bindings) == 0 {
+		return policy
+	}
+
+	lines := make([]string, 0, len(groups.Bindings))
+	for _, binding := range groups.Bindings {
+		if !isValidDexGroupBindingName(binding.Group) || !isValidDexGroupBindingName(binding.Role) {
+			continue // Would inject extra policy lines, rejected by the validation
+		}
+		lines = append(lines, fmt.Sprintf("g, %s, %s", binding.Group, binding.Role))
+	}
+	if len(lines) == 0 {
+		return policy
+	}
+	if policy != "" && !strings.HasSuffix(policy, "\n") {
+		policy += "\n"
+	}
+	return policy + strings.Join(lines, "\n") + "\n"
+}
+
+// isValidDexGroupBindingName returns true if the given group or role name of a Dex group binding can be written to a
+// policy line, which requires it to be non-empty and without the separators of the policy CSV.
+func isValidDexGroupBindingName(name string) bool {
+	return strings.TrimSpace(name) != "" && !strings.ContainsAny(name, ",\r\n")
+}
+
+// getRBACScopesWithDexGroups will return the given RBAC scopes with the scope holding the Dex groups of the given
+// ArgoCD added.
+func getRBACScopesWithDexGroups(scopes string, cr *argoprojv1a1.ArgoCD) string {
+	groups := getDexGroups(cr)
+	if groups == nil {
+		return scopes
+	}
+
+	scope := getDexGroupsScope(groups)
+	list := make([]string, 0)
+	for _, s := range strings.Split(strings.Trim(strings.TrimSpace(scopes), "[]"), ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			if s == scope {
+				return scopes
+			}
+			list = append(list, s)
+		}
+	}
+	return fmt.Sprintf("[%s]", strings.Join(append(list, scope), ","))
+}
@@ -0,0 +1,115 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocd
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+
+	corev1 "k8s.io/api/core/v1"
+
+	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/pkg/controller/argoutil"
+)
+
+const (
+	// dexMigrationEventReason is the reason of the Event emitted when an ArgoCD relies on the deprecated Dex settings.
+	dexMigrationEventReason = "DeprecatedDexConfiguration"
+
+	// dexDisabledEventReason is the reason of the Event emitted when the deprecated Dex settings of an ArgoCD are
+	// ignored as Dex is not installed.
+	dexDisabledEventReason = "DexDisabled"
+)
+
+// getMigratedSSO will return the .spec.sso equivalent of the deprecated Dex settings of the given ArgoCD, i.e. the
+// .spec.dex field and the DISABLE_DEX environment variable of the operator, or nil when the ArgoCD does not rely on
+// them. An ArgoCD with .spec.sso set is never migrated, as the deprecated settings are ignored.
+func getMigratedSSO(cr *argoprojv1a1.ArgoCD) *argoprojv1a1.ArgoCDSSOSpec {
+	if cr.Spec.SSO != nil {
+		return nil
+	}
+
+	legacyDex := !reflect.DeepEqual(cr.Spec.Dex, argoprojv1a1.ArgoCDDexSpec{})
+	if os.Getenv("DISABLE_DEX") == "" && !legacyDex {
+		return nil // Default Dex without any settings, nothing to migrate...
+	}
+
+	sso := &argoprojv1a1.ArgoCDSSOSpec{}
+	if !isDexDisabled() {
+		sso.Provider = argoprojv1a1.SSOProviderTypeDex
+		sso.Dex = &argoprojv1a1.ArgoCDSSODexSpec{
+			ArgoCDDexSpec: *cr.Spec.Dex.DeepCopy(),
+		}
+	}
+	return sso
+}
+
+// getDexDisabledCondition will return the DexDisabled condition for the given ArgoCD when Dex is not installed although
+// the deprecated .spec.dex field is set, or nil otherwise.
+func getDexDisabledCondition(cr *argoprojv1a1.ArgoCD) *argoprojv1a1.ArgoCDCondition {
+	if isManagedDexEnabled(cr) || reflect.DeepEqual(cr.Spec.Dex, argoprojv1a1.ArgoCDDexSpec{}) {
+		return nil
+	}
+
+	condition := &argoprojv1a1.ArgoCDCondition{
+		Type:    argoprojv1a1.ArgoCDConditionTypeDexDisabled,
+		Status:  corev1.ConditionTrue,
+		Reason:  "DisabledByEnvironment",
+		Message: "Dex is not installed as the DISABLE_DEX environment variable of the operator is true, .spec.dex is ignored",
+	}
+	if cr.Spec.SSO != nil {
+		condition.Reason = "SSOProviderNotDex"
+		condition.Message = fmt.Sprintf("Dex is not installed as .spec.sso.provider is %q, .spec.dex is ignored", cr.Spec.SSO.Provider)
+	}
+	return condition
+}
+
+// reconcileStatusDexMigration will ensure that the MigratedSSO status reflects the deprecated Dex settings that the
+// given ArgoCD relies on, and that the DexDisabled condition reports the deprecated settings that are ignored. An
+// Event is emitted whenever either of them changes so that the deprecation is visible.
+func (r *ReconcileArgoCD) reconcileStatusDexMigration(cr *argoprojv1a1.ArgoCD) error {
+	changed := false
+
+	if migrated := getMigratedSSO(cr); !reflect.DeepEqual(cr.Status.MigratedSSO, migrated) {
+		if migrated != nil {
+			message := fmt.Sprintf("ArgoCD %s relies on the deprecated .spec.dex field or DISABLE_DEX environment variable, "+
+				"the equivalent .spec.sso configuration is available in .status.migratedSSO", cr.Name)
+			log.Info(message)
+			if err := argoutil.CreateEvent(r.client, "Deprecated", message, dexMigrationEventReason, cr.ObjectMeta); err != nil {
+				return err
+			}
+		}
+		cr.Status.MigratedSSO = migrated
+		changed = true
+	}
+
+	if condition := getDexDisabledCondition(cr); condition != nil {
+		if setArgoCDCondition(cr, *condition) {
+			log.Info(fmt.Sprintf("ArgoCD %s: %s", cr.Name, condition.Message))
+			if err := argoutil.CreateEvent(r.client, "Disabled", condition.Message, dexDisabledEventReason, cr.ObjectMeta); err != nil {
+				return err
+			}
+			changed = true
+		}
+	} else if removeArgoCDCondition(cr, argoprojv1a1.ArgoCDConditionTypeDexDisabled) {
+		changed = true
+	}
+
+	if changed {
+		return r.updateArgoCDStatus(cr)
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocd
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+
+	argoprojv1alpha1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+)
+
+func TestGetMigratedSSO(t *testing.T) {
+	restoreEnv(t)
+	os.Unsetenv("DISABLE_DEX")
+
+	a := makeTestArgoCD()
+	assert.Assert(t, getMigratedSSO(a) == nil)
+
+	a.Spec.Dex.Config = "connectors: []"
+	a.Spec.Dex.OpenShiftOAuth = true
+	want := &argoprojv1alpha1.ArgoCDSSOSpec{
+		Provider: argoprojv1alpha1.SSOProviderTypeDex,
+		Dex: &argoprojv1alpha1.ArgoCDSSODexSpec{
+			ArgoCDDexSpec: argoprojv1alpha1.ArgoCDDexSpec{
+				Config:         "connectors: []",
+				OpenShiftOAuth: true,
+			},
+		},
+	}
+	assert.DeepEqual(t, getMigratedSSO(a), want)
+
+	// Dex disabled with the environment variable migrates to .spec.sso without the dex provider.
+	os.Setenv("DISABLE_DEX", "true")
+	assert.DeepEqual(t, getMigratedSSO(a), &argoprojv1alpha1.ArgoCDSSOSpec{})
+
+	// The deprecated settings are ignored once .spec.sso is set.
+	a.Spec.SSO = &argoprojv1alpha1.ArgoCDSSOSpec{Provider: argoprojv1alpha1.SSOProviderTypeKeycloak}
+	assert.Assert(t, getMigratedSSO(a) == nil)
+}
+
+func TestReconcileArgoCD_reconcileStatusDexMigration(t *testing.T) {
+	restoreEnv(t)
+	os.Unsetenv("DISABLE_DEX")
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.Dex.Image = "dex"
+	})
+	r := makeTestReconciler(t, a)
+
+	assert.NilError(t, r.reconcileStatusDexMigration(a))
+	assert.Equal(t, a.Status.MigratedSSO.Provider, argoprojv1alpha1.SSOProviderTypeDex)
+	assert.Equal(t, a.Status.MigratedSSO.Dex.Image, "dex")
+
+	events := &corev1.EventList{}
+	assert.NilError(t, r.client.List(context.TODO(), events))
+	assert.Equal(t, len(events.Items), 1)
+	assert.Equal(t, events.Items[0].Reason, dexMigrationEventReason)
+
+	// The event is only emitted when the migrated form changes.
+	assert.NilError(t, r.reconcileStatusDexMigration(a))
+	assert.NilError(t, r.client.List(context.TODO(), events))
+	assert.Equal(t, len(events.Items), 1)
+
+	a.Spec.Dex = argoprojv1alpha1.ArgoCDDexSpec{}
+	a.Spec.SSO = &argoprojv1alpha1.ArgoCDSSOSpec{
+		Provider: argoprojv1alpha1.SSOProviderTypeDex,
+		Dex: &argoprojv1alpha1.ArgoCDSSODexSpec{
+			ArgoCDDexSpec: argoprojv1alpha1.ArgoCDDexSpec{Image: "dex"},
+		},
+	}
+	assert.NilError(t, r.reconcileStatusDexMigration(a))
+	assert.Assert(t, a.Status.MigratedSSO == nil)
+}
+
+func TestReconcileArgoCD_reconcileStatusDexMigration_dexDisabled(t *testing.T) {
+	restoreEnv(t)
+	os.Unsetenv("DISABLE_DEX")
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.Dex.Image = "dex"
+		a.Spec.SSO = &argoprojv1alpha1.ArgoCDSSOSpec{Provider: argoprojv1alpha1.SSOProviderTypeKeycloak}
+	})
+	r := makeTestReconciler(t, a)
+
+	assert.NilError(t, r.reconcileStatusDexMigration(a))
+	assert.Assert(t, isArgoCDConditionTrue(a, argoprojv1alpha1.ArgoCDConditionTypeDexDisabled))
+
+	events := &corev1.EventList{}
+	assert.NilError(t, r.client.List(context.TODO(), events))
+	assert.Equal(t, len(events.Items), 1)
+	assert.Equal(t, events.Items[0].Reason, dexDisabledEventReason)
+
+	// The event is only emitted when the condition changes.
+	assert.NilError(t, r.reconcileStatusDexMigration(a))
+	assert.NilError(t, r.client.List(context.TODO(), events))
+	assert.Equal(t, len(events.Items), 1)
+
+	a.Spec.SSO = nil
+	os.Setenv("DISABLE_DEX", "true")
+	assert.NilError(t, r.reconcileStatusDexMigration(a))
+	assert.Assert(t, isArgoCDConditionTrue(a, argoprojv1alpha1.ArgoCDConditionTypeDexDisabled))
+
+	a.Spec.Dex = argoprojv1alpha1.ArgoCDDexSpec{}
+	assert.NilError(t, r.reconcileStatusDexMigration(a))
+	assert.Assert(t, !isArgoCDConditionTrue(a, argoprojv1alpha1.ArgoCDConditionTypeDexDisabled))
+	assert.Equal(t, len(a.Status.Conditions), 0)
+}
+
+func TestIsManagedDexEnabled(t *testing.T) {
+	restoreEnv(t)
+	os.Setenv("DISABLE_DEX", "true")
+
+	a := makeTestArgoCD()
+	assert.Assert(t, !isManagedDexEnabled(a))
+
+	// The dex provider ignores the deprecated environment variable.
+	a.Spec.SSO = &argoprojv1alpha1.ArgoCDSSOSpec{Provider: argoprojv1alpha1.SSOProviderTypeDex}
+	assert.Assert(t, isManagedDexEnabled(a))
+
+	os.Unsetenv("DISABLE_DEX")
+	a.Spec.SSO.Provider = argoprojv1alpha1.SSOProviderTypeKeycloak
+	assert.Assert(t, !isManagedDexEnabled(a))
+}
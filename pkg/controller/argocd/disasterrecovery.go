@@ -0,0 +1,322 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocd
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/sethvargo/go-password/password"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
+	"github.com/argoproj-labs/argocd-operator/pkg/controller/argoutil"
+)
+
+// disasterRecoverySecret is a Secret mirrored in a disaster recovery bundle.
+type disasterRecoverySecret struct {
+	Annotations map[string]string `json:"annotations,omitempty"`
+	Data        map[string][]byte `json:"data,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Name        string            `json:"name"`
+	Type        corev1.SecretType `json:"type,omitempty"`
+}
+
+// generateDisasterRecoveryKey will generate and return the key used to seal the disaster recovery bundle.
+func generateDisasterRecoveryKey() ([]byte, error) {
+	pass, err := password.Generate(
+		common.ArgoCDDefaultBackupKeyLength,
+		common.ArgoCDDefaultBackupKeyNumDigits,
+		common.ArgoCDDefaultBackupKeyNumSymbols,
+		false, false)
+
+	return []byte(pass), err
+}
+
+// getDisasterRecoveryMode will return the disaster recovery mode for the given ArgoCD.
+func getDisasterRecoveryMode(cr *argoprojv1a1.ArgoCD) string {
+	if cr.Spec.DisasterRecovery == nil || cr.Spec.DisasterRecovery.Mode == "" {
+		return common.ArgoCDDisasterRecoveryModeMirror
+	}
+	return cr.Spec.DisasterRecovery.Mode
+}
+
+// getDisasterRecoveryKeySecretName will return the name of the Secret with the key used to seal the bundle.
+func getDisasterRecoveryKeySecretName(cr *argoprojv1a1.ArgoCD) string {
+	if cr.Spec.DisasterRecovery != nil && cr.Spec.DisasterRecovery.KeySecretName != "" {
+		return cr.Spec.DisasterRecovery.KeySecretName
+	}
+	return nameWithSuffix("disaster-recovery-key", cr)
+}
+
+// isDisasterRecoverySecret will return true if the given Secret is one of the critical Secrets of the given ArgoCD
+// that are mirrored into the disaster recovery bundle.
+func isDisasterRecoverySecret(cr *argoprojv1a1.ArgoCD, secret *corev1.Secret) bool {
+	if secret.Namespace != cr.Namespace {
+		return false
+	}
+	if secret.Name == common.ArgoCDSecretName || secret.Name == nameWithSuffix("cluster", cr) {
+		return true
+	}
+	switch secret.Labels[common.ArgoCDSecretTypeLabel] {
+	case common.ArgoCDSecretTypeCluster, common.ArgoCDSecretTypeRepository, common.ArgoCDSecretTypeRepoCreds:
+		return true
+	}
+	return false
+}
+
+// getDisasterRecoverySecrets will return the critical Secrets of the given ArgoCD, sorted by name: the Argo CD Secret,
+// the admin credentials Secret and the cluster, repository and repository credential template Secrets.
+func (r *ReconcileArgoCD) getDisasterRecoverySecrets(cr *argoprojv1a1.ArgoCD) ([]disasterRecoverySecret, error) {
+	secrets := &corev1.SecretList{}
+	if err := r.client.List(context.TODO(), secrets, client.InNamespace(cr.Namespace)); err != nil {
+		return nil, err
+	}
+
+	result := make([]disasterRecoverySecret, 0)
+	for _, secret := range secrets.Items {
+		if !isDisasterRecoverySecret(cr, &secret) {
+			continue
+		}
+		result = append(result, disasterRecoverySecret{
+			Annotations: secret.Annotations,
+			Data:        secret.Data,
+			Labels:      secret.Labels,
+			Name:        secret.Name,
+			Type:        secret.Type,
+		})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result, nil
+}
+
+// newDisasterRecoveryCipher will return the AEAD cipher for the given key.
+func newDisasterRecoveryCipher(key []byte) (cipher.AEAD, error) {
+	sum := sha256.Sum256(key)
+	block, err := aes.NewCipher(sum[:])
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// getDisasterRecoveryChecksum will return the keyed checksum of the given unsealed bundle content.
+func getDisasterRecoveryChecksum(key []byte, content []byte) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(content)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// sealDisasterRecoveryBundle will encrypt the given content with the given key, using AES-256-GCM with a key derived
+// from the SHA-256 sum of the given key. The nonce is prepended to the result.
+func sealDisasterRecoveryBundle(key []byte, content []byte) ([]byte, error) {
+	gcm, err := newDisasterRecoveryCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, content, nil), nil
+}
+
+// unsealDisasterRecoveryBundle will decrypt the given bundle, sealed by sealDisasterRecoveryBundle, with the given key.
+func unsealDisasterRecoveryBundle(key []byte, bundle []byte) ([]byte, error) {
+	gcm, err := newDisasterRecoveryCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(bundle) < gcm.NonceSize() {
+		return nil, errors.New("disaster recovery bundle is too short")
+	}
+	nonce, sealed := bundle[:gcm.NonceSize()], bundle[gcm.NonceSize():]
+	return gcm.Open(nil, nonce, sealed, nil)
+}
+
+// isDisasterRecoveryObjectOwned will return true if the given object, outside of the namespace of the given ArgoCD,
+// was created for it by the operator.
+func isDisasterRecoveryObjectOwned(cr *argoprojv1a1.ArgoCD, obj metav1.Object) bool {
+	return obj.GetAnnotations()[common.AnnotationName] == cr.Name &&
+		obj.GetAnnotations()[common.AnnotationNamespace] == cr.Namespace
+}
+
+// reconcileDisasterRecoveryKey will ensure that the Secret with the key used to seal the disaster recovery bundle is
+// present and return the key. A key is only generated in the mirror mode, the restore mode requires the original key.
+// The key Secret is kept outside of the namespace of the ArgoCD, so that it is not lost with the Secrets it protects.
+func (r *ReconcileArgoCD) reconcileDisasterRecoveryKey(cr *argoprojv1a1.ArgoCD) ([]byte, error) {
+	secret := argoutil.NewSecretWithName(metav1.ObjectMeta{
+		Name:      cr.Name,
+		Namespace: cr.Spec.DisasterRecovery.KeySecretNamespace,
+	}, getDisasterRecoveryKeySecretName(cr))
+	found, err := r.isLiveObjectFound(secret.Namespace, secret.Name, secret)
+	if err != nil {
+		return nil, err
+	}
+	if found {
+		if len(secret.Data[common.ArgoCDKeyBackupKey]) <= 0 {
+			return nil, fmt.Errorf("disaster recovery key secret %s in namespace %s has no %s key", secret.Name, secret.Namespace, common.ArgoCDKeyBackupKey)
+		}
+		return secret.Data[common.ArgoCDKeyBackupKey], nil
+	}
+
+	if getDisasterRecoveryMode(cr) == common.ArgoCDDisasterRecoveryModeRestore {
+		return nil, fmt.Errorf("disaster recovery key secret %s not found in namespace %s", secret.Name, secret.Namespace)
+	}
+
+	key, err := generateDisasterRecoveryKey()
+	if err != nil {
+		return nil, err
+	}
+	secret.Annotations = argoutil.DefaultAnnotations(cr)
+	secret.Data = map[string][]byte{
+		common.ArgoCDKeyBackupKey: key,
+	}
+
+	// The key is not owned by the ArgoCD, so that it outlives the instance it is needed to recover.
+	log.Info(fmt.Sprintf("creating disaster recovery key secret %s in namespace %s", secret.Name, secret.Namespace))
+	if err := r.client.Create(context.TODO(), secret); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// reconcileDisasterRecovery will keep the sealed bundle of the critical Secrets in the disaster recovery namespace up
+// to date, or restore the missing Secrets from the bundle, depending on the disaster recovery mode.
+func (r *ReconcileArgoCD) reconcileDisasterRecovery(cr *argoprojv1a1.ArgoCD) error {
+	if cr.Spec.DisasterRecovery == nil {
+		return nil
+	}
+
+	key, err := r.reconcileDisasterRecoveryKey(cr)
+	if err != nil {
+		return err
+	}
+
+	if getDisasterRecoveryMode(cr) == common.ArgoCDDisasterRecoveryModeRestore {
+		return r.restoreDisasterRecoveryBundle(cr, key)
+	}
+	return r.reconcileDisasterRecoveryBundle(cr, key)
+}
+
+// reconcileDisasterRecoveryBundle will ensure that the sealed bundle in the disaster recovery namespace matches the
+// current critical Secrets of the given ArgoCD. The bundle is only resealed when the Secrets change.
+func (r *ReconcileArgoCD) reconcileDisasterRecoveryBundle(cr *argoprojv1a1.ArgoCD, key []byte) error {
+	secrets, err := r.getDisasterRecoverySecrets(cr)
+	if err != nil {
+		return err
+	}
+
+	content, err := json.Marshal(secrets)
+	if err != nil {
+		return err
+	}
+	checksum := getDisasterRecoveryChecksum(key, content)
+
+	bundle := argoutil.NewSecretWithName(metav1.ObjectMeta{
+		Name:      cr.Name,
+		Namespace: cr.Spec.DisasterRecovery.Namespace,
+	}, nameWithSuffix("disaster-recovery", cr))
+	found, err := r.isLiveObjectFound(bundle.Namespace, bundle.Name, bundle)
+	if err != nil {
+		return err
+	}
+	if found && !isDisasterRecoveryObjectOwned(cr, bundle) {
+		return fmt.Errorf("refusing to overwrite secret %s in namespace %s, it is not a disaster recovery bundle of this ArgoCD", bundle.Name, bundle.Namespace)
+	}
+	if found && bundle.Annotations[common.ArgoCDDisasterRecoveryChecksumAnnotation] == checksum {
+		return nil // Bundle is up to date
+	}
+
+	sealed, err := sealDisasterRecoveryBundle(key, content)
+	if err != nil {
+		return err
+	}
+
+	bundle.Annotations = argoutil.AppendStringMap(bundle.Annotations, argoutil.DefaultAnnotations(cr))
+	bundle.Annotations[common.ArgoCDDisasterRecoveryChecksumAnnotation] = checksum
+	bundle.Data = map[string][]byte{
+		common.ArgoCDKeyDisasterRecoveryBundle: sealed,
+	}
+
+	// The bundle lives in another namespace and is not owned by the ArgoCD, so that it survives the instance.
+	if found {
+		log.Info(fmt.Sprintf("updating disaster recovery bundle %s in namespace %s", bundle.Name, bundle.Namespace))
+		return r.client.Update(context.TODO(), bundle)
+	}
+	log.Info(fmt.Sprintf("creating disaster recovery bundle %s in namespace %s", bundle.Name, bundle.Namespace))
+	return r.client.Create(context.TODO(), bundle)
+}
+
+// restoreDisasterRecoveryBundle will create the Secrets from the sealed bundle in the disaster recovery namespace that
+// are missing from the namespace of the given ArgoCD. Existing Secrets are never overwritten.
+func (r *ReconcileArgoCD) restoreDisasterRecoveryBundle(cr *argoprojv1a1.ArgoCD, key []byte) error {
+	bundle := &corev1.Secret{}
+	name := nameWithSuffix("disaster-recovery", cr)
+	bundleKey := client.ObjectKey{Namespace: cr.Spec.DisasterRecovery.Namespace, Name: name}
+	if err := r.reader.Get(context.TODO(), bundleKey, bundle); err != nil {
+		return fmt.Errorf("failed to get the disaster recovery bundle %s in namespace %s : %w", name, cr.Spec.DisasterRecovery.Namespace, err)
+	}
+
+	content, err := unsealDisasterRecoveryBundle(key, bundle.Data[common.ArgoCDKeyDisasterRecoveryBundle])
+	if err != nil {
+		return fmt.Errorf("failed to unseal the disaster recovery bundle %s : %w", name, err)
+	}
+
+	secrets := make([]disasterRecoverySecret, 0)
+	if err := json.Unmarshal(content, &secrets); err != nil {
+		return err
+	}
+
+	for _, s := range secrets {
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: s.Annotations,
+				Labels:      s.Labels,
+				Name:        s.Name,
+				Namespace:   cr.Namespace,
+			},
+			Data: s.Data,
+			Type: s.Type,
+		}
+		if argoutil.IsObjectFound(r.client, cr.Namespace, secret.Name, &corev1.Secret{}) {
+			continue
+		}
+
+		log.Info(fmt.Sprintf("restoring secret %s from disaster recovery bundle %s", secret.Name, name))
+		if err := r.client.Create(context.TODO(), secret); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,176 @@
+package argocd
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+
+	argoprojv1alpha1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
+)
+
+const (
+	testDisasterRecoveryNamespace    = "argocd-dr"
+	testDisasterRecoveryKeyNamespace = "argocd-dr-keys"
+)
+
+func makeTestDisasterRecoverySecrets() []*corev1.Secret {
+	return []*corev1.Secret{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: common.ArgoCDSecretName, Namespace: testNamespace},
+			Data:       map[string][]byte{"server.secretkey": []byte("session")},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "prod-cluster",
+				Namespace: testNamespace,
+				Labels:    map[string]string{common.ArgoCDSecretTypeLabel: common.ArgoCDSecretTypeCluster},
+			},
+			Data: map[string][]byte{"server": []byte("https://prod.example.com")},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "unrelated", Namespace: testNamespace},
+			Data:       map[string][]byte{"token": []byte("other")},
+		},
+	}
+}
+
+func TestDisasterRecoveryBundle_sealAndUnseal(t *testing.T) {
+	sealed, err := sealDisasterRecoveryBundle([]byte("key"), []byte("content"))
+	assert.NilError(t, err)
+
+	content, err := unsealDisasterRecoveryBundle([]byte("key"), sealed)
+	assert.NilError(t, err)
+	assert.Equal(t, string(content), "content")
+
+	_, err = unsealDisasterRecoveryBundle([]byte("wrong"), sealed)
+	assert.Assert(t, err != nil)
+}
+
+func TestReconcileArgoCD_reconcileDisasterRecovery_mirror(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.DisasterRecovery = &argoprojv1alpha1.ArgoCDDisasterRecoverySpec{
+			KeySecretNamespace: testDisasterRecoveryKeyNamespace,
+			Namespace:          testDisasterRecoveryNamespace,
+		}
+	})
+	r := makeTestReconciler(t, a)
+	for _, s := range makeTestDisasterRecoverySecrets() {
+		assert.NilError(t, r.client.Create(context.TODO(), s))
+	}
+
+	assert.NilError(t, r.reconcileDisasterRecovery(a))
+
+	key := &corev1.Secret{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-disaster-recovery-key", Namespace: testDisasterRecoveryKeyNamespace}, key))
+	assert.Assert(t, len(key.Data[common.ArgoCDKeyBackupKey]) > 0)
+
+	bundle := &corev1.Secret{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-disaster-recovery", Namespace: testDisasterRecoveryNamespace}, bundle))
+	content, err := unsealDisasterRecoveryBundle(key.Data[common.ArgoCDKeyBackupKey], bundle.Data[common.ArgoCDKeyDisasterRecoveryBundle])
+	assert.NilError(t, err)
+
+	secrets := []disasterRecoverySecret{}
+	assert.NilError(t, json.Unmarshal(content, &secrets))
+	assert.Equal(t, len(secrets), 2)
+	assert.Equal(t, secrets[0].Name, common.ArgoCDSecretName)
+	assert.Equal(t, secrets[1].Name, "prod-cluster")
+	assert.Equal(t, string(secrets[1].Data["server"]), "https://prod.example.com")
+
+	// The bundle is not resealed when the Secrets did not change.
+	assert.NilError(t, r.reconcileDisasterRecovery(a))
+	unchanged := &corev1.Secret{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-disaster-recovery", Namespace: testDisasterRecoveryNamespace}, unchanged))
+	assert.DeepEqual(t, unchanged.Data, bundle.Data)
+
+	// The bundle is resealed when a mirrored Secret changes.
+	cluster := &corev1.Secret{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: "prod-cluster", Namespace: testNamespace}, cluster))
+	cluster.Data["server"] = []byte("https://prod.example.org")
+	assert.NilError(t, r.client.Update(context.TODO(), cluster))
+
+	assert.NilError(t, r.reconcileDisasterRecovery(a))
+	changed := &corev1.Secret{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-disaster-recovery", Namespace: testDisasterRecoveryNamespace}, changed))
+	assert.Assert(t, changed.Annotations[common.ArgoCDDisasterRecoveryChecksumAnnotation] != bundle.Annotations[common.ArgoCDDisasterRecoveryChecksumAnnotation])
+}
+
+func TestReconcileArgoCD_reconcileDisasterRecovery_restore(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	mirror := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.DisasterRecovery = &argoprojv1alpha1.ArgoCDDisasterRecoverySpec{
+			KeySecretNamespace: testDisasterRecoveryKeyNamespace,
+			Namespace:          testDisasterRecoveryNamespace,
+		}
+	})
+	r := makeTestReconciler(t, mirror)
+	for _, s := range makeTestDisasterRecoverySecrets() {
+		assert.NilError(t, r.client.Create(context.TODO(), s))
+	}
+	assert.NilError(t, r.reconcileDisasterRecovery(mirror))
+
+	// Simulate a cold start, with only the key and the bundle left.
+	for _, s := range makeTestDisasterRecoverySecrets() {
+		assert.NilError(t, r.client.Delete(context.TODO(), s))
+	}
+
+	restore := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.DisasterRecovery = &argoprojv1alpha1.ArgoCDDisasterRecoverySpec{
+			KeySecretNamespace: testDisasterRecoveryKeyNamespace,
+			Mode:               common.ArgoCDDisasterRecoveryModeRestore,
+			Namespace:          testDisasterRecoveryNamespace,
+		}
+	})
+	assert.NilError(t, r.reconcileDisasterRecovery(restore))
+
+	cluster := &corev1.Secret{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: "prod-cluster", Namespace: testNamespace}, cluster))
+	assert.Equal(t, string(cluster.Data["server"]), "https://prod.example.com")
+	assert.Equal(t, cluster.Labels[common.ArgoCDSecretTypeLabel], common.ArgoCDSecretTypeCluster)
+
+	unrelated := &corev1.Secret{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: "unrelated", Namespace: testNamespace}, unrelated)
+	assert.Assert(t, err != nil)
+}
+
+func TestReconcileArgoCD_reconcileDisasterRecovery_restoreWithoutKey(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.DisasterRecovery = &argoprojv1alpha1.ArgoCDDisasterRecoverySpec{
+			KeySecretNamespace: testDisasterRecoveryKeyNamespace,
+			Mode:               common.ArgoCDDisasterRecoveryModeRestore,
+			Namespace:          testDisasterRecoveryNamespace,
+		}
+	})
+	r := makeTestReconciler(t, a)
+
+	assert.ErrorContains(t, r.reconcileDisasterRecovery(a), "disaster recovery key secret argocd-disaster-recovery-key not found in namespace argocd-dr-keys")
+}
+
+func TestReconcileArgoCD_reconcileDisasterRecovery_unownedBundle(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.DisasterRecovery = &argoprojv1alpha1.ArgoCDDisasterRecoverySpec{
+			KeySecretNamespace: testDisasterRecoveryKeyNamespace,
+			Namespace:          testDisasterRecoveryNamespace,
+		}
+	})
+	existing := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "argocd-disaster-recovery", Namespace: testDisasterRecoveryNamespace},
+		Data:       map[string][]byte{"token": []byte("other")},
+	}
+	r := makeTestReconciler(t, a, existing)
+
+	assert.ErrorContains(t, r.reconcileDisasterRecovery(a), "refusing to overwrite secret argocd-disaster-recovery")
+
+	unchanged := &corev1.Secret{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-disaster-recovery", Namespace: testDisasterRecoveryNamespace}, unchanged))
+	assert.DeepEqual(t, unchanged.Data, existing.Data)
+}
@@ -0,0 +1,95 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocd
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+
+	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
+	"github.com/argoproj-labs/argocd-operator/pkg/controller/argoutil"
+)
+
+// argoServerExtensionsPath is where the extension installers write the UI extensions read by the Argo CD server.
+const argoServerExtensionsPath = "/tmp/extensions/"
+
+// getServerExtensionInstallerImage will return the container image of the installer for the given extension.
+func getServerExtensionInstallerImage(cr *argoprojv1a1.ArgoCD, ext argoprojv1a1.ArgoCDServerExtensionSpec) string {
+	if len(ext.Image) > 0 {
+		return ext.Image
+	}
+	if e := argoutil.GetDefaultImage(common.ArgoCDExtensionInstallerImageEnvName); e != "" {
+		return e
+	}
+	img := argoutil.ReplaceImageRegistry(common.ArgoCDDefaultExtensionInstallerImage, cr.Spec.ImageRegistry)
+	return argoutil.CombineImageTag(img, common.ArgoCDDefaultExtensionInstallerVersion)
+}
+
+// getServerExtensionInitContainers will return an init container installing each UI extension of the Argo CD server.
+func getServerExtensionInitContainers(cr *argoprojv1a1.ArgoCD) []corev1.Container {
+	var runAsUser int64 = 1000
+	containers := make([]corev1.Container, 0)
+	for _, ext := range cr.Spec.Server.Extensions {
+		env := []corev1.EnvVar{{
+			Name:  "EXTENSION_NAME",
+			Value: ext.Name,
+		}, {
+			Name:  "EXTENSION_URL",
+			Value: ext.URL,
+		}}
+		if len(ext.ChecksumURL) > 0 {
+			env = append(env, corev1.EnvVar{
+				Name:  "EXTENSION_CHECKSUM_URL",
+				Value: ext.ChecksumURL,
+			})
+		}
+		containers = append(containers, corev1.Container{
+			Env:             proxyEnvVars(cr, env...),
+			Image:           getServerExtensionInstallerImage(cr, ext),
+			ImagePullPolicy: corev1.PullIfNotPresent,
+			Name:            fmt.Sprintf("extension-%s", ext.Name),
+			SecurityContext: &corev1.SecurityContext{
+				AllowPrivilegeEscalation: boolPtr(false),
+				RunAsUser:                &runAsUser,
+			},
+			VolumeMounts: []corev1.VolumeMount{{
+				Name:      "extensions",
+				MountPath: argoServerExtensionsPath,
+			}},
+		})
+	}
+	return containers
+}
+
+// applyServerExtensions will add the extension installer init containers and the volume holding the installed UI
+// extensions to the given Argo CD server pod spec.
+func applyServerExtensions(cr *argoprojv1a1.ArgoCD, podSpec *corev1.PodSpec) {
+	if len(cr.Spec.Server.Extensions) == 0 {
+		return
+	}
+	podSpec.InitContainers = append(podSpec.InitContainers, getServerExtensionInitContainers(cr)...)
+	podSpec.Containers[0].VolumeMounts = append(podSpec.Containers[0].VolumeMounts, corev1.VolumeMount{
+		Name:      "extensions",
+		MountPath: argoServerExtensionsPath,
+	})
+	podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+		Name: "extensions",
+		VolumeSource: corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{},
+		},
+	})
+}
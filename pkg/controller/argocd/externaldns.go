@@ -0,0 +1,61 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocd
+
+import (
+	"strconv"
+	"strings"
+
+	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
+)
+
+// getArgoServerExternalDNSHostnames will return the hostnames published by external-dns for an endpoint of the Argo CD
+// Server with the given host, followed by the additional hostnames of the given ArgoCD.
+func getArgoServerExternalDNSHostnames(cr *argoprojv1a1.ArgoCD, host string) []string {
+	return append([]string{host}, cr.Spec.Server.ExternalDNS.Hostnames...)
+}
+
+// addExternalDNSAnnotations will return a copy of the given annotations with the external-dns annotations publishing
+// the given hostnames, or the given annotations as is when external-dns is not enabled for the given ArgoCD. Empty and
+// duplicate hostnames are left out.
+func addExternalDNSAnnotations(cr *argoprojv1a1.ArgoCD, annotations map[string]string, hostnames []string) map[string]string {
+	externalDNS := cr.Spec.Server.ExternalDNS
+	if !externalDNS.Enabled {
+		return annotations
+	}
+
+	atns := make(map[string]string, len(annotations)+2)
+	for key, value := range annotations {
+		atns[key] = value
+	}
+
+	names := make([]string, 0, len(hostnames))
+	seen := make(map[string]bool)
+	for _, name := range hostnames {
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	if len(names) > 0 {
+		atns[common.ArgoCDKeyExternalDNSHostname] = strings.Join(names, ",")
+	}
+	if externalDNS.TTL > 0 {
+		atns[common.ArgoCDKeyExternalDNSTTL] = strconv.Itoa(int(externalDNS.TTL))
+	}
+	return atns
+}
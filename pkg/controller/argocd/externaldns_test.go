@@ -0,0 +1,123 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocd
+
+import (
+	"context"
+	"testing"
+
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	extv1beta1 "k8s.io/api/extensions/v1beta1"
+	"k8s.io/apimachinery/pkg/types"
+
+	argoprojv1alpha1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
+)
+
+func TestAddExternalDNSAnnotations(t *testing.T) {
+	a := makeTestArgoCD()
+	atns := map[string]string{"example.com/waf-policy": "strict"}
+
+	assert.DeepEqual(t, addExternalDNSAnnotations(a, atns, []string{"argocd.example.com"}), atns)
+
+	a.Spec.Server.ExternalDNS = argoprojv1alpha1.ArgoCDExternalDNSSpec{
+		Enabled:   true,
+		Hostnames: []string{"argocd.example.com", "cd.example.com"},
+		TTL:       60,
+	}
+	got := addExternalDNSAnnotations(a, atns, getArgoServerExternalDNSHostnames(a, "argocd.example.com"))
+	assert.DeepEqual(t, got, map[string]string{
+		"example.com/waf-policy":            "strict",
+		common.ArgoCDKeyExternalDNSHostname: "argocd.example.com,cd.example.com",
+		common.ArgoCDKeyExternalDNSTTL:      "60",
+	})
+	assert.Equal(t, len(atns), 1)
+
+	a.Spec.Server.ExternalDNS.TTL = 0
+	got = addExternalDNSAnnotations(a, nil, getArgoServerExternalDNSHostnames(a, ""))
+	assert.DeepEqual(t, got, map[string]string{
+		common.ArgoCDKeyExternalDNSHostname: "argocd.example.com,cd.example.com",
+	})
+}
+
+func TestReconcileArgoCD_reconcileArgoServerIngress_externalDNS(t *testing.T) {
+	restoreEnv(t)
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.Server.Host = "argocd.example.com"
+		a.Spec.Server.Ingress.Enabled = true
+		a.Spec.Server.GRPC.Host = "grpc.argocd.example.com"
+		a.Spec.Server.GRPC.Ingress.Enabled = true
+		a.Spec.Server.ExternalDNS.Enabled = true
+	})
+	r := makeTestReconciler(t, a)
+	key := types.NamespacedName{Name: "argocd-server", Namespace: testNamespace}
+	grpcKey := types.NamespacedName{Name: "argocd-grpc", Namespace: testNamespace}
+
+	assert.NilError(t, r.reconcileArgoServerIngress(a))
+	assert.NilError(t, r.reconcileArgoServerGRPCIngress(a))
+
+	ingress := &extv1beta1.Ingress{}
+	assert.NilError(t, r.client.Get(context.TODO(), key, ingress))
+	assert.Equal(t, ingress.Annotations[common.ArgoCDKeyExternalDNSHostname], "argocd.example.com")
+	assert.Equal(t, ingress.Annotations[common.ArgoCDKeyIngressClass], "nginx")
+	grpc := &extv1beta1.Ingress{}
+	assert.NilError(t, r.client.Get(context.TODO(), grpcKey, grpc))
+	assert.Equal(t, grpc.Annotations[common.ArgoCDKeyExternalDNSHostname], "grpc.argocd.example.com")
+
+	// Disabling external-dns removes its annotations from the existing Ingress.
+	a.Spec.Server.ExternalDNS.Enabled = false
+	assert.NilError(t, r.reconcileArgoServerIngress(a))
+
+	ingress = &extv1beta1.Ingress{}
+	assert.NilError(t, r.client.Get(context.TODO(), key, ingress))
+	_, ok := ingress.Annotations[common.ArgoCDKeyExternalDNSHostname]
+	assert.Assert(t, !ok)
+	assert.Equal(t, ingress.Annotations[common.ArgoCDKeyIngressClass], "nginx")
+}
+
+func TestReconcileArgoCD_reconcileServerService_externalDNS(t *testing.T) {
+	restoreEnv(t)
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.Server.Host = "argocd.example.com"
+		a.Spec.Server.ExternalDNS.Enabled = true
+	})
+	r := makeTestReconciler(t, a)
+	key := types.NamespacedName{Name: "argocd-server", Namespace: testNamespace}
+
+	// A ClusterIP Service has no address for external-dns to publish.
+	assert.NilError(t, r.reconcileServerService(a))
+
+	svc := &corev1.Service{}
+	assert.NilError(t, r.client.Get(context.TODO(), key, svc))
+	_, ok := svc.Annotations[common.ArgoCDKeyExternalDNSHostname]
+	assert.Assert(t, !ok)
+
+	assert.NilError(t, r.client.Delete(context.TODO(), svc))
+	a.Spec.Server.Service.Type = corev1.ServiceTypeLoadBalancer
+	assert.NilError(t, r.reconcileServerService(a))
+
+	svc = &corev1.Service{}
+	assert.NilError(t, r.client.Get(context.TODO(), key, svc))
+	assert.Equal(t, svc.Annotations[common.ArgoCDKeyExternalDNSHostname], "argocd.example.com")
+
+	a.Spec.Server.ExternalDNS.Enabled = false
+	assert.NilError(t, r.reconcileServerService(a))
+
+	svc = &corev1.Service{}
+	assert.NilError(t, r.client.Get(context.TODO(), key, svc))
+	_, ok = svc.Annotations[common.ArgoCDKeyExternalDNSHostname]
+	assert.Assert(t, !ok)
+}
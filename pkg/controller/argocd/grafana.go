@@ -29,6 +29,9 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 )
 
+// grafanaExternalLinksMinimumVersion is the first version of Argo CD supporting the deep links to the external Grafana.
+const grafanaExternalLinksMinimumVersion = "v2.6.0"
+
 // GrafanaConfig represents the Grafana configuration options.
 type GrafanaConfig struct {
 	// Security options
@@ -58,6 +61,50 @@ func generateGrafanaSecretKey() ([]byte, error) {
 	return []byte(key), err
 }
 
+// isGrafanaExternal will return true if the given ArgoCD uses an existing Grafana in place of the Grafana managed by
+// the operator.
+func isGrafanaExternal(cr *argoprojv1a1.ArgoCD) bool {
+	return cr.Spec.Grafana.Enabled && cr.Spec.Grafana.External != nil && cr.Spec.Grafana.External.URL != ""
+}
+
+// isManagedGrafanaEnabled will return true if the operator should deploy Grafana for the given ArgoCD.
+func isManagedGrafanaEnabled(cr *argoprojv1a1.ArgoCD) bool {
+	return cr.Spec.Grafana.Enabled && !isGrafanaExternal(cr)
+}
+
+// getGrafanaExternalDatasourceName will return the name of the Prometheus datasource provisioned in an external
+// Grafana for the given ArgoCD. The name is unique across namespaces, as the external Grafana may be shared.
+func getGrafanaExternalDatasourceName(cr *argoprojv1a1.ArgoCD) string {
+	return GenerateUniqueResourceName("prometheus", cr)
+}
+
+// getGrafanaExternalDatasource will return the datasource provisioning configuration for an external Grafana, which
+// points at the Prometheus in the namespace of the given ArgoCD.
+func getGrafanaExternalDatasource(cr *argoprojv1a1.ArgoCD) string {
+	return fmt.Sprintf(`apiVersion: 1
+datasources:
+- name: %s
+  type: prometheus
+  access: proxy
+  orgId: 1
+  url: http://prometheus-operated.%s.svc:9090
+  version: 1
+  editable: false
+`, getGrafanaExternalDatasourceName(cr), cr.Namespace)
+}
+
+// getGrafanaExternalApplicationLinks will return the deep links from the Applications to the Argo CD dashboard of
+// the external Grafana for the given ArgoCD, or an empty string when no external Grafana is used or the deep links
+// are not supported by the Argo CD version.
+func getGrafanaExternalApplicationLinks(cr *argoprojv1a1.ArgoCD) string {
+	if !isGrafanaExternal(cr) || !isArgoCDVersionAtLeast(cr, grafanaExternalLinksMinimumVersion) {
+		return ""
+	}
+	return fmt.Sprintf(`- title: Grafana
+  url: %s/d/%s/argocd?var-namespace=%s
+`, strings.TrimSuffix(cr.Spec.Grafana.External.URL, "/"), common.ArgoCDGrafanaDashboardUID, cr.Namespace)
+}
+
 // getGrafanaHost will return the hostname value for Grafana.
 func getGrafanaHost(cr *argoprojv1a1.ArgoCD) string {
 	host := nameWithSuffix("grafana", cr)
@@ -0,0 +1,104 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
+)
+
+// hibernateReplicas will return the desired replica count for a workload given its current replica count and
+// annotations, along with whether the workload should be updated.
+func hibernateReplicas(cr *argoprojv1a1.ArgoCD, meta *metav1.ObjectMeta, replicas *int32) (*int32, bool, error) {
+	saved, hibernated := meta.Annotations[common.AnnotationHibernatedReplicas]
+
+	if cr.Spec.Hibernate {
+		if replicas != nil && *replicas == 0 {
+			return replicas, false, nil // Already scaled down, move along...
+		}
+
+		current := int32(1)
+		if replicas != nil {
+			current = *replicas
+		}
+		if meta.Annotations == nil {
+			meta.Annotations = make(map[string]string)
+		}
+		meta.Annotations[common.AnnotationHibernatedReplicas] = fmt.Sprint(current)
+
+		var zero int32
+		return &zero, true, nil
+	}
+
+	if !hibernated {
+		return replicas, false, nil // Not hibernated, nothing to restore...
+	}
+
+	delete(meta.Annotations, common.AnnotationHibernatedReplicas)
+	count, err := strconv.ParseInt(saved, 10, 32)
+	if err != nil {
+		return nil, false, fmt.Errorf("invalid %s annotation on %s: %w", common.AnnotationHibernatedReplicas, meta.Name, err)
+	}
+	restored := int32(count)
+	return &restored, true, nil
+}
+
+// reconcileHibernation will ensure that the workloads for the given ArgoCD are scaled to zero while hibernated
+// and restored to their previous replica counts once hibernation ends.
+func (r *ReconcileArgoCD) reconcileHibernation(cr *argoprojv1a1.ArgoCD) error {
+	deploys := &appsv1.DeploymentList{}
+	if err := r.client.List(context.TODO(), deploys, managedResourceListOptions(cr)...); err != nil {
+		return err
+	}
+	for i := range deploys.Items {
+		deploy := &deploys.Items[i]
+		replicas, changed, err := hibernateReplicas(cr, &deploy.ObjectMeta, deploy.Spec.Replicas)
+		if err != nil {
+			return err
+		}
+		if changed {
+			deploy.Spec.Replicas = replicas
+			if err := r.client.Update(context.TODO(), deploy); err != nil {
+				return err
+			}
+		}
+	}
+
+	sets := &appsv1.StatefulSetList{}
+	if err := r.client.List(context.TODO(), sets, managedResourceListOptions(cr)...); err != nil {
+		return err
+	}
+	for i := range sets.Items {
+		ss := &sets.Items[i]
+		replicas, changed, err := hibernateReplicas(cr, &ss.ObjectMeta, ss.Spec.Replicas)
+		if err != nil {
+			return err
+		}
+		if changed {
+			ss.Spec.Replicas = replicas
+			if err := r.client.Update(context.TODO(), ss); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
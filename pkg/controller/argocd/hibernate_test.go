@@ -0,0 +1,68 @@
+package argocd
+
+import (
+	"context"
+	"testing"
+
+	"gotest.tools/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/types"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+
+	argoprojv1alpha1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
+)
+
+func TestReconcileArgoCD_reconcileHibernation(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD()
+	r := makeTestReconciler(t, a)
+
+	assert.NilError(t, r.reconcileRepoDeployment(a))
+	assert.NilError(t, r.reconcileApplicationControllerStatefulSet(a))
+
+	a.Spec.Hibernate = true
+	assert.NilError(t, r.reconcileHibernation(a))
+
+	deploy := &appsv1.Deployment{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-repo-server", Namespace: testNamespace}, deploy))
+	assert.Equal(t, *deploy.Spec.Replicas, int32(0))
+	assert.Equal(t, deploy.Annotations[common.AnnotationHibernatedReplicas], "1")
+
+	ss := &appsv1.StatefulSet{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-application-controller", Namespace: testNamespace}, ss))
+	assert.Equal(t, *ss.Spec.Replicas, int32(0))
+	assert.Equal(t, ss.Annotations[common.AnnotationHibernatedReplicas], "1")
+
+	a.Spec.Hibernate = false
+	assert.NilError(t, r.reconcileHibernation(a))
+
+	deploy = &appsv1.Deployment{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-repo-server", Namespace: testNamespace}, deploy))
+	assert.Equal(t, *deploy.Spec.Replicas, int32(1))
+	_, ok := deploy.Annotations[common.AnnotationHibernatedReplicas]
+	assert.Assert(t, !ok)
+
+	ss = &appsv1.StatefulSet{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-application-controller", Namespace: testNamespace}, ss))
+	assert.Equal(t, *ss.Spec.Replicas, int32(1))
+}
+
+func TestReconcileArgoCD_reconcileHibernation_preservesScaledReplicas(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.Hibernate = true
+	})
+	d := newDeploymentWithSuffix("server", "server", a)
+	var replicas int32 = 3
+	d.Spec.Replicas = &replicas
+	r := makeTestReconciler(t, a, d)
+
+	assert.NilError(t, r.reconcileHibernation(a))
+	a.Spec.Hibernate = false
+	assert.NilError(t, r.reconcileHibernation(a))
+
+	deploy := &appsv1.Deployment{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-server", Namespace: testNamespace}, deploy))
+	assert.Equal(t, *deploy.Spec.Replicas, int32(3))
+}
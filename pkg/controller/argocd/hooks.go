@@ -22,10 +22,13 @@ func Register(h ...Hook) {
 	hooks = append(hooks, h...)
 }
 
+// applyReconcilerHook will apply the registered hooks to the given object. The hooks are called outside of the lock,
+// so that the reconciliations of other ArgoCD clusters do not wait on a slow hook, e.g. the reconciler webhook.
 func applyReconcilerHook(cr *argoprojv1alpha1.ArgoCD, i interface{}, hint string) error {
-	mutex.Lock()
-	defer mutex.Unlock()
-	for _, v := range hooks {
+	mutex.RLock()
+	registered := append([]Hook(nil), hooks...)
+	mutex.RUnlock()
+	for _, v := range registered {
 		if err := v(cr, i, hint); err != nil {
 			return err
 		}
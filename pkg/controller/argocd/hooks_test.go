@@ -96,3 +96,16 @@ func resetHooks() func() {
 		hooks = origDefaultHooksFunc
 	}
 }
+
+func TestReconcileArgoCD_hookOutsideOfLock(t *testing.T) {
+	defer resetHooks()()
+	a := makeTestArgoCD()
+
+	// A hook registering another hook would deadlock if the hooks were called while holding the lock.
+	Register(func(cr *argoprojv1alpha1.ArgoCD, v interface{}, s string) error {
+		Register(testDeploymentHook)
+		return nil
+	})
+
+	assert.NilError(t, applyReconcilerHook(a, makeTestDeployment(), ""))
+}
@@ -56,6 +56,9 @@ func (r *ReconcileArgoCD) reconcileServerHPA(cr *argoprojv1a1.ArgoCD) error {
 		if !cr.Spec.Server.Autoscale.Enabled {
 			return r.client.Delete(context.TODO(), hpa) // HorizontalPodAutoscaler found but globally disabled, delete it.
 		}
+		if cr.Spec.Hibernate {
+			return r.client.Delete(context.TODO(), hpa) // HorizontalPodAutoscaler would scale up the hibernated server, delete it.
+		}
 		return nil // HorizontalPodAutoscaler found and configured, nothing do to, move along...
 	}
 
@@ -63,6 +66,10 @@ func (r *ReconcileArgoCD) reconcileServerHPA(cr *argoprojv1a1.ArgoCD) error {
 		return nil // AutoScale not enabled, move along...
 	}
 
+	if cr.Spec.Hibernate {
+		return nil // Hibernated, the HorizontalPodAutoscaler will be created once hibernation ends.
+	}
+
 	if cr.Spec.Server.Autoscale.HPA != nil {
 		hpa.Spec = *cr.Spec.Server.Autoscale.HPA
 	} else {
@@ -0,0 +1,350 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocd
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
+	"github.com/argoproj-labs/argocd-operator/pkg/controller/argoutil"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// reconcileImageUpdater will ensure that the resources for the Argo CD Image Updater are present when enabled
+// and removed when disabled for the given ArgoCD.
+func (r *ReconcileArgoCD) reconcileImageUpdater(cr *argoprojv1a1.ArgoCD) error {
+	if !cr.Spec.ImageUpdater.Enabled {
+		return r.deleteImageUpdaterResources(cr)
+	}
+
+	log.Info("reconciling image updater serviceaccounts")
+	sa, err := r.reconcileImageUpdaterServiceAccount(cr)
+	if err != nil {
+		return err
+	}
+
+	log.Info("reconciling image updater roles")
+	role, err := r.reconcileImageUpdaterRole(cr)
+	if err != nil {
+		return err
+	}
+
+	log.Info("reconciling image updater role bindings")
+	if err := r.reconcileImageUpdaterRoleBinding(cr, role, sa); err != nil {
+		return err
+	}
+
+	log.Info("reconciling image updater configmaps")
+	if err := r.reconcileImageUpdaterConfigMap(cr); err != nil {
+		return err
+	}
+
+	log.Info("reconciling image updater deployments")
+	return r.reconcileImageUpdaterDeployment(cr, sa)
+}
+
+// deleteImageUpdaterResources will remove any resources created for the Argo CD Image Updater.
+func (r *ReconcileArgoCD) deleteImageUpdaterResources(cr *argoprojv1a1.ArgoCD) error {
+	objs := []runtime.Object{
+		newDeploymentWithSuffix("image-updater", "image-updater", cr),
+		newConfigMapWithName(common.ArgoCDImageUpdaterConfigMapName, cr),
+		newRoleBindingWithname("image-updater", cr),
+		newRole("image-updater", nil, cr),
+		newServiceAccountWithName("image-updater", cr),
+	}
+	return r.pruneObjects(cr, objs)
+}
+
+// reconcileImageUpdaterConfigMap will ensure that the Argo CD Image Updater ConfigMap is present and holds the
+// configured registries.
+func (r *ReconcileArgoCD) reconcileImageUpdaterConfigMap(cr *argoprojv1a1.ArgoCD) error {
+	cm := newConfigMapWithName(common.ArgoCDImageUpdaterConfigMapName, cr)
+	data := map[string]string{
+		common.ArgoCDKeyImageUpdaterRegistries: cr.Spec.ImageUpdater.Registries,
+	}
+
+	if argoutil.IsObjectFound(r.client, cr.Namespace, cm.Name, cm) {
+		if !reflect.DeepEqual(cm.Data, data) {
+			cm.Data = data
+			return r.client.Update(context.TODO(), cm)
+		}
+		return nil // ConfigMap found with nothing changed, move along...
+	}
+
+	cm.Data = data
+	if err := controllerutil.SetControllerReference(cr, cm, r.scheme); err != nil {
+		return err
+	}
+	return r.client.Create(context.TODO(), cm)
+}
+
+// reconcileImageUpdaterDeployment will ensure the Deployment resource is present for the Argo CD Image Updater.
+func (r *ReconcileArgoCD) reconcileImageUpdaterDeployment(cr *argoprojv1a1.ArgoCD, sa *corev1.ServiceAccount) error {
+	deploy := newDeploymentWithSuffix("image-updater", "image-updater", cr)
+
+	podSpec := &deploy.Spec.Template.Spec
+	podSpec.ServiceAccountName = sa.ObjectMeta.Name
+	podSpec.Volumes = []corev1.Volume{
+		{
+			Name: "image-updater-conf",
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: common.ArgoCDImageUpdaterConfigMapName,
+					},
+				},
+			},
+		},
+		{
+			Name: "ssh-known-hosts",
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: common.ArgoCDKnownHostsConfigMapName,
+					},
+				},
+			},
+		},
+	}
+
+	podSpec.Containers = []corev1.Container{{
+		Command:         getImageUpdaterCommand(cr),
+		Env:             proxyEnvVars(cr, getImageUpdaterEnv(cr)...),
+		Image:           getImageUpdaterContainerImage(cr),
+		ImagePullPolicy: corev1.PullAlways,
+		Name:            "argocd-image-updater",
+		Resources:       getImageUpdaterResources(cr),
+		VolumeMounts: []corev1.VolumeMount{
+			{
+				Name:      "image-updater-conf",
+				MountPath: "/app/config",
+			},
+			{
+				Name:      "ssh-known-hosts",
+				MountPath: "/app/config/ssh",
+			},
+		},
+	}}
+
+	if err := applyReconcilerHook(cr, deploy, ""); err != nil {
+		return err
+	}
+	setContainerCommand(&deploy.Spec.Template, cr.Spec.ImageUpdater.CommandOverride)
+
+	if err := r.reconcilePodSecurityCompliance(cr, "Deployment/"+deploy.Name, &deploy.Spec.Template); err != nil {
+		return err
+	}
+
+	existing := newDeploymentWithSuffix("image-updater", "image-updater", cr)
+	if argoutil.IsObjectFound(r.client, cr.Namespace, existing.Name, existing) {
+		changed := false
+		if !reflect.DeepEqual(existing.Spec.Template.Spec.Containers, podSpec.Containers) {
+			existing.Spec.Template.Spec.Containers = podSpec.Containers
+			changed = true
+		}
+		if !reflect.DeepEqual(existing.Spec.Template.Spec.Volumes, podSpec.Volumes) {
+			existing.Spec.Template.Spec.Volumes = podSpec.Volumes
+			changed = true
+		}
+		if changed {
+			return r.client.Update(context.TODO(), existing)
+		}
+		return nil // Deployment found with nothing to do, move along...
+	}
+
+	if err := controllerutil.SetControllerReference(cr, deploy, r.scheme); err != nil {
+		return err
+	}
+	return r.client.Create(context.TODO(), deploy)
+}
+
+func (r *ReconcileArgoCD) reconcileImageUpdaterServiceAccount(cr *argoprojv1a1.ArgoCD) (*corev1.ServiceAccount, error) {
+	if name := cr.Spec.ImageUpdater.ServiceAccountName; name != "" {
+		return newExternalServiceAccount(name, cr), nil // ServiceAccount managed outside of the operator
+	}
+	sa := newServiceAccountWithName("image-updater", cr)
+	if argoutil.IsObjectFound(r.client, cr.Namespace, sa.Name, sa) {
+		return sa, nil // ServiceAccount found, move along...
+	}
+
+	if err := controllerutil.SetControllerReference(cr, sa, r.scheme); err != nil {
+		return nil, err
+	}
+	return sa, r.client.Create(context.TODO(), sa)
+}
+
+func (r *ReconcileArgoCD) reconcileImageUpdaterRole(cr *argoprojv1a1.ArgoCD) (*v1.Role, error) {
+	policyRules := []v1.PolicyRule{
+		// Applications
+		{
+			APIGroups: []string{"argoproj.io"},
+			Resources: []string{
+				"applications",
+			},
+			Verbs: []string{
+				"get",
+				"list",
+				"patch",
+				"update",
+				"watch",
+			},
+		},
+
+		// Read Secrets/ConfigMaps
+		{
+			APIGroups: []string{""},
+			Resources: []string{
+				"secrets",
+				"configmaps",
+			},
+			Verbs: []string{
+				"get",
+				"list",
+				"watch",
+			},
+		},
+
+		// Events
+		{
+			APIGroups: []string{""},
+			Resources: []string{
+				"events",
+			},
+			Verbs: []string{
+				"create",
+			},
+		},
+	}
+
+	role := newRole("image-updater", policyRules, cr)
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: role.Name, Namespace: cr.Namespace}, role)
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			return nil, fmt.Errorf("failed to reconcile the role for the service account associated with %s : %s", role.Name, err)
+		}
+		if err := controllerutil.SetControllerReference(cr, role, r.scheme); err != nil {
+			return nil, err
+		}
+		return role, r.client.Create(context.TODO(), role)
+	}
+
+	if !reflect.DeepEqual(role.Rules, policyRules) {
+		role.Rules = policyRules
+		return role, r.client.Update(context.TODO(), role)
+	}
+	return role, nil
+}
+
+func (r *ReconcileArgoCD) reconcileImageUpdaterRoleBinding(cr *argoprojv1a1.ArgoCD, role *v1.Role, sa *corev1.ServiceAccount) error {
+	roleBinding := newRoleBindingWithname("image-updater", cr)
+	roleBinding.RoleRef = v1.RoleRef{
+		APIGroup: v1.GroupName,
+		Kind:     "Role",
+		Name:     role.Name,
+	}
+	roleBinding.Subjects = []v1.Subject{
+		{
+			Kind:      v1.ServiceAccountKind,
+			Name:      sa.Name,
+			Namespace: sa.Namespace,
+		},
+	}
+
+	existing := newRoleBindingWithname("image-updater", cr)
+	if argoutil.IsObjectFound(r.client, cr.Namespace, existing.Name, existing) {
+		// the RoleRef of a RoleBinding can not be updated, delete the existing one and create a new one
+		if !reflect.DeepEqual(existing.RoleRef, roleBinding.RoleRef) {
+			if err := r.client.Delete(context.TODO(), existing); err != nil {
+				return err
+			}
+		} else {
+			if !reflect.DeepEqual(existing.Subjects, roleBinding.Subjects) {
+				existing.Subjects = roleBinding.Subjects
+				return r.client.Update(context.TODO(), existing)
+			}
+			return nil // RoleBinding found with nothing changed, move along...
+		}
+	}
+
+	if err := controllerutil.SetControllerReference(cr, roleBinding, r.scheme); err != nil {
+		return err
+	}
+	return r.client.Create(context.TODO(), roleBinding)
+}
+
+// getImageUpdaterCommand will return the command for the Argo CD Image Updater container.
+func getImageUpdaterCommand(cr *argoprojv1a1.ArgoCD) []string {
+	cmd := []string{"argocd-image-updater", "run"}
+	if cr.Spec.ImageUpdater.Interval != nil {
+		cmd = append(cmd, "--interval", cr.Spec.ImageUpdater.Interval.Duration.String())
+	}
+	return cmd
+}
+
+// getImageUpdaterContainerImage will return the container image for the Argo CD Image Updater.
+func getImageUpdaterContainerImage(cr *argoprojv1a1.ArgoCD) string {
+	defaultImg, defaultTag := false, false
+	img := cr.Spec.ImageUpdater.Image
+	if img == "" {
+		img = argoutil.ReplaceImageRegistry(common.ArgoCDDefaultImageUpdaterImage, cr.Spec.ImageRegistry)
+		defaultImg = true
+	}
+
+	tag := cr.Spec.ImageUpdater.Version
+	if tag == "" {
+		tag = common.ArgoCDDefaultImageUpdaterVersion
+		defaultTag = true
+	}
+	if e := argoutil.GetDefaultImage(common.ArgoCDRelatedImageImageUpdaterEnvName, common.ArgoCDImageUpdaterImageEnvName); e != "" && (defaultTag && defaultImg) {
+		return e
+	}
+	return argoutil.CombineImageTag(img, tag)
+}
+
+// getImageUpdaterEnv will return the environment for the Argo CD Image Updater container. The Image Updater reads
+// and updates Applications through the Kubernetes API, so it needs neither an Argo CD API token nor Redis.
+func getImageUpdaterEnv(cr *argoprojv1a1.ArgoCD) []corev1.EnvVar {
+	return []corev1.EnvVar{
+		{
+			Name:  "APPLICATIONS_API",
+			Value: "kubernetes",
+		},
+	}
+}
+
+// getImageUpdaterResources will return the ResourceRequirements for the Argo CD Image Updater container.
+func getImageUpdaterResources(cr *argoprojv1a1.ArgoCD) corev1.ResourceRequirements {
+	resources := corev1.ResourceRequirements{}
+
+	// Allow override of the default resource requirements from the sizing profile
+	if profile := getSizingProfile(cr); profile != nil {
+		resources = *profile.imageUpdaterResources.DeepCopy()
+	}
+
+	// Allow override of resource requirements from CR
+	if cr.Spec.ImageUpdater.Resources != nil {
+		resources = *cr.Spec.ImageUpdater.Resources
+	}
+
+	return resources
+}
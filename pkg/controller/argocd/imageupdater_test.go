@@ -0,0 +1,95 @@
+package argocd
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+
+	argoprojv1alpha1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
+	"github.com/argoproj-labs/argocd-operator/pkg/controller/argoutil"
+)
+
+func withImageUpdater(a *argoprojv1alpha1.ArgoCD) {
+	a.Spec.ImageUpdater = argoprojv1alpha1.ArgoCDImageUpdaterSpec{
+		Enabled:    true,
+		Interval:   &metav1.Duration{Duration: 5 * time.Minute},
+		Registries: "registries:\n- name: Docker Hub\n  prefix: docker.io\n",
+	}
+}
+
+func TestReconcileImageUpdater_enabled(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD(withImageUpdater)
+	r := makeTestReconciler(t, a)
+
+	assert.NilError(t, r.reconcileImageUpdater(a))
+
+	deploy := &appsv1.Deployment{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-image-updater", Namespace: a.Namespace}, deploy))
+	container := deploy.Spec.Template.Spec.Containers[0]
+	assert.Equal(t, container.Image, argoutil.CombineImageTag(common.ArgoCDDefaultImageUpdaterImage, common.ArgoCDDefaultImageUpdaterVersion))
+	assert.DeepEqual(t, container.Command, []string{"argocd-image-updater", "run", "--interval", "5m0s"})
+	assert.Equal(t, deploy.Spec.Template.Spec.ServiceAccountName, "argocd-image-updater")
+
+	assert.DeepEqual(t, container.Env, []corev1.EnvVar{{Name: "APPLICATIONS_API", Value: "kubernetes"}})
+
+	cm := &corev1.ConfigMap{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: common.ArgoCDImageUpdaterConfigMapName, Namespace: a.Namespace}, cm))
+	assert.Equal(t, cm.Data[common.ArgoCDKeyImageUpdaterRegistries], a.Spec.ImageUpdater.Registries)
+
+	rb := &rbacv1.RoleBinding{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-image-updater", Namespace: a.Namespace}, rb))
+	assert.Equal(t, rb.RoleRef.Name, "argocd-image-updater")
+}
+
+func TestReconcileImageUpdater_disabled(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD(withImageUpdater)
+	r := makeTestReconciler(t, a)
+
+	assert.NilError(t, r.reconcileImageUpdater(a))
+
+	a.Spec.ImageUpdater.Enabled = false
+	assert.NilError(t, r.reconcileImageUpdater(a))
+
+	deploy := &appsv1.Deployment{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-image-updater", Namespace: a.Namespace}, deploy)
+	assert.Assert(t, errors.IsNotFound(err))
+
+	sa := &corev1.ServiceAccount{}
+	err = r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-image-updater", Namespace: a.Namespace}, sa)
+	assert.Assert(t, errors.IsNotFound(err))
+}
+
+func TestReconcileImageUpdater_roleBindingDrift(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD(withImageUpdater)
+	r := makeTestReconciler(t, a)
+	key := types.NamespacedName{Name: "argocd-image-updater", Namespace: a.Namespace}
+
+	assert.NilError(t, r.reconcileImageUpdater(a))
+
+	rb := &rbacv1.RoleBinding{}
+	assert.NilError(t, r.client.Get(context.TODO(), key, rb))
+	rb.Subjects[0].Name = "other"
+	assert.NilError(t, r.client.Update(context.TODO(), rb))
+
+	// Using an existing ServiceAccount updates the subjects of the RoleBinding.
+	a.Spec.ImageUpdater.ServiceAccountName = "image-updater-sa"
+	assert.NilError(t, r.reconcileImageUpdater(a))
+
+	rb = &rbacv1.RoleBinding{}
+	assert.NilError(t, r.client.Get(context.TODO(), key, rb))
+	assert.Equal(t, rb.Subjects[0].Name, "image-updater-sa")
+	assert.Equal(t, rb.RoleRef.Name, "argocd-image-updater")
+}
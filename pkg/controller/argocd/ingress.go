@@ -17,6 +17,8 @@ package argocd
 import (
 	"context"
 	"fmt"
+	"reflect"
+	"strconv"
 
 	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
 	"github.com/argoproj-labs/argocd-operator/pkg/common"
@@ -24,9 +26,13 @@ import (
 	extv1beta1 "k8s.io/api/extensions/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
+// serverIngressComponent is the component label value of the additional Ingresses of the Argo CD Server.
+const serverIngressComponent = "server-ingress"
+
 // getDefaultIngressAnnotations will return the default Ingress Annotations for the given ArgoCD.
 func getDefaultIngressAnnotations(cr *argoprojv1a1.ArgoCD) map[string]string {
 	annotations := make(map[string]string)
@@ -34,6 +40,87 @@ func getDefaultIngressAnnotations(cr *argoprojv1a1.ArgoCD) map[string]string {
 	return annotations
 }
 
+// legacyIngressAnnotationKeys are the keys of the default annotations set on the Ingresses created before the managed
+// annotations were recorded.
+var legacyIngressAnnotationKeys = []string{
+	common.ArgoCDKeyIngressBackendProtocol,
+	common.ArgoCDKeyIngressClass,
+	common.ArgoCDKeyIngressSSLRedirect,
+}
+
+// getArgoServerIngressBackend will return the name of the Argo CD Server Service port and the backend protocol used
+// by the Ingresses of the Argo CD Server. Both Service ports target the same server port, so it is the protocol that
+// matters: plain HTTP is only forwarded to an insecure server, as the server otherwise redirects it back to HTTPS.
+func getArgoServerIngressBackend(cr *argoprojv1a1.ArgoCD) (string, string) {
+	if getArgoServerInsecure(cr) {
+		return "http", "HTTP"
+	}
+	return "https", "HTTPS"
+}
+
+// getArgoServerGRPCIngressBackend will return the name of the Argo CD Server Service port and the backend protocol
+// used by the Argo CD Server GRPC Ingress, using TLS unless the server is insecure.
+func getArgoServerGRPCIngressBackend(cr *argoprojv1a1.ArgoCD) (string, string) {
+	if getArgoServerInsecure(cr) {
+		return "http", "GRPC"
+	}
+	return "https", "GRPCS"
+}
+
+// withIngressBackendProtocol will return a copy of the given Ingress annotations with the given backend protocol,
+// unless the annotations already set one.
+func withIngressBackendProtocol(annotations map[string]string, protocol string) map[string]string {
+	atns := make(map[string]string, len(annotations)+1)
+	for key, value := range annotations {
+		atns[key] = value
+	}
+	if _, ok := atns[common.ArgoCDKeyIngressBackendProtocol]; !ok {
+		atns[common.ArgoCDKeyIngressBackendProtocol] = protocol
+	}
+	return atns
+}
+
+// getArgoServerIngressAnnotations will return the annotations of the Argo CD Server Ingress for the given ArgoCD,
+// either the annotations of the spec or the defaults, with the backend protocol of the server unless given and the
+// external-dns annotations when enabled.
+func getArgoServerIngressAnnotations(cr *argoprojv1a1.ArgoCD) map[string]string {
+	atns := cr.Spec.Server.Ingress.Annotations
+	if len(atns) == 0 {
+		atns = getDefaultIngressAnnotations(cr)
+		atns[common.ArgoCDKeyIngressSSLRedirect] = strconv.FormatBool(!cr.Spec.Server.DisableHTTPSRedirect)
+	}
+	_, protocol := getArgoServerIngressBackend(cr)
+	atns = withIngressBackendProtocol(atns, protocol)
+	return addExternalDNSAnnotations(cr, atns, getArgoServerExternalDNSHostnames(cr, cr.Spec.Server.Host))
+}
+
+// getArgoServerGRPCIngressAnnotations will return the annotations of the Argo CD Server GRPC Ingress for the given
+// ArgoCD, either the annotations of the spec or the defaults, with the GRPC backend protocol of the server unless
+// given and the external-dns annotations when enabled.
+func getArgoServerGRPCIngressAnnotations(cr *argoprojv1a1.ArgoCD) map[string]string {
+	atns := cr.Spec.Server.GRPC.Ingress.Annotations
+	if len(atns) == 0 {
+		atns = getDefaultIngressAnnotations(cr)
+	}
+	_, protocol := getArgoServerGRPCIngressBackend(cr)
+	atns = withIngressBackendProtocol(atns, protocol)
+	return addExternalDNSAnnotations(cr, atns, []string{cr.Spec.Server.GRPC.Host})
+}
+
+// getArgoServerIngressesAnnotations will return the annotations of the given additional Ingress of the Argo CD Server,
+// with the backend protocol of the server unless given and the external-dns annotations when enabled. The ingress class
+// of the additional Ingresses is set by their spec, so the default ingress class annotation is left out.
+func getArgoServerIngressesAnnotations(cr *argoprojv1a1.ArgoCD, spec argoprojv1a1.ArgoCDServerIngressSpec) map[string]string {
+	_, protocol := getArgoServerIngressBackend(cr)
+	atns := withIngressBackendProtocol(spec.Annotations, protocol)
+
+	dnsHost := spec.Host
+	if dnsHost == "" {
+		dnsHost = cr.Spec.Server.Host
+	}
+	return addExternalDNSAnnotations(cr, atns, getArgoServerExternalDNSHostnames(cr, dnsHost))
+}
+
 // getArgoServerPath will return the Ingress Path for the Argo CD component.
 func getPathOrDefault(path string) string {
 	result := common.ArgoCDDefaultIngressPath
@@ -43,6 +130,56 @@ func getPathOrDefault(path string) string {
 	return result
 }
 
+// getArgoServerIngressRules will return the rules of an Ingress of the Argo CD Server for the given host and path,
+// targeting the given port of the Argo CD Server Service.
+func getArgoServerIngressRules(cr *argoprojv1a1.ArgoCD, host string, path string, port string) []extv1beta1.IngressRule {
+	return []extv1beta1.IngressRule{
+		{
+			Host: host,
+			IngressRuleValue: extv1beta1.IngressRuleValue{
+				HTTP: &extv1beta1.HTTPIngressRuleValue{
+					Paths: []extv1beta1.HTTPIngressPath{
+						{
+							Path: getPathOrDefault(path),
+							Backend: extv1beta1.IngressBackend{
+								ServiceName: nameWithSuffix("server", cr),
+								ServicePort: intstr.FromString(port),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// getIngressTLS will return the given TLS options of an Ingress, or the default TLS options for the given host.
+func getIngressTLS(host string, tls []extv1beta1.IngressTLS) []extv1beta1.IngressTLS {
+	if len(tls) > 0 {
+		return tls
+	}
+	return []extv1beta1.IngressTLS{
+		{
+			Hosts:      []string{host},
+			SecretName: common.ArgoCDSecretName,
+		},
+	}
+}
+
+// updateIngressSpec will set the given rules and TLS options on the given Ingress and return true if it was changed.
+func updateIngressSpec(ingress *extv1beta1.Ingress, rules []extv1beta1.IngressRule, tls []extv1beta1.IngressTLS) bool {
+	changed := false
+	if !reflect.DeepEqual(ingress.Spec.Rules, rules) {
+		ingress.Spec.Rules = rules
+		changed = true
+	}
+	if !reflect.DeepEqual(ingress.Spec.TLS, tls) {
+		ingress.Spec.TLS = tls
+		changed = true
+	}
+	return changed
+}
+
 // newIngress returns a new Ingress instance for the given ArgoCD.
 func newIngress(cr *argoprojv1a1.ArgoCD) *extv1beta1.Ingress {
 	return &extv1beta1.Ingress{
@@ -81,6 +218,10 @@ func (r *ReconcileArgoCD) reconcileIngresses(cr *argoprojv1a1.ArgoCD) error {
 		return err
 	}
 
+	if err := r.reconcileArgoServerIngresses(cr); err != nil {
+		return err
+	}
+
 	if err := r.reconcileGrafanaIngress(cr); err != nil {
 		return err
 	}
@@ -91,66 +232,35 @@ func (r *ReconcileArgoCD) reconcileIngresses(cr *argoprojv1a1.ArgoCD) error {
 	return nil
 }
 
-// reconcileArgoServerIngress will ensure that the ArgoCD Server Ingress is present.
+// reconcileArgoServerIngress will ensure that the ArgoCD Server Ingress is present and targets the backend of the
+// server.
 func (r *ReconcileArgoCD) reconcileArgoServerIngress(cr *argoprojv1a1.ArgoCD) error {
 	ingress := newIngressWithSuffix("server", cr)
-	if argoutil.IsObjectFound(r.client, cr.Namespace, ingress.Name, ingress) {
-		if !cr.Spec.Server.Ingress.Enabled {
+	found := argoutil.IsObjectFound(r.client, cr.Namespace, ingress.Name, ingress)
+	if !cr.Spec.Server.Ingress.Enabled {
+		if found {
 			// Ingress exists but enabled flag has been set to false, delete the Ingress
 			return r.client.Delete(context.TODO(), ingress)
 		}
-		return nil // Ingress found and enabled, do nothing
-	}
-
-	if !cr.Spec.Server.Ingress.Enabled {
 		return nil // Ingress not enabled, move along...
 	}
 
-	// Add annotations
-	atns := getDefaultIngressAnnotations(cr)
-	atns[common.ArgoCDKeyIngressSSLRedirect] = "true"
-	atns[common.ArgoCDKeyIngressBackendProtocol] = "HTTP"
-
-	// Override default annotations if specified
-	if len(cr.Spec.Server.Ingress.Annotations) > 0 {
-		atns = cr.Spec.Server.Ingress.Annotations
-	}
-
-	ingress.ObjectMeta.Annotations = atns
+	// Add labels and annotations
+	seedManagedAnnotations(&ingress.ObjectMeta, legacyIngressAnnotationKeys)
+	changed := applyManagedMetadata(&ingress.ObjectMeta, cr.Spec.Server.Ingress.Labels, getArgoServerIngressAnnotations(cr))
 
-	// Add rules
-	ingress.Spec.Rules = []extv1beta1.IngressRule{
-		{
-			Host: getArgoServerHost(cr),
-			IngressRuleValue: extv1beta1.IngressRuleValue{
-				HTTP: &extv1beta1.HTTPIngressRuleValue{
-					Paths: []extv1beta1.HTTPIngressPath{
-						{
-							Path: getPathOrDefault(cr.Spec.Server.Ingress.Path),
-							Backend: extv1beta1.IngressBackend{
-								ServiceName: nameWithSuffix("server", cr),
-								ServicePort: intstr.FromString("http"),
-							},
-						},
-					},
-				},
-			},
-		},
+	// Add rules and TLS options, allowing override of TLS options if specified
+	port, _ := getArgoServerIngressBackend(cr)
+	rules := getArgoServerIngressRules(cr, getArgoServerHost(cr), cr.Spec.Server.Ingress.Path, port)
+	if updateIngressSpec(ingress, rules, getIngressTLS(getArgoServerHost(cr), cr.Spec.Server.Ingress.TLS)) {
+		changed = true
 	}
 
-	// Add default TLS options
-	ingress.Spec.TLS = []extv1beta1.IngressTLS{
-		{
-			Hosts: []string{
-				getArgoServerHost(cr),
-			},
-			SecretName: common.ArgoCDSecretName,
-		},
-	}
-
-	// Allow override of TLS options if specified
-	if len(cr.Spec.Server.Ingress.TLS) > 0 {
-		ingress.Spec.TLS = cr.Spec.Server.Ingress.TLS
+	if found {
+		if changed {
+			return r.client.Update(context.TODO(), ingress)
+		}
+		return nil
 	}
 
 	if err := controllerutil.SetControllerReference(cr, ingress, r.scheme); err != nil {
@@ -159,85 +269,130 @@ func (r *ReconcileArgoCD) reconcileArgoServerIngress(cr *argoprojv1a1.ArgoCD) er
 	return r.client.Create(context.TODO(), ingress)
 }
 
-// reconcileArgoServerGRPCIngress will ensure that the ArgoCD Server GRPC Ingress is present.
+// reconcileArgoServerGRPCIngress will ensure that the ArgoCD Server GRPC Ingress is present and targets the backend
+// of the server.
 func (r *ReconcileArgoCD) reconcileArgoServerGRPCIngress(cr *argoprojv1a1.ArgoCD) error {
 	ingress := newIngressWithSuffix("grpc", cr)
-	if argoutil.IsObjectFound(r.client, cr.Namespace, ingress.Name, ingress) {
-		if !cr.Spec.Server.GRPC.Ingress.Enabled {
+	found := argoutil.IsObjectFound(r.client, cr.Namespace, ingress.Name, ingress)
+	if !cr.Spec.Server.GRPC.Ingress.Enabled {
+		if found {
 			// Ingress exists but enabled flag has been set to false, delete the Ingress
 			return r.client.Delete(context.TODO(), ingress)
 		}
-		return nil // Ingress found and enabled, do nothing
-	}
-
-	if !cr.Spec.Server.GRPC.Ingress.Enabled {
 		return nil // Ingress not enabled, move along...
 	}
 
-	// Add annotations
-	atns := getDefaultIngressAnnotations(cr)
-	atns[common.ArgoCDKeyIngressBackendProtocol] = "GRPC"
+	// Add labels and annotations
+	seedManagedAnnotations(&ingress.ObjectMeta, legacyIngressAnnotationKeys)
+	changed := applyManagedMetadata(&ingress.ObjectMeta, cr.Spec.Server.GRPC.Ingress.Labels, getArgoServerGRPCIngressAnnotations(cr))
 
-	// Override default annotations if specified
-	if len(cr.Spec.Server.GRPC.Ingress.Annotations) > 0 {
-		atns = cr.Spec.Server.GRPC.Ingress.Annotations
+	// Add rules and TLS options, allowing override of TLS options if specified
+	port, _ := getArgoServerGRPCIngressBackend(cr)
+	rules := getArgoServerIngressRules(cr, getArgoServerGRPCHost(cr), cr.Spec.Server.GRPC.Ingress.Path, port)
+	if updateIngressSpec(ingress, rules, getIngressTLS(getArgoServerGRPCHost(cr), cr.Spec.Server.GRPC.Ingress.TLS)) {
+		changed = true
 	}
 
-	ingress.ObjectMeta.Annotations = atns
-
-	// Add rules
-	ingress.Spec.Rules = []extv1beta1.IngressRule{
-		{
-			Host: getArgoServerGRPCHost(cr),
-			IngressRuleValue: extv1beta1.IngressRuleValue{
-				HTTP: &extv1beta1.HTTPIngressRuleValue{
-					Paths: []extv1beta1.HTTPIngressPath{
-						{
-							Path: getPathOrDefault(cr.Spec.Server.GRPC.Ingress.Path),
-							Backend: extv1beta1.IngressBackend{
-								ServiceName: nameWithSuffix("server", cr),
-								ServicePort: intstr.FromString("https"),
-							},
-						},
-					},
-				},
-			},
-		},
+	if found {
+		if changed {
+			return r.client.Update(context.TODO(), ingress)
+		}
+		return nil
 	}
 
-	// Add TLS options
-	ingress.Spec.TLS = []extv1beta1.IngressTLS{
-		{
-			Hosts: []string{
-				getArgoServerGRPCHost(cr),
-			},
-			SecretName: common.ArgoCDSecretName,
-		},
+	if err := controllerutil.SetControllerReference(cr, ingress, r.scheme); err != nil {
+		return err
 	}
+	return r.client.Create(context.TODO(), ingress)
+}
 
-	// Allow override of TLS options if specified
-	if len(cr.Spec.Server.GRPC.Ingress.TLS) > 0 {
-		ingress.Spec.TLS = cr.Spec.Server.GRPC.Ingress.TLS
+// reconcileArgoServerIngresses will ensure that the additional Ingresses of the ArgoCD Server are present and up to
+// date, and that the Ingresses no longer in the spec are removed.
+func (r *ReconcileArgoCD) reconcileArgoServerIngresses(cr *argoprojv1a1.ArgoCD) error {
+	desired := make(map[string]bool)
+	for _, spec := range cr.Spec.Server.Ingresses {
+		ingress := newIngressWithSuffix(fmt.Sprintf("server-%s", spec.Name), cr)
+		desired[ingress.Name] = true
+		found := argoutil.IsObjectFound(r.client, cr.Namespace, ingress.Name, ingress)
+
+		host := spec.Host
+		if host == "" {
+			host = getArgoServerHost(cr)
+		}
+
+		port, _ := getArgoServerIngressBackend(cr)
+
+		changed := applyManagedMetadata(&ingress.ObjectMeta, spec.Labels, getArgoServerIngressesAnnotations(cr, spec))
+		if ingress.ObjectMeta.Labels[common.ArgoCDKeyComponent] != serverIngressComponent {
+			ingress.ObjectMeta.Labels[common.ArgoCDKeyComponent] = serverIngressComponent
+			changed = true
+		}
+
+		var ingressClassName *string
+		if spec.IngressClassName != "" {
+			ingressClassName = &spec.IngressClassName
+		}
+
+		if !reflect.DeepEqual(ingress.Spec.IngressClassName, ingressClassName) {
+			ingress.Spec.IngressClassName = ingressClassName
+			changed = true
+		}
+
+		// Add rules and TLS options, allowing override of TLS options if specified
+		if updateIngressSpec(ingress, getArgoServerIngressRules(cr, host, spec.Path, port), getIngressTLS(host, spec.TLS)) {
+			changed = true
+		}
+
+		if found {
+			if changed {
+				if err := r.client.Update(context.TODO(), ingress); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		if err := controllerutil.SetControllerReference(cr, ingress, r.scheme); err != nil {
+			return err
+		}
+		if err := r.client.Create(context.TODO(), ingress); err != nil {
+			return err
+		}
 	}
 
-	if err := controllerutil.SetControllerReference(cr, ingress, r.scheme); err != nil {
+	ingresses := &extv1beta1.IngressList{}
+	opts := []client.ListOption{
+		client.InNamespace(cr.Namespace),
+		client.MatchingLabels{
+			common.ArgoCDKeyComponent: serverIngressComponent,
+			common.ArgoCDKeyManagedBy: cr.Name,
+			common.ArgoCDKeyPartOf:    common.ArgoCDAppName,
+		},
+	}
+	if err := r.client.List(context.TODO(), ingresses, opts...); err != nil {
 		return err
 	}
-	return r.client.Create(context.TODO(), ingress)
+	for i := range ingresses.Items {
+		if desired[ingresses.Items[i].Name] {
+			continue
+		}
+		log.Info(fmt.Sprintf("removing server ingress %s that is no longer in the spec", ingresses.Items[i].Name))
+		if err := r.client.Delete(context.TODO(), &ingresses.Items[i]); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
-// reconcileGrafanaIngress will ensure that the ArgoCD Server GRPC Ingress is present.
+// reconcileGrafanaIngress will ensure that the Grafana Ingress is present and up to date.
 func (r *ReconcileArgoCD) reconcileGrafanaIngress(cr *argoprojv1a1.ArgoCD) error {
 	ingress := newIngressWithSuffix("grafana", cr)
-	if argoutil.IsObjectFound(r.client, cr.Namespace, ingress.Name, ingress) {
-		if !cr.Spec.Grafana.Enabled || !cr.Spec.Grafana.Ingress.Enabled {
+	found := argoutil.IsObjectFound(r.client, cr.Namespace, ingress.Name, ingress)
+	if !isManagedGrafanaEnabled(cr) || !cr.Spec.Grafana.Ingress.Enabled {
+		if found {
 			// Ingress exists but enabled flag has been set to false, delete the Ingress
 			return r.client.Delete(context.TODO(), ingress)
 		}
-		return nil // Ingress found and enabled, do nothing
-	}
-
-	if !cr.Spec.Grafana.Enabled || !cr.Spec.Grafana.Ingress.Enabled {
 		return nil // Grafana itself or Ingress not enabled, move along...
 	}
 
@@ -251,7 +406,9 @@ func (r *ReconcileArgoCD) reconcileGrafanaIngress(cr *argoprojv1a1.ArgoCD) error
 		atns = cr.Spec.Grafana.Ingress.Annotations
 	}
 
-	ingress.ObjectMeta.Annotations = atns
+	// Add the labels and annotations, keeping the ones added by others
+	seedManagedAnnotations(&ingress.ObjectMeta, legacyIngressAnnotationKeys)
+	applyManagedMetadata(&ingress.ObjectMeta, cr.Spec.Grafana.Ingress.Labels, atns)
 
 	// Add rules
 	ingress.Spec.Rules = []extv1beta1.IngressRule{
@@ -289,24 +446,25 @@ func (r *ReconcileArgoCD) reconcileGrafanaIngress(cr *argoprojv1a1.ArgoCD) error
 		ingress.Spec.TLS = cr.Spec.Grafana.Ingress.TLS
 	}
 
+	if found {
+		return r.client.Update(context.TODO(), ingress)
+	}
+
 	if err := controllerutil.SetControllerReference(cr, ingress, r.scheme); err != nil {
 		return err
 	}
 	return r.client.Create(context.TODO(), ingress)
 }
 
-// reconcilePrometheusIngress will ensure that the Prometheus Ingress is present.
+// reconcilePrometheusIngress will ensure that the Prometheus Ingress is present and up to date.
 func (r *ReconcileArgoCD) reconcilePrometheusIngress(cr *argoprojv1a1.ArgoCD) error {
 	ingress := newIngressWithSuffix("prometheus", cr)
-	if argoutil.IsObjectFound(r.client, cr.Namespace, ingress.Name, ingress) {
-		if !cr.Spec.Prometheus.Enabled || !cr.Spec.Prometheus.Ingress.Enabled {
+	found := argoutil.IsObjectFound(r.client, cr.Namespace, ingress.Name, ingress)
+	if !cr.Spec.Prometheus.Enabled || !cr.Spec.Prometheus.Ingress.Enabled {
+		if found {
 			// Ingress exists but enabled flag has been set to false, delete the Ingress
 			return r.client.Delete(context.TODO(), ingress)
 		}
-		return nil // Ingress found and enabled, do nothing
-	}
-
-	if !cr.Spec.Prometheus.Enabled || !cr.Spec.Prometheus.Ingress.Enabled {
 		return nil // Prometheus itself or Ingress not enabled, move along...
 	}
 
@@ -320,7 +478,9 @@ func (r *ReconcileArgoCD) reconcilePrometheusIngress(cr *argoprojv1a1.ArgoCD) er
 		atns = cr.Spec.Prometheus.Ingress.Annotations
 	}
 
-	ingress.ObjectMeta.Annotations = atns
+	// Add the labels and annotations, keeping the ones added by others
+	seedManagedAnnotations(&ingress.ObjectMeta, legacyIngressAnnotationKeys)
+	applyManagedMetadata(&ingress.ObjectMeta, cr.Spec.Prometheus.Ingress.Labels, atns)
 
 	// Add rules
 	ingress.Spec.Rules = []extv1beta1.IngressRule{
@@ -345,7 +505,10 @@ func (r *ReconcileArgoCD) reconcilePrometheusIngress(cr *argoprojv1a1.ArgoCD) er
 	// Add TLS options
 	ingress.Spec.TLS = []extv1beta1.IngressTLS{
 		{
-			Hosts:      []string{cr.Name},
+			Hosts: []string{
+				cr.Name,
+				getPrometheusHost(cr),
+			},
 			SecretName: common.ArgoCDSecretName,
 		},
 	}
@@ -355,6 +518,10 @@ func (r *ReconcileArgoCD) reconcilePrometheusIngress(cr *argoprojv1a1.ArgoCD) er
 		ingress.Spec.TLS = cr.Spec.Prometheus.Ingress.TLS
 	}
 
+	if found {
+		return r.client.Update(context.TODO(), ingress)
+	}
+
 	if err := controllerutil.SetControllerReference(cr, ingress, r.scheme); err != nil {
 		return err
 	}
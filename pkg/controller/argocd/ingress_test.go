@@ -0,0 +1,191 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocd
+
+import (
+	"context"
+	"testing"
+
+	"gotest.tools/assert"
+	extv1beta1 "k8s.io/api/extensions/v1beta1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	argoprojv1alpha1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
+)
+
+func TestReconcileArgoCD_reconcileArgoServerIngresses(t *testing.T) {
+	restoreEnv(t)
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.Server.Host = "argocd.example.com"
+		a.Spec.Server.Ingresses = []argoprojv1alpha1.ArgoCDServerIngressSpec{
+			{
+				Name:             "internal",
+				IngressClassName: "nginx",
+			},
+			{
+				Name:             "external",
+				Host:             "argocd.public.example.com",
+				IngressClassName: "alb",
+				Annotations:      map[string]string{"alb.ingress.kubernetes.io/scheme": "internet-facing"},
+			},
+		}
+	})
+	r := makeTestReconciler(t, a)
+
+	assert.NilError(t, r.reconcileArgoServerIngresses(a))
+
+	internal := &extv1beta1.Ingress{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-server-internal", Namespace: testNamespace}, internal))
+	assert.Equal(t, *internal.Spec.IngressClassName, "nginx")
+	assert.Equal(t, internal.Spec.Rules[0].Host, "argocd.example.com")
+	assert.Equal(t, internal.Spec.Rules[0].HTTP.Paths[0].Backend.ServiceName, "argocd-server")
+	assert.DeepEqual(t, internal.Spec.TLS, []extv1beta1.IngressTLS{{
+		Hosts:      []string{"argocd.example.com"},
+		SecretName: common.ArgoCDSecretName,
+	}})
+
+	external := &extv1beta1.Ingress{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-server-external", Namespace: testNamespace}, external))
+	assert.Equal(t, *external.Spec.IngressClassName, "alb")
+	assert.Equal(t, external.Spec.Rules[0].Host, "argocd.public.example.com")
+	assert.Equal(t, external.Annotations["alb.ingress.kubernetes.io/scheme"], "internet-facing")
+	assert.Equal(t, external.Annotations[common.ArgoCDKeyIngressBackendProtocol], "HTTPS")
+
+	// An Ingress that is up to date is not updated.
+	assert.NilError(t, r.reconcileArgoServerIngresses(a))
+	unchanged := &extv1beta1.Ingress{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-server-external", Namespace: testNamespace}, unchanged))
+	assert.Equal(t, unchanged.ResourceVersion, external.ResourceVersion)
+
+	// Changes to an Ingress are applied, and an Ingress removed from the spec is deleted.
+	a.Spec.Server.Ingresses = []argoprojv1alpha1.ArgoCDServerIngressSpec{
+		{
+			Name:             "internal",
+			IngressClassName: "nginx-internal",
+		},
+	}
+	assert.NilError(t, r.reconcileArgoServerIngresses(a))
+
+	internal = &extv1beta1.Ingress{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-server-internal", Namespace: testNamespace}, internal))
+	assert.Equal(t, *internal.Spec.IngressClassName, "nginx-internal")
+
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-server-external", Namespace: testNamespace}, &extv1beta1.Ingress{})
+	assert.Assert(t, errors.IsNotFound(err))
+}
+
+func TestReconcileArgoCD_reconcileArgoServerIngress_backend(t *testing.T) {
+	restoreEnv(t)
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.Server.Ingress.Enabled = true
+		a.Spec.Server.GRPC.Ingress.Enabled = true
+	})
+	r := makeTestReconciler(t, a)
+
+	// Toggling the insecure flag also updates the backend of the existing Ingresses.
+	for _, insecure := range []bool{false, true, false} {
+		a.Spec.Server.Insecure = insecure
+		assert.NilError(t, r.reconcileArgoServerIngress(a))
+		assert.NilError(t, r.reconcileArgoServerGRPCIngress(a))
+
+		port, protocol, grpcProtocol := "https", "HTTPS", "GRPCS"
+		if insecure {
+			port, protocol, grpcProtocol = "http", "HTTP", "GRPC"
+		}
+
+		ingress := &extv1beta1.Ingress{}
+		assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-server", Namespace: testNamespace}, ingress))
+		assert.Equal(t, ingress.Annotations[common.ArgoCDKeyIngressBackendProtocol], protocol)
+		assert.Equal(t, ingress.Spec.Rules[0].HTTP.Paths[0].Backend.ServicePort.StrVal, port)
+
+		grpc := &extv1beta1.Ingress{}
+		assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-grpc", Namespace: testNamespace}, grpc))
+		assert.Equal(t, grpc.Annotations[common.ArgoCDKeyIngressBackendProtocol], grpcProtocol)
+		assert.Equal(t, grpc.Spec.Rules[0].HTTP.Paths[0].Backend.ServicePort.StrVal, port)
+	}
+
+	// The backend protocol is added to the annotations of the spec unless given.
+	a.Spec.Server.Ingress.Annotations = map[string]string{"cert-manager.io/cluster-issuer": "letsencrypt"}
+	assert.NilError(t, r.reconcileArgoServerIngress(a))
+
+	ingress := &extv1beta1.Ingress{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-server", Namespace: testNamespace}, ingress))
+	assert.Equal(t, ingress.Annotations[common.ArgoCDKeyIngressBackendProtocol], "HTTPS")
+	assert.Equal(t, len(a.Spec.Server.Ingress.Annotations), 1)
+}
+
+func TestReconcileArgoCD_reconcileArgoServerIngress_metadata(t *testing.T) {
+	restoreEnv(t)
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.Server.Ingress.Enabled = true
+		a.Spec.Server.Ingress.Labels = map[string]string{"example.com/external-dns": "public"}
+	})
+	r := makeTestReconciler(t, a)
+	key := types.NamespacedName{Name: "argocd-server", Namespace: testNamespace}
+
+	assert.NilError(t, r.reconcileArgoServerIngress(a))
+
+	ingress := &extv1beta1.Ingress{}
+	assert.NilError(t, r.client.Get(context.TODO(), key, ingress))
+	assert.Equal(t, ingress.Labels["example.com/external-dns"], "public")
+	assert.Equal(t, ingress.Annotations[common.ArgoCDKeyIngressClass], "nginx")
+
+	// The annotations of the spec replace the default annotations of an existing Ingress, while the annotations added
+	// by others are kept.
+	ingress.Annotations["cert-manager.io/issued"] = "true"
+	assert.NilError(t, r.client.Update(context.TODO(), ingress))
+	a.Spec.Server.Ingress.Labels = nil
+	a.Spec.Server.Ingress.Annotations = map[string]string{"cert-manager.io/cluster-issuer": "letsencrypt"}
+	assert.NilError(t, r.reconcileArgoServerIngress(a))
+
+	ingress = &extv1beta1.Ingress{}
+	assert.NilError(t, r.client.Get(context.TODO(), key, ingress))
+	_, ok := ingress.Labels["example.com/external-dns"]
+	assert.Assert(t, !ok)
+	_, ok = ingress.Annotations[common.ArgoCDKeyIngressClass]
+	assert.Assert(t, !ok)
+	assert.Equal(t, ingress.Annotations["cert-manager.io/cluster-issuer"], "letsencrypt")
+	assert.Equal(t, ingress.Annotations["cert-manager.io/issued"], "true")
+}
+
+func TestReconcileArgoCD_reconcileArgoServerIngress_legacyAnnotations(t *testing.T) {
+	restoreEnv(t)
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.Server.Ingress.Enabled = true
+		a.Spec.Server.Ingress.Annotations = map[string]string{"cert-manager.io/cluster-issuer": "letsencrypt"}
+	})
+
+	// An Ingress created before the managed annotations were recorded carries the default annotations of that time.
+	ingress := newIngressWithSuffix("server", a)
+	ingress.Annotations = map[string]string{
+		common.ArgoCDKeyIngressClass:       "nginx",
+		common.ArgoCDKeyIngressSSLRedirect: "true",
+		"cert-manager.io/issued":           "true",
+	}
+	r := makeTestReconciler(t, a, ingress)
+
+	assert.NilError(t, r.reconcileArgoServerIngress(a))
+
+	ingress = &extv1beta1.Ingress{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-server", Namespace: testNamespace}, ingress))
+	_, ok := ingress.Annotations[common.ArgoCDKeyIngressClass]
+	assert.Assert(t, !ok)
+	_, ok = ingress.Annotations[common.ArgoCDKeyIngressSSLRedirect]
+	assert.Assert(t, !ok)
+	assert.Equal(t, ingress.Annotations["cert-manager.io/cluster-issuer"], "letsencrypt")
+	assert.Equal(t, ingress.Annotations["cert-manager.io/issued"], "true")
+}
@@ -16,6 +16,7 @@ package argocd
 
 import (
 	"context"
+	"crypto/sha256"
 	b64 "encoding/base64"
 	json "encoding/json"
 	"fmt"
@@ -51,6 +52,45 @@ func getKeycloakContainerImage(img string, ver string) string {
 	return argoutil.CombineImageTag(img, ver)
 }
 
+// getKeycloakSpec will return the Keycloak options for the given ArgoCD, or empty options when none are set.
+func getKeycloakSpec(cr *argoprojv1a1.ArgoCD) *argoprojv1a1.ArgoCDKeycloakSpec {
+	if cr.Spec.SSO == nil || cr.Spec.SSO.Keycloak == nil {
+		return &argoprojv1a1.ArgoCDKeycloakSpec{}
+	}
+	return cr.Spec.SSO.Keycloak
+}
+
+// getKeycloakVersion will return the Keycloak image tag for the given ArgoCD.
+func getKeycloakVersion(cr *argoprojv1a1.ArgoCD) string {
+	if v := getKeycloakSpec(cr).Version; v != "" {
+		return v
+	}
+	return common.ArgoCDKeycloakVersion
+}
+
+// isKeycloakImageOverridden will return true if the Keycloak image is set in the given ArgoCD or the environment,
+// in which case it is used directly instead of the ImageStreamTag.
+func isKeycloakImageOverridden(cr *argoprojv1a1.ArgoCD) bool {
+	spec := getKeycloakSpec(cr)
+	if spec.Image != "" {
+		return true
+	}
+	return spec.Version == "" && argoutil.GetDefaultImage(common.ArgoCDRelatedImageKeycloakEnvName) != ""
+}
+
+// getKeycloakImage will return the image used for Keycloak, which is either set in the given ArgoCD, overridden
+// from the environment or resolved from the default ImageStreamTag.
+func getKeycloakImage(cr *argoprojv1a1.ArgoCD) string {
+	spec := getKeycloakSpec(cr)
+	if spec.Image != "" {
+		return getKeycloakContainerImage(spec.Image, getKeycloakVersion(cr))
+	}
+	if e := argoutil.GetDefaultImage(common.ArgoCDRelatedImageKeycloakEnvName); e != "" && spec.Version == "" {
+		return e
+	}
+	return getKeycloakContainerImage(common.ArgoCDKeycloakImageName, getKeycloakVersion(cr))
+}
+
 func getKeycloakConfigMapTemplate(ns string) *corev1.ConfigMap {
 	return &corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
@@ -161,13 +201,49 @@ func getKeycloakContainer() corev1.Container {
 	}
 }
 
+// getKeycloakDatabaseEnv will return the environment variables that configure Keycloak to use the external
+// PostgreSQL database of the given ArgoCD, read from its credentials Secret.
+func getKeycloakDatabaseEnv(cr *argoprojv1a1.ArgoCD) []corev1.EnvVar {
+	database := getKeycloakSpec(cr).Database
+	if database == nil {
+		return nil
+	}
+
+	secretKeyRef := func(key string) *corev1.EnvVarSource {
+		return &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: database.CredentialsSecret,
+				},
+				Key: key,
+			},
+		}
+	}
+
+	// The RH-SSO image resolves the database host and port from the service environment variables matching the
+	// prefix mapping.
+	return []corev1.EnvVar{
+		{Name: "DB_SERVICE_PREFIX_MAPPING", Value: "keycloak-postgresql=DB"},
+		{Name: "DB_JNDI", Value: "java:jboss/datasources/KeycloakDS"},
+		{Name: "DB_DATABASE", ValueFrom: secretKeyRef("database")},
+		{Name: "DB_USERNAME", ValueFrom: secretKeyRef("username")},
+		{Name: "DB_PASSWORD", ValueFrom: secretKeyRef("password")},
+		{Name: "KEYCLOAK_POSTGRESQL_SERVICE_HOST", ValueFrom: secretKeyRef("host")},
+		{Name: "KEYCLOAK_POSTGRESQL_SERVICE_PORT", ValueFrom: secretKeyRef("port")},
+	}
+}
+
 func getKeycloakDeploymentConfigTemplate(cr *argoprojv1a1.ArgoCD) *appsv1.DeploymentConfig {
 	ns := cr.Namespace
 	keycloakContainer := getKeycloakContainer()
+	keycloakContainer.Env = append(keycloakContainer.Env, getKeycloakDatabaseEnv(cr)...)
+	if resources := getKeycloakSpec(cr).Resources; resources != nil {
+		keycloakContainer.Resources = *resources
+	}
 	keycloakImage := common.ArgoCDKeycloakImageName
-	keycloakVersion := common.ArgoCDKeycloakVersion
+	keycloakVersion := getKeycloakVersion(cr)
 
-	return &appsv1.DeploymentConfig{
+	dc := &appsv1.DeploymentConfig{
 		ObjectMeta: metav1.ObjectMeta{
 			Annotations: map[string]string{
 				"argocd.argoproj.io/realm-created": "false",
@@ -246,6 +322,17 @@ func getKeycloakDeploymentConfigTemplate(cr *argoprojv1a1.ArgoCD) *appsv1.Deploy
 			},
 		},
 	}
+
+	// When the image is overridden, use it directly instead of the ImageStreamTag.
+	if isKeycloakImageOverridden(cr) {
+		dc.Spec.Template.Spec.Containers[0].Image = getKeycloakImage(cr)
+		dc.Spec.Triggers = appsv1.DeploymentTriggerPolicies{
+			appsv1.DeploymentTriggerPolicy{
+				Type: "ConfigChange",
+			},
+		}
+	}
+	return dc
 }
 
 func getKeycloakServiceTemplate(ns string) *corev1.Service {
@@ -271,7 +358,8 @@ func getKeycloakServiceTemplate(ns string) *corev1.Service {
 	}
 }
 
-func getKeycloakRouteTemplate(ns string) *routev1.Route {
+func getKeycloakRouteTemplate(cr *argoprojv1a1.ArgoCD) *routev1.Route {
+	ns := cr.Namespace
 	return &routev1.Route{
 		ObjectMeta: metav1.ObjectMeta{
 			Labels:      map[string]string{"application": "${APPLICATION_NAME}"},
@@ -281,6 +369,7 @@ func getKeycloakRouteTemplate(ns string) *routev1.Route {
 		},
 		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Route"},
 		Spec: routev1.RouteSpec{
+			Host: getKeycloakSpec(cr).Host,
 			TLS: &routev1.TLSConfig{
 				Termination: "reencrypt",
 			},
@@ -296,10 +385,17 @@ func newKeycloakTemplateInstance(cr *argoprojv1a1.ArgoCD) (*template.TemplateIns
 	if err != nil {
 		return nil, err
 	}
+	data, err := json.Marshal(tpl)
+	if err != nil {
+		return nil, err
+	}
 	return &template.TemplateInstance{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      defaultTemplateIdentifier,
 			Namespace: cr.Namespace,
+			Annotations: map[string]string{
+				common.ArgoCDKeycloakTemplateChecksumAnnotation: fmt.Sprintf("%x", sha256.Sum256(data)),
+			},
 		},
 		Spec: template.TemplateInstanceSpec{
 			Template: tpl,
@@ -314,7 +410,7 @@ func newKeycloakTemplate(cr *argoprojv1a1.ArgoCD) (template.Template, error) {
 	secretTemplate := getKeycloakSecretTemplate(ns)
 	deploymentConfigTemplate := getKeycloakDeploymentConfigTemplate(cr)
 	serviceTemplate := getKeycloakServiceTemplate(ns)
-	routeTemplate := getKeycloakRouteTemplate(ns)
+	routeTemplate := getKeycloakRouteTemplate(cr)
 
 	configMap, err := json.Marshal(configMapTemplate)
 	if err != nil {
@@ -447,6 +543,11 @@ func (r *ReconcileArgoCD) prepareKeycloakConfig(cr *argoprojv1a1.ArgoCD) (*keycl
 		return nil, err
 	}
 
+	// Trust the custom root CA as well, the Route may be used when the service is not reachable.
+	if rootCA := getKeycloakSpec(cr).RootCA; rootCA != "" {
+		serverCert = append(append(serverCert, '\n'), rootCA...)
+	}
+
 	// By default TLS Verification should be enabled.
 	if cr.Spec.SSO.VerifyTLS == nil || *cr.Spec.SSO.VerifyTLS == true {
 		tlsVerification = true
@@ -601,7 +702,7 @@ func (r *ReconcileArgoCD) updateArgoCDConfiguration(cr *argoprojv1a1.ArgoCD, kRo
 		return err
 	}
 
-	argoCDSecret.Data["oidc.keycloak.clientSecret"] = []byte(argocdClientSecret)
+	argoCDSecret.Data[keycloakClientSecretKey] = []byte(argocdClientSecret)
 	err = r.client.Update(context.TODO(), argoCDSecret)
 	if err != nil {
 		log.Error(err, fmt.Sprintf("Error updating ArgoCD Secret for ArgoCD %s in namespace %s",
@@ -648,6 +749,7 @@ func (r *ReconcileArgoCD) updateArgoCDConfiguration(cr *argoprojv1a1.ArgoCD, kRo
 		ClientID:       keycloakClient,
 		ClientSecret:   "$oidc.keycloak.clientSecret",
 		RequestedScope: []string{"openid", "profile", "email", "groups"},
+		RootCA:         getKeycloakSpec(cr).RootCA,
 	})
 
 	argoCDCM := newConfigMapWithName(common.ArgoCDConfigMapName, cr)
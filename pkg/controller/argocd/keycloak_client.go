@@ -64,6 +64,110 @@ func createRealm(cfg *keycloakConfig) (string, error) {
 	return status, nil
 }
 
+// updateClientSecret will set the secret of the Argo CD client in the Argo CD realm of keycloak.
+func updateClientSecret(cfg *keycloakConfig, secret string) error {
+	req, err := defaultRequester(cfg.KeycloakServerCert, cfg.VerifyTLS)
+	if err != nil {
+		return err
+	}
+
+	// create a new http client.
+	h := &httpclient{
+		requester: req,
+	}
+
+	kSvcName := h.getKeycloakURL(cfg.ArgoNamespace)
+	if kSvcName != "" {
+		cfg.KeycloakURL = kSvcName
+	}
+
+	h.URL = cfg.KeycloakURL
+
+	// login request updates the auth token for httpclient.
+	if err := h.login(cfg.Username, cfg.Password); err != nil {
+		return err
+	}
+
+	id, err := h.getClientID(keycloakRealm, keycloakClient)
+	if err != nil {
+		return err
+	}
+
+	return h.putClientSecret(keycloakRealm, id, secret)
+}
+
+// getClientID returns the internal id of the client with the given client ID in the given realm.
+func (h *httpclient) getClientID(realm, clientID string) (string, error) {
+	request, err := http.NewRequest("GET",
+		fmt.Sprintf("%s%s/%s/clients?clientId=%s", h.URL, realmURL, realm, url.QueryEscape(clientID)),
+		nil)
+	if err != nil {
+		return "", err
+	}
+
+	request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", h.token))
+
+	response, err := h.requester.Do(request)
+	if err != nil {
+		return "", err
+	}
+
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return "", errors.Errorf("failed to get keycloak client %s: %s", clientID, response.Status)
+	}
+
+	body, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return "", err
+	}
+
+	clients := []struct {
+		ID string `json:"id"`
+	}{}
+	if err := json.Unmarshal(body, &clients); err != nil {
+		return "", err
+	}
+
+	if len(clients) == 0 {
+		return "", errors.Errorf("keycloak client %s not found in realm %s", clientID, realm)
+	}
+	return clients[0].ID, nil
+}
+
+// putClientSecret updates the secret of the client with the given internal id in the given realm.
+func (h *httpclient) putClientSecret(realm, id, secret string) error {
+	client, err := json.Marshal(map[string]string{
+		"id":     id,
+		"secret": secret,
+	})
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequest("PUT",
+		fmt.Sprintf("%s%s/%s/clients/%s", h.URL, realmURL, realm, id),
+		bytes.NewBuffer(client))
+	if err != nil {
+		return err
+	}
+
+	// set headers.
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", h.token))
+
+	response, err := h.requester.Do(request)
+	if err != nil {
+		return err
+	}
+
+	_ = response.Body.Close()
+	if response.StatusCode != http.StatusNoContent {
+		return errors.Errorf("failed to update keycloak client secret: %s", response.Status)
+	}
+	return nil
+}
+
 // login requests a new auth token.
 func (h *httpclient) login(user, pass string) error {
 	form := url.Values{}
@@ -107,3 +107,36 @@ func TestClient_useKeycloakServerCertificate(t *testing.T) {
 	assert.Equal(t, resp.StatusCode, 200)
 
 }
+
+func TestKeycloak_testUpdateClientSecret(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.Method {
+		case http.MethodGet:
+			assert.Equal(t, req.URL.Path, realmURL+"/argocd/clients")
+			assert.Equal(t, req.URL.Query().Get("clientId"), keycloakClient)
+			_, err := w.Write([]byte(`[{"id":"1234","clientId":"argocd"}]`))
+			assert.NilError(t, err)
+		case http.MethodPut:
+			assert.Equal(t, req.URL.Path, realmURL+"/argocd/clients/1234")
+			body := map[string]string{}
+			assert.NilError(t, jsoniter.NewDecoder(req.Body).Decode(&body))
+			assert.Equal(t, body["secret"], "rotated")
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("unexpected request %s %s", req.Method, req.URL.Path)
+		}
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	h := &httpclient{
+		requester: server.Client(),
+		URL:       server.URL,
+		token:     "dummy",
+	}
+
+	id, err := h.getClientID(keycloakRealm, keycloakClient)
+	assert.NilError(t, err)
+	assert.Equal(t, id, "1234")
+	assert.NilError(t, h.putClientSecret(keycloakRealm, id, "rotated"))
+}
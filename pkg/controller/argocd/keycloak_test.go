@@ -23,6 +23,7 @@ import (
 	routev1 "github.com/openshift/api/route/v1"
 	"gotest.tools/assert"
 	corev1 "k8s.io/api/core/v1"
+	resourcev1 "k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -118,7 +119,7 @@ func TestNewKeycloakTemplate_testService(t *testing.T) {
 }
 
 func TestNewKeycloakTemplate_testRoute(t *testing.T) {
-	route := getKeycloakRouteTemplate(fakeNs)
+	route := getKeycloakRouteTemplate(makeTestArgoCD(func(a *argoappv1.ArgoCD) { a.Namespace = fakeNs }))
 	assert.Equal(t, route.Name, "${APPLICATION_NAME}")
 	assert.Equal(t, route.Namespace, fakeNs)
 	assert.DeepEqual(t, route.Spec.To,
@@ -166,3 +167,62 @@ func TestKeycloak_testServerCert(t *testing.T) {
 	_, err = r.getKCServerCert(a)
 	assert.NilError(t, err)
 }
+
+func TestNewKeycloakTemplate_testExternalDatabase(t *testing.T) {
+	a := makeTestArgoCD()
+	a.Spec.SSO = &argoappv1.ArgoCDSSOSpec{
+		Provider: "keycloak",
+	}
+	dc := getKeycloakDeploymentConfigTemplate(a)
+	for _, env := range dc.Spec.Template.Spec.Containers[0].Env {
+		assert.Assert(t, env.Name != "DB_SERVICE_PREFIX_MAPPING")
+	}
+
+	a.Spec.SSO.Keycloak = &argoappv1.ArgoCDKeycloakSpec{
+		Database: &argoappv1.ArgoCDKeycloakDatabaseSpec{
+			CredentialsSecret: "keycloak-db",
+		},
+	}
+	dc = getKeycloakDeploymentConfigTemplate(a)
+
+	env := make(map[string]corev1.EnvVar)
+	for _, e := range dc.Spec.Template.Spec.Containers[0].Env {
+		env[e.Name] = e
+	}
+	assert.Equal(t, env["DB_SERVICE_PREFIX_MAPPING"].Value, "keycloak-postgresql=DB")
+	assert.Equal(t, env["DB_PASSWORD"].ValueFrom.SecretKeyRef.Name, "keycloak-db")
+	assert.Equal(t, env["DB_PASSWORD"].ValueFrom.SecretKeyRef.Key, "password")
+	assert.Equal(t, env["KEYCLOAK_POSTGRESQL_SERVICE_HOST"].ValueFrom.SecretKeyRef.Key, "host")
+}
+
+func TestNewKeycloakTemplate_testCustomization(t *testing.T) {
+	a := makeTestArgoCD()
+	a.Spec.SSO = &argoappv1.ArgoCDSSOSpec{
+		Provider: "keycloak",
+		Keycloak: &argoappv1.ArgoCDKeycloakSpec{
+			Host:  "sso.example.com",
+			Image: "registry.example.com/keycloak",
+			Resources: &corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{
+					corev1.ResourceMemory: resourcev1.MustParse("2Gi"),
+				},
+			},
+			Version: "7.5",
+		},
+	}
+
+	dc := getKeycloakDeploymentConfigTemplate(a)
+	container := dc.Spec.Template.Spec.Containers[0]
+	assert.Equal(t, container.Image, "registry.example.com/keycloak:7.5")
+	assert.DeepEqual(t, container.Resources, *a.Spec.SSO.Keycloak.Resources)
+	assert.DeepEqual(t, dc.Spec.Triggers, appsv1.DeploymentTriggerPolicies{{Type: "ConfigChange"}})
+	assert.Equal(t, getKeycloakImage(a), "registry.example.com/keycloak:7.5")
+
+	route := getKeycloakRouteTemplate(a)
+	assert.Equal(t, route.Spec.Host, "sso.example.com")
+
+	// Only the version is set, the ImageStreamTag is used with the given version.
+	a.Spec.SSO.Keycloak.Image = ""
+	dc = getKeycloakDeploymentConfigTemplate(a)
+	assert.Equal(t, dc.Spec.Triggers[0].ImageChangeParams.From.Name, "sso74-openshift-rhel8:7.5")
+}
@@ -0,0 +1,368 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocd
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1b1 "k8s.io/api/batch/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
+	"github.com/argoproj-labs/argocd-operator/pkg/controller/argoutil"
+)
+
+// knownHostsScanSuffix is the name suffix of the resources created for the SSH known hosts scans.
+const knownHostsScanSuffix = "ssh-known-hosts-scan"
+
+// isKnownHostsAutoScanEnabled will return true when Git hosts are configured for the SSH known hosts scans.
+func isKnownHostsAutoScanEnabled(cr *argoprojv1a1.ArgoCD) bool {
+	return len(cr.Spec.KnownHostsAutoScan.Hosts) > 0
+}
+
+// getKnownHostsScanSchedule will return the Cron schedule of the SSH known hosts scans for the given ArgoCD.
+func getKnownHostsScanSchedule(cr *argoprojv1a1.ArgoCD) string {
+	schedule := common.ArgoCDDefaultKnownHostsScanSchedule
+	if cr.Spec.KnownHostsAutoScan.Schedule != "" {
+		schedule = cr.Spec.KnownHostsAutoScan.Schedule
+	}
+	return schedule
+}
+
+// newKnownHostsScanCronJob returns a new CronJob instance for the SSH known hosts scans of the given ArgoCD.
+func newKnownHostsScanCronJob(cr *argoprojv1a1.ArgoCD) *batchv1b1.CronJob {
+	return &batchv1b1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      nameWithSuffix(knownHostsScanSuffix, cr),
+			Namespace: cr.Namespace,
+			Labels:    labelsForCluster(cr),
+		},
+	}
+}
+
+// newKnownHostsScanJob returns a new Job instance for the initial SSH known hosts scan of the given ArgoCD.
+func newKnownHostsScanJob(cr *argoprojv1a1.ArgoCD) *batchv1.Job {
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      nameWithSuffix(knownHostsScanSuffix+"-initial", cr),
+			Namespace: cr.Namespace,
+			Labels:    labelsForCluster(cr),
+		},
+	}
+}
+
+// newKnownHostsScanPodTemplateSpec will return the pod template of the SSH known hosts scans for the given ArgoCD.
+// The scan writes the host keys to the scan results ConfigMap, which the operator merges into the SSH known hosts.
+func newKnownHostsScanPodTemplateSpec(cr *argoprojv1a1.ArgoCD) corev1.PodTemplateSpec {
+	cmd := []string{"uid_entrypoint.sh", "argocd-operator-util", "scan-known-hosts", nameWithSuffix(knownHostsScanSuffix, cr)}
+	cmd = append(cmd, cr.Spec.KnownHostsAutoScan.Hosts...)
+
+	return corev1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{
+				common.ArgoCDKeyName: nameWithSuffix(knownHostsScanSuffix, cr),
+			},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{
+				Command:         cmd,
+				Image:           getDefaultExportJobImage(),
+				ImagePullPolicy: corev1.PullAlways,
+				Name:            "ssh-known-hosts-scan",
+			}},
+			RestartPolicy:      corev1.RestartPolicyOnFailure,
+			ServiceAccountName: nameWithSuffix(knownHostsScanSuffix, cr),
+		},
+	}
+}
+
+// reconcileKnownHostsAutoScan will ensure that the resources for the SSH known hosts scans are present when Git hosts
+// are configured and removed otherwise, and that the scanned host keys are merged into the SSH known hosts.
+func (r *ReconcileArgoCD) reconcileKnownHostsAutoScan(cr *argoprojv1a1.ArgoCD) error {
+	if !isKnownHostsAutoScanEnabled(cr) {
+		return r.deleteKnownHostsScanResources(cr)
+	}
+
+	sa, err := r.reconcileKnownHostsScanServiceAccount(cr)
+	if err != nil {
+		return err
+	}
+
+	role, err := r.reconcileKnownHostsScanRole(cr)
+	if err != nil {
+		return err
+	}
+
+	if err := r.reconcileKnownHostsScanRoleBinding(cr, role, sa); err != nil {
+		return err
+	}
+
+	if err := r.reconcileKnownHostsScanConfigMap(cr); err != nil {
+		return err
+	}
+
+	if err := r.reconcileKnownHostsScanCronJob(cr); err != nil {
+		return err
+	}
+
+	if err := r.reconcileKnownHostsScanJob(cr); err != nil {
+		return err
+	}
+
+	return r.reconcileScannedKnownHosts(cr)
+}
+
+// deleteKnownHostsScanResources will remove any resources created for the SSH known hosts scans.
+func (r *ReconcileArgoCD) deleteKnownHostsScanResources(cr *argoprojv1a1.ArgoCD) error {
+	objs := []runtime.Object{
+		newKnownHostsScanCronJob(cr),
+		newKnownHostsScanJob(cr),
+		newConfigMapWithSuffix(knownHostsScanSuffix, cr),
+		newRoleBindingWithname(knownHostsScanSuffix, cr),
+		newRole(knownHostsScanSuffix, nil, cr),
+		newServiceAccountWithName(knownHostsScanSuffix, cr),
+	}
+	return r.pruneObjects(cr, objs)
+}
+
+// reconcileKnownHostsScanServiceAccount will ensure that the ServiceAccount of the SSH known hosts scans is present.
+func (r *ReconcileArgoCD) reconcileKnownHostsScanServiceAccount(cr *argoprojv1a1.ArgoCD) (*corev1.ServiceAccount, error) {
+	sa := newServiceAccountWithName(knownHostsScanSuffix, cr)
+	if argoutil.IsObjectFound(r.client, cr.Namespace, sa.Name, sa) {
+		return sa, nil // ServiceAccount found, move along...
+	}
+
+	if err := controllerutil.SetControllerReference(cr, sa, r.scheme); err != nil {
+		return nil, err
+	}
+	return sa, r.client.Create(context.TODO(), sa)
+}
+
+// reconcileKnownHostsScanRole will ensure that the Role of the SSH known hosts scans is present, only allowing the
+// scans to update the scan results ConfigMap.
+func (r *ReconcileArgoCD) reconcileKnownHostsScanRole(cr *argoprojv1a1.ArgoCD) (*v1.Role, error) {
+	policyRules := []v1.PolicyRule{
+		{
+			APIGroups:     []string{""},
+			Resources:     []string{"configmaps"},
+			ResourceNames: []string{nameWithSuffix(knownHostsScanSuffix, cr)},
+			Verbs:         []string{"get", "patch"},
+		},
+	}
+
+	role := newRole(knownHostsScanSuffix, policyRules, cr)
+	if argoutil.IsObjectFound(r.client, cr.Namespace, role.Name, role) {
+		if !reflect.DeepEqual(role.Rules, policyRules) {
+			role.Rules = policyRules
+			return role, r.client.Update(context.TODO(), role)
+		}
+		return role, nil // Role found with nothing changed, move along...
+	}
+
+	if err := controllerutil.SetControllerReference(cr, role, r.scheme); err != nil {
+		return nil, err
+	}
+	return role, r.client.Create(context.TODO(), role)
+}
+
+// reconcileKnownHostsScanRoleBinding will ensure that the RoleBinding of the SSH known hosts scans is present.
+func (r *ReconcileArgoCD) reconcileKnownHostsScanRoleBinding(cr *argoprojv1a1.ArgoCD, role *v1.Role, sa *corev1.ServiceAccount) error {
+	roleBinding := newRoleBindingWithname(knownHostsScanSuffix, cr)
+	if argoutil.IsObjectFound(r.client, cr.Namespace, roleBinding.Name, roleBinding) {
+		return nil // RoleBinding found, move along...
+	}
+
+	roleBinding.RoleRef = v1.RoleRef{
+		APIGroup: v1.GroupName,
+		Kind:     "Role",
+		Name:     role.Name,
+	}
+	roleBinding.Subjects = []v1.Subject{
+		{
+			Kind:      v1.ServiceAccountKind,
+			Name:      sa.Name,
+			Namespace: sa.Namespace,
+		},
+	}
+
+	if err := controllerutil.SetControllerReference(cr, roleBinding, r.scheme); err != nil {
+		return err
+	}
+	return r.client.Create(context.TODO(), roleBinding)
+}
+
+// reconcileKnownHostsScanConfigMap will ensure that the ConfigMap holding the results of the SSH known hosts scans is
+// present. The ConfigMap is written by the scans, so an existing ConfigMap is left as is.
+func (r *ReconcileArgoCD) reconcileKnownHostsScanConfigMap(cr *argoprojv1a1.ArgoCD) error {
+	cm := newConfigMapWithSuffix(knownHostsScanSuffix, cr)
+	if argoutil.IsObjectFound(r.client, cr.Namespace, cm.Name, cm) {
+		return nil // ConfigMap found, move along...
+	}
+
+	cm.Data = map[string]string{
+		common.ArgoCDKeySSHKnownHosts: "",
+	}
+	if err := controllerutil.SetControllerReference(cr, cm, r.scheme); err != nil {
+		return err
+	}
+	return r.client.Create(context.TODO(), cm)
+}
+
+// reconcileKnownHostsScanCronJob will ensure that the CronJob running the SSH known hosts scans on a schedule is
+// present and up to date.
+func (r *ReconcileArgoCD) reconcileKnownHostsScanCronJob(cr *argoprojv1a1.ArgoCD) error {
+	template := newKnownHostsScanPodTemplateSpec(cr)
+
+	cj := newKnownHostsScanCronJob(cr)
+	if err := r.reconcilePodSecurityCompliance(cr, "CronJob/"+cj.Name, &template); err != nil {
+		return err
+	}
+	if argoutil.IsObjectFound(r.client, cr.Namespace, cj.Name, cj) {
+		changed := false
+		if cj.Spec.Schedule != getKnownHostsScanSchedule(cr) {
+			cj.Spec.Schedule = getKnownHostsScanSchedule(cr)
+			changed = true
+		}
+		if !reflect.DeepEqual(cj.Spec.JobTemplate.Spec.Template.Spec.Containers, template.Spec.Containers) {
+			cj.Spec.JobTemplate.Spec.Template.Spec.Containers = template.Spec.Containers
+			changed = true
+		}
+		if changed {
+			return r.client.Update(context.TODO(), cj)
+		}
+		return nil // CronJob found with nothing changed, move along...
+	}
+
+	cj.Spec.ConcurrencyPolicy = batchv1b1.ForbidConcurrent
+	cj.Spec.Schedule = getKnownHostsScanSchedule(cr)
+	cj.Spec.JobTemplate.Spec.Template = template
+
+	if err := controllerutil.SetControllerReference(cr, cj, r.scheme); err != nil {
+		return err
+	}
+	return r.client.Create(context.TODO(), cj)
+}
+
+// reconcileKnownHostsScanJob will ensure that the SSH known hosts are scanned once for the current hosts, so that
+// they are populated without waiting for the schedule of the CronJob. The Job is recreated when the hosts change.
+func (r *ReconcileArgoCD) reconcileKnownHostsScanJob(cr *argoprojv1a1.ArgoCD) error {
+	hosts := strings.Join(cr.Spec.KnownHostsAutoScan.Hosts, ",")
+
+	job := newKnownHostsScanJob(cr)
+	if argoutil.IsObjectFound(r.client, cr.Namespace, job.Name, job) {
+		if job.Annotations[common.ArgoCDKnownHostsScanHostsAnnotation] == hosts {
+			return nil // Job found for the current hosts, move along...
+		}
+		log.Info("rescanning ssh known hosts for updated hosts")
+		if err := r.client.Delete(context.TODO(), job, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		job = newKnownHostsScanJob(cr)
+	}
+
+	job.Annotations = map[string]string{
+		common.ArgoCDKnownHostsScanHostsAnnotation: hosts,
+	}
+	job.Spec.Template = newKnownHostsScanPodTemplateSpec(cr)
+	if err := r.reconcilePodSecurityCompliance(cr, "Job/"+job.Name, &job.Spec.Template); err != nil {
+		return err
+	}
+
+	if err := controllerutil.SetControllerReference(cr, job, r.scheme); err != nil {
+		return err
+	}
+	return r.client.Create(context.TODO(), job)
+}
+
+// reconcileScannedKnownHosts will ensure that the host keys written to the scan results ConfigMap are added to the
+// SSH known hosts ConfigMap for the hosts that have no entries yet.
+func (r *ReconcileArgoCD) reconcileScannedKnownHosts(cr *argoprojv1a1.ArgoCD) error {
+	scan := newConfigMapWithSuffix(knownHostsScanSuffix, cr)
+	if err := argoutil.FetchObject(r.client, cr.Namespace, scan.Name, scan); err != nil {
+		return err
+	}
+	scanned := scan.Data[common.ArgoCDKeySSHKnownHosts]
+	if strings.TrimSpace(scanned) == "" {
+		return nil // Nothing scanned yet, move along...
+	}
+
+	cm := newConfigMapWithName(common.ArgoCDKnownHostsConfigMapName, cr)
+	if err := argoutil.FetchObject(r.client, cr.Namespace, cm.Name, cm); err != nil {
+		return err
+	}
+
+	merged := mergeKnownHosts(cm.Data[common.ArgoCDKeySSHKnownHosts], scanned)
+	if cm.Data[common.ArgoCDKeySSHKnownHosts] == merged {
+		return nil
+	}
+
+	log.Info(fmt.Sprintf("updating ssh known hosts from scan results in configmap %s", scan.Name))
+	if cm.Data == nil {
+		cm.Data = make(map[string]string)
+	}
+	cm.Data[common.ArgoCDKeySSHKnownHosts] = merged
+	return r.client.Update(context.TODO(), cm)
+}
+
+// mergeKnownHosts will return the given SSH known hosts with the scanned entries added for the hosts that have no
+// entries yet. The existing entries are never replaced, so that a host key pinned by the user, or trusted on the first
+// scan, can not be changed by a later scan, e.g. of a spoofed host.
+func mergeKnownHosts(knownHosts, scanned string) string {
+	knownHostNames := make(map[string]bool)
+	lines := make([]string, 0)
+	for _, line := range strings.Split(knownHosts, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		lines = append(lines, line)
+		if len(fields) < 3 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		host := fields[0]
+		if strings.HasPrefix(host, "@") && len(fields) > 3 {
+			host = fields[1] // Marker, e.g. @cert-authority
+		}
+		for _, name := range strings.Split(host, ",") {
+			knownHostNames[name] = true
+		}
+	}
+
+	added := make([]string, 0)
+	for _, line := range strings.Split(scanned, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 3 || strings.HasPrefix(fields[0], "#") || knownHostNames[fields[0]] {
+			continue
+		}
+		added = append(added, strings.Join(fields, " "))
+	}
+	if len(added) == 0 {
+		return knownHosts
+	}
+	sort.Strings(added)
+	return strings.Join(append(lines, added...), "\n") + "\n"
+}
@@ -0,0 +1,132 @@
+package argocd
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"gotest.tools/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1b1 "k8s.io/api/batch/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+
+	argoprojv1alpha1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
+)
+
+func withKnownHostsAutoScan(a *argoprojv1alpha1.ArgoCD) {
+	a.Spec.KnownHostsAutoScan = argoprojv1alpha1.ArgoCDKnownHostsAutoScanSpec{
+		Hosts: []string{"github.com", "git.example.com:2222"},
+	}
+}
+
+func TestReconcileKnownHostsAutoScan_enabled(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD(withKnownHostsAutoScan)
+	r := makeTestReconciler(t, a)
+	assert.NilError(t, r.reconcileSSHKnownHosts(a))
+
+	assert.NilError(t, r.reconcileKnownHostsAutoScan(a))
+
+	cj := &batchv1b1.CronJob{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-ssh-known-hosts-scan", Namespace: a.Namespace}, cj))
+	assert.Equal(t, cj.Spec.Schedule, common.ArgoCDDefaultKnownHostsScanSchedule)
+	podSpec := cj.Spec.JobTemplate.Spec.Template.Spec
+	assert.Equal(t, podSpec.ServiceAccountName, "argocd-ssh-known-hosts-scan")
+	assert.DeepEqual(t, podSpec.Containers[0].Command, []string{
+		"uid_entrypoint.sh", "argocd-operator-util", "scan-known-hosts", "argocd-ssh-known-hosts-scan",
+		"github.com", "git.example.com:2222",
+	})
+
+	job := &batchv1.Job{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-ssh-known-hosts-scan-initial", Namespace: a.Namespace}, job))
+	assert.Equal(t, job.Annotations[common.ArgoCDKnownHostsScanHostsAnnotation], "github.com,git.example.com:2222")
+
+	// The scan writes its results, which are added to the SSH known hosts for the hosts without entries.
+	scan := &corev1.ConfigMap{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-ssh-known-hosts-scan", Namespace: a.Namespace}, scan))
+	scan.Data[common.ArgoCDKeySSHKnownHosts] = "github.com ssh-ed25519 NEWKEY\n[git.example.com]:2222 ssh-rsa GITKEY\n"
+	assert.NilError(t, r.client.Update(context.TODO(), scan))
+
+	a.Spec.KnownHostsAutoScan.Schedule = "@hourly"
+	assert.NilError(t, r.reconcileKnownHostsAutoScan(a))
+
+	cm := &corev1.ConfigMap{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: common.ArgoCDKnownHostsConfigMapName, Namespace: a.Namespace}, cm))
+	knownHosts := cm.Data[common.ArgoCDKeySSHKnownHosts]
+	assert.Assert(t, strings.Contains(knownHosts, "github.com ssh-rsa"))
+	assert.Assert(t, !strings.Contains(knownHosts, "github.com ssh-ed25519 NEWKEY"))
+	assert.Assert(t, strings.Contains(knownHosts, "[git.example.com]:2222 ssh-rsa GITKEY"))
+	assert.Assert(t, strings.Contains(knownHosts, "bitbucket.org ssh-rsa"))
+
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-ssh-known-hosts-scan", Namespace: a.Namespace}, cj))
+	assert.Equal(t, cj.Spec.Schedule, "@hourly")
+}
+
+func TestReconcileKnownHostsAutoScan_hostsChanged(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD(withKnownHostsAutoScan)
+	r := makeTestReconciler(t, a)
+	assert.NilError(t, r.reconcileSSHKnownHosts(a))
+	assert.NilError(t, r.reconcileKnownHostsAutoScan(a))
+
+	a.Spec.KnownHostsAutoScan.Hosts = []string{"gitlab.com"}
+	assert.NilError(t, r.reconcileKnownHostsAutoScan(a))
+
+	job := &batchv1.Job{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-ssh-known-hosts-scan-initial", Namespace: a.Namespace}, job))
+	assert.Equal(t, job.Annotations[common.ArgoCDKnownHostsScanHostsAnnotation], "gitlab.com")
+}
+
+func TestReconcileKnownHostsAutoScan_disabled(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD(withKnownHostsAutoScan)
+	r := makeTestReconciler(t, a)
+	assert.NilError(t, r.reconcileSSHKnownHosts(a))
+	assert.NilError(t, r.reconcileKnownHostsAutoScan(a))
+
+	a.Spec.KnownHostsAutoScan.Hosts = nil
+	assert.NilError(t, r.reconcileKnownHostsAutoScan(a))
+
+	cj := &batchv1b1.CronJob{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-ssh-known-hosts-scan", Namespace: a.Namespace}, cj)
+	assert.Assert(t, errors.IsNotFound(err))
+
+	sa := &corev1.ServiceAccount{}
+	err = r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-ssh-known-hosts-scan", Namespace: a.Namespace}, sa)
+	assert.Assert(t, errors.IsNotFound(err))
+}
+
+func TestMergeKnownHosts(t *testing.T) {
+	knownHosts := "# comment\ngithub.com,140.82.112.3 ssh-rsa OLDKEY\ngitlab.com ssh-ed25519 GITLABKEY\n"
+
+	tests := []struct {
+		name    string
+		scanned string
+		want    string
+	}{
+		{
+			name:    "keeps the existing entries of the scanned hosts",
+			scanned: "# github.com:22 SSH-2.0-babeld\ngithub.com ssh-rsa NEWKEY\ngithub.com ssh-ed25519 EDKEY\n",
+			want:    knownHosts,
+		},
+		{
+			name:    "adds the hosts without entries",
+			scanned: "github.com ssh-rsa NEWKEY\n[git.example.com]:2222 ssh-ed25519 EXAMPLEKEY\nbitbucket.org ssh-rsa BITBUCKETKEY\n",
+			want:    "# comment\ngithub.com,140.82.112.3 ssh-rsa OLDKEY\ngitlab.com ssh-ed25519 GITLABKEY\n[git.example.com]:2222 ssh-ed25519 EXAMPLEKEY\nbitbucket.org ssh-rsa BITBUCKETKEY\n",
+		},
+		{
+			name:    "nothing scanned",
+			scanned: "# unreachable.example.com:22\n",
+			want:    knownHosts,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, mergeKnownHosts(knownHosts, test.scanned), test.want)
+		})
+	}
+}
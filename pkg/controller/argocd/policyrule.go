@@ -45,6 +45,87 @@ func policyRuleForRedisHa(cr *argoprojv1alpha1.ArgoCD) []v1.PolicyRule {
 	return rules
 }
 
+func policyRuleForApplicationSetController() []v1.PolicyRule {
+
+	return []v1.PolicyRule{
+		// ApplicationSet
+		{
+			APIGroups: []string{"argoproj.io"},
+			Resources: []string{
+				"applications",
+				"applicationsets",
+				"appprojects",
+				"applicationsets/finalizers",
+			},
+			Verbs: []string{
+				"create",
+				"delete",
+				"get",
+				"list",
+				"patch",
+				"update",
+				"watch",
+			},
+		},
+		// ApplicationSet Status
+		{
+			APIGroups: []string{"argoproj.io"},
+			Resources: []string{
+				"applicationsets/status",
+			},
+			Verbs: []string{
+				"get",
+				"patch",
+				"update",
+			},
+		},
+
+		// Events
+		{
+			APIGroups: []string{""},
+			Resources: []string{
+				"events",
+			},
+			Verbs: []string{
+				"create",
+				"delete",
+				"get",
+				"list",
+				"patch",
+				"update",
+				"watch",
+			},
+		},
+
+		// Read Secrets/ConfigMaps
+		{
+			APIGroups: []string{""},
+			Resources: []string{
+				"secrets",
+				"configmaps",
+			},
+			Verbs: []string{
+				"get",
+				"list",
+				"watch",
+			},
+		},
+
+		// Read Deployments
+		{
+			APIGroups: []string{"apps", "extensions"},
+			Resources: []string{
+				"deployments",
+			},
+			Verbs: []string{
+				"get",
+				"list",
+				"watch",
+			},
+		},
+	}
+}
+
 func policyRuleForDexServer() []v1.PolicyRule {
 
 	return []v1.PolicyRule{
@@ -158,3 +239,24 @@ func policyRuleForServerClusterRole() []v1.PolicyRule {
 		},
 	}
 }
+
+// getApplicationSetRoleRules will return the policy rules of the Roles of the ApplicationSet controller, followed by
+// the additional policy rules from the ArgoCD spec.
+func getApplicationSetRoleRules(cr *argoprojv1alpha1.ArgoCD) []v1.PolicyRule {
+	return append(policyRuleForApplicationSetController(), cr.Spec.ExtraRoleRules.ApplicationSet...)
+}
+
+// getExtraRoleRules will return the additional policy rules from the ArgoCD spec for the given component.
+func getExtraRoleRules(name string, cr *argoprojv1alpha1.ArgoCD) []v1.PolicyRule {
+	switch name {
+	case applicationController:
+		return cr.Spec.ExtraRoleRules.ApplicationController
+	case dexServer:
+		return cr.Spec.ExtraRoleRules.Dex
+	case redisHa:
+		return cr.Spec.ExtraRoleRules.HA
+	case server:
+		return cr.Spec.ExtraRoleRules.Server
+	}
+	return nil
+}
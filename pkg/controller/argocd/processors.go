@@ -0,0 +1,126 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocd
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
+)
+
+const (
+	// applicationsPerStatusProcessor is the number of Applications a single status processor of the Application
+	// Controller is expected to keep up with.
+	applicationsPerStatusProcessor = 20
+
+	// applicationsPerOperationProcessor is the number of Applications a single operation processor of the
+	// Application Controller is expected to keep up with.
+	applicationsPerOperationProcessor = 40
+)
+
+// isProcessorsAutosizeEnabled will return true if the Application Controller processors of the given ArgoCD are
+// sized by the operator.
+func isProcessorsAutosizeEnabled(cr *argoprojv1a1.ArgoCD) bool {
+	return cr.Spec.Controller.ProcessorsAutosize != nil && cr.Spec.Controller.ProcessorsAutosize.Enabled
+}
+
+// getProcessorsAutosizeMaxOperation will return the upper bound of the operation processors sized by the operator.
+func getProcessorsAutosizeMaxOperation(cr *argoprojv1a1.ArgoCD) int32 {
+	if n := cr.Spec.Controller.ProcessorsAutosize.MaxOperation; n > 0 {
+		return n
+	}
+	return common.ArgoCDDefaultServerMaxOperationProcessors
+}
+
+// getProcessorsAutosizeMaxStatus will return the upper bound of the status processors sized by the operator.
+func getProcessorsAutosizeMaxStatus(cr *argoprojv1a1.ArgoCD) int32 {
+	if n := cr.Spec.Controller.ProcessorsAutosize.MaxStatus; n > 0 {
+		return n
+	}
+	return common.ArgoCDDefaultServerMaxStatusProcessors
+}
+
+// getAutosizedProcessors will return the number of processors needed for the given numbers of Applications and
+// managed clusters, within the given bounds. The current number is kept unless the needed number is at least a quarter
+// lower, so that the Application Controller is not restarted each time an Application is removed.
+func getAutosizedProcessors(current, applications, clusters, applicationsPerProcessor, lower, upper int32) int32 {
+	n := (applications + applicationsPerProcessor - 1) / applicationsPerProcessor
+	if n < clusters {
+		n = clusters
+	}
+	if n < current && n*4 > current*3 {
+		n = current
+	}
+	if n > upper {
+		n = upper
+	}
+	if n < lower {
+		n = lower
+	}
+	return n
+}
+
+// reconcileStatusControllerProcessors will ensure that the ControllerProcessors Status holds the number of Application
+// Controller processors sized for the Applications and managed clusters of the given ArgoCD, when enabled.
+func (r *ReconcileArgoCD) reconcileStatusControllerProcessors(cr *argoprojv1a1.ArgoCD) error {
+	if !isProcessorsAutosizeEnabled(cr) {
+		if cr.Status.ControllerProcessors == nil {
+			return nil
+		}
+		cr.Status.ControllerProcessors = nil
+		return r.updateArgoCDStatus(cr)
+	}
+
+	clusters, err := r.countClusterSecrets(cr)
+	if err != nil {
+		return err
+	}
+
+	current := argoprojv1a1.ArgoCDApplicationControllerProcessorsStatus{}
+	if cr.Status.ControllerProcessors != nil {
+		current = *cr.Status.ControllerProcessors
+	}
+	processors := &argoprojv1a1.ArgoCDApplicationControllerProcessorsStatus{
+		Operation: getAutosizedProcessors(current.Operation, cr.Status.ApplicationCount, clusters, applicationsPerOperationProcessor,
+			getConfiguredArgoServerOperationProcessors(cr), getProcessorsAutosizeMaxOperation(cr)),
+		Status: getAutosizedProcessors(current.Status, cr.Status.ApplicationCount, clusters, applicationsPerStatusProcessor,
+			getConfiguredArgoServerStatusProcessors(cr), getProcessorsAutosizeMaxStatus(cr)),
+	}
+	if reflect.DeepEqual(cr.Status.ControllerProcessors, processors) {
+		return nil
+	}
+
+	log.Info(fmt.Sprintf("sizing application controller processors to %d operation and %d status processors",
+		processors.Operation, processors.Status))
+	cr.Status.ControllerProcessors = processors
+	return r.updateArgoCDStatus(cr)
+}
+
+// countClusterSecrets will return the number of cluster Secrets in the namespace of the given ArgoCD.
+func (r *ReconcileArgoCD) countClusterSecrets(cr *argoprojv1a1.ArgoCD) (int32, error) {
+	secrets := &corev1.SecretList{}
+	if err := r.client.List(context.TODO(), secrets, client.InNamespace(cr.Namespace), client.MatchingLabels{
+		common.ArgoCDSecretTypeLabel: common.ArgoCDSecretTypeCluster,
+	}); err != nil {
+		return 0, fmt.Errorf("failed to list cluster secrets for %s: %w", cr.Name, err)
+	}
+	return int32(len(secrets.Items)), nil
+}
@@ -0,0 +1,75 @@
+package argocd
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+
+	argoprojv1alpha1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
+)
+
+func TestGetAutosizedProcessors(t *testing.T) {
+	tests := []struct {
+		name                                  string
+		current, applications, clusters, want int32
+	}{
+		{"lower bound", 0, 10, 1, 5},
+		{"applications", 0, 1000, 1, 50},
+		{"clusters", 0, 100, 30, 30},
+		{"upper bound", 0, 5000, 1, 100},
+		{"keep current", 50, 900, 1, 50},
+		{"scale down", 50, 700, 1, 35},
+		{"current above upper bound", 150, 2800, 1, 100},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, getAutosizedProcessors(test.current, test.applications, test.clusters, 20, 5, 100), test.want)
+		})
+	}
+}
+
+func TestReconcileArgoCD_reconcileStatusControllerProcessors(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	restoreEnv(t)
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.Controller.ProcessorsAutosize = &argoprojv1alpha1.ArgoCDApplicationControllerProcessorsAutosizeSpec{
+			Enabled:   true,
+			MaxStatus: 60,
+		}
+	})
+	a.Status.ApplicationCount = 1000
+	r := makeTestReconciler(t, a)
+
+	assert.NilError(t, r.reconcileStatusControllerProcessors(a))
+	assert.DeepEqual(t, a.Status.ControllerProcessors, &argoprojv1alpha1.ArgoCDApplicationControllerProcessorsStatus{
+		Operation: 25,
+		Status:    50,
+	})
+	assert.Equal(t, getArgoServerOperationProcessors(a), int32(25))
+	assert.Equal(t, getArgoServerStatusProcessors(a), int32(50))
+
+	// Each managed cluster gets at least one processor, within the upper bound.
+	for i := 0; i < 70; i++ {
+		assert.NilError(t, r.client.Create(context.TODO(), &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("cluster-%d", i),
+				Namespace: a.Namespace,
+				Labels:    map[string]string{common.ArgoCDSecretTypeLabel: common.ArgoCDSecretTypeCluster},
+			},
+		}))
+	}
+	assert.NilError(t, r.reconcileStatusControllerProcessors(a))
+	assert.Equal(t, a.Status.ControllerProcessors.Operation, common.ArgoCDDefaultServerMaxOperationProcessors)
+	assert.Equal(t, a.Status.ControllerProcessors.Status, int32(60))
+
+	a.Spec.Controller.ProcessorsAutosize.Enabled = false
+	assert.NilError(t, r.reconcileStatusControllerProcessors(a))
+	assert.Assert(t, a.Status.ControllerProcessors == nil)
+	assert.Equal(t, getArgoServerStatusProcessors(a), common.ArgoCDDefaultServerStatusProcessors)
+}
@@ -0,0 +1,127 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocd
+
+import (
+	"fmt"
+	"os"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
+)
+
+// sizingProfile holds the defaults applied to the Argo CD components by a named profile.
+type sizingProfile struct {
+	applicationSetResources corev1.ResourceRequirements
+	controllerResources     corev1.ResourceRequirements
+	dexResources            corev1.ResourceRequirements
+	grafanaResources        corev1.ResourceRequirements
+	haProxyResources        corev1.ResourceRequirements
+	imageUpdaterResources   corev1.ResourceRequirements
+	operationProcessors     int32
+	statusProcessors        int32
+	redisResources          corev1.ResourceRequirements
+	repoReplicas            int32
+	repoResources           corev1.ResourceRequirements
+	serverResources         corev1.ResourceRequirements
+}
+
+// sizingProfiles are the profiles that can be selected with Spec.Profile, by name.
+var sizingProfiles = map[string]sizingProfile{
+	common.ArgoCDProfileSmall: {
+		applicationSetResources: newResourceRequirements("100m", "128Mi", "500m", "256Mi"),
+		controllerResources:     newResourceRequirements("250m", "512Mi", "1", "1Gi"),
+		dexResources:            newResourceRequirements("50m", "64Mi", "250m", "128Mi"),
+		grafanaResources:        newResourceRequirements("100m", "128Mi", "250m", "256Mi"),
+		haProxyResources:        newResourceRequirements("50m", "64Mi", "250m", "128Mi"),
+		imageUpdaterResources:   newResourceRequirements("50m", "64Mi", "250m", "128Mi"),
+		operationProcessors:     10,
+		statusProcessors:        20,
+		redisResources:          newResourceRequirements("100m", "64Mi", "250m", "128Mi"),
+		repoReplicas:            1,
+		repoResources:           newResourceRequirements("100m", "256Mi", "500m", "512Mi"),
+		serverResources:         newResourceRequirements("100m", "128Mi", "500m", "256Mi"),
+	},
+	common.ArgoCDProfileMedium: {
+		applicationSetResources: newResourceRequirements("250m", "256Mi", "1", "512Mi"),
+		controllerResources:     newResourceRequirements("500m", "1Gi", "2", "2Gi"),
+		dexResources:            newResourceRequirements("100m", "128Mi", "500m", "256Mi"),
+		grafanaResources:        newResourceRequirements("250m", "256Mi", "500m", "512Mi"),
+		haProxyResources:        newResourceRequirements("100m", "128Mi", "500m", "256Mi"),
+		imageUpdaterResources:   newResourceRequirements("100m", "128Mi", "500m", "256Mi"),
+		operationProcessors:     25,
+		statusProcessors:        50,
+		redisResources:          newResourceRequirements("250m", "128Mi", "500m", "256Mi"),
+		repoReplicas:            2,
+		repoResources:           newResourceRequirements("250m", "512Mi", "1", "1Gi"),
+		serverResources:         newResourceRequirements("125m", "128Mi", "500m", "256Mi"),
+	},
+	common.ArgoCDProfileLarge: {
+		applicationSetResources: newResourceRequirements("500m", "512Mi", "2", "1Gi"),
+		controllerResources:     newResourceRequirements("1", "2Gi", "4", "4Gi"),
+		dexResources:            newResourceRequirements("250m", "256Mi", "1", "512Mi"),
+		grafanaResources:        newResourceRequirements("500m", "512Mi", "1", "1Gi"),
+		haProxyResources:        newResourceRequirements("250m", "256Mi", "1", "512Mi"),
+		imageUpdaterResources:   newResourceRequirements("250m", "256Mi", "1", "512Mi"),
+		operationProcessors:     50,
+		statusProcessors:        100,
+		redisResources:          newResourceRequirements("500m", "256Mi", "1", "512Mi"),
+		repoReplicas:            3,
+		repoResources:           newResourceRequirements("500m", "1Gi", "2", "2Gi"),
+		serverResources:         newResourceRequirements("250m", "256Mi", "1", "512Mi"),
+	},
+}
+
+// newResourceRequirements will return the ResourceRequirements with the given CPU and memory requests and limits.
+func newResourceRequirements(requestCPU, requestMemory, limitCPU, limitMemory string) corev1.ResourceRequirements {
+	return corev1.ResourceRequirements{
+		Limits: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse(limitCPU),
+			corev1.ResourceMemory: resource.MustParse(limitMemory),
+		},
+		Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse(requestCPU),
+			corev1.ResourceMemory: resource.MustParse(requestMemory),
+		},
+	}
+}
+
+// unknownSizingProfiles holds the names of the unknown sizing profiles that were already reported, so that each one is
+// only logged once rather than on every reconcile.
+var unknownSizingProfiles sync.Map
+
+// getSizingProfile will return the sizing profile selected by the given ArgoCD, falling back to the profile set in the
+// environment of the operator. It returns nil when no profile is selected.
+func getSizingProfile(cr *argoprojv1a1.ArgoCD) *sizingProfile {
+	name := cr.Spec.Profile
+	if name == "" {
+		name = os.Getenv(common.ArgoCDDefaultProfileEnvName)
+		if name == "" {
+			return nil
+		}
+	}
+	profile, ok := sizingProfiles[name]
+	if !ok {
+		if _, reported := unknownSizingProfiles.LoadOrStore(name, true); !reported {
+			log.Info(fmt.Sprintf("ignoring unknown sizing profile %q", name))
+		}
+		return nil
+	}
+	return &profile
+}
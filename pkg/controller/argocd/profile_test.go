@@ -0,0 +1,102 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocd
+
+import (
+	"os"
+	"testing"
+
+	"gotest.tools/assert"
+
+	argoprojv1alpha1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
+)
+
+func profile(name string) argoCDOpt {
+	return func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.Profile = name
+	}
+}
+
+func TestGetSizingProfile(t *testing.T) {
+	assert.Assert(t, getSizingProfile(makeTestArgoCD()) == nil)
+	assert.Assert(t, getSizingProfile(makeTestArgoCD(profile("huge"))) == nil)
+
+	p := getSizingProfile(makeTestArgoCD(profile(common.ArgoCDProfileLarge)))
+	assert.Equal(t, p.repoReplicas, int32(3))
+
+	// The profile of the operator is used when the ArgoCD does not select one.
+	os.Setenv(common.ArgoCDDefaultProfileEnvName, common.ArgoCDProfileMedium)
+	defer os.Unsetenv(common.ArgoCDDefaultProfileEnvName)
+
+	p = getSizingProfile(makeTestArgoCD())
+	assert.Equal(t, p.repoReplicas, int32(2))
+	p = getSizingProfile(makeTestArgoCD(profile(common.ArgoCDProfileSmall)))
+	assert.Equal(t, p.repoReplicas, int32(1))
+}
+
+func TestSizingProfile_defaults(t *testing.T) {
+	a := makeTestArgoCD(profile(common.ArgoCDProfileLarge), func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.ApplicationSet = &argoprojv1alpha1.ArgoCDApplicationSet{}
+	})
+
+	controller := getArgoApplicationControllerResources(a)
+	assert.Equal(t, controller.Limits.Memory().String(), "4Gi")
+	repo := getArgoRepoResources(a)
+	assert.Equal(t, repo.Limits.Cpu().String(), "2")
+	server := getArgoServerResources(a)
+	assert.Equal(t, server.Requests.Memory().String(), "256Mi")
+	redis := getRedisResources(a)
+	assert.Equal(t, redis.Requests.Cpu().String(), "500m")
+	appSet := getApplicationSetResources(a)
+	assert.Equal(t, appSet.Limits.Memory().String(), "1Gi")
+	dex := getDexResources(a)
+	assert.Equal(t, dex.Requests.Cpu().String(), "250m")
+	grafana := getGrafanaResources(a)
+	assert.Equal(t, grafana.Limits.Cpu().String(), "1")
+	haProxy := getRedisHAProxyResources(a)
+	assert.Equal(t, haProxy.Requests.Memory().String(), "256Mi")
+	imageUpdater := getImageUpdaterResources(a)
+	assert.Equal(t, imageUpdater.Limits.Memory().String(), "512Mi")
+	assert.Equal(t, *getArgoRepoReplicas(a), int32(3))
+	assert.Equal(t, getArgoServerOperationProcessors(a), int32(50))
+	assert.Equal(t, getArgoServerStatusProcessors(a), int32(100))
+}
+
+func TestSizingProfile_overrides(t *testing.T) {
+	os.Setenv(common.ArgoCDDefaultRepoResourcesEnvName, `{"limits":{"cpu":"8"}}`)
+	defer os.Unsetenv(common.ArgoCDDefaultRepoResourcesEnvName)
+
+	replicas := int32(1)
+	a := makeTestArgoCD(profile(common.ArgoCDProfileMedium), controllerProcessors(30), func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.Repo.Replicas = &replicas
+		a.Spec.Server.Resources = makeTestDexResources()
+	})
+
+	// The profile takes precedence over the defaults of the operator, and properties set on the ArgoCD take
+	// precedence over the profile.
+	repo := getArgoRepoResources(a)
+	assert.Equal(t, repo.Limits.Cpu().String(), "1")
+	assert.DeepEqual(t, getArgoServerResources(a), *makeTestDexResources())
+	assert.Equal(t, *getArgoRepoReplicas(a), int32(1))
+	assert.Equal(t, getArgoServerOperationProcessors(a), int32(25))
+	assert.Equal(t, getArgoServerStatusProcessors(a), int32(30))
+
+	// The profile does not lower the replicas required for HA.
+	a = makeTestArgoCD(profile(common.ArgoCDProfileSmall), func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.HA.Enabled = true
+	})
+	assert.Equal(t, *getArgoRepoReplicas(a), common.ArgoCDDefaultRepoServerHAReplicas)
+}
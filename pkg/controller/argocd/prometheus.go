@@ -17,11 +17,15 @@ package argocd
 import (
 	"context"
 	"fmt"
+	"reflect"
+	"sort"
+	"strings"
 
 	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
 	"github.com/argoproj-labs/argocd-operator/pkg/common"
 	"github.com/argoproj-labs/argocd-operator/pkg/controller/argoutil"
 	monitoringv1 "github.com/coreos/prometheus-operator/pkg/apis/monitoring/v1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
@@ -122,6 +126,78 @@ func newServiceMonitorWithSuffix(suffix string, cr *argoprojv1a1.ArgoCD) *monito
 	return newServiceMonitorWithName(fmt.Sprintf("%s-%s", cr.Name, suffix), cr)
 }
 
+// ensureMetricsLabels will ensure that the given metrics Service or ServiceMonitor carries the configured metrics
+// labels. The keys of the added labels are recorded in an annotation, a label that is no longer configured is reset to
+// its value in the given defaults or removed. Returns true when the object changed.
+func ensureMetricsLabels(cr *argoprojv1a1.ArgoCD, defaults map[string]string, meta *metav1.ObjectMeta) bool {
+	changed := false
+	if meta.Labels == nil {
+		meta.Labels = make(map[string]string)
+	}
+
+	for _, key := range strings.Split(meta.Annotations[common.ArgoCDMetricsLabelsAnnotation], ",") {
+		if _, ok := cr.Spec.Prometheus.Labels[key]; ok || key == "" {
+			continue
+		}
+		if value, ok := defaults[key]; ok {
+			if meta.Labels[key] != value {
+				meta.Labels[key] = value
+				changed = true
+			}
+		} else if _, ok := meta.Labels[key]; ok {
+			delete(meta.Labels, key)
+			changed = true
+		}
+	}
+
+	keys := make([]string, 0, len(cr.Spec.Prometheus.Labels))
+	for key, value := range cr.Spec.Prometheus.Labels {
+		keys = append(keys, key)
+		if meta.Labels[key] != value {
+			meta.Labels[key] = value
+			changed = true
+		}
+	}
+	sort.Strings(keys)
+
+	applied := strings.Join(keys, ",")
+	if meta.Annotations[common.ArgoCDMetricsLabelsAnnotation] != applied {
+		if applied == "" {
+			delete(meta.Annotations, common.ArgoCDMetricsLabelsAnnotation)
+		} else {
+			if meta.Annotations == nil {
+				meta.Annotations = make(map[string]string)
+			}
+			meta.Annotations[common.ArgoCDMetricsLabelsAnnotation] = applied
+		}
+		changed = true
+	}
+	return changed
+}
+
+// getMetricsServiceMonitorEndpoints will return the endpoints of the ServiceMonitor for the ArgoCD metrics Service.
+// When TLS is enabled for the metrics, the certificate of the metrics Secret is used to scrape them.
+func getMetricsServiceMonitorEndpoints(cr *argoprojv1a1.ArgoCD) []monitoringv1.Endpoint {
+	endpoint := monitoringv1.Endpoint{
+		Port: common.ArgoCDKeyMetrics,
+	}
+	if isArgoApplicationControllerMetricsTLSEnabled(cr) {
+		secret := corev1.LocalObjectReference{Name: cr.Spec.Controller.Metrics.TLS.SecretName}
+		endpoint.Scheme = "https"
+		endpoint.TLSConfig = &monitoringv1.TLSConfig{
+			CA: monitoringv1.SecretOrConfigMap{
+				Secret: &corev1.SecretKeySelector{LocalObjectReference: secret, Key: "ca.crt"},
+			},
+			Cert: monitoringv1.SecretOrConfigMap{
+				Secret: &corev1.SecretKeySelector{LocalObjectReference: secret, Key: corev1.TLSCertKey},
+			},
+			KeySecret:  &corev1.SecretKeySelector{LocalObjectReference: secret, Key: corev1.TLSPrivateKeyKey},
+			ServerName: fmt.Sprintf("%s.%s.svc", nameWithSuffix(common.ArgoCDKeyMetrics, cr), cr.Namespace),
+		}
+	}
+	return []monitoringv1.Endpoint{endpoint}
+}
+
 // reconcileMetricsServiceMonitor will ensure that the ServiceMonitor is present for the ArgoCD metrics Service.
 func (r *ReconcileArgoCD) reconcileMetricsServiceMonitor(cr *argoprojv1a1.ArgoCD) error {
 	sm := newServiceMonitorWithSuffix(common.ArgoCDKeyMetrics, cr)
@@ -130,6 +206,14 @@ func (r *ReconcileArgoCD) reconcileMetricsServiceMonitor(cr *argoprojv1a1.ArgoCD
 			// ServiceMonitor exists but enabled flag has been set to false, delete the ServiceMonitor
 			return r.client.Delete(context.TODO(), sm)
 		}
+		changed := ensureMetricsLabels(cr, newServiceMonitorWithSuffix(common.ArgoCDKeyMetrics, cr).Labels, &sm.ObjectMeta)
+		if endpoints := getMetricsServiceMonitorEndpoints(cr); !reflect.DeepEqual(sm.Spec.Endpoints, endpoints) {
+			sm.Spec.Endpoints = endpoints
+			changed = true
+		}
+		if changed {
+			return r.client.Update(context.TODO(), sm)
+		}
 		return nil // ServiceMonitor found, do nothing
 	}
 
@@ -142,11 +226,8 @@ func (r *ReconcileArgoCD) reconcileMetricsServiceMonitor(cr *argoprojv1a1.ArgoCD
 			common.ArgoCDKeyName: nameWithSuffix(common.ArgoCDKeyMetrics, cr),
 		},
 	}
-	sm.Spec.Endpoints = []monitoringv1.Endpoint{
-		{
-			Port: common.ArgoCDKeyMetrics,
-		},
-	}
+	sm.Spec.Endpoints = getMetricsServiceMonitorEndpoints(cr)
+	ensureMetricsLabels(cr, nil, &sm.ObjectMeta)
 
 	if err := controllerutil.SetControllerReference(cr, sm, r.scheme); err != nil {
 		return err
@@ -191,6 +272,9 @@ func (r *ReconcileArgoCD) reconcileRepoServerServiceMonitor(cr *argoprojv1a1.Arg
 			// ServiceMonitor exists but enabled flag has been set to false, delete the ServiceMonitor
 			return r.client.Delete(context.TODO(), sm)
 		}
+		if ensureMetricsLabels(cr, newServiceMonitorWithSuffix("repo-server-metrics", cr).Labels, &sm.ObjectMeta) {
+			return r.client.Update(context.TODO(), sm)
+		}
 		return nil // ServiceMonitor found, do nothing
 	}
 
@@ -208,6 +292,7 @@ func (r *ReconcileArgoCD) reconcileRepoServerServiceMonitor(cr *argoprojv1a1.Arg
 			Port: common.ArgoCDKeyMetrics,
 		},
 	}
+	ensureMetricsLabels(cr, nil, &sm.ObjectMeta)
 
 	if err := controllerutil.SetControllerReference(cr, sm, r.scheme); err != nil {
 		return err
@@ -223,6 +308,9 @@ func (r *ReconcileArgoCD) reconcileServerMetricsServiceMonitor(cr *argoprojv1a1.
 			// ServiceMonitor exists but enabled flag has been set to false, delete the ServiceMonitor
 			return r.client.Delete(context.TODO(), sm)
 		}
+		if ensureMetricsLabels(cr, newServiceMonitorWithSuffix("server-metrics", cr).Labels, &sm.ObjectMeta) {
+			return r.client.Update(context.TODO(), sm)
+		}
 		return nil // ServiceMonitor found, do nothing
 	}
 
@@ -240,6 +328,7 @@ func (r *ReconcileArgoCD) reconcileServerMetricsServiceMonitor(cr *argoprojv1a1.
 			Port: common.ArgoCDKeyMetrics,
 		},
 	}
+	ensureMetricsLabels(cr, nil, &sm.ObjectMeta)
 
 	if err := controllerutil.SetControllerReference(cr, sm, r.scheme); err != nil {
 		return err
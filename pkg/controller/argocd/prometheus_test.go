@@ -0,0 +1,52 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocd
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+
+	argoprojv1alpha1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
+)
+
+func TestEnsureMetricsLabels(t *testing.T) {
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.Prometheus.Labels = map[string]string{
+			common.ArgoCDKeyRelease: "kube-prometheus-stack",
+			"team":                  "platform",
+		}
+	})
+	sm := newServiceMonitorWithSuffix(common.ArgoCDKeyMetrics, a)
+	defaults := newServiceMonitorWithSuffix(common.ArgoCDKeyMetrics, a).Labels
+
+	assert.Assert(t, ensureMetricsLabels(a, defaults, &sm.ObjectMeta))
+	assert.Equal(t, sm.Labels[common.ArgoCDKeyRelease], "kube-prometheus-stack")
+	assert.Equal(t, sm.Labels["team"], "platform")
+	assert.Equal(t, sm.Annotations[common.ArgoCDMetricsLabelsAnnotation], "release,team")
+
+	// Nothing changes when the labels are present.
+	assert.Assert(t, !ensureMetricsLabels(a, defaults, &sm.ObjectMeta))
+
+	// Removed labels are reset to their default or removed.
+	a.Spec.Prometheus.Labels = nil
+	assert.Assert(t, ensureMetricsLabels(a, defaults, &sm.ObjectMeta))
+	assert.Equal(t, sm.Labels[common.ArgoCDKeyRelease], "prometheus-operator")
+	_, found := sm.Labels["team"]
+	assert.Assert(t, !found)
+	_, found = sm.Annotations[common.ArgoCDMetricsLabelsAnnotation]
+	assert.Assert(t, !found)
+}
@@ -0,0 +1,262 @@
+// Copyright 2019 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocd
+
+import (
+	"context"
+	"fmt"
+
+	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
+	"github.com/argoproj-labs/argocd-operator/pkg/controller/argoutil"
+	v1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// prunableFeature describes an optional feature of Argo CD and the namespaced resources created for it. The
+// resources are removed when the feature is disabled for the ArgoCD.
+type prunableFeature struct {
+	name    string
+	enabled func(cr *argoprojv1a1.ArgoCD) bool
+	objects func(cr *argoprojv1a1.ArgoCD) []runtime.Object
+
+	// prune removes the resources whose names cannot be told from the current spec, when set.
+	prune func(r *ReconcileArgoCD, cr *argoprojv1a1.ArgoCD) error
+}
+
+// prunableFeatures are the optional features whose resources are pruned when disabled.
+var prunableFeatures = []prunableFeature{
+	{
+		name:    "applicationset",
+		enabled: func(cr *argoprojv1a1.ArgoCD) bool { return cr.Spec.ApplicationSet != nil },
+		objects: getApplicationSetPrunableObjects,
+	},
+	{
+		name:    "grafana",
+		enabled: isManagedGrafanaEnabled,
+		objects: getGrafanaPrunableObjects,
+	},
+	{
+		name:    "grafana-dashboards",
+		enabled: func(cr *argoprojv1a1.ArgoCD) bool { return cr.Spec.Grafana.Enabled },
+		objects: func(cr *argoprojv1a1.ArgoCD) []runtime.Object {
+			return []runtime.Object{newConfigMapWithSuffix(common.ArgoCDGrafanaDashboardConfigMapSuffix, cr)}
+		},
+	},
+	{
+		name:    "grafana-external",
+		enabled: isGrafanaExternal,
+		objects: func(cr *argoprojv1a1.ArgoCD) []runtime.Object {
+			return []runtime.Object{newConfigMapWithSuffix(common.ArgoCDGrafanaDatasourceConfigMapSuffix, cr)}
+		},
+	},
+	{
+		name:    "prometheus",
+		enabled: func(cr *argoprojv1a1.ArgoCD) bool { return cr.Spec.Prometheus.Enabled },
+		objects: getPrometheusPrunableObjects,
+	},
+	{
+		// Redis HA is kept while migrating back to the single Redis server.
+		name:    "redis-ha",
+		enabled: isRedisHAInUse,
+		objects: getRedisHAPrunableObjects,
+		prune: func(r *ReconcileArgoCD, cr *argoprojv1a1.ArgoCD) error {
+			// The number of announce Services may have changed since they were created.
+			return r.deleteRedisHAAnnounceServicesFrom(cr, 0)
+		},
+	},
+	{
+		// The single Redis server is kept while migrating to Redis HA.
+		name: "redis",
+		enabled: func(cr *argoprojv1a1.ArgoCD) bool {
+			return isManagedRedisEnabled(cr) &&
+				(!cr.Spec.HA.Enabled || isArgoCDConditionTrue(cr, argoprojv1a1.ArgoCDConditionTypeRedisHAMigrating))
+		},
+		objects: getRedisPrunableObjects,
+	},
+	{
+		name:    "server-ingress",
+		enabled: func(cr *argoprojv1a1.ArgoCD) bool { return cr.Spec.Server.Ingress.Enabled },
+		objects: func(cr *argoprojv1a1.ArgoCD) []runtime.Object {
+			return []runtime.Object{newIngressWithSuffix("server", cr)}
+		},
+	},
+	{
+		name:    "server-grpc-ingress",
+		enabled: func(cr *argoprojv1a1.ArgoCD) bool { return cr.Spec.Server.GRPC.Ingress.Enabled },
+		objects: func(cr *argoprojv1a1.ArgoCD) []runtime.Object {
+			return []runtime.Object{newIngressWithSuffix("grpc", cr)}
+		},
+	},
+}
+
+// getApplicationSetPrunableObjects will return the resources created for the ApplicationSet controller.
+func getApplicationSetPrunableObjects(cr *argoprojv1a1.ArgoCD) []runtime.Object {
+	return []runtime.Object{
+		newDeploymentWithSuffix("applicationset-controller", "controller", cr),
+		newRoleBindingWithname("applicationset-controller", cr),
+		newRole("applicationset-controller", nil, cr),
+		newServiceAccountWithName("applicationset-controller", cr),
+	}
+}
+
+// getGrafanaPrunableObjects will return the resources created for the Grafana managed by the operator.
+func getGrafanaPrunableObjects(cr *argoprojv1a1.ArgoCD) []runtime.Object {
+	objs := []runtime.Object{
+		newDeploymentWithSuffix("grafana", "grafana", cr),
+		newServiceWithSuffix("grafana", "grafana", cr),
+		newIngressWithSuffix("grafana", cr),
+		newConfigMapWithSuffix(common.ArgoCDGrafanaConfigMapSuffix, cr),
+		argoutil.NewSecretWithSuffix(cr.ObjectMeta, "grafana"),
+	}
+	if IsRouteAPIAvailable() {
+		objs = append(objs, newRouteWithSuffix("grafana", cr))
+	}
+	return objs
+}
+
+// getPrometheusPrunableObjects will return the resources created for Prometheus.
+func getPrometheusPrunableObjects(cr *argoprojv1a1.ArgoCD) []runtime.Object {
+	objs := []runtime.Object{
+		newIngressWithSuffix("prometheus", cr),
+	}
+	if IsPrometheusAPIAvailable() {
+		objs = append(objs,
+			newPrometheus(cr),
+			newServiceMonitorWithSuffix(common.ArgoCDKeyMetrics, cr),
+			newServiceMonitorWithSuffix("repo-server-metrics", cr),
+			newServiceMonitorWithSuffix("server-metrics", cr),
+		)
+	}
+	if IsRouteAPIAvailable() {
+		objs = append(objs, newRouteWithSuffix("prometheus", cr))
+	}
+	return objs
+}
+
+// getRedisHAPrunableObjects will return the resources created for Redis when running in HA mode.
+func getRedisHAPrunableObjects(cr *argoprojv1a1.ArgoCD) []runtime.Object {
+	return []runtime.Object{
+		newStatefulSetWithSuffix("redis-ha-server", "redis", cr),
+		newDeploymentWithSuffix("redis-ha-haproxy", "redis", cr),
+		newServiceWithSuffix("redis-ha", "redis", cr),
+		newServiceWithSuffix("redis-ha-haproxy", "redis", cr),
+		newConfigMapWithName(common.ArgoCDRedisHAConfigMapName, cr),
+	}
+}
+
+// getRedisPrunableObjects will return the resources created for the single Redis server.
+func getRedisPrunableObjects(cr *argoprojv1a1.ArgoCD) []runtime.Object {
+	return []runtime.Object{
+		newDeploymentWithSuffix("redis", "redis", cr),
+		newServiceWithSuffix("redis", "redis", cr),
+	}
+}
+
+// pruneObjects will delete the given objects from the namespace of the given ArgoCD when they are present.
+func (r *ReconcileArgoCD) pruneObjects(cr *argoprojv1a1.ArgoCD, objs []runtime.Object) error {
+	for _, obj := range objs {
+		name := obj.(metav1.Object).GetName()
+		if !argoutil.IsObjectFound(r.client, cr.Namespace, name, obj) {
+			continue
+		}
+		log.Info(fmt.Sprintf("pruning %T %s", obj, name))
+		if err := r.client.Delete(context.TODO(), obj); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// reconcilePrunedResources will remove the resources of any optional features that are disabled for the given
+// ArgoCD.
+func (r *ReconcileArgoCD) reconcilePrunedResources(cr *argoprojv1a1.ArgoCD) error {
+	for _, feature := range prunableFeatures {
+		if feature.enabled(cr) {
+			continue
+		}
+		if err := r.pruneObjects(cr, feature.objects(cr)); err != nil {
+			return fmt.Errorf("failed to prune resources for %s: %w", feature.name, err)
+		}
+		if feature.prune == nil {
+			continue
+		}
+		if err := feature.prune(r, cr); err != nil {
+			return fmt.Errorf("failed to prune resources for %s: %w", feature.name, err)
+		}
+	}
+	return nil
+}
+
+// pruneOrphanedClusterResource will delete the given cluster-scoped resource when the ArgoCD named in its annotations
+// no longer exists, e.g. after the ArgoCD was recreated with a different name or in a different namespace. Only
+// resources labeled as managed by that ArgoCD are deleted.
+func (r *ReconcileArgoCD) pruneOrphanedClusterResource(obj runtime.Object) error {
+	meta := obj.(metav1.Object)
+	key := types.NamespacedName{
+		Name:      meta.GetAnnotations()[common.AnnotationName],
+		Namespace: meta.GetAnnotations()[common.AnnotationNamespace],
+	}
+	if key.Name == "" || key.Namespace == "" {
+		return nil // Not owned by an ArgoCD, move along...
+	}
+	if meta.GetLabels()[common.ArgoCDKeyManagedBy] != key.Name {
+		return nil // Not created by the operator, move along...
+	}
+
+	// The ArgoCD is read from the apiserver, as it may be outside of the watched namespaces or missing from the
+	// cache.
+	err := r.reader.Get(context.TODO(), key, &argoprojv1a1.ArgoCD{})
+	if err == nil || !errors.IsNotFound(err) {
+		return err
+	}
+
+	log.Info(fmt.Sprintf("pruning %T %s of deleted ArgoCD %s", obj, meta.GetName(), key))
+	if err := r.client.Delete(context.TODO(), obj); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// reconcileOrphanedClusterResources will remove the ClusterRoles and ClusterRoleBindings created by the operator for
+// any ArgoCD that no longer exists.
+func (r *ReconcileArgoCD) reconcileOrphanedClusterResources() error {
+	selector := client.MatchingLabels{common.ArgoCDKeyPartOf: common.ArgoCDAppName}
+
+	clusterRoles := &v1.ClusterRoleList{}
+	if err := r.client.List(context.TODO(), clusterRoles, selector); err != nil {
+		return fmt.Errorf("failed to list ClusterRoles: %w", err)
+	}
+	for i := range clusterRoles.Items {
+		if err := r.pruneOrphanedClusterResource(&clusterRoles.Items[i]); err != nil {
+			return err
+		}
+	}
+
+	clusterRoleBindings := &v1.ClusterRoleBindingList{}
+	if err := r.client.List(context.TODO(), clusterRoleBindings, selector); err != nil {
+		return fmt.Errorf("failed to list ClusterRoleBindings: %w", err)
+	}
+	for i := range clusterRoleBindings.Items {
+		if err := r.pruneOrphanedClusterResource(&clusterRoleBindings.Items[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
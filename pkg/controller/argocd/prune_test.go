@@ -0,0 +1,184 @@
+// Copyright 2019 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocd
+
+import (
+	"context"
+	"testing"
+
+	"gotest.tools/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+
+	argoprojv1alpha1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
+	"github.com/argoproj-labs/argocd-operator/pkg/controller/argoutil"
+)
+
+func TestReconcileArgoCD_reconcilePrunedResources_grafana(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.Grafana.Enabled = true
+	})
+	r := makeTestReconciler(t, a)
+
+	for _, obj := range getGrafanaPrunableObjects(a) {
+		assert.NilError(t, r.client.Create(context.TODO(), obj))
+	}
+	assert.NilError(t, r.client.Create(context.TODO(), newConfigMapWithSuffix(common.ArgoCDGrafanaDashboardConfigMapSuffix, a)))
+
+	assert.NilError(t, r.reconcilePrunedResources(a))
+	assert.Assert(t, argoutil.IsObjectFound(r.client, testNamespace, "argocd-grafana", &corev1.Secret{}))
+	assert.Assert(t, argoutil.IsObjectFound(r.client, testNamespace, "argocd-"+common.ArgoCDGrafanaConfigMapSuffix, &corev1.ConfigMap{}))
+
+	// The dashboards are kept for an external Grafana.
+	a.Spec.Grafana.External = &argoprojv1alpha1.ArgoCDGrafanaExternalSpec{URL: "https://grafana.example.com"}
+	assert.NilError(t, r.reconcilePrunedResources(a))
+	assert.Assert(t, !argoutil.IsObjectFound(r.client, testNamespace, "argocd-grafana", &appsv1.Deployment{}))
+	assert.Assert(t, argoutil.IsObjectFound(r.client, testNamespace, "argocd-"+common.ArgoCDGrafanaDashboardConfigMapSuffix, &corev1.ConfigMap{}))
+
+	a.Spec.Grafana.Enabled = false
+	assert.NilError(t, r.reconcilePrunedResources(a))
+	assert.Assert(t, !argoutil.IsObjectFound(r.client, testNamespace, "argocd-grafana", &appsv1.Deployment{}))
+	assert.Assert(t, !argoutil.IsObjectFound(r.client, testNamespace, "argocd-grafana", &corev1.Service{}))
+	assert.Assert(t, !argoutil.IsObjectFound(r.client, testNamespace, "argocd-grafana", &corev1.Secret{}))
+	assert.Assert(t, !argoutil.IsObjectFound(r.client, testNamespace, "argocd-"+common.ArgoCDGrafanaConfigMapSuffix, &corev1.ConfigMap{}))
+	assert.Assert(t, !argoutil.IsObjectFound(r.client, testNamespace, "argocd-"+common.ArgoCDGrafanaDashboardConfigMapSuffix, &corev1.ConfigMap{}))
+}
+
+func TestReconcileArgoCD_reconcilePrunedResources_redisHA(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.HA.Enabled = true
+	})
+	r := makeTestReconciler(t, a)
+
+	for _, obj := range append(getRedisHAPrunableObjects(a), getRedisPrunableObjects(a)...) {
+		assert.NilError(t, r.client.Create(context.TODO(), obj))
+	}
+	a.Spec.HA.RedisConfig.AnnounceServices = 4
+	assert.NilError(t, r.reconcileRedisHAAnnounceServices(a))
+
+	// The single Redis server is kept while migrating to Redis HA.
+	setArgoCDCondition(a, argoprojv1alpha1.ArgoCDCondition{
+		Type:   argoprojv1alpha1.ArgoCDConditionTypeRedisHAMigrating,
+		Status: corev1.ConditionTrue,
+		Reason: redisHAMigrationRepointing,
+	})
+	assert.NilError(t, r.reconcilePrunedResources(a))
+	assert.Assert(t, argoutil.IsObjectFound(r.client, testNamespace, "argocd-redis", &appsv1.Deployment{}))
+	assert.Assert(t, argoutil.IsObjectFound(r.client, testNamespace, "argocd-redis", &corev1.Service{}))
+	assert.Assert(t, argoutil.IsObjectFound(r.client, testNamespace, "argocd-redis-ha-haproxy", &corev1.Service{}))
+
+	setArgoCDCondition(a, argoprojv1alpha1.ArgoCDCondition{
+		Type:   argoprojv1alpha1.ArgoCDConditionTypeRedisHAMigrating,
+		Status: corev1.ConditionFalse,
+		Reason: redisHAMigrationCompleted,
+	})
+	assert.NilError(t, r.reconcilePrunedResources(a))
+	assert.Assert(t, !argoutil.IsObjectFound(r.client, testNamespace, "argocd-redis", &corev1.Service{}))
+	assert.Assert(t, argoutil.IsObjectFound(r.client, testNamespace, "argocd-redis-ha-haproxy", &corev1.Service{}))
+
+	// All announce Services are removed, also when their number has changed since they were created.
+	a.Spec.HA.Enabled = false
+	a.Spec.HA.RedisConfig.AnnounceServices = 0
+	assert.NilError(t, r.reconcilePrunedResources(a))
+	assert.Assert(t, !argoutil.IsObjectFound(r.client, testNamespace, "argocd-redis-ha-server", &appsv1.StatefulSet{}))
+	assert.Assert(t, !argoutil.IsObjectFound(r.client, testNamespace, "argocd-redis-ha-haproxy", &appsv1.Deployment{}))
+	assert.Assert(t, !argoutil.IsObjectFound(r.client, testNamespace, "argocd-redis-ha-announce-0", &corev1.Service{}))
+	assert.Assert(t, !argoutil.IsObjectFound(r.client, testNamespace, "argocd-redis-ha-announce-3", &corev1.Service{}))
+	assert.Assert(t, !argoutil.IsObjectFound(r.client, testNamespace, common.ArgoCDRedisHAConfigMapName, &corev1.ConfigMap{}))
+}
+
+func TestReconcileArgoCD_reconcilePrunedResources_remoteRedis(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD()
+	r := makeTestReconciler(t, a)
+
+	assert.NilError(t, r.reconcileRedisDeployment(a))
+	assert.NilError(t, r.reconcileRedisService(a))
+	assert.Assert(t, argoutil.IsObjectFound(r.client, testNamespace, "argocd-redis", &appsv1.Deployment{}))
+	assert.Assert(t, argoutil.IsObjectFound(r.client, testNamespace, "argocd-redis", &corev1.Service{}))
+
+	a.Spec.Redis.Enabled = boolPtr(false)
+	a.Spec.Redis.Remote = "redis.example.com:6379"
+	assert.Equal(t, getRedisServerAddress(a), "redis.example.com:6379")
+	assert.NilError(t, r.reconcilePrunedResources(a))
+	assert.Assert(t, !argoutil.IsObjectFound(r.client, testNamespace, "argocd-redis", &appsv1.Deployment{}))
+	assert.Assert(t, !argoutil.IsObjectFound(r.client, testNamespace, "argocd-redis", &corev1.Service{}))
+
+	// The managed Redis is not recreated.
+	assert.NilError(t, r.reconcileRedisDeployment(a))
+	assert.NilError(t, r.reconcileRedisService(a))
+	assert.Assert(t, !argoutil.IsObjectFound(r.client, testNamespace, "argocd-redis", &appsv1.Deployment{}))
+	assert.Assert(t, !argoutil.IsObjectFound(r.client, testNamespace, "argocd-redis", &corev1.Service{}))
+}
+
+func TestReconcileArgoCD_reconcileOrphanedClusterResources(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD()
+	orphan := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Name = "renamed"
+	})
+	unmanaged := &rbacv1.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "unmanaged",
+			Labels: map[string]string{common.ArgoCDKeyPartOf: common.ArgoCDAppName},
+		},
+	}
+	r := makeTestReconciler(t, a,
+		newClusterRole(applicationController, nil, a),
+		newClusterRoleBindingWithname(applicationController, a),
+		newClusterRole(applicationController, nil, orphan),
+		newClusterRoleBindingWithname(applicationController, orphan),
+		unmanaged)
+
+	assert.NilError(t, r.reconcileOrphanedClusterResources())
+
+	name := GenerateUniqueResourceName(applicationController, a)
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: name}, &rbacv1.ClusterRole{}))
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: name}, &rbacv1.ClusterRoleBinding{}))
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: unmanaged.Name}, &rbacv1.ClusterRole{}))
+
+	orphanName := GenerateUniqueResourceName(applicationController, orphan)
+	assert.ErrorContains(t, r.client.Get(context.TODO(), types.NamespacedName{Name: orphanName}, &rbacv1.ClusterRole{}), "not found")
+	assert.ErrorContains(t, r.client.Get(context.TODO(), types.NamespacedName{Name: orphanName}, &rbacv1.ClusterRoleBinding{}), "not found")
+}
+
+func TestReconcileArgoCD_reconcileOrphanedClusterResources_notPruned(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD()
+	unwatched := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Namespace = "unwatched"
+	})
+	deleted := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Name = "deleted"
+	})
+	unlabeled := newClusterRole(applicationController, nil, deleted)
+	delete(unlabeled.Labels, common.ArgoCDKeyManagedBy)
+	r := makeTestReconciler(t, a, newClusterRole(applicationController, nil, unwatched), unlabeled)
+	// The ArgoCD outside of the watched namespaces is only found by reading from the apiserver.
+	r.reader = makeTestReconciler(t, a, unwatched).client
+
+	assert.NilError(t, r.reconcileOrphanedClusterResources())
+
+	name := GenerateUniqueResourceName(applicationController, unwatched)
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: name}, &rbacv1.ClusterRole{}))
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: unlabeled.Name}, &rbacv1.ClusterRole{}))
+}
@@ -0,0 +1,77 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocd
+
+import (
+	"fmt"
+	"time"
+
+	"golang.org/x/time/rate"
+	"k8s.io/client-go/util/workqueue"
+)
+
+// RateLimiterOptions configures how quickly the requests of the ArgoCD controller are retried after a failed
+// reconciliation.
+type RateLimiterOptions struct {
+	// BaseDelay is the delay before the first retry of a request, which doubles with each consecutive failure.
+	BaseDelay time.Duration
+
+	// MaxDelay is the maximum delay between the retries of a request.
+	MaxDelay time.Duration
+
+	// QPS is the overall number of retries per second across all requests.
+	QPS float64
+
+	// Burst is the overall number of retries that may exceed the QPS at once.
+	Burst int
+}
+
+// DefaultRateLimiterOptions returns the options of the default controller-runtime rate limiter.
+func DefaultRateLimiterOptions() RateLimiterOptions {
+	return RateLimiterOptions{
+		BaseDelay: 5 * time.Millisecond,
+		MaxDelay:  1000 * time.Second,
+		QPS:       10,
+		Burst:     100,
+	}
+}
+
+// rateLimiterOptions are the options used for the rate limiter of the ArgoCD controller.
+var rateLimiterOptions = DefaultRateLimiterOptions()
+
+// SetRateLimiterOptions sets the options for the rate limiter of the ArgoCD controller, it must be called before the
+// controller is added to the manager.
+func SetRateLimiterOptions(opts RateLimiterOptions) error {
+	if opts.BaseDelay <= 0 {
+		return fmt.Errorf("the base delay must be positive, got %s", opts.BaseDelay)
+	}
+	if opts.MaxDelay < opts.BaseDelay {
+		return fmt.Errorf("the max delay (%s) must not be less than the base delay (%s)", opts.MaxDelay, opts.BaseDelay)
+	}
+	if opts.QPS <= 0 || opts.Burst <= 0 {
+		return fmt.Errorf("the qps and burst must be positive, got %v and %d", opts.QPS, opts.Burst)
+	}
+	rateLimiterOptions = opts
+	return nil
+}
+
+// newRateLimiter will return a rate limiter for the given options, which delays a request by the larger of its
+// exponential backoff and the overall token bucket.
+func newRateLimiter(opts RateLimiterOptions) workqueue.RateLimiter {
+	return workqueue.NewMaxOfRateLimiter(
+		workqueue.NewItemExponentialFailureRateLimiter(opts.BaseDelay, opts.MaxDelay),
+		&workqueue.BucketRateLimiter{Limiter: rate.NewLimiter(rate.Limit(opts.QPS), opts.Burst)},
+	)
+}
@@ -0,0 +1,63 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocd
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+)
+
+func TestNewRateLimiter(t *testing.T) {
+	rl := newRateLimiter(RateLimiterOptions{
+		BaseDelay: time.Second,
+		MaxDelay:  4 * time.Second,
+		QPS:       100,
+		Burst:     100,
+	})
+
+	// The delay of a request doubles with each failure up to the max delay.
+	assert.Equal(t, rl.When("argocd/argocd"), time.Second)
+	assert.Equal(t, rl.When("argocd/argocd"), 2*time.Second)
+	assert.Equal(t, rl.When("argocd/argocd"), 4*time.Second)
+	assert.Equal(t, rl.When("argocd/argocd"), 4*time.Second)
+	assert.Equal(t, rl.When("other/argocd"), time.Second)
+
+	rl.Forget("argocd/argocd")
+	assert.Equal(t, rl.When("argocd/argocd"), time.Second)
+}
+
+func TestSetRateLimiterOptions(t *testing.T) {
+	defer func() { rateLimiterOptions = DefaultRateLimiterOptions() }()
+
+	opts := DefaultRateLimiterOptions()
+	opts.MaxDelay = 5 * time.Minute
+	assert.NilError(t, SetRateLimiterOptions(opts))
+	assert.Equal(t, rateLimiterOptions, opts)
+
+	invalid := []func(*RateLimiterOptions){
+		func(o *RateLimiterOptions) { o.BaseDelay = 0 },
+		func(o *RateLimiterOptions) { o.MaxDelay = o.BaseDelay / 2 },
+		func(o *RateLimiterOptions) { o.QPS = 0 },
+		func(o *RateLimiterOptions) { o.Burst = -1 },
+	}
+	for _, f := range invalid {
+		o := DefaultRateLimiterOptions()
+		f(&o)
+		assert.Assert(t, SetRateLimiterOptions(o) != nil)
+	}
+	assert.Equal(t, rateLimiterOptions, opts)
+}
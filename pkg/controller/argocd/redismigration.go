@@ -0,0 +1,249 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocd
+
+import (
+	"context"
+	"fmt"
+
+	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/pkg/controller/argoutil"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+)
+
+const (
+	// redisHAMigrationWaitingForQuorum is the reason used while the Redis HA servers are being brought up.
+	redisHAMigrationWaitingForQuorum = "WaitingForSentinelQuorum"
+
+	// redisHAMigrationRepointing is the reason used while the Argo CD components are switched over to Redis HA.
+	redisHAMigrationRepointing = "RepointingComponents"
+
+	// redisHAMigrationCancelled is the reason used when Redis HA is disabled during a migration.
+	redisHAMigrationCancelled = "MigrationCancelled"
+
+	// redisHAMigrationCompleted is the reason used once the single Redis server, or Redis HA, has been removed.
+	redisHAMigrationCompleted = "MigrationCompleted"
+
+	// redisHAMigrationWaitingForRedis is the reason used while the single Redis server is being brought up, after
+	// Redis HA has been disabled.
+	redisHAMigrationWaitingForRedis = "WaitingForRedis"
+
+	// redisHAMigrationRepointingToRedis is the reason used while the Argo CD components are switched back to the
+	// single Redis server.
+	redisHAMigrationRepointingToRedis = "RepointingComponentsToRedis"
+)
+
+// reconcileRedisHAMigration will orchestrate the migration from a single Redis server to Redis HA for the given
+// ArgoCD. The single Redis server is kept, and used by the Argo CD components, until the Redis HA servers have
+// reached a sentinel quorum. The components are then pointed at Redis HA, and the single Redis server is removed once
+// all of them have rolled out. Progress is reported with the RedisHAMigrating status condition.
+func (r *ReconcileArgoCD) reconcileRedisHAMigration(cr *argoprojv1a1.ArgoCD) error {
+	if !cr.Spec.HA.Enabled {
+		return r.reconcileRedisHAMigrationToRedis(cr)
+	}
+
+	redis := newDeploymentWithSuffix("redis", "redis", cr)
+	if !argoutil.IsObjectFound(r.client, cr.Namespace, redis.Name, redis) {
+		return nil // No single Redis server to migrate from
+	}
+
+	condition := argoprojv1a1.ArgoCDCondition{
+		Type:   argoprojv1a1.ArgoCDConditionTypeRedisHAMigrating,
+		Status: corev1.ConditionTrue,
+		Reason: redisHAMigrationWaitingForQuorum,
+	}
+
+	ready, err := r.getRedisHAReadyReplicas(cr)
+	if err != nil {
+		return err
+	}
+	if ready < getRedisHAQuorum(cr) || !r.isRedisHAProxyAvailable(cr) {
+		condition.Message = fmt.Sprintf("%d of %d Redis HA servers are ready, waiting for a sentinel quorum of %d and the Redis HA proxy",
+			ready, *getRedisHAReplicas(cr), getRedisHAQuorum(cr))
+		return r.updateRedisHAMigrationCondition(cr, condition)
+	}
+
+	condition.Reason = redisHAMigrationRepointing
+	condition.Message = "waiting for the Argo CD components to roll out using Redis HA"
+	if err := r.updateRedisHAMigrationCondition(cr, condition); err != nil {
+		return err
+	}
+
+	repointed, err := r.areRedisClientsUsing(cr, getRedisHAProxyAddress(cr))
+	if err != nil || !repointed {
+		return err
+	}
+
+	log.Info("redis ha migration completed, removing the single redis server")
+	if err := r.client.Delete(context.TODO(), redis); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+
+	condition.Status = corev1.ConditionFalse
+	condition.Reason = redisHAMigrationCompleted
+	condition.Message = "the Argo CD components are using Redis HA and the single Redis server has been removed"
+	return r.updateRedisHAMigrationCondition(cr, condition)
+}
+
+// reconcileRedisHAMigrationToRedis will orchestrate the migration from Redis HA back to a single Redis server for the
+// given ArgoCD, once Redis HA has been disabled. Redis HA is kept, and used by the Argo CD components, until the single
+// Redis server is available. The components are then pointed at the single Redis server, and Redis HA is removed once
+// all of them have rolled out.
+func (r *ReconcileArgoCD) reconcileRedisHAMigrationToRedis(cr *argoprojv1a1.ArgoCD) error {
+	ss := newStatefulSetWithSuffix("redis-ha-server", "redis", cr)
+	if !isManagedRedisEnabled(cr) || !argoutil.IsObjectFound(r.client, cr.Namespace, ss.Name, ss) {
+		if !isArgoCDConditionTrue(cr, argoprojv1a1.ArgoCDConditionTypeRedisHAMigrating) {
+			return nil // No Redis HA to migrate from
+		}
+		return r.updateRedisHAMigrationCondition(cr, argoprojv1a1.ArgoCDCondition{
+			Type:    argoprojv1a1.ArgoCDConditionTypeRedisHAMigrating,
+			Status:  corev1.ConditionFalse,
+			Reason:  redisHAMigrationCancelled,
+			Message: "Redis HA has been disabled",
+		})
+	}
+
+	condition := argoprojv1a1.ArgoCDCondition{
+		Type:    argoprojv1a1.ArgoCDConditionTypeRedisHAMigrating,
+		Status:  corev1.ConditionTrue,
+		Reason:  redisHAMigrationWaitingForRedis,
+		Message: "Redis HA has been disabled, waiting for the single Redis server to become available",
+	}
+
+	redis := newDeploymentWithSuffix("redis", "redis", cr)
+	if !argoutil.IsObjectFound(r.client, cr.Namespace, redis.Name, redis) || redis.Status.AvailableReplicas == 0 {
+		return r.updateRedisHAMigrationCondition(cr, condition)
+	}
+
+	condition.Reason = redisHAMigrationRepointingToRedis
+	condition.Message = "waiting for the Argo CD components to roll out using the single Redis server"
+	if err := r.updateRedisHAMigrationCondition(cr, condition); err != nil {
+		return err
+	}
+
+	repointed, err := r.areRedisClientsUsing(cr, getRedisServerAddress(cr))
+	if err != nil || !repointed {
+		return err
+	}
+
+	// Redis HA is removed by the reconciliation of its resources, once the migration is no longer in progress.
+	log.Info("redis ha migration completed, removing redis ha")
+	condition.Status = corev1.ConditionFalse
+	condition.Reason = redisHAMigrationCompleted
+	condition.Message = "the Argo CD components are using the single Redis server and Redis HA has been removed"
+	return r.updateRedisHAMigrationCondition(cr, condition)
+}
+
+// updateRedisHAMigrationCondition will set the given RedisHAMigrating condition on the given ArgoCD, updating the
+// Status if it changed.
+func (r *ReconcileArgoCD) updateRedisHAMigrationCondition(cr *argoprojv1a1.ArgoCD, condition argoprojv1a1.ArgoCDCondition) error {
+	if setArgoCDCondition(cr, condition) {
+		return r.updateArgoCDStatus(cr)
+	}
+	return nil
+}
+
+// getRedisHAReadyReplicas will return the number of ready Redis HA servers for the given ArgoCD.
+func (r *ReconcileArgoCD) getRedisHAReadyReplicas(cr *argoprojv1a1.ArgoCD) (int32, error) {
+	ss := newStatefulSetWithSuffix("redis-ha-server", "redis", cr)
+	if err := argoutil.FetchObject(r.client, cr.Namespace, ss.Name, ss); err != nil {
+		if errors.IsNotFound(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return ss.Status.ReadyReplicas, nil
+}
+
+// isRedisHAProxyAvailable will return true if the Redis HA proxy for the given ArgoCD has an available replica.
+func (r *ReconcileArgoCD) isRedisHAProxyAvailable(cr *argoprojv1a1.ArgoCD) bool {
+	deploy := newDeploymentWithSuffix("redis-ha-haproxy", "redis", cr)
+	return argoutil.IsObjectFound(r.client, cr.Namespace, deploy.Name, deploy) && deploy.Status.AvailableReplicas > 0
+}
+
+// areRedisClientsUsing will return true once the Repo Server, Server and Application Controller of the given ArgoCD
+// have been configured to use the Redis server with the given address and have completed their rollouts.
+func (r *ReconcileArgoCD) areRedisClientsUsing(cr *argoprojv1a1.ArgoCD, address string) (bool, error) {
+	for _, suffix := range []string{"repo-server", "server"} {
+		deploy := newDeploymentWithSuffix(suffix, suffix, cr)
+		if err := argoutil.FetchObject(r.client, cr.Namespace, deploy.Name, deploy); err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return false, err
+		}
+		if !containsString(deploy.Spec.Template.Spec.Containers[0].Command, address) || !isDeploymentRolledOut(deploy) {
+			return false, nil
+		}
+	}
+
+	ss := newStatefulSetWithSuffix("application-controller", "application-controller", cr)
+	if err := argoutil.FetchObject(r.client, cr.Namespace, ss.Name, ss); err != nil {
+		if errors.IsNotFound(err) {
+			return true, nil
+		}
+		return false, err
+	}
+	return containsString(ss.Spec.Template.Spec.Containers[0].Command, address) && isStatefulSetRolledOut(ss), nil
+}
+
+// isDeploymentRolledOut will return true if all replicas of the given Deployment run the latest template.
+func isDeploymentRolledOut(deploy *appsv1.Deployment) bool {
+	replicas := int32(1)
+	if deploy.Spec.Replicas != nil {
+		replicas = *deploy.Spec.Replicas
+	}
+	return deploy.Status.ObservedGeneration >= deploy.Generation &&
+		deploy.Status.UpdatedReplicas == replicas &&
+		deploy.Status.AvailableReplicas == replicas &&
+		deploy.Status.Replicas == replicas
+}
+
+// isStatefulSetRolledOut will return true if all replicas of the given StatefulSet run the latest template.
+func isStatefulSetRolledOut(ss *appsv1.StatefulSet) bool {
+	replicas := int32(1)
+	if ss.Spec.Replicas != nil {
+		replicas = *ss.Spec.Replicas
+	}
+	return ss.Status.ObservedGeneration >= ss.Generation &&
+		ss.Status.UpdatedReplicas == replicas &&
+		ss.Status.ReadyReplicas == replicas
+}
+
+// getRedisHAMigrationReason will return the reason of the RedisHAMigrating condition of the given ArgoCD while a
+// migration is in progress, or an empty string otherwise.
+func getRedisHAMigrationReason(cr *argoprojv1a1.ArgoCD) string {
+	for _, c := range cr.Status.Conditions {
+		if c.Type == argoprojv1a1.ArgoCDConditionTypeRedisHAMigrating && c.Status == corev1.ConditionTrue {
+			return c.Reason
+		}
+	}
+	return ""
+}
+
+// isWaitingForRedisHA will return true while a migration to Redis HA is waiting for the Redis HA servers of the given
+// ArgoCD, during which the Argo CD components keep using the single Redis server.
+func isWaitingForRedisHA(cr *argoprojv1a1.ArgoCD) bool {
+	return getRedisHAMigrationReason(cr) == redisHAMigrationWaitingForQuorum
+}
+
+// isRedisHAInUse will return true if the Redis HA resources of the given ArgoCD must be kept, either because HA is
+// enabled or because a migration back to the single Redis server is in progress.
+func isRedisHAInUse(cr *argoprojv1a1.ArgoCD) bool {
+	reason := getRedisHAMigrationReason(cr)
+	return cr.Spec.HA.Enabled || reason == redisHAMigrationWaitingForRedis || reason == redisHAMigrationRepointingToRedis
+}
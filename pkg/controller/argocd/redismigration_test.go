@@ -0,0 +1,162 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocd
+
+import (
+	"context"
+	"testing"
+
+	"gotest.tools/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+
+	argoprojv1alpha1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/pkg/controller/argoutil"
+)
+
+func assertRedisHAMigrationCondition(t *testing.T, cr *argoprojv1alpha1.ArgoCD, status corev1.ConditionStatus, reason string) {
+	t.Helper()
+	for _, c := range cr.Status.Conditions {
+		if c.Type == argoprojv1alpha1.ArgoCDConditionTypeRedisHAMigrating {
+			assert.Equal(t, c.Status, status)
+			assert.Equal(t, c.Reason, reason)
+			return
+		}
+	}
+	t.Fatalf("condition %s not found", argoprojv1alpha1.ArgoCDConditionTypeRedisHAMigrating)
+}
+
+func TestReconcileArgoCD_reconcileRedisHAMigration(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD()
+	r := makeTestReconciler(t, a)
+	assert.NilError(t, r.reconcileRedisDeployment(a))
+	assert.NilError(t, r.reconcileRepoDeployment(a))
+	assert.NilError(t, r.reconcileApplicationControllerStatefulSet(a))
+
+	// Enabling HA keeps the single Redis server until the Redis HA servers have a quorum.
+	a.Spec.HA.Enabled = true
+	assert.NilError(t, r.reconcileRedisHAMigration(a))
+	assertRedisHAMigrationCondition(t, a, corev1.ConditionTrue, redisHAMigrationWaitingForQuorum)
+	assert.Equal(t, getRedisServerAddress(a), "argocd-redis.argocd.svc.cluster.local:6379")
+
+	assert.NilError(t, r.reconcileRedisDeployment(a))
+	assert.Assert(t, argoutil.IsObjectFound(r.client, testNamespace, "argocd-redis", &appsv1.Deployment{}))
+
+	ss := newStatefulSetWithSuffix("redis-ha-server", "redis", a)
+	ss.Status.ReadyReplicas = 2
+	assert.NilError(t, r.client.Create(context.TODO(), ss))
+	haproxy := newDeploymentWithSuffix("redis-ha-haproxy", "redis", a)
+	haproxy.Status.AvailableReplicas = 1
+	assert.NilError(t, r.client.Create(context.TODO(), haproxy))
+
+	// Once the quorum is reached, the components are pointed at Redis HA.
+	assert.NilError(t, r.reconcileRedisHAMigration(a))
+	assertRedisHAMigrationCondition(t, a, corev1.ConditionTrue, redisHAMigrationRepointing)
+	assert.Equal(t, getRedisServerAddress(a), getRedisHAProxyAddress(a))
+	assert.Assert(t, argoutil.IsObjectFound(r.client, testNamespace, "argocd-redis", &appsv1.Deployment{}))
+
+	assert.NilError(t, r.reconcileRepoDeployment(a))
+	assert.NilError(t, r.reconcileApplicationControllerStatefulSet(a))
+
+	// The single Redis server is kept until the components have rolled out.
+	assert.NilError(t, r.reconcileRedisHAMigration(a))
+	assert.Assert(t, argoutil.IsObjectFound(r.client, testNamespace, "argocd-redis", &appsv1.Deployment{}))
+
+	repo := &appsv1.Deployment{}
+	assert.NilError(t, argoutil.FetchObject(r.client, testNamespace, "argocd-repo-server", repo))
+	repo.Status.Replicas = *repo.Spec.Replicas
+	repo.Status.UpdatedReplicas = *repo.Spec.Replicas
+	repo.Status.AvailableReplicas = *repo.Spec.Replicas
+	assert.NilError(t, r.client.Status().Update(context.TODO(), repo))
+
+	controller := &appsv1.StatefulSet{}
+	assert.NilError(t, argoutil.FetchObject(r.client, testNamespace, "argocd-application-controller", controller))
+	controller.Status.UpdatedReplicas = 1
+	controller.Status.ReadyReplicas = 1
+	assert.NilError(t, r.client.Status().Update(context.TODO(), controller))
+
+	assert.NilError(t, r.reconcileRedisHAMigration(a))
+	assertRedisHAMigrationCondition(t, a, corev1.ConditionFalse, redisHAMigrationCompleted)
+	assert.Assert(t, !argoutil.IsObjectFound(r.client, testNamespace, "argocd-redis", &appsv1.Deployment{}))
+}
+
+func TestReconcileArgoCD_reconcileRedisHAMigration_cancelled(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD()
+	r := makeTestReconciler(t, a)
+	assert.NilError(t, r.reconcileRedisDeployment(a))
+
+	a.Spec.HA.Enabled = true
+	assert.NilError(t, r.reconcileRedisHAMigration(a))
+	assertRedisHAMigrationCondition(t, a, corev1.ConditionTrue, redisHAMigrationWaitingForQuorum)
+
+	a.Spec.HA.Enabled = false
+	assert.NilError(t, r.reconcileRedisHAMigration(a))
+	assertRedisHAMigrationCondition(t, a, corev1.ConditionFalse, redisHAMigrationCancelled)
+	assert.Assert(t, argoutil.IsObjectFound(r.client, testNamespace, "argocd-redis", &appsv1.Deployment{}))
+}
+
+func TestReconcileArgoCD_reconcileRedisHAMigration_toRedis(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.HA.Enabled = true
+	})
+	r := makeTestReconciler(t, a)
+	assert.NilError(t, r.reconcileRedisStatefulSet(a))
+	assert.NilError(t, r.reconcileRedisHAProxyDeployment(a))
+	assert.NilError(t, r.reconcileRepoDeployment(a))
+
+	// Disabling HA keeps Redis HA until the single Redis server is available.
+	a.Spec.HA.Enabled = false
+	assert.NilError(t, r.reconcileRedisHAMigration(a))
+	assertRedisHAMigrationCondition(t, a, corev1.ConditionTrue, redisHAMigrationWaitingForRedis)
+	assert.Equal(t, getRedisServerAddress(a), getRedisHAProxyAddress(a))
+
+	assert.NilError(t, r.reconcileRedisStatefulSet(a))
+	assert.NilError(t, r.reconcileRedisHAProxyDeployment(a))
+	assert.NilError(t, r.reconcileRedisDeployment(a))
+	assert.Assert(t, argoutil.IsObjectFound(r.client, testNamespace, "argocd-redis-ha-server", &appsv1.StatefulSet{}))
+	assert.Assert(t, argoutil.IsObjectFound(r.client, testNamespace, "argocd-redis-ha-haproxy", &appsv1.Deployment{}))
+
+	redis := &appsv1.Deployment{}
+	assert.NilError(t, argoutil.FetchObject(r.client, testNamespace, "argocd-redis", redis))
+	redis.Status.AvailableReplicas = 1
+	assert.NilError(t, r.client.Status().Update(context.TODO(), redis))
+
+	// Once the single Redis server is available, the components are pointed back at it.
+	assert.NilError(t, r.reconcileRedisHAMigration(a))
+	assertRedisHAMigrationCondition(t, a, corev1.ConditionTrue, redisHAMigrationRepointingToRedis)
+	assert.Equal(t, getRedisServerAddress(a), "argocd-redis.argocd.svc.cluster.local:6379")
+	assert.NilError(t, r.reconcileRepoDeployment(a))
+	assert.NilError(t, r.reconcileRedisStatefulSet(a))
+	assert.Assert(t, argoutil.IsObjectFound(r.client, testNamespace, "argocd-redis-ha-server", &appsv1.StatefulSet{}))
+
+	repo := &appsv1.Deployment{}
+	assert.NilError(t, argoutil.FetchObject(r.client, testNamespace, "argocd-repo-server", repo))
+	repo.Status.Replicas = *repo.Spec.Replicas
+	repo.Status.UpdatedReplicas = *repo.Spec.Replicas
+	repo.Status.AvailableReplicas = *repo.Spec.Replicas
+	assert.NilError(t, r.client.Status().Update(context.TODO(), repo))
+
+	// Redis HA is removed once the components have rolled out.
+	assert.NilError(t, r.reconcileRedisHAMigration(a))
+	assertRedisHAMigrationCondition(t, a, corev1.ConditionFalse, redisHAMigrationCompleted)
+	assert.NilError(t, r.reconcileRedisStatefulSet(a))
+	assert.NilError(t, r.reconcileRedisHAProxyDeployment(a))
+	assert.Assert(t, !argoutil.IsObjectFound(r.client, testNamespace, "argocd-redis-ha-server", &appsv1.StatefulSet{}))
+	assert.Assert(t, !argoutil.IsObjectFound(r.client, testNamespace, "argocd-redis-ha-haproxy", &appsv1.Deployment{}))
+}
@@ -0,0 +1,139 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocd
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/argoproj-labs/argocd-operator/pkg/apis"
+	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
+)
+
+// renderListKinds will return the list kinds of the given Scheme, which are collected when rendering the manifests for
+// an ArgoCD, in a stable order.
+func renderListKinds(s *runtime.Scheme) []schema.GroupVersionKind {
+	kinds := make([]schema.GroupVersionKind, 0)
+	for gvk := range s.AllKnownTypes() {
+		if gvk.Version == runtime.APIVersionInternal || !strings.HasSuffix(gvk.Kind, "List") {
+			continue
+		}
+		if obj, err := s.New(gvk); err != nil || !meta.IsListType(obj) {
+			continue
+		} else if unversioned, _ := s.IsUnversioned(obj); unversioned {
+			continue
+		}
+		kinds = append(kinds, gvk)
+	}
+	sort.Slice(kinds, func(i, j int) bool {
+		return kinds[i].String() < kinds[j].String()
+	})
+	return kinds
+}
+
+// NewRenderScheme returns the Scheme used when rendering the manifests for an ArgoCD.
+func NewRenderScheme() (*runtime.Scheme, error) {
+	s := runtime.NewScheme()
+	if err := scheme.AddToScheme(s); err != nil {
+		return nil, err
+	}
+	if err := apis.AddToScheme(s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Render will run the reconcilers for the given ArgoCD against an in-memory client and return all of the
+// resources that would be created, without contacting a cluster.
+func Render(cr *argoprojv1a1.ArgoCD, s *runtime.Scheme) ([]runtime.Object, error) {
+	// The namespace of the ArgoCD is looked up to find the namespaces it manages, labeled as the operator does before
+	// reconciling the resources. It is not part of the output.
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:   cr.Namespace,
+		Labels: map[string]string{common.ArgoCDManagedByLabel: cr.Namespace},
+	}}
+	cl := fake.NewFakeClientWithScheme(s, cr.DeepCopy(), ns)
+	r := &ReconcileArgoCD{
+		client: cl,
+		reader: cl,
+		cache:  cl,
+		scheme: s,
+	}
+
+	if err := r.reconcileResources(context.TODO(), cr); err != nil {
+		return nil, err
+	}
+
+	// Every kind known to the scheme is collected, so that no resource created by the reconcilers is left out. The
+	// objects are only stored under the version they were created with, so each object is listed once.
+	objs := make([]runtime.Object, 0)
+	for _, gvk := range renderListKinds(s) {
+		list, err := s.New(gvk)
+		if err != nil {
+			return nil, err
+		}
+		if err := r.client.List(context.TODO(), list, &client.ListOptions{}); err != nil {
+			return nil, err
+		}
+		items, err := meta.ExtractList(list)
+		if err != nil {
+			return nil, err
+		}
+		for _, obj := range items {
+			switch o := obj.(type) {
+			case *argoprojv1a1.ArgoCD:
+				continue // The rendered ArgoCD itself is not part of the output.
+			case *corev1.Namespace:
+				if o.Name == ns.Name {
+					continue
+				}
+			}
+			if err := cleanRenderedObject(obj, s); err != nil {
+				return nil, err
+			}
+			objs = append(objs, obj)
+		}
+	}
+	return objs, nil
+}
+
+// cleanRenderedObject will set the type information on the given object and remove the fields that are
+// only meaningful for objects stored in a cluster.
+func cleanRenderedObject(obj runtime.Object, s *runtime.Scheme) error {
+	gvks, _, err := s.ObjectKinds(obj)
+	if err != nil {
+		return err
+	}
+	obj.GetObjectKind().SetGroupVersionKind(gvks[0])
+
+	m, err := meta.Accessor(obj)
+	if err != nil {
+		return err
+	}
+	m.SetResourceVersion("")
+	m.SetUID("")
+	return nil
+}
@@ -0,0 +1,38 @@
+package argocd
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+func TestRender(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	s, err := NewRenderScheme()
+	assert.NilError(t, err)
+
+	objs, err := Render(makeTestArgoCD(), s)
+	assert.NilError(t, err)
+
+	kinds := map[string]bool{}
+	found := false
+	for _, o := range objs {
+		kinds[o.GetObjectKind().GroupVersionKind().Kind] = true
+		d, ok := o.(*appsv1.Deployment)
+		if !ok || d.Name != "argocd-server" {
+			continue
+		}
+		found = true
+		assert.Equal(t, d.Namespace, testNamespace)
+		assert.Equal(t, d.Kind, "Deployment")
+		assert.Equal(t, d.APIVersion, "apps/v1")
+		assert.Equal(t, d.ResourceVersion, "")
+	}
+	assert.Assert(t, found, "argocd-server Deployment was not rendered")
+
+	// The Roles in the managed namespace are rendered, while the ArgoCD and its namespace are left out.
+	assert.Assert(t, kinds["Role"] && kinds["RoleBinding"])
+	assert.Assert(t, !kinds["ArgoCD"] && !kinds["Namespace"])
+}
@@ -0,0 +1,99 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocd
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+)
+
+const (
+	// repoVolumeGPGKeyring is the Repo server volume holding the GPG keyring.
+	repoVolumeGPGKeyring = "gpg-keyring"
+
+	// repoVolumeCustomTools is the Repo server volume holding the tools installed for the argocd-vault-plugin.
+	repoVolumeCustomTools = "custom-tools"
+
+	// repoVolumeHelmCache is the Repo server volume holding the Helm repository and dependency cache, which is only
+	// added when it is backed by a PersistentVolumeClaim.
+	repoVolumeHelmCache = "helm-cache"
+
+	// repoHelmCachePath is the default Helm cache directory of the argocd user in the Repo server container.
+	repoHelmCachePath = "/home/argocd/.cache/helm"
+
+	// argoCDUserID is the UID and GID of the argocd user of the Argo CD image.
+	argoCDUserID int64 = 999
+)
+
+// repoClaimableVolumes are the Repo server volumes that may be backed by a PersistentVolumeClaim.
+var repoClaimableVolumes = []string{repoVolumeGPGKeyring, repoVolumeCustomTools, repoVolumeHelmCache}
+
+// getArgoRepoVolumeClaim will return the name of the PersistentVolumeClaim backing the given Repo server volume, or
+// an empty string when the volume is not backed by a claim.
+func getArgoRepoVolumeClaim(cr *argoprojv1a1.ArgoCD, volume string) string {
+	for _, claim := range cr.Spec.Repo.VolumeClaims {
+		if claim.Volume == volume {
+			return claim.ClaimName
+		}
+	}
+	return ""
+}
+
+// applyArgoRepoVolumeClaims will back the volumes of the given Repo server pod spec with the PersistentVolumeClaims
+// of the given ArgoCD, adding the Helm cache volume when it is claimed.
+func applyArgoRepoVolumeClaims(cr *argoprojv1a1.ArgoCD, podSpec *corev1.PodSpec) {
+	if claim := getArgoRepoVolumeClaim(cr, repoVolumeHelmCache); claim != "" {
+		podSpec.Containers[0].VolumeMounts = append(podSpec.Containers[0].VolumeMounts, corev1.VolumeMount{
+			Name:      repoVolumeHelmCache,
+			MountPath: repoHelmCachePath,
+		})
+		podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{Name: repoVolumeHelmCache})
+	}
+
+	for i := range podSpec.Volumes {
+		if claim := getArgoRepoVolumeClaim(cr, podSpec.Volumes[i].Name); claim != "" {
+			podSpec.Volumes[i].VolumeSource = corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+					ClaimName: claim,
+				},
+			}
+		}
+	}
+}
+
+// getArgoRepoPodSecurityContext will return the security context of the Repo server pods with the user and group of
+// the given ArgoCD, or nil when neither is set. When the permissions of the claimed volumes are fixed, the group
+// defaults to the argocd group and the kubelet changes the ownership of the volumes whose root does not match it.
+func getArgoRepoPodSecurityContext(cr *argoprojv1a1.ArgoCD) *corev1.PodSecurityContext {
+	fixPermissions := cr.Spec.Repo.FixVolumePermissions && len(cr.Spec.Repo.VolumeClaims) > 0
+	if cr.Spec.Repo.RunAsUser == nil && cr.Spec.Repo.FSGroup == nil && !fixPermissions {
+		return nil
+	}
+
+	sc := &corev1.PodSecurityContext{
+		FSGroup:   cr.Spec.Repo.FSGroup,
+		RunAsUser: cr.Spec.Repo.RunAsUser,
+	}
+	if fixPermissions {
+		if sc.FSGroup == nil {
+			gid := argoCDUserID
+			sc.FSGroup = &gid
+		}
+		policy := corev1.FSGroupChangeOnRootMismatch
+		sc.FSGroupChangePolicy = &policy
+	}
+	return sc
+}
@@ -0,0 +1,238 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
+)
+
+const (
+	// resourceUsageSampleInterval is the minimum time between two snapshots of the resource usage of the components.
+	resourceUsageSampleInterval = 5 * time.Minute
+
+	// resourceUsageHighUtilization is the percentage of a resource limit above which raising the limit is recommended.
+	resourceUsageHighUtilization = 80
+)
+
+// podMetricsGVK is the GroupVersionKind of the PodMetrics resource served by the metrics-server.
+var podMetricsGVK = schema.GroupVersionKind{Group: "metrics.k8s.io", Version: "v1beta1", Kind: "PodMetrics"}
+
+// resourceUsageComponent describes an Argo CD component whose resource usage is sampled.
+type resourceUsageComponent struct {
+	name      string
+	container string
+	specPath  string
+	resources func(cr *argoprojv1a1.ArgoCD) corev1.ResourceRequirements
+}
+
+// resourceUsageComponents are the components whose resource usage is reported in the Status.
+var resourceUsageComponents = []resourceUsageComponent{
+	{"application-controller", "argocd-application-controller", "spec.controller", getArgoApplicationControllerResources},
+	{"repo-server", "argocd-repo-server", "spec.repo", getArgoRepoResources},
+}
+
+// setStatusResourceUsage will set the ResourceUsage Status and the ResourcePressure condition of the given ArgoCD to a
+// new snapshot from the metrics-server once the sample interval has passed, returning true if the Status was changed.
+// Both are removed when the metrics-server is not installed. The snapshot is skipped when the metrics can't be read,
+// e.g. when the metrics API is unavailable, so that the rest of the Status is still updated.
+func (r *ReconcileArgoCD) setStatusResourceUsage(cr *argoprojv1a1.ArgoCD) bool {
+	now := time.Now()
+	if getResourceUsageSampleDelay(cr, now) > 0 {
+		return false
+	}
+
+	usage := make([]argoprojv1a1.ArgoCDResourceUsageStatus, 0, len(resourceUsageComponents))
+	for _, component := range resourceUsageComponents {
+		status, available, err := r.getResourceUsage(cr, component, now)
+		if err != nil {
+			log.Error(err, "skipping the resource usage snapshot")
+			return false
+		}
+		if !available {
+			changed := removeArgoCDCondition(cr, argoprojv1a1.ArgoCDConditionTypeResourcePressure)
+			if len(cr.Status.ResourceUsage) > 0 {
+				cr.Status.ResourceUsage = nil
+				changed = true
+			}
+			return changed
+		}
+		if status != nil {
+			usage = append(usage, *status)
+		}
+	}
+	if len(usage) == 0 && len(cr.Status.ResourceUsage) == 0 {
+		// The pods have not been reported by the metrics-server yet.
+		return false
+	}
+
+	cr.Status.ResourceUsage = usage
+	setArgoCDCondition(cr, getResourcePressureCondition(cr))
+	return true
+}
+
+// getResourceUsageSampleDelay will return the time left until the next snapshot of the resource usage of the given
+// ArgoCD is due. Zero is returned when no snapshot has been taken yet.
+func getResourceUsageSampleDelay(cr *argoprojv1a1.ArgoCD, now time.Time) time.Duration {
+	var delay time.Duration
+	for _, status := range cr.Status.ResourceUsage {
+		if d := status.SampleTime.Add(resourceUsageSampleInterval).Sub(now); d > delay {
+			delay = d
+		}
+	}
+	return delay
+}
+
+// getResourceUsage will return a snapshot of the resource usage of the given component of the given ArgoCD, or nil
+// when no pod of the component is reported by the metrics-server. False is returned when the metrics-server is not
+// installed.
+func (r *ReconcileArgoCD) getResourceUsage(cr *argoprojv1a1.ArgoCD, component resourceUsageComponent, now time.Time) (*argoprojv1a1.ArgoCDResourceUsageStatus, bool, error) {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(podMetricsGVK.GroupVersion().WithKind(podMetricsGVK.Kind + "List"))
+	if err := r.client.List(context.TODO(), list, client.InNamespace(cr.Namespace), client.MatchingLabels{
+		common.ArgoCDKeyName: nameWithSuffix(component.name, cr),
+	}); err != nil {
+		if meta.IsNoMatchError(err) || runtime.IsNotRegisteredError(err) {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("failed to list pod metrics for %s: %w", component.name, err)
+	}
+	if len(list.Items) == 0 {
+		return nil, true, nil
+	}
+
+	var cpu, memory resource.Quantity
+	for _, item := range list.Items {
+		usage, err := getContainerUsage(item, component.container)
+		if err != nil {
+			return nil, true, err
+		}
+		if q := usage[corev1.ResourceCPU]; q.Cmp(cpu) > 0 {
+			cpu = q
+		}
+		if q := usage[corev1.ResourceMemory]; q.Cmp(memory) > 0 {
+			memory = q
+		}
+	}
+
+	limits := component.resources(cr).Limits
+	return &argoprojv1a1.ArgoCDResourceUsageStatus{
+		Component:         component.name,
+		CPU:               cpu.String(),
+		CPUUtilization:    getUtilization(cpu, limits[corev1.ResourceCPU]),
+		Memory:            memory.String(),
+		MemoryUtilization: getUtilization(memory, limits[corev1.ResourceMemory]),
+		Pods:              int32(len(list.Items)),
+		SampleTime:        metav1.NewTime(now),
+	}, true, nil
+}
+
+// getContainerUsage will return the usage of the container with the given name from the given PodMetrics.
+func getContainerUsage(podMetrics unstructured.Unstructured, name string) (corev1.ResourceList, error) {
+	containers, _, err := unstructured.NestedSlice(podMetrics.Object, "containers")
+	if err != nil {
+		return nil, err
+	}
+
+	usage := corev1.ResourceList{}
+	for _, c := range containers {
+		container, ok := c.(map[string]interface{})
+		if !ok || container["name"] != name {
+			continue
+		}
+		values, _, err := unstructured.NestedStringMap(container, "usage")
+		if err != nil {
+			return nil, err
+		}
+		for key, value := range values {
+			q, err := resource.ParseQuantity(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s usage of pod %s: %w", key, podMetrics.GetName(), err)
+			}
+			usage[corev1.ResourceName(key)] = q
+		}
+	}
+	return usage, nil
+}
+
+// getUtilization will return the given usage as a percentage of the given limit, or zero when no limit is set.
+func getUtilization(usage, limit resource.Quantity) int32 {
+	if limit.IsZero() {
+		return 0
+	}
+	return int32(usage.MilliValue() * 100 / limit.MilliValue())
+}
+
+// getResourcePressureCondition will return the ResourcePressure condition for the resource usage in the Status of the
+// given ArgoCD, with a recommendation for each limit that is nearly reached and each processor count that is too low
+// for the number of Applications.
+func getResourcePressureCondition(cr *argoprojv1a1.ArgoCD) argoprojv1a1.ArgoCDCondition {
+	recommendations := make([]string, 0)
+	for _, status := range cr.Status.ResourceUsage {
+		specPath := ""
+		for _, component := range resourceUsageComponents {
+			if component.name == status.Component {
+				specPath = component.specPath
+			}
+		}
+		if status.CPUUtilization >= resourceUsageHighUtilization {
+			recommendations = append(recommendations, fmt.Sprintf("%s is using %d%% of its CPU limit, consider raising .%s.resources.limits.cpu",
+				status.Component, status.CPUUtilization, specPath))
+		}
+		if status.MemoryUtilization >= resourceUsageHighUtilization {
+			recommendations = append(recommendations, fmt.Sprintf("%s is using %d%% of its memory limit, consider raising .%s.resources.limits.memory",
+				status.Component, status.MemoryUtilization, specPath))
+		}
+	}
+
+	statusPath, operationPath := "spec.controller.processors.status", "spec.controller.processors.operation"
+	if isProcessorsAutosizeEnabled(cr) {
+		statusPath, operationPath = "spec.controller.processorsAutosize.maxStatus", "spec.controller.processorsAutosize.maxOperation"
+	}
+	if want := (cr.Status.ApplicationCount + applicationsPerStatusProcessor - 1) / applicationsPerStatusProcessor; want > getArgoServerStatusProcessors(cr) {
+		recommendations = append(recommendations, fmt.Sprintf("%d Applications exceed the capacity of %d status processors, consider raising .%s to %d",
+			cr.Status.ApplicationCount, getArgoServerStatusProcessors(cr), statusPath, want))
+	}
+	if want := (cr.Status.ApplicationCount + applicationsPerOperationProcessor - 1) / applicationsPerOperationProcessor; want > getArgoServerOperationProcessors(cr) {
+		recommendations = append(recommendations, fmt.Sprintf("%d Applications exceed the capacity of %d operation processors, consider raising .%s to %d",
+			cr.Status.ApplicationCount, getArgoServerOperationProcessors(cr), operationPath, want))
+	}
+
+	condition := argoprojv1a1.ArgoCDCondition{
+		Type:   argoprojv1a1.ArgoCDConditionTypeResourcePressure,
+		Status: corev1.ConditionFalse,
+		Reason: "ResourcesSufficient",
+	}
+	if len(recommendations) > 0 {
+		condition.Status = corev1.ConditionTrue
+		condition.Reason = "ResourcesInsufficient"
+		condition.Message = strings.Join(recommendations, "; ")
+	}
+	return condition
+}
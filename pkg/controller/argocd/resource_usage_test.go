@@ -0,0 +1,141 @@
+package argocd
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+
+	argoprojv1alpha1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
+)
+
+func makeTestPodMetrics(cr *argoprojv1alpha1.ArgoCD, pod, component, container, cpu, memory string) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{}
+	u.SetGroupVersionKind(podMetricsGVK)
+	u.SetNamespace(cr.Namespace)
+	u.SetName(pod)
+	u.SetLabels(map[string]string{common.ArgoCDKeyName: nameWithSuffix(component, cr)})
+	u.Object["containers"] = []interface{}{
+		map[string]interface{}{
+			"name":  container,
+			"usage": map[string]interface{}{"cpu": cpu, "memory": memory},
+		},
+		map[string]interface{}{
+			"name":  "sidecar",
+			"usage": map[string]interface{}{"cpu": "4", "memory": "4Gi"},
+		},
+	}
+	return u
+}
+
+func TestReconcileArgoCD_setStatusResourceUsage(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.Controller.Resources = &corev1.ResourceRequirements{
+			Limits: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("1"),
+				corev1.ResourceMemory: resource.MustParse("1Gi"),
+			},
+		}
+	})
+	r := makeTestReconciler(t, a)
+
+	// No snapshot is taken until the pods are reported by the metrics-server.
+	assert.Assert(t, !r.setStatusResourceUsage(a))
+	assert.Equal(t, len(a.Status.ResourceUsage), 0)
+	assert.Equal(t, len(a.Status.Conditions), 0)
+
+	for _, obj := range []*unstructured.Unstructured{
+		makeTestPodMetrics(a, "argocd-application-controller-0", "application-controller", "argocd-application-controller", "900m", "256Mi"),
+		makeTestPodMetrics(a, "argocd-application-controller-1", "application-controller", "argocd-application-controller", "300m", "512Mi"),
+		makeTestPodMetrics(a, "argocd-repo-server-abcde", "repo-server", "argocd-repo-server", "100m", "64Mi"),
+	} {
+		assert.NilError(t, r.client.Create(context.TODO(), obj))
+	}
+
+	assert.Assert(t, r.setStatusResourceUsage(a))
+	assert.Equal(t, len(a.Status.ResourceUsage), 2)
+	controller := a.Status.ResourceUsage[0]
+	assert.Equal(t, controller.Component, "application-controller")
+	assert.Equal(t, controller.CPU, "900m")
+	assert.Equal(t, controller.CPUUtilization, int32(90))
+	assert.Equal(t, controller.Memory, "512Mi")
+	assert.Equal(t, controller.MemoryUtilization, int32(50))
+	assert.Equal(t, controller.Pods, int32(2))
+	repo := a.Status.ResourceUsage[1]
+	assert.Equal(t, repo.Component, "repo-server")
+	assert.Equal(t, repo.CPU, "100m")
+	assert.Equal(t, repo.CPUUtilization, int32(0))
+
+	assert.Equal(t, len(a.Status.Conditions), 1)
+	c := a.Status.Conditions[0]
+	assert.Equal(t, c.Type, argoprojv1alpha1.ArgoCDConditionTypeResourcePressure)
+	assert.Equal(t, c.Status, corev1.ConditionTrue)
+	assert.Equal(t, c.Message, "application-controller is using 90% of its CPU limit, consider raising .spec.controller.resources.limits.cpu")
+
+	// A new snapshot is only taken once the sample interval has passed.
+	sampleTime := controller.SampleTime
+	assert.Assert(t, !r.setStatusResourceUsage(a))
+	assert.Equal(t, a.Status.ResourceUsage[0].SampleTime, sampleTime)
+	assert.Assert(t, r.getRequeueDelay(a) <= resourceUsageSampleInterval+time.Second)
+
+	a.Status.ResourceUsage[0].SampleTime = metav1.NewTime(time.Now().Add(-resourceUsageSampleInterval))
+	a.Status.ResourceUsage[1].SampleTime = a.Status.ResourceUsage[0].SampleTime
+	a.Spec.Controller.Resources.Limits[corev1.ResourceCPU] = resource.MustParse("2")
+	assert.Assert(t, r.setStatusResourceUsage(a))
+	assert.Assert(t, a.Status.ResourceUsage[0].SampleTime.After(sampleTime.Time))
+	assert.Equal(t, a.Status.ResourceUsage[0].CPUUtilization, int32(45))
+	assert.Equal(t, a.Status.Conditions[0].Status, corev1.ConditionFalse)
+}
+
+// podMetricsErrorClient fails to list PodMetrics, like a client of an unavailable metrics API.
+type podMetricsErrorClient struct {
+	client.Client
+}
+
+func (c podMetricsErrorClient) List(ctx context.Context, list runtime.Object, opts ...client.ListOption) error {
+	if u, ok := list.(*unstructured.UnstructuredList); ok && u.GroupVersionKind().Group == podMetricsGVK.Group {
+		return errors.NewServiceUnavailable("the server is currently unable to handle the request")
+	}
+	return c.Client.List(ctx, list, opts...)
+}
+
+func TestReconcileArgoCD_reconcileStatusConditions_resourceUsageUnavailable(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD()
+	r := makeTestReconciler(t, a)
+	r.client = podMetricsErrorClient{r.client}
+
+	// The snapshot is skipped, the other conditions are still written.
+	assert.NilError(t, r.reconcileStatusConditions(a))
+	assert.Equal(t, len(a.Status.ResourceUsage), 0)
+	assert.Equal(t, len(a.Status.Conditions), 1)
+	assert.Equal(t, a.Status.Conditions[0].Type, argoprojv1alpha1.ArgoCDConditionTypeDegraded)
+}
+
+func TestGetResourcePressureCondition_processors(t *testing.T) {
+	restoreEnv(t)
+	a := makeTestArgoCD()
+	a.Status.ApplicationCount = 1000
+
+	c := getResourcePressureCondition(a)
+	assert.Equal(t, c.Status, corev1.ConditionTrue)
+	assert.Equal(t, c.Message, "1000 Applications exceed the capacity of 20 status processors, consider raising .spec.controller.processors.status to 50; "+
+		"1000 Applications exceed the capacity of 10 operation processors, consider raising .spec.controller.processors.operation to 25")
+
+	a.Spec.Controller.Processors.Status = 50
+	a.Spec.Controller.Processors.Operation = 25
+	c = getResourcePressureCondition(a)
+	assert.Equal(t, c.Status, corev1.ConditionFalse)
+	assert.Equal(t, c.Reason, "ResourcesSufficient")
+}
@@ -6,8 +6,7 @@ import (
 	"os"
 
 	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
-	"github.com/argoproj-labs/argocd-operator/pkg/common"
-	corev1 "k8s.io/api/core/v1"
+	"github.com/argoproj-labs/argocd-operator/pkg/controller/argoutil"
 	v1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -88,22 +87,37 @@ func (r *ReconcileArgoCD) reconcileRoles(cr *argoprojv1a1.ArgoCD) (role *v1.Role
 // Managed by a single instance of ArgoCD.
 func (r *ReconcileArgoCD) reconcileRole(name string, policyRules []v1.PolicyRule, cr *argoprojv1a1.ArgoCD) ([]*v1.Role, error) {
 	var roles []*v1.Role
-	namespaces := corev1.NamespaceList{}
-	listOption := client.MatchingLabels{
-		common.ArgoCDManagedByLabel: cr.Namespace,
-	}
 
 	// get the list of namespaces managed by the ArgoCD instance
-	if err := r.client.List(context.TODO(), &namespaces, listOption); err != nil {
+	namespaces, rejected, err := r.getManagedNamespaces(cr)
+	if err != nil {
 		return nil, err
 	}
 
+	// remove any Role and RoleBinding left in a namespace that the ArgoCD instance is not allowed to manage
+	for _, namespace := range rejected {
+		existingRole := &v1.Role{}
+		if argoutil.IsObjectFound(r.client, namespace.Name, generateResourceName(name, cr), existingRole) {
+			if err := r.client.Delete(context.TODO(), existingRole); err != nil {
+				return nil, err
+			}
+		}
+		existingRoleBinding := &v1.RoleBinding{}
+		if argoutil.IsObjectFound(r.client, namespace.Name, generateResourceName(name, cr), existingRoleBinding) {
+			if err := r.client.Delete(context.TODO(), existingRoleBinding); err != nil {
+				return nil, err
+			}
+		}
+	}
+
 	// create policy rules for each namespace
-	for _, namespace := range namespaces.Items {
+	for _, namespace := range namespaces {
 		role := newRole(name, policyRules, cr)
 		if err := applyReconcilerHook(cr, role, ""); err != nil {
 			return nil, err
 		}
+		// extra rules are added after the hooks so that they are not overwritten
+		role.Rules = append(append([]v1.PolicyRule{}, role.Rules...), getExtraRoleRules(name, cr)...)
 		role.Namespace = namespace.Name
 		existingRole := v1.Role{}
 		err := r.client.Get(context.TODO(), types.NamespacedName{Name: role.Name, Namespace: role.Namespace}, &existingRole)
@@ -112,7 +126,7 @@ func (r *ReconcileArgoCD) reconcileRole(name string, policyRules []v1.PolicyRule
 				return nil, fmt.Errorf("failed to reconcile the role for the service account associated with %s : %s", name, err)
 			}
 			roles = append(roles, role)
-			if name == dexServer && isDexDisabled() {
+			if name == dexServer && !isManagedDexEnabled(cr) {
 				continue // Dex is disabled, do nothing
 			}
 			controllerutil.SetControllerReference(cr, role, r.scheme)
@@ -122,7 +136,7 @@ func (r *ReconcileArgoCD) reconcileRole(name string, policyRules []v1.PolicyRule
 			continue
 		}
 
-		if name == dexServer && isDexDisabled() {
+		if name == dexServer && !isManagedDexEnabled(cr) {
 			// Delete any existing Role created for Dex
 			if err := r.client.Delete(context.TODO(), &existingRole); err != nil {
 				return nil, err
@@ -143,10 +157,16 @@ func (r *ReconcileArgoCD) reconcileClusterRole(name string, policyRules []v1.Pol
 	if allowedNamespace(cr.Namespace, os.Getenv("ARGOCD_CLUSTER_CONFIG_NAMESPACES")) {
 		allowed = true
 	}
+
+	if name == applicationController && cr.Spec.DefaultClusterScopedRole != "" {
+		return r.reconcileDefaultClusterScopedRole(name, allowed, cr)
+	}
+
 	clusterRole := newClusterRole(name, policyRules, cr)
 	if err := applyReconcilerHook(cr, clusterRole, ""); err != nil {
 		return nil, err
 	}
+	clusterRole.Rules = append(append([]v1.PolicyRule{}, clusterRole.Rules...), getExtraRoleRules(name, cr)...)
 
 	existingClusterRole := &v1.ClusterRole{}
 	err := r.client.Get(context.TODO(), types.NamespacedName{Name: clusterRole.Name}, existingClusterRole)
@@ -170,6 +190,30 @@ func (r *ReconcileArgoCD) reconcileClusterRole(name string, policyRules []v1.Pol
 	return existingClusterRole, r.client.Update(context.TODO(), existingClusterRole)
 }
 
+// reconcileDefaultClusterScopedRole will remove the ClusterRole generated for the given component and return the
+// existing ClusterRole named in the ArgoCD spec, so that the component is bound to it instead.
+func (r *ReconcileArgoCD) reconcileDefaultClusterScopedRole(name string, allowed bool, cr *argoprojv1a1.ArgoCD) (*v1.ClusterRole, error) {
+	generatedClusterRole := &v1.ClusterRole{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: GenerateUniqueResourceName(name, cr)}, generatedClusterRole)
+	if err == nil {
+		if err := r.client.Delete(context.TODO(), generatedClusterRole); err != nil {
+			return nil, err
+		}
+	} else if !errors.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to reconcile the cluster role for the service account associated with %s : %s", name, err)
+	}
+
+	if !allowed {
+		return nil, nil
+	}
+
+	clusterRole := &v1.ClusterRole{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: cr.Spec.DefaultClusterScopedRole}, clusterRole); err != nil {
+		return nil, fmt.Errorf("failed to get the cluster role %s for the service account associated with %s : %s", cr.Spec.DefaultClusterScopedRole, name, err)
+	}
+	return clusterRole, nil
+}
+
 func deleteClusterRoles(c client.Client, clusterRoleList *v1.ClusterRoleList) error {
 	for _, clusterRole := range clusterRoleList.Items {
 		if err := c.Delete(context.TODO(), &clusterRole); err != nil {
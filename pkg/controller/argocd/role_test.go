@@ -6,6 +6,7 @@ import (
 	"os"
 	"testing"
 
+	argoprojv1alpha1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
 	"github.com/argoproj-labs/argocd-operator/pkg/common"
 	"gotest.tools/assert"
 	v1 "k8s.io/api/rbac/v1"
@@ -124,3 +125,72 @@ func TestReconcileArgoCD_RoleHooks(t *testing.T) {
 	assert.NilError(t, err)
 	assert.DeepEqual(t, role.Rules, []v1.PolicyRule{})
 }
+
+func TestReconcileArgoCD_reconcileRole_rejectedNamespace(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD()
+	r := makeTestReconciler(t, a)
+	assert.NilError(t, createNamespace(r, a.Namespace, a.Namespace))
+	assert.NilError(t, createNamespace(r, "tenant", a.Namespace))
+
+	rules := policyRuleForApplicationController()
+	role := newRole(applicationController, rules, a)
+
+	_, err := r.reconcileRole(applicationController, rules, a)
+	assert.NilError(t, err)
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: role.Name, Namespace: "tenant"}, role))
+
+	// The tenant namespace is not in the allowlist of the instance, the Role is removed.
+	os.Setenv(common.ArgoCDManagedNamespacesAllowlistEnvName, fmt.Sprintf("%s=team-*", a.Namespace))
+	defer os.Unsetenv(common.ArgoCDManagedNamespacesAllowlistEnvName)
+
+	roles, err := r.reconcileRole(applicationController, rules, a)
+	assert.NilError(t, err)
+	assert.Equal(t, len(roles), 1)
+	assert.Equal(t, roles[0].Namespace, a.Namespace)
+	assert.ErrorContains(t, r.client.Get(context.TODO(), types.NamespacedName{Name: role.Name, Namespace: "tenant"}, role), "not found")
+}
+
+func TestReconcileArgoCD_reconcileRole_extraRoleRules(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	extraRules := []v1.PolicyRule{
+		{
+			APIGroups: []string{"example.com"},
+			Resources: []string{"widgets"},
+			Verbs:     []string{"get", "list", "watch"},
+		},
+	}
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.ExtraRoleRules.ApplicationController = extraRules
+		a.Spec.ExtraRoleRules.Dex = extraRules
+		a.Spec.ExtraRoleRules.HA = extraRules
+	})
+	r := makeTestReconciler(t, a)
+	assert.NilError(t, createNamespace(r, a.Namespace, a.Namespace))
+
+	rules := policyRuleForApplicationController()
+	expectedRules := append(policyRuleForApplicationController(), extraRules...)
+
+	roles, err := r.reconcileRole(applicationController, rules, a)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, roles[0].Rules, expectedRules)
+	assert.Equal(t, len(rules), 1)
+
+	// The extra rules are only added to the Role of the matching component.
+	roles, err = r.reconcileRole(server, policyRuleForServer(), a)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, roles[0].Rules, policyRuleForServer())
+	roles, err = r.reconcileRole(dexServer, policyRuleForDexServer(), a)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, roles[0].Rules, append(policyRuleForDexServer(), extraRules...))
+	roles, err = r.reconcileRole(redisHa, policyRuleForRedisHa(a), a)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, roles[0].Rules, append(policyRuleForRedisHa(a), extraRules...))
+
+	os.Setenv("ARGOCD_CLUSTER_CONFIG_NAMESPACES", a.Namespace)
+	defer os.Unsetenv("ARGOCD_CLUSTER_CONFIG_NAMESPACES")
+
+	clusterRole, err := r.reconcileClusterRole(applicationController, rules, a)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, clusterRole.Rules, expectedRules)
+}
@@ -110,7 +110,7 @@ func (r *ReconcileArgoCD) reconcileRoleBinding(name string, rules []v1.PolicyRul
 			if !errors.IsNotFound(err) {
 				return fmt.Errorf("failed to get the rolebinding associated with %s : %s", name, err)
 			}
-			if name == dexServer && isDexDisabled() {
+			if name == dexServer && !isManagedDexEnabled(cr) {
 				continue // Dex is disabled, do nothing
 			}
 			roleBindingExists = false
@@ -130,7 +130,7 @@ func (r *ReconcileArgoCD) reconcileRoleBinding(name string, rules []v1.PolicyRul
 		}
 
 		if roleBindingExists {
-			if name == dexServer && isDexDisabled() {
+			if name == dexServer && !isManagedDexEnabled(cr) {
 				// Delete any existing RoleBinding created for Dex
 				if err = r.client.Delete(context.TODO(), existingRoleBinding); err != nil {
 					return err
@@ -184,18 +184,29 @@ func (r *ReconcileArgoCD) reconcileClusterRoleBinding(name string, role *v1.Clus
 		return nil
 	}
 
+	roleRef := v1.RoleRef{
+		APIGroup: v1.GroupName,
+		Kind:     "ClusterRole",
+		Name:     role.Name,
+	}
+
+	// the RoleRef of a ClusterRoleBinding can not be updated, delete the existing one and create a new one
+	if roleBindingExists && !reflect.DeepEqual(roleRef, roleBinding.RoleRef) {
+		if err := r.client.Delete(context.TODO(), roleBinding); err != nil {
+			return err
+		}
+		roleBindingExists = false
+		roleBinding = newClusterRoleBindingWithname(name, cr)
+	}
+
 	roleBinding.Subjects = []v1.Subject{
 		{
 			Kind:      v1.ServiceAccountKind,
-			Name:      generateResourceName(name, cr),
-			Namespace: cr.Namespace,
+			Name:      sa.Name,
+			Namespace: sa.Namespace,
 		},
 	}
-	roleBinding.RoleRef = v1.RoleRef{
-		APIGroup: v1.GroupName,
-		Kind:     "ClusterRole",
-		Name:     GenerateUniqueResourceName(name, cr),
-	}
+	roleBinding.RoleRef = roleRef
 
 	controllerutil.SetControllerReference(cr, roleBinding, r.scheme)
 	if roleBindingExists {
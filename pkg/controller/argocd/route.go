@@ -17,6 +17,7 @@ package argocd
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
 	"github.com/argoproj-labs/argocd-operator/pkg/common"
@@ -88,51 +89,47 @@ func (r *ReconcileArgoCD) reconcileRoutes(cr *argoprojv1a1.ArgoCD) error {
 	return nil
 }
 
-// reconcileGrafanaRoute will ensure that the ArgoCD Grafana Route is present.
+// reconcileGrafanaRoute will ensure that the ArgoCD Grafana Route is present and up to date.
 func (r *ReconcileArgoCD) reconcileGrafanaRoute(cr *argoprojv1a1.ArgoCD) error {
 	route := newRouteWithSuffix("grafana", cr)
-	if argoutil.IsObjectFound(r.client, cr.Namespace, route.Name, route) {
-		if !cr.Spec.Grafana.Enabled || !cr.Spec.Grafana.Route.Enabled {
+	found := argoutil.IsObjectFound(r.client, cr.Namespace, route.Name, route)
+	if !isManagedGrafanaEnabled(cr) || !cr.Spec.Grafana.Route.Enabled {
+		if found {
 			// Route exists but enabled flag has been set to false, delete the Route
 			return r.client.Delete(context.TODO(), route)
 		}
-		return nil // Route found, do nothing
-	}
-
-	if !cr.Spec.Grafana.Enabled || !cr.Spec.Grafana.Route.Enabled {
 		return nil // Grafana itself or Route not enabled, do nothing.
 	}
 
-	// Allow override of the Annotations for the Route.
-	if len(cr.Spec.Grafana.Route.Annotations) > 0 {
-		route.Annotations = cr.Spec.Grafana.Route.Annotations
-	}
+	// Add the labels and annotations of the spec, keeping the ones added by others, e.g. by the router.
+	applyManagedMetadata(&route.ObjectMeta, cr.Spec.Grafana.Route.Labels, cr.Spec.Grafana.Route.Annotations)
 
-	// Allow override of the Host for the Route.
+	// Allow override of the Host for the Route. A cleared host is ignored by the API, so the Route is recreated to
+	// get a generated host again.
 	if len(cr.Spec.Grafana.Host) > 0 {
 		route.Spec.Host = cr.Spec.Grafana.Host // TODO: What additional role needed for this?
+	} else if found && !isGeneratedRouteHost(route) {
+		return r.client.Delete(context.TODO(), route)
 	}
 
 	// Allow override of the Path for the Route
-	if len(cr.Spec.Grafana.Route.Path) > 0 {
-		route.Spec.Path = cr.Spec.Grafana.Route.Path
-	}
+	route.Spec.Path = cr.Spec.Grafana.Route.Path
 
 	route.Spec.Port = &routev1.RoutePort{
 		TargetPort: intstr.FromString("http"),
 	}
 
 	// Allow override of TLS options for the Route
-	if cr.Spec.Grafana.Route.TLS != nil {
-		route.Spec.TLS = cr.Spec.Grafana.Route.TLS
-	}
+	route.Spec.TLS = cr.Spec.Grafana.Route.TLS
 
 	route.Spec.To.Kind = "Service"
 	route.Spec.To.Name = nameWithSuffix("grafana", cr)
 
 	// Allow override of the WildcardPolicy for the Route
-	if cr.Spec.Grafana.Route.WildcardPolicy != nil && len(*cr.Spec.Grafana.Route.WildcardPolicy) > 0 {
-		route.Spec.WildcardPolicy = *cr.Spec.Grafana.Route.WildcardPolicy
+	route.Spec.WildcardPolicy = getRouteWildcardPolicy(cr.Spec.Grafana.Route.WildcardPolicy)
+
+	if found {
+		return r.client.Update(context.TODO(), route)
 	}
 
 	if err := controllerutil.SetControllerReference(cr, route, r.scheme); err != nil {
@@ -141,46 +138,47 @@ func (r *ReconcileArgoCD) reconcileGrafanaRoute(cr *argoprojv1a1.ArgoCD) error {
 	return r.client.Create(context.TODO(), route)
 }
 
-// reconcilePrometheusRoute will ensure that the ArgoCD Prometheus Route is present.
+// reconcilePrometheusRoute will ensure that the ArgoCD Prometheus Route is present and up to date.
 func (r *ReconcileArgoCD) reconcilePrometheusRoute(cr *argoprojv1a1.ArgoCD) error {
 	route := newRouteWithSuffix("prometheus", cr)
-	if argoutil.IsObjectFound(r.client, cr.Namespace, route.Name, route) {
-		if !cr.Spec.Prometheus.Enabled || !cr.Spec.Prometheus.Route.Enabled {
+	found := argoutil.IsObjectFound(r.client, cr.Namespace, route.Name, route)
+	if !cr.Spec.Prometheus.Enabled || !cr.Spec.Prometheus.Route.Enabled {
+		if found {
 			// Route exists but enabled flag has been set to false, delete the Route
 			return r.client.Delete(context.TODO(), route)
 		}
-		return nil // Route found, do nothing
-	}
-
-	if !cr.Spec.Prometheus.Enabled || !cr.Spec.Prometheus.Route.Enabled {
 		return nil // Prometheus itself or Route not enabled, do nothing.
 	}
 
-	// Allow override of the Annotations for the Route.
-	if len(cr.Spec.Prometheus.Route.Annotations) > 0 {
-		route.Annotations = cr.Spec.Prometheus.Route.Annotations
-	}
+	// Add the labels and annotations of the spec, keeping the ones added by others, e.g. by the router.
+	applyManagedMetadata(&route.ObjectMeta, cr.Spec.Prometheus.Route.Labels, cr.Spec.Prometheus.Route.Annotations)
 
-	// Allow override of the Host for the Route.
+	// Allow override of the Host for the Route. A cleared host is ignored by the API, so the Route is recreated to
+	// get a generated host again.
 	if len(cr.Spec.Prometheus.Host) > 0 {
 		route.Spec.Host = cr.Spec.Prometheus.Host // TODO: What additional role needed for this?
+	} else if found && !isGeneratedRouteHost(route) {
+		return r.client.Delete(context.TODO(), route)
 	}
 
+	// Allow override of the Path for the Route
+	route.Spec.Path = cr.Spec.Prometheus.Route.Path
+
 	route.Spec.Port = &routev1.RoutePort{
 		TargetPort: intstr.FromString("web"),
 	}
 
 	// Allow override of TLS options for the Route
-	if cr.Spec.Prometheus.Route.TLS != nil {
-		route.Spec.TLS = cr.Spec.Prometheus.Route.TLS
-	}
+	route.Spec.TLS = cr.Spec.Prometheus.Route.TLS
 
 	route.Spec.To.Kind = "Service"
 	route.Spec.To.Name = "prometheus-operated"
 
 	// Allow override of the WildcardPolicy for the Route
-	if cr.Spec.Prometheus.Route.WildcardPolicy != nil && len(*cr.Spec.Prometheus.Route.WildcardPolicy) > 0 {
-		route.Spec.WildcardPolicy = *cr.Spec.Prometheus.Route.WildcardPolicy
+	route.Spec.WildcardPolicy = getRouteWildcardPolicy(cr.Spec.Prometheus.Route.WildcardPolicy)
+
+	if found {
+		return r.client.Update(context.TODO(), route)
 	}
 
 	if err := controllerutil.SetControllerReference(cr, route, r.scheme); err != nil {
@@ -189,6 +187,31 @@ func (r *ReconcileArgoCD) reconcilePrometheusRoute(cr *argoprojv1a1.ArgoCD) erro
 	return r.client.Create(context.TODO(), route)
 }
 
+// isGeneratedRouteHost will return true if the given Route has no host or a host generated by the router, rather
+// than a host set from the spec of the ArgoCD.
+func isGeneratedRouteHost(route *routev1.Route) bool {
+	return route.Spec.Host == "" || strings.HasPrefix(route.Spec.Host, fmt.Sprintf("%s-%s.", route.Name, route.Namespace))
+}
+
+// getRouteWildcardPolicy will return the given WildcardPolicy for a Route, or the default of the API when it is not
+// set.
+func getRouteWildcardPolicy(policy *routev1.WildcardPolicyType) routev1.WildcardPolicyType {
+	if policy == nil || len(*policy) == 0 {
+		return routev1.WildcardPolicyNone
+	}
+	return *policy
+}
+
+// getArgoServerRouteTargetPort will return the name of the Argo CD Server Service port targeted by a Route with the
+// given TLS configuration. Edge terminated traffic is forwarded as plain HTTP, while passthrough and reencrypt traffic
+// reaches the server over TLS.
+func getArgoServerRouteTargetPort(tls *routev1.TLSConfig) string {
+	if tls != nil && tls.Termination == routev1.TLSTerminationEdge {
+		return "http"
+	}
+	return "https"
+}
+
 // reconcileServerRoute will ensure that the ArgoCD Server Route is present.
 func (r *ReconcileArgoCD) reconcileServerRoute(cr *argoprojv1a1.ArgoCD) error {
 
@@ -205,10 +228,10 @@ func (r *ReconcileArgoCD) reconcileServerRoute(cr *argoprojv1a1.ArgoCD) error {
 		return nil // Route not enabled, move along...
 	}
 
-	// Allow override of the Annotations for the Route.
-	if len(cr.Spec.Server.Route.Annotations) > 0 {
-		route.Annotations = cr.Spec.Server.Route.Annotations
-	}
+	// Add the labels and annotations of the spec, keeping the ones added by others, e.g. by the router.
+	annotations := addExternalDNSAnnotations(cr, cr.Spec.Server.Route.Annotations,
+		getArgoServerExternalDNSHostnames(cr, cr.Spec.Server.Host))
+	applyManagedMetadata(&route.ObjectMeta, cr.Spec.Server.Route.Labels, annotations)
 
 	// Allow override of the Host for the Route.
 	if len(cr.Spec.Server.Host) > 0 {
@@ -217,22 +240,23 @@ func (r *ReconcileArgoCD) reconcileServerRoute(cr *argoprojv1a1.ArgoCD) error {
 
 	if cr.Spec.Server.Insecure {
 		// Disable TLS and rely on the cluster certificate.
-		route.Spec.Port = &routev1.RoutePort{
-			TargetPort: intstr.FromString("http"),
-		}
 		route.Spec.TLS = &routev1.TLSConfig{
 			InsecureEdgeTerminationPolicy: routev1.InsecureEdgeTerminationPolicyRedirect,
 			Termination:                   routev1.TLSTerminationEdge,
 		}
+		if cr.Spec.Server.DisableHTTPSRedirect {
+			route.Spec.TLS.InsecureEdgeTerminationPolicy = routev1.InsecureEdgeTerminationPolicyAllow
+		}
 	} else {
 		// Server is using TLS configure passthrough.
-		route.Spec.Port = &routev1.RoutePort{
-			TargetPort: intstr.FromString("https"),
-		}
 		route.Spec.TLS = &routev1.TLSConfig{
 			InsecureEdgeTerminationPolicy: routev1.InsecureEdgeTerminationPolicyRedirect,
 			Termination:                   routev1.TLSTerminationPassthrough,
 		}
+		if cr.Spec.Server.DisableHTTPSRedirect {
+			// Allow is not supported with passthrough termination, plain HTTP requests are rejected instead.
+			route.Spec.TLS.InsecureEdgeTerminationPolicy = routev1.InsecureEdgeTerminationPolicyNone
+		}
 	}
 
 	// Allow override of TLS options for the Route
@@ -240,6 +264,11 @@ func (r *ReconcileArgoCD) reconcileServerRoute(cr *argoprojv1a1.ArgoCD) error {
 		route.Spec.TLS = cr.Spec.Server.Route.TLS
 	}
 
+	// Target the Service port matching the termination, the server does not accept plain HTTP unless it is insecure.
+	route.Spec.Port = &routev1.RoutePort{
+		TargetPort: intstr.FromString(getArgoServerRouteTargetPort(route.Spec.TLS)),
+	}
+
 	route.Spec.To.Kind = "Service"
 	route.Spec.To.Name = nameWithSuffix("server", cr)
 
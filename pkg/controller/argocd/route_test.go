@@ -6,6 +6,7 @@ import (
 
 	"gotest.tools/assert"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -17,6 +18,7 @@ import (
 	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
 
 	argov1alpha1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
 	"github.com/google/go-cmp/cmp"
 	routev1 "github.com/openshift/api/route/v1"
 )
@@ -164,6 +166,148 @@ func TestReconcileRouteUnsetsInsecure(t *testing.T) {
 	}
 }
 
+func TestReconcileArgoCD_reconcilePrometheusRoute_update(t *testing.T) {
+	routeAPIFound = true
+	ctx := context.Background()
+	logf.SetLogger(logf.ZapLogger(true))
+	argoCD := makeArgoCD(func(a *argov1alpha1.ArgoCD) {
+		a.Spec.Prometheus.Enabled = true
+		a.Spec.Prometheus.Route.Enabled = true
+	})
+	r := makeReconciler(t, argoCD, argoCD)
+
+	assert.NilError(t, r.reconcilePrometheusRoute(argoCD))
+
+	argoCD.Spec.Prometheus.Host = "prometheus.example.com"
+	argoCD.Spec.Prometheus.Route.Path = "/prometheus"
+	assert.NilError(t, r.reconcilePrometheusRoute(argoCD))
+
+	loaded := &routev1.Route{}
+	assert.NilError(t, r.client.Get(ctx, types.NamespacedName{Name: testArgoCDName + "-prometheus", Namespace: testNamespace}, loaded))
+	assert.Equal(t, loaded.Spec.Host, "prometheus.example.com")
+	assert.Equal(t, loaded.Spec.Path, "/prometheus")
+
+	// Clearing the fields restores the defaults, the Route is recreated to get a generated host.
+	wildcard := routev1.WildcardPolicySubdomain
+	argoCD.Spec.Prometheus.Host = "prometheus-argocd.apps.example.com"
+	argoCD.Spec.Prometheus.Route.TLS = &routev1.TLSConfig{Termination: routev1.TLSTerminationEdge}
+	argoCD.Spec.Prometheus.Route.WildcardPolicy = &wildcard
+	assert.NilError(t, r.reconcilePrometheusRoute(argoCD))
+	argoCD.Spec.Prometheus.Host = ""
+	argoCD.Spec.Prometheus.Route.Path = ""
+	argoCD.Spec.Prometheus.Route.TLS = nil
+	argoCD.Spec.Prometheus.Route.WildcardPolicy = nil
+	assert.NilError(t, r.reconcilePrometheusRoute(argoCD))
+	err := r.client.Get(ctx, types.NamespacedName{Name: testArgoCDName + "-prometheus", Namespace: testNamespace}, loaded)
+	assert.Assert(t, errors.IsNotFound(err))
+	assert.NilError(t, r.reconcilePrometheusRoute(argoCD))
+	loaded = &routev1.Route{}
+	assert.NilError(t, r.client.Get(ctx, types.NamespacedName{Name: testArgoCDName + "-prometheus", Namespace: testNamespace}, loaded))
+	assert.Equal(t, loaded.Spec.Host, "")
+	assert.Equal(t, loaded.Spec.Path, "")
+	assert.Assert(t, loaded.Spec.TLS == nil)
+	assert.Equal(t, loaded.Spec.WildcardPolicy, routev1.WildcardPolicyNone)
+
+	// A generated host is kept.
+	loaded.Spec.Host = testArgoCDName + "-prometheus-" + testNamespace + ".apps.example.com"
+	assert.NilError(t, r.client.Update(ctx, loaded))
+	assert.NilError(t, r.reconcilePrometheusRoute(argoCD))
+	assert.NilError(t, r.client.Get(ctx, types.NamespacedName{Name: testArgoCDName + "-prometheus", Namespace: testNamespace}, loaded))
+	assert.Equal(t, loaded.Spec.Host, testArgoCDName+"-prometheus-"+testNamespace+".apps.example.com")
+
+	argoCD.Spec.Prometheus.Route.Enabled = false
+	assert.NilError(t, r.reconcilePrometheusRoute(argoCD))
+	err = r.client.Get(ctx, types.NamespacedName{Name: testArgoCDName + "-prometheus", Namespace: testNamespace}, loaded)
+	assert.Assert(t, errors.IsNotFound(err))
+}
+
+func TestReconcileArgoCD_reconcileServerRoute_reencrypt(t *testing.T) {
+	routeAPIFound = true
+	ctx := context.Background()
+	logf.SetLogger(logf.ZapLogger(true))
+	argoCD := makeArgoCD(func(a *argov1alpha1.ArgoCD) {
+		a.Spec.Server.Route.Enabled = true
+		a.Spec.Server.Route.TLS = &routev1.TLSConfig{Termination: routev1.TLSTerminationReencrypt}
+	})
+	r := makeReconciler(t, argoCD, argoCD)
+
+	assert.NilError(t, r.reconcileServerRoute(argoCD))
+
+	loaded := &routev1.Route{}
+	assert.NilError(t, r.client.Get(ctx, types.NamespacedName{Name: testArgoCDName + "-server", Namespace: testNamespace}, loaded))
+	assert.Equal(t, loaded.Spec.TLS.Termination, routev1.TLSTerminationReencrypt)
+	assert.Equal(t, loaded.Spec.Port.TargetPort, intstr.FromString("https"))
+
+	// An edge terminated Route forwards plain HTTP to an insecure server.
+	argoCD.Spec.Server.Insecure = true
+	argoCD.Spec.Server.Route.TLS = &routev1.TLSConfig{Termination: routev1.TLSTerminationEdge}
+	assert.NilError(t, r.reconcileServerRoute(argoCD))
+
+	loaded = &routev1.Route{}
+	assert.NilError(t, r.client.Get(ctx, types.NamespacedName{Name: testArgoCDName + "-server", Namespace: testNamespace}, loaded))
+	assert.Equal(t, loaded.Spec.Port.TargetPort, intstr.FromString("http"))
+}
+
+func TestReconcileArgoCD_reconcileServerRoute_metadata(t *testing.T) {
+	routeAPIFound = true
+	ctx := context.Background()
+	logf.SetLogger(logf.ZapLogger(true))
+	argoCD := makeArgoCD(func(a *argov1alpha1.ArgoCD) {
+		a.Spec.Server.Route.Enabled = true
+		a.Spec.Server.Route.Labels = map[string]string{"example.com/external-dns": "public"}
+		a.Spec.Server.Route.Annotations = map[string]string{"example.com/waf-policy": "strict"}
+	})
+	r := makeReconciler(t, argoCD, argoCD)
+	key := types.NamespacedName{Name: testArgoCDName + "-server", Namespace: testNamespace}
+
+	assert.NilError(t, r.reconcileServerRoute(argoCD))
+
+	loaded := &routev1.Route{}
+	assert.NilError(t, r.client.Get(ctx, key, loaded))
+	assert.Equal(t, loaded.Labels["example.com/external-dns"], "public")
+	assert.Equal(t, loaded.Labels["app.kubernetes.io/name"], testArgoCDName+"-server")
+	assert.Equal(t, loaded.Annotations["example.com/waf-policy"], "strict")
+
+	// Labels and annotations added by others are kept, the ones removed from the spec are removed.
+	loaded.Labels["example.com/team"] = "a"
+	loaded.Annotations["openshift.io/host.generated"] = "true"
+	assert.NilError(t, r.client.Update(ctx, loaded))
+	argoCD.Spec.Server.Route.Labels = nil
+	argoCD.Spec.Server.Route.Annotations = map[string]string{"example.com/waf-policy": "relaxed"}
+	assert.NilError(t, r.reconcileServerRoute(argoCD))
+
+	loaded = &routev1.Route{}
+	assert.NilError(t, r.client.Get(ctx, key, loaded))
+	_, ok := loaded.Labels["example.com/external-dns"]
+	assert.Assert(t, !ok)
+	assert.Equal(t, loaded.Labels["example.com/team"], "a")
+	assert.Equal(t, loaded.Annotations["example.com/waf-policy"], "relaxed")
+	assert.Equal(t, loaded.Annotations["openshift.io/host.generated"], "true")
+}
+
+func TestReconcileArgoCD_reconcileServerRoute_externalDNS(t *testing.T) {
+	routeAPIFound = true
+	ctx := context.Background()
+	argoCD := makeArgoCD(func(a *argov1alpha1.ArgoCD) {
+		a.Spec.Server.Host = "argocd.example.com"
+		a.Spec.Server.Route.Enabled = true
+		a.Spec.Server.ExternalDNS = argov1alpha1.ArgoCDExternalDNSSpec{
+			Enabled:   true,
+			Hostnames: []string{"cd.example.com"},
+			TTL:       300,
+		}
+	})
+	r := makeReconciler(t, argoCD, argoCD)
+
+	assert.NilError(t, r.reconcileServerRoute(argoCD))
+
+	loaded := &routev1.Route{}
+	key := types.NamespacedName{Name: testArgoCDName + "-server", Namespace: testNamespace}
+	assert.NilError(t, r.client.Get(ctx, key, loaded))
+	assert.Equal(t, loaded.Annotations[common.ArgoCDKeyExternalDNSHostname], "argocd.example.com,cd.example.com")
+	assert.Equal(t, loaded.Annotations[common.ArgoCDKeyExternalDNSTTL], "300")
+}
+
 func makeReconciler(t *testing.T, acd *argov1alpha1.ArgoCD, objs ...runtime.Object) *ReconcileArgoCD {
 	t.Helper()
 	s := scheme.Scheme
@@ -172,6 +316,8 @@ func makeReconciler(t *testing.T, acd *argov1alpha1.ArgoCD, objs ...runtime.Obje
 	cl := fake.NewFakeClient(objs...)
 	return &ReconcileArgoCD{
 		client: cl,
+		reader: cl,
+		cache:  cl,
 		scheme: s,
 	}
 }
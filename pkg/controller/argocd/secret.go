@@ -15,13 +15,16 @@
 package argocd
 
 import (
+	"bytes"
 	"context"
 	"crypto/rsa"
 	"crypto/sha256"
 	"crypto/x509"
 	"encoding/json"
 	"fmt"
+	"net"
 	"os"
+	"reflect"
 	"sort"
 	"strings"
 	"time"
@@ -34,8 +37,10 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
@@ -53,20 +58,6 @@ func hasArgoAdminPasswordChanged(actual *corev1.Secret, expected *corev1.Secret)
 	return false
 }
 
-// hasArgoTLSChanged will return true if the Argo TLS certificate or key have changed.
-func hasArgoTLSChanged(actual *corev1.Secret, expected *corev1.Secret) bool {
-	actualCert := string(actual.Data[common.ArgoCDKeyTLSCert])
-	actualKey := string(actual.Data[common.ArgoCDKeyTLSPrivateKey])
-	expectedCert := string(expected.Data[common.ArgoCDKeyTLSCert])
-	expectedKey := string(expected.Data[common.ArgoCDKeyTLSPrivateKey])
-
-	if actualCert != expectedCert || actualKey != expectedKey {
-		log.Info("tls secret has changed")
-		return true
-	}
-	return false
-}
-
 // nowBytes is a shortcut function to return the current date/time in RFC3339 format.
 func nowBytes() []byte {
 	return []byte(time.Now().UTC().Format(time.RFC3339))
@@ -106,36 +97,146 @@ func newCASecret(cr *argoprojv1a1.ArgoCD) (*corev1.Secret, error) {
 	return secret, nil
 }
 
-// newCertificateSecret creates a new secret using the given name suffix for the given TLS certificate.
-func newCertificateSecret(suffix string, caCert *x509.Certificate, caKey *rsa.PrivateKey, cr *argoprojv1a1.ArgoCD) (*corev1.Secret, error) {
-	secret := argoutil.NewTLSSecret(cr.ObjectMeta, suffix)
+// clusterServiceSuffixes are the name suffixes of the Services created for the components of an ArgoCD, which are
+// included in the certificate generated for the cluster.
+var clusterServiceSuffixes = []string{
+	"dex-server",
+	"grafana",
+	"metrics",
+	"redis",
+	"redis-ha",
+	"redis-ha-haproxy",
+	"repo-server",
+	"server",
+	"server-metrics",
+}
 
-	key, err := argoutil.NewPrivateKey()
-	if err != nil {
-		return nil, err
+// getServiceDNSNames will return the short, namespaced and fully qualified DNS names of the given Service in the
+// namespace of the given ArgoCD.
+func getServiceDNSNames(service string, cr *argoprojv1a1.ArgoCD) []string {
+	return []string{
+		service,
+		fmt.Sprintf("%s.%s", service, cr.Namespace),
+		fmt.Sprintf("%s.%s.svc", service, cr.Namespace),
+		fmt.Sprintf("%s.%s.svc.cluster.local", service, cr.Namespace),
 	}
+}
 
-	cfg := &tlsutil.CertConfig{
-		CertName:     secret.Name,
-		CertType:     tlsutil.ClientAndServingCert,
-		CommonName:   secret.Name,
-		Organization: []string{cr.ObjectMeta.Namespace},
+// getCertificateDNSNames will return the given DNS names followed by the extra SANs of the given ArgoCD, without
+// duplicates.
+func getCertificateDNSNames(cr *argoprojv1a1.ArgoCD, dnsNames ...string) []string {
+	var result []string
+	seen := make(map[string]bool)
+	for _, name := range append(dnsNames, cr.Spec.TLS.ExtraSANs...) {
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		result = append(result, name)
 	}
+	return result
+}
 
+// getClusterCertificateDNSNames will return the DNS names of the certificate generated for the given ArgoCD.
+func getClusterCertificateDNSNames(cr *argoprojv1a1.ArgoCD) []string {
 	dnsNames := []string{
 		cr.ObjectMeta.Name,
 		nameWithSuffix("grpc", cr),
 		fmt.Sprintf("%s.%s.svc.cluster.local", cr.ObjectMeta.Name, cr.ObjectMeta.Namespace),
 	}
 
-	if cr.Spec.Grafana.Enabled {
+	for _, suffix := range clusterServiceSuffixes {
+		dnsNames = append(dnsNames, getServiceDNSNames(nameWithSuffix(suffix, cr), cr)...)
+	}
+
+	if isManagedGrafanaEnabled(cr) {
 		dnsNames = append(dnsNames, getGrafanaHost(cr))
 	}
 	if cr.Spec.Prometheus.Enabled {
 		dnsNames = append(dnsNames, getPrometheusHost(cr))
 	}
+	return getCertificateDNSNames(cr, dnsNames...)
+}
+
+// getClusterCARenewalTime will return the time at which the CA in the given Secret is due for rotation. The zero time
+// is returned when the certificate cannot be parsed.
+func getClusterCARenewalTime(secret *corev1.Secret) time.Time {
+	cert, err := argoutil.ParsePEMEncodedCert(secret.Data[corev1.TLSCertKey])
+	if err != nil {
+		return time.Time{}
+	}
+	return cert.NotAfter.Add(-common.ArgoCDDefaultAutoTLSRenewBefore)
+}
+
+// getClusterCARenewalDelay will return the time until the CA generated by the operator for the given ArgoCD is due for
+// rotation, or zero when the operator does not manage the CA.
+func (r *ReconcileArgoCD) getClusterCARenewalDelay(cr *argoprojv1a1.ArgoCD) time.Duration {
+	secret := argoutil.NewSecretWithSuffix(cr.ObjectMeta, "ca")
+	if !argoutil.IsObjectFound(r.client, cr.Namespace, secret.Name, secret) || !metav1.IsControlledBy(secret, cr) {
+		return 0
+	}
+
+	if delay := time.Until(getClusterCARenewalTime(secret)); delay > 0 {
+		return delay
+	}
+	return 0
+}
+
+// isCertificateSignedBy will return true if the certificate in the given Secret is signed by the given CA.
+func isCertificateSignedBy(secret *corev1.Secret, caCert *x509.Certificate) bool {
+	cert, err := argoutil.ParsePEMEncodedCert(secret.Data[corev1.TLSCertKey])
+	if err != nil {
+		return false
+	}
+	return cert.CheckSignatureFrom(caCert) == nil
+}
+
+// hasCertificateDNSNames will return true if the certificate in the given Secret was issued for all of the given
+// names, as IP address SANs for the IP addresses and as DNS name SANs otherwise.
+func hasCertificateDNSNames(secret *corev1.Secret, dnsNames []string) bool {
+	cert, err := argoutil.ParsePEMEncodedCert(secret.Data[corev1.TLSCertKey])
+	if err != nil {
+		return false
+	}
+	for _, name := range dnsNames {
+		if ip := net.ParseIP(name); ip != nil {
+			if !containsIP(cert.IPAddresses, ip) {
+				return false
+			}
+		} else if !containsString(cert.DNSNames, name) {
+			return false
+		}
+	}
+	return true
+}
+
+// containsIP will return true if the given IP addresses contain the given IP address.
+func containsIP(ips []net.IP, ip net.IP) bool {
+	for _, i := range ips {
+		if i.Equal(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// newCertificateSecret creates a new secret using the given name suffix for the given TLS certificate.
+func newCertificateSecret(suffix string, caCert *x509.Certificate, caKey *rsa.PrivateKey, cr *argoprojv1a1.ArgoCD) (*corev1.Secret, error) {
+	secret := argoutil.NewTLSSecret(cr.ObjectMeta, suffix)
+
+	key, err := argoutil.NewPrivateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &tlsutil.CertConfig{
+		CertName:     secret.Name,
+		CertType:     tlsutil.ClientAndServingCert,
+		CommonName:   secret.Name,
+		Organization: []string{cr.ObjectMeta.Namespace},
+	}
 
-	cert, err := argoutil.NewSignedCertificate(cfg, dnsNames, key, caCert, caKey)
+	cert, err := argoutil.NewSignedCertificate(cfg, getClusterCertificateDNSNames(cr), key, caCert, caKey)
 	if err != nil {
 		return nil, err
 	}
@@ -148,6 +249,119 @@ func newCertificateSecret(suffix string, caCert *x509.Certificate, caKey *rsa.Pr
 	return secret, nil
 }
 
+// getServerTLSSecret will return the Secret holding the certificate for the Argo CD Server of the given ArgoCD, either
+// the existing Secret named in the ArgoCD spec or the Secret generated by the operator. It returns nil when the Secret
+// is not found or does not hold a certificate and private key.
+func (r *ReconcileArgoCD) getServerTLSSecret(cr *argoprojv1a1.ArgoCD) *corev1.Secret {
+	name := cr.Spec.Server.TLS.SecretName
+	if name == "" {
+		name = argoutil.NewSecretWithSuffix(cr.ObjectMeta, "tls").Name
+	}
+
+	secret := argoutil.NewSecretWithName(cr.ObjectMeta, name)
+	if !argoutil.IsObjectFound(r.client, cr.Namespace, secret.Name, secret) {
+		log.Info(fmt.Sprintf("tls secret [%s] not found for argo server", secret.Name))
+		return nil
+	}
+
+	if len(secret.Data[corev1.TLSCertKey]) == 0 || len(secret.Data[corev1.TLSPrivateKeyKey]) == 0 {
+		log.Info(fmt.Sprintf("tls secret [%s] for argo server must contain both %s and %s", secret.Name, corev1.TLSCertKey, corev1.TLSPrivateKeyKey))
+		return nil
+	}
+	return secret
+}
+
+// getServerTLSSecretCondition will return the ServerTLSSecretValid condition for the existing TLS Secret named in the
+// spec of the given ArgoCD, or nil when the operator generates the Secret.
+func (r *ReconcileArgoCD) getServerTLSSecretCondition(cr *argoprojv1a1.ArgoCD) *argoprojv1a1.ArgoCDCondition {
+	name := cr.Spec.Server.TLS.SecretName
+	if name == "" {
+		return nil
+	}
+
+	secret := argoutil.NewSecretWithName(cr.ObjectMeta, name)
+	if !argoutil.IsObjectFound(r.client, cr.Namespace, secret.Name, secret) {
+		return &argoprojv1a1.ArgoCDCondition{
+			Type:    argoprojv1a1.ArgoCDConditionTypeServerTLSSecretValid,
+			Status:  corev1.ConditionFalse,
+			Reason:  "SecretNotFound",
+			Message: fmt.Sprintf("tls secret [%s] not found for argo server, the argo secret is not reconciled", name),
+		}
+	}
+
+	if len(secret.Data[corev1.TLSCertKey]) == 0 || len(secret.Data[corev1.TLSPrivateKeyKey]) == 0 {
+		return &argoprojv1a1.ArgoCDCondition{
+			Type:   argoprojv1a1.ArgoCDConditionTypeServerTLSSecretValid,
+			Status: corev1.ConditionFalse,
+			Reason: "SecretInvalid",
+			Message: fmt.Sprintf("tls secret [%s] for argo server must contain both %s and %s, the argo secret is not reconciled",
+				name, corev1.TLSCertKey, corev1.TLSPrivateKeyKey),
+		}
+	}
+
+	return &argoprojv1a1.ArgoCDCondition{
+		Type:   argoprojv1a1.ArgoCDConditionTypeServerTLSSecretValid,
+		Status: corev1.ConditionTrue,
+		Reason: "SecretValid",
+	}
+}
+
+// getSecretKeyRefKey will return the key of the value in the Secret referenced for the given key of the Argo CD Secret.
+func getSecretKeyRefKey(ref argoprojv1a1.ArgoCDSecretKeySpec) string {
+	if ref.SecretKey != "" {
+		return ref.SecretKey
+	}
+	return ref.Key
+}
+
+// isArgoSecretKeyReferenced will return true if the given key of the Argo CD Secret is sourced from another Secret for
+// the given ArgoCD, in which case the operator no longer manages its value.
+func isArgoSecretKeyReferenced(cr *argoprojv1a1.ArgoCD, key string) bool {
+	for _, ref := range cr.Spec.SecretKeys {
+		if ref.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// getArgoSecretKeyValues will return the values of the keys of the Argo CD Secret that are sourced from other Secrets
+// for the given ArgoCD. Keys whose Secret or value is not found are left out, so that their current value is kept.
+func (r *ReconcileArgoCD) getArgoSecretKeyValues(cr *argoprojv1a1.ArgoCD) map[string][]byte {
+	values := make(map[string][]byte)
+	for _, ref := range cr.Spec.SecretKeys {
+		secret := argoutil.NewSecretWithName(cr.ObjectMeta, ref.SecretName)
+		if !argoutil.IsObjectFound(r.client, cr.Namespace, secret.Name, secret) {
+			log.Info(fmt.Sprintf("secret [%s] not found for argo secret key [%s]", secret.Name, ref.Key))
+			continue
+		}
+		value, ok := secret.Data[getSecretKeyRefKey(ref)]
+		if !ok {
+			log.Info(fmt.Sprintf("secret [%s] has no key [%s] for argo secret key [%s]", secret.Name, getSecretKeyRefKey(ref), ref.Key))
+			continue
+		}
+		values[ref.Key] = value
+	}
+	return values
+}
+
+// applyArgoSecretKeyValues will copy the values of the keys sourced from other Secrets for the given ArgoCD to the
+// given Argo CD Secret. It returns true if any value has changed.
+func (r *ReconcileArgoCD) applyArgoSecretKeyValues(cr *argoprojv1a1.ArgoCD, secret *corev1.Secret) bool {
+	changed := false
+	for key, value := range r.getArgoSecretKeyValues(cr) {
+		if bytes.Equal(secret.Data[key], value) {
+			continue
+		}
+		secret.Data[key] = value
+		if key == common.ArgoCDKeyAdminPassword && !isArgoSecretKeyReferenced(cr, common.ArgoCDKeyAdminPasswordMTime) {
+			secret.Data[common.ArgoCDKeyAdminPasswordMTime] = nowBytes()
+		}
+		changed = true
+	}
+	return changed
+}
+
 // reconcileArgoSecret will ensure that the Argo CD Secret is present.
 func (r *ReconcileArgoCD) reconcileArgoSecret(cr *argoprojv1a1.ArgoCD) error {
 	clusterSecret := argoutil.NewSecretWithSuffix(cr.ObjectMeta, "cluster")
@@ -158,9 +372,9 @@ func (r *ReconcileArgoCD) reconcileArgoSecret(cr *argoprojv1a1.ArgoCD) error {
 		return nil
 	}
 
-	tlsSecret := argoutil.NewSecretWithSuffix(cr.ObjectMeta, "tls")
-	if !argoutil.IsObjectFound(r.client, cr.Namespace, tlsSecret.Name, tlsSecret) {
-		log.Info(fmt.Sprintf("tls secret [%s] not found, waiting to reconcile argo secret [%s]", tlsSecret.Name, secret.Name))
+	tlsSecret := r.getServerTLSSecret(cr)
+	if tlsSecret == nil {
+		log.Info(fmt.Sprintf("tls secret not found, waiting to reconcile argo secret [%s]", secret.Name))
 		return nil
 	}
 
@@ -187,6 +401,16 @@ func (r *ReconcileArgoCD) reconcileArgoSecret(cr *argoprojv1a1.ArgoCD) error {
 		common.ArgoCDKeyTLSPrivateKey:      tlsSecret.Data[common.ArgoCDKeyTLSPrivateKey],
 	}
 
+	for key, value := range r.getDexStaticClientSecrets(cr) {
+		secret.Data[key] = value
+	}
+
+	if dexSecret := r.getExternalDexClientSecret(cr); dexSecret != nil {
+		secret.Data[common.ArgoCDKeyOIDCDexClientSecret] = dexSecret.Data[common.ArgoCDKeyDexExternalClientSecret]
+	}
+
+	r.applyArgoSecretKeyValues(cr, secret)
+
 	if err := controllerutil.SetControllerReference(cr, secret, r.scheme); err != nil {
 		return err
 	}
@@ -217,9 +441,10 @@ func (r *ReconcileArgoCD) reconcileClusterMainSecret(cr *argoprojv1a1.ArgoCD) er
 
 // reconcileClusterTLSSecret ensures the TLS Secret is created for the ArgoCD cluster.
 func (r *ReconcileArgoCD) reconcileClusterTLSSecret(cr *argoprojv1a1.ArgoCD) error {
-	secret := argoutil.NewTLSSecret(cr.ObjectMeta, "tls")
-	if argoutil.IsObjectFound(r.client, cr.Namespace, secret.Name, secret) {
-		return nil // Secret found, do nothing
+	existing := argoutil.NewTLSSecret(cr.ObjectMeta, "tls")
+	found := argoutil.IsObjectFound(r.client, cr.Namespace, existing.Name, existing)
+	if found && !metav1.IsControlledBy(existing, cr) {
+		return nil // Secret not generated by the operator, do nothing
 	}
 
 	caSecret := argoutil.NewSecretWithSuffix(cr.ObjectMeta, "ca")
@@ -233,16 +458,26 @@ func (r *ReconcileArgoCD) reconcileClusterTLSSecret(cr *argoprojv1a1.ArgoCD) err
 		return err
 	}
 
+	if found && isCertificateSignedBy(existing, caCert) && hasCertificateDNSNames(existing, getClusterCertificateDNSNames(cr)) {
+		return nil // Secret found, signed by the CA and valid for all of the DNS names, do nothing
+	}
+
 	caKey, err := argoutil.ParsePEMEncodedPrivateKey(caSecret.Data[corev1.TLSPrivateKeyKey])
 	if err != nil {
 		return err
 	}
 
-	secret, err = newCertificateSecret("tls", caCert, caKey, cr)
+	secret, err := newCertificateSecret("tls", caCert, caKey, cr)
 	if err != nil {
 		return err
 	}
 
+	if found {
+		log.Info(fmt.Sprintf("reissuing certificate in secret [%s] for the CA and DNS names", existing.Name))
+		existing.Data = secret.Data
+		return r.client.Update(context.TODO(), existing)
+	}
+
 	if err := controllerutil.SetControllerReference(cr, secret, r.scheme); err != nil {
 		return err
 	}
@@ -250,11 +485,13 @@ func (r *ReconcileArgoCD) reconcileClusterTLSSecret(cr *argoprojv1a1.ArgoCD) err
 	return r.client.Create(context.TODO(), secret)
 }
 
-// reconcileClusterCASecret ensures the CA Secret is created for the ArgoCD cluster.
+// reconcileClusterCASecret ensures the CA Secret is created for the ArgoCD cluster, and that the generated CA is
+// rotated before it expires. The certificates signed by the previous CA are reissued by their reconcilers.
 func (r *ReconcileArgoCD) reconcileClusterCASecret(cr *argoprojv1a1.ArgoCD) error {
-	secret := argoutil.NewSecretWithSuffix(cr.ObjectMeta, "ca")
-	if argoutil.IsObjectFound(r.client, cr.Namespace, secret.Name, secret) {
-		return nil // Secret found, do nothing
+	existing := argoutil.NewSecretWithSuffix(cr.ObjectMeta, "ca")
+	found := argoutil.IsObjectFound(r.client, cr.Namespace, existing.Name, existing)
+	if found && (!metav1.IsControlledBy(existing, cr) || time.Now().Before(getClusterCARenewalTime(existing))) {
+		return nil // Secret found and not due for renewal, do nothing
 	}
 
 	secret, err := newCASecret(cr)
@@ -262,6 +499,12 @@ func (r *ReconcileArgoCD) reconcileClusterCASecret(cr *argoprojv1a1.ArgoCD) erro
 		return err
 	}
 
+	if found {
+		log.Info(fmt.Sprintf("rotating CA in secret [%s]", existing.Name))
+		existing.Data = secret.Data
+		return r.client.Update(context.TODO(), existing)
+	}
+
 	if err := controllerutil.SetControllerReference(cr, secret, r.scheme); err != nil {
 		return err
 	}
@@ -297,7 +540,7 @@ func (r *ReconcileArgoCD) reconcileClusterSecrets(cr *argoprojv1a1.ArgoCD) error
 func (r *ReconcileArgoCD) reconcileExistingArgoSecret(cr *argoprojv1a1.ArgoCD, secret *corev1.Secret, clusterSecret *corev1.Secret, tlsSecret *corev1.Secret) error {
 	changed := false
 
-	if hasArgoAdminPasswordChanged(secret, clusterSecret) {
+	if !isArgoSecretKeyReferenced(cr, common.ArgoCDKeyAdminPassword) && hasArgoAdminPasswordChanged(secret, clusterSecret) {
 		hashedPassword, err := argopass.HashPassword(string(clusterSecret.Data[common.ArgoCDKeyAdminPassword]))
 		if err != nil {
 			return err
@@ -308,9 +551,42 @@ func (r *ReconcileArgoCD) reconcileExistingArgoSecret(cr *argoprojv1a1.ArgoCD, s
 		changed = true
 	}
 
-	if hasArgoTLSChanged(secret, tlsSecret) {
-		secret.Data[common.ArgoCDKeyTLSCert] = tlsSecret.Data[common.ArgoCDKeyTLSCert]
-		secret.Data[common.ArgoCDKeyTLSPrivateKey] = tlsSecret.Data[common.ArgoCDKeyTLSPrivateKey]
+	for _, key := range []string{common.ArgoCDKeyTLSCert, common.ArgoCDKeyTLSPrivateKey} {
+		if !isArgoSecretKeyReferenced(cr, key) && !bytes.Equal(secret.Data[key], tlsSecret.Data[key]) {
+			log.Info(fmt.Sprintf("tls secret %s has changed", key))
+			secret.Data[key] = tlsSecret.Data[key]
+			changed = true
+		}
+	}
+
+	clientSecrets := r.getDexStaticClientSecrets(cr)
+	for key := range secret.Data {
+		if _, ok := clientSecrets[key]; !ok && isDexStaticClientSecretKey(key) && !isArgoSecretKeyReferenced(cr, key) {
+			delete(secret.Data, key)
+			changed = true
+		}
+	}
+	for key, value := range clientSecrets {
+		if !isArgoSecretKeyReferenced(cr, key) && !bytes.Equal(secret.Data[key], value) {
+			secret.Data[key] = value
+			changed = true
+		}
+	}
+
+	if !isArgoSecretKeyReferenced(cr, common.ArgoCDKeyOIDCDexClientSecret) {
+		if dexSecret := r.getExternalDexClientSecret(cr); dexSecret != nil {
+			value := dexSecret.Data[common.ArgoCDKeyDexExternalClientSecret]
+			if !bytes.Equal(secret.Data[common.ArgoCDKeyOIDCDexClientSecret], value) {
+				secret.Data[common.ArgoCDKeyOIDCDexClientSecret] = value
+				changed = true
+			}
+		} else if _, ok := secret.Data[common.ArgoCDKeyOIDCDexClientSecret]; ok {
+			delete(secret.Data, common.ArgoCDKeyOIDCDexClientSecret)
+			changed = true
+		}
+	}
+
+	if r.applyArgoSecretKeyValues(cr, secret) {
 		changed = true
 	}
 
@@ -330,7 +606,7 @@ func (r *ReconcileArgoCD) reconcileExistingArgoSecret(cr *argoprojv1a1.ArgoCD, s
 
 // reconcileGrafanaSecret will ensure that the Grafana Secret is present.
 func (r *ReconcileArgoCD) reconcileGrafanaSecret(cr *argoprojv1a1.ArgoCD) error {
-	if !cr.Spec.Grafana.Enabled {
+	if !isManagedGrafanaEnabled(cr) {
 		return nil // Grafana not enabled, do nothing.
 	}
 
@@ -394,25 +670,25 @@ func (r *ReconcileArgoCD) reconcileGrafanaSecret(cr *argoprojv1a1.ArgoCD) error
 func (r *ReconcileArgoCD) reconcileClusterPermissionsSecret(cr *argoprojv1a1.ArgoCD) error {
 	var clusterConfigInstance bool
 	secret := argoutil.NewSecretWithSuffix(cr.ObjectMeta, "default-cluster-config")
-	secret.Labels[common.ArgoCDSecretTypeLabel] = "cluster"
+	secret.Labels[common.ArgoCDSecretTypeLabel] = common.ArgoCDSecretTypeCluster
 	dataBytes, _ := json.Marshal(map[string]interface{}{
 		"tlsClientConfig": map[string]interface{}{
 			"insecure": false,
 		},
 	})
 
-	namespaceList := corev1.NamespaceList{}
-	listOption := client.MatchingLabels{
-		common.ArgoCDManagedByLabel: cr.Namespace,
-	}
-	if err := r.client.List(context.TODO(), &namespaceList, listOption); err != nil {
+	namespaceList, rejectedList, err := r.getManagedNamespaces(cr)
+	if err != nil {
 		return err
 	}
 
-	var namespaces []string
-	for _, namespace := range namespaceList.Items {
+	var namespaces, rejected []string
+	for _, namespace := range namespaceList {
 		namespaces = append(namespaces, namespace.Name)
 	}
+	for _, namespace := range rejectedList {
+		rejected = append(rejected, namespace.Name)
+	}
 
 	sort.Strings(namespaces)
 
@@ -447,7 +723,13 @@ func (r *ReconcileArgoCD) reconcileClusterPermissionsSecret(cr *argoprojv1a1.Arg
 					return err
 				}
 			} else {
-				ns := strings.Split(string(s.Data["namespaces"]), ",")
+				var ns []string
+				for _, n := range strings.Split(string(s.Data["namespaces"]), ",") {
+					// drop the namespaces that the ArgoCD instance is no longer allowed to manage
+					if !containsString(rejected, strings.TrimSpace(n)) {
+						ns = append(ns, n)
+					}
+				}
 				for _, n := range namespaces {
 					if !containsString(ns, strings.TrimSpace(n)) {
 						ns = append(ns, strings.TrimSpace(n))
@@ -471,6 +753,140 @@ func (r *ReconcileArgoCD) reconcileClusterPermissionsSecret(cr *argoprojv1a1.Arg
 	return r.client.Create(context.TODO(), secret)
 }
 
+// getRepoServerTLSValidity will return the validity of the repo-server certificate generated by the operator.
+func getRepoServerTLSValidity(cr *argoprojv1a1.ArgoCD) time.Duration {
+	if v := cr.Spec.Repo.AutoTLSCertificate.Validity; v != nil && v.Duration > 0 {
+		return v.Duration
+	}
+	return common.ArgoCDDuration365Days
+}
+
+// getRepoServerTLSRenewBefore will return how long before expiry the repo-server certificate generated by the
+// operator is renewed.
+func getRepoServerTLSRenewBefore(cr *argoprojv1a1.ArgoCD) time.Duration {
+	if rb := cr.Spec.Repo.AutoTLSCertificate.RenewBefore; rb != nil {
+		return rb.Duration
+	}
+	return common.ArgoCDDefaultAutoTLSRenewBefore
+}
+
+// newRepoServerTLSSecret creates a new argocd-repo-server-tls secret with a certificate for the repo-server Service
+// that is signed by the given CA.
+func newRepoServerTLSSecret(caCert *x509.Certificate, caKey *rsa.PrivateKey, cr *argoprojv1a1.ArgoCD) (*corev1.Secret, error) {
+	secret := argoutil.NewSecretWithName(cr.ObjectMeta, common.ArgoCDRepoServerTLSSecretName)
+	secret.Type = corev1.SecretTypeTLS
+
+	key, err := argoutil.NewPrivateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	service := nameWithSuffix("repo-server", cr)
+	cfg := &tlsutil.CertConfig{
+		CertName:     secret.Name,
+		CertType:     tlsutil.ServingCert,
+		CommonName:   service,
+		Organization: []string{cr.ObjectMeta.Namespace},
+	}
+
+	cert, err := argoutil.NewSignedCertificateWithValidity(cfg, getRepoServerTLSDNSNames(cr), key, caCert, caKey, getRepoServerTLSValidity(cr))
+	if err != nil {
+		return nil, err
+	}
+
+	secret.Data = map[string][]byte{
+		corev1.TLSCertKey:              argoutil.EncodeCertificatePEM(cert),
+		corev1.TLSPrivateKeyKey:        argoutil.EncodePrivateKeyPEM(key),
+		corev1.ServiceAccountRootCAKey: argoutil.EncodeCertificatePEM(caCert),
+	}
+
+	return secret, nil
+}
+
+// getRepoServerTLSDNSNames will return the DNS names of the repo-server certificate generated for the given ArgoCD.
+func getRepoServerTLSDNSNames(cr *argoprojv1a1.ArgoCD) []string {
+	return getCertificateDNSNames(cr, getServiceDNSNames(nameWithSuffix("repo-server", cr), cr)...)
+}
+
+// getRepoServerTLSRenewalTime will return the time at which the certificate in the given argocd-repo-server-tls
+// secret is due for renewal. The zero time is returned when the certificate cannot be parsed.
+func getRepoServerTLSRenewalTime(secret *corev1.Secret, cr *argoprojv1a1.ArgoCD) time.Time {
+	cert, err := argoutil.ParsePEMEncodedCert(secret.Data[corev1.TLSCertKey])
+	if err != nil {
+		return time.Time{}
+	}
+	return cert.NotAfter.Add(-getRepoServerTLSRenewBefore(cr))
+}
+
+// getRepoServerTLSRenewalDelay will return the time until the argocd-repo-server-tls certificate generated by the
+// operator is due for renewal, or zero when the operator does not manage the certificate.
+func (r *ReconcileArgoCD) getRepoServerTLSRenewalDelay(cr *argoprojv1a1.ArgoCD) time.Duration {
+	if cr.Spec.Repo.AutoTLS != common.ArgoCDRepoServerAutoTLSOperator {
+		return 0
+	}
+
+	secret := argoutil.NewSecretWithName(cr.ObjectMeta, common.ArgoCDRepoServerTLSSecretName)
+	if !argoutil.IsObjectFound(r.client, cr.Namespace, secret.Name, secret) || !metav1.IsControlledBy(secret, cr) {
+		return 0
+	}
+
+	if delay := time.Until(getRepoServerTLSRenewalTime(secret, cr)); delay > 0 {
+		return delay
+	}
+	return 0
+}
+
+// reconcileRepoServerGeneratedTLSSecret will ensure that the argocd-repo-server-tls secret is present when the
+// operator generates the repo-server certificate, and that the certificate is renewed before it expires. A secret
+// that is not owned by the ArgoCD is left untouched.
+func (r *ReconcileArgoCD) reconcileRepoServerGeneratedTLSSecret(cr *argoprojv1a1.ArgoCD) error {
+	existing := argoutil.NewSecretWithName(cr.ObjectMeta, common.ArgoCDRepoServerTLSSecretName)
+	found := argoutil.IsObjectFound(r.client, cr.Namespace, existing.Name, existing)
+	if found {
+		if !metav1.IsControlledBy(existing, cr) {
+			log.Info(fmt.Sprintf("secret [%s] is not owned by the ArgoCD, skipping certificate rotation", existing.Name))
+			return nil
+		}
+	}
+
+	caSecret := argoutil.NewSecretWithSuffix(cr.ObjectMeta, "ca")
+	caSecret, err := argoutil.FetchSecret(r.client, cr.ObjectMeta, caSecret.Name)
+	if err != nil {
+		return err
+	}
+
+	caCert, err := argoutil.ParsePEMEncodedCert(caSecret.Data[corev1.TLSCertKey])
+	if err != nil {
+		return err
+	}
+
+	if found && time.Now().Before(getRepoServerTLSRenewalTime(existing, cr)) && isCertificateSignedBy(existing, caCert) &&
+		hasCertificateDNSNames(existing, getRepoServerTLSDNSNames(cr)) {
+		return nil // Certificate is signed by the CA and not due for renewal, do nothing
+	}
+
+	caKey, err := argoutil.ParsePEMEncodedPrivateKey(caSecret.Data[corev1.TLSPrivateKeyKey])
+	if err != nil {
+		return err
+	}
+
+	secret, err := newRepoServerTLSSecret(caCert, caKey, cr)
+	if err != nil {
+		return err
+	}
+
+	if found {
+		log.Info(fmt.Sprintf("renewing certificate in secret [%s]", existing.Name))
+		existing.Data = secret.Data
+		return r.client.Update(context.TODO(), existing)
+	}
+
+	if err := controllerutil.SetControllerReference(cr, secret, r.scheme); err != nil {
+		return err
+	}
+	return r.client.Create(context.TODO(), secret)
+}
+
 // reconcileRepoServerTLSSecret checks whether the argocd-repo-server-tls secret
 // has changed since our last reconciliation loop. It does so by comparing the
 // checksum of tls.crt and tls.key in the status of the ArgoCD CR against the
@@ -481,6 +897,12 @@ func (r *ReconcileArgoCD) reconcileRepoServerTLSSecret(cr *argoprojv1a1.ArgoCD)
 
 	log.Info("reconciling repo-server TLS secret")
 
+	if cr.Spec.Repo.AutoTLS == common.ArgoCDRepoServerAutoTLSOperator {
+		if err := r.reconcileRepoServerGeneratedTLSSecret(cr); err != nil {
+			return err
+		}
+	}
+
 	tlsSecretName := types.NamespacedName{Namespace: cr.Namespace, Name: common.ArgoCDRepoServerTLSSecretName}
 	err := r.client.Get(context.TODO(), tlsSecretName, &tlsSecretObj)
 	if err != nil {
@@ -509,7 +931,7 @@ func (r *ReconcileArgoCD) reconcileRepoServerTLSSecret(cr *argoprojv1a1.ArgoCD)
 		// cost of a possibly missed restart when we cannot update the status
 		// field of the resource.
 		cr.Status.RepoTLSChecksum = sha256sum
-		err = r.client.Status().Update(context.TODO(), cr)
+		err = r.updateArgoCDStatus(cr)
 		if err != nil {
 			return err
 		}
@@ -534,6 +956,15 @@ func (r *ReconcileArgoCD) reconcileRepoServerTLSSecret(cr *argoprojv1a1.ArgoCD)
 		if err != nil {
 			return err
 		}
+
+		// Trigger rollout of ApplicationSet controller
+		if cr.Spec.ApplicationSet != nil {
+			appsetDepl := newDeploymentWithSuffix("applicationset-controller", "controller", cr)
+			err = r.triggerRollout(appsetDepl, "repo.tls.cert.changed")
+			if err != nil {
+				return err
+			}
+		}
 	}
 
 	return nil
@@ -545,9 +976,212 @@ func (r *ReconcileArgoCD) reconcileSecrets(cr *argoprojv1a1.ArgoCD) error {
 		return err
 	}
 
+	if err := r.reconcileDexStaticClientSecrets(cr); err != nil {
+		return err
+	}
+
 	if err := r.reconcileArgoSecret(cr); err != nil {
 		return err
 	}
 
+	if err := r.reconcileHelmOCIRegistrySecrets(cr); err != nil {
+		return err
+	}
+
+	if err := r.reconcileCredentialSecrets(cr); err != nil {
+		return err
+	}
+
+	if err := r.reconcileDiscoveredClusterSecrets(cr); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// reconcileCredentialSecrets will ensure that the pre-existing Secrets selected for the given ArgoCD are labeled
+// with the Argo CD secret type, so that Argo CD uses their content without the operator owning it. Secrets that
+// are no longer selected are released.
+func (r *ReconcileArgoCD) reconcileCredentialSecrets(cr *argoprojv1a1.ArgoCD) error {
+	selectors := []struct {
+		secretType string
+		selector   *metav1.LabelSelector
+	}{
+		{common.ArgoCDSecretTypeCluster, cr.Spec.CredentialSecrets.Clusters},
+		{common.ArgoCDSecretTypeRepository, cr.Spec.CredentialSecrets.Repositories},
+		{common.ArgoCDSecretTypeRepoCreds, cr.Spec.CredentialSecrets.RepositoryTemplates},
+	}
+
+	selected := make(map[string]bool)
+	for _, s := range selectors {
+		if s.selector == nil {
+			continue
+		}
+		selector, err := metav1.LabelSelectorAsSelector(s.selector)
+		if err != nil {
+			return fmt.Errorf("invalid %s credential secrets selector: %w", s.secretType, err)
+		}
+
+		secrets := &corev1.SecretList{}
+		if err := r.client.List(context.TODO(), secrets, client.InNamespace(cr.Namespace), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+			return err
+		}
+		for i := range secrets.Items {
+			secret := &secrets.Items[i]
+			if secret.Labels[common.ArgoCDKeyManagedBy] == cr.Name || selected[secret.Name] {
+				continue // Secret managed by the operator or already selected, leave it alone...
+			}
+			selected[secret.Name] = true
+			if !setCredentialSecretLabels(secret, s.secretType, cr) {
+				continue
+			}
+			log.Info(fmt.Sprintf("labeling secret %s with %s credentials type", secret.Name, s.secretType))
+			if err := r.client.Update(context.TODO(), secret); err != nil {
+				return err
+			}
+		}
+	}
+
+	secrets := &corev1.SecretList{}
+	if err := r.client.List(context.TODO(), secrets, client.InNamespace(cr.Namespace), client.MatchingLabels{
+		common.ArgoCDCredentialsForLabel: cr.Name,
+	}); err != nil {
+		return err
+	}
+	for i := range secrets.Items {
+		secret := &secrets.Items[i]
+		if selected[secret.Name] {
+			continue
+		}
+		setCredentialSecretLabels(secret, "", cr)
+		log.Info(fmt.Sprintf("releasing secret %s that is no longer selected", secret.Name))
+		if err := r.client.Update(context.TODO(), secret); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setCredentialSecretLabels will label the given pre-existing Secret with the given Argo CD secret type, or release
+// it when the secret type is empty. The labels added by the operator are recorded, so that a secret type label set by
+// the owner of the Secret is neither overwritten nor removed. Returns true when the labels changed.
+func setCredentialSecretLabels(secret *corev1.Secret, secretType string, cr *argoprojv1a1.ArgoCD) bool {
+	if secret.Labels == nil {
+		secret.Labels = make(map[string]string)
+	}
+	if secret.Annotations == nil {
+		secret.Annotations = make(map[string]string)
+	}
+
+	if _, ok := secret.Annotations[common.ArgoCDManagedLabelsAnnotation]; !ok && secret.Labels[common.ArgoCDCredentialsForLabel] == cr.Name {
+		// Labeled before the labels were recorded, when the operator always set the secret type.
+		secret.Annotations[common.ArgoCDManagedLabelsAnnotation] = common.ArgoCDCredentialsForLabel + "," + common.ArgoCDSecretTypeLabel
+	}
+
+	desired := make(map[string]string)
+	if secretType != "" {
+		desired[common.ArgoCDCredentialsForLabel] = cr.Name
+		managed := strings.Split(secret.Annotations[common.ArgoCDManagedLabelsAnnotation], ",")
+		if _, ok := secret.Labels[common.ArgoCDSecretTypeLabel]; !ok || containsString(managed, common.ArgoCDSecretTypeLabel) {
+			desired[common.ArgoCDSecretTypeLabel] = secretType
+		}
+	}
+	return applyManagedKeys(secret.Labels, desired, secret.Annotations, common.ArgoCDManagedLabelsAnnotation)
+}
+
+// sanitizeSecretName will return the given value lower-cased, with any character that is not valid in a resource
+// name replaced by a dash.
+func sanitizeSecretName(value string) string {
+	name := strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '-'
+	}, strings.ToLower(value))
+	return strings.Trim(name, "-")
+}
+
+// getHelmOCIRegistrySecretName will return the name of the repository credentials Secret for the given registry. The
+// sanitized registry is truncated to fit the name limit and followed by a short hash of the registry, as registries
+// such as ghcr.io/x and ghcr-io/x would otherwise share the same name.
+func getHelmOCIRegistrySecretName(registry string, cr *argoprojv1a1.ArgoCD) string {
+	hash := fmt.Sprintf("%x", sha256.Sum256([]byte(registry)))[:8]
+	prefix := sanitizeSecretName(registry)
+	limit := validation.DNS1123SubdomainMaxLength - len(nameWithSuffix("helm-oci--"+hash, cr))
+	if limit < 0 {
+		limit = 0
+	}
+	if len(prefix) > limit {
+		prefix = strings.TrimRight(prefix[:limit], "-")
+	}
+	if prefix == "" {
+		return nameWithSuffix("helm-oci-"+hash, cr)
+	}
+	return nameWithSuffix("helm-oci-"+prefix+"-"+hash, cr)
+}
+
+// reconcileHelmOCIRegistrySecrets will ensure that a repository credentials Secret is present for each of the Helm
+// OCI registries of the given ArgoCD, and that Secrets for registries that have been removed are deleted.
+func (r *ReconcileArgoCD) reconcileHelmOCIRegistrySecrets(cr *argoprojv1a1.ArgoCD) error {
+	desired := make(map[string]bool)
+	for _, registry := range cr.Spec.HelmOCIRegistries {
+		credentials := &corev1.Secret{}
+		if err := argoutil.FetchObject(r.client, cr.Namespace, registry.CredentialsSecret, credentials); err != nil {
+			return fmt.Errorf("failed to get credentials secret %s for helm oci registry %s: %w", registry.CredentialsSecret, registry.Registry, err)
+		}
+
+		secret := argoutil.NewSecretWithName(cr.ObjectMeta, getHelmOCIRegistrySecretName(registry.Registry, cr))
+		secret.Labels[common.ArgoCDKeyComponent] = "helm-oci-registry"
+		secret.Labels[common.ArgoCDSecretTypeLabel] = common.ArgoCDSecretTypeRepoCreds
+		desired[secret.Name] = true
+
+		data := map[string][]byte{
+			"enableOCI": []byte("true"),
+			"password":  credentials.Data["password"],
+			"type":      []byte("helm"),
+			"url":       []byte(registry.Registry),
+			"username":  credentials.Data["username"],
+		}
+
+		if argoutil.IsObjectFound(r.client, cr.Namespace, secret.Name, secret) {
+			if !reflect.DeepEqual(secret.Data, data) {
+				secret.Data = data
+				if err := r.client.Update(context.TODO(), secret); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		secret.Data = data
+		if err := controllerutil.SetControllerReference(cr, secret, r.scheme); err != nil {
+			return err
+		}
+		if err := r.client.Create(context.TODO(), secret); err != nil {
+			return err
+		}
+	}
+
+	secrets := &corev1.SecretList{}
+	opts := []client.ListOption{
+		client.InNamespace(cr.Namespace),
+		client.MatchingLabels{
+			common.ArgoCDKeyComponent:    "helm-oci-registry",
+			common.ArgoCDKeyManagedBy:    cr.Name,
+			common.ArgoCDKeyPartOf:       common.ArgoCDAppName,
+			common.ArgoCDSecretTypeLabel: common.ArgoCDSecretTypeRepoCreds,
+		},
+	}
+	if err := r.client.List(context.TODO(), secrets, opts...); err != nil {
+		return err
+	}
+	for i := range secrets.Items {
+		if desired[secrets.Items[i].Name] {
+			continue
+		}
+		if err := r.client.Delete(context.TODO(), &secrets.Items[i]); err != nil {
+			return err
+		}
+	}
 	return nil
 }
@@ -2,13 +2,18 @@ package argocd
 
 import (
 	"context"
+	"crypto/rand"
 	"crypto/sha256"
+	"crypto/x509"
 	"fmt"
 	"gotest.tools/assert"
+	"math/big"
 	"os"
 	"reflect"
 	"sort"
+	"strings"
 	"testing"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/core/v1"
@@ -19,6 +24,7 @@ import (
 
 	"github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
 	argoprojv1alpha1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
 	"github.com/argoproj-labs/argocd-operator/pkg/controller/argoutil"
 )
 
@@ -242,3 +248,424 @@ func Test_ReconcileArgoCD_ClusterPermissionsSecret(t *testing.T) {
 	assert.NilError(t, r.reconcileClusterPermissionsSecret(a))
 	assert.ErrorContains(t, r.client.Get(context.TODO(), types.NamespacedName{Name: testSecret.Name, Namespace: testSecret.Namespace}, testSecret), "not found")
 }
+
+func Test_ReconcileArgoCD_HelmOCIRegistrySecrets(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	credentials := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "oci-credentials",
+			Namespace: testNamespace,
+		},
+		Data: map[string][]byte{
+			"username": []byte("user"),
+			"password": []byte("secret"),
+		},
+	}
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.HelmOCIRegistries = []argoprojv1alpha1.ArgoCDHelmOCIRegistrySpec{{
+			CredentialsSecret: "oci-credentials",
+			Registry:          "registry.example.com:5000/charts",
+		}}
+	})
+	r := makeTestReconciler(t, a, credentials)
+
+	assert.NilError(t, r.reconcileHelmOCIRegistrySecrets(a))
+
+	secret := &corev1.Secret{}
+	name := "argocd-helm-oci-registry-example-com-5000-charts-0f529d5a"
+	assert.Equal(t, getHelmOCIRegistrySecretName(a.Spec.HelmOCIRegistries[0].Registry, a), name)
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: testNamespace}, secret))
+	assert.Equal(t, secret.Labels[common.ArgoCDSecretTypeLabel], common.ArgoCDSecretTypeRepoCreds)
+	assert.DeepEqual(t, secret.Data, map[string][]byte{
+		"enableOCI": []byte("true"),
+		"password":  []byte("secret"),
+		"type":      []byte("helm"),
+		"url":       []byte("registry.example.com:5000/charts"),
+		"username":  []byte("user"),
+	})
+
+	a.Spec.HelmOCIRegistries = nil
+	assert.NilError(t, r.reconcileHelmOCIRegistrySecrets(a))
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: testNamespace}, secret)
+	assert.ErrorContains(t, err, "not found")
+}
+
+func Test_getHelmOCIRegistrySecretName(t *testing.T) {
+	a := makeTestArgoCD()
+
+	assert.Assert(t, getHelmOCIRegistrySecretName("ghcr.io/x", a) != getHelmOCIRegistrySecretName("ghcr-io/x", a))
+
+	name := getHelmOCIRegistrySecretName("registry.example.com/"+strings.Repeat("charts", 50), a)
+	assert.Equal(t, len(name), 253)
+	assert.Assert(t, strings.HasPrefix(name, "argocd-helm-oci-registry-example-com-chartscharts"))
+}
+
+func Test_ReconcileArgoCD_CredentialSecrets(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	external := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "external-repo",
+			Namespace: testNamespace,
+			Labels:    map[string]string{"external-secrets": "repo"},
+		},
+		Data: map[string][]byte{
+			"url": []byte("https://github.com/argoproj/argocd-example-apps"),
+		},
+	}
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.CredentialSecrets.Repositories = &metav1.LabelSelector{
+			MatchLabels: map[string]string{"external-secrets": "repo"},
+		}
+	})
+	r := makeTestReconciler(t, a, external)
+
+	assert.NilError(t, r.reconcileCredentialSecrets(a))
+
+	secret := &corev1.Secret{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: external.Name, Namespace: testNamespace}, secret))
+	assert.Equal(t, secret.Labels[common.ArgoCDSecretTypeLabel], common.ArgoCDSecretTypeRepository)
+	assert.Equal(t, secret.Labels[common.ArgoCDCredentialsForLabel], a.Name)
+	assert.Equal(t, len(secret.OwnerReferences), 0)
+
+	a.Spec.CredentialSecrets.Repositories = nil
+	assert.NilError(t, r.reconcileCredentialSecrets(a))
+
+	secret = &corev1.Secret{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: external.Name, Namespace: testNamespace}, secret))
+	_, ok := secret.Labels[common.ArgoCDSecretTypeLabel]
+	assert.Assert(t, !ok)
+	assert.Equal(t, secret.Labels["external-secrets"], "repo")
+}
+
+func Test_ReconcileArgoCD_CredentialSecrets_ownSecretType(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	external := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "external-repo",
+			Namespace: testNamespace,
+			Labels: map[string]string{
+				"external-secrets":           "repo",
+				common.ArgoCDSecretTypeLabel: common.ArgoCDSecretTypeRepoCreds,
+			},
+		},
+	}
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.CredentialSecrets.Repositories = &metav1.LabelSelector{
+			MatchLabels: map[string]string{"external-secrets": "repo"},
+		}
+	})
+	r := makeTestReconciler(t, a, external)
+	key := types.NamespacedName{Name: external.Name, Namespace: testNamespace}
+
+	// The secret type set on the Secret is neither overwritten nor removed.
+	assert.NilError(t, r.reconcileCredentialSecrets(a))
+
+	secret := &corev1.Secret{}
+	assert.NilError(t, r.client.Get(context.TODO(), key, secret))
+	assert.Equal(t, secret.Labels[common.ArgoCDSecretTypeLabel], common.ArgoCDSecretTypeRepoCreds)
+	assert.Equal(t, secret.Labels[common.ArgoCDCredentialsForLabel], a.Name)
+
+	a.Spec.CredentialSecrets.Repositories = nil
+	assert.NilError(t, r.reconcileCredentialSecrets(a))
+
+	secret = &corev1.Secret{}
+	assert.NilError(t, r.client.Get(context.TODO(), key, secret))
+	assert.Equal(t, secret.Labels[common.ArgoCDSecretTypeLabel], common.ArgoCDSecretTypeRepoCreds)
+	_, ok := secret.Labels[common.ArgoCDCredentialsForLabel]
+	assert.Assert(t, !ok)
+	_, ok = secret.Annotations[common.ArgoCDManagedLabelsAnnotation]
+	assert.Assert(t, !ok)
+}
+
+func Test_ReconcileArgoCD_DexStaticClientSecrets(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.Dex.StaticClients = []argoprojv1alpha1.ArgoCDDexStaticClientSpec{
+			{ID: "ci-system"},
+			{ID: "cli", Public: true},
+		}
+	})
+	r := makeTestReconciler(t, a)
+
+	assert.NilError(t, r.reconcileDexStaticClientSecrets(a))
+
+	secret := &corev1.Secret{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-dex-client-ci-system", Namespace: testNamespace}, secret))
+	clientSecret := secret.Data[common.ArgoCDKeyDexStaticClientSecret]
+	assert.Equal(t, len(clientSecret), common.ArgoCDDefaultOAuthClientSecretLength)
+
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-dex-client-cli", Namespace: testNamespace}, secret)
+	assert.ErrorContains(t, err, "not found")
+
+	// The generated client secret is kept across reconciliations.
+	assert.NilError(t, r.reconcileDexStaticClientSecrets(a))
+	assert.DeepEqual(t, r.getDexStaticClientSecrets(a), map[string][]byte{
+		"dex.ci-system.clientSecret": clientSecret,
+	})
+
+	a.Spec.Dex.StaticClients = nil
+	assert.NilError(t, r.reconcileDexStaticClientSecrets(a))
+	err = r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-dex-client-ci-system", Namespace: testNamespace}, secret)
+	assert.ErrorContains(t, err, "not found")
+}
+
+func Test_ReconcileArgoCD_RepoServerGeneratedTLSSecret(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.Repo.AutoTLS = common.ArgoCDRepoServerAutoTLSOperator
+	})
+	r := makeTestReconciler(t, a)
+	assert.NilError(t, r.reconcileClusterCASecret(a))
+
+	assert.NilError(t, r.reconcileRepoServerTLSSecret(a))
+
+	secret := &corev1.Secret{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: common.ArgoCDRepoServerTLSSecretName, Namespace: testNamespace}, secret))
+	assert.Equal(t, secret.Type, corev1.SecretTypeTLS)
+	assert.Assert(t, metav1.IsControlledBy(secret, a))
+	cert, err := argoutil.ParsePEMEncodedCert(secret.Data[corev1.TLSCertKey])
+	assert.NilError(t, err)
+	assert.DeepEqual(t, cert.DNSNames, []string{
+		"argocd-repo-server",
+		"argocd-repo-server.argocd",
+		"argocd-repo-server.argocd.svc",
+		"argocd-repo-server.argocd.svc.cluster.local",
+	})
+	assert.Assert(t, a.Status.RepoTLSChecksum != "")
+
+	// The certificate is kept while it is not due for renewal.
+	assert.Assert(t, r.getRepoServerTLSRenewalDelay(a) > 0)
+	checksum := a.Status.RepoTLSChecksum
+	assert.NilError(t, r.reconcileRepoServerTLSSecret(a))
+	assert.Equal(t, a.Status.RepoTLSChecksum, checksum)
+
+	// The certificate is renewed once it is within the renewal window.
+	a.Spec.Repo.AutoTLSCertificate.RenewBefore = &metav1.Duration{Duration: 2 * common.ArgoCDDuration365Days}
+	assert.Equal(t, r.getRepoServerTLSRenewalDelay(a), time.Duration(0))
+	a.Spec.Repo.AutoTLSCertificate.Validity = &metav1.Duration{Duration: 3 * common.ArgoCDDuration365Days}
+	assert.NilError(t, r.reconcileRepoServerTLSSecret(a))
+	assert.Assert(t, a.Status.RepoTLSChecksum != checksum)
+	assert.Assert(t, r.getRepoServerTLSRenewalDelay(a) > 0)
+}
+
+func Test_ReconcileArgoCD_ClusterCASecret_rotation(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.Repo.AutoTLS = common.ArgoCDRepoServerAutoTLSOperator
+	})
+	r := makeTestReconciler(t, a)
+	assert.NilError(t, r.reconcileCertificateAuthority(a))
+	assert.NilError(t, r.reconcileClusterTLSSecret(a))
+	assert.NilError(t, r.reconcileRepoServerGeneratedTLSSecret(a))
+
+	// The CA is kept while it is not due for rotation.
+	assert.Assert(t, r.getClusterCARenewalDelay(a) > 0)
+	ca := &corev1.Secret{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-ca", Namespace: testNamespace}, ca))
+	data := ca.Data
+	assert.NilError(t, r.reconcileClusterCASecret(a))
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-ca", Namespace: testNamespace}, ca))
+	assert.DeepEqual(t, ca.Data, data)
+
+	// A CA that expires within the renewal window is rotated, and the certificates it signed are reissued.
+	key, err := argoutil.NewPrivateKey()
+	assert.NilError(t, err)
+	tmpl := x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		NotBefore:             time.Now().Add(-common.ArgoCDDuration365Days),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &tmpl, &tmpl, key.Public(), key)
+	assert.NilError(t, err)
+	ca.Data[corev1.TLSCertKey] = argoutil.EncodeCertificatePEM(&x509.Certificate{Raw: der})
+	ca.Data[corev1.TLSPrivateKeyKey] = argoutil.EncodePrivateKeyPEM(key)
+	assert.NilError(t, r.client.Update(context.TODO(), ca))
+	assert.Equal(t, r.getClusterCARenewalDelay(a), time.Duration(0))
+
+	assert.NilError(t, r.reconcileCertificateAuthority(a))
+	assert.NilError(t, r.reconcileClusterTLSSecret(a))
+	assert.NilError(t, r.reconcileRepoServerGeneratedTLSSecret(a))
+
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-ca", Namespace: testNamespace}, ca))
+	caCert, err := argoutil.ParsePEMEncodedCert(ca.Data[corev1.TLSCertKey])
+	assert.NilError(t, err)
+	assert.Assert(t, caCert.NotAfter.After(time.Now().Add(common.ArgoCDDefaultAutoTLSRenewBefore)))
+	assert.Assert(t, r.getClusterCARenewalDelay(a) > 0)
+
+	cm := &corev1.ConfigMap{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: getCAConfigMapName(a), Namespace: testNamespace}, cm))
+	assert.Equal(t, cm.Data[common.ArgoCDKeyTLSCert], string(ca.Data[corev1.TLSCertKey]))
+
+	for _, name := range []string{"argocd-tls", common.ArgoCDRepoServerTLSSecretName} {
+		secret := &corev1.Secret{}
+		assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: testNamespace}, secret))
+		assert.Assert(t, isCertificateSignedBy(secret, caCert), name)
+	}
+}
+
+func Test_ReconcileArgoCD_RepoServerGeneratedTLSSecret_notOwned(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.Repo.AutoTLS = common.ArgoCDRepoServerAutoTLSOperator
+	})
+	existing := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      common.ArgoCDRepoServerTLSSecretName,
+			Namespace: testNamespace,
+		},
+		Type: corev1.SecretTypeTLS,
+		Data: map[string][]byte{
+			corev1.TLSCertKey:       []byte("foo"),
+			corev1.TLSPrivateKeyKey: []byte("bar"),
+		},
+	}
+	r := makeTestReconciler(t, a, existing)
+
+	assert.NilError(t, r.reconcileRepoServerGeneratedTLSSecret(a))
+
+	secret := &corev1.Secret{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: common.ArgoCDRepoServerTLSSecretName, Namespace: testNamespace}, secret))
+	assert.DeepEqual(t, secret.Data, existing.Data)
+	assert.Equal(t, r.getRepoServerTLSRenewalDelay(a), time.Duration(0))
+}
+
+func Test_ReconcileArgoCD_ServerTLSSecretName(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.Server.TLS.SecretName = "server-cert"
+	})
+	clusterSecret := argoutil.NewSecretWithSuffix(a.ObjectMeta, "cluster")
+	clusterSecret.Data = map[string][]byte{common.ArgoCDKeyAdminPassword: []byte("password")}
+	r := makeTestReconciler(t, a, clusterSecret)
+
+	// The Argo CD Secret is not created until the existing Secret is available.
+	assert.NilError(t, r.reconcileArgoSecret(a))
+	secret := &corev1.Secret{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: common.ArgoCDSecretName, Namespace: testNamespace}, secret)
+	assert.ErrorContains(t, err, "not found")
+
+	serverCert := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "server-cert", Namespace: testNamespace},
+		Type:       corev1.SecretTypeTLS,
+		Data: map[string][]byte{
+			corev1.TLSCertKey:       []byte("cert"),
+			corev1.TLSPrivateKeyKey: []byte("key"),
+		},
+	}
+	assert.NilError(t, r.client.Create(context.TODO(), serverCert))
+	assert.NilError(t, r.reconcileArgoSecret(a))
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: common.ArgoCDSecretName, Namespace: testNamespace}, secret))
+	assert.Equal(t, string(secret.Data[common.ArgoCDKeyTLSCert]), "cert")
+	assert.Equal(t, string(secret.Data[common.ArgoCDKeyTLSPrivateKey]), "key")
+
+	// A rotated certificate is copied to the Argo CD Secret.
+	serverCert.Data[corev1.TLSCertKey] = []byte("rotated-cert")
+	assert.NilError(t, r.client.Update(context.TODO(), serverCert))
+	assert.NilError(t, r.reconcileArgoSecret(a))
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: common.ArgoCDSecretName, Namespace: testNamespace}, secret))
+	assert.Equal(t, string(secret.Data[common.ArgoCDKeyTLSCert]), "rotated-cert")
+}
+
+func Test_ReconcileArgoCD_ArgoSecretKeys(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.Server.TLS.SecretName = "server-cert"
+		a.Spec.SecretKeys = []argoprojv1alpha1.ArgoCDSecretKeySpec{
+			{Key: "webhook.github.secret", SecretName: "webhooks", SecretKey: "github"},
+			{Key: common.ArgoCDKeyAdminPassword, SecretName: "admin"},
+		}
+	})
+	clusterSecret := argoutil.NewSecretWithSuffix(a.ObjectMeta, "cluster")
+	clusterSecret.Data = map[string][]byte{common.ArgoCDKeyAdminPassword: []byte("password")}
+	serverCert := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "server-cert", Namespace: testNamespace},
+		Data: map[string][]byte{
+			corev1.TLSCertKey:       []byte("cert"),
+			corev1.TLSPrivateKeyKey: []byte("key"),
+		},
+	}
+	webhooks := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "webhooks", Namespace: testNamespace},
+		Data:       map[string][]byte{"github": []byte("github-secret")},
+	}
+	admin := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "admin", Namespace: testNamespace},
+		Data:       map[string][]byte{common.ArgoCDKeyAdminPassword: []byte("$2a$10$hash")},
+	}
+	r := makeTestReconciler(t, a, clusterSecret, serverCert, webhooks, admin)
+
+	assert.NilError(t, r.reconcileArgoSecret(a))
+	secret := &corev1.Secret{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: common.ArgoCDSecretName, Namespace: testNamespace}, secret))
+	assert.Equal(t, string(secret.Data["webhook.github.secret"]), "github-secret")
+	assert.Equal(t, string(secret.Data[common.ArgoCDKeyAdminPassword]), "$2a$10$hash")
+	assert.Equal(t, string(secret.Data[common.ArgoCDKeyTLSCert]), "cert")
+
+	// The referenced keys are updated, and keys that are not managed by the operator are kept.
+	webhooks.Data["github"] = []byte("rotated-secret")
+	assert.NilError(t, r.client.Update(context.TODO(), webhooks))
+	secret.Data["webhook.gitlab.secret"] = []byte("gitlab-secret")
+	assert.NilError(t, r.client.Update(context.TODO(), secret))
+
+	assert.NilError(t, r.reconcileArgoSecret(a))
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: common.ArgoCDSecretName, Namespace: testNamespace}, secret))
+	assert.Equal(t, string(secret.Data["webhook.github.secret"]), "rotated-secret")
+	assert.Equal(t, string(secret.Data["webhook.gitlab.secret"]), "gitlab-secret")
+	assert.Equal(t, string(secret.Data[common.ArgoCDKeyAdminPassword]), "$2a$10$hash")
+
+	// The operator manages the admin password again once it is no longer referenced.
+	a.Spec.SecretKeys = a.Spec.SecretKeys[:1]
+	assert.NilError(t, r.reconcileArgoSecret(a))
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: common.ArgoCDSecretName, Namespace: testNamespace}, secret))
+	assert.Assert(t, !hasArgoAdminPasswordChanged(secret, clusterSecret))
+}
+
+func Test_ReconcileArgoCD_ClusterTLSSecret_DNSNames(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD()
+	r := makeTestReconciler(t, a)
+	assert.NilError(t, r.reconcileClusterCASecret(a))
+
+	assert.NilError(t, r.reconcileClusterTLSSecret(a))
+
+	secret := argoutil.NewTLSSecret(a.ObjectMeta, "tls")
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: secret.Name, Namespace: testNamespace}, secret))
+	cert, err := argoutil.ParsePEMEncodedCert(secret.Data[corev1.TLSCertKey])
+	assert.NilError(t, err)
+	for _, name := range []string{"argocd-server", "argocd-server.argocd", "argocd-server.argocd.svc", "argocd-server.argocd.svc.cluster.local", "argocd-repo-server.argocd.svc", "argocd-dex-server.argocd"} {
+		assert.NilError(t, cert.VerifyHostname(name))
+	}
+
+	// The certificate is kept while it is valid for all of the DNS names.
+	data := secret.Data
+	assert.NilError(t, r.reconcileClusterTLSSecret(a))
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: secret.Name, Namespace: testNamespace}, secret))
+	assert.DeepEqual(t, secret.Data, data)
+
+	// The certificate is reissued when extra SANs are added.
+	a.Spec.TLS.ExtraSANs = []string{"argocd.example.com", "argocd-server"}
+	assert.NilError(t, r.reconcileClusterTLSSecret(a))
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: secret.Name, Namespace: testNamespace}, secret))
+	cert, err = argoutil.ParsePEMEncodedCert(secret.Data[corev1.TLSCertKey])
+	assert.NilError(t, err)
+	assert.NilError(t, cert.VerifyHostname("argocd.example.com"))
+	assert.Equal(t, cert.DNSNames[len(cert.DNSNames)-1], "argocd.example.com")
+
+	// IP addresses are issued as IP address SANs, and the certificate is then kept.
+	a.Spec.TLS.ExtraSANs = []string{"argocd.example.com", "192.0.2.10", "2001:db8::10"}
+	assert.NilError(t, r.reconcileClusterTLSSecret(a))
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: secret.Name, Namespace: testNamespace}, secret))
+	cert, err = argoutil.ParsePEMEncodedCert(secret.Data[corev1.TLSCertKey])
+	assert.NilError(t, err)
+	assert.NilError(t, cert.VerifyHostname("192.0.2.10"))
+	assert.Equal(t, len(cert.IPAddresses), 2)
+	assert.Assert(t, !containsString(cert.DNSNames, "192.0.2.10"))
+
+	data = secret.Data
+	assert.NilError(t, r.reconcileClusterTLSSecret(a))
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: secret.Name, Namespace: testNamespace}, secret))
+	assert.DeepEqual(t, secret.Data, data)
+}
@@ -17,16 +17,60 @@ package argocd
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"strings"
 
 	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
 	"github.com/argoproj-labs/argocd-operator/pkg/common"
 	"github.com/argoproj-labs/argocd-operator/pkg/controller/argoutil"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
+// getServiceIPFamilyFields will return the IP family fields of the Service spec for the given ArgoCD. These fields
+// are newer than the Kubernetes API the operator is built with, so they are set on unstructured Services.
+func getServiceIPFamilyFields(cr *argoprojv1a1.ArgoCD) map[string]interface{} {
+	fields := make(map[string]interface{})
+	if len(cr.Spec.IPFamilies) > 0 {
+		families := make([]interface{}, 0, len(cr.Spec.IPFamilies))
+		for _, f := range cr.Spec.IPFamilies {
+			families = append(families, f)
+		}
+		fields["ipFamilies"] = families
+	}
+	if cr.Spec.IPFamilyPolicy != "" {
+		fields["ipFamilyPolicy"] = cr.Spec.IPFamilyPolicy
+	}
+	return fields
+}
+
+// createService will create the given Service with the IP families for the given ArgoCD. The IP families are only set
+// when the Service is created, as the primary IP family of a Service can't be changed once it is created.
+func (r *ReconcileArgoCD) createService(cr *argoprojv1a1.ArgoCD, svc *corev1.Service) error {
+	fields := getServiceIPFamilyFields(cr)
+	if len(fields) == 0 {
+		return r.client.Create(context.TODO(), svc)
+	}
+
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(svc)
+	if err != nil {
+		return err
+	}
+	u := &unstructured.Unstructured{Object: obj}
+	u.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Service"))
+	for name, value := range fields {
+		if err := unstructured.SetNestedField(u.Object, value, "spec", name); err != nil {
+			return err
+		}
+	}
+	return r.client.Create(context.TODO(), u)
+}
+
 // getArgoServerServiceType will return the server Service type for the ArgoCD.
 func getArgoServerServiceType(cr *argoprojv1a1.ArgoCD) corev1.ServiceType {
 	if len(cr.Spec.Server.Service.Type) > 0 {
@@ -68,14 +112,14 @@ func newServiceWithSuffix(suffix string, component string, cr *argoprojv1a1.Argo
 func (r *ReconcileArgoCD) reconcileDexService(cr *argoprojv1a1.ArgoCD) error {
 	svc := newServiceWithSuffix("dex-server", "dex-server", cr)
 	if argoutil.IsObjectFound(r.client, cr.Namespace, svc.Name, svc) {
-		if isDexDisabled() {
+		if !isManagedDexEnabled(cr) {
 			// Service exists but enabled flag has been set to false, delete the Service
 			return r.client.Delete(context.TODO(), svc)
 		}
 		return nil
 	}
 
-	if isDexDisabled() {
+	if !isManagedDexEnabled(cr) {
 		return nil // Dex is disabled, do nothing
 	}
 
@@ -100,21 +144,21 @@ func (r *ReconcileArgoCD) reconcileDexService(cr *argoprojv1a1.ArgoCD) error {
 	if err := controllerutil.SetControllerReference(cr, svc, r.scheme); err != nil {
 		return err
 	}
-	return r.client.Create(context.TODO(), svc)
+	return r.createService(cr, svc)
 }
 
 // reconcileGrafanaService will ensure that the Service for Grafana is present.
 func (r *ReconcileArgoCD) reconcileGrafanaService(cr *argoprojv1a1.ArgoCD) error {
 	svc := newServiceWithSuffix("grafana", "grafana", cr)
 	if argoutil.IsObjectFound(r.client, cr.Namespace, svc.Name, svc) {
-		if !cr.Spec.Grafana.Enabled {
+		if !isManagedGrafanaEnabled(cr) {
 			// Service exists but enabled flag has been set to false, delete the Service
 			return r.client.Delete(context.TODO(), svc)
 		}
 		return nil // Service found, do nothing
 	}
 
-	if !cr.Spec.Grafana.Enabled {
+	if !isManagedGrafanaEnabled(cr) {
 		return nil // Grafana not enabled, do nothing.
 	}
 
@@ -134,42 +178,63 @@ func (r *ReconcileArgoCD) reconcileGrafanaService(cr *argoprojv1a1.ArgoCD) error
 	if err := controllerutil.SetControllerReference(cr, svc, r.scheme); err != nil {
 		return err
 	}
-	return r.client.Create(context.TODO(), svc)
+	return r.createService(cr, svc)
+}
+
+// getMetricsServicePorts will return the ports of the Service for the Argo CD application controller metrics.
+func getMetricsServicePorts(port int32) []corev1.ServicePort {
+	return []corev1.ServicePort{
+		{
+			Name:       "metrics",
+			Port:       port,
+			Protocol:   corev1.ProtocolTCP,
+			TargetPort: intstr.FromInt(int(port)),
+		},
+	}
 }
 
 // reconcileMetricsService will ensure that the Service for the Argo CD application controller metrics is present.
 func (r *ReconcileArgoCD) reconcileMetricsService(cr *argoprojv1a1.ArgoCD) error {
 	svc := newServiceWithSuffix("metrics", "metrics", cr)
+	port := getArgoApplicationControllerMetricsPort(cr)
 	if argoutil.IsObjectFound(r.client, cr.Namespace, svc.Name, svc) {
-		// Service found, do nothing
-		return nil
+		changed := ensureMetricsLabels(cr, newServiceWithSuffix("metrics", "metrics", cr).Labels, &svc.ObjectMeta)
+		if len(svc.Spec.Ports) != 1 || svc.Spec.Ports[0].Port != port || svc.Spec.Ports[0].TargetPort.IntValue() != int(port) {
+			svc.Spec.Ports = getMetricsServicePorts(port)
+			changed = true
+		}
+		if changed {
+			return r.client.Update(context.TODO(), svc)
+		}
+		return nil // Service found, do nothing
 	}
 
+	ensureMetricsLabels(cr, nil, &svc.ObjectMeta)
+
 	svc.Spec.Selector = map[string]string{
 		common.ArgoCDKeyName: nameWithSuffix("application-controller", cr),
 	}
 
-	svc.Spec.Ports = []corev1.ServicePort{
-		{
-			Name:       "metrics",
-			Port:       8082,
-			Protocol:   corev1.ProtocolTCP,
-			TargetPort: intstr.FromInt(8082),
-		},
-	}
+	svc.Spec.Ports = getMetricsServicePorts(port)
 
 	if err := controllerutil.SetControllerReference(cr, svc, r.scheme); err != nil {
 		return err
 	}
-	return r.client.Create(context.TODO(), svc)
+	return r.createService(cr, svc)
 }
 
 // reconcileRedisHAAnnounceServices will ensure that the announce Services are present for Redis when running in HA mode.
+// The announce Services of Redis HA servers that have been removed are deleted.
 func (r *ReconcileArgoCD) reconcileRedisHAAnnounceServices(cr *argoprojv1a1.ArgoCD) error {
-	for i := int32(0); i < common.ArgoCDDefaultRedisHAReplicas; i++ {
+	replicas := *getRedisHAReplicas(cr)
+	if err := r.deleteRedisHAAnnounceServicesFrom(cr, replicas); err != nil {
+		return err
+	}
+
+	for i := int32(0); i < replicas; i++ {
 		svc := newServiceWithSuffix(fmt.Sprintf("redis-ha-announce-%d", i), "redis", cr)
 		if argoutil.IsObjectFound(r.client, cr.Namespace, svc.Name, svc) {
-			return nil // Service found, do nothing
+			continue // Service found, do nothing
 		}
 
 		svc.ObjectMeta.Annotations = map[string]string{
@@ -201,7 +266,32 @@ func (r *ReconcileArgoCD) reconcileRedisHAAnnounceServices(cr *argoprojv1a1.Argo
 			return err
 		}
 
-		if err := r.client.Create(context.TODO(), svc); err != nil {
+		if err := r.createService(cr, svc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteRedisHAAnnounceServicesFrom will delete the Redis HA announce Services with an index of at least the given
+// index.
+func (r *ReconcileArgoCD) deleteRedisHAAnnounceServicesFrom(cr *argoprojv1a1.ArgoCD, index int32) error {
+	services := &corev1.ServiceList{}
+	if err := r.client.List(context.TODO(), services, managedResourceListOptions(cr)...); err != nil {
+		return err
+	}
+
+	prefix := nameWithSuffix("redis-ha-announce-", cr)
+	for i := range services.Items {
+		svc := &services.Items[i]
+		if !strings.HasPrefix(svc.Name, prefix) {
+			continue
+		}
+		n, err := strconv.Atoi(strings.TrimPrefix(svc.Name, prefix))
+		if err != nil || int32(n) < index {
+			continue
+		}
+		if err := r.client.Delete(context.TODO(), svc); err != nil && !errors.IsNotFound(err) {
 			return err
 		}
 	}
@@ -236,7 +326,7 @@ func (r *ReconcileArgoCD) reconcileRedisHAMasterService(cr *argoprojv1a1.ArgoCD)
 	if err := controllerutil.SetControllerReference(cr, svc, r.scheme); err != nil {
 		return err
 	}
-	return r.client.Create(context.TODO(), svc)
+	return r.createService(cr, svc)
 }
 
 // reconcileRedisHAProxyService will ensure that the HA Proxy Service is present for Redis when running in HA mode.
@@ -262,7 +352,7 @@ func (r *ReconcileArgoCD) reconcileRedisHAProxyService(cr *argoprojv1a1.ArgoCD)
 	if err := controllerutil.SetControllerReference(cr, svc, r.scheme); err != nil {
 		return err
 	}
-	return r.client.Create(context.TODO(), svc)
+	return r.createService(cr, svc)
 }
 
 // reconcileRedisHAServices will ensure that all required Services are present for Redis when running in HA mode.
@@ -283,6 +373,10 @@ func (r *ReconcileArgoCD) reconcileRedisHAServices(cr *argoprojv1a1.ArgoCD) erro
 
 // reconcileRedisService will ensure that the Service for Redis is present.
 func (r *ReconcileArgoCD) reconcileRedisService(cr *argoprojv1a1.ArgoCD) error {
+	if !isManagedRedisEnabled(cr) {
+		return nil // Remote Redis, the Service is pruned.
+	}
+
 	svc := newServiceWithSuffix("redis", "redis", cr)
 	if argoutil.IsObjectFound(r.client, cr.Namespace, svc.Name, svc) {
 		return nil // Service found, do nothing
@@ -304,12 +398,12 @@ func (r *ReconcileArgoCD) reconcileRedisService(cr *argoprojv1a1.ArgoCD) error {
 	if err := controllerutil.SetControllerReference(cr, svc, r.scheme); err != nil {
 		return err
 	}
-	return r.client.Create(context.TODO(), svc)
+	return r.createService(cr, svc)
 }
 
 func ensureAutoTLSAnnotation(cr *argoprojv1a1.ArgoCD, svc *corev1.Service) bool {
 	autoTLSAnnotationName := ""
-	if cr.Spec.Repo.AutoTLS == "openshift" {
+	if cr.Spec.Repo.AutoTLS == common.ArgoCDRepoServerAutoTLSOpenShift {
 		autoTLSAnnotationName = "service.beta.openshift.io/serving-cert-secret-name"
 	}
 	if autoTLSAnnotationName != "" {
@@ -332,13 +426,18 @@ func (r *ReconcileArgoCD) reconcileRepoService(cr *argoprojv1a1.ArgoCD) error {
 	svc := newServiceWithSuffix("repo-server", "repo-server", cr)
 
 	if argoutil.IsObjectFound(r.client, cr.Namespace, svc.Name, svc) {
-		if ensureAutoTLSAnnotation(cr, svc) {
+		changed := ensureAutoTLSAnnotation(cr, svc)
+		if ensureMetricsLabels(cr, newServiceWithSuffix("repo-server", "repo-server", cr).Labels, &svc.ObjectMeta) {
+			changed = true
+		}
+		if changed {
 			return r.client.Update(context.TODO(), svc)
 		}
 		return nil // Service found, do nothing
 	}
 
 	ensureAutoTLSAnnotation(cr, svc)
+	ensureMetricsLabels(cr, nil, &svc.ObjectMeta)
 
 	svc.Spec.Selector = map[string]string{
 		common.ArgoCDKeyName: nameWithSuffix("repo-server", cr),
@@ -361,16 +460,21 @@ func (r *ReconcileArgoCD) reconcileRepoService(cr *argoprojv1a1.ArgoCD) error {
 	if err := controllerutil.SetControllerReference(cr, svc, r.scheme); err != nil {
 		return err
 	}
-	return r.client.Create(context.TODO(), svc)
+	return r.createService(cr, svc)
 }
 
 // reconcileServerMetricsService will ensure that the Service for the Argo CD server metrics is present.
 func (r *ReconcileArgoCD) reconcileServerMetricsService(cr *argoprojv1a1.ArgoCD) error {
 	svc := newServiceWithSuffix("server-metrics", "server", cr)
 	if argoutil.IsObjectFound(r.client, cr.Namespace, svc.Name, svc) {
+		if ensureMetricsLabels(cr, newServiceWithSuffix("server-metrics", "server", cr).Labels, &svc.ObjectMeta) {
+			return r.client.Update(context.TODO(), svc)
+		}
 		return nil // Service found, do nothing
 	}
 
+	ensureMetricsLabels(cr, nil, &svc.ObjectMeta)
+
 	svc.Spec.Selector = map[string]string{
 		common.ArgoCDKeyName: nameWithSuffix("server", cr),
 	}
@@ -387,16 +491,31 @@ func (r *ReconcileArgoCD) reconcileServerMetricsService(cr *argoprojv1a1.ArgoCD)
 	if err := controllerutil.SetControllerReference(cr, svc, r.scheme); err != nil {
 		return err
 	}
-	return r.client.Create(context.TODO(), svc)
+	return r.createService(cr, svc)
+}
+
+// getArgoServerServiceAnnotations will return the annotations of the Argo CD Server Service for the given ArgoCD. The
+// external-dns annotations are only added to a LoadBalancer Service, as external-dns publishes the address of its load
+// balancer.
+func getArgoServerServiceAnnotations(cr *argoprojv1a1.ArgoCD) map[string]string {
+	if getArgoServerServiceType(cr) != corev1.ServiceTypeLoadBalancer {
+		return nil
+	}
+	return addExternalDNSAnnotations(cr, nil, getArgoServerExternalDNSHostnames(cr, cr.Spec.Server.Host))
 }
 
 // reconcileServerService will ensure that the Service is present for the Argo CD server component.
 func (r *ReconcileArgoCD) reconcileServerService(cr *argoprojv1a1.ArgoCD) error {
 	svc := newServiceWithSuffix("server", "server", cr)
 	if argoutil.IsObjectFound(r.client, cr.Namespace, svc.Name, svc) {
+		if applyManagedMetadata(&svc.ObjectMeta, nil, getArgoServerServiceAnnotations(cr)) {
+			return r.client.Update(context.TODO(), svc)
+		}
 		return nil // Service found, do nothing
 	}
 
+	applyManagedMetadata(&svc.ObjectMeta, nil, getArgoServerServiceAnnotations(cr))
+
 	svc.Spec.Ports = []corev1.ServicePort{
 		{
 			Name:       "http",
@@ -420,7 +539,7 @@ func (r *ReconcileArgoCD) reconcileServerService(cr *argoprojv1a1.ArgoCD) error
 	if err := controllerutil.SetControllerReference(cr, svc, r.scheme); err != nil {
 		return err
 	}
-	return r.client.Create(context.TODO(), svc)
+	return r.createService(cr, svc)
 }
 
 // reconcileServices will ensure that all Services are present for the given ArgoCD.
@@ -440,12 +559,13 @@ func (r *ReconcileArgoCD) reconcileServices(cr *argoprojv1a1.ArgoCD) error {
 		return err
 	}
 
-	if cr.Spec.HA.Enabled {
+	if isRedisHAInUse(cr) {
 		err = r.reconcileRedisHAServices(cr)
 		if err != nil {
 			return err
 		}
-	} else {
+	}
+	if !cr.Spec.HA.Enabled {
 		err = r.reconcileRedisService(cr)
 		if err != nil {
 			return err
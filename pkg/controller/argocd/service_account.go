@@ -57,6 +57,43 @@ func newServiceAccountWithName(name string, cr *argoprojv1a1.ArgoCD) *corev1.Ser
 	return sa
 }
 
+// newExternalServiceAccount returns a reference to an existing ServiceAccount with the given name that is not
+// managed by the operator.
+func newExternalServiceAccount(name string, cr *argoprojv1a1.ArgoCD) *corev1.ServiceAccount {
+	return &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: cr.Namespace,
+		},
+	}
+}
+
+// getServiceAccountNameOverride will return the name of the existing ServiceAccount configured for the given
+// component, or an empty string when the operator manages the ServiceAccount of the component.
+func getServiceAccountNameOverride(name string, cr *argoprojv1a1.ArgoCD) string {
+	switch name {
+	case common.ArgoCDApplicationControllerComponent:
+		return cr.Spec.Controller.ServiceAccountName
+	case common.ArgoCDDexServerComponent:
+		return getDexSpec(cr).ServiceAccountName
+	case common.ArgoCDRedisComponent:
+		return cr.Spec.Redis.ServiceAccountName
+	case common.ArgoCDRedisHAComponent, common.ArgoCDRedisHAProxyComponent:
+		return cr.Spec.HA.ServiceAccountName
+	case common.ArgoCDServerComponent:
+		return cr.Spec.Server.ServiceAccountName
+	}
+	return ""
+}
+
+// getServiceAccountName will return the name of the ServiceAccount used by the pods of the given component.
+func getServiceAccountName(name string, cr *argoprojv1a1.ArgoCD) string {
+	if override := getServiceAccountNameOverride(name, cr); override != "" {
+		return override
+	}
+	return generateResourceName(name, cr)
+}
+
 // reconcileServiceAccounts will ensure that all ArgoCD Service Accounts are configured.
 func (r *ReconcileArgoCD) reconcileServiceAccounts(cr *argoprojv1a1.ArgoCD) error {
 
@@ -84,6 +121,13 @@ func (r *ReconcileArgoCD) reconcileServiceAccounts(cr *argoprojv1a1.ArgoCD) erro
 		return err
 	}
 
+	// Redis and the Redis HA Proxy do not need any API permissions
+	for _, name := range []string{common.ArgoCDRedisComponent, common.ArgoCDRedisHAProxyComponent} {
+		if _, err := r.reconcileServiceAccount(name, cr); err != nil {
+			return err
+		}
+	}
+
 	// specialized handling for dex
 
 	if err := r.reconcileDexServiceAccount(cr); err != nil {
@@ -93,14 +137,26 @@ func (r *ReconcileArgoCD) reconcileServiceAccounts(cr *argoprojv1a1.ArgoCD) erro
 	return nil
 }
 
+// getRedisAutomountServiceAccountToken will return whether a service account token should be mounted in the Redis pods.
+func getRedisAutomountServiceAccountToken(cr *argoprojv1a1.ArgoCD) *bool {
+	if cr.Spec.HA.Enabled {
+		if cr.Spec.HA.AutomountServiceAccountToken != nil {
+			return cr.Spec.HA.AutomountServiceAccountToken
+		}
+	} else if cr.Spec.Redis.AutomountServiceAccountToken != nil {
+		return cr.Spec.Redis.AutomountServiceAccountToken
+	}
+	return boolPtr(false)
+}
+
 // reconcileDexServiceAccount will ensure that the Dex ServiceAccount is configured properly for OpenShift OAuth.
 func (r *ReconcileArgoCD) reconcileDexServiceAccount(cr *argoprojv1a1.ArgoCD) error {
-	if !cr.Spec.Dex.OpenShiftOAuth {
+	if !getDexSpec(cr).OpenShiftOAuth {
 		return nil // OpenShift OAuth not enabled, move along...
 	}
 
 	log.Info("oauth enabled, configuring dex service account")
-	sa := newServiceAccountWithName(common.ArgoCDDefaultDexServiceAccountName, cr)
+	sa := newExternalServiceAccount(getServiceAccountName(dexServer, cr), cr)
 	if err := argoutil.FetchObject(r.client, cr.Namespace, sa.Name, sa); err != nil {
 		return err
 	}
@@ -149,6 +205,10 @@ func (r *ReconcileArgoCD) reconcileServiceAccountPermissions(name string, rules
 }
 
 func (r *ReconcileArgoCD) reconcileServiceAccount(name string, cr *argoprojv1a1.ArgoCD) (*corev1.ServiceAccount, error) {
+	if override := getServiceAccountNameOverride(name, cr); override != "" {
+		return newExternalServiceAccount(override, cr), nil // ServiceAccount managed outside of the operator
+	}
+
 	sa := newServiceAccountWithName(name, cr)
 
 	exists := true
@@ -156,13 +216,13 @@ func (r *ReconcileArgoCD) reconcileServiceAccount(name string, cr *argoprojv1a1.
 		if !errors.IsNotFound(err) {
 			return nil, err
 		}
-		if name == dexServer && isDexDisabled() {
+		if name == dexServer && !isManagedDexEnabled(cr) {
 			return sa, nil // Dex is disabled, do nothing
 		}
 		exists = false
 	}
 	if exists {
-		if name == dexServer && isDexDisabled() {
+		if name == dexServer && !isManagedDexEnabled(cr) {
 			// Delete any existing Service Account created for Dex
 			return sa, r.client.Delete(context.TODO(), sa)
 		}
@@ -21,10 +21,15 @@ import (
 	"testing"
 
 	"gotest.tools/assert"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+
+	argoprojv1alpha1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
 )
 
 func TestReconcileArgoCD_reconcileServiceAccountPermissions(t *testing.T) {
@@ -67,6 +72,36 @@ func TestReconcileArgoCD_reconcileServiceAccountPermissions(t *testing.T) {
 	assert.DeepEqual(t, expectedRules, reconciledRole.Rules)
 }
 
+func TestReconcileArgoCD_reconcileServiceAccountPermissions_withServiceAccountName(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.Server.ServiceAccountName = "platform-argocd-server"
+	})
+	r := makeTestReconciler(t, a)
+	assert.NilError(t, createNamespace(r, a.Namespace, a.Namespace))
+
+	assert.NilError(t, r.reconcileServiceAccountPermissions(server, policyRuleForServer(), a))
+
+	// The operator does not create a ServiceAccount for the server...
+	sa := &corev1.ServiceAccount{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: fmt.Sprintf("%s-%s", a.Name, server), Namespace: a.Namespace}, sa)
+	assert.Assert(t, errors.IsNotFound(err))
+
+	// ...but binds its Role to the existing ServiceAccount.
+	roleBinding := &v1.RoleBinding{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: fmt.Sprintf("%s-%s", a.Name, server), Namespace: a.Namespace}, roleBinding))
+	assert.DeepEqual(t, roleBinding.Subjects, []v1.Subject{{
+		Kind:      v1.ServiceAccountKind,
+		Name:      "platform-argocd-server",
+		Namespace: a.Namespace,
+	}})
+
+	assert.NilError(t, r.reconcileServerDeployment(a))
+	deploy := &appsv1.Deployment{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-server", Namespace: a.Namespace}, deploy))
+	assert.Equal(t, deploy.Spec.Template.Spec.ServiceAccountName, "platform-argocd-server")
+}
+
 func TestReconcileArgoCD_reconcileServiceAccountClusterPermissions(t *testing.T) {
 	logf.SetLogger(logf.ZapLogger(true))
 	a := makeTestArgoCD()
@@ -157,3 +192,38 @@ func testRules() []v1.PolicyRule {
 		},
 	}
 }
+
+func TestReconcileArgoCD_reconcileServiceAccountClusterPermissions_defaultClusterScopedRole(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	existingClusterRole := &v1.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{Name: "approved-application-controller"},
+		Rules:      testRules(),
+	}
+	a := makeTestArgoCD()
+	r := makeTestReconciler(t, a, existingClusterRole)
+
+	os.Setenv("ARGOCD_CLUSTER_CONFIG_NAMESPACES", a.Namespace)
+	defer os.Unsetenv("ARGOCD_CLUSTER_CONFIG_NAMESPACES")
+
+	generatedClusterRoleName := GenerateUniqueResourceName(applicationController, a)
+	clusterRoleBinding := &v1.ClusterRoleBinding{}
+	assert.NilError(t, r.reconcileServiceAccountClusterPermissions(applicationController, policyRuleForApplicationController(), a))
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: generatedClusterRoleName}, &v1.ClusterRole{}))
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: generatedClusterRoleName}, clusterRoleBinding))
+	assert.Equal(t, clusterRoleBinding.RoleRef.Name, generatedClusterRoleName)
+
+	// The generated ClusterRole is replaced by the existing one.
+	a.Spec.DefaultClusterScopedRole = existingClusterRole.Name
+	assert.NilError(t, r.reconcileServiceAccountClusterPermissions(applicationController, policyRuleForApplicationController(), a))
+	assert.ErrorContains(t, r.client.Get(context.TODO(), types.NamespacedName{Name: generatedClusterRoleName}, &v1.ClusterRole{}), "not found")
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: generatedClusterRoleName}, clusterRoleBinding))
+	assert.Equal(t, clusterRoleBinding.RoleRef.Name, existingClusterRole.Name)
+
+	// The existing ClusterRole is never modified by the operator.
+	reconciledClusterRole := &v1.ClusterRole{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: existingClusterRole.Name}, reconciledClusterRole))
+	assert.DeepEqual(t, reconciledClusterRole.Rules, testRules())
+
+	a.Spec.DefaultClusterScopedRole = "missing"
+	assert.ErrorContains(t, r.reconcileServiceAccountClusterPermissions(applicationController, policyRuleForApplicationController(), a), "not found")
+}
@@ -2,10 +2,14 @@ package argocd
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"testing"
 
+	argoprojv1alpha1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
 	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
 	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
 )
@@ -45,3 +49,101 @@ func TestReconcileArgoCD_reconcileDexService_Dex_Disabled(t *testing.T) {
 	assert.NilError(t, r.reconcileDexService(a))
 	assert.ErrorContains(t, r.client.Get(context.TODO(), types.NamespacedName{Namespace: s.Namespace, Name: s.Name}, s), "not found")
 }
+
+func TestReconcileArgoCD_reconcileMetricsService(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD()
+	r := makeTestReconciler(t, a)
+
+	s := newServiceWithSuffix("metrics", "metrics", a)
+
+	assert.NilError(t, r.reconcileMetricsService(a))
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Namespace: s.Namespace, Name: s.Name}, s))
+	assert.Equal(t, s.Spec.Ports[0].Port, int32(8082))
+
+	// Enabling TLS moves the metrics to the port of the TLS proxy.
+	a.Spec.Controller.Metrics.TLS = &argoprojv1alpha1.ArgoCDApplicationControllerMetricsTLSSpec{SecretName: "metrics-tls"}
+	assert.NilError(t, r.reconcileMetricsService(a))
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Namespace: s.Namespace, Name: s.Name}, s))
+	assert.Equal(t, s.Spec.Ports[0].Port, int32(8443))
+	assert.Equal(t, s.Spec.Ports[0].TargetPort.IntValue(), 8443)
+}
+
+func TestReconcileArgoCD_reconcileRedisHAAnnounceServices(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.HA.Enabled = true
+		a.Spec.HA.RedisConfig.AnnounceServices = 5
+	})
+	r := makeTestReconciler(t, a)
+
+	assert.NilError(t, r.reconcileRedisHAAnnounceServices(a))
+	for i := 0; i < 5; i++ {
+		s := newServiceWithSuffix(fmt.Sprintf("redis-ha-announce-%d", i), "redis", a)
+		assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Namespace: s.Namespace, Name: s.Name}, s))
+	}
+
+	// Reducing the number of announce services deletes the surplus ones
+	a.Spec.HA.RedisConfig.AnnounceServices = 3
+	assert.NilError(t, r.reconcileRedisHAAnnounceServices(a))
+	for i := 0; i < 5; i++ {
+		s := newServiceWithSuffix(fmt.Sprintf("redis-ha-announce-%d", i), "redis", a)
+		err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: s.Namespace, Name: s.Name}, s)
+		if i < 3 {
+			assert.NilError(t, err)
+		} else {
+			assert.ErrorContains(t, err, "not found")
+		}
+	}
+}
+
+func getUnstructuredService(t *testing.T, r *ReconcileArgoCD, name string) *unstructured.Unstructured {
+	t.Helper()
+	u := &unstructured.Unstructured{}
+	u.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Service"))
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: name}, u))
+	return u
+}
+
+func TestReconcileArgoCD_reconcileServerService_withIPFamilies(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.IPFamilies = []string{"IPv6", "IPv4"}
+		a.Spec.IPFamilyPolicy = "PreferDualStack"
+	})
+	r := makeTestReconciler(t, a)
+
+	assert.NilError(t, r.reconcileServerService(a))
+
+	u := getUnstructuredService(t, r, "argocd-server")
+	families, _, _ := unstructured.NestedStringSlice(u.Object, "spec", "ipFamilies")
+	assert.DeepEqual(t, families, []string{"IPv6", "IPv4"})
+	policy, _, _ := unstructured.NestedString(u.Object, "spec", "ipFamilyPolicy")
+	assert.Equal(t, policy, "PreferDualStack")
+
+	s := newServiceWithSuffix("server", "server", a)
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Namespace: s.Namespace, Name: s.Name}, s))
+	assert.Equal(t, s.Spec.Ports[0].Name, "http")
+}
+
+func TestReconcileArgoCD_reconcileServerMetricsService_withMetricsLabels(t *testing.T) {
+	restoreEnv(t)
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.Prometheus.Labels = map[string]string{"release": "kube-prometheus-stack"}
+	})
+	r := makeTestReconciler(t, a)
+
+	assert.NilError(t, r.reconcileServerMetricsService(a))
+
+	svc := &corev1.Service{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-server-metrics", Namespace: testNamespace}, svc))
+	assert.Equal(t, svc.Labels["release"], "kube-prometheus-stack")
+
+	a.Spec.Prometheus.Labels = nil
+	assert.NilError(t, r.reconcileServerMetricsService(a))
+
+	svc = &corev1.Service{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-server-metrics", Namespace: testNamespace}, svc))
+	_, found := svc.Labels["release"]
+	assert.Assert(t, !found)
+}
@@ -0,0 +1,168 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocd
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
+	"github.com/argoproj-labs/argocd-operator/pkg/controller/argoutil"
+)
+
+const (
+	// sopsConfigHome is the XDG config directory used by the Repo server when KSOPS is enabled.
+	sopsConfigHome = "/.config"
+
+	// sopsGPGKeyring is the path of the GPG keyring used by the Repo server.
+	sopsGPGKeyring = "/app/config/gpg/keys"
+)
+
+// getKSOPSContainerImage will return the container image for KSOPS.
+func getKSOPSContainerImage(cr *argoprojv1a1.ArgoCD) string {
+	defaultTag, defaultImg := false, false
+	img := cr.Spec.Repo.SOPS.Image
+	if len(img) == 0 {
+		img = argoutil.ReplaceImageRegistry(common.ArgoCDDefaultKSOPSImage, cr.Spec.ImageRegistry)
+		defaultImg = true
+	}
+
+	tag := cr.Spec.Repo.SOPS.Version
+	if len(tag) == 0 {
+		tag = common.ArgoCDDefaultKSOPSVersion
+		defaultTag = true
+	}
+	if e := argoutil.GetDefaultImage(common.ArgoCDKSOPSImageEnvName); e != "" && (defaultTag && defaultImg) {
+		return e
+	}
+	return argoutil.CombineImageTag(img, tag)
+}
+
+// getSOPSRepoEnv will return the environment variables needed by the Repo server to decrypt manifests with KSOPS.
+func getSOPSRepoEnv() []corev1.EnvVar {
+	return []corev1.EnvVar{
+		{
+			Name:  "GNUPGHOME",
+			Value: sopsGPGKeyring,
+		},
+		{
+			Name:  "XDG_CONFIG_HOME",
+			Value: sopsConfigHome,
+		},
+	}
+}
+
+// getSOPSInitContainers will return the init containers that install KSOPS and import the GPG keys for the
+// Repo server.
+func getSOPSInitContainers(cr *argoprojv1a1.ArgoCD) []corev1.Container {
+	containers := []corev1.Container{{
+		Command:         []string{"/bin/sh", "-c", "mv ksops /ksops-tools/ && mv $GOPATH/bin/kustomize /ksops-tools/"},
+		Image:           getKSOPSContainerImage(cr),
+		ImagePullPolicy: corev1.PullIfNotPresent,
+		Name:            "install-ksops",
+		VolumeMounts: []corev1.VolumeMount{
+			{
+				Name:      "ksops-tools",
+				MountPath: "/ksops-tools",
+			},
+		},
+	}}
+
+	if len(cr.Spec.Repo.SOPS.GPGKeySecret) > 0 {
+		containers = append(containers, corev1.Container{
+			Command: []string{"/bin/sh", "-c", "gpg --batch --import /sops-gpg/*"},
+			Env: []corev1.EnvVar{{
+				Name:  "GNUPGHOME",
+				Value: sopsGPGKeyring,
+			}},
+			Image:           getArgoContainerImage(cr),
+			ImagePullPolicy: corev1.PullAlways,
+			Name:            "import-sops-gpg-keys",
+			VolumeMounts: []corev1.VolumeMount{
+				{
+					Name:      "gpg-keyring",
+					MountPath: sopsGPGKeyring,
+				},
+				{
+					Name:      "sops-gpg",
+					MountPath: "/sops-gpg",
+				},
+			},
+		})
+	}
+	return containers
+}
+
+// getSOPSRepoVolumeMounts will return the volume mounts needed by the Repo server container to run KSOPS.
+func getSOPSRepoVolumeMounts(cr *argoprojv1a1.ArgoCD) []corev1.VolumeMount {
+	mounts := []corev1.VolumeMount{
+		{
+			Name:      "ksops-tools",
+			MountPath: "/usr/local/bin/kustomize",
+			SubPath:   "kustomize",
+		},
+		{
+			Name:      "ksops-tools",
+			MountPath: sopsConfigHome + "/kustomize/plugin/viaduct.ai/v1/ksops/ksops",
+			SubPath:   "ksops",
+		},
+	}
+
+	if len(cr.Spec.Repo.SOPS.AgeKeySecret) > 0 {
+		mounts = append(mounts, corev1.VolumeMount{
+			Name:      "sops-age",
+			MountPath: sopsConfigHome + "/sops/age",
+			ReadOnly:  true,
+		})
+	}
+	return mounts
+}
+
+// getSOPSVolumes will return the volumes needed by the Repo server to run KSOPS.
+func getSOPSVolumes(cr *argoprojv1a1.ArgoCD) []corev1.Volume {
+	volumes := []corev1.Volume{{
+		Name: "ksops-tools",
+		VolumeSource: corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{},
+		},
+	}}
+
+	if len(cr.Spec.Repo.SOPS.AgeKeySecret) > 0 {
+		volumes = append(volumes, corev1.Volume{
+			Name: "sops-age",
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: cr.Spec.Repo.SOPS.AgeKeySecret,
+					Items: []corev1.KeyToPath{{
+						Key:  common.ArgoCDKeySOPSAgeKeys,
+						Path: common.ArgoCDKeySOPSAgeKeys,
+					}},
+				},
+			},
+		})
+	}
+
+	if len(cr.Spec.Repo.SOPS.GPGKeySecret) > 0 {
+		volumes = append(volumes, corev1.Volume{
+			Name: "sops-gpg",
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: cr.Spec.Repo.SOPS.GPGKeySecret,
+				},
+			},
+		})
+	}
+	return volumes
+}
@@ -19,6 +19,7 @@ import (
 	"fmt"
 
 	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
 	"github.com/argoproj-labs/argocd-operator/pkg/controller/argoutil"
 	oappsv1 "github.com/openshift/api/apps/v1"
 	template "github.com/openshift/api/template/v1"
@@ -29,6 +30,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/config"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
@@ -58,6 +60,8 @@ const (
 	defaultTemplateIdentifier = "rhsso"
 	// Default name for Keycloak broker.
 	defaultKeycloakBrokerName = "keycloak-broker"
+	// Key of the Argo CD Secret holding the secret of the Keycloak client for Argo CD.
+	keycloakClientSecretKey = "oidc.keycloak.clientSecret"
 )
 
 var (
@@ -82,6 +86,7 @@ type oidcConfig struct {
 	ClientID       string   `json:"clientID"`
 	ClientSecret   string   `json:"clientSecret"`
 	RequestedScope []string `json:"requestedScopes"`
+	RootCA         string   `json:"rootCA,omitempty"`
 }
 
 // IsTemplateAPIAvailable returns true if the template API is present.
@@ -99,6 +104,31 @@ func verifyTemplateAPI() error {
 	return nil
 }
 
+// reconcileKeycloakTemplateInstanceChecksum will delete the existing Keycloak TemplateInstance when its template
+// differs from the desired one, as the template of a TemplateInstance can not be updated, and return a NotFound error
+// so that it is recreated. A TemplateInstance created without a checksum is adopted as is.
+func (r *ReconcileArgoCD) reconcileKeycloakTemplateInstanceChecksum(cr *argoprojv1a1.ArgoCD, existing, desired *template.TemplateInstance) error {
+	checksum := desired.Annotations[common.ArgoCDKeycloakTemplateChecksumAnnotation]
+	current, ok := existing.Annotations[common.ArgoCDKeycloakTemplateChecksumAnnotation]
+	if !ok {
+		if existing.Annotations == nil {
+			existing.Annotations = make(map[string]string)
+		}
+		existing.Annotations[common.ArgoCDKeycloakTemplateChecksumAnnotation] = checksum
+		return r.client.Update(context.TODO(), existing)
+	}
+	if current == checksum {
+		return nil
+	}
+
+	log.Info(fmt.Sprintf("Keycloak options changed, recreating the template instance for ArgoCD %s in namespace %s",
+		cr.Name, cr.Namespace))
+	if err := r.client.Delete(context.TODO(), existing); err != nil {
+		return err
+	}
+	return errors.NewNotFound(template.Resource("templateinstances"), existing.Name)
+}
+
 func (r *ReconcileArgoCD) reconcileSSO(cr *argoprojv1a1.ArgoCD) error {
 	if cr.Spec.SSO.Provider == argoprojv1a1.SSOProviderTypeKeycloak {
 		// TemplateAPI is available, Install keycloack using openshift templates.
@@ -107,8 +137,12 @@ func (r *ReconcileArgoCD) reconcileSSO(cr *argoprojv1a1.ArgoCD) error {
 			if err != nil {
 				return err
 			}
+			existing := &template.TemplateInstance{}
 			err = r.client.Get(context.TODO(), types.NamespacedName{Name: templateInstanceRef.Name,
-				Namespace: templateInstanceRef.Namespace}, &template.TemplateInstance{})
+				Namespace: templateInstanceRef.Namespace}, existing)
+			if err == nil {
+				err = r.reconcileKeycloakTemplateInstanceChecksum(cr, existing, templateInstanceRef)
+			}
 			if err != nil {
 				if errors.IsNotFound(err) {
 					log.Info(fmt.Sprintf("Template API found, Installing keycloak using openshift templates for ArgoCD %s in namespace %s",
@@ -182,6 +216,128 @@ func (r *ReconcileArgoCD) reconcileSSO(cr *argoprojv1a1.ArgoCD) error {
 	return nil
 }
 
+// reconcileSSOClientSecretRotation will rotate the OIDC client secret used between Argo CD and its SSO provider when
+// the rotation annotation is present on the given ArgoCD. The annotation is removed once the rotation is complete.
+func (r *ReconcileArgoCD) reconcileSSOClientSecretRotation(cr *argoprojv1a1.ArgoCD) error {
+	if _, ok := cr.Annotations[common.AnnotationRotateSSOClientSecret]; !ok {
+		return nil // Rotation not requested, move along...
+	}
+
+	if cr.Spec.SSO != nil && cr.Spec.SSO.Provider == argoprojv1a1.SSOProviderTypeKeycloak {
+		if isArgoSecretKeyReferenced(cr, keycloakClientSecretKey) {
+			return r.refuseSSOClientSecretRotation(cr, keycloakClientSecretKey)
+		}
+		if err := r.rotateKeycloakClientSecret(cr); err != nil {
+			return err
+		}
+	} else if isManagedDexEnabled(cr) {
+		// The Dex client secret is derived from the server signature key, regenerating it also ends the sessions of
+		// all users, so it is only done when explicitly requested.
+		if cr.Annotations[common.AnnotationRotateSSOClientSecret] != common.AnnotationRotateSSOClientSecretServerKey {
+			log.Info(fmt.Sprintf("not rotating the Dex client secret for ArgoCD %s in namespace %s, set the %s annotation to %q to regenerate the server signature key",
+				cr.Name, cr.Namespace, common.AnnotationRotateSSOClientSecret, common.AnnotationRotateSSOClientSecretServerKey))
+			return r.removeSSOClientSecretRotationAnnotation(cr)
+		}
+		if isArgoSecretKeyReferenced(cr, common.ArgoCDKeyServerSecretKey) {
+			return r.refuseSSOClientSecretRotation(cr, common.ArgoCDKeyServerSecretKey)
+		}
+		if err := r.rotateDexClientSecret(cr); err != nil {
+			return err
+		}
+	}
+
+	log.Info(fmt.Sprintf("rotated SSO client secret for ArgoCD %s in namespace %s", cr.Name, cr.Namespace))
+	return r.removeSSOClientSecretRotationAnnotation(cr)
+}
+
+// refuseSSOClientSecretRotation will log that the SSO client secret of the given ArgoCD is not rotated, as the given
+// key of the Argo CD Secret is sourced from another Secret through SecretKeys and would be reverted to its value, and
+// remove the rotation annotation.
+func (r *ReconcileArgoCD) refuseSSOClientSecretRotation(cr *argoprojv1a1.ArgoCD, key string) error {
+	log.Info(fmt.Sprintf("not rotating the SSO client secret for ArgoCD %s in namespace %s, the %s key of the %s Secret is sourced from another Secret, rotate it there instead",
+		cr.Name, cr.Namespace, key, common.ArgoCDSecretName))
+	return r.removeSSOClientSecretRotationAnnotation(cr)
+}
+
+// removeSSOClientSecretRotationAnnotation will remove the annotation requesting the rotation of the SSO client secret
+// from the given ArgoCD.
+func (r *ReconcileArgoCD) removeSSOClientSecretRotationAnnotation(cr *argoprojv1a1.ArgoCD) error {
+	// Only the annotation is patched, from a copy, as the spec may hold defaults inherited from the ArgoCDDefault that
+	// the response of the API server would drop.
+	patched := cr.DeepCopy()
+	patch := client.MergeFrom(cr.DeepCopy())
+	delete(patched.Annotations, common.AnnotationRotateSSOClientSecret)
+	if err := r.client.Patch(context.TODO(), patched, patch); err != nil {
+		return err
+	}
+	delete(cr.Annotations, common.AnnotationRotateSSOClientSecret)
+	cr.ResourceVersion = patched.ResourceVersion
+	return nil
+}
+
+// rotateDexClientSecret will rotate the client secret used between Argo CD and Dex. Argo CD derives the Dex client
+// secret from the server signature key, so the key is regenerated and both the Argo CD server and Dex are restarted.
+func (r *ReconcileArgoCD) rotateDexClientSecret(cr *argoprojv1a1.ArgoCD) error {
+	secret := argoutil.NewSecretWithName(cr.ObjectMeta, common.ArgoCDSecretName)
+	if err := argoutil.FetchObject(r.client, cr.Namespace, secret.Name, secret); err != nil {
+		return err
+	}
+
+	sessionKey, err := generateArgoServerSessionKey()
+	if err != nil {
+		return err
+	}
+
+	secret.Data[common.ArgoCDKeyServerSecretKey] = sessionKey
+	if err := r.client.Update(context.TODO(), secret); err != nil {
+		return err
+	}
+
+	if err := r.triggerRollout(newDeploymentWithSuffix("server", "server", cr), "sso.secret.rotated"); err != nil {
+		return err
+	}
+	return r.triggerRollout(newDeploymentWithSuffix("dex-server", "dex-server", cr), "sso.secret.rotated")
+}
+
+// rotateKeycloakClientSecret will rotate the secret of the Argo CD client in Keycloak, update the Argo CD Secret to
+// match and restart the Argo CD server.
+func (r *ReconcileArgoCD) rotateKeycloakClientSecret(cr *argoprojv1a1.ArgoCD) error {
+	existingDC := &oappsv1.DeploymentConfig{}
+	if err := argoutil.FetchObject(r.client, cr.Namespace, defaultKeycloakIdentifier, existingDC); err != nil {
+		return err
+	}
+
+	if existingDC.Annotations["argocd.argoproj.io/realm-created"] != "true" {
+		return fmt.Errorf("keycloak realm for ArgoCD %s in namespace %s has not been created yet", cr.Name, cr.Namespace)
+	}
+
+	cfg, err := r.prepareKeycloakConfig(cr)
+	if err != nil {
+		return err
+	}
+
+	clientSecret, err := generateOAuthClientSecret()
+	if err != nil {
+		return err
+	}
+
+	if err := updateClientSecret(cfg, string(clientSecret)); err != nil {
+		return err
+	}
+
+	secret := argoutil.NewSecretWithName(cr.ObjectMeta, common.ArgoCDSecretName)
+	if err := argoutil.FetchObject(r.client, cr.Namespace, secret.Name, secret); err != nil {
+		return err
+	}
+
+	secret.Data[keycloakClientSecretKey] = clientSecret
+	if err := r.client.Update(context.TODO(), secret); err != nil {
+		return err
+	}
+
+	return r.triggerRollout(newDeploymentWithSuffix("server", "server", cr), "sso.secret.rotated")
+}
+
 func deleteSSOConfiguration(cr *argoprojv1a1.ArgoCD) error {
 
 	// If SSO is installed using OpenShift templates.
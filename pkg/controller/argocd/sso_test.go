@@ -19,6 +19,8 @@ import (
 	"testing"
 
 	argov1alpha1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
+	"github.com/argoproj-labs/argocd-operator/pkg/controller/argoutil"
 	oappsv1 "github.com/openshift/api/apps/v1"
 	routev1 "github.com/openshift/api/route/v1"
 	templatev1 "github.com/openshift/api/template/v1"
@@ -43,6 +45,8 @@ func makeFakeReconciler(t *testing.T, acd *argov1alpha1.ArgoCD, objs ...runtime.
 	cl := fake.NewFakeClientWithScheme(s, objs...)
 	return &ReconcileArgoCD{
 		client: cl,
+		reader: cl,
+		cache:  cl,
 		scheme: s,
 	}
 }
@@ -66,6 +70,30 @@ func TestReconcile_testKeycloakTemplateInstance(t *testing.T) {
 		templateInstance))
 }
 
+func TestReconcile_testKeycloakTemplateInstanceUpdate(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCDForKeycloak()
+
+	templateAPIFound = true
+	r := makeFakeReconciler(t, a)
+	key := types.NamespacedName{Name: "rhsso", Namespace: a.Namespace}
+
+	assert.NilError(t, r.reconcileSSO(a))
+
+	templateInstance := &templatev1.TemplateInstance{}
+	assert.NilError(t, r.client.Get(context.TODO(), key, templateInstance))
+	checksum := templateInstance.Annotations[common.ArgoCDKeycloakTemplateChecksumAnnotation]
+	assert.Assert(t, checksum != "")
+
+	// The TemplateInstance is recreated with the new template when the Keycloak options change.
+	a.Spec.SSO.Keycloak = &argov1alpha1.ArgoCDKeycloakSpec{Host: "sso.example.com"}
+	assert.NilError(t, r.reconcileSSO(a))
+
+	templateInstance = &templatev1.TemplateInstance{}
+	assert.NilError(t, r.client.Get(context.TODO(), key, templateInstance))
+	assert.Assert(t, templateInstance.Annotations[common.ArgoCDKeycloakTemplateChecksumAnnotation] != checksum)
+}
+
 func TestReconcile_noTemplateInstance(t *testing.T) {
 	logf.SetLogger(logf.ZapLogger(true))
 	a := makeTestArgoCDForKeycloak()
@@ -73,3 +101,52 @@ func TestReconcile_noTemplateInstance(t *testing.T) {
 
 	assert.NilError(t, r.reconcileSSO(a))
 }
+
+func TestReconcile_testSSOClientSecretRotation(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD(func(a *argov1alpha1.ArgoCD) {
+		a.Annotations = map[string]string{
+			common.AnnotationRotateSSOClientSecret: "true",
+		}
+	})
+	secret := argoutil.NewSecretWithName(a.ObjectMeta, common.ArgoCDSecretName)
+	secret.Data = map[string][]byte{
+		common.ArgoCDKeyServerSecretKey: []byte("old-key"),
+	}
+	r := makeTestReconciler(t, a, secret)
+
+	// The server signature key is only regenerated for Dex when explicitly requested.
+	assert.NilError(t, r.reconcileSSOClientSecretRotation(a))
+
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: secret.Name, Namespace: a.Namespace}, secret))
+	assert.Equal(t, string(secret.Data[common.ArgoCDKeyServerSecretKey]), "old-key")
+	_, ok := a.Annotations[common.AnnotationRotateSSOClientSecret]
+	assert.Assert(t, !ok)
+
+	a.Annotations[common.AnnotationRotateSSOClientSecret] = common.AnnotationRotateSSOClientSecretServerKey
+	assert.NilError(t, r.client.Update(context.TODO(), a))
+	assert.NilError(t, r.reconcileSSOClientSecretRotation(a))
+
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: secret.Name, Namespace: a.Namespace}, secret))
+	assert.Assert(t, string(secret.Data[common.ArgoCDKeyServerSecretKey]) != "old-key")
+
+	_, ok = a.Annotations[common.AnnotationRotateSSOClientSecret]
+	assert.Assert(t, !ok)
+
+	// The secret is left alone when no rotation is requested.
+	key := secret.Data[common.ArgoCDKeyServerSecretKey]
+	assert.NilError(t, r.reconcileSSOClientSecretRotation(a))
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: secret.Name, Namespace: a.Namespace}, secret))
+	assert.DeepEqual(t, secret.Data[common.ArgoCDKeyServerSecretKey], key)
+
+	// A key sourced from another Secret is not rotated, as it would be reverted to the value of that Secret.
+	a.Spec.SecretKeys = []argov1alpha1.ArgoCDSecretKeySpec{{Key: common.ArgoCDKeyServerSecretKey, SecretName: "server-key"}}
+	a.Annotations[common.AnnotationRotateSSOClientSecret] = common.AnnotationRotateSSOClientSecretServerKey
+	assert.NilError(t, r.client.Update(context.TODO(), a))
+	assert.NilError(t, r.reconcileSSOClientSecretRotation(a))
+
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: secret.Name, Namespace: a.Namespace}, secret))
+	assert.DeepEqual(t, secret.Data[common.ArgoCDKeyServerSecretKey], key)
+	_, ok = a.Annotations[common.AnnotationRotateSSOClientSecret]
+	assert.Assert(t, !ok)
+}
@@ -16,6 +16,8 @@ package argocd
 
 import (
 	"context"
+	"crypto/sha1"
+	"crypto/sha256"
 	"fmt"
 	"reflect"
 	"time"
@@ -30,12 +32,61 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
+// redisConfigChecksumAnnotation is the pod template annotation with the checksum of the Redis HA server
+// configuration, sentinel configuration and init script, so that the Redis HA servers are restarted when any of them
+// changes.
+const redisConfigChecksumAnnotation = "checksum/redis-config"
+
+// redisHASentinelIDs are the IDs of the sentinels of the first Redis HA servers.
+var redisHASentinelIDs = []string{
+	"25b71bd9d0e4a51945d8422cab53f27027397c12",
+	"896627000a81c7bdad8dbdcffd39728c9c17b309",
+	"3acbca861108bc47379b71b1d87d1c137dce591f",
+}
+
+// getRedisConfChecksum will return the checksum of the configuration files rendered for the Redis HA servers of the
+// given ArgoCD, which are only read when the Redis HA servers start.
+func getRedisConfChecksum(cr *argoprojv1a1.ArgoCD) string {
+	hash := sha256.New()
+	for _, conf := range []string{getRedisInitScript(cr), getRedisConf(cr), getRedisSentinelConf(cr)} {
+		hash.Write([]byte(conf))
+		hash.Write([]byte{0})
+	}
+	return fmt.Sprintf("%x", hash.Sum(nil))
+}
+
+// getRedisHAReplicas will return the number of Redis HA servers, each exposed through an announce Service.
 func getRedisHAReplicas(cr *argoprojv1a1.ArgoCD) *int32 {
 	replicas := common.ArgoCDDefaultRedisHAReplicas
-	// TODO: Allow override of this value through CR?
+	if n := cr.Spec.HA.RedisConfig.AnnounceServices; n > 0 {
+		replicas = n
+	}
 	return &replicas
 }
 
+// getRedisHAQuorum will return the number of sentinels that must agree on the Redis HA master, a majority of the
+// Redis HA servers.
+func getRedisHAQuorum(cr *argoprojv1a1.ArgoCD) int32 {
+	return *getRedisHAReplicas(cr)/2 + 1
+}
+
+// getRedisHASentinelEnv will return the environment variables holding the sentinel ID of each Redis HA server, read
+// by the Redis HA init script.
+func getRedisHASentinelEnv(cr *argoprojv1a1.ArgoCD) []corev1.EnvVar {
+	env := make([]corev1.EnvVar, 0)
+	for i := 0; i < int(*getRedisHAReplicas(cr)); i++ {
+		id := fmt.Sprintf("%x", sha1.Sum([]byte(fmt.Sprintf("sentinel-%d", i))))
+		if i < len(redisHASentinelIDs) {
+			id = redisHASentinelIDs[i]
+		}
+		env = append(env, corev1.EnvVar{
+			Name:  fmt.Sprintf("SENTINEL_ID_%d", i),
+			Value: id,
+		})
+	}
+	return env
+}
+
 // newStatefulSet returns a new StatefulSet instance for the given ArgoCD instance.
 func newStatefulSet(cr *argoprojv1a1.ArgoCD) *appsv1.StatefulSet {
 	return &appsv1.StatefulSet{
@@ -84,31 +135,11 @@ func newStatefulSetWithSuffix(suffix string, component string, cr *argoprojv1a1.
 
 func (r *ReconcileArgoCD) reconcileRedisStatefulSet(cr *argoprojv1a1.ArgoCD) error {
 	ss := newStatefulSetWithSuffix("redis-ha-server", "redis", cr)
-	if argoutil.IsObjectFound(r.client, cr.Namespace, ss.Name, ss) {
-		if !cr.Spec.HA.Enabled {
+	if !isRedisHAInUse(cr) {
+		if argoutil.IsObjectFound(r.client, cr.Namespace, ss.Name, ss) {
 			// StatefulSet exists but HA enabled flag has been set to false, delete the StatefulSet
 			return r.client.Delete(context.TODO(), ss)
 		}
-
-		desiredImage := getRedisHAContainerImage(cr)
-		changed := false
-
-		for i, container := range ss.Spec.Template.Spec.Containers {
-			if container.Image != desiredImage {
-				ss.Spec.Template.Spec.Containers[i].Image = getRedisHAContainerImage(cr)
-				changed = true
-			}
-		}
-
-		if changed {
-			ss.Spec.Template.ObjectMeta.Labels["image.upgraded"] = time.Now().UTC().Format("01022006-150406-MST")
-			return r.client.Update(context.TODO(), ss)
-		}
-
-		return nil // StatefulSet found, do nothing
-	}
-
-	if !cr.Spec.HA.Enabled {
 		return nil // HA not enabled, do nothing.
 	}
 
@@ -124,7 +155,8 @@ func (r *ReconcileArgoCD) reconcileRedisStatefulSet(cr *argoprojv1a1.ArgoCD) err
 
 	ss.Spec.Template.ObjectMeta = metav1.ObjectMeta{
 		Annotations: map[string]string{
-			"checksum/init-config": "552ee3bec8fe5d9d865e371f7b615c6d472253649eb65d53ed4ae874f782647c", // TODO: Should this be hard-coded?
+			"checksum/init-config":        "552ee3bec8fe5d9d865e371f7b615c6d472253649eb65d53ed4ae874f782647c", // TODO: Should this be hard-coded?
+			redisConfigChecksumAnnotation: getRedisConfChecksum(cr),
 		},
 		Labels: map[string]string{
 			common.ArgoCDKeyName: nameWithSuffix("redis-ha", cr),
@@ -225,24 +257,11 @@ func (r *ReconcileArgoCD) reconcileRedisStatefulSet(cr *argoprojv1a1.ArgoCD) err
 		Command: []string{
 			"sh",
 		},
-		Env: []corev1.EnvVar{
-			{
-				Name:  "SENTINEL_ID_0",
-				Value: "25b71bd9d0e4a51945d8422cab53f27027397c12", // TODO: Should this be hard-coded?
-			},
-			{
-				Name:  "SENTINEL_ID_1",
-				Value: "896627000a81c7bdad8dbdcffd39728c9c17b309", // TODO: Should this be hard-coded?
-			},
-			{
-				Name:  "SENTINEL_ID_2",
-				Value: "3acbca861108bc47379b71b1d87d1c137dce591f", // TODO: Should this be hard-coded?
-			},
-		},
+		Env:             getRedisHASentinelEnv(cr),
 		Image:           getRedisHAContainerImage(cr),
 		ImagePullPolicy: corev1.PullIfNotPresent,
 		Name:            "config-init",
-		Resources:       getRedisResources(cr),
+		Resources:       getInitContainerResources(cr.Spec.Redis.InitContainerResources, getRedisResources(cr)),
 		VolumeMounts: []corev1.VolumeMount{
 			{
 				MountPath: "/readonly-config",
@@ -266,7 +285,8 @@ func (r *ReconcileArgoCD) reconcileRedisStatefulSet(cr *argoprojv1a1.ArgoCD) err
 		RunAsUser:    &runAsUser,
 	}
 
-	ss.Spec.Template.Spec.ServiceAccountName = nameWithSuffix("argocd-redis-ha", cr)
+	ss.Spec.Template.Spec.ServiceAccountName = getServiceAccountName(common.ArgoCDRedisHAComponent, cr)
+	ss.Spec.Template.Spec.AutomountServiceAccountToken = getRedisAutomountServiceAccountToken(cr)
 
 	ss.Spec.Template.Spec.Volumes = []corev1.Volume{
 		{
@@ -293,6 +313,75 @@ func (r *ReconcileArgoCD) reconcileRedisStatefulSet(cr *argoprojv1a1.ArgoCD) err
 	if err := applyReconcilerHook(cr, ss, ""); err != nil {
 		return err
 	}
+	setContainerCommand(&ss.Spec.Template, cr.Spec.Redis.CommandOverride)
+
+	if err := r.reconcilePodSecurityCompliance(cr, "StatefulSet/"+ss.Name, &ss.Spec.Template); err != nil {
+		return err
+	}
+
+	existing := newStatefulSetWithSuffix("redis-ha-server", "redis", cr)
+	if argoutil.IsObjectFound(r.client, cr.Namespace, existing.Name, existing) {
+		desiredImage := getRedisHAContainerImage(cr)
+		changed := false
+
+		for i, container := range existing.Spec.Template.Spec.Containers {
+			if container.Image != desiredImage {
+				existing.Spec.Template.Spec.Containers[i].Image = getRedisHAContainerImage(cr)
+				existing.Spec.Template.ObjectMeta.Labels["image.upgraded"] = time.Now().UTC().Format("01022006-150406-MST")
+				changed = true
+			}
+		}
+
+		if updatePodServiceAccount(&existing.Spec.Template.Spec, &ss.Spec.Template.Spec) {
+			changed = true
+		}
+
+		if len(existing.Spec.Template.Spec.InitContainers) > 0 &&
+			!reflect.DeepEqual(existing.Spec.Template.Spec.InitContainers[0].Env, getRedisHASentinelEnv(cr)) {
+			existing.Spec.Template.Spec.InitContainers[0].Env = getRedisHASentinelEnv(cr)
+			changed = true
+		}
+
+		initResources := getInitContainerResources(cr.Spec.Redis.InitContainerResources, getRedisResources(cr))
+		if len(existing.Spec.Template.Spec.InitContainers) > 0 &&
+			!reflect.DeepEqual(existing.Spec.Template.Spec.InitContainers[0].Resources, initResources) {
+			existing.Spec.Template.Spec.InitContainers[0].Resources = initResources
+			changed = true
+		}
+
+		if checksum := getRedisConfChecksum(cr); existing.Spec.Template.ObjectMeta.Annotations[redisConfigChecksumAnnotation] != checksum {
+			if existing.Spec.Template.ObjectMeta.Annotations == nil {
+				existing.Spec.Template.ObjectMeta.Annotations = make(map[string]string)
+			}
+			log.Info("redis configuration has changed, rolling out redis ha statefulset")
+			existing.Spec.Template.ObjectMeta.Annotations[redisConfigChecksumAnnotation] = checksum
+			changed = true
+		}
+
+		// The replica count of a hibernated StatefulSet is managed by reconcileHibernation.
+		_, hibernated := existing.Annotations[common.AnnotationHibernatedReplicas]
+		if !cr.Spec.Hibernate && !hibernated && !reflect.DeepEqual(existing.Spec.Replicas, getRedisHAReplicas(cr)) {
+			existing.Spec.Replicas = getRedisHAReplicas(cr)
+			changed = true
+		}
+
+		if updateContainerCommand(&existing.Spec.Template, &ss.Spec.Template) {
+			changed = true
+		}
+
+		if updatePodSecurityContext(&existing.Spec.Template.Spec, &ss.Spec.Template.Spec) {
+			changed = true
+		}
+		if updateContainerSecurityContexts(&existing.Spec.Template.Spec, &ss.Spec.Template.Spec) {
+			changed = true
+		}
+
+		if changed {
+			return r.client.Update(context.TODO(), existing)
+		}
+
+		return nil // StatefulSet found, do nothing
+	}
 
 	if err := controllerutil.SetControllerReference(cr, ss, r.scheme); err != nil {
 		return err
@@ -304,40 +393,48 @@ func (r *ReconcileArgoCD) reconcileApplicationControllerStatefulSet(cr *argoproj
 	var replicas int32 = 1 // TODO: allow override using CR ?
 	ss := newStatefulSetWithSuffix("application-controller", "application-controller", cr)
 	ss.Spec.Replicas = &replicas
+	image := r.getArgoContainerImageForComponent(cr, "application-controller")
+	listenPort := getArgoApplicationControllerListenPort(cr)
+
+	command := getArgoApplicationControllerCommand(cr)
+	if isRepoServerTLSVerificationRequested(cr) {
+		command = append(command, "--repo-server-strict-tls")
+	}
 
 	podSpec := &ss.Spec.Template.Spec
 	podSpec.Containers = []corev1.Container{{
-		Command:         getArgoApplicationControllerCommand(cr),
-		Image:           getArgoContainerImage(cr),
+		Command:         command,
+		Image:           image,
 		ImagePullPolicy: corev1.PullAlways,
 		Name:            "argocd-application-controller",
 		LivenessProbe: &corev1.Probe{
 			Handler: corev1.Handler{
 				HTTPGet: &corev1.HTTPGetAction{
 					Path: "/healthz",
-					Port: intstr.FromInt(8082),
+					Port: intstr.FromInt(int(listenPort)),
 				},
 			},
 			InitialDelaySeconds: 5,
 			PeriodSeconds:       10,
 		},
-		Env: proxyEnvVars(),
+		Env: getArgoApplicationControllerEnv(cr),
 		Ports: []corev1.ContainerPort{
 			{
-				ContainerPort: 8082,
+				ContainerPort: listenPort,
 			},
 		},
 		ReadinessProbe: &corev1.Probe{
 			Handler: corev1.Handler{
 				HTTPGet: &corev1.HTTPGetAction{
 					Path: "/healthz",
-					Port: intstr.FromInt(8082),
+					Port: intstr.FromInt(int(listenPort)),
 				},
 			},
 			InitialDelaySeconds: 5,
 			PeriodSeconds:       10,
 		},
-		Resources: getArgoApplicationControllerResources(cr),
+		Resources:    getArgoApplicationControllerResources(cr),
+		StartupProbe: getArgoApplicationControllerStartupProbe(cr),
 		VolumeMounts: []corev1.VolumeMount{
 			{
 				Name:      "argocd-repo-server-tls",
@@ -345,7 +442,8 @@ func (r *ReconcileArgoCD) reconcileApplicationControllerStatefulSet(cr *argoproj
 			},
 		},
 	}}
-	podSpec.ServiceAccountName = nameWithSuffix("argocd-application-controller", cr)
+	podSpec.ServiceAccountName = getServiceAccountName(common.ArgoCDApplicationControllerComponent, cr)
+	podSpec.AutomountServiceAccountToken = cr.Spec.Controller.AutomountServiceAccountToken
 	podSpec.Volumes = []corev1.Volume{
 		{
 			Name: "argocd-repo-server-tls",
@@ -392,8 +490,8 @@ func (r *ReconcileArgoCD) reconcileApplicationControllerStatefulSet(cr *argoproj
 	} else {
 		podSpec.InitContainers = []corev1.Container{{
 			Command:         getArgoImportCommand(r.client, cr),
-			Env:             proxyEnvVars(getArgoImportContainerEnv(export)...),
-			Resources:       getArgoApplicationControllerResources(cr),
+			Env:             proxyEnvVars(cr, getArgoImportContainerEnv(export)...),
+			Resources:       getInitContainerResources(cr.Spec.Controller.InitContainerResources, getArgoApplicationControllerResources(cr)),
 			Image:           getArgoImportContainerImage(export),
 			ImagePullPolicy: corev1.PullAlways,
 			Name:            "argocd-import",
@@ -402,23 +500,38 @@ func (r *ReconcileArgoCD) reconcileApplicationControllerStatefulSet(cr *argoproj
 
 		podSpec.Volumes = getArgoImportVolumes(export)
 	}
+	if isArgoApplicationControllerMetricsTLSEnabled(cr) {
+		podSpec.Containers = append(podSpec.Containers, getArgoApplicationControllerMetricsTLSProxyContainer(cr))
+		podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+			Name: "argocd-application-controller-metrics-tls",
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: cr.Spec.Controller.Metrics.TLS.SecretName,
+				},
+			},
+		})
+	}
+	setPodDNS(podSpec, cr.Spec.Controller.HostAliases, cr.Spec.Controller.DNSConfig, cr.Spec.Controller.DNSPolicy)
+	if err := applyReconcilerHook(cr, ss, ""); err != nil {
+		return err
+	}
+	setContainerCommand(&ss.Spec.Template, cr.Spec.Controller.CommandOverride)
+
+	if err := r.reconcilePodSecurityCompliance(cr, "StatefulSet/"+ss.Name, &ss.Spec.Template); err != nil {
+		return err
+	}
 
 	existing := newStatefulSetWithSuffix("application-controller", "application-controller", cr)
 	if argoutil.IsObjectFound(r.client, cr.Namespace, existing.Name, existing) {
 		actualImage := existing.Spec.Template.Spec.Containers[0].Image
-		desiredImage := getArgoContainerImage(cr)
+		desiredImage := image
 		changed := false
 		if actualImage != desiredImage {
 			existing.Spec.Template.Spec.Containers[0].Image = desiredImage
 			existing.Spec.Template.ObjectMeta.Labels["image.upgraded"] = time.Now().UTC().Format("01022006-150406-MST")
 			changed = true
 		}
-		desiredCommand := getArgoApplicationControllerCommand(cr)
-		if isRepoServerTLSVerificationRequested(cr) {
-			desiredCommand = append(desiredCommand, "--repo-server-strict-tls")
-		}
-		if !reflect.DeepEqual(desiredCommand, existing.Spec.Template.Spec.Containers[0].Command) {
-			existing.Spec.Template.Spec.Containers[0].Command = desiredCommand
+		if updateContainerCommand(&existing.Spec.Template, &ss.Spec.Template) {
 			changed = true
 		}
 
@@ -436,6 +549,27 @@ func (r *ReconcileArgoCD) reconcileApplicationControllerStatefulSet(cr *argoproj
 			existing.Spec.Template.Spec.Containers[0].VolumeMounts = ss.Spec.Template.Spec.Containers[0].VolumeMounts
 			changed = true
 		}
+		if ports := existing.Spec.Template.Spec.Containers[0].Ports; len(ports) != 1 || ports[0].ContainerPort != listenPort {
+			existing.Spec.Template.Spec.Containers[0].Ports = ss.Spec.Template.Spec.Containers[0].Ports
+			existing.Spec.Template.Spec.Containers[0].LivenessProbe = ss.Spec.Template.Spec.Containers[0].LivenessProbe
+			existing.Spec.Template.Spec.Containers[0].ReadinessProbe = ss.Spec.Template.Spec.Containers[0].ReadinessProbe
+			changed = true
+		}
+		if probeChanged(existing.Spec.Template.Spec.Containers[0].StartupProbe,
+			ss.Spec.Template.Spec.Containers[0].StartupProbe) {
+			existing.Spec.Template.Spec.Containers[0].StartupProbe = ss.Spec.Template.Spec.Containers[0].StartupProbe
+			changed = true
+		}
+		if sidecarContainersChanged(existing.Spec.Template.Spec.Containers[1:], ss.Spec.Template.Spec.Containers[1:]) {
+			existing.Spec.Template.Spec.Containers = append(existing.Spec.Template.Spec.Containers[:1], ss.Spec.Template.Spec.Containers[1:]...)
+			changed = true
+		}
+		if updatePodDNS(&existing.Spec.Template.Spec, &ss.Spec.Template.Spec) {
+			changed = true
+		}
+		if updatePodServiceAccount(&existing.Spec.Template.Spec, &ss.Spec.Template.Spec) {
+			changed = true
+		}
 
 		if changed {
 			return r.client.Update(context.TODO(), existing)
@@ -455,6 +589,53 @@ func (r *ReconcileArgoCD) reconcileApplicationControllerStatefulSet(cr *argoproj
 	return r.client.Create(context.TODO(), ss)
 }
 
+// getArgoApplicationControllerMetricsTLSProxyContainer will return the container that serves the metrics of the
+// Application Controller over TLS, only accepting clients with a certificate signed by the CA of the metrics Secret.
+func getArgoApplicationControllerMetricsTLSProxyContainer(cr *argoprojv1a1.ArgoCD) corev1.Container {
+	port := getArgoApplicationControllerMetricsPort(cr)
+	return corev1.Container{
+		Args: []string{
+			"server",
+			"--listen", fmt.Sprintf("0.0.0.0:%d", port),
+			"--target", fmt.Sprintf("127.0.0.1:%d", getArgoApplicationControllerListenPort(cr)),
+			"--cert", "/app/config/metrics/tls/tls.crt",
+			"--key", "/app/config/metrics/tls/tls.key",
+			"--cacert", "/app/config/metrics/tls/ca.crt",
+			"--allow-all",
+		},
+		Image:           getMetricsTLSProxyContainerImage(cr),
+		ImagePullPolicy: corev1.PullIfNotPresent,
+		Name:            "metrics-tls-proxy",
+		Ports: []corev1.ContainerPort{
+			{
+				ContainerPort: port,
+				Name:          common.ArgoCDKeyMetrics,
+			},
+		},
+		VolumeMounts: []corev1.VolumeMount{
+			{
+				Name:      "argocd-application-controller-metrics-tls",
+				MountPath: "/app/config/metrics/tls",
+				ReadOnly:  true,
+			},
+		},
+	}
+}
+
+// sidecarContainersChanged will return true when the name, image or arguments of the given sidecar containers differ.
+func sidecarContainersChanged(actual, desired []corev1.Container) bool {
+	if len(actual) != len(desired) {
+		return true
+	}
+	for i := range desired {
+		if actual[i].Name != desired[i].Name || actual[i].Image != desired[i].Image ||
+			!reflect.DeepEqual(actual[i].Args, desired[i].Args) {
+			return true
+		}
+	}
+	return false
+}
+
 // reconcileStatefulSets will ensure that all StatefulSets are present for the given ArgoCD.
 func (r *ReconcileArgoCD) reconcileStatefulSets(cr *argoprojv1a1.ArgoCD) error {
 	if err := r.reconcileApplicationControllerStatefulSet(cr); err != nil {
@@ -6,7 +6,9 @@ import (
 	argoprojv1alpha1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
 	resourcev1 "k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"os"
 	"testing"
+	"time"
 
 	"github.com/argoproj-labs/argocd-operator/pkg/common"
 	"github.com/google/go-cmp/cmp"
@@ -16,6 +18,7 @@ import (
 	"gotest.tools/assert"
 
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
 )
 
@@ -86,6 +89,118 @@ func TestReconcileArgoCD_reconcileRedisStatefulSet_HA_enabled(t *testing.T) {
 	assert.ErrorContains(t, r.client.Get(context.TODO(), types.NamespacedName{Name: s.Name, Namespace: a.Namespace}, s), "not found")
 }
 
+func TestReconcileArgoCD_reconcileRedisStatefulSet_HA_securityContext(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.HA.Enabled = true
+	})
+	r := makeTestReconciler(t, a)
+	assert.NilError(t, r.reconcileRedisStatefulSet(a))
+
+	s := &appsv1.StatefulSet{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-redis-ha-server", Namespace: a.Namespace}, s))
+	want := s.Spec.Template.Spec.SecurityContext.DeepCopy()
+
+	// test the security contexts are restored on reconciliation
+	privileged := true
+	s.Spec.Template.Spec.SecurityContext = nil
+	s.Spec.Template.Spec.Containers[0].SecurityContext = &corev1.SecurityContext{Privileged: &privileged}
+	assert.NilError(t, r.client.Update(context.TODO(), s))
+	assert.NilError(t, r.reconcileRedisStatefulSet(a))
+
+	s = &appsv1.StatefulSet{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-redis-ha-server", Namespace: a.Namespace}, s))
+	assert.DeepEqual(t, s.Spec.Template.Spec.SecurityContext, want)
+	assert.Assert(t, s.Spec.Template.Spec.Containers[0].SecurityContext == nil)
+}
+
+func TestReconcileArgoCD_reconcileRedisStatefulSet_HA_serviceAccount(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.HA.Enabled = true
+	})
+	r := makeTestReconciler(t, a)
+	assert.NilError(t, r.reconcileRedisStatefulSet(a))
+
+	// test the service account options are applied to an existing StatefulSet
+	automount := true
+	a.Spec.HA.ServiceAccountName = "platform-redis-ha"
+	a.Spec.HA.AutomountServiceAccountToken = &automount
+	assert.NilError(t, r.reconcileRedisStatefulSet(a))
+
+	s := &appsv1.StatefulSet{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-redis-ha-server", Namespace: a.Namespace}, s))
+	assert.Equal(t, s.Spec.Template.Spec.ServiceAccountName, "platform-redis-ha")
+	assert.DeepEqual(t, s.Spec.Template.Spec.AutomountServiceAccountToken, &automount)
+}
+
+func TestReconcileArgoCD_reconcileRedisStatefulSet_HA_announceServices(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.HA.Enabled = true
+	})
+	r := makeTestReconciler(t, a)
+	assert.NilError(t, r.reconcileRedisStatefulSet(a))
+
+	s := &appsv1.StatefulSet{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-redis-ha-server", Namespace: a.Namespace}, s))
+	assert.Equal(t, *s.Spec.Replicas, int32(3))
+	assert.Equal(t, len(s.Spec.Template.Spec.InitContainers[0].Env), 3)
+
+	// test replicas and sentinel IDs follow the number of announce services
+	a.Spec.HA.RedisConfig.AnnounceServices = 5
+	assert.NilError(t, r.reconcileRedisStatefulSet(a))
+
+	s = &appsv1.StatefulSet{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-redis-ha-server", Namespace: a.Namespace}, s))
+	assert.Equal(t, *s.Spec.Replicas, int32(5))
+	env := s.Spec.Template.Spec.InitContainers[0].Env
+	assert.Equal(t, len(env), 5)
+	assert.Equal(t, env[0].Value, redisHASentinelIDs[0])
+	assert.Equal(t, env[4].Name, "SENTINEL_ID_4")
+	assert.Equal(t, len(env[4].Value), 40)
+}
+
+func TestReconcileArgoCD_reconcileRedisStatefulSet_HA_redisConfig(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	os.Setenv("REDIS_CONFIG_PATH", "../../../build/redis")
+	t.Cleanup(func() {
+		os.Unsetenv("REDIS_CONFIG_PATH")
+	})
+
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.HA.Enabled = true
+	})
+	r := makeTestReconciler(t, a)
+	assert.NilError(t, r.reconcileRedisStatefulSet(a))
+
+	s := &appsv1.StatefulSet{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-redis-ha-server", Namespace: a.Namespace}, s))
+	checksum := s.Spec.Template.Annotations[redisConfigChecksumAnnotation]
+	assert.Equal(t, checksum, getRedisConfChecksum(a))
+
+	// test the Redis HA servers are restarted when the configuration changes
+	a.Spec.Redis.Config.MaxMemory = "1gb"
+	assert.NilError(t, r.reconcileRedisStatefulSet(a))
+
+	s = &appsv1.StatefulSet{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-redis-ha-server", Namespace: a.Namespace}, s))
+	assert.Assert(t, s.Spec.Template.Annotations[redisConfigChecksumAnnotation] != checksum)
+	assert.Equal(t, s.Spec.Template.Annotations[redisConfigChecksumAnnotation], getRedisConfChecksum(a))
+
+	// test the Redis HA servers are restarted when the sentinel configuration changes
+	checksum = s.Spec.Template.Annotations[redisConfigChecksumAnnotation]
+	a.Spec.HA.RedisConfig.DownAfterMilliseconds = 5000
+	assert.NilError(t, r.reconcileRedisStatefulSet(a))
+
+	s = &appsv1.StatefulSet{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-redis-ha-server", Namespace: a.Namespace}, s))
+	assert.Assert(t, s.Spec.Template.Annotations[redisConfigChecksumAnnotation] != checksum)
+}
+
 func TestReconcileArgoCD_reconcileApplicationController(t *testing.T) {
 	logf.SetLogger(logf.ZapLogger(true))
 	a := makeTestArgoCD()
@@ -204,5 +319,141 @@ func TestReconcileArgoCD_reconcileApplicationController_withResources(t *testing
 		},
 	}
 	assert.DeepEqual(t, ss.Spec.Template.Spec.Containers[0].Resources, testResources)
-	assert.DeepEqual(t, ss.Spec.Template.Spec.InitContainers[0].Resources, testResources)
+	assert.DeepEqual(t, ss.Spec.Template.Spec.InitContainers[0].Resources, defaultInitContainerResources())
+}
+
+func TestReconcileArgoCD_reconcileApplicationController_withSharding(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD()
+	r := makeTestReconciler(t, a)
+
+	assert.NilError(t, r.reconcileApplicationControllerStatefulSet(a))
+
+	a.Spec.Version = "v2.8.0"
+	a.Spec.Controller.Sharding.Algorithm = "round-robin"
+	assert.NilError(t, r.reconcileApplicationControllerStatefulSet(a))
+
+	ss := &appsv1.StatefulSet{}
+	assert.NilError(t, r.client.Get(
+		context.TODO(),
+		types.NamespacedName{
+			Name:      "argocd-application-controller",
+			Namespace: a.Namespace,
+		},
+		ss))
+	want := []corev1.EnvVar{
+		{Name: common.ArgoCDControllerShardingAlgorithmEnvName, Value: "round-robin"},
+	}
+	assert.DeepEqual(t, ss.Spec.Template.Spec.Containers[0].Env, want)
+
+	// Versions of Argo CD without sharding algorithms are not given the option.
+	assert.Equal(t, len(getArgoApplicationControllerEnv(makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.Controller.Sharding.Algorithm = "round-robin"
+	}))), 0)
+}
+
+func TestReconcileArgoCD_reconcileApplicationController_withClusterCache(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.Controller.ClusterCache = argoprojv1alpha1.ArgoCDApplicationControllerClusterCacheSpec{
+			ListPageSize:        250,
+			ListSemaphore:       25,
+			ResyncDuration:      &metav1.Duration{Duration: 12 * time.Hour},
+			WatchResyncDuration: &metav1.Duration{Duration: 10 * time.Minute},
+		}
+	})
+	r := makeTestReconciler(t, a)
+
+	assert.NilError(t, r.reconcileApplicationControllerStatefulSet(a))
+
+	ss := &appsv1.StatefulSet{}
+	assert.NilError(t, r.client.Get(
+		context.TODO(),
+		types.NamespacedName{
+			Name:      "argocd-application-controller",
+			Namespace: a.Namespace,
+		},
+		ss))
+	want := []corev1.EnvVar{
+		{Name: common.ArgoCDControllerClusterCacheListPageSizeEnvName, Value: "250"},
+		{Name: common.ArgoCDControllerClusterCacheListSemaphoreEnvName, Value: "25"},
+		{Name: common.ArgoCDControllerClusterCacheResyncDurationEnvName, Value: "12h0m0s"},
+		{Name: common.ArgoCDControllerClusterCacheWatchResyncDurationEnvName, Value: "10m0s"},
+	}
+	assert.DeepEqual(t, ss.Spec.Template.Spec.Containers[0].Env, want)
+}
+
+func TestReconcileArgoCD_reconcileApplicationController_withStartupProbe(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	probe := &corev1.Probe{
+		Handler: corev1.Handler{
+			TCPSocket: &corev1.TCPSocketAction{
+				Port: intstr.FromInt(8082),
+			},
+		},
+		FailureThreshold: 90,
+		PeriodSeconds:    20,
+	}
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.Controller.StartupProbe = probe
+	})
+	r := makeTestReconciler(t, a)
+
+	assert.NilError(t, r.reconcileApplicationControllerStatefulSet(a))
+
+	ss := &appsv1.StatefulSet{}
+	assert.NilError(t, r.client.Get(
+		context.TODO(),
+		types.NamespacedName{
+			Name:      "argocd-application-controller",
+			Namespace: a.Namespace,
+		},
+		ss))
+	assert.DeepEqual(t, ss.Spec.Template.Spec.Containers[0].StartupProbe, probe)
+}
+
+func TestReconcileArgoCD_reconcileApplicationController_withMetrics(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.Controller.Metrics.TLS = &argoprojv1alpha1.ArgoCDApplicationControllerMetricsTLSSpec{SecretName: "metrics-tls"}
+	})
+	r := makeTestReconciler(t, a)
+
+	assert.NilError(t, r.reconcileApplicationControllerStatefulSet(a))
+
+	ss := &appsv1.StatefulSet{}
+	key := types.NamespacedName{Name: "argocd-application-controller", Namespace: a.Namespace}
+	assert.NilError(t, r.client.Get(context.TODO(), key, ss))
+
+	// The controller keeps the default port and the metrics are served over TLS by the proxy.
+	containers := ss.Spec.Template.Spec.Containers
+	assert.Equal(t, len(containers), 2)
+	assert.Equal(t, containers[0].Ports[0].ContainerPort, int32(8082))
+	assert.Equal(t, containers[1].Name, "metrics-tls-proxy")
+	assert.Equal(t, containers[1].Ports[0].ContainerPort, int32(8443))
+	assert.Equal(t, containers[1].Args[4], "127.0.0.1:8082")
+	wantVolumes := append(controllerDefaultVolumes(), corev1.Volume{
+		Name: "argocd-application-controller-metrics-tls",
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{SecretName: "metrics-tls"},
+		},
+	})
+	if diff := cmp.Diff(wantVolumes, ss.Spec.Template.Spec.Volumes); diff != "" {
+		t.Fatalf("reconciliation failed:\n%s", diff)
+	}
+
+	// Disabling TLS removes the proxy and the controller listens on the metrics port.
+	a.Spec.Controller.Metrics = argoprojv1alpha1.ArgoCDApplicationControllerMetricsSpec{Port: 9090}
+	assert.NilError(t, r.reconcileApplicationControllerStatefulSet(a))
+	assert.NilError(t, r.client.Get(context.TODO(), key, ss))
+
+	containers = ss.Spec.Template.Spec.Containers
+	assert.Equal(t, len(containers), 1)
+	assert.Equal(t, containers[0].Ports[0].ContainerPort, int32(9090))
+	assert.Equal(t, containers[0].LivenessProbe.HTTPGet.Port.IntValue(), 9090)
+	assert.Equal(t, containers[0].ReadinessProbe.HTTPGet.Port.IntValue(), 9090)
+	assert.DeepEqual(t, containers[0].Command[len(containers[0].Command)-2:], []string{"--metrics-port", "9090"})
+	if diff := cmp.Diff(controllerDefaultVolumes(), ss.Spec.Template.Spec.Volumes); diff != "" {
+		t.Fatalf("reconciliation failed:\n%s", diff)
+	}
 }
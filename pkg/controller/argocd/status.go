@@ -16,25 +16,94 @@ package argocd
 
 import (
 	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
 	"github.com/argoproj-labs/argocd-operator/pkg/controller/argoutil"
 )
 
+var (
+	// applicationGVK is the GroupVersionKind of the Argo CD Application resource.
+	applicationGVK = schema.GroupVersionKind{Group: "argoproj.io", Version: "v1alpha1", Kind: "Application"}
+
+	// appProjectGVK is the GroupVersionKind of the Argo CD AppProject resource.
+	appProjectGVK = schema.GroupVersionKind{Group: "argoproj.io", Version: "v1alpha1", Kind: "AppProject"}
+)
+
+// updateArgoCDStatus will update the status of the given ArgoCD. The update is sent from a copy, as the response of the
+// API server would otherwise replace the spec of the given ArgoCD, dropping the defaults inherited from the
+// ArgoCDDefault for the rest of the reconcile.
+func (r *ReconcileArgoCD) updateArgoCDStatus(cr *argoprojv1a1.ArgoCD) error {
+	updated := cr.DeepCopy()
+	if err := r.client.Status().Update(context.TODO(), updated); err != nil {
+		return err
+	}
+	cr.ResourceVersion = updated.ResourceVersion
+	return nil
+}
+
 // reconcileStatus will ensure that all of the Status properties are updated for the given ArgoCD.
 func (r *ReconcileArgoCD) reconcileStatus(cr *argoprojv1a1.ArgoCD) error {
 	if err := r.reconcileStatusApplicationController(cr); err != nil {
 		return err
 	}
 
+	if err := r.reconcileStatusApplications(cr); err != nil {
+		return err
+	}
+
+	if err := r.reconcileStatusControllerProcessors(cr); err != nil {
+		return err
+	}
+
 	if err := r.reconcileStatusDex(cr); err != nil {
 		return err
 	}
 
+	if err := r.reconcileStatusDexMigration(cr); err != nil {
+		return err
+	}
+
+	if err := r.reconcileStatusCommandOverrides(cr); err != nil {
+		return err
+	}
+
+	if err := r.reconcileStatusConditions(cr); err != nil {
+		return err
+	}
+
+	if err := r.reconcileStatusHost(cr); err != nil {
+		return err
+	}
+
+	if err := r.reconcileStatusImages(cr); err != nil {
+		return err
+	}
+
+	if err := r.reconcileStatusManagedNamespaces(cr); err != nil {
+		return err
+	}
+
 	if err := r.reconcileStatusPhase(cr); err != nil {
 		return err
 	}
 
+	if err := r.reconcileStatusPodSecurity(cr); err != nil {
+		return err
+	}
+
 	if err := r.reconcileStatusRedis(cr); err != nil {
 		return err
 	}
@@ -46,6 +115,10 @@ func (r *ReconcileArgoCD) reconcileStatus(cr *argoprojv1a1.ArgoCD) error {
 	if err := r.reconcileStatusServer(cr); err != nil {
 		return err
 	}
+
+	if err := r.reconcileStatusVersion(cr); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -66,11 +139,27 @@ func (r *ReconcileArgoCD) reconcileStatusApplicationController(cr *argoprojv1a1.
 
 	if cr.Status.ApplicationController != status {
 		cr.Status.ApplicationController = status
-		return r.client.Status().Update(context.TODO(), cr)
+		return r.updateArgoCDStatus(cr)
 	}
 	return nil
 }
 
+// listCachedArgoObjects will return the Argo CD objects of the given kind in the namespace of the given ArgoCD. The
+// objects are read from the cache, so that the apiserver is not asked for every object on each reconcile. No objects
+// are returned when the Argo CD CRDs are not installed, or the kind is unknown to the client, e.g. the in-memory client
+// used when rendering manifests.
+func (r *ReconcileArgoCD) listCachedArgoObjects(cr *argoprojv1a1.ArgoCD, gvk schema.GroupVersionKind) ([]unstructured.Unstructured, error) {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+	if err := r.cache.List(context.TODO(), list, client.InNamespace(cr.Namespace)); err != nil {
+		if meta.IsNoMatchError(err) || runtime.IsNotRegisteredError(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to list %s objects for %s: %w", gvk.Kind, cr.Name, err)
+	}
+	return list.Items, nil
+}
+
 // reconcileStatusDex will ensure that the Dex status is updated for the given ArgoCD.
 func (r *ReconcileArgoCD) reconcileStatusDex(cr *argoprojv1a1.ArgoCD) error {
 	status := "Unknown"
@@ -88,7 +177,274 @@ func (r *ReconcileArgoCD) reconcileStatusDex(cr *argoprojv1a1.ArgoCD) error {
 
 	if cr.Status.Dex != status {
 		cr.Status.Dex = status
-		return r.client.Status().Update(context.TODO(), cr)
+		return r.updateArgoCDStatus(cr)
+	}
+	return nil
+}
+
+// reconcileStatusCommandOverrides will ensure that the CommandOverridden condition lists the component containers
+// whose command is overridden for the given ArgoCD, as the operator no longer manages the flags of those containers.
+func (r *ReconcileArgoCD) reconcileStatusCommandOverrides(cr *argoprojv1a1.ArgoCD) error {
+	overrides := map[string]argoprojv1a1.ArgoCDCommandOverrideSpec{
+		"application-controller": cr.Spec.Controller.CommandOverride,
+		"dex":                    getDexSpec(cr).CommandOverride,
+		"image-updater":          cr.Spec.ImageUpdater.CommandOverride,
+		"redis":                  cr.Spec.Redis.CommandOverride,
+		"repo-server":            cr.Spec.Repo.CommandOverride,
+		"server":                 cr.Spec.Server.CommandOverride,
+	}
+	if cr.Spec.ApplicationSet != nil {
+		overrides["applicationset-controller"] = cr.Spec.ApplicationSet.CommandOverride
+	}
+
+	components := make([]string, 0)
+	for component, override := range overrides {
+		if len(override.Command) > 0 || len(override.Args) > 0 {
+			components = append(components, component)
+		}
+	}
+	sort.Strings(components)
+
+	condition := argoprojv1a1.ArgoCDCondition{
+		Type:   argoprojv1a1.ArgoCDConditionTypeCommandOverridden,
+		Status: corev1.ConditionFalse,
+		Reason: "OperatorManaged",
+	}
+	if len(components) > 0 {
+		condition.Status = corev1.ConditionTrue
+		condition.Reason = "CommandOverridden"
+		condition.Message = fmt.Sprintf("the operator no longer manages the flags of the overridden containers: %s", strings.Join(components, ", "))
+	}
+
+	if setArgoCDCondition(cr, condition) {
+		return r.updateArgoCDStatus(cr)
+	}
+	return nil
+}
+
+// reconcileStatusConditions will ensure that the Status Conditions are updated for the given ArgoCD. The ArgoCD is
+// marked as Degraded while any of the component Deployments has failed to roll out, or while the pods of any of the
+// component Deployments or StatefulSets are crash looping. The resource usage snapshot and the ResourcePressure
+// condition are written with the other conditions.
+func (r *ReconcileArgoCD) reconcileStatusConditions(cr *argoprojv1a1.ArgoCD) error {
+	deploys := &appsv1.DeploymentList{}
+	if err := r.client.List(context.TODO(), deploys, managedResourceListOptions(cr)...); err != nil {
+		return err
+	}
+
+	statefulsets := &appsv1.StatefulSetList{}
+	if err := r.client.List(context.TODO(), statefulsets, managedResourceListOptions(cr)...); err != nil {
+		return err
+	}
+
+	failures := make([]string, 0)
+	for i := range deploys.Items {
+		msg, err := r.getDeploymentRolloutFailure(&deploys.Items[i])
+		if err != nil {
+			return err
+		}
+		if msg != "" {
+			failures = append(failures, fmt.Sprintf("%s: %s", deploys.Items[i].Name, msg))
+		}
+	}
+	for i := range statefulsets.Items {
+		msg, err := r.getPodsFailure(statefulsets.Items[i].Namespace, statefulsets.Items[i].Spec.Selector)
+		if err != nil {
+			return err
+		}
+		if msg != "" {
+			failures = append(failures, fmt.Sprintf("%s: %s", statefulsets.Items[i].Name, msg))
+		}
+	}
+	sort.Strings(failures)
+
+	condition := argoprojv1a1.ArgoCDCondition{
+		Type:   argoprojv1a1.ArgoCDConditionTypeDegraded,
+		Status: corev1.ConditionFalse,
+		Reason: "RolloutSucceeded",
+	}
+	if len(failures) > 0 {
+		condition.Status = corev1.ConditionTrue
+		condition.Reason = "RolloutFailed"
+		condition.Message = strings.Join(failures, "; ")
+	}
+
+	changed := setArgoCDCondition(cr, condition)
+
+	if tlsCondition := r.getServerTLSSecretCondition(cr); tlsCondition != nil {
+		if setArgoCDCondition(cr, *tlsCondition) {
+			if tlsCondition.Status == corev1.ConditionFalse {
+				log.Info(fmt.Sprintf("ArgoCD %s: %s", cr.Name, tlsCondition.Message))
+				if err := argoutil.CreateEvent(r.client, "WaitingForSecret", tlsCondition.Message, tlsCondition.Reason, cr.ObjectMeta); err != nil {
+					return err
+				}
+			}
+			changed = true
+		}
+	} else if removeArgoCDCondition(cr, argoprojv1a1.ArgoCDConditionTypeServerTLSSecretValid) {
+		changed = true
+	}
+
+	if r.setStatusResourceUsage(cr) {
+		changed = true
+	}
+
+	if changed {
+		return r.updateArgoCDStatus(cr)
+	}
+	return nil
+}
+
+// getDeploymentRolloutFailure will return a message describing why the given Deployment has failed to roll out,
+// or an empty string if the rollout has not failed.
+func (r *ReconcileArgoCD) getDeploymentRolloutFailure(deploy *appsv1.Deployment) (string, error) {
+	for _, c := range deploy.Status.Conditions {
+		if c.Type == appsv1.DeploymentProgressing && c.Status == corev1.ConditionFalse && c.Reason == "ProgressDeadlineExceeded" {
+			return c.Message, nil
+		}
+		if c.Type == appsv1.DeploymentReplicaFailure && c.Status == corev1.ConditionTrue {
+			return c.Message, nil
+		}
+	}
+
+	return r.getPodsFailure(deploy.Namespace, deploy.Spec.Selector)
+}
+
+// getPodsFailure will return a message describing why the pods selected by the given selector are failing, or an
+// empty string if none of them is failing.
+func (r *ReconcileArgoCD) getPodsFailure(namespace string, selector *metav1.LabelSelector) (string, error) {
+	if selector == nil {
+		return "", nil
+	}
+	pods := &corev1.PodList{}
+	if err := r.client.List(context.TODO(), pods, client.InNamespace(namespace), client.MatchingLabels(selector.MatchLabels)); err != nil {
+		return "", err
+	}
+	for _, pod := range pods.Items {
+		for _, cs := range pod.Status.ContainerStatuses {
+			if cs.State.Waiting != nil && cs.State.Waiting.Reason == "CrashLoopBackOff" {
+				return fmt.Sprintf("container %s in pod %s is in CrashLoopBackOff: %s", cs.Name, pod.Name, cs.State.Waiting.Message), nil
+			}
+		}
+	}
+	return "", nil
+}
+
+// isArgoCDConditionTrue will return true if the condition of the given type is set to True on the given ArgoCD.
+func isArgoCDConditionTrue(cr *argoprojv1a1.ArgoCD, conditionType string) bool {
+	for _, c := range cr.Status.Conditions {
+		if c.Type == conditionType {
+			return c.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// setArgoCDCondition will add or update the given condition on the given ArgoCD, returning true if the Status was
+// changed.
+func setArgoCDCondition(cr *argoprojv1a1.ArgoCD, condition argoprojv1a1.ArgoCDCondition) bool {
+	for i, c := range cr.Status.Conditions {
+		if c.Type != condition.Type {
+			continue
+		}
+		if c.Status == condition.Status && c.Reason == condition.Reason && c.Message == condition.Message {
+			return false
+		}
+		condition.LastTransitionTime = c.LastTransitionTime
+		if c.Status != condition.Status {
+			condition.LastTransitionTime = metav1.Now()
+		}
+		cr.Status.Conditions[i] = condition
+		return true
+	}
+
+	condition.LastTransitionTime = metav1.Now()
+	cr.Status.Conditions = append(cr.Status.Conditions, condition)
+	return true
+}
+
+// removeArgoCDCondition will remove the condition of the given type from the given ArgoCD, returning true if the
+// Status was changed.
+func removeArgoCDCondition(cr *argoprojv1a1.ArgoCD, conditionType string) bool {
+	for i, c := range cr.Status.Conditions {
+		if c.Type == conditionType {
+			cr.Status.Conditions = append(cr.Status.Conditions[:i], cr.Status.Conditions[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// reconcileStatusHost will ensure that the Host Status is updated with the hostname of the Argo CD server for the
+// given ArgoCD.
+func (r *ReconcileArgoCD) reconcileStatusHost(cr *argoprojv1a1.ArgoCD) error {
+	host := strings.TrimPrefix(r.getArgoServerURI(cr), "https://")
+
+	if cr.Status.Host != host {
+		cr.Status.Host = host
+		return r.updateArgoCDStatus(cr)
+	}
+	return nil
+}
+
+// reconcileStatusImages will ensure that the Images Status is updated with the container images resolved for the
+// given ArgoCD.
+func (r *ReconcileArgoCD) reconcileStatusImages(cr *argoprojv1a1.ArgoCD) error {
+	images := argoprojv1a1.ArgoCDImagesStatus{
+		ArgoCD: getArgoContainerImage(cr),
+	}
+
+	if cr.Spec.ApplicationSet != nil {
+		images.ApplicationSet = getApplicationSetContainerImage(cr)
+	}
+
+	if isManagedDexEnabled(cr) {
+		images.Dex = getDexContainerImage(cr)
+	}
+
+	if isManagedGrafanaEnabled(cr) {
+		images.Grafana = getGrafanaContainerImage(cr)
+	}
+
+	if cr.Spec.ImageUpdater.Enabled {
+		images.ImageUpdater = getImageUpdaterContainerImage(cr)
+	}
+
+	if cr.Spec.SSO != nil && cr.Spec.SSO.Provider == argoprojv1a1.SSOProviderTypeKeycloak {
+		images.Keycloak = getKeycloakImage(cr)
+	}
+
+	if isManagedRedisEnabled(cr) && cr.Spec.HA.Enabled {
+		images.RedisHA = getRedisHAContainerImage(cr)
+		images.RedisHAProxy = getRedisHAProxyContainerImage(cr)
+	} else if isManagedRedisEnabled(cr) {
+		images.Redis = getRedisContainerImage(cr)
+	}
+
+	if cr.Status.Images != images {
+		cr.Status.Images = images
+		return r.updateArgoCDStatus(cr)
+	}
+	return nil
+}
+
+// reconcileStatusManagedNamespaces will ensure that the ManagedNamespaces Status is updated with the namespaces
+// managed by the given ArgoCD.
+func (r *ReconcileArgoCD) reconcileStatusManagedNamespaces(cr *argoprojv1a1.ArgoCD) error {
+	namespaces, _, err := r.getManagedNamespaces(cr)
+	if err != nil {
+		return err
+	}
+
+	var names []string
+	for _, ns := range namespaces {
+		names = append(names, ns.Name)
+	}
+	sort.Strings(names)
+
+	if !reflect.DeepEqual(cr.Status.ManagedNamespaces, names) {
+		cr.Status.ManagedNamespaces = names
+		return r.updateArgoCDStatus(cr)
 	}
 	return nil
 }
@@ -97,7 +453,9 @@ func (r *ReconcileArgoCD) reconcileStatusDex(cr *argoprojv1a1.ArgoCD) error {
 func (r *ReconcileArgoCD) reconcileStatusPhase(cr *argoprojv1a1.ArgoCD) error {
 	phase := "Unknown"
 
-	if cr.Status.ApplicationController == "Running" && cr.Status.Redis == "Running" && cr.Status.Repo == "Running" && cr.Status.Server == "Running" {
+	if isArgoCDConditionTrue(cr, argoprojv1a1.ArgoCDConditionTypeDegraded) {
+		phase = "Failed"
+	} else if cr.Status.ApplicationController == "Running" && (cr.Status.Redis == "Running" || cr.Status.Redis == "Remote") && cr.Status.Repo == "Running" && cr.Status.Server == "Running" {
 		phase = "Available"
 	} else {
 		phase = "Pending"
@@ -105,7 +463,7 @@ func (r *ReconcileArgoCD) reconcileStatusPhase(cr *argoprojv1a1.ArgoCD) error {
 
 	if cr.Status.Phase != phase {
 		cr.Status.Phase = phase
-		return r.client.Status().Update(context.TODO(), cr)
+		return r.updateArgoCDStatus(cr)
 	}
 	return nil
 }
@@ -114,7 +472,9 @@ func (r *ReconcileArgoCD) reconcileStatusPhase(cr *argoprojv1a1.ArgoCD) error {
 func (r *ReconcileArgoCD) reconcileStatusRedis(cr *argoprojv1a1.ArgoCD) error {
 	status := "Unknown"
 
-	if !cr.Spec.HA.Enabled {
+	if !isManagedRedisEnabled(cr) {
+		status = "Remote"
+	} else if !cr.Spec.HA.Enabled {
 		deploy := newDeploymentWithSuffix("redis", "redis", cr)
 		if argoutil.IsObjectFound(r.client, cr.Namespace, deploy.Name, deploy) {
 			status = "Pending"
@@ -139,7 +499,7 @@ func (r *ReconcileArgoCD) reconcileStatusRedis(cr *argoprojv1a1.ArgoCD) error {
 
 	if cr.Status.Redis != status {
 		cr.Status.Redis = status
-		return r.client.Status().Update(context.TODO(), cr)
+		return r.updateArgoCDStatus(cr)
 	}
 	return nil
 }
@@ -161,7 +521,7 @@ func (r *ReconcileArgoCD) reconcileStatusRepo(cr *argoprojv1a1.ArgoCD) error {
 
 	if cr.Status.Repo != status {
 		cr.Status.Repo = status
-		return r.client.Status().Update(context.TODO(), cr)
+		return r.updateArgoCDStatus(cr)
 	}
 	return nil
 }
@@ -184,7 +544,19 @@ func (r *ReconcileArgoCD) reconcileStatusServer(cr *argoprojv1a1.ArgoCD) error {
 
 	if cr.Status.Server != status {
 		cr.Status.Server = status
-		return r.client.Status().Update(context.TODO(), cr)
+		return r.updateArgoCDStatus(cr)
+	}
+	return nil
+}
+
+// reconcileStatusVersion will ensure that the Version Status is updated with the tag or digest of the Argo CD container
+// image for the given ArgoCD.
+func (r *ReconcileArgoCD) reconcileStatusVersion(cr *argoprojv1a1.ArgoCD) error {
+	version := argoutil.GetImageTag(getArgoContainerImage(cr))
+
+	if cr.Status.Version != version {
+		cr.Status.Version = version
+		return r.updateArgoCDStatus(cr)
 	}
 	return nil
 }
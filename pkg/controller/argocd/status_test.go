@@ -0,0 +1,277 @@
+package argocd
+
+import (
+	"context"
+	"testing"
+
+	"gotest.tools/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+
+	argoprojv1alpha1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
+)
+
+func TestReconcileArgoCD_reconcileStatusConditions_progressDeadlineExceeded(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD()
+	d := newDeploymentWithSuffix("repo-server", "repo-server", a)
+	d.Status.Conditions = []appsv1.DeploymentCondition{
+		{
+			Type:    appsv1.DeploymentProgressing,
+			Status:  corev1.ConditionFalse,
+			Reason:  "ProgressDeadlineExceeded",
+			Message: `ReplicaSet "argocd-repo-server-1234" has timed out progressing.`,
+		},
+	}
+	r := makeTestReconciler(t, a, d)
+
+	assert.NilError(t, r.reconcileStatusConditions(a))
+	assert.NilError(t, r.reconcileStatusPhase(a))
+
+	assert.Equal(t, len(a.Status.Conditions), 1)
+	c := a.Status.Conditions[0]
+	assert.Equal(t, c.Type, argoprojv1alpha1.ArgoCDConditionTypeDegraded)
+	assert.Equal(t, c.Status, corev1.ConditionTrue)
+	assert.Equal(t, c.Reason, "RolloutFailed")
+	assert.Equal(t, c.Message, `argocd-repo-server: ReplicaSet "argocd-repo-server-1234" has timed out progressing.`)
+	assert.Equal(t, a.Status.Phase, "Failed")
+}
+
+func TestReconcileArgoCD_reconcileStatusConditions_crashLoopBackOff(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD()
+	r := makeTestReconciler(t, a)
+	assert.NilError(t, r.reconcileServerDeployment(a))
+
+	assert.NilError(t, r.reconcileStatusConditions(a))
+	assert.Equal(t, a.Status.Conditions[0].Status, corev1.ConditionFalse)
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "argocd-server-abc",
+			Namespace: testNamespace,
+			Labels:    map[string]string{"app.kubernetes.io/name": "argocd-server"},
+		},
+		Status: corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{
+				{
+					Name: "argocd-server",
+					State: corev1.ContainerState{
+						Waiting: &corev1.ContainerStateWaiting{
+							Reason:  "CrashLoopBackOff",
+							Message: "back-off 5m0s restarting failed container",
+						},
+					},
+				},
+			},
+		},
+	}
+	assert.NilError(t, r.client.Create(context.TODO(), pod))
+
+	assert.NilError(t, r.reconcileStatusConditions(a))
+	c := a.Status.Conditions[0]
+	assert.Equal(t, c.Status, corev1.ConditionTrue)
+	assert.Equal(t, c.Message, "argocd-server: container argocd-server in pod argocd-server-abc is in CrashLoopBackOff: back-off 5m0s restarting failed container")
+
+	assert.NilError(t, r.client.Delete(context.TODO(), pod))
+	assert.NilError(t, r.reconcileStatusConditions(a))
+	assert.Equal(t, a.Status.Conditions[0].Status, corev1.ConditionFalse)
+	assert.Equal(t, a.Status.Conditions[0].Reason, "RolloutSucceeded")
+}
+
+func TestReconcileArgoCD_reconcileStatusCommandOverrides(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD()
+	r := makeTestReconciler(t, a)
+
+	assert.NilError(t, r.reconcileStatusCommandOverrides(a))
+	assert.Equal(t, len(a.Status.Conditions), 1)
+	assert.Equal(t, a.Status.Conditions[0].Type, argoprojv1alpha1.ArgoCDConditionTypeCommandOverridden)
+	assert.Equal(t, a.Status.Conditions[0].Status, corev1.ConditionFalse)
+
+	a.Spec.Server.CommandOverride.Command = []string{"/apm/wrapper", "argocd-server"}
+	a.Spec.Repo.CommandOverride.Args = []string{"--loglevel", "debug"}
+	assert.NilError(t, r.reconcileStatusCommandOverrides(a))
+	c := a.Status.Conditions[0]
+	assert.Equal(t, c.Status, corev1.ConditionTrue)
+	assert.Equal(t, c.Reason, "CommandOverridden")
+	assert.Equal(t, c.Message, "the operator no longer manages the flags of the overridden containers: repo-server, server")
+}
+
+func TestReconcileArgoCD_reconcileStatusImages(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	setTestEnv(t, common.ArgoCDRelatedImageRedisHAEnvName, "testing/redis@"+testImageDigest)
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.Image = "testing/argocd"
+		a.Spec.Version = "latest"
+		a.Spec.HA.Enabled = true
+	})
+	r := makeTestReconciler(t, a)
+
+	assert.NilError(t, r.reconcileStatusImages(a))
+
+	assert.Equal(t, a.Status.Images.ArgoCD, argoTestImage)
+	assert.Equal(t, a.Status.Images.RedisHA, "testing/redis@"+testImageDigest)
+	assert.Equal(t, a.Status.Images.Redis, "")
+	assert.Equal(t, a.Status.Images.Grafana, "")
+}
+
+func TestReconcileArgoCD_reconcileStatusHost(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD()
+	r := makeTestReconciler(t, a)
+
+	assert.NilError(t, r.reconcileStatusHost(a))
+	assert.Equal(t, a.Status.Host, "argocd-server")
+
+	a.Spec.Server.Host = "argocd.example.com"
+	assert.NilError(t, r.reconcileStatusHost(a))
+	assert.Equal(t, a.Status.Host, "argocd.example.com")
+}
+
+func TestReconcileArgoCD_reconcileStatusVersion(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	restoreEnv(t)
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.Image = "testing/argocd"
+		a.Spec.Version = "v1.8.1"
+	})
+	r := makeTestReconciler(t, a)
+
+	assert.NilError(t, r.reconcileStatusVersion(a))
+	assert.Equal(t, a.Status.Version, "v1.8.1")
+
+	a.Spec.Version = testImageDigest
+	assert.NilError(t, r.reconcileStatusVersion(a))
+	assert.Equal(t, a.Status.Version, testImageDigest)
+}
+
+func TestReconcileArgoCD_reconcileStatusApplications_counts(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD()
+	r := makeTestReconciler(t, a)
+
+	assert.NilError(t, r.reconcileStatusApplications(a))
+	assert.Equal(t, a.Status.ApplicationCount, int32(0))
+	assert.Equal(t, a.Status.AppProjectCount, int32(0))
+
+	for _, obj := range []struct {
+		gvk             schema.GroupVersionKind
+		namespace, name string
+	}{
+		{applicationGVK, a.Namespace, "guestbook"},
+		{applicationGVK, a.Namespace, "helm-guestbook"},
+		{applicationGVK, "other", "guestbook"},
+		{appProjectGVK, a.Namespace, "default"},
+	} {
+		u := &unstructured.Unstructured{}
+		u.SetGroupVersionKind(obj.gvk)
+		u.SetNamespace(obj.namespace)
+		u.SetName(obj.name)
+		assert.NilError(t, r.client.Create(context.TODO(), u))
+	}
+
+	assert.NilError(t, r.reconcileStatusApplications(a))
+	assert.Equal(t, a.Status.ApplicationCount, int32(2))
+	assert.Equal(t, a.Status.AppProjectCount, int32(1))
+}
+
+func TestReconcileArgoCD_reconcileStatusManagedNamespaces(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD()
+	r := makeTestReconciler(t, a)
+	assert.NilError(t, createNamespace(r, a.Namespace, a.Namespace))
+	assert.NilError(t, createNamespace(r, "team-b", a.Namespace))
+	assert.NilError(t, createNamespace(r, "team-a", a.Namespace))
+	assert.NilError(t, createNamespace(r, "other", "other-argocd"))
+
+	assert.NilError(t, r.reconcileStatusManagedNamespaces(a))
+	assert.DeepEqual(t, a.Status.ManagedNamespaces, []string{a.Namespace, "team-a", "team-b"})
+}
+
+func TestReconcileArgoCD_reconcileStatusConditions_statefulSetCrashLoopBackOff(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD()
+	r := makeTestReconciler(t, a)
+	assert.NilError(t, r.reconcileApplicationControllerStatefulSet(a))
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "argocd-application-controller-0",
+			Namespace: testNamespace,
+			Labels:    map[string]string{"app.kubernetes.io/name": "argocd-application-controller"},
+		},
+		Status: corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{
+				{
+					Name: "argocd-application-controller",
+					State: corev1.ContainerState{
+						Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff", Message: "back-off 5m0s"},
+					},
+				},
+			},
+		},
+	}
+	assert.NilError(t, r.client.Create(context.TODO(), pod))
+
+	assert.NilError(t, r.reconcileStatusConditions(a))
+	c := a.Status.Conditions[0]
+	assert.Equal(t, c.Status, corev1.ConditionTrue)
+	assert.Equal(t, c.Message, "argocd-application-controller: container argocd-application-controller in pod argocd-application-controller-0 is in CrashLoopBackOff: back-off 5m0s")
+}
+
+func TestReconcileArgoCD_reconcileStatusConditions_serverTLSSecret(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.Server.TLS.SecretName = "custom-tls"
+	})
+	r := makeTestReconciler(t, a)
+
+	assert.NilError(t, r.reconcileStatusConditions(a))
+	c := getArgoCDCondition(a, argoprojv1alpha1.ArgoCDConditionTypeServerTLSSecretValid)
+	assert.Assert(t, c != nil)
+	assert.Equal(t, c.Status, corev1.ConditionFalse)
+	assert.Equal(t, c.Reason, "SecretNotFound")
+
+	events := &corev1.EventList{}
+	assert.NilError(t, r.client.List(context.TODO(), events))
+	assert.Equal(t, len(events.Items), 1)
+	assert.Equal(t, events.Items[0].Reason, "SecretNotFound")
+
+	// The event is only emitted when the condition changes.
+	assert.NilError(t, r.reconcileStatusConditions(a))
+	assert.NilError(t, r.client.List(context.TODO(), events))
+	assert.Equal(t, len(events.Items), 1)
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "custom-tls", Namespace: testNamespace},
+		Data:       map[string][]byte{corev1.TLSCertKey: []byte("cert")},
+	}
+	assert.NilError(t, r.client.Create(context.TODO(), secret))
+	assert.NilError(t, r.reconcileStatusConditions(a))
+	c = getArgoCDCondition(a, argoprojv1alpha1.ArgoCDConditionTypeServerTLSSecretValid)
+	assert.Equal(t, c.Reason, "SecretInvalid")
+
+	secret.Data[corev1.TLSPrivateKeyKey] = []byte("key")
+	assert.NilError(t, r.client.Update(context.TODO(), secret))
+	assert.NilError(t, r.reconcileStatusConditions(a))
+	assert.Assert(t, isArgoCDConditionTrue(a, argoprojv1alpha1.ArgoCDConditionTypeServerTLSSecretValid))
+
+	a.Spec.Server.TLS.SecretName = ""
+	assert.NilError(t, r.reconcileStatusConditions(a))
+	assert.Assert(t, getArgoCDCondition(a, argoprojv1alpha1.ArgoCDConditionTypeServerTLSSecretValid) == nil)
+}
+
+func getArgoCDCondition(cr *argoprojv1alpha1.ArgoCD, conditionType string) *argoprojv1alpha1.ArgoCDCondition {
+	for i := range cr.Status.Conditions {
+		if cr.Status.Conditions[i].Type == conditionType {
+			return &cr.Status.Conditions[i]
+		}
+	}
+	return nil
+}
@@ -28,7 +28,9 @@ import (
 	v1 "k8s.io/api/rbac/v1"
 	resourcev1 "k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/kubernetes/scheme"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
@@ -44,10 +46,18 @@ const (
 func makeTestReconciler(t *testing.T, objs ...runtime.Object) *ReconcileArgoCD {
 	s := scheme.Scheme
 	assert.NilError(t, apis.AddToScheme(s))
+	// The Argo CD Application and AppProject resources and the PodMetrics of the metrics-server are only read as
+	// unstructured objects.
+	for _, gvk := range []schema.GroupVersionKind{applicationGVK, appProjectGVK, podMetricsGVK} {
+		s.AddKnownTypeWithName(gvk, &unstructured.Unstructured{})
+		s.AddKnownTypeWithName(gvk.GroupVersion().WithKind(gvk.Kind+"List"), &unstructured.UnstructuredList{})
+	}
 
 	cl := fake.NewFakeClientWithScheme(s, objs...)
 	return &ReconcileArgoCD{
 		client: cl,
+		reader: cl,
+		cache:  cl,
 		scheme: s,
 	}
 }
@@ -0,0 +1,42 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocd
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+
+	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+)
+
+// tracer records the reconciliation of ArgoCD instances. Spans are dropped unless the operator has been configured
+// with an OTLP endpoint to export them to.
+var tracer = otel.Tracer("github.com/argoproj-labs/argocd-operator/pkg/controller/argocd")
+
+// traceReconcile will run the given reconciler for the given ArgoCD in a span named after the reconciler, recording
+// the error returned by the reconciler on the span.
+func traceReconcile(ctx context.Context, name string, reconcile func(*argoprojv1a1.ArgoCD) error, cr *argoprojv1a1.ArgoCD) error {
+	_, span := tracer.Start(ctx, name)
+	defer span.End()
+
+	if err := reconcile(cr); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocd
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"gotest.tools/assert"
+
+	argoprojv1alpha1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+)
+
+func TestTraceReconcile(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+	a := makeTestArgoCD()
+
+	assert.NilError(t, traceReconcile(context.TODO(), "reconcileSucceeds", func(*argoprojv1alpha1.ArgoCD) error {
+		return nil
+	}, a))
+	err := traceReconcile(context.TODO(), "reconcileFails", func(*argoprojv1alpha1.ArgoCD) error {
+		return errors.New("reconcile failed")
+	}, a)
+	assert.Error(t, err, "reconcile failed")
+
+	spans := exporter.GetSpans()
+	assert.Equal(t, len(spans), 2)
+	assert.Equal(t, spans[0].Name, "reconcileSucceeds")
+	assert.Equal(t, spans[0].StatusCode, codes.Unset)
+	assert.Equal(t, spans[1].Name, "reconcileFails")
+	assert.Equal(t, spans[1].StatusCode, codes.Error)
+	assert.Equal(t, spans[1].StatusMessage, "reconcile failed")
+}
@@ -0,0 +1,86 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocd
+
+import (
+	"fmt"
+
+	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
+	"github.com/argoproj-labs/argocd-operator/pkg/controller/argoutil"
+)
+
+// upgradeStages is the order in which the Argo CD components are upgraded when using the Staged upgrade strategy.
+var upgradeStages = []string{"repo-server", "application-controller", "server"}
+
+// getArgoComponentImage will return the image and readiness of the given Argo CD component, along with whether
+// the component was found.
+func (r *ReconcileArgoCD) getArgoComponentImage(cr *argoprojv1a1.ArgoCD, component string) (string, bool, bool) {
+	if component == "application-controller" {
+		ss := newStatefulSetWithSuffix(component, component, cr)
+		if !argoutil.IsObjectFound(r.client, cr.Namespace, ss.Name, ss) || len(ss.Spec.Template.Spec.Containers) == 0 {
+			return "", false, false
+		}
+		replicas := int32(1)
+		if ss.Spec.Replicas != nil {
+			replicas = *ss.Spec.Replicas
+		}
+		ready := ss.Status.ObservedGeneration >= ss.Generation &&
+			ss.Status.UpdatedReplicas == replicas &&
+			ss.Status.ReadyReplicas == replicas
+		return ss.Spec.Template.Spec.Containers[0].Image, ready, true
+	}
+
+	deploy := newDeploymentWithSuffix(component, component, cr)
+	if !argoutil.IsObjectFound(r.client, cr.Namespace, deploy.Name, deploy) || len(deploy.Spec.Template.Spec.Containers) == 0 {
+		return "", false, false
+	}
+	replicas := int32(1)
+	if deploy.Spec.Replicas != nil {
+		replicas = *deploy.Spec.Replicas
+	}
+	ready := deploy.Status.ObservedGeneration >= deploy.Generation &&
+		deploy.Status.UpdatedReplicas == replicas &&
+		deploy.Status.ReadyReplicas == replicas
+	return deploy.Spec.Template.Spec.Containers[0].Image, ready, true
+}
+
+// getArgoContainerImageForComponent will return the container image to use for the given Argo CD component.
+// When the Staged upgrade strategy is used, a component keeps its current image until the component before it
+// in the upgrade order is running the desired image and is ready.
+func (r *ReconcileArgoCD) getArgoContainerImageForComponent(cr *argoprojv1a1.ArgoCD, component string) string {
+	desired := getArgoContainerImage(cr)
+	if cr.Spec.UpgradeStrategy.Type != common.ArgoCDUpgradeStrategyStaged {
+		return desired
+	}
+
+	current, _, found := r.getArgoComponentImage(cr, component)
+	if !found || current == desired {
+		return desired // New component or already upgraded, nothing to wait for...
+	}
+
+	for i, stage := range upgradeStages {
+		if stage != component || i == 0 {
+			continue
+		}
+		previous := upgradeStages[i-1]
+		image, ready, found := r.getArgoComponentImage(cr, previous)
+		if found && (image != desired || !ready) {
+			log.Info(fmt.Sprintf("waiting for %s to be upgraded before upgrading %s", previous, component))
+			return current
+		}
+	}
+	return desired
+}
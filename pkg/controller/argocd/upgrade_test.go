@@ -0,0 +1,91 @@
+package argocd
+
+import (
+	"context"
+	"testing"
+
+	"gotest.tools/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/types"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+
+	argoprojv1alpha1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
+)
+
+func getTestDeploymentImage(t *testing.T, r *ReconcileArgoCD, name string) string {
+	t.Helper()
+	deploy := &appsv1.Deployment{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: testNamespace}, deploy))
+	return deploy.Spec.Template.Spec.Containers[0].Image
+}
+
+func getTestStatefulSetImage(t *testing.T, r *ReconcileArgoCD, name string) string {
+	t.Helper()
+	ss := &appsv1.StatefulSet{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: testNamespace}, ss))
+	return ss.Spec.Template.Spec.Containers[0].Image
+}
+
+func TestReconcileArgoCD_stagedUpgrade(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.Image = "argoproj/argocd"
+		a.Spec.Version = "v1.0.0"
+		a.Spec.UpgradeStrategy.Type = common.ArgoCDUpgradeStrategyStaged
+	})
+	r := makeTestReconciler(t, a)
+
+	reconcileAll := func() {
+		assert.NilError(t, r.reconcileServerDeployment(a))
+		assert.NilError(t, r.reconcileApplicationControllerStatefulSet(a))
+		assert.NilError(t, r.reconcileRepoDeployment(a))
+	}
+	reconcileAll()
+
+	a.Spec.Version = "v2.0.0"
+	reconcileAll()
+
+	// Only the repo server is upgraded until it becomes ready.
+	assert.Equal(t, getTestDeploymentImage(t, r, "argocd-repo-server"), "argoproj/argocd:v2.0.0")
+	assert.Equal(t, getTestStatefulSetImage(t, r, "argocd-application-controller"), "argoproj/argocd:v1.0.0")
+	assert.Equal(t, getTestDeploymentImage(t, r, "argocd-server"), "argoproj/argocd:v1.0.0")
+
+	repo := &appsv1.Deployment{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-repo-server", Namespace: testNamespace}, repo))
+	repo.Status.UpdatedReplicas = 1
+	repo.Status.ReadyReplicas = 1
+	assert.NilError(t, r.client.Status().Update(context.TODO(), repo))
+	reconcileAll()
+
+	// The application controller is upgraded next, the server waits for it to become ready.
+	assert.Equal(t, getTestStatefulSetImage(t, r, "argocd-application-controller"), "argoproj/argocd:v2.0.0")
+	assert.Equal(t, getTestDeploymentImage(t, r, "argocd-server"), "argoproj/argocd:v1.0.0")
+
+	ss := &appsv1.StatefulSet{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-application-controller", Namespace: testNamespace}, ss))
+	ss.Status.UpdatedReplicas = 1
+	ss.Status.ReadyReplicas = 1
+	assert.NilError(t, r.client.Status().Update(context.TODO(), ss))
+	reconcileAll()
+
+	assert.Equal(t, getTestDeploymentImage(t, r, "argocd-server"), "argoproj/argocd:v2.0.0")
+}
+
+func TestReconcileArgoCD_defaultUpgradeStrategy(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.Image = "argoproj/argocd"
+		a.Spec.Version = "v1.0.0"
+	})
+	r := makeTestReconciler(t, a)
+	assert.NilError(t, r.reconcileServerDeployment(a))
+	assert.NilError(t, r.reconcileApplicationControllerStatefulSet(a))
+
+	a.Spec.Version = "v2.0.0"
+	assert.NilError(t, r.reconcileServerDeployment(a))
+	assert.NilError(t, r.reconcileApplicationControllerStatefulSet(a))
+
+	assert.Equal(t, getTestDeploymentImage(t, r, "argocd-server"), "argoproj/argocd:v2.0.0")
+	assert.Equal(t, getTestStatefulSetImage(t, r, "argocd-application-controller"), "argoproj/argocd:v2.0.0")
+}
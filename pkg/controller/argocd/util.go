@@ -17,14 +17,19 @@ package argocd
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"k8s.io/apimachinery/pkg/types"
 	"os"
+	"path"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 	"text/template"
+	"time"
 
 	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
 	"github.com/argoproj-labs/argocd-operator/pkg/common"
@@ -40,10 +45,14 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	extv1beta1 "k8s.io/api/extensions/v1beta1"
 	v1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/event"
@@ -82,9 +91,25 @@ func generateArgoServerSessionKey() ([]byte, error) {
 	return []byte(pass), err
 }
 
+// generateOAuthClientSecret will generate and return a client secret for an OAuth client.
+func generateOAuthClientSecret() ([]byte, error) {
+	pass, err := password.Generate(
+		common.ArgoCDDefaultOAuthClientSecretLength,
+		common.ArgoCDDefaultOAuthClientSecretNumDigits,
+		common.ArgoCDDefaultOAuthClientSecretNumSymbols,
+		false, false)
+
+	return []byte(pass), err
+}
+
 // getArgoApplicationControllerResources will return the ResourceRequirements for the Argo CD application controller container.
 func getArgoApplicationControllerResources(cr *argoprojv1a1.ArgoCD) corev1.ResourceRequirements {
-	resources := corev1.ResourceRequirements{}
+	resources := getDefaultResourcesFromEnv(common.ArgoCDDefaultControllerResourcesEnvName)
+
+	// Allow override of the default resource requirements from the sizing profile
+	if profile := getSizingProfile(cr); profile != nil {
+		resources = *profile.controllerResources.DeepCopy()
+	}
 
 	// Allow override of resource requirements from CR
 	if cr.Spec.Controller.Resources != nil {
@@ -94,6 +119,55 @@ func getArgoApplicationControllerResources(cr *argoprojv1a1.ArgoCD) corev1.Resou
 	return resources
 }
 
+// getArgoApplicationControllerEnv will return the environment variables for the Argo CD application controller container.
+func getArgoApplicationControllerEnv(cr *argoprojv1a1.ArgoCD) []corev1.EnvVar {
+	env := make([]corev1.EnvVar, 0)
+
+	cache := cr.Spec.Controller.ClusterCache
+	if cache.ListPageSize > 0 {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ArgoCDControllerClusterCacheListPageSizeEnvName,
+			Value: fmt.Sprint(cache.ListPageSize),
+		})
+	}
+	if cache.ListSemaphore > 0 {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ArgoCDControllerClusterCacheListSemaphoreEnvName,
+			Value: fmt.Sprint(cache.ListSemaphore),
+		})
+	}
+	if cache.ResyncDuration != nil {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ArgoCDControllerClusterCacheResyncDurationEnvName,
+			Value: cache.ResyncDuration.Duration.String(),
+		})
+	}
+	if cache.WatchResyncDuration != nil {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ArgoCDControllerClusterCacheWatchResyncDurationEnvName,
+			Value: cache.WatchResyncDuration.Duration.String(),
+		})
+	}
+
+	if a := cr.Spec.Controller.Sharding.Algorithm; a != "" && isArgoCDVersionAtLeast(cr, getShardingAlgorithmMinimumVersion(a)) {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ArgoCDControllerShardingAlgorithmEnvName,
+			Value: cr.Spec.Controller.Sharding.Algorithm,
+		})
+	}
+	return proxyEnvVars(cr, env...)
+}
+
+// syncTimeoutMinimumVersion is the first version of Argo CD whose Application Controller can terminate sync operations
+// after a timeout.
+const syncTimeoutMinimumVersion = "v2.10.0"
+
+// getDurationSeconds will return the given duration as a number of seconds for a command line flag. A fraction of a
+// second is rounded up, so that a short duration is not turned into 0, which often disables the option.
+func getDurationSeconds(d time.Duration) string {
+	return strconv.FormatInt(int64((d+time.Second-1)/time.Second), 10)
+}
+
 // getArgoApplicationControllerCommand will return the command for the ArgoCD Application Controller component.
 func getArgoApplicationControllerCommand(cr *argoprojv1a1.ArgoCD) []string {
 	cmd := []string{
@@ -106,15 +180,131 @@ func getArgoApplicationControllerCommand(cr *argoprojv1a1.ArgoCD) []string {
 	if cr.Spec.Controller.AppSync != nil {
 		cmd = append(cmd, "--app-resync", strconv.FormatInt(int64(cr.Spec.Controller.AppSync.Seconds()), 10))
 	}
+	if cr.Spec.Controller.SelfHealTimeout != nil {
+		cmd = append(cmd, "--self-heal-timeout-seconds", getDurationSeconds(cr.Spec.Controller.SelfHealTimeout.Duration))
+	}
+	if cr.Spec.Controller.SyncTimeout != nil && isArgoCDVersionAtLeast(cr, syncTimeoutMinimumVersion) {
+		cmd = append(cmd, "--sync-timeout", getDurationSeconds(cr.Spec.Controller.SyncTimeout.Duration))
+	}
+	if port := getArgoApplicationControllerListenPort(cr); port != common.ArgoCDDefaultApplicationControllerMetricsPort {
+		cmd = append(cmd, "--metrics-port", fmt.Sprint(port))
+	}
+	if level := getDefaultLogLevel(); level != "" {
+		cmd = append(cmd, "--loglevel", level)
+	}
 	return cmd
 }
 
+// getArgoApplicationControllerMetricsPort will return the port of the metrics endpoint of the Application Controller.
+func getArgoApplicationControllerMetricsPort(cr *argoprojv1a1.ArgoCD) int32 {
+	if port := cr.Spec.Controller.Metrics.Port; port > 0 {
+		return port
+	}
+	if isArgoApplicationControllerMetricsTLSEnabled(cr) {
+		return common.ArgoCDDefaultApplicationControllerMetricsTLSPort
+	}
+	return common.ArgoCDDefaultApplicationControllerMetricsPort
+}
+
+// getArgoApplicationControllerListenPort will return the port that the Application Controller listens on for metrics
+// and health checks. When TLS is enabled for the metrics the default port is used, as the metrics endpoint is served
+// by a TLS proxy.
+func getArgoApplicationControllerListenPort(cr *argoprojv1a1.ArgoCD) int32 {
+	if isArgoApplicationControllerMetricsTLSEnabled(cr) {
+		return common.ArgoCDDefaultApplicationControllerMetricsPort
+	}
+	return getArgoApplicationControllerMetricsPort(cr)
+}
+
+// isArgoApplicationControllerMetricsTLSEnabled will return true when TLS is enabled for the metrics endpoint of the
+// Application Controller.
+func isArgoApplicationControllerMetricsTLSEnabled(cr *argoprojv1a1.ArgoCD) bool {
+	return cr.Spec.Controller.Metrics.TLS != nil
+}
+
+// getMetricsTLSProxyContainerImage will return the container image for the proxy that serves metrics over TLS.
+func getMetricsTLSProxyContainerImage(cr *argoprojv1a1.ArgoCD) string {
+	if e := argoutil.GetDefaultImage(common.ArgoCDMetricsTLSProxyImageEnvName); e != "" {
+		return e
+	}
+	img := argoutil.ReplaceImageRegistry(common.ArgoCDDefaultMetricsTLSProxyImage, cr.Spec.ImageRegistry)
+	return argoutil.CombineImageTag(img, common.ArgoCDDefaultMetricsTLSProxyVersion)
+}
+
+// getDefaultInt32FromEnv will return the value of the given environment variable of the operator as an int32, or
+// the given default when it is not set or is not a positive number.
+func getDefaultInt32FromEnv(name string, def int32) int32 {
+	v := os.Getenv(name)
+	if v == "" {
+		return def
+	}
+	i, err := strconv.ParseInt(v, 10, 32)
+	if err != nil || i <= 0 {
+		log.Info(fmt.Sprintf("ignoring invalid value %q for environment variable %s", v, name))
+		return def
+	}
+	return int32(i)
+}
+
+// getDefaultResourcesFromEnv will return the ResourceRequirements held as JSON in the given environment variable of
+// the operator, or empty ResourceRequirements when it is not set or cannot be parsed.
+func getDefaultResourcesFromEnv(name string) corev1.ResourceRequirements {
+	resources := corev1.ResourceRequirements{}
+	v := os.Getenv(name)
+	if v == "" {
+		return resources
+	}
+	if err := json.Unmarshal([]byte(v), &resources); err != nil {
+		log.Error(err, fmt.Sprintf("ignoring invalid value for environment variable %s", name))
+		return corev1.ResourceRequirements{}
+	}
+	return resources
+}
+
+// getInitContainerResources will return the ResourceRequirements for an init container of a component, given the
+// requirements set in the CR and the requirements of the main container of the component. Unless set in the CR or the
+// environment of the operator, init containers only get small default requirements when the main container has any,
+// so that they do not reserve the full requirements of the main container.
+func getInitContainerResources(resources *corev1.ResourceRequirements, main corev1.ResourceRequirements) corev1.ResourceRequirements {
+	// Allow override of resource requirements from CR
+	if resources != nil {
+		return *resources
+	}
+
+	if defaults := getDefaultResourcesFromEnv(common.ArgoCDDefaultInitContainerResourcesEnvName); len(defaults.Limits) > 0 || len(defaults.Requests) > 0 {
+		return defaults
+	}
+
+	if len(main.Limits) == 0 && len(main.Requests) == 0 {
+		return corev1.ResourceRequirements{}
+	}
+	return newResourceRequirements(
+		common.ArgoCDDefaultInitContainerResourceRequestCPU,
+		common.ArgoCDDefaultInitContainerResourceRequestMemory,
+		common.ArgoCDDefaultInitContainerResourceLimitCPU,
+		common.ArgoCDDefaultInitContainerResourceLimitMemory)
+}
+
+// getDefaultLogLevel will return the log level for the Argo CD components from the environment of the operator, or
+// an empty string when it is not set or is not one of debug, info, warn or error.
+func getDefaultLogLevel() string {
+	v := strings.ToLower(os.Getenv(common.ArgoCDDefaultLogLevelEnvName))
+	switch v {
+	case "":
+		return ""
+	case "debug", "info", "warn", "error":
+		return v
+	}
+	log.Info(fmt.Sprintf("ignoring invalid value %q for environment variable %s", v, common.ArgoCDDefaultLogLevelEnvName))
+	return ""
+}
+
 // getArgoContainerImage will return the container image for ArgoCD.
 func getArgoContainerImage(cr *argoprojv1a1.ArgoCD) string {
 	defaultTag, defaultImg := false, false
 	img := cr.Spec.Image
 	if img == "" {
-		img = common.ArgoCDDefaultArgoImage
+		img = argoutil.ReplaceImageRegistry(common.ArgoCDDefaultArgoImage, cr.Spec.ImageRegistry)
 		defaultImg = true
 	}
 
@@ -123,16 +313,50 @@ func getArgoContainerImage(cr *argoprojv1a1.ArgoCD) string {
 		tag = common.ArgoCDDefaultArgoVersion
 		defaultTag = true
 	}
-	if e := os.Getenv(common.ArgoCDImageEnvName); e != "" && (defaultTag && defaultImg) {
+	if e := argoutil.GetDefaultImage(common.ArgoCDRelatedImageArgoCDEnvName, common.ArgoCDImageEnvName); e != "" && (defaultTag && defaultImg) {
 		return e
 	}
 
 	return argoutil.CombineImageTag(img, tag)
 }
 
+// isArgoCDVersionAtLeast will return true if the given ArgoCD runs the given minimum version of Argo CD or a later
+// one, or an image of an unknown version.
+func isArgoCDVersionAtLeast(cr *argoprojv1a1.ArgoCD, minimum string) bool {
+	return argoutil.IsArgoCDVersionAtLeast(getArgoContainerImage(cr), minimum)
+}
+
+// getShardingAlgorithmMinimumVersion will return the first version of Argo CD supporting the given sharding algorithm.
+func getShardingAlgorithmMinimumVersion(algorithm string) string {
+	if algorithm == "consistent-hashing" {
+		return "v2.12.0"
+	}
+	return "v2.8.0"
+}
+
+// getArgoRepoReplicas will return the replica count for the Argo CD Repo server Deployment.
+func getArgoRepoReplicas(cr *argoprojv1a1.ArgoCD) *int32 {
+	replicas := common.ArgoCDDefaultRepoServerReplicas
+	if cr.Spec.HA.Enabled {
+		replicas = common.ArgoCDDefaultRepoServerHAReplicas
+	}
+	if profile := getSizingProfile(cr); profile != nil && profile.repoReplicas > replicas {
+		replicas = profile.repoReplicas
+	}
+	if cr.Spec.Repo.Replicas != nil && *cr.Spec.Repo.Replicas >= 0 {
+		replicas = *cr.Spec.Repo.Replicas
+	}
+	return &replicas
+}
+
 // getArgoRepoResources will return the ResourceRequirements for the Argo CD Repo server container.
 func getArgoRepoResources(cr *argoprojv1a1.ArgoCD) corev1.ResourceRequirements {
-	resources := corev1.ResourceRequirements{}
+	resources := getDefaultResourcesFromEnv(common.ArgoCDDefaultRepoResourcesEnvName)
+
+	// Allow override of the default resource requirements from the sizing profile
+	if profile := getSizingProfile(cr); profile != nil {
+		resources = *profile.repoResources.DeepCopy()
+	}
 
 	// Allow override of resource requirements from CR
 	if cr.Spec.Repo.Resources != nil {
@@ -186,6 +410,16 @@ func getArgoServerResources(cr *argoprojv1a1.ArgoCD) corev1.ResourceRequirements
 		}
 	}
 
+	// Allow override of the default resource requirements from the environment of the operator
+	if env := getDefaultResourcesFromEnv(common.ArgoCDDefaultServerResourcesEnvName); env.Limits != nil || env.Requests != nil {
+		resources = env
+	}
+
+	// Allow override of the default resource requirements from the sizing profile
+	if profile := getSizingProfile(cr); profile != nil {
+		resources = *profile.serverResources.DeepCopy()
+	}
+
 	// Allow override of resource requirements from CR
 	if cr.Spec.Server.Resources != nil {
 		resources = *cr.Spec.Server.Resources
@@ -225,6 +459,21 @@ func (r *ReconcileArgoCD) getArgoServerURI(cr *argoprojv1a1.ArgoCD) string {
 
 // getArgoServerOperationProcessors will return the numeric Operation Processors value for the ArgoCD Server.
 func getArgoServerOperationProcessors(cr *argoprojv1a1.ArgoCD) int32 {
+	if isProcessorsAutosizeEnabled(cr) && cr.Status.ControllerProcessors != nil && cr.Status.ControllerProcessors.Operation > 0 {
+		return cr.Status.ControllerProcessors.Operation
+	}
+	return getConfiguredArgoServerOperationProcessors(cr)
+}
+
+// getConfiguredArgoServerOperationProcessors will return the numeric Operation Processors value configured for the
+// ArgoCD Server, which is the lower bound when the processors are sized by the operator.
+func getConfiguredArgoServerOperationProcessors(cr *argoprojv1a1.ArgoCD) int32 {
+	if cr.Spec.Controller.Processors.Operation == 0 {
+		if profile := getSizingProfile(cr); profile != nil {
+			return profile.operationProcessors
+		}
+		return getDefaultInt32FromEnv(common.ArgoCDDefaultControllerOperationProcessorsEnvName, common.ArgoCDDefaultServerOperationProcessors)
+	}
 	op := common.ArgoCDDefaultServerOperationProcessors
 	if cr.Spec.Controller.Processors.Operation > op {
 		op = cr.Spec.Controller.Processors.Operation
@@ -234,6 +483,21 @@ func getArgoServerOperationProcessors(cr *argoprojv1a1.ArgoCD) int32 {
 
 // getArgoServerStatusProcessors will return the numeric Status Processors value for the ArgoCD Server.
 func getArgoServerStatusProcessors(cr *argoprojv1a1.ArgoCD) int32 {
+	if isProcessorsAutosizeEnabled(cr) && cr.Status.ControllerProcessors != nil && cr.Status.ControllerProcessors.Status > 0 {
+		return cr.Status.ControllerProcessors.Status
+	}
+	return getConfiguredArgoServerStatusProcessors(cr)
+}
+
+// getConfiguredArgoServerStatusProcessors will return the numeric Status Processors value configured for the ArgoCD
+// Server, which is the lower bound when the processors are sized by the operator.
+func getConfiguredArgoServerStatusProcessors(cr *argoprojv1a1.ArgoCD) int32 {
+	if cr.Spec.Controller.Processors.Status == 0 {
+		if profile := getSizingProfile(cr); profile != nil {
+			return profile.statusProcessors
+		}
+		return getDefaultInt32FromEnv(common.ArgoCDDefaultControllerStatusProcessorsEnvName, common.ArgoCDDefaultServerStatusProcessors)
+	}
 	sp := common.ArgoCDDefaultServerStatusProcessors
 	if cr.Spec.Controller.Processors.Status > sp {
 		sp = cr.Spec.Controller.Processors.Status
@@ -248,24 +512,24 @@ func getArgoServerStatusProcessors(cr *argoprojv1a1.ArgoCD) int32 {
 //
 // 1. from the Spec, the spec.dex field has an image and version to use for
 // generating an image reference.
-// 2. from the Environment, this looks for the `ARGOCD_DEX_IMAGE` field and uses
-// that if the spec is not configured.
+// 2. from the Environment, this looks for the `RELATED_IMAGE_DEX` field, then the
+// `ARGOCD_DEX_IMAGE` field and uses that if the spec is not configured.
 // 3. the default is configured in common.ArgoCDDefaultDexVersion and
 // common.ArgoCDDefaultDexImage.
 func getDexContainerImage(cr *argoprojv1a1.ArgoCD) string {
 	defaultImg, defaultTag := false, false
-	img := cr.Spec.Dex.Image
+	img := getDexSpec(cr).Image
 	if img == "" {
-		img = common.ArgoCDDefaultDexImage
+		img = argoutil.ReplaceImageRegistry(common.ArgoCDDefaultDexImage, cr.Spec.ImageRegistry)
 		defaultImg = true
 	}
 
-	tag := cr.Spec.Dex.Version
+	tag := getDexSpec(cr).Version
 	if tag == "" {
 		tag = common.ArgoCDDefaultDexVersion
 		defaultTag = true
 	}
-	if e := os.Getenv(common.ArgoCDDexImageEnvName); e != "" && (defaultTag && defaultImg) {
+	if e := argoutil.GetDefaultImage(common.ArgoCDRelatedImageDexEnvName, common.ArgoCDDexImageEnvName); e != "" && (defaultTag && defaultImg) {
 		return e
 	}
 	return argoutil.CombineImageTag(img, tag)
@@ -273,22 +537,30 @@ func getDexContainerImage(cr *argoprojv1a1.ArgoCD) string {
 
 // getDexOAuthClientID will return the OAuth client ID for the given ArgoCD.
 func getDexOAuthClientID(cr *argoprojv1a1.ArgoCD) string {
-	return fmt.Sprintf("system:serviceaccount:%s:%s", cr.Namespace, fmt.Sprintf("%s-%s", cr.Name, common.ArgoCDDefaultDexServiceAccountName))
+	return fmt.Sprintf("system:serviceaccount:%s:%s", cr.Namespace, getServiceAccountName(dexServer, cr))
 }
 
 // getDexOAuthClientSecret will return the OAuth client secret for the given ArgoCD.
 func (r *ReconcileArgoCD) getDexOAuthClientSecret(cr *argoprojv1a1.ArgoCD) (*string, error) {
-	sa := newServiceAccountWithName(common.ArgoCDDefaultDexServiceAccountName, cr)
+	sa := newExternalServiceAccount(getServiceAccountName(dexServer, cr), cr)
 	if err := argoutil.FetchObject(r.client, cr.Namespace, sa.Name, sa); err != nil {
 		return nil, err
 	}
 
-	// Find the token secret
-	var tokenSecret *corev1.ObjectReference
+	// Find the most recent token secret, the token may have been rotated since the ServiceAccount was created.
+	var tokenSecret *corev1.Secret
 	for _, saSecret := range sa.Secrets {
-		if strings.Contains(saSecret.Name, "token") {
-			tokenSecret = &saSecret
-			break
+		if !strings.Contains(saSecret.Name, "token") {
+			continue
+		}
+
+		secret := argoutil.NewSecretWithName(cr.ObjectMeta, saSecret.Name)
+		if !argoutil.IsObjectFound(r.client, cr.Namespace, secret.Name, secret) {
+			continue // Token secret has been removed, look for a replacement.
+		}
+
+		if tokenSecret == nil || tokenSecret.CreationTimestamp.Before(&secret.CreationTimestamp) {
+			tokenSecret = secret
 		}
 	}
 
@@ -296,13 +568,7 @@ func (r *ReconcileArgoCD) getDexOAuthClientSecret(cr *argoprojv1a1.ArgoCD) (*str
 		return nil, errors.New("unable to locate ServiceAccount token for OAuth client secret")
 	}
 
-	// Fetch the secret to obtain the token
-	secret := argoutil.NewSecretWithName(cr.ObjectMeta, tokenSecret.Name)
-	if err := argoutil.FetchObject(r.client, cr.Namespace, secret.Name, secret); err != nil {
-		return nil, err
-	}
-
-	token := string(secret.Data["token"])
+	token := string(tokenSecret.Data["token"])
 	return &token, nil
 }
 
@@ -310,20 +576,120 @@ func (r *ReconcileArgoCD) getDexOAuthClientSecret(cr *argoprojv1a1.ArgoCD) (*str
 func getDexResources(cr *argoprojv1a1.ArgoCD) corev1.ResourceRequirements {
 	resources := corev1.ResourceRequirements{}
 
+	// Allow override of the default resource requirements from the sizing profile
+	if profile := getSizingProfile(cr); profile != nil {
+		resources = *profile.dexResources.DeepCopy()
+	}
+
 	// Allow override of resource requirements from CR
-	if cr.Spec.Dex.Resources != nil {
-		resources = *cr.Spec.Dex.Resources
+	if dex := getDexSpec(cr); dex.Resources != nil {
+		resources = *dex.Resources
 	}
 
 	return resources
 }
 
+// getDexProbe will return the default probe for the Dex container, which checks the health endpoint of Dex.
+func getDexProbe() *corev1.Probe {
+	return &corev1.Probe{
+		Handler: corev1.Handler{
+			HTTPGet: &corev1.HTTPGetAction{
+				Path:   common.ArgoCDDefaultDexHealthzPath,
+				Port:   intstr.FromInt(common.ArgoCDDefaultDexHTTPPort),
+				Scheme: corev1.URISchemeHTTP,
+			},
+		},
+		InitialDelaySeconds: 10,
+		TimeoutSeconds:      1,
+		PeriodSeconds:       10,
+		SuccessThreshold:    1,
+		FailureThreshold:    3,
+	}
+}
+
+// getDexLivenessProbe will return the liveness probe for the Dex container.
+func getDexLivenessProbe(cr *argoprojv1a1.ArgoCD) *corev1.Probe {
+	if probe := getDexSpec(cr).LivenessProbe; probe != nil {
+		return probe
+	}
+	return getDexProbe()
+}
+
+// getDexReadinessProbe will return the readiness probe for the Dex container.
+func getDexReadinessProbe(cr *argoprojv1a1.ArgoCD) *corev1.Probe {
+	if probe := getDexSpec(cr).ReadinessProbe; probe != nil {
+		return probe
+	}
+	return getDexProbe()
+}
+
+// getArgoServerStartupProbe will return the startup probe for the Argo CD server container, or nil when none is set.
+func getArgoServerStartupProbe(cr *argoprojv1a1.ArgoCD) *corev1.Probe {
+	return getHealthzStartupProbe(cr.Spec.Server.StartupProbe, 8080)
+}
+
+// getArgoApplicationControllerStartupProbe will return the startup probe for the Application Controller container,
+// or nil when none is set.
+func getArgoApplicationControllerStartupProbe(cr *argoprojv1a1.ArgoCD) *corev1.Probe {
+	return getHealthzStartupProbe(cr.Spec.Controller.StartupProbe, int(getArgoApplicationControllerListenPort(cr)))
+}
+
+// getHealthzStartupProbe will return a copy of the given startup probe. When the probe does not specify a handler,
+// the /healthz endpoint on the given port is checked, so that only the thresholds and timings need to be configured.
+func getHealthzStartupProbe(probe *corev1.Probe, port int) *corev1.Probe {
+	if probe == nil {
+		return nil
+	}
+	probe = probe.DeepCopy()
+	if probe.Exec == nil && probe.HTTPGet == nil && probe.TCPSocket == nil {
+		probe.HTTPGet = &corev1.HTTPGetAction{
+			Path: "/healthz",
+			Port: intstr.FromInt(port),
+		}
+	}
+	return probe
+}
+
+// probeChanged will return true when the given existing probe differs from the desired probe. The existing probe is
+// also compared with a copy of the desired probe holding the defaults set by the API server, so that they do not cause
+// an update on every reconcile.
+func probeChanged(existing, desired *corev1.Probe) bool {
+	if existing == nil || desired == nil {
+		return existing != desired
+	}
+	if reflect.DeepEqual(existing, desired) {
+		return false
+	}
+	defaulted := desired.DeepCopy()
+	if defaulted.TimeoutSeconds == 0 {
+		defaulted.TimeoutSeconds = 1
+	}
+	if defaulted.PeriodSeconds == 0 {
+		defaulted.PeriodSeconds = 10
+	}
+	if defaulted.SuccessThreshold == 0 {
+		defaulted.SuccessThreshold = 1
+	}
+	if defaulted.FailureThreshold == 0 {
+		defaulted.FailureThreshold = 3
+	}
+	if defaulted.HTTPGet != nil {
+		if defaulted.HTTPGet.Path == "" {
+			defaulted.HTTPGet.Path = "/"
+		}
+		if defaulted.HTTPGet.Scheme == "" {
+			defaulted.HTTPGet.Scheme = corev1.URISchemeHTTP
+		}
+	}
+	return !reflect.DeepEqual(existing, defaulted)
+}
+
 // getGrafanaContainerImage will return the container image for the Grafana server.
 func getGrafanaContainerImage(cr *argoprojv1a1.ArgoCD) string {
 	defaultTag, defaultImg := false, false
 	img := cr.Spec.Grafana.Image
 	if img == "" {
-		img = common.ArgoCDDefaultGrafanaImage
+		img = argoutil.ReplaceImageRegistry(common.ArgoCDDefaultGrafanaImage, cr.Spec.ImageRegistry)
 		defaultImg = true
 	}
 
@@ -332,7 +698,7 @@ func getGrafanaContainerImage(cr *argoprojv1a1.ArgoCD) string {
 		tag = common.ArgoCDDefaultGrafanaVersion
 		defaultTag = true
 	}
-	if e := os.Getenv(common.ArgoCDGrafanaImageEnvName); e != "" && (defaultTag && defaultImg) {
+	if e := argoutil.GetDefaultImage(common.ArgoCDRelatedImageGrafanaEnvName, common.ArgoCDGrafanaImageEnvName); e != "" && (defaultTag && defaultImg) {
 		return e
 	}
 	return argoutil.CombineImageTag(img, tag)
@@ -342,6 +708,11 @@ func getGrafanaContainerImage(cr *argoprojv1a1.ArgoCD) string {
 func getGrafanaResources(cr *argoprojv1a1.ArgoCD) corev1.ResourceRequirements {
 	resources := corev1.ResourceRequirements{}
 
+	// Allow override of the default resource requirements from the sizing profile
+	if profile := getSizingProfile(cr); profile != nil {
+		resources = *profile.grafanaResources.DeepCopy()
+	}
+
 	// Allow override of resource requirements from CR
 	if cr.Spec.Grafana.Resources != nil {
 		resources = *cr.Spec.Grafana.Resources
@@ -393,7 +764,17 @@ func getRedisConfigPath() string {
 // If an error occurs, an empty string value will be returned.
 func getRedisConf(cr *argoprojv1a1.ArgoCD) string {
 	path := fmt.Sprintf("%s/redis.conf.tpl", getRedisConfigPath())
-	conf, err := loadTemplateFile(path, map[string]string{})
+	config := cr.Spec.Redis.Config
+	vars := map[string]interface{}{
+		"MaxMemory":       common.ArgoCDDefaultRedisHAMaxMemory,
+		"MaxMemoryPolicy": getRedisMaxMemoryPolicy(cr),
+		"Save":            config.Save,
+	}
+	if config.MaxMemory != "" {
+		vars["MaxMemory"] = config.MaxMemory
+	}
+
+	conf, err := loadTemplateFile(path, vars)
 	if err != nil {
 		log.Error(err, "unable to load redis configuration")
 		return ""
@@ -401,12 +782,42 @@ func getRedisConf(cr *argoprojv1a1.ArgoCD) string {
 	return conf
 }
 
+// getRedisMaxMemoryPolicy will return the policy used by Redis to evict keys when its memory limit is reached for the
+// given ArgoCD.
+func getRedisMaxMemoryPolicy(cr *argoprojv1a1.ArgoCD) string {
+	if cr.Spec.Redis.Config.MaxMemoryPolicy != "" {
+		return cr.Spec.Redis.Config.MaxMemoryPolicy
+	}
+	return common.ArgoCDDefaultRedisMaxMemoryPolicy
+}
+
+// getRedisArgs will return the arguments of the Redis server in non-HA mode for the given ArgoCD. Snapshots are
+// disabled unless save points are configured, and the memory limit evicts keys like in HA mode.
+func getRedisArgs(cr *argoprojv1a1.ArgoCD) []string {
+	config := cr.Spec.Redis.Config
+	args := make([]string, 0)
+	if len(config.Save) == 0 {
+		args = append(args, "--save", "")
+	}
+	for _, save := range config.Save {
+		args = append(args, "--save", fmt.Sprint(save.Seconds), fmt.Sprint(save.Changes))
+	}
+	args = append(args, "--appendonly", "no")
+	if config.MaxMemory != "" {
+		args = append(args, "--maxmemory", config.MaxMemory)
+	}
+	if config.MaxMemory != "" || config.MaxMemoryPolicy != "" {
+		args = append(args, "--maxmemory-policy", getRedisMaxMemoryPolicy(cr))
+	}
+	return args
+}
+
 // getRedisContainerImage will return the container image for the Redis server.
 func getRedisContainerImage(cr *argoprojv1a1.ArgoCD) string {
 	defaultImg, defaultTag := false, false
 	img := cr.Spec.Redis.Image
 	if img == "" {
-		img = common.ArgoCDDefaultRedisImage
+		img = argoutil.ReplaceImageRegistry(common.ArgoCDDefaultRedisImage, cr.Spec.ImageRegistry)
 		defaultImg = true
 	}
 	tag := cr.Spec.Redis.Version
@@ -414,7 +825,7 @@ func getRedisContainerImage(cr *argoprojv1a1.ArgoCD) string {
 		tag = common.ArgoCDDefaultRedisVersion
 		defaultTag = true
 	}
-	if e := os.Getenv(common.ArgoCDRedisImageEnvName); e != "" && (defaultTag && defaultImg) {
+	if e := argoutil.GetDefaultImage(common.ArgoCDRelatedImageRedisEnvName, common.ArgoCDRedisImageEnvName); e != "" && (defaultTag && defaultImg) {
 		return e
 	}
 	return argoutil.CombineImageTag(img, tag)
@@ -425,7 +836,7 @@ func getRedisHAContainerImage(cr *argoprojv1a1.ArgoCD) string {
 	defaultImg, defaultTag := false, false
 	img := cr.Spec.Redis.Image
 	if img == "" {
-		img = common.ArgoCDDefaultRedisImage
+		img = argoutil.ReplaceImageRegistry(common.ArgoCDDefaultRedisImage, cr.Spec.ImageRegistry)
 		defaultImg = true
 	}
 	tag := cr.Spec.Redis.Version
@@ -433,7 +844,7 @@ func getRedisHAContainerImage(cr *argoprojv1a1.ArgoCD) string {
 		tag = common.ArgoCDDefaultRedisVersionHA
 		defaultTag = true
 	}
-	if e := os.Getenv(common.ArgoCDRedisHAImageEnvName); e != "" && (defaultTag && defaultImg) {
+	if e := argoutil.GetDefaultImage(common.ArgoCDRelatedImageRedisHAEnvName, common.ArgoCDRedisHAImageEnvName); e != "" && (defaultTag && defaultImg) {
 		return e
 	}
 	return argoutil.CombineImageTag(img, tag)
@@ -449,7 +860,7 @@ func getRedisHAProxyContainerImage(cr *argoprojv1a1.ArgoCD) string {
 	defaultImg, defaultTag := false, false
 	img := cr.Spec.HA.RedisProxyImage
 	if len(img) <= 0 {
-		img = common.ArgoCDDefaultRedisHAProxyImage
+		img = argoutil.ReplaceImageRegistry(common.ArgoCDDefaultRedisHAProxyImage, cr.Spec.ImageRegistry)
 		defaultImg = true
 	}
 
@@ -459,7 +870,7 @@ func getRedisHAProxyContainerImage(cr *argoprojv1a1.ArgoCD) string {
 		defaultTag = true
 	}
 
-	if e := os.Getenv(common.ArgoCDRedisHAProxyImageEnvName); e != "" && (defaultTag && defaultImg) {
+	if e := argoutil.GetDefaultImage(common.ArgoCDRelatedImageRedisHAProxyEnvName, common.ArgoCDRedisHAProxyImageEnvName); e != "" && (defaultTag && defaultImg) {
 		return e
 	}
 
@@ -470,9 +881,7 @@ func getRedisHAProxyContainerImage(cr *argoprojv1a1.ArgoCD) string {
 // If an error occurs, an empty string value will be returned.
 func getRedisInitScript(cr *argoprojv1a1.ArgoCD) string {
 	path := fmt.Sprintf("%s/init.sh.tpl", getRedisConfigPath())
-	vars := map[string]string{
-		"ServiceName": nameWithSuffix("redis-ha", cr),
-	}
+	vars := getRedisHATemplateVars(cr)
 
 	script, err := loadTemplateFile(path, vars)
 	if err != nil {
@@ -486,9 +895,7 @@ func getRedisInitScript(cr *argoprojv1a1.ArgoCD) string {
 // If an error occurs, an empty string value will be returned.
 func getRedisHAProxyConfig(cr *argoprojv1a1.ArgoCD) string {
 	path := fmt.Sprintf("%s/haproxy.cfg.tpl", getRedisConfigPath())
-	vars := map[string]string{
-		"ServiceName": nameWithSuffix("redis-ha", cr),
-	}
+	vars := getRedisHATemplateVars(cr)
 
 	script, err := loadTemplateFile(path, vars)
 	if err != nil {
@@ -498,13 +905,21 @@ func getRedisHAProxyConfig(cr *argoprojv1a1.ArgoCD) string {
 	return script
 }
 
+// redisHAProxyConfigChecksumAnnotation is the annotation on the Redis HA Proxy pod template with the checksum of the
+// Redis HA Proxy configuration.
+const redisHAProxyConfigChecksumAnnotation = "checksum/haproxy-config"
+
+// getRedisHAProxyConfigChecksum will return the checksum of the Redis HA Proxy configuration for the given ArgoCD. The
+// configuration is copied when the Redis HA Proxy starts, so the Redis HA Proxy is restarted when the checksum changes.
+func getRedisHAProxyConfigChecksum(cr *argoprojv1a1.ArgoCD) string {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(getRedisHAProxyConfig(cr))))
+}
+
 // getRedisHAProxyScript will load the Redis HA Proxy init script from a template on disk for the given ArgoCD.
 // If an error occurs, an empty string value will be returned.
 func getRedisHAProxyScript(cr *argoprojv1a1.ArgoCD) string {
 	path := fmt.Sprintf("%s/haproxy_init.sh.tpl", getRedisConfigPath())
-	vars := map[string]string{
-		"ServiceName": nameWithSuffix("redis-ha", cr),
-	}
+	vars := getRedisHATemplateVars(cr)
 
 	script, err := loadTemplateFile(path, vars)
 	if err != nil {
@@ -518,6 +933,11 @@ func getRedisHAProxyScript(cr *argoprojv1a1.ArgoCD) string {
 func getRedisResources(cr *argoprojv1a1.ArgoCD) corev1.ResourceRequirements {
 	resources := corev1.ResourceRequirements{}
 
+	// Allow override of the default resource requirements from the sizing profile
+	if profile := getSizingProfile(cr); profile != nil {
+		resources = *profile.redisResources.DeepCopy()
+	}
+
 	// Allow override of resource requirements from CR
 	if cr.Spec.Redis.Resources != nil {
 		resources = *cr.Spec.Redis.Resources
@@ -530,6 +950,11 @@ func getRedisResources(cr *argoprojv1a1.ArgoCD) corev1.ResourceRequirements {
 func getRedisHAProxyResources(cr *argoprojv1a1.ArgoCD) corev1.ResourceRequirements {
 	resources := corev1.ResourceRequirements{}
 
+	// Allow override of the default resource requirements from the sizing profile
+	if profile := getSizingProfile(cr); profile != nil {
+		resources = *profile.haProxyResources.DeepCopy()
+	}
+
 	// Allow override of resource requirements from CR
 	if cr.Spec.HA.Resources != nil {
 		resources = *cr.Spec.HA.Resources
@@ -538,11 +963,71 @@ func getRedisHAProxyResources(cr *argoprojv1a1.ArgoCD) corev1.ResourceRequiremen
 	return resources
 }
 
+// getRedisHADownAfterMilliseconds will return the time in milliseconds before the sentinels consider a Redis HA
+// server down.
+func getRedisHADownAfterMilliseconds(cr *argoprojv1a1.ArgoCD) int32 {
+	if ms := cr.Spec.HA.RedisConfig.DownAfterMilliseconds; ms > 0 {
+		return ms
+	}
+	return common.ArgoCDDefaultRedisHADownAfterMilliseconds
+}
+
+// getRedisHAFailoverTimeout will return the time in milliseconds the sentinels allow for a failover of Redis HA.
+func getRedisHAFailoverTimeout(cr *argoprojv1a1.ArgoCD) int32 {
+	if ms := cr.Spec.HA.RedisConfig.FailoverTimeout; ms > 0 {
+		return ms
+	}
+	return common.ArgoCDDefaultRedisHAFailoverTimeout
+}
+
+// getRedisHAProxyTime will return the given duration in the time format of the Redis HAProxy configuration, or the
+// given default when not set.
+func getRedisHAProxyTime(d *metav1.Duration, def time.Duration) string {
+	t := def
+	if d != nil && d.Duration > 0 {
+		t = d.Duration
+	}
+	for _, unit := range []struct {
+		d time.Duration
+		s string
+	}{{time.Hour, "h"}, {time.Minute, "m"}, {time.Second, "s"}} {
+		if t%unit.d == 0 {
+			return fmt.Sprintf("%d%s", t/unit.d, unit.s)
+		}
+	}
+	return fmt.Sprintf("%dms", t.Milliseconds())
+}
+
+// getRedisHATemplateVars will return the variables used to render the Redis HA scripts and configuration for the
+// given ArgoCD, with the index of each Redis HA server and its announce Service.
+func getRedisHATemplateVars(cr *argoprojv1a1.ArgoCD) map[string]interface{} {
+	replicas := make([]int32, *getRedisHAReplicas(cr))
+	for i := range replicas {
+		replicas[i] = int32(i)
+	}
+	haproxy := cr.Spec.HA.HAProxy
+	return map[string]interface{}{
+		"CheckInterval":  getRedisHAProxyTime(haproxy.CheckInterval, common.ArgoCDDefaultRedisHAProxyCheckInterval),
+		"Quorum":         getRedisHAQuorum(cr),
+		"Replicas":       replicas,
+		"ServiceName":    nameWithSuffix("redis-ha", cr),
+		"TimeoutCheck":   getRedisHAProxyTime(haproxy.TimeoutCheck, common.ArgoCDDefaultRedisHAProxyTimeoutCheck),
+		"TimeoutClient":  getRedisHAProxyTime(haproxy.TimeoutClient, common.ArgoCDDefaultRedisHAProxyTimeoutClient),
+		"TimeoutConnect": getRedisHAProxyTime(haproxy.TimeoutConnect, common.ArgoCDDefaultRedisHAProxyTimeoutConnect),
+		"TimeoutServer":  getRedisHAProxyTime(haproxy.TimeoutServer, common.ArgoCDDefaultRedisHAProxyTimeoutServer),
+	}
+}
+
 // getRedisSentinelConf will load the redis sentinel configuration from a template on disk for the given ArgoCD.
 // If an error occurs, an empty string value will be returned.
 func getRedisSentinelConf(cr *argoprojv1a1.ArgoCD) string {
 	path := fmt.Sprintf("%s/sentinel.conf.tpl", getRedisConfigPath())
-	conf, err := loadTemplateFile(path, map[string]string{})
+	vars := map[string]interface{}{
+		"DownAfterMilliseconds": getRedisHADownAfterMilliseconds(cr),
+		"FailoverTimeout":       getRedisHAFailoverTimeout(cr),
+	}
+
+	conf, err := loadTemplateFile(path, vars)
 	if err != nil {
 		log.Error(err, "unable to load redis sentinel configuration")
 		return ""
@@ -550,16 +1035,24 @@ func getRedisSentinelConf(cr *argoprojv1a1.ArgoCD) string {
 	return conf
 }
 
+// isManagedRedisEnabled will return true if the operator manages a Redis server for the given ArgoCD.
+func isManagedRedisEnabled(cr *argoprojv1a1.ArgoCD) bool {
+	return cr.Spec.Redis.Enabled == nil || *cr.Spec.Redis.Enabled
+}
+
 // getRedisServerAddress will return the Redis service address for the given ArgoCD.
 func getRedisServerAddress(cr *argoprojv1a1.ArgoCD) string {
-	if cr.Spec.HA.Enabled {
+	if !isManagedRedisEnabled(cr) {
+		return cr.Spec.Redis.Remote
+	}
+	if (cr.Spec.HA.Enabled && !isWaitingForRedisHA(cr)) || getRedisHAMigrationReason(cr) == redisHAMigrationWaitingForRedis {
 		return getRedisHAProxyAddress(cr)
 	}
 	return fqdnServiceRef(common.ArgoCDDefaultRedisSuffix, common.ArgoCDDefaultRedisPort, cr)
 }
 
 // loadTemplateFile will parse a template with the given path and execute it with the given params.
-func loadTemplateFile(path string, params map[string]string) (string, error) {
+func loadTemplateFile(path string, params interface{}) (string, error) {
 	tmpl, err := template.ParseFiles(path)
 	if err != nil {
 		log.Error(err, "unable to parse template")
@@ -575,6 +1068,17 @@ func loadTemplateFile(path string, params map[string]string) (string, error) {
 	return buf.String(), nil
 }
 
+// isLiveObjectFound will return true if the object with the given namespace and name exists, read directly from the
+// API server so that objects outside of the namespaces watched by the cache are found. The result will be stored in the
+// given object.
+func (r *ReconcileArgoCD) isLiveObjectFound(namespace string, name string, obj runtime.Object) (bool, error) {
+	err := r.reader.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: name}, obj)
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
 // nameWithSuffix will return a name based on the given ArgoCD. The given suffix is appended to the generated name.
 // Example: Given an ArgoCD with the name "example-argocd", providing the suffix "foo" would result in the value of
 // "example-argocd-foo" being returned.
@@ -599,9 +1103,17 @@ func InspectCluster() error {
 		return err
 	}
 
+	if err := verifyConsoleAPI(); err != nil {
+		return err
+	}
+
 	if err := verifyTemplateAPI(); err != nil {
 		return err
 	}
+
+	if err := verifyApplicationAPI(); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -619,112 +1131,174 @@ func (r *ReconcileArgoCD) reconcileCertificateAuthority(cr *argoprojv1a1.ArgoCD)
 	return nil
 }
 
-// reconcileResources will reconcile common ArgoCD resources.
-func (r *ReconcileArgoCD) reconcileResources(cr *argoprojv1a1.ArgoCD) error {
+// reconcileResources will reconcile common ArgoCD resources, recording a span in ctx for each of the reconcilers.
+func (r *ReconcileArgoCD) reconcileResources(ctx context.Context, cr *argoprojv1a1.ArgoCD) error {
 	log.Info("reconciling status")
-	if err := r.reconcileStatus(cr); err != nil {
+	if err := traceReconcile(ctx, "reconcileStatus", r.reconcileStatus, cr); err != nil {
 		return err
 	}
 
 	log.Info("reconciling roles")
-	if _, err := r.reconcileRoles(cr); err != nil {
+	if err := traceReconcile(ctx, "reconcileRoles", func(cr *argoprojv1a1.ArgoCD) error {
+		_, err := r.reconcileRoles(cr)
+		return err
+	}, cr); err != nil {
 		return err
 	}
 
 	log.Info("reconciling rolebindings")
-	if err := r.reconcileRoleBindings(cr); err != nil {
+	if err := traceReconcile(ctx, "reconcileRoleBindings", r.reconcileRoleBindings, cr); err != nil {
 		return err
 	}
 
 	log.Info("reconciling service accounts")
-	if err := r.reconcileServiceAccounts(cr); err != nil {
+	if err := traceReconcile(ctx, "reconcileServiceAccounts", r.reconcileServiceAccounts, cr); err != nil {
 		return err
 	}
 
 	log.Info("reconciling certificate authority")
-	if err := r.reconcileCertificateAuthority(cr); err != nil {
+	if err := traceReconcile(ctx, "reconcileCertificateAuthority", r.reconcileCertificateAuthority, cr); err != nil {
+		return err
+	}
+
+	// Restored Secrets must be present before the secrets are reconciled, to not generate new ones in their place.
+	log.Info("reconciling disaster recovery")
+	if err := traceReconcile(ctx, "reconcileDisasterRecovery", r.reconcileDisasterRecovery, cr); err != nil {
 		return err
 	}
 
 	log.Info("reconciling secrets")
-	if err := r.reconcileSecrets(cr); err != nil {
+	if err := traceReconcile(ctx, "reconcileSecrets", r.reconcileSecrets, cr); err != nil {
+		return err
+	}
+
+	// The migration decides which Redis resources are kept, so it is reconciled before any of them.
+	log.Info("reconciling redis ha migration")
+	if err := traceReconcile(ctx, "reconcileRedisHAMigration", r.reconcileRedisHAMigration, cr); err != nil {
 		return err
 	}
 
 	log.Info("reconciling config maps")
-	if err := r.reconcileConfigMaps(cr); err != nil {
+	if err := traceReconcile(ctx, "reconcileConfigMaps", r.reconcileConfigMaps, cr); err != nil {
 		return err
 	}
 
 	log.Info("reconciling services")
-	if err := r.reconcileServices(cr); err != nil {
+	if err := traceReconcile(ctx, "reconcileServices", r.reconcileServices, cr); err != nil {
 		return err
 	}
 
 	log.Info("reconciling deployments")
-	if err := r.reconcileDeployments(cr); err != nil {
+	if err := traceReconcile(ctx, "reconcileDeployments", r.reconcileDeployments, cr); err != nil {
 		return err
 	}
 
 	log.Info("reconciling statefulsets")
-	if err := r.reconcileStatefulSets(cr); err != nil {
+	if err := traceReconcile(ctx, "reconcileStatefulSets", r.reconcileStatefulSets, cr); err != nil {
 		return err
 	}
 
 	log.Info("reconciling autoscalers")
-	if err := r.reconcileAutoscalers(cr); err != nil {
+	if err := traceReconcile(ctx, "reconcileAutoscalers", r.reconcileAutoscalers, cr); err != nil {
+		return err
+	}
+
+	log.Info("reconciling hibernation")
+	if err := traceReconcile(ctx, "reconcileHibernation", r.reconcileHibernation, cr); err != nil {
 		return err
 	}
 
 	log.Info("reconciling ingresses")
-	if err := r.reconcileIngresses(cr); err != nil {
+	if err := traceReconcile(ctx, "reconcileIngresses", r.reconcileIngresses, cr); err != nil {
 		return err
 	}
 
 	if IsRouteAPIAvailable() {
 		log.Info("reconciling routes")
-		if err := r.reconcileRoutes(cr); err != nil {
+		if err := traceReconcile(ctx, "reconcileRoutes", r.reconcileRoutes, cr); err != nil {
+			return err
+		}
+	}
+
+	if IsConsoleAPIAvailable() {
+		log.Info("reconciling console link")
+		if err := traceReconcile(ctx, "reconcileConsoleLink", r.reconcileConsoleLink, cr); err != nil {
 			return err
 		}
 	}
 
 	if IsPrometheusAPIAvailable() {
 		log.Info("reconciling prometheus")
-		if err := r.reconcilePrometheus(cr); err != nil {
+		if err := traceReconcile(ctx, "reconcilePrometheus", r.reconcilePrometheus, cr); err != nil {
 			return err
 		}
 
-		if err := r.reconcileMetricsServiceMonitor(cr); err != nil {
+		if err := traceReconcile(ctx, "reconcileMetricsServiceMonitor", r.reconcileMetricsServiceMonitor, cr); err != nil {
 			return err
 		}
 
-		if err := r.reconcileRepoServerServiceMonitor(cr); err != nil {
+		if err := traceReconcile(ctx, "reconcileRepoServerServiceMonitor", r.reconcileRepoServerServiceMonitor, cr); err != nil {
 			return err
 		}
 
-		if err := r.reconcileServerMetricsServiceMonitor(cr); err != nil {
+		if err := traceReconcile(ctx, "reconcileServerMetricsServiceMonitor", r.reconcileServerMetricsServiceMonitor, cr); err != nil {
 			return err
 		}
 	}
 
 	if cr.Spec.ApplicationSet != nil {
 		log.Info("reconciling ApplicationSet controller")
-		if err := r.reconcileApplicationSetController(cr); err != nil {
+		if err := traceReconcile(ctx, "reconcileApplicationSetController", r.reconcileApplicationSetController, cr); err != nil {
 			return err
 		}
+	} else if err := traceReconcile(ctx, "deleteApplicationSetSourceNamespaceResources", func(cr *argoprojv1a1.ArgoCD) error {
+		return r.deleteApplicationSetSourceNamespaceResources(cr, nil)
+	}, cr); err != nil {
+		return err
 	}
 
-	if err := r.reconcileRepoServerTLSSecret(cr); err != nil {
+	log.Info("reconciling image updater")
+	if err := traceReconcile(ctx, "reconcileImageUpdater", r.reconcileImageUpdater, cr); err != nil {
+		return err
+	}
+
+	log.Info("reconciling ssh known hosts scans")
+	if err := traceReconcile(ctx, "reconcileKnownHostsAutoScan", r.reconcileKnownHostsAutoScan, cr); err != nil {
+		return err
+	}
+
+	log.Info("reconciling cli config")
+	if err := traceReconcile(ctx, "reconcileCLIConfig", r.reconcileCLIConfig, cr); err != nil {
+		return err
+	}
+
+	if err := traceReconcile(ctx, "reconcileRepoServerTLSSecret", r.reconcileRepoServerTLSSecret, cr); err != nil {
 		return err
 	}
 
 	if cr.Spec.SSO != nil {
 		log.Info("reconciling SSO")
-		if err := r.reconcileSSO(cr); err != nil {
+		if err := traceReconcile(ctx, "reconcileSSO", r.reconcileSSO, cr); err != nil {
 			return err
 		}
 	}
 
+	if err := traceReconcile(ctx, "reconcileSSOClientSecretRotation", r.reconcileSSOClientSecretRotation, cr); err != nil {
+		return err
+	}
+
+	log.Info("pruning resources of disabled features")
+	if err := traceReconcile(ctx, "reconcilePrunedResources", r.reconcilePrunedResources, cr); err != nil {
+		return err
+	}
+
+	log.Info("pruning cluster resources of deleted instances")
+	if err := traceReconcile(ctx, "reconcileOrphanedClusterResources", func(*argoprojv1a1.ArgoCD) error {
+		return r.reconcileOrphanedClusterResources()
+	}, cr); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -752,6 +1326,24 @@ func (r *ReconcileArgoCD) deleteClusterResources(cr *argoprojv1a1.ArgoCD) error
 		return err
 	}
 
+	if err := r.deleteApplicationSetSourceNamespaceResources(cr, nil); err != nil {
+		return err
+	}
+
+	if err := r.deleteCLIConfigMaps(cr, nil); err != nil {
+		return err
+	}
+
+	if err := r.deleteCLIConfigSecrets(cr, nil); err != nil {
+		return err
+	}
+
+	if IsConsoleAPIAvailable() {
+		if err := r.deleteConsoleLink(cr); err != nil {
+			return fmt.Errorf("failed to delete ConsoleLink for %s: %w", cr.Name, err)
+		}
+	}
+
 	return nil
 }
 
@@ -817,6 +1409,17 @@ func labelsForCluster(cr *argoprojv1a1.ArgoCD) map[string]string {
 	return labels
 }
 
+// managedResourceListOptions returns the options used to list the resources managed for the given ArgoCD.
+func managedResourceListOptions(cr *argoprojv1a1.ArgoCD) []client.ListOption {
+	return []client.ListOption{
+		client.InNamespace(cr.Namespace),
+		client.MatchingLabels{
+			common.ArgoCDKeyManagedBy: cr.Name,
+			common.ArgoCDKeyPartOf:    common.ArgoCDAppName,
+		},
+	}
+}
+
 // annotationsForCluster returns the annotations for all cluster resources.
 func annotationsForCluster(cr *argoprojv1a1.ArgoCD) map[string]string {
 	annotations := argoutil.DefaultAnnotations(cr)
@@ -827,7 +1430,7 @@ func annotationsForCluster(cr *argoprojv1a1.ArgoCD) map[string]string {
 }
 
 // watchResources will register Watches for each of the supported Resources.
-func watchResources(c controller.Controller, clusterResourceMapper, tlsSecretMapper, namespaceResourceMapper handler.ToRequestsFunc) error {
+func watchResources(c controller.Controller, clusterResourceMapper, tlsSecretMapper, dexTokenSecretMapper, dexConfigSecretMapper, serverTLSSecretMapper, secretKeysMapper, resourceHealthChecksMapper, tlsCertsMapper, disasterRecoverySecretMapper, clusterDiscoverySecretMapper, helmOCIRegistrySecretMapper, namespaceResourceMapper, argoCDDefaultMapper, applicationMapper handler.ToRequestsFunc) error {
 
 	deploymentConfigPred := predicate.Funcs{
 		UpdateFunc: func(e event.UpdateEvent) bool {
@@ -928,6 +1531,42 @@ func watchResources(c controller.Controller, clusterResourceMapper, tlsSecretMap
 		ToRequests: tlsSecretMapper,
 	}
 
+	dexTokenSecretHandler := &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: dexTokenSecretMapper,
+	}
+
+	dexConfigSecretHandler := &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: dexConfigSecretMapper,
+	}
+
+	serverTLSSecretHandler := &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: serverTLSSecretMapper,
+	}
+
+	secretKeysHandler := &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: secretKeysMapper,
+	}
+
+	resourceHealthChecksHandler := &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: resourceHealthChecksMapper,
+	}
+
+	tlsCertsHandler := &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: tlsCertsMapper,
+	}
+
+	disasterRecoverySecretHandler := &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: disasterRecoverySecretMapper,
+	}
+
+	clusterDiscoverySecretHandler := &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: clusterDiscoverySecretMapper,
+	}
+
+	helmOCIRegistrySecretHandler := &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: helmOCIRegistrySecretMapper,
+	}
+
 	if err := c.Watch(&source.Kind{Type: &v1.ClusterRoleBinding{}}, clusterResourceHandler); err != nil {
 		return err
 	}
@@ -937,7 +1576,72 @@ func watchResources(c controller.Controller, clusterResourceMapper, tlsSecretMap
 	}
 
 	// Watch for secrets of type TLS that might be created by external processes
-	if err := c.Watch(&source.Kind{Type: &corev1.Secret{Type: corev1.SecretTypeTLS}}, tlsSecretHandler); err != nil {
+	if err := c.Watch(&source.Kind{Type: &corev1.Secret{}}, tlsSecretHandler, secretTypePredicate(corev1.SecretTypeTLS)); err != nil {
+		return err
+	}
+
+	// Watch for ServiceAccount token secrets used as the OpenShift OAuth client secret for Dex, so that the Dex
+	// configuration is regenerated when the token is rotated.
+	if err := c.Watch(&source.Kind{Type: &corev1.Secret{}}, dexTokenSecretHandler, secretTypePredicate(corev1.SecretTypeServiceAccountToken)); err != nil {
+		return err
+	}
+
+	// Watch for the Secrets that the Dex configuration may reference, which Argo CD requires to be labeled as part of
+	// Argo CD, so that Dex is restarted when the referenced values change.
+	if err := c.Watch(&source.Kind{Type: &corev1.Secret{}}, dexConfigSecretHandler, partOfArgoCDPredicate(), notOwnedByArgoCDPredicate()); err != nil {
+		return err
+	}
+
+	// Watch for existing Secrets used as the certificate of the Argo CD Server, so that the Argo CD Secret is updated
+	// when the certificate is rotated.
+	if err := c.Watch(&source.Kind{Type: &corev1.Secret{}}, serverTLSSecretHandler); err != nil {
+		return err
+	}
+
+	// Watch for existing Secrets holding keys of the Argo CD Secret, so that the keys are updated when they change.
+	if err := c.Watch(&source.Kind{Type: &corev1.Secret{}}, secretKeysHandler, notOwnedByArgoCDPredicate()); err != nil {
+		return err
+	}
+
+	// Watch for the critical Secrets mirrored into the disaster recovery bundle, and for the bundle itself, so that the
+	// bundle is refreshed when they change.
+	if err := c.Watch(&source.Kind{Type: &corev1.Secret{}}, disasterRecoverySecretHandler); err != nil {
+		return err
+	}
+
+	// Watch for the kubeconfig Secrets selected for cluster discovery, so that the cluster Secrets are created, updated
+	// and removed along with them. The selector is matched against both the old and the new labels of an update, so
+	// that a Secret that is no longer selected has its cluster Secret removed.
+	if err := c.Watch(&source.Kind{Type: &corev1.Secret{}}, clusterDiscoverySecretHandler, secretDataKeyPredicate(common.ArgoCDKeyClusterDiscoveryKubeconfig), notOwnedByArgoCDPredicate()); err != nil {
+		return err
+	}
+
+	// Watch for the Secrets holding the credentials of Helm OCI registries, so that the repository credentials are
+	// updated when they change.
+	if err := c.Watch(&source.Kind{Type: &corev1.Secret{}}, helmOCIRegistrySecretHandler, notOwnedByArgoCDPredicate()); err != nil {
+		return err
+	}
+
+	// Watch for the ArgoCDDefault of the cluster, so that every ArgoCD inherits the changes to the defaults.
+	if err := c.Watch(&source.Kind{Type: &argoprojv1a1.ArgoCDDefault{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: argoCDDefaultMapper,
+	}); err != nil {
+		return err
+	}
+
+	// Watch for ConfigMaps with Lua health checks that are imported into the resource customizations.
+	if err := c.Watch(&source.Kind{Type: &corev1.ConfigMap{}}, resourceHealthChecksHandler); err != nil {
+		return err
+	}
+
+	// Watch for ConfigMaps with TLS certificates that are added to the argocd-tls-certs-cm ConfigMap, which is itself
+	// owned by the ArgoCD and filtered out.
+	if err := c.Watch(&source.Kind{Type: &corev1.ConfigMap{}}, tlsCertsHandler, notOwnedByArgoCDPredicate()); err != nil {
+		return err
+	}
+
+	// Watch for changes to ServiceAccount sub-resources owned by ArgoCD instances.
+	if err := watchOwnedResource(c, &corev1.ServiceAccount{}); err != nil {
 		return err
 	}
 
@@ -982,6 +1686,17 @@ func watchResources(c controller.Controller, clusterResourceMapper, tlsSecretMap
 		}
 	}
 
+	if IsApplicationAPIAvailable() {
+		// Watch for changes to the health and sync status of the Applications summarized in the status of ArgoCDs.
+		application := &unstructured.Unstructured{}
+		application.SetGroupVersionKind(applicationGVK)
+		if err := c.Watch(&source.Kind{Type: application}, &handler.EnqueueRequestsFromMapFunc{
+			ToRequests: applicationMapper,
+		}, applicationStatusPredicate()); err != nil {
+			return err
+		}
+	}
+
 	namespaceHandler := &handler.EnqueueRequestsFromMapFunc{
 		ToRequests: namespaceResourceMapper,
 	}
@@ -1044,6 +1759,61 @@ func allowedNamespace(current string, namespaces string) bool {
 	return false
 }
 
+// matchNamespace will return true when the given namespace matches one of the given patterns, which may contain
+// shell wildcards, e.g. "team-*".
+func matchNamespace(namespace string, patterns []string) bool {
+	for _, p := range patterns {
+		if ok, err := path.Match(p, namespace); err == nil && ok {
+			return true
+		}
+	}
+	return false
+}
+
+// isManagedNamespaceAllowed will return true when the given namespace may be managed by the ArgoCD instance in the
+// given namespace, according to the managed namespaces allowlist and denylist of the operator. An instance always
+// manages its own namespace, and instances without an allowlist entry may manage any namespace that is not denied.
+func isManagedNamespaceAllowed(argocdNamespace string, namespace string) bool {
+	if namespace == argocdNamespace {
+		return true
+	}
+
+	if matchNamespace(namespace, splitList(os.Getenv(common.ArgoCDManagedNamespacesDenylistEnvName))) {
+		return false
+	}
+
+	for _, entry := range strings.Split(os.Getenv(common.ArgoCDManagedNamespacesAllowlistEnvName), ";") {
+		parts := strings.SplitN(entry, "=", 2)
+		if len(parts) == 2 && strings.TrimSpace(parts[0]) == argocdNamespace {
+			return matchNamespace(namespace, splitList(parts[1]))
+		}
+	}
+	return true
+}
+
+// getManagedNamespaces will return the namespaces that carry the managed-by label for the given ArgoCD, split into
+// the namespaces the ArgoCD is allowed to manage and the ones that are rejected by the operator configuration.
+func (r *ReconcileArgoCD) getManagedNamespaces(cr *argoprojv1a1.ArgoCD) ([]corev1.Namespace, []corev1.Namespace, error) {
+	namespaces := corev1.NamespaceList{}
+	if err := r.client.List(context.TODO(), &namespaces, client.MatchingLabels{
+		common.ArgoCDManagedByLabel: cr.Namespace,
+	}); err != nil {
+		return nil, nil, err
+	}
+
+	var allowed, rejected []corev1.Namespace
+	for _, ns := range namespaces.Items {
+		if isManagedNamespaceAllowed(cr.Namespace, ns.Name) {
+			allowed = append(allowed, ns)
+			continue
+		}
+		log.Info(fmt.Sprintf("namespace %s is not allowed to be managed by ArgoCD %s in namespace %s",
+			ns.Name, cr.Name, cr.Namespace))
+		rejected = append(rejected, ns)
+	}
+	return allowed, rejected, nil
+}
+
 func splitList(s string) []string {
 	elems := strings.Split(s, ",")
 	for i := range elems {
@@ -1061,6 +1831,63 @@ func containsString(arr []string, s string) bool {
 	return false
 }
 
+// objectPredicate will return a predicate that only passes the events of the objects for which the given function
+// returns true. An update passes when either the old or the new object does, so that a change that deselects an
+// object, e.g. a removed label, is still mapped to a reconcile.
+func objectPredicate(f func(meta metav1.Object, obj runtime.Object) bool) predicate.Predicate {
+	return predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			return f(e.Meta, e.Object)
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return f(e.MetaOld, e.ObjectOld) || f(e.MetaNew, e.ObjectNew)
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return f(e.Meta, e.Object)
+		},
+		GenericFunc: func(e event.GenericEvent) bool {
+			return f(e.Meta, e.Object)
+		},
+	}
+}
+
+// notOwnedByArgoCDPredicate will return a predicate that filters out the objects controlled by an ArgoCD, as the
+// changes to those are already reconciled through the watches of the owned resources.
+func notOwnedByArgoCDPredicate() predicate.Predicate {
+	return objectPredicate(func(meta metav1.Object, obj runtime.Object) bool {
+		ref := metav1.GetControllerOf(meta)
+		return ref == nil || ref.Kind != "ArgoCD"
+	})
+}
+
+// partOfArgoCDPredicate will return a predicate that only passes the events of the objects labeled as part of Argo CD.
+func partOfArgoCDPredicate() predicate.Predicate {
+	return objectPredicate(func(meta metav1.Object, obj runtime.Object) bool {
+		return meta.GetLabels()[common.ArgoCDKeyPartOf] == common.ArgoCDAppName
+	})
+}
+
+// secretTypePredicate will return a predicate that only passes the events of the Secrets of the given type. The type
+// of the object given to a watch is not used as a filter.
+func secretTypePredicate(secretType corev1.SecretType) predicate.Predicate {
+	return objectPredicate(func(meta metav1.Object, obj runtime.Object) bool {
+		secret, ok := obj.(*corev1.Secret)
+		return ok && secret.Type == secretType
+	})
+}
+
+// secretDataKeyPredicate will return a predicate that only passes the events of the Secrets holding the given key.
+func secretDataKeyPredicate(key string) predicate.Predicate {
+	return objectPredicate(func(meta metav1.Object, obj runtime.Object) bool {
+		secret, ok := obj.(*corev1.Secret)
+		if !ok {
+			return false
+		}
+		_, ok = secret.Data[key]
+		return ok
+	})
+}
+
 func namespaceFilterPredicate() predicate.Predicate {
 	return predicate.Funcs{
 		UpdateFunc: func(e event.UpdateEvent) bool {
@@ -1071,3 +1898,76 @@ func namespaceFilterPredicate() predicate.Predicate {
 		},
 	}
 }
+
+// applyManagedMetadata will ensure that the given metadata of a Route, an Ingress or a Service carries the given labels
+// and annotations, next to the ones added by others, e.g. by the router or by cert-manager. The keys of the applied
+// labels and annotations are recorded in annotations, so that the ones that are no longer given are removed. Returns
+// true when the metadata changed.
+func applyManagedMetadata(meta *metav1.ObjectMeta, labels, annotations map[string]string) bool {
+	if meta.Labels == nil {
+		meta.Labels = make(map[string]string)
+	}
+	if meta.Annotations == nil {
+		meta.Annotations = make(map[string]string)
+	}
+	changed := applyManagedKeys(meta.Labels, labels, meta.Annotations, common.ArgoCDManagedLabelsAnnotation)
+	if applyManagedKeys(meta.Annotations, annotations, meta.Annotations, common.ArgoCDManagedAnnotationsAnnotation) {
+		changed = true
+	}
+	return changed
+}
+
+// seedManagedAnnotations will record the given keys as managed annotations of the given metadata when no keys are
+// recorded yet, so that the default annotations set on an object before the managed annotations were recorded are
+// removed once they are no longer desired. Only the keys found in the annotations are recorded.
+func seedManagedAnnotations(meta *metav1.ObjectMeta, keys []string) {
+	if _, ok := meta.Annotations[common.ArgoCDManagedAnnotationsAnnotation]; ok {
+		return
+	}
+	seeded := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if _, ok := meta.Annotations[key]; ok {
+			seeded = append(seeded, key)
+		}
+	}
+	if len(seeded) > 0 {
+		sort.Strings(seeded)
+		meta.Annotations[common.ArgoCDManagedAnnotationsAnnotation] = strings.Join(seeded, ",")
+	}
+}
+
+// applyManagedKeys will set the desired keys in current, and remove the keys recorded in the given annotation that are
+// no longer desired. Returns true when current or the annotation changed.
+func applyManagedKeys(current, desired, annotations map[string]string, annotation string) bool {
+	changed := false
+	for _, key := range strings.Split(annotations[annotation], ",") {
+		if _, ok := desired[key]; ok || key == "" {
+			continue
+		}
+		if _, ok := current[key]; ok {
+			delete(current, key)
+			changed = true
+		}
+	}
+
+	keys := make([]string, 0, len(desired))
+	for key, value := range desired {
+		keys = append(keys, key)
+		if current[key] != value {
+			current[key] = value
+			changed = true
+		}
+	}
+	sort.Strings(keys)
+
+	managed := strings.Join(keys, ",")
+	if annotations[annotation] != managed {
+		if managed == "" {
+			delete(annotations, annotation)
+		} else {
+			annotations[annotation] = managed
+		}
+		changed = true
+	}
+	return changed
+}
@@ -10,6 +10,9 @@ import (
 	"github.com/argoproj-labs/argocd-operator/pkg/common"
 	"github.com/argoproj-labs/argocd-operator/pkg/controller/argoutil"
 	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 )
 
 const (
@@ -19,6 +22,7 @@ const (
 	redisTestImage        = "testing/redis:latest"
 	redisHATestImage      = "testing/redis:latest-ha"
 	redisHAProxyTestImage = "testing/redis-ha-haproxy:latest-ha"
+	testImageDigest       = "sha256:1f8d1b7c0e8e4a0f5a5b7c8d9e0f1a2b3c4d5e6f7a8b9c0d1e2f3a4b5c6d7e8f"
 )
 
 var imageTests = []struct {
@@ -187,6 +191,87 @@ var imageTests = []struct {
 			os.Setenv(common.ArgoCDRedisHAProxyImageEnvName, redisHAProxyTestImage)
 		},
 	},
+	{
+		name:      "argo spec digest configuration",
+		imageFunc: getArgoContainerImage,
+		want:      "testing/argocd@" + testImageDigest,
+		opts: []argoCDOpt{func(a *argoprojv1alpha1.ArgoCD) {
+			a.Spec.Image = "testing/argocd"
+			a.Spec.Version = testImageDigest
+		}},
+	},
+	{
+		name:      "argo related image env configuration",
+		imageFunc: getArgoContainerImage,
+		want:      "testing/argocd@" + testImageDigest,
+		pre: func(t *testing.T) {
+			setTestEnv(t, common.ArgoCDImageEnvName, argoTestImage)
+			setTestEnv(t, common.ArgoCDRelatedImageArgoCDEnvName, "testing/argocd@"+testImageDigest)
+		},
+	},
+	{
+		name:      "argo related image env does not override spec",
+		imageFunc: getArgoContainerImage,
+		want:      argoTestImage,
+		pre: func(t *testing.T) {
+			setTestEnv(t, common.ArgoCDRelatedImageArgoCDEnvName, "testing/argocd@"+testImageDigest)
+		},
+		opts: []argoCDOpt{func(a *argoprojv1alpha1.ArgoCD) {
+			a.Spec.Image = "testing/argocd"
+			a.Spec.Version = "latest"
+		}},
+	},
+	{
+		name:      "dex related image env configuration",
+		imageFunc: getDexContainerImage,
+		want:      dexTestImage,
+		pre: func(t *testing.T) {
+			setTestEnv(t, common.ArgoCDRelatedImageDexEnvName, dexTestImage)
+		},
+	},
+	{
+		name:      "redis ha proxy related image env configuration",
+		imageFunc: getRedisHAProxyContainerImage,
+		want:      redisHAProxyTestImage,
+		pre: func(t *testing.T) {
+			setTestEnv(t, common.ArgoCDRelatedImageRedisHAProxyEnvName, redisHAProxyTestImage)
+		},
+	},
+	{
+		name:      "argo image registry configuration",
+		imageFunc: getArgoContainerImage,
+		want:      argoutil.CombineImageTag("mirror.example.com/"+common.ArgoCDDefaultArgoImage, common.ArgoCDDefaultArgoVersion),
+		opts: []argoCDOpt{func(a *argoprojv1alpha1.ArgoCD) {
+			a.Spec.ImageRegistry = "mirror.example.com"
+		}},
+	},
+	{
+		name:      "dex image registry configuration",
+		imageFunc: getDexContainerImage,
+		want:      argoutil.CombineImageTag("mirror.example.com/dexidp/dex", common.ArgoCDDefaultDexVersion),
+		opts: []argoCDOpt{func(a *argoprojv1alpha1.ArgoCD) {
+			a.Spec.ImageRegistry = "mirror.example.com"
+		}},
+	},
+	{
+		name:      "redis image registry does not override spec",
+		imageFunc: getRedisContainerImage,
+		want:      redisTestImage,
+		opts: []argoCDOpt{func(a *argoprojv1alpha1.ArgoCD) {
+			a.Spec.ImageRegistry = "mirror.example.com"
+			a.Spec.Redis.Image = "testing/redis"
+			a.Spec.Redis.Version = "latest"
+		}},
+	},
+}
+
+func setTestEnv(t *testing.T, name, value string) {
+	t.Helper()
+	old := os.Getenv(name)
+	t.Cleanup(func() {
+		os.Setenv(name, old)
+	})
+	os.Setenv(name, value)
 }
 
 func TestContainerImages_configuration(t *testing.T) {
@@ -377,3 +462,213 @@ func TestGetArgoApplicationControllerCommand(t *testing.T) {
 		}
 	}
 }
+
+func TestGetArgoApplicationControllerCommand_operatorDefaults(t *testing.T) {
+	os.Setenv(common.ArgoCDDefaultControllerOperationProcessorsEnvName, "25")
+	os.Setenv(common.ArgoCDDefaultControllerStatusProcessorsEnvName, "50")
+	os.Setenv(common.ArgoCDDefaultLogLevelEnvName, "debug")
+	defer os.Unsetenv(common.ArgoCDDefaultControllerOperationProcessorsEnvName)
+	defer os.Unsetenv(common.ArgoCDDefaultControllerStatusProcessorsEnvName)
+	defer os.Unsetenv(common.ArgoCDDefaultLogLevelEnvName)
+
+	want := []string{
+		"argocd-application-controller",
+		"--operation-processors",
+		"25",
+		"--redis",
+		"argocd-redis.argocd.svc.cluster.local:6379",
+		"--repo-server",
+		"argocd-repo-server.argocd.svc.cluster.local:8081",
+		"--status-processors",
+		"30",
+		"--loglevel",
+		"debug",
+	}
+	cmd := getArgoApplicationControllerCommand(makeTestArgoCD(controllerProcessors(30)))
+	assert.DeepEqual(t, cmd, want)
+}
+
+func TestGetDefaultResourcesFromEnv(t *testing.T) {
+	os.Setenv(common.ArgoCDDefaultRepoResourcesEnvName, `{"limits":{"cpu":"1","memory":"1Gi"}}`)
+	os.Setenv(common.ArgoCDDefaultControllerResourcesEnvName, "not-json")
+	defer os.Unsetenv(common.ArgoCDDefaultRepoResourcesEnvName)
+	defer os.Unsetenv(common.ArgoCDDefaultControllerResourcesEnvName)
+
+	a := makeTestArgoCD()
+	repo := getArgoRepoResources(a)
+	assert.Equal(t, repo.Limits.Cpu().String(), "1")
+	assert.Equal(t, repo.Limits.Memory().String(), "1Gi")
+	assert.Equal(t, len(getArgoApplicationControllerResources(a).Limits), 0)
+
+	// Resources set on the ArgoCD take precedence over the defaults of the operator.
+	a.Spec.Repo.Resources = makeTestDexResources()
+	assert.Assert(t, reflect.DeepEqual(getArgoRepoResources(a), *makeTestDexResources()))
+}
+
+func TestGetArgoApplicationControllerCommand_timeouts(t *testing.T) {
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.Controller.SelfHealTimeout = &metav1.Duration{Duration: time.Second * 30}
+		a.Spec.Controller.SyncTimeout = &metav1.Duration{Duration: time.Minute * 30}
+	})
+
+	want := []string{
+		"argocd-application-controller",
+		"--operation-processors",
+		"10",
+		"--redis",
+		"argocd-redis.argocd.svc.cluster.local:6379",
+		"--repo-server",
+		"argocd-repo-server.argocd.svc.cluster.local:8081",
+		"--status-processors",
+		"20",
+		"--self-heal-timeout-seconds",
+		"30",
+	}
+	// The sync timeout is not passed to a version of Argo CD that does not support it.
+	assert.DeepEqual(t, getArgoApplicationControllerCommand(a), want)
+
+	a.Spec.Version = "v2.10.0"
+	a.Spec.Controller.SelfHealTimeout = &metav1.Duration{Duration: time.Millisecond * 1500}
+	want[10] = "2"
+	want = append(want, "--sync-timeout", "1800")
+	assert.DeepEqual(t, getArgoApplicationControllerCommand(a), want)
+}
+
+func TestIsManagedNamespaceAllowed(t *testing.T) {
+	t.Cleanup(func() {
+		os.Unsetenv(common.ArgoCDManagedNamespacesAllowlistEnvName)
+		os.Unsetenv(common.ArgoCDManagedNamespacesDenylistEnvName)
+	})
+
+	assert.Assert(t, isManagedNamespaceAllowed("argocd", "team-a"))
+
+	os.Setenv(common.ArgoCDManagedNamespacesDenylistEnvName, "kube-*, openshift-*")
+	os.Setenv(common.ArgoCDManagedNamespacesAllowlistEnvName, "argocd=team-a,team-b-*;gitops=*")
+
+	allowTests := []struct {
+		argocd    string
+		namespace string
+		want      bool
+	}{
+		{"argocd", "argocd", true},
+		{"argocd", "team-a", true},
+		{"argocd", "team-b-dev", true},
+		{"argocd", "team-c", false},
+		{"argocd", "kube-system", false},
+		{"gitops", "team-c", true},
+		{"gitops", "openshift-config", false},
+		{"kube-system", "kube-system", true},
+		{"other", "team-c", true},
+	}
+	for _, tt := range allowTests {
+		assert.Equal(t, isManagedNamespaceAllowed(tt.argocd, tt.namespace), tt.want, "%s/%s", tt.argocd, tt.namespace)
+	}
+}
+
+func defaultInitContainerResources() corev1.ResourceRequirements {
+	return newResourceRequirements(
+		common.ArgoCDDefaultInitContainerResourceRequestCPU,
+		common.ArgoCDDefaultInitContainerResourceRequestMemory,
+		common.ArgoCDDefaultInitContainerResourceLimitCPU,
+		common.ArgoCDDefaultInitContainerResourceLimitMemory)
+}
+
+func TestGetInitContainerResources(t *testing.T) {
+	main := newResourceRequirements("1", "1Gi", "2", "2Gi")
+	custom := newResourceRequirements("50m", "64Mi", "100m", "128Mi")
+
+	t.Run("main container without resources", func(t *testing.T) {
+		assert.DeepEqual(t, getInitContainerResources(nil, corev1.ResourceRequirements{}), corev1.ResourceRequirements{})
+	})
+
+	t.Run("main container with resources", func(t *testing.T) {
+		assert.DeepEqual(t, getInitContainerResources(nil, main), defaultInitContainerResources())
+	})
+
+	t.Run("resources from the CR", func(t *testing.T) {
+		assert.DeepEqual(t, getInitContainerResources(&custom, main), custom)
+	})
+
+	t.Run("resources from the operator environment", func(t *testing.T) {
+		os.Setenv(common.ArgoCDDefaultInitContainerResourcesEnvName, `{"requests":{"cpu":"50m","memory":"64Mi"},"limits":{"cpu":"100m","memory":"128Mi"}}`)
+		t.Cleanup(func() {
+			os.Unsetenv(common.ArgoCDDefaultInitContainerResourcesEnvName)
+		})
+		assert.DeepEqual(t, getInitContainerResources(nil, corev1.ResourceRequirements{}), custom)
+	})
+}
+
+func TestNotOwnedByArgoCDPredicate(t *testing.T) {
+	a := makeTestArgoCD()
+	owned := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "owned",
+			Namespace:       a.Namespace,
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(a, argoprojv1alpha1.SchemeGroupVersion.WithKind("ArgoCD"))},
+		},
+	}
+	unowned := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "unowned",
+			Namespace: a.Namespace,
+		},
+	}
+
+	p := notOwnedByArgoCDPredicate()
+	assert.Assert(t, !p.Create(event.CreateEvent{Meta: owned, Object: owned}))
+	assert.Assert(t, p.Create(event.CreateEvent{Meta: unowned, Object: unowned}))
+	assert.Assert(t, !p.Delete(event.DeleteEvent{Meta: owned, Object: owned}))
+
+	// An update passes when either the old or the new object passes.
+	assert.Assert(t, p.Update(event.UpdateEvent{MetaOld: unowned, ObjectOld: unowned, MetaNew: owned, ObjectNew: owned}))
+	assert.Assert(t, !p.Update(event.UpdateEvent{MetaOld: owned, ObjectOld: owned, MetaNew: owned, ObjectNew: owned}))
+}
+
+func TestSecretTypePredicate(t *testing.T) {
+	token := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "token", Namespace: testNamespace},
+		Type:       corev1.SecretTypeServiceAccountToken,
+	}
+	opaque := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "opaque", Namespace: testNamespace},
+		Type:       corev1.SecretTypeOpaque,
+	}
+
+	p := secretTypePredicate(corev1.SecretTypeServiceAccountToken)
+	assert.Assert(t, p.Create(event.CreateEvent{Meta: token, Object: token}))
+	assert.Assert(t, !p.Create(event.CreateEvent{Meta: opaque, Object: opaque}))
+	assert.Assert(t, !p.Update(event.UpdateEvent{MetaOld: opaque, ObjectOld: opaque, MetaNew: opaque, ObjectNew: opaque}))
+	assert.Assert(t, p.Delete(event.DeleteEvent{Meta: token, Object: token}))
+}
+
+func TestSecretDataKeyPredicate(t *testing.T) {
+	kubeconfig := makeTestKubeconfigSecret("prod")
+	opaque := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "opaque", Namespace: testNamespace},
+		Data:       map[string][]byte{"password": []byte("secret")},
+	}
+
+	p := secretDataKeyPredicate(common.ArgoCDKeyClusterDiscoveryKubeconfig)
+	assert.Assert(t, p.Create(event.CreateEvent{Meta: kubeconfig, Object: kubeconfig}))
+	assert.Assert(t, !p.Create(event.CreateEvent{Meta: opaque, Object: opaque}))
+	assert.Assert(t, p.Update(event.UpdateEvent{MetaOld: kubeconfig, ObjectOld: kubeconfig, MetaNew: opaque, ObjectNew: opaque}))
+	assert.Assert(t, !p.Delete(event.DeleteEvent{Meta: opaque, Object: opaque}))
+}
+
+func TestProbeChanged(t *testing.T) {
+	desired := getHealthzStartupProbe(&corev1.Probe{FailureThreshold: 60}, 8080)
+	defaulted := desired.DeepCopy()
+	defaulted.HTTPGet.Scheme = corev1.URISchemeHTTP
+	defaulted.TimeoutSeconds = 1
+	defaulted.PeriodSeconds = 10
+	defaulted.SuccessThreshold = 1
+
+	assert.Assert(t, !probeChanged(nil, nil))
+	assert.Assert(t, !probeChanged(desired, desired))
+	assert.Assert(t, !probeChanged(defaulted, desired))
+	assert.Assert(t, probeChanged(nil, desired))
+	assert.Assert(t, probeChanged(defaulted, nil))
+
+	defaulted.FailureThreshold = 30
+	assert.Assert(t, probeChanged(defaulted, desired))
+}
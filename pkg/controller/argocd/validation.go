@@ -0,0 +1,804 @@
+// Copyright 2019 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocd
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
+	"github.com/argoproj-labs/argocd-operator/pkg/controller/argoutil"
+	routev1 "github.com/openshift/api/route/v1"
+	"gopkg.in/yaml.v2"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilvalidation "k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+const (
+	// specValidReasonValid is the SpecValid condition reason when the spec passed validation.
+	specValidReasonValid = "Valid"
+
+	// specValidReasonInvalid is the SpecValid condition reason when the spec failed validation.
+	specValidReasonInvalid = "InvalidSpec"
+)
+
+// redisMemoryPattern matches a Redis memory size, in bytes or with a unit.
+var redisMemoryPattern = regexp.MustCompile(`^[0-9]+([kKmMgG][bB]?)?$`)
+
+var (
+	// argoCDProfiles are the supported sizing profiles.
+	argoCDProfiles = []string{common.ArgoCDProfileSmall, common.ArgoCDProfileMedium, common.ArgoCDProfileLarge}
+
+	// argoCDComplianceModes are the supported compliance modes.
+	argoCDComplianceModes = []string{common.ArgoCDComplianceModeRestricted}
+
+	// tlsMinVersions are the supported minimum TLS versions of the Argo CD Server.
+	tlsMinVersions = []string{"1.0", "1.1", "1.2", "1.3"}
+)
+
+// resourceRequirementsField is a ResourceRequirements field of the ArgoCD spec to validate.
+type resourceRequirementsField struct {
+	path *field.Path
+	r    *corev1.ResourceRequirements
+}
+
+// validateArgoCDSpec will check the given ArgoCD spec for semantic errors that the API server does not catch, such
+// as resource requests that exceed their limits or unsupported enumeration values.
+func validateArgoCDSpec(cr *argoprojv1a1.ArgoCD) field.ErrorList {
+	allErrs := field.ErrorList{}
+	spec := field.NewPath("spec")
+
+	resources := []resourceRequirementsField{
+		{spec.Child("controller", "initContainerResources"), cr.Spec.Controller.InitContainerResources},
+		{spec.Child("controller", "resources"), cr.Spec.Controller.Resources},
+		{spec.Child("dex", "initContainerResources"), cr.Spec.Dex.InitContainerResources},
+		{spec.Child("dex", "resources"), cr.Spec.Dex.Resources},
+		{spec.Child("grafana", "resources"), cr.Spec.Grafana.Resources},
+		{spec.Child("ha", "initContainerResources"), cr.Spec.HA.InitContainerResources},
+		{spec.Child("ha", "resources"), cr.Spec.HA.Resources},
+		{spec.Child("imageUpdater", "resources"), cr.Spec.ImageUpdater.Resources},
+		{spec.Child("redis", "initContainerResources"), cr.Spec.Redis.InitContainerResources},
+		{spec.Child("redis", "resources"), cr.Spec.Redis.Resources},
+		{spec.Child("repo", "initContainerResources"), cr.Spec.Repo.InitContainerResources},
+		{spec.Child("repo", "resources"), cr.Spec.Repo.Resources},
+		{spec.Child("server", "resources"), cr.Spec.Server.Resources},
+	}
+	if cr.Spec.ApplicationSet != nil {
+		resources = append(resources, resourceRequirementsField{spec.Child("applicationSet", "resources"), cr.Spec.ApplicationSet.Resources})
+	}
+	if cr.Spec.SSO != nil && cr.Spec.SSO.Dex != nil {
+		resources = append(resources, resourceRequirementsField{spec.Child("sso", "dex", "initContainerResources"), cr.Spec.SSO.Dex.InitContainerResources})
+		resources = append(resources, resourceRequirementsField{spec.Child("sso", "dex", "resources"), cr.Spec.SSO.Dex.Resources})
+	}
+	if cr.Spec.SSO != nil && cr.Spec.SSO.Keycloak != nil {
+		resources = append(resources, resourceRequirementsField{spec.Child("sso", "keycloak", "resources"), cr.Spec.SSO.Keycloak.Resources})
+	}
+	for _, res := range resources {
+		allErrs = append(allErrs, validateResourceRequirements(res.r, res.path)...)
+	}
+
+	cache := cr.Spec.Controller.ClusterCache
+	durations := []struct {
+		path *field.Path
+		d    *metav1.Duration
+	}{
+		{spec.Child("controller", "appSync"), cr.Spec.Controller.AppSync},
+		{spec.Child("controller", "clusterCache", "resyncDuration"), cache.ResyncDuration},
+		{spec.Child("controller", "clusterCache", "watchResyncDuration"), cache.WatchResyncDuration},
+		{spec.Child("controller", "selfHealTimeout"), cr.Spec.Controller.SelfHealTimeout},
+		{spec.Child("controller", "syncTimeout"), cr.Spec.Controller.SyncTimeout},
+		{spec.Child("imageUpdater", "interval"), cr.Spec.ImageUpdater.Interval},
+		{spec.Child("repo", "autotlsCertificate", "renewBefore"), cr.Spec.Repo.AutoTLSCertificate.RenewBefore},
+		{spec.Child("repo", "autotlsCertificate", "validity"), cr.Spec.Repo.AutoTLSCertificate.Validity},
+		{spec.Child("repo", "cacheExpiration"), cr.Spec.Repo.CacheExpiration},
+		{spec.Child("repo", "gitRetry", "duration"), cr.Spec.Repo.GitRetry.Duration},
+		{spec.Child("repo", "gitRetry", "maxDuration"), cr.Spec.Repo.GitRetry.MaxDuration},
+		{spec.Child("server", "session", "duration"), cr.Spec.Server.Session.Duration},
+		{spec.Child("server", "session", "idTokenExpiration"), cr.Spec.Server.Session.IDTokenExpiration},
+	}
+	for _, dur := range durations {
+		if dur.d != nil && dur.d.Duration < 0 {
+			allErrs = append(allErrs, field.Invalid(dur.path, dur.d.Duration.String(), "must not be negative"))
+		}
+	}
+
+	if d := cr.Spec.Controller.SelfHealTimeout; d != nil && d.Duration >= 0 && d.Duration < time.Second {
+		allErrs = append(allErrs, field.Invalid(spec.Child("controller", "selfHealTimeout"), d.Duration.String(), "must be at least 1s"))
+	}
+	if d := cr.Spec.Controller.SyncTimeout; d != nil && d.Duration > 0 && d.Duration < time.Second {
+		allErrs = append(allErrs, field.Invalid(spec.Child("controller", "syncTimeout"), d.Duration.String(), "must be 0s or at least 1s"))
+	}
+	if d := cr.Spec.Controller.SyncTimeout; d != nil {
+		allErrs = append(allErrs, validateArgoCDVersion(cr, spec.Child("controller", "syncTimeout"), d.Duration.String(), syncTimeoutMinimumVersion)...)
+	}
+
+	retry := cr.Spec.Repo.GitRetry
+	if retry.Duration != nil && retry.MaxDuration != nil && retry.MaxDuration.Duration < retry.Duration.Duration {
+		allErrs = append(allErrs, field.Invalid(spec.Child("repo", "gitRetry", "maxDuration"), retry.MaxDuration.Duration.String(),
+			fmt.Sprintf("must not be less than spec.repo.gitRetry.duration (%s)", retry.Duration.Duration)))
+	}
+
+	if interval := cr.Spec.ReconcileInterval; interval != nil && interval.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(spec.Child("reconcileInterval"), interval.Duration.String(), "must be positive"))
+	}
+
+	if replicas := cr.Spec.Repo.Replicas; replicas != nil {
+		if *replicas < 0 {
+			allErrs = append(allErrs, field.Invalid(spec.Child("repo", "replicas"), *replicas, "must not be negative"))
+		} else if cr.Spec.HA.Enabled && *replicas < common.ArgoCDDefaultRepoServerHAReplicas {
+			allErrs = append(allErrs, field.Invalid(spec.Child("repo", "replicas"), *replicas,
+				fmt.Sprintf("must be at least %d when spec.ha.enabled is true", common.ArgoCDDefaultRepoServerHAReplicas)))
+		}
+	}
+
+	redisConfig := cr.Spec.HA.RedisConfig
+	if n := redisConfig.AnnounceServices; n != 0 && n < common.ArgoCDDefaultRedisHAReplicas {
+		allErrs = append(allErrs, field.Invalid(spec.Child("ha", "redisConfig", "announceServices"), n,
+			fmt.Sprintf("must be at least %d", common.ArgoCDDefaultRedisHAReplicas)))
+	}
+	if redisConfig.DownAfterMilliseconds < 0 {
+		allErrs = append(allErrs, field.Invalid(spec.Child("ha", "redisConfig", "downAfterMilliseconds"), redisConfig.DownAfterMilliseconds, "must not be negative"))
+	}
+	if redisConfig.FailoverTimeout < 0 {
+		allErrs = append(allErrs, field.Invalid(spec.Child("ha", "redisConfig", "failoverTimeout"), redisConfig.FailoverTimeout, "must not be negative"))
+	}
+
+	haproxy := cr.Spec.HA.HAProxy
+	for _, t := range []struct {
+		name string
+		d    *metav1.Duration
+	}{
+		{"checkInterval", haproxy.CheckInterval},
+		{"timeoutCheck", haproxy.TimeoutCheck},
+		{"timeoutClient", haproxy.TimeoutClient},
+		{"timeoutConnect", haproxy.TimeoutConnect},
+		{"timeoutServer", haproxy.TimeoutServer},
+	} {
+		if t.d != nil && t.d.Duration < time.Millisecond {
+			allErrs = append(allErrs, field.Invalid(spec.Child("ha", "haproxy", t.name), t.d.Duration.String(), "must be at least 1ms"))
+		}
+	}
+
+	cert := cr.Spec.Repo.AutoTLSCertificate
+	if cert.RenewBefore != nil && cert.RenewBefore.Duration >= getRepoServerTLSValidity(cr) {
+		allErrs = append(allErrs, field.Invalid(spec.Child("repo", "autotlsCertificate", "renewBefore"), cert.RenewBefore.Duration.String(),
+			fmt.Sprintf("must be less than the certificate validity (%s)", getRepoServerTLSValidity(cr))))
+	}
+
+	if ext := cr.Spec.Grafana.External; ext != nil {
+		if u, err := url.Parse(ext.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			allErrs = append(allErrs, field.Invalid(spec.Child("grafana", "external", "url"), ext.URL, "must be an http or https URL"))
+		}
+	}
+
+	if remote := cr.Spec.Redis.Remote; !isManagedRedisEnabled(cr) {
+		if remote == "" {
+			allErrs = append(allErrs, field.Required(spec.Child("redis", "remote"), "a remote Redis is required when spec.redis.enabled is false"))
+		} else if _, _, err := net.SplitHostPort(remote); err != nil {
+			allErrs = append(allErrs, field.Invalid(spec.Child("redis", "remote"), remote, "must be in the host:port form"))
+		}
+		if cr.Spec.HA.Enabled {
+			allErrs = append(allErrs, field.Forbidden(spec.Child("ha", "enabled"), "must not be set when spec.redis.enabled is false"))
+		}
+	} else if remote != "" {
+		allErrs = append(allErrs, field.Forbidden(spec.Child("redis", "remote"), "must only be set when spec.redis.enabled is false"))
+	}
+
+	metrics := cr.Spec.Controller.Metrics
+	if metrics.Port < 0 || metrics.Port > 65535 {
+		allErrs = append(allErrs, field.Invalid(spec.Child("controller", "metrics", "port"), metrics.Port, "must be between 1 and 65535"))
+	} else if metrics.TLS != nil && metrics.Port == common.ArgoCDDefaultApplicationControllerMetricsPort {
+		allErrs = append(allErrs, field.Invalid(spec.Child("controller", "metrics", "port"), metrics.Port,
+			fmt.Sprintf("must not be %d when TLS is enabled, the port is used by the application controller", common.ArgoCDDefaultApplicationControllerMetricsPort)))
+	}
+	if metrics.TLS != nil && metrics.TLS.SecretName == "" {
+		allErrs = append(allErrs, field.Required(spec.Child("controller", "metrics", "tls", "secretName"), "the name of the TLS Secret is required"))
+	}
+
+	algorithms := []string{"legacy", "round-robin", "consistent-hashing"}
+	if a := cr.Spec.Controller.Sharding.Algorithm; a != "" && !containsString(algorithms, a) {
+		allErrs = append(allErrs, field.NotSupported(spec.Child("controller", "sharding", "algorithm"), a, algorithms))
+	} else if a != "" && a != "legacy" {
+		// The legacy algorithm is the one used by the versions of Argo CD without the option.
+		allErrs = append(allErrs, validateArgoCDVersion(cr, spec.Child("controller", "sharding", "algorithm"), a, getShardingAlgorithmMinimumVersion(a))...)
+	}
+
+	if autosize := cr.Spec.Controller.ProcessorsAutosize; autosize != nil {
+		bounds := []struct {
+			path     *field.Path
+			max, min int32
+		}{
+			{spec.Child("controller", "processorsAutosize", "maxOperation"), autosize.MaxOperation, cr.Spec.Controller.Processors.Operation},
+			{spec.Child("controller", "processorsAutosize", "maxStatus"), autosize.MaxStatus, cr.Spec.Controller.Processors.Status},
+		}
+		for _, b := range bounds {
+			if b.max < 0 {
+				allErrs = append(allErrs, field.Invalid(b.path, b.max, "must not be negative"))
+			} else if b.max > 0 && b.max < b.min {
+				allErrs = append(allErrs, field.Invalid(b.path, b.max, fmt.Sprintf("must not be less than the %d processors configured in spec.controller.processors", b.min)))
+			}
+		}
+	}
+
+	autoTLS := []string{common.ArgoCDRepoServerAutoTLSOpenShift, common.ArgoCDRepoServerAutoTLSOperator}
+	if p := cr.Spec.Repo.AutoTLS; p != "" && !containsString(autoTLS, p) {
+		allErrs = append(allErrs, field.NotSupported(spec.Child("repo", "autotls"), p, autoTLS))
+	}
+
+	logLevels := []string{"debug", "info", "warn", "error"}
+	if cr.Spec.ApplicationSet != nil && cr.Spec.ApplicationSet.LogLevel != "" && !containsString(logLevels, cr.Spec.ApplicationSet.LogLevel) {
+		allErrs = append(allErrs, field.NotSupported(spec.Child("applicationSet", "logLevel"), cr.Spec.ApplicationSet.LogLevel, logLevels))
+	}
+
+	if cr.Spec.ApplicationSet != nil {
+		for i, namespace := range cr.Spec.ApplicationSet.SourceNamespaces {
+			if namespace != "" && !isManagedNamespaceAllowed(cr.Namespace, namespace) {
+				allErrs = append(allErrs, field.Forbidden(spec.Child("applicationSet", "sourceNamespaces").Index(i),
+					fmt.Sprintf("namespace %s is not allowed to be managed by the ArgoCD in namespace %s", namespace, cr.Namespace)))
+			}
+		}
+	}
+
+	if len(getApplicationSetSourceNamespaces(cr)) > 0 && !isApplicationSetNamespacesSupported(cr) {
+		allErrs = append(allErrs, field.Invalid(spec.Child("applicationSet", "sourceNamespaces"), cr.Spec.ApplicationSet.SourceNamespaces,
+			fmt.Sprintf("requires the ApplicationSet controller of Argo CD %s or later, the image in use is %s", applicationSetNamespacesMinimumVersion, getApplicationSetContainerImage(cr))))
+	}
+
+	if p := cr.Spec.Profile; p != "" && !containsString(argoCDProfiles, p) {
+		allErrs = append(allErrs, field.NotSupported(spec.Child("profile"), p, argoCDProfiles))
+	}
+
+	strategies := []string{common.ArgoCDUpgradeStrategyAll, common.ArgoCDUpgradeStrategyStaged}
+	if s := cr.Spec.UpgradeStrategy.Type; s != "" && !containsString(strategies, s) {
+		allErrs = append(allErrs, field.NotSupported(spec.Child("upgradeStrategy", "type"), s, strategies))
+	}
+
+	serviceTypes := []string{
+		string(corev1.ServiceTypeClusterIP),
+		string(corev1.ServiceTypeNodePort),
+		string(corev1.ServiceTypeLoadBalancer),
+		string(corev1.ServiceTypeExternalName),
+	}
+	if t := string(cr.Spec.Server.Service.Type); t != "" && !containsString(serviceTypes, t) {
+		allErrs = append(allErrs, field.NotSupported(spec.Child("server", "service", "type"), t, serviceTypes))
+	}
+
+	if v := cr.Spec.Server.TLS.MinVersion; v != "" && !containsString(tlsMinVersions, v) {
+		allErrs = append(allErrs, field.NotSupported(spec.Child("server", "tls", "minVersion"), v, tlsMinVersions))
+	}
+
+	if ciphers := cr.Spec.Server.TLS.Ciphers; len(ciphers) > 0 && cr.Spec.Server.TLS.MinVersion == "1.3" {
+		allErrs = append(allErrs, field.Forbidden(spec.Child("server", "tls", "ciphers"),
+			"the cipher suites are not configurable with TLS 1.3"))
+	} else {
+		supported := getConfigurableTLSCipherSuites()
+		for i, c := range ciphers {
+			if !containsString(supported, c) {
+				allErrs = append(allErrs, field.NotSupported(spec.Child("server", "tls", "ciphers").Index(i), c, supported))
+			}
+		}
+	}
+
+	if m := cr.Spec.ComplianceMode; m != "" && !containsString(argoCDComplianceModes, m) {
+		allErrs = append(allErrs, field.NotSupported(spec.Child("complianceMode"), m, argoCDComplianceModes))
+	}
+
+	deletionPolicies := []string{
+		common.ArgoCDDeletionPolicyOrphan,
+		common.ArgoCDDeletionPolicyCascade,
+		common.ArgoCDDeletionPolicyStripFinalizers,
+	}
+	if p := cr.Spec.DeletionPolicy; p != "" && !containsString(deletionPolicies, p) {
+		allErrs = append(allErrs, field.NotSupported(spec.Child("deletionPolicy"), p, deletionPolicies))
+	}
+
+	cliConfigKinds := []string{common.ArgoCDCLIConfigKindConfigMap, common.ArgoCDCLIConfigKindSecret}
+	if k := cr.Spec.CLIConfig.Kind; k != "" && !containsString(cliConfigKinds, k) {
+		allErrs = append(allErrs, field.NotSupported(spec.Child("cliConfig", "kind"), k, cliConfigKinds))
+	}
+	if ns := cr.Spec.CLIConfig.Namespace; ns != "" {
+		for _, msg := range utilvalidation.IsDNS1123Label(ns) {
+			allErrs = append(allErrs, field.Invalid(spec.Child("cliConfig", "namespace"), ns, msg))
+		}
+	}
+
+	ipFamilies := []string{string(corev1.IPv4Protocol), string(corev1.IPv6Protocol)}
+	for i, f := range cr.Spec.IPFamilies {
+		if !containsString(ipFamilies, f) {
+			allErrs = append(allErrs, field.NotSupported(spec.Child("ipFamilies").Index(i), f, ipFamilies))
+		} else if containsString(cr.Spec.IPFamilies[:i], f) {
+			allErrs = append(allErrs, field.Duplicate(spec.Child("ipFamilies").Index(i), f))
+		}
+	}
+	ipFamilyPolicies := []string{
+		common.ArgoCDIPFamilyPolicySingleStack,
+		common.ArgoCDIPFamilyPolicyPreferDualStack,
+		common.ArgoCDIPFamilyPolicyRequireDualStack,
+	}
+	if p := cr.Spec.IPFamilyPolicy; p != "" && !containsString(ipFamilyPolicies, p) {
+		allErrs = append(allErrs, field.NotSupported(spec.Child("ipFamilyPolicy"), p, ipFamilyPolicies))
+	}
+	if cr.Spec.IPFamilyPolicy == common.ArgoCDIPFamilyPolicySingleStack && len(cr.Spec.IPFamilies) > 1 {
+		allErrs = append(allErrs, field.Invalid(spec.Child("ipFamilies"), cr.Spec.IPFamilies, "must hold a single IP family when the IP family policy is SingleStack"))
+	}
+
+	redisPath := spec.Child("redis", "config")
+	if m := cr.Spec.Redis.Config.MaxMemory; m != "" && !redisMemoryPattern.MatchString(m) {
+		allErrs = append(allErrs, field.Invalid(redisPath.Child("maxMemory"), m, "must be a number of bytes, optionally followed by a unit such as kb, mb or gb"))
+	}
+	// The noeviction policy is not supported, as Redis would reject the writes of the Argo CD cache at the memory limit.
+	evictionPolicies := []string{
+		"allkeys-lru", "allkeys-lfu", "allkeys-random", "volatile-lru", "volatile-lfu", "volatile-random", "volatile-ttl",
+	}
+	if p := cr.Spec.Redis.Config.MaxMemoryPolicy; p != "" && !containsString(evictionPolicies, p) {
+		allErrs = append(allErrs, field.NotSupported(redisPath.Child("maxMemoryPolicy"), p, evictionPolicies))
+	}
+	for i, save := range cr.Spec.Redis.Config.Save {
+		if save.Seconds < 1 {
+			allErrs = append(allErrs, field.Invalid(redisPath.Child("save").Index(i).Child("seconds"), save.Seconds, "must be greater than 0"))
+		}
+		if save.Changes < 1 {
+			allErrs = append(allErrs, field.Invalid(redisPath.Child("save").Index(i).Child("changes"), save.Changes, "must be greater than 0"))
+		}
+	}
+
+	if isExternalDex(cr) {
+		path := spec.Child("sso", "dex", "external")
+		ext := cr.Spec.SSO.Dex.External
+		if u, err := url.Parse(ext.IssuerURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			allErrs = append(allErrs, field.Invalid(path.Child("issuerURL"), ext.IssuerURL, "must be an http or https URL"))
+		}
+		if ext.ClientSecret == "" {
+			allErrs = append(allErrs, field.Required(path.Child("clientSecret"), "the name of the client Secret is required"))
+		}
+		if cr.Spec.SSO.Provider == argoprojv1a1.SSOProviderTypeKeycloak {
+			allErrs = append(allErrs, field.Forbidden(path, "must not be set with the keycloak provider"))
+		}
+		if cr.Spec.OIDCConfig != "" {
+			allErrs = append(allErrs, field.Forbidden(spec.Child("oidcConfig"), "must not be set with an external dex"))
+		}
+	}
+
+	providers := []string{string(argoprojv1a1.SSOProviderTypeDex), string(argoprojv1a1.SSOProviderTypeKeycloak)}
+	if cr.Spec.SSO != nil && cr.Spec.SSO.Provider != "" && !containsString(providers, string(cr.Spec.SSO.Provider)) {
+		allErrs = append(allErrs, field.NotSupported(spec.Child("sso", "provider"), cr.Spec.SSO.Provider, providers))
+	}
+
+	if cr.Spec.SSO != nil && cr.Spec.SSO.Provider == argoprojv1a1.SSOProviderTypeDex {
+		if !reflect.DeepEqual(cr.Spec.Dex, argoprojv1a1.ArgoCDDexSpec{}) {
+			allErrs = append(allErrs, field.Forbidden(spec.Child("dex"), "must not be set with the dex SSO provider, use spec.sso.dex instead"))
+		}
+	} else if cr.Spec.SSO != nil && cr.Spec.SSO.Dex != nil && !reflect.DeepEqual(cr.Spec.SSO.Dex.ArgoCDDexSpec, argoprojv1a1.ArgoCDDexSpec{}) {
+		allErrs = append(allErrs, field.Forbidden(spec.Child("sso", "dex"), "the options of the managed dex require the dex SSO provider"))
+	}
+
+	if cr.Spec.SSO != nil && cr.Spec.SSO.Dex != nil && cr.Spec.SSO.Dex.Groups != nil {
+		path := spec.Child("sso", "dex", "groups")
+		if !isManagedDexEnabled(cr) {
+			allErrs = append(allErrs, field.Forbidden(path, "requires the managed dex of the dex SSO provider"))
+		}
+		for i, binding := range cr.Spec.SSO.Dex.Groups.Bindings {
+			if !isValidDexGroupBindingName(binding.Group) {
+				allErrs = append(allErrs, field.Invalid(path.Child("bindings").Index(i).Child("group"), binding.Group, "must be a non-empty group name without commas or line breaks"))
+			}
+			if !isValidDexGroupBindingName(binding.Role) {
+				allErrs = append(allErrs, field.Invalid(path.Child("bindings").Index(i).Child("role"), binding.Role, "must be a non-empty role name without commas or line breaks"))
+			}
+		}
+	}
+
+	extraRules := spec.Child("extraRoleRules")
+	allErrs = append(allErrs, validatePolicyRules(cr.Spec.ExtraRoleRules.ApplicationController, extraRules.Child("applicationController"))...)
+	allErrs = append(allErrs, validatePolicyRules(cr.Spec.ExtraRoleRules.ApplicationSet, extraRules.Child("applicationSet"))...)
+	allErrs = append(allErrs, validatePolicyRules(cr.Spec.ExtraRoleRules.Dex, extraRules.Child("dex"))...)
+	allErrs = append(allErrs, validatePolicyRules(cr.Spec.ExtraRoleRules.HA, extraRules.Child("ha"))...)
+	allErrs = append(allErrs, validatePolicyRules(cr.Spec.ExtraRoleRules.Server, extraRules.Child("server"))...)
+
+	for i, ref := range cr.Spec.ResourceHealthChecksFrom {
+		if ref.Name == "" {
+			allErrs = append(allErrs, field.Required(spec.Child("resourceHealthChecksFrom").Index(i).Child("name"), "the name of the ConfigMap is required"))
+		}
+	}
+
+	for i, ref := range cr.Spec.TLS.CertsFrom {
+		if ref.Name == "" {
+			allErrs = append(allErrs, field.Required(spec.Child("tls", "certsFrom").Index(i).Child("name"), "the name of the ConfigMap is required"))
+		}
+	}
+
+	for i, san := range cr.Spec.TLS.ExtraSANs {
+		if !isValidCertificateSAN(san) {
+			allErrs = append(allErrs, field.Invalid(spec.Child("tls", "extraSANs").Index(i), san,
+				"must be an IP address or a lowercase DNS name, optionally starting with a *. wildcard"))
+		}
+	}
+
+	secretKeys := map[string]bool{}
+	for i, ref := range cr.Spec.SecretKeys {
+		path := spec.Child("secretKeys").Index(i)
+		switch {
+		case ref.Key == "":
+			allErrs = append(allErrs, field.Required(path.Child("key"), "the key of the Argo CD Secret is required"))
+		case secretKeys[ref.Key]:
+			allErrs = append(allErrs, field.Duplicate(path.Child("key"), ref.Key))
+		}
+		secretKeys[ref.Key] = true
+		if ref.SecretName == "" {
+			allErrs = append(allErrs, field.Required(path.Child("secretName"), "the name of the Secret is required"))
+		}
+	}
+
+	if cr.Spec.Repo.VaultPlugin.Enabled {
+		allErrs = append(allErrs, validateArgoCDVersion(cr, spec.Child("repo", "vaultPlugin", "enabled"), true, vaultPluginMinimumVersion)...)
+	}
+
+	claimed := map[string]bool{}
+	for i, claim := range cr.Spec.Repo.VolumeClaims {
+		path := spec.Child("repo", "volumeClaims").Index(i)
+		switch {
+		case !containsString(repoClaimableVolumes, claim.Volume):
+			allErrs = append(allErrs, field.NotSupported(path.Child("volume"), claim.Volume, repoClaimableVolumes))
+		case claimed[claim.Volume]:
+			allErrs = append(allErrs, field.Duplicate(path.Child("volume"), claim.Volume))
+		case claim.Volume == repoVolumeCustomTools && !cr.Spec.Repo.VaultPlugin.Enabled:
+			allErrs = append(allErrs, field.Invalid(path.Child("volume"), claim.Volume, "requires spec.repo.vaultPlugin.enabled to be true"))
+		}
+		claimed[claim.Volume] = true
+		if claim.ClaimName == "" {
+			allErrs = append(allErrs, field.Required(path.Child("claimName"), "the name of the PersistentVolumeClaim is required"))
+		}
+	}
+	if u := cr.Spec.Repo.RunAsUser; u != nil && *u < 0 {
+		allErrs = append(allErrs, field.Invalid(spec.Child("repo", "runAsUser"), *u, "must not be negative"))
+	}
+	if g := cr.Spec.Repo.FSGroup; g != nil && *g < 0 {
+		allErrs = append(allErrs, field.Invalid(spec.Child("repo", "fsGroup"), *g, "must not be negative"))
+	}
+
+	extensions := map[string]bool{}
+	for i, ext := range cr.Spec.Server.Extensions {
+		path := spec.Child("server", "extensions").Index(i)
+		switch {
+		case ext.Name == "":
+			allErrs = append(allErrs, field.Required(path.Child("name"), "the name of the extension is required"))
+		case extensions[ext.Name]:
+			allErrs = append(allErrs, field.Duplicate(path.Child("name"), ext.Name))
+		default:
+			// The name of the extension is used in the name of its installer init container.
+			for _, msg := range utilvalidation.IsDNS1123Label(fmt.Sprintf("extension-%s", ext.Name)) {
+				allErrs = append(allErrs, field.Invalid(path.Child("name"), ext.Name, msg))
+			}
+		}
+		extensions[ext.Name] = true
+		if u, err := url.Parse(ext.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			allErrs = append(allErrs, field.Invalid(path.Child("url"), ext.URL, "must be an http or https URL"))
+		}
+		if ext.ChecksumURL != "" {
+			if u, err := url.Parse(ext.ChecksumURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				allErrs = append(allErrs, field.Invalid(path.Child("checksumURL"), ext.ChecksumURL, "must be an http or https URL"))
+			}
+		}
+	}
+
+	ingresses := map[string]bool{}
+	for i, ing := range cr.Spec.Server.Ingresses {
+		path := spec.Child("server", "ingresses").Index(i)
+		switch {
+		case ing.Name == "":
+			allErrs = append(allErrs, field.Required(path.Child("name"), "the name of the ingress is required"))
+		case ingresses[ing.Name]:
+			allErrs = append(allErrs, field.Duplicate(path.Child("name"), ing.Name))
+		case ing.Name == "grpc":
+			allErrs = append(allErrs, field.Invalid(path.Child("name"), ing.Name, "is reserved for the gRPC ingress of the server"))
+		default:
+			for _, msg := range utilvalidation.IsDNS1123Subdomain(fmt.Sprintf("%s-server-%s", cr.Name, ing.Name)) {
+				allErrs = append(allErrs, field.Invalid(path.Child("name"), ing.Name, msg))
+			}
+		}
+		ingresses[ing.Name] = true
+		allErrs = append(allErrs, validateArgoServerIngressBackend(cr, ing.Annotations, path.Child("annotations"))...)
+	}
+
+	if cr.Spec.Server.Ingress.Enabled {
+		allErrs = append(allErrs, validateArgoServerIngressBackend(cr, cr.Spec.Server.Ingress.Annotations, spec.Child("server", "ingress", "annotations"))...)
+	}
+
+	if cr.Spec.Server.GRPC.Ingress.Enabled {
+		allErrs = append(allErrs, validateArgoServerIngressBackend(cr, cr.Spec.Server.GRPC.Ingress.Annotations, spec.Child("server", "grpc", "ingress", "annotations"))...)
+	}
+
+	if externalDNS := cr.Spec.Server.ExternalDNS; externalDNS.Enabled {
+		path := spec.Child("server", "externalDNS")
+		if cr.Spec.Server.Host == "" && len(externalDNS.Hostnames) == 0 {
+			allErrs = append(allErrs, field.Required(path.Child("hostnames"), "a hostname is required when spec.server.host is not set"))
+		}
+		for i, name := range externalDNS.Hostnames {
+			if strings.HasPrefix(name, "*.") {
+				for _, msg := range utilvalidation.IsWildcardDNS1123Subdomain(name) {
+					allErrs = append(allErrs, field.Invalid(path.Child("hostnames").Index(i), name, msg))
+				}
+				continue
+			}
+			for _, msg := range utilvalidation.IsDNS1123Subdomain(name) {
+				allErrs = append(allErrs, field.Invalid(path.Child("hostnames").Index(i), name, msg))
+			}
+		}
+		if externalDNS.TTL < 0 {
+			allErrs = append(allErrs, field.Invalid(path.Child("ttl"), externalDNS.TTL, "must not be negative"))
+		}
+		allErrs = append(allErrs, validateArgoServerExternalDNSEndpoints(cr, spec)...)
+	}
+
+	if tls := cr.Spec.Server.Route.TLS; cr.Spec.Server.Route.Enabled && tls != nil {
+		path := spec.Child("server", "route", "tls", "termination")
+		switch {
+		case cr.Spec.Server.Insecure && tls.Termination != routev1.TLSTerminationEdge:
+			allErrs = append(allErrs, field.Invalid(path, tls.Termination, "must be edge when spec.server.insecure is true, the server does not serve TLS"))
+		case !cr.Spec.Server.Insecure && tls.Termination == routev1.TLSTerminationEdge:
+			allErrs = append(allErrs, field.Invalid(path, tls.Termination, "requires spec.server.insecure to be true, the server redirects plain HTTP back to HTTPS"))
+		}
+	}
+
+	for key, value := range cr.Spec.Prometheus.Labels {
+		path := spec.Child("prometheus", "labels").Key(key)
+		if key == common.ArgoCDKeyName {
+			// The name label is used by the ServiceMonitors to select the metrics Services.
+			allErrs = append(allErrs, field.Invalid(path, key, "must not override the name label of the metrics Services"))
+			continue
+		}
+		for _, msg := range utilvalidation.IsQualifiedName(key) {
+			allErrs = append(allErrs, field.Invalid(path, key, msg))
+		}
+		for _, msg := range utilvalidation.IsValidLabelValue(value) {
+			allErrs = append(allErrs, field.Invalid(path, value, msg))
+		}
+	}
+
+	if cr.Spec.Server.ProxyExtension.Enabled {
+		path := spec.Child("server", "proxyExtension", "enabled")
+		allErrs = append(allErrs, validateArgoCDVersion(cr, path, true, proxyExtensionMinimumVersion)...)
+	}
+
+	if config := cr.Spec.Server.ProxyExtension.Config; config != "" {
+		path := spec.Child("server", "proxyExtension", "config")
+		if err := yaml.Unmarshal([]byte(config), &map[string]interface{}{}); err != nil {
+			allErrs = append(allErrs, field.Invalid(path, config, fmt.Sprintf("must be valid YAML: %v", err)))
+		} else if !cr.Spec.Server.ProxyExtension.Enabled {
+			allErrs = append(allErrs, field.Invalid(path, config, "requires spec.server.proxyExtension.enabled to be true"))
+		}
+	}
+
+	if dr := cr.Spec.DisasterRecovery; dr != nil {
+		path := spec.Child("disasterRecovery")
+		if dr.Namespace == "" {
+			allErrs = append(allErrs, field.Required(path.Child("namespace"), "the disaster recovery namespace is required"))
+		} else if dr.Namespace == cr.Namespace {
+			allErrs = append(allErrs, field.Invalid(path.Child("namespace"), dr.Namespace, "must not be the namespace of the ArgoCD"))
+		}
+		if dr.KeySecretNamespace == "" {
+			allErrs = append(allErrs, field.Required(path.Child("keySecretNamespace"), "the namespace of the key secret is required"))
+		} else if dr.KeySecretNamespace == cr.Namespace || dr.KeySecretNamespace == dr.Namespace {
+			allErrs = append(allErrs, field.Invalid(path.Child("keySecretNamespace"), dr.KeySecretNamespace, "must be neither the namespace of the ArgoCD nor the disaster recovery namespace"))
+		}
+		modes := []string{common.ArgoCDDisasterRecoveryModeMirror, common.ArgoCDDisasterRecoveryModeRestore}
+		if dr.Mode != "" && !containsString(modes, dr.Mode) {
+			allErrs = append(allErrs, field.NotSupported(path.Child("mode"), dr.Mode, modes))
+		}
+	}
+
+	for i, host := range cr.Spec.KnownHostsAutoScan.Hosts {
+		if !isValidKnownHostsScanHost(host) {
+			allErrs = append(allErrs, field.Invalid(spec.Child("knownHostsAutoScan", "hosts").Index(i), host, "must be a hostname or IP address, optionally followed by a port"))
+		}
+	}
+
+	if selector := cr.Spec.ClusterDiscovery.SecretSelector; selector != nil {
+		if _, err := metav1.LabelSelectorAsSelector(selector); err != nil {
+			allErrs = append(allErrs, field.Invalid(spec.Child("clusterDiscovery", "secretSelector"), selector, err.Error()))
+		}
+	}
+
+	return allErrs
+}
+
+// validateArgoServerIngressBackend will check that the backend protocol in the given annotations of an Ingress of the
+// Argo CD Server matches the insecure flag of the server.
+func validateArgoServerIngressBackend(cr *argoprojv1a1.ArgoCD, annotations map[string]string, path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	protocol, ok := annotations[common.ArgoCDKeyIngressBackendProtocol]
+	if !ok {
+		return allErrs
+	}
+	path = path.Key(common.ArgoCDKeyIngressBackendProtocol)
+	switch {
+	case cr.Spec.Server.Insecure && (protocol == "HTTPS" || protocol == "GRPCS"):
+		allErrs = append(allErrs, field.Invalid(path, protocol, "must not use TLS when spec.server.insecure is true"))
+	case !cr.Spec.Server.Insecure && protocol == "HTTP":
+		allErrs = append(allErrs, field.Invalid(path, protocol, "requires spec.server.insecure to be true, the server redirects plain HTTP back to HTTPS"))
+	case !cr.Spec.Server.Insecure && protocol == "GRPC":
+		allErrs = append(allErrs, field.Invalid(path, protocol, "requires spec.server.insecure to be true, the server only accepts gRPC over TLS"))
+	}
+	return allErrs
+}
+
+// validateArgoServerExternalDNSEndpoints will check that each hostname is published by external-dns for a single
+// endpoint of the Argo CD Server. external-dns gives a hostname a single owner, so a hostname annotated on several
+// endpoints would flap between their targets.
+func validateArgoServerExternalDNSEndpoints(cr *argoprojv1a1.ArgoCD, spec *field.Path) field.ErrorList {
+	type endpoint struct {
+		path      *field.Path
+		hostnames []string
+	}
+	endpoints := []endpoint{}
+	if cr.Spec.Server.Ingress.Enabled {
+		endpoints = append(endpoints, endpoint{spec.Child("server", "ingress", "enabled"), getArgoServerExternalDNSHostnames(cr, cr.Spec.Server.Host)})
+	}
+	if cr.Spec.Server.GRPC.Ingress.Enabled {
+		endpoints = append(endpoints, endpoint{spec.Child("server", "grpc", "ingress", "enabled"), []string{cr.Spec.Server.GRPC.Host}})
+	}
+	if cr.Spec.Server.Route.Enabled {
+		endpoints = append(endpoints, endpoint{spec.Child("server", "route", "enabled"), getArgoServerExternalDNSHostnames(cr, cr.Spec.Server.Host)})
+	}
+	if getArgoServerServiceType(cr) == corev1.ServiceTypeLoadBalancer {
+		endpoints = append(endpoints, endpoint{spec.Child("server", "service", "type"), getArgoServerExternalDNSHostnames(cr, cr.Spec.Server.Host)})
+	}
+	for i, ing := range cr.Spec.Server.Ingresses {
+		host := ing.Host
+		if host == "" {
+			host = cr.Spec.Server.Host
+		}
+		endpoints = append(endpoints, endpoint{spec.Child("server", "ingresses").Index(i), getArgoServerExternalDNSHostnames(cr, host)})
+	}
+
+	allErrs := field.ErrorList{}
+	published := make(map[string]*field.Path)
+	for _, e := range endpoints {
+		for _, name := range e.hostnames {
+			if name == "" {
+				continue
+			}
+			if first, ok := published[name]; ok && first != e.path {
+				allErrs = append(allErrs, field.Forbidden(e.path, fmt.Sprintf(
+					"external-dns would publish %s for both %s and this endpoint, enable a single endpoint of the server or disable spec.server.externalDNS", name, first)))
+				break
+			}
+			published[name] = e.path
+		}
+	}
+	return allErrs
+}
+
+// validateArgoCDVersion will return an error for the given option when the given ArgoCD runs a version of Argo CD
+// older than the given minimum version, which does not support the option.
+func validateArgoCDVersion(cr *argoprojv1a1.ArgoCD, path *field.Path, value interface{}, minimum string) field.ErrorList {
+	if isArgoCDVersionAtLeast(cr, minimum) {
+		return nil
+	}
+	v := argoutil.GetArgoCDVersion(getArgoContainerImage(cr))
+	return field.ErrorList{field.Invalid(path, value, fmt.Sprintf("requires Argo CD %s or later, the version in use is v%s", minimum, v))}
+}
+
+// validatePolicyRules will check that the given policy rules can be added to both a Role and a ClusterRole.
+func validatePolicyRules(rules []rbacv1.PolicyRule, path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	for i, rule := range rules {
+		if len(rule.Verbs) == 0 {
+			allErrs = append(allErrs, field.Required(path.Index(i).Child("verbs"), "at least one verb is required"))
+		}
+		if len(rule.Resources) == 0 {
+			allErrs = append(allErrs, field.Required(path.Index(i).Child("resources"), "at least one resource is required"))
+		}
+		if len(rule.NonResourceURLs) > 0 {
+			allErrs = append(allErrs, field.Forbidden(path.Index(i).Child("nonResourceURLs"), "must not be set in the rules of a Role"))
+		}
+	}
+	return allErrs
+}
+
+// validateResourceRequirements will check that none of the given resource requests exceed the corresponding limit.
+func validateResourceRequirements(r *corev1.ResourceRequirements, path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if r == nil {
+		return allErrs
+	}
+	names := make([]string, 0, len(r.Requests))
+	for name := range r.Requests {
+		names = append(names, string(name))
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		request := r.Requests[corev1.ResourceName(name)]
+		limit, ok := r.Limits[corev1.ResourceName(name)]
+		if ok && request.Cmp(limit) > 0 {
+			allErrs = append(allErrs, field.Invalid(path.Child("requests").Key(name), request.String(),
+				fmt.Sprintf("must be less than or equal to %s limit of %s", name, limit.String())))
+		}
+	}
+	return allErrs
+}
+
+// isValidKnownHostsScanHost will return true when the given host is a hostname or IP address, optionally followed by
+// a port, which can be passed to ssh-keyscan.
+func isValidKnownHostsScanHost(host string) bool {
+	if h, p, err := net.SplitHostPort(host); err == nil {
+		port, err := strconv.Atoi(p)
+		if err != nil || len(utilvalidation.IsValidPortNum(port)) > 0 {
+			return false
+		}
+		host = h
+	}
+	return net.ParseIP(host) != nil || len(utilvalidation.IsDNS1123Subdomain(host)) == 0
+}
+
+// isValidCertificateSAN will return true when the given SAN of a certificate is an IP address or a DNS name, where
+// the DNS name may start with a wildcard label.
+func isValidCertificateSAN(san string) bool {
+	if net.ParseIP(san) != nil {
+		return true
+	}
+	return len(utilvalidation.IsWildcardDNS1123Subdomain(san)) == 0 || len(utilvalidation.IsDNS1123Subdomain(san)) == 0
+}
+
+// reconcileSpecValidation will validate the spec of the given ArgoCD and update the SpecValid condition. It returns
+// false when the spec is invalid and the resources of the ArgoCD should not be reconciled.
+func (r *ReconcileArgoCD) reconcileSpecValidation(cr *argoprojv1a1.ArgoCD) (bool, error) {
+	condition := argoprojv1a1.ArgoCDCondition{
+		Type:   argoprojv1a1.ArgoCDConditionTypeSpecValid,
+		Status: corev1.ConditionTrue,
+		Reason: specValidReasonValid,
+	}
+
+	errs := validateArgoCDSpec(cr)
+	if len(errs) > 0 {
+		condition.Status = corev1.ConditionFalse
+		condition.Reason = specValidReasonInvalid
+		condition.Message = errs.ToAggregate().Error()
+		log.Info(fmt.Sprintf("ArgoCD %s/%s has an invalid spec: %s", cr.Namespace, cr.Name, condition.Message))
+	}
+
+	if setArgoCDCondition(cr, condition) {
+		if err := r.updateArgoCDStatus(cr); err != nil {
+			return false, err
+		}
+	}
+	return len(errs) == 0, nil
+}
+
+// getConfigurableTLSCipherSuites will return the IANA names of the secure TLS cipher suites that can be configured for
+// the Argo CD Server. The TLS 1.3 cipher suites are not configurable.
+func getConfigurableTLSCipherSuites() []string {
+	var names []string
+	for _, suite := range tls.CipherSuites() {
+		for _, v := range suite.SupportedVersions {
+			if v < tls.VersionTLS13 {
+				names = append(names, suite.Name)
+				break
+			}
+		}
+	}
+	return names
+}
@@ -0,0 +1,614 @@
+// Copyright 2019 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocd
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	resourcev1 "k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+
+	argoprojv1alpha1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
+	"github.com/argoproj-labs/argocd-operator/pkg/controller/argoutil"
+	routev1 "github.com/openshift/api/route/v1"
+)
+
+func TestValidateArgoCDSpec(t *testing.T) {
+	validationTests := []struct {
+		name string
+		opts []argoCDOpt
+		want []string
+	}{
+		{
+			name: "default spec",
+		},
+		{
+			name: "requests exceed limits",
+			opts: []argoCDOpt{func(a *argoprojv1alpha1.ArgoCD) {
+				a.Spec.Repo.Resources = &corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceMemory: resourcev1.MustParse("2Gi")},
+					Limits:   corev1.ResourceList{corev1.ResourceMemory: resourcev1.MustParse("1Gi")},
+				}
+			}},
+			want: []string{"spec.repo.resources.requests[memory]"},
+		},
+		{
+			name: "init container requests exceed limits",
+			opts: []argoCDOpt{func(a *argoprojv1alpha1.ArgoCD) {
+				a.Spec.Dex.InitContainerResources = &corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceCPU: resourcev1.MustParse("200m")},
+					Limits:   corev1.ResourceList{corev1.ResourceCPU: resourcev1.MustParse("100m")},
+				}
+			}},
+			want: []string{"spec.dex.initContainerResources.requests[cpu]"},
+		},
+		{
+			name: "negative duration",
+			opts: []argoCDOpt{appSync(-time.Minute)},
+			want: []string{"spec.controller.appSync"},
+		},
+		{
+			name: "git retry max duration below duration",
+			opts: []argoCDOpt{func(a *argoprojv1alpha1.ArgoCD) {
+				a.Spec.Repo.GitRetry.Duration = &metav1.Duration{Duration: time.Minute}
+				a.Spec.Repo.GitRetry.MaxDuration = &metav1.Duration{Duration: time.Second}
+			}},
+			want: []string{"spec.repo.gitRetry.maxDuration"},
+		},
+		{
+			name: "invalid repo volume claims",
+			opts: []argoCDOpt{func(a *argoprojv1alpha1.ArgoCD) {
+				a.Spec.Repo.VolumeClaims = []argoprojv1alpha1.ArgoCDRepoVolumeClaimSpec{
+					{Volume: "gpg-keyring", ClaimName: "gpg"},
+					{Volume: "gpg-keyring", ClaimName: "gpg"},
+					{Volume: "tmp", ClaimName: "tmp"},
+					{Volume: "custom-tools", ClaimName: "tools"},
+					{Volume: "helm-cache"},
+				}
+			}},
+			want: []string{
+				"spec.repo.volumeClaims[1].volume",
+				"spec.repo.volumeClaims[2].volume",
+				"spec.repo.volumeClaims[3].volume",
+				"spec.repo.volumeClaims[4].claimName",
+			},
+		},
+		{
+			name: "zero reconcile interval",
+			opts: []argoCDOpt{func(a *argoprojv1alpha1.ArgoCD) {
+				a.Spec.ReconcileInterval = &metav1.Duration{}
+			}},
+			want: []string{"spec.reconcileInterval"},
+		},
+		{
+			name: "certificate renewed before it is issued",
+			opts: []argoCDOpt{func(a *argoprojv1alpha1.ArgoCD) {
+				a.Spec.Repo.AutoTLSCertificate.Validity = &metav1.Duration{Duration: 24 * time.Hour}
+				a.Spec.Repo.AutoTLSCertificate.RenewBefore = &metav1.Duration{Duration: 48 * time.Hour}
+			}},
+			want: []string{"spec.repo.autotlsCertificate.renewBefore"},
+		},
+		{
+			name: "repo replicas below the HA minimum",
+			opts: []argoCDOpt{func(a *argoprojv1alpha1.ArgoCD) {
+				replicas := int32(1)
+				a.Spec.HA.Enabled = true
+				a.Spec.Repo.Replicas = &replicas
+			}},
+			want: []string{"spec.repo.replicas"},
+		},
+		{
+			name: "invalid external dex",
+			opts: []argoCDOpt{func(a *argoprojv1alpha1.ArgoCD) {
+				a.Spec.OIDCConfig = "name: Okta"
+				a.Spec.SSO = &argoprojv1alpha1.ArgoCDSSOSpec{
+					Dex: &argoprojv1alpha1.ArgoCDSSODexSpec{
+						External: &argoprojv1alpha1.ArgoCDDexExternalSpec{IssuerURL: "dex.example.com"},
+					},
+				}
+			}},
+			want: []string{"spec.sso.dex.external.issuerURL", "spec.sso.dex.external.clientSecret", "spec.oidcConfig"},
+		},
+		{
+			name: "deprecated dex with the dex provider",
+			opts: []argoCDOpt{func(a *argoprojv1alpha1.ArgoCD) {
+				a.Spec.Dex.OpenShiftOAuth = true
+				a.Spec.SSO = &argoprojv1alpha1.ArgoCDSSOSpec{Provider: argoprojv1alpha1.SSOProviderTypeDex}
+			}},
+			want: []string{"spec.dex"},
+		},
+		{
+			name: "managed dex options without the dex provider",
+			opts: []argoCDOpt{func(a *argoprojv1alpha1.ArgoCD) {
+				a.Spec.SSO = &argoprojv1alpha1.ArgoCDSSOSpec{
+					Provider: argoprojv1alpha1.SSOProviderTypeKeycloak,
+					Dex: &argoprojv1alpha1.ArgoCDSSODexSpec{
+						ArgoCDDexSpec: argoprojv1alpha1.ArgoCDDexSpec{OpenShiftOAuth: true},
+					},
+				}
+			}},
+			want: []string{"spec.sso.dex"},
+		},
+		{
+			name: "managed redis disabled without a remote redis",
+			opts: []argoCDOpt{func(a *argoprojv1alpha1.ArgoCD) {
+				a.Spec.Redis.Enabled = boolPtr(false)
+				a.Spec.HA.Enabled = true
+			}},
+			want: []string{"spec.redis.remote", "spec.ha.enabled"},
+		},
+		{
+			name: "remote redis with the managed redis",
+			opts: []argoCDOpt{func(a *argoprojv1alpha1.ArgoCD) {
+				a.Spec.Redis.Remote = "redis.example.com:6379"
+			}},
+			want: []string{"spec.redis.remote"},
+		},
+		{
+			name: "remote redis without a port",
+			opts: []argoCDOpt{func(a *argoprojv1alpha1.ArgoCD) {
+				a.Spec.Redis.Enabled = boolPtr(false)
+				a.Spec.Redis.Remote = "redis.example.com"
+			}},
+			want: []string{"spec.redis.remote"},
+		},
+		{
+			name: "invalid redis ha config",
+			opts: []argoCDOpt{func(a *argoprojv1alpha1.ArgoCD) {
+				a.Spec.HA.RedisConfig = argoprojv1alpha1.ArgoCDHARedisConfigSpec{
+					AnnounceServices:      2,
+					DownAfterMilliseconds: -1,
+					FailoverTimeout:       -1,
+				}
+			}},
+			want: []string{"spec.ha.redisConfig.announceServices", "spec.ha.redisConfig.downAfterMilliseconds", "spec.ha.redisConfig.failoverTimeout"},
+		},
+		{
+			name: "invalid redis haproxy timeouts",
+			opts: []argoCDOpt{func(a *argoprojv1alpha1.ArgoCD) {
+				a.Spec.HA.HAProxy = argoprojv1alpha1.ArgoCDHAProxySpec{
+					CheckInterval: &metav1.Duration{Duration: -time.Second},
+					TimeoutClient: &metav1.Duration{Duration: time.Hour},
+					TimeoutServer: &metav1.Duration{},
+				}
+			}},
+			want: []string{"spec.ha.haproxy.checkInterval", "spec.ha.haproxy.timeoutServer"},
+		},
+		{
+			name: "invalid extra role rules",
+			opts: []argoCDOpt{func(a *argoprojv1alpha1.ArgoCD) {
+				a.Spec.ExtraRoleRules.ApplicationController = []rbacv1.PolicyRule{
+					{APIGroups: []string{"example.com"}, Resources: []string{"widgets"}, Verbs: []string{"*"}},
+					{APIGroups: []string{"example.com"}, Resources: []string{"gadgets"}},
+				}
+				a.Spec.ExtraRoleRules.Dex = []rbacv1.PolicyRule{
+					{APIGroups: []string{""}, Resources: []string{"secrets"}},
+				}
+				a.Spec.ExtraRoleRules.Server = []rbacv1.PolicyRule{
+					{NonResourceURLs: []string{"/metrics"}, Verbs: []string{"get"}},
+				}
+			}},
+			want: []string{"spec.extraRoleRules.applicationController[1].verbs", "spec.extraRoleRules.dex[0].verbs", "spec.extraRoleRules.server[0].resources", "spec.extraRoleRules.server[0].nonResourceURLs"},
+		},
+		{
+			name: "invalid extra SANs",
+			opts: []argoCDOpt{func(a *argoprojv1alpha1.ArgoCD) {
+				a.Spec.TLS.ExtraSANs = []string{"argocd.example.com", "*.apps.example.com", "192.0.2.10", "https://argocd.example.com", "ArgoCD"}
+			}},
+			want: []string{"spec.tls.extraSANs[3]", "spec.tls.extraSANs[4]"},
+		},
+		{
+			name: "invalid controller metrics",
+			opts: []argoCDOpt{func(a *argoprojv1alpha1.ArgoCD) {
+				a.Spec.Controller.Metrics.Port = 8082
+				a.Spec.Controller.Metrics.TLS = &argoprojv1alpha1.ArgoCDApplicationControllerMetricsTLSSpec{}
+			}},
+			want: []string{"spec.controller.metrics.port", "spec.controller.metrics.tls.secretName"},
+		},
+		{
+			name: "resource health checks without a name",
+			opts: []argoCDOpt{func(a *argoprojv1alpha1.ArgoCD) {
+				a.Spec.ResourceHealthChecksFrom = []argoprojv1alpha1.ConfigMapRef{{Name: "team-health"}, {}}
+			}},
+			want: []string{"spec.resourceHealthChecksFrom[1].name"},
+		},
+		{
+			name: "tls certs without a name",
+			opts: []argoCDOpt{func(a *argoprojv1alpha1.ArgoCD) {
+				a.Spec.TLS.CertsFrom = []argoprojv1alpha1.ConfigMapRef{{}}
+			}},
+			want: []string{"spec.tls.certsFrom[0].name"},
+		},
+		{
+			name: "invalid secret keys",
+			opts: []argoCDOpt{func(a *argoprojv1alpha1.ArgoCD) {
+				a.Spec.SecretKeys = []argoprojv1alpha1.ArgoCDSecretKeySpec{
+					{Key: "webhook.github.secret", SecretName: "github-webhook"},
+					{Key: "webhook.github.secret", SecretName: "github-webhook"},
+					{SecretName: "oidc"},
+					{Key: "admin.password"},
+				}
+			}},
+			want: []string{"spec.secretKeys[1].key", "spec.secretKeys[2].key", "spec.secretKeys[3].secretName"},
+		},
+		{
+			name: "external-dns without hostnames",
+			opts: []argoCDOpt{func(a *argoprojv1alpha1.ArgoCD) {
+				a.Spec.Server.ExternalDNS.Enabled = true
+			}},
+			want: []string{"spec.server.externalDNS.hostnames"},
+		},
+		{
+			name: "invalid external-dns options",
+			opts: []argoCDOpt{func(a *argoprojv1alpha1.ArgoCD) {
+				a.Spec.Server.ExternalDNS = argoprojv1alpha1.ArgoCDExternalDNSSpec{
+					Enabled:   true,
+					Hostnames: []string{"argocd.example.com", "*.example.com", "Argo_CD.example.com"},
+					TTL:       -1,
+				}
+			}},
+			want: []string{"spec.server.externalDNS.hostnames[2]", "spec.server.externalDNS.ttl"},
+		},
+		{
+			name: "external-dns hostnames published by several endpoints",
+			opts: []argoCDOpt{func(a *argoprojv1alpha1.ArgoCD) {
+				a.Spec.Server.Host = "argocd.example.com"
+				a.Spec.Server.Ingress.Enabled = true
+				a.Spec.Server.Route.Enabled = true
+				a.Spec.Server.Ingresses = []argoprojv1alpha1.ArgoCDServerIngressSpec{{Name: "internal", Host: "argocd.internal.example.com"}}
+				a.Spec.Server.ExternalDNS = argoprojv1alpha1.ArgoCDExternalDNSSpec{Enabled: true, Hostnames: []string{"cd.example.com"}}
+			}},
+			want: []string{"spec.server.route.enabled", "spec.server.ingresses[0]"},
+		},
+		{
+			name: "external-dns hostnames published by distinct endpoints",
+			opts: []argoCDOpt{func(a *argoprojv1alpha1.ArgoCD) {
+				a.Spec.Server.Host = "argocd.example.com"
+				a.Spec.Server.Ingress.Enabled = true
+				a.Spec.Server.GRPC.Host = "grpc.argocd.example.com"
+				a.Spec.Server.GRPC.Ingress.Enabled = true
+				a.Spec.Server.Ingresses = []argoprojv1alpha1.ArgoCDServerIngressSpec{{Name: "internal", Host: "argocd.internal.example.com"}}
+				a.Spec.Server.ExternalDNS.Enabled = true
+			}},
+		},
+		{
+			name: "invalid server extensions",
+			opts: []argoCDOpt{func(a *argoprojv1alpha1.ArgoCD) {
+				a.Spec.Server.Extensions = []argoprojv1alpha1.ArgoCDServerExtensionSpec{
+					{Name: "rollouts", URL: "https://example.com/rollouts.tar"},
+					{Name: "rollouts", URL: "https://example.com/rollouts.tar"},
+					{Name: "Metrics", URL: "example.com/metrics.tar", ChecksumURL: "ftp://example.com/metrics.sha256"},
+				}
+			}},
+			want: []string{"spec.server.extensions[1].name", "spec.server.extensions[2].name", "spec.server.extensions[2].url", "spec.server.extensions[2].checksumURL"},
+		},
+		{
+			name: "invalid server ingresses",
+			opts: []argoCDOpt{func(a *argoprojv1alpha1.ArgoCD) {
+				a.Spec.Server.Ingresses = []argoprojv1alpha1.ArgoCDServerIngressSpec{
+					{Name: "internal"},
+					{Name: "internal"},
+					{Name: "External_ALB"},
+					{},
+					{Name: "grpc"},
+				}
+			}},
+			want: []string{"spec.server.ingresses[1].name", "spec.server.ingresses[2].name", "spec.server.ingresses[3].name", "spec.server.ingresses[4].name"},
+		},
+		{
+			name: "invalid prometheus labels",
+			opts: []argoCDOpt{func(a *argoprojv1alpha1.ArgoCD) {
+				a.Spec.Prometheus.Labels = map[string]string{
+					"app.kubernetes.io/name": "metrics",
+				}
+			}},
+			want: []string{"spec.prometheus.labels[app.kubernetes.io/name]"},
+		},
+		{
+			name: "invalid prometheus label value",
+			opts: []argoCDOpt{func(a *argoprojv1alpha1.ArgoCD) {
+				a.Spec.Prometheus.Labels = map[string]string{
+					"release": "kube prometheus stack",
+				}
+			}},
+			want: []string{"spec.prometheus.labels[release]"},
+		},
+		{
+			name: "invalid processors autosize bounds",
+			opts: []argoCDOpt{func(a *argoprojv1alpha1.ArgoCD) {
+				a.Spec.Controller.Processors.Status = 50
+				a.Spec.Controller.ProcessorsAutosize = &argoprojv1alpha1.ArgoCDApplicationControllerProcessorsAutosizeSpec{
+					Enabled:      true,
+					MaxOperation: -1,
+					MaxStatus:    40,
+				}
+			}},
+			want: []string{"spec.controller.processorsAutosize.maxOperation", "spec.controller.processorsAutosize.maxStatus"},
+		},
+		{
+			name: "negative repo user and group",
+			opts: []argoCDOpt{func(a *argoprojv1alpha1.ArgoCD) {
+				id := int64(-1)
+				a.Spec.Repo.RunAsUser = &id
+				a.Spec.Repo.FSGroup = &id
+			}},
+			want: []string{"spec.repo.runAsUser", "spec.repo.fsGroup"},
+		},
+		{
+			name: "server tls termination incoherent with insecure",
+			opts: []argoCDOpt{func(a *argoprojv1alpha1.ArgoCD) {
+				a.Spec.Server.Route.Enabled = true
+				a.Spec.Server.Route.TLS = &routev1.TLSConfig{Termination: routev1.TLSTerminationEdge}
+				a.Spec.Server.Ingress.Enabled = true
+				a.Spec.Server.Ingress.Annotations = map[string]string{common.ArgoCDKeyIngressBackendProtocol: "HTTP"}
+				a.Spec.Server.GRPC.Ingress.Enabled = true
+				a.Spec.Server.GRPC.Ingress.Annotations = map[string]string{common.ArgoCDKeyIngressBackendProtocol: "GRPC"}
+				a.Spec.Server.Ingresses = []argoprojv1alpha1.ArgoCDServerIngressSpec{
+					{Name: "internal", Annotations: map[string]string{common.ArgoCDKeyIngressBackendProtocol: "HTTPS"}},
+				}
+			}},
+			want: []string{"spec.server.ingress.annotations[nginx.ingress.kubernetes.io/backend-protocol]", "spec.server.grpc.ingress.annotations[nginx.ingress.kubernetes.io/backend-protocol]", "spec.server.route.tls.termination"},
+		},
+		{
+			name: "insecure server with reencrypt termination",
+			opts: []argoCDOpt{func(a *argoprojv1alpha1.ArgoCD) {
+				a.Spec.Server.Insecure = true
+				a.Spec.Server.Route.Enabled = true
+				a.Spec.Server.Route.TLS = &routev1.TLSConfig{Termination: routev1.TLSTerminationReencrypt}
+				a.Spec.Server.Ingresses = []argoprojv1alpha1.ArgoCDServerIngressSpec{
+					{Name: "internal", Annotations: map[string]string{common.ArgoCDKeyIngressBackendProtocol: "HTTPS"}},
+				}
+			}},
+			want: []string{"spec.server.ingresses[0].annotations[nginx.ingress.kubernetes.io/backend-protocol]", "spec.server.route.tls.termination"},
+		},
+		{
+			name: "invalid proxy extension config",
+			opts: []argoCDOpt{func(a *argoprojv1alpha1.ArgoCD) {
+				a.Spec.Version = "v2.7.0"
+				a.Spec.Server.ProxyExtension.Enabled = true
+				a.Spec.Server.ProxyExtension.Config = "extensions: [name: metrics"
+			}},
+			want: []string{"spec.server.proxyExtension.config"},
+		},
+		{
+			name: "proxy extension on an unsupported version",
+			opts: []argoCDOpt{func(a *argoprojv1alpha1.ArgoCD) {
+				a.Spec.Server.ProxyExtension.Enabled = true
+			}},
+			want: []string{"spec.server.proxyExtension.enabled"},
+		},
+		{
+			name: "proxy extension config without the proxy extensions",
+			opts: []argoCDOpt{func(a *argoprojv1alpha1.ArgoCD) {
+				a.Spec.Server.ProxyExtension.Config = "extensions: []"
+			}},
+			want: []string{"spec.server.proxyExtension.config"},
+		},
+		{
+			name: "invalid disaster recovery",
+			opts: []argoCDOpt{func(a *argoprojv1alpha1.ArgoCD) {
+				a.Spec.DisasterRecovery = &argoprojv1alpha1.ArgoCDDisasterRecoverySpec{Namespace: testNamespace, Mode: "backup"}
+			}},
+			want: []string{"spec.disasterRecovery.namespace", "spec.disasterRecovery.keySecretNamespace", "spec.disasterRecovery.mode"},
+		},
+		{
+			name: "disaster recovery key with the bundle",
+			opts: []argoCDOpt{func(a *argoprojv1alpha1.ArgoCD) {
+				a.Spec.DisasterRecovery = &argoprojv1alpha1.ArgoCDDisasterRecoverySpec{KeySecretNamespace: "argocd-dr", Namespace: "argocd-dr"}
+			}},
+			want: []string{"spec.disasterRecovery.keySecretNamespace"},
+		},
+		{
+			name: "invalid dex groups",
+			opts: []argoCDOpt{func(a *argoprojv1alpha1.ArgoCD) {
+				a.Spec.SSO = &argoprojv1alpha1.ArgoCDSSOSpec{
+					Provider: argoprojv1alpha1.SSOProviderTypeKeycloak,
+					Dex: &argoprojv1alpha1.ArgoCDSSODexSpec{
+						Groups: &argoprojv1alpha1.ArgoCDDexGroupsSpec{
+							Bindings: []argoprojv1alpha1.ArgoCDDexGroupBindingSpec{{Group: "a,b", Role: "role:admin"}, {Group: "dev"}, {Group: "ops", Role: "role:readonly\np, role:readonly, *, *, */*, allow"}},
+						},
+					},
+				}
+			}},
+			want: []string{"spec.sso.dex.groups", "spec.sso.dex.groups.bindings[0].group", "spec.sso.dex.groups.bindings[1].role", "spec.sso.dex.groups.bindings[2].role"},
+		},
+		{
+			name: "invalid cluster discovery secret selector",
+			opts: []argoCDOpt{func(a *argoprojv1alpha1.ArgoCD) {
+				a.Spec.ClusterDiscovery.SecretSelector = &metav1.LabelSelector{
+					MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "cluster", Operator: "Like"}},
+				}
+			}},
+			want: []string{"spec.clusterDiscovery.secretSelector"},
+		},
+		{
+			name: "invalid known hosts scan hosts",
+			opts: []argoCDOpt{func(a *argoprojv1alpha1.ArgoCD) {
+				a.Spec.KnownHostsAutoScan.Hosts = []string{"git.example.com:2222", "-oProxyCommand=x", "git.example.com:99999"}
+			}},
+			want: []string{"spec.knownHostsAutoScan.hosts[1]", "spec.knownHostsAutoScan.hosts[2]"},
+		},
+		{
+			name: "invalid redis config",
+			opts: []argoCDOpt{func(a *argoprojv1alpha1.ArgoCD) {
+				a.Spec.Redis.Config = argoprojv1alpha1.ArgoCDRedisConfigSpec{
+					MaxMemory:       "2 gigabytes",
+					MaxMemoryPolicy: "lru",
+					Save:            []argoprojv1alpha1.ArgoCDRedisSaveSpec{{Seconds: 900, Changes: 1}, {Seconds: 0, Changes: 10}},
+				}
+			}},
+			want: []string{"spec.redis.config.maxMemory", "spec.redis.config.maxMemoryPolicy", "spec.redis.config.save[1].seconds"},
+		},
+		{
+			name: "redis noeviction policy",
+			opts: []argoCDOpt{func(a *argoprojv1alpha1.ArgoCD) {
+				a.Spec.Redis.Config = argoprojv1alpha1.ArgoCDRedisConfigSpec{MaxMemory: "2gb", MaxMemoryPolicy: "noeviction"}
+			}},
+			want: []string{"spec.redis.config.maxMemoryPolicy"},
+		},
+		{
+			name: "invalid ip families",
+			opts: []argoCDOpt{func(a *argoprojv1alpha1.ArgoCD) {
+				a.Spec.IPFamilies = []string{"IPv6", "IPv6", "IPv5"}
+				a.Spec.IPFamilyPolicy = "DualStack"
+			}},
+			want: []string{"spec.ipFamilies[1]", "spec.ipFamilies[2]", "spec.ipFamilyPolicy"},
+		},
+		{
+			name: "single stack with two ip families",
+			opts: []argoCDOpt{func(a *argoprojv1alpha1.ArgoCD) {
+				a.Spec.IPFamilies = []string{"IPv6", "IPv4"}
+				a.Spec.IPFamilyPolicy = "SingleStack"
+			}},
+			want: []string{"spec.ipFamilies"},
+		},
+		{
+			name: "invalid cli config namespace",
+			opts: []argoCDOpt{func(a *argoprojv1alpha1.ArgoCD) {
+				a.Spec.CLIConfig.Namespace = "CI_Pipelines"
+			}},
+			want: []string{"spec.cliConfig.namespace"},
+		},
+		{
+			name: "unsupported cli config kind",
+			opts: []argoCDOpt{func(a *argoprojv1alpha1.ArgoCD) {
+				a.Spec.CLIConfig.Kind = "Route"
+			}},
+			want: []string{"spec.cliConfig.kind"},
+		},
+		{
+			name: "sharding algorithm unsupported by the Argo CD version",
+			opts: []argoCDOpt{func(a *argoprojv1alpha1.ArgoCD) {
+				a.Spec.Version = "v2.11.4"
+				a.Spec.Controller.Sharding.Algorithm = "consistent-hashing"
+			}},
+			want: []string{"spec.controller.sharding.algorithm"},
+		},
+		{
+			name: "vault plugin unsupported by the Argo CD version",
+			opts: []argoCDOpt{func(a *argoprojv1alpha1.ArgoCD) {
+				a.Spec.Version = "v2.3.5"
+				a.Spec.Repo.VaultPlugin.Enabled = true
+			}},
+			want: []string{"spec.repo.vaultPlugin.enabled"},
+		},
+		{
+			name: "controller timeouts",
+			opts: []argoCDOpt{func(a *argoprojv1alpha1.ArgoCD) {
+				a.Spec.Version = "v2.10.0"
+				a.Spec.Controller.SelfHealTimeout = &metav1.Duration{Duration: 0}
+				a.Spec.Controller.SyncTimeout = &metav1.Duration{Duration: time.Millisecond * 500}
+			}},
+			want: []string{"spec.controller.selfHealTimeout", "spec.controller.syncTimeout"},
+		},
+		{
+			name: "sync timeout unsupported by the Argo CD version",
+			opts: []argoCDOpt{func(a *argoprojv1alpha1.ArgoCD) {
+				a.Spec.Controller.SyncTimeout = &metav1.Duration{Duration: time.Minute}
+			}},
+			want: []string{"spec.controller.syncTimeout"},
+		},
+		{
+			name: "applicationset source namespaces unsupported by the image",
+			opts: []argoCDOpt{func(a *argoprojv1alpha1.ArgoCD) {
+				a.Spec.ApplicationSet = &argoprojv1alpha1.ArgoCDApplicationSet{
+					SourceNamespaces: []string{"team-a"},
+				}
+			}},
+			want: []string{"spec.applicationSet.sourceNamespaces"},
+		},
+		{
+			name: "server tls ciphers",
+			opts: []argoCDOpt{func(a *argoprojv1alpha1.ArgoCD) {
+				a.Spec.Server.TLS.Ciphers = []string{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256", "TLS_AES_128_GCM_SHA256", "ECDHE-RSA-AES128-GCM-SHA256"}
+			}},
+			want: []string{"spec.server.tls.ciphers[1]", "spec.server.tls.ciphers[2]"},
+		},
+		{
+			name: "server tls ciphers with TLS 1.3",
+			opts: []argoCDOpt{func(a *argoprojv1alpha1.ArgoCD) {
+				a.Spec.Server.TLS.MinVersion = "1.3"
+				a.Spec.Server.TLS.Ciphers = []string{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256"}
+			}},
+			want: []string{"spec.server.tls.ciphers"},
+		},
+		{
+			name: "unsupported values",
+			opts: []argoCDOpt{func(a *argoprojv1alpha1.ArgoCD) {
+				a.Spec.Controller.Sharding.Algorithm = "random"
+				a.Spec.Repo.AutoTLS = "cert-manager"
+				a.Spec.Profile = "huge"
+				a.Spec.UpgradeStrategy.Type = "Canary"
+				a.Spec.Server.TLS.MinVersion = "1.4"
+				a.Spec.ComplianceMode = "baseline"
+				a.Spec.DeletionPolicy = "Delete"
+			}},
+			want: []string{"spec.controller.sharding.algorithm", "spec.repo.autotls", "spec.profile", "spec.upgradeStrategy.type", "spec.server.tls.minVersion", "spec.complianceMode", "spec.deletionPolicy"},
+		},
+	}
+
+	for _, tt := range validationTests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := validateArgoCDSpec(makeTestArgoCD(tt.opts...))
+			assert.Equal(t, len(errs), len(tt.want))
+			for i, want := range tt.want {
+				assert.Equal(t, errs[i].Field, want)
+			}
+		})
+	}
+}
+
+func TestReconcileArgoCD_Reconcile_invalidSpec(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.UpgradeStrategy.Type = "Canary"
+	})
+	r := makeTestReconciler(t, a)
+	assert.NilError(t, createNamespace(r, a.Namespace, ""))
+
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: a.Name, Namespace: a.Namespace}}
+	_, err := r.Reconcile(req)
+	assert.NilError(t, err)
+
+	assert.NilError(t, r.client.Get(context.TODO(), req.NamespacedName, a))
+	assert.Assert(t, !isArgoCDConditionTrue(a, argoprojv1alpha1.ArgoCDConditionTypeSpecValid))
+	for _, c := range a.Status.Conditions {
+		if c.Type == argoprojv1alpha1.ArgoCDConditionTypeSpecValid {
+			assert.Equal(t, c.Reason, specValidReasonInvalid)
+			assert.Assert(t, strings.Contains(c.Message, "spec.upgradeStrategy.type"))
+		}
+	}
+	assert.Assert(t, !argoutil.IsObjectFound(r.client, testNamespace, "argocd-server", &appsv1.Deployment{}))
+
+	a.Spec.UpgradeStrategy.Type = ""
+	assert.NilError(t, r.client.Update(context.TODO(), a))
+	_, err = r.Reconcile(req)
+	assert.NilError(t, err)
+
+	assert.NilError(t, r.client.Get(context.TODO(), req.NamespacedName, a))
+	assert.Assert(t, isArgoCDConditionTrue(a, argoprojv1alpha1.ArgoCDConditionTypeSpecValid))
+	assert.Assert(t, argoutil.IsObjectFound(r.client, testNamespace, "argocd-server", &appsv1.Deployment{}))
+}
@@ -0,0 +1,270 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocd
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
+	"github.com/argoproj-labs/argocd-operator/pkg/controller/argoutil"
+)
+
+// vaultPluginMinimumVersion is the first version of Argo CD that runs config management plugins as sidecars.
+const vaultPluginMinimumVersion = "v2.4.0"
+
+// vaultPluginDownloadScript downloads the argocd-vault-plugin release for the architecture of the node and verifies
+// it against the checksums published with the release.
+const vaultPluginDownloadScript = `set -e
+case "$(uname -m)" in
+  x86_64) arch=amd64 ;;
+  aarch64) arch=arm64 ;;
+  ppc64le) arch=ppc64le ;;
+  s390x) arch=s390x ;;
+  *) echo "unsupported architecture $(uname -m)" >&2; exit 1 ;;
+esac
+asset="argocd-vault-plugin_%[1]s_linux_${arch}"
+url="https://github.com/argoproj-labs/argocd-vault-plugin/releases/download/v%[1]s"
+cd /custom-tools
+wget -O "${asset}" "${url}/${asset}"
+wget -O checksums.txt "${url}/argocd-vault-plugin_%[1]s_checksums.txt"
+grep " ${asset}$" checksums.txt | sha256sum -c -
+mv "${asset}" argocd-vault-plugin
+rm checksums.txt
+chmod +x argocd-vault-plugin
+`
+
+// vaultPluginConfig is the ConfigManagementPlugin definition used by the argocd-vault-plugin sidecar.
+const vaultPluginConfig = `apiVersion: argoproj.io/v1alpha1
+kind: ConfigManagementPlugin
+metadata:
+  name: argocd-vault-plugin
+spec:
+  allowConcurrency: true
+  discover:
+    find:
+      command:
+      - sh
+      - "-c"
+      - "find . -name '*.yaml' | xargs -I {} grep \"<path\\|avp\\.kubernetes\\.io\" {} | grep ."
+  generate:
+    command:
+    - argocd-vault-plugin
+    - generate
+    - "."
+  lockRepo: false
+`
+
+// getVaultPluginCredentialsSecret will return the name of the Secret holding the argocd-vault-plugin configuration.
+func getVaultPluginCredentialsSecret(cr *argoprojv1a1.ArgoCD) string {
+	name := common.ArgoCDDefaultVaultPluginCredentialsSecret
+	if len(cr.Spec.Repo.VaultPlugin.CredentialsSecret) > 0 {
+		name = cr.Spec.Repo.VaultPlugin.CredentialsSecret
+	}
+	return name
+}
+
+// getVaultPluginImage will return the container image used to download the argocd-vault-plugin.
+func getVaultPluginImage(cr *argoprojv1a1.ArgoCD) string {
+	img := cr.Spec.Repo.VaultPlugin.Image
+	if len(img) > 0 {
+		return img
+	}
+	if e := argoutil.GetDefaultImage(common.ArgoCDVaultPluginImageEnvName); e != "" {
+		return e
+	}
+	return argoutil.ReplaceImageRegistry(common.ArgoCDDefaultVaultPluginImage, cr.Spec.ImageRegistry)
+}
+
+// getVaultPluginVersion will return the argocd-vault-plugin release to install.
+func getVaultPluginVersion(cr *argoprojv1a1.ArgoCD) string {
+	version := common.ArgoCDDefaultVaultPluginVersion
+	if len(cr.Spec.Repo.VaultPlugin.Version) > 0 {
+		version = cr.Spec.Repo.VaultPlugin.Version
+	}
+	return version
+}
+
+// getVaultPluginInitContainers will return the init containers that install the config management plugin server
+// and the argocd-vault-plugin binary for the Repo server.
+func getVaultPluginInitContainers(cr *argoprojv1a1.ArgoCD) []corev1.Container {
+	download := fmt.Sprintf(vaultPluginDownloadScript, getVaultPluginVersion(cr))
+
+	return []corev1.Container{
+		{
+			Command:         []string{"cp", "-n", "/usr/local/bin/argocd", "/var/run/argocd/argocd-cmp-server"},
+			Image:           getArgoContainerImage(cr),
+			ImagePullPolicy: corev1.PullAlways,
+			Name:            "copyutil",
+			VolumeMounts: []corev1.VolumeMount{
+				{
+					Name:      "var-files",
+					MountPath: "/var/run/argocd",
+				},
+			},
+		},
+		{
+			Command:         []string{"sh", "-c", download},
+			Env:             proxyEnvVars(cr),
+			Image:           getVaultPluginImage(cr),
+			ImagePullPolicy: corev1.PullIfNotPresent,
+			Name:            "download-tools",
+			VolumeMounts: []corev1.VolumeMount{
+				{
+					Name:      "custom-tools",
+					MountPath: "/custom-tools",
+				},
+			},
+		},
+	}
+}
+
+// getVaultPluginSidecar will return the config management plugin sidecar container for the argocd-vault-plugin.
+func getVaultPluginSidecar(cr *argoprojv1a1.ArgoCD) corev1.Container {
+	var runAsUser int64 = 999
+	return corev1.Container{
+		Command: []string{"/var/run/argocd/argocd-cmp-server"},
+		EnvFrom: []corev1.EnvFromSource{{
+			SecretRef: &corev1.SecretEnvSource{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: getVaultPluginCredentialsSecret(cr),
+				},
+				Optional: boolPtr(true),
+			},
+		}},
+		Image:           getArgoContainerImage(cr),
+		ImagePullPolicy: corev1.PullAlways,
+		Name:            "avp",
+		SecurityContext: &corev1.SecurityContext{
+			RunAsNonRoot: boolPtr(true),
+			RunAsUser:    &runAsUser,
+		},
+		VolumeMounts: []corev1.VolumeMount{
+			{
+				Name:      "var-files",
+				MountPath: "/var/run/argocd",
+			},
+			{
+				Name:      "plugins",
+				MountPath: "/home/argocd/cmp-server/plugins",
+			},
+			{
+				Name:      "cmp-tmp",
+				MountPath: "/tmp",
+			},
+			{
+				Name:      "cmp-plugin",
+				MountPath: "/home/argocd/cmp-server/config/plugin.yaml",
+				SubPath:   common.ArgoCDKeyVaultPluginConfig,
+			},
+			{
+				Name:      "custom-tools",
+				MountPath: "/usr/local/bin/argocd-vault-plugin",
+				SubPath:   "argocd-vault-plugin",
+			},
+		},
+	}
+}
+
+// getVaultPluginRepoVolumeMounts will return the volume mounts needed by the Repo server container to communicate
+// with the argocd-vault-plugin sidecar.
+func getVaultPluginRepoVolumeMounts() []corev1.VolumeMount {
+	return []corev1.VolumeMount{
+		{
+			Name:      "var-files",
+			MountPath: "/var/run/argocd",
+		},
+		{
+			Name:      "plugins",
+			MountPath: "/home/argocd/cmp-server/plugins",
+		},
+	}
+}
+
+// getVaultPluginVolumes will return the volumes needed by the argocd-vault-plugin sidecar.
+func getVaultPluginVolumes() []corev1.Volume {
+	return []corev1.Volume{
+		{
+			Name: "var-files",
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{},
+			},
+		},
+		{
+			Name: "plugins",
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{},
+			},
+		},
+		{
+			Name: "cmp-tmp",
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{},
+			},
+		},
+		{
+			Name: "custom-tools",
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{},
+			},
+		},
+		{
+			Name: "cmp-plugin",
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: common.ArgoCDVaultPluginConfigMapName,
+					},
+				},
+			},
+		},
+	}
+}
+
+// reconcileVaultPluginConfigMap will ensure that the argocd-vault-plugin ConfigMap is present when the plugin is
+// enabled for the given ArgoCD.
+func (r *ReconcileArgoCD) reconcileVaultPluginConfigMap(cr *argoprojv1a1.ArgoCD) error {
+	cm := newConfigMapWithName(common.ArgoCDVaultPluginConfigMapName, cr)
+	if argoutil.IsObjectFound(r.client, cr.Namespace, cm.Name, cm) {
+		if !cr.Spec.Repo.VaultPlugin.Enabled {
+			// ConfigMap exists but enabled flag has been set to false, delete the ConfigMap
+			return r.client.Delete(context.TODO(), cm)
+		}
+		if cm.Data[common.ArgoCDKeyVaultPluginConfig] != vaultPluginConfig {
+			cm.Data = map[string]string{
+				common.ArgoCDKeyVaultPluginConfig: vaultPluginConfig,
+			}
+			return r.client.Update(context.TODO(), cm)
+		}
+		return nil // ConfigMap found with nothing changed, move along...
+	}
+
+	if !cr.Spec.Repo.VaultPlugin.Enabled {
+		return nil // Vault plugin not enabled, do nothing.
+	}
+
+	cm.Data = map[string]string{
+		common.ArgoCDKeyVaultPluginConfig: vaultPluginConfig,
+	}
+
+	if err := controllerutil.SetControllerReference(cr, cm, r.scheme); err != nil {
+		return err
+	}
+	return r.client.Create(context.TODO(), cm)
+}
@@ -0,0 +1,131 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocdagent
+
+import (
+	"context"
+	"fmt"
+
+	argoproj "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
+	"github.com/argoproj-labs/argocd-operator/pkg/controller/argoutil"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var log = logf.Log.WithName("controller_argocdagent")
+
+// Add creates a new ArgoCDAgent Controller and adds it to the Manager. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(mgr manager.Manager) error {
+	return add(mgr, newReconciler(mgr))
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager) reconcile.Reconciler {
+	return &ReconcileArgoCDAgent{client: mgr.GetClient(), scheme: mgr.GetScheme(), hubClient: argoutil.NewClientFromKubeconfig}
+}
+
+// add adds a new Controller to mgr with r as the reconcile.Reconciler
+func add(mgr manager.Manager, r reconcile.Reconciler) error {
+	// Create a new controller
+	c, err := controller.New("argocdagent-controller", mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+
+	// Register watches for all controller resources
+	if err := watchArgoCDAgentResources(c); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// blank assignment to verify that ReconcileArgoCDAgent implements reconcile.Reconciler
+var _ reconcile.Reconciler = &ReconcileArgoCDAgent{}
+
+// ReconcileArgoCDAgent reconciles a ArgoCDAgent object
+type ReconcileArgoCDAgent struct {
+	// This client, initialized using mgr.Client() above, is a split client
+	// that reads objects from the cache and writes to the apiserver
+	client client.Client
+	scheme *runtime.Scheme
+
+	// hubClient returns a client for the hub cluster from the given kubeconfig data.
+	hubClient func(kubeconfig []byte) (client.Client, error)
+}
+
+// Reconcile reads that state of the cluster for a ArgoCDAgent object and makes changes based on the state read
+// and what is in the ArgoCDAgent.Spec
+// Note:
+// The Controller will requeue the Request to be processed again if the returned error is non-nil or
+// Result.Requeue is true, otherwise upon completion it will remove the work from the queue.
+func (r *ReconcileArgoCDAgent) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	reqLogger := log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
+	reqLogger.Info("Reconciling ArgoCDAgent")
+
+	// Fetch the ArgoCDAgent instance
+	agent := &argoproj.ArgoCDAgent{}
+	err := r.client.Get(context.TODO(), request.NamespacedName, agent)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			// Request object not found, could have been deleted after reconcile request.
+			// Owned objects are automatically garbage collected. For additional cleanup logic use finalizers.
+			// Return and don't requeue
+			return reconcile.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return reconcile.Result{}, err
+	}
+
+	if agent.GetDeletionTimestamp() != nil {
+		if agent.IsDeletionFinalizerPresent() {
+			if err := r.deleteAgentResources(agent); err != nil {
+				return reconcile.Result{}, fmt.Errorf("failed to delete agent resources: %w", err)
+			}
+
+			agent.Finalizers = removeString(agent.GetFinalizers(), common.ArgoCDDeletionFinalizer)
+			if err := r.client.Update(context.TODO(), agent); err != nil {
+				return reconcile.Result{}, fmt.Errorf("failed to remove deletion finalizer from %s: %w", agent.Name, err)
+			}
+		}
+		return reconcile.Result{}, nil
+	}
+
+	if !agent.IsDeletionFinalizerPresent() {
+		agent.Finalizers = append(agent.Finalizers, common.ArgoCDDeletionFinalizer)
+		if err := r.client.Update(context.TODO(), agent); err != nil {
+			return reconcile.Result{}, fmt.Errorf("failed to add deletion finalizer for %s: %w", agent.Name, err)
+		}
+	}
+
+	if err := r.reconcileArgoCDAgentResources(agent); err != nil {
+		// Error reconciling ArgoCDAgent sub-resources - requeue the request.
+		return reconcile.Result{}, err
+	}
+
+	if delay := getTokenRotationDelay(agent); delay > 0 {
+		// Requeue to rotate the token once the rotation interval has elapsed, or to revoke the previous token.
+		return reconcile.Result{RequeueAfter: delay}, nil
+	}
+
+	return reconcile.Result{}, nil
+}
@@ -0,0 +1,288 @@
+package argocdagent
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/argoproj-labs/argocd-operator/pkg/apis"
+	argoprojv1alpha1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
+	"github.com/argoproj-labs/argocd-operator/pkg/controller/argoutil"
+)
+
+const (
+	testNamespace    = "argocd-agent"
+	testAgentName    = "spoke"
+	testHubNamespace = "argocd"
+)
+
+func makeTestAgent() *argoprojv1alpha1.ArgoCDAgent {
+	return &argoprojv1alpha1.ArgoCDAgent{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      testAgentName,
+			Namespace: testNamespace,
+		},
+		Spec: argoprojv1alpha1.ArgoCDAgentSpec{
+			Hub: argoprojv1alpha1.ArgoCDAgentHubSpec{
+				KubeconfigSecret:   "hub-kubeconfig",
+				Namespace:          testHubNamespace,
+				RepoServer:         "argocd-repo-server.hub.example.com:8081",
+				RepoServerCASecret: "hub-repo-server-ca",
+				Server:             "https://spoke.example.com:6443",
+			},
+		},
+	}
+}
+
+func makeTestReconciler(t *testing.T, hub client.Client, objs ...runtime.Object) *ReconcileArgoCDAgent {
+	s := scheme.Scheme
+	assert.NilError(t, apis.AddToScheme(s))
+
+	return &ReconcileArgoCDAgent{
+		client: fake.NewFakeClientWithScheme(s, objs...),
+		scheme: s,
+		hubClient: func(kubeconfig []byte) (client.Client, error) {
+			return hub, nil
+		},
+	}
+}
+
+func makeTestSecrets(cr *argoprojv1alpha1.ArgoCDAgent, token string) []runtime.Object {
+	return []runtime.Object{
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "hub-kubeconfig", Namespace: testNamespace},
+			Data:       map[string][]byte{common.ArgoCDKeyAgentKubeconfig: []byte("kubeconfig")},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{corev1.ServiceAccountNameKey: nameWithSuffix(agentHubComponent, cr)},
+				Labels:      agentObjectMeta(nameWithSuffix(agentHubComponent+"-token", cr), agentHubComponent, cr).Labels,
+				Name:        nameWithSuffix(agentHubComponent+"-token", cr),
+				Namespace:   testNamespace,
+			},
+			Type: corev1.SecretTypeServiceAccountToken,
+			Data: map[string][]byte{
+				corev1.ServiceAccountTokenKey:  []byte(token),
+				corev1.ServiceAccountRootCAKey: []byte("ca"),
+			},
+		},
+	}
+}
+
+func TestNewApplicationControllerStatefulSet(t *testing.T) {
+	a := makeTestAgent()
+
+	ss := newApplicationControllerStatefulSet(a)
+
+	assert.DeepEqual(t, ss.Spec.Template.Spec.Containers[0].Command, []string{
+		"argocd-application-controller",
+		"--operation-processors", "10",
+		"--redis", "spoke-redis.argocd-agent.svc.cluster.local:6379",
+		"--repo-server", "argocd-repo-server.hub.example.com:8081",
+		"--repo-server-strict-tls",
+		"--status-processors", "20",
+	})
+	assert.Equal(t, ss.Spec.Template.Spec.ServiceAccountName, "spoke-argocd-application-controller")
+	assert.Equal(t, ss.Spec.Template.Spec.Volumes[0].Secret.SecretName, "hub-repo-server-ca")
+	assert.Equal(t, ss.Spec.Template.Spec.Containers[0].VolumeMounts[0].MountPath, "/app/config/controller/tls")
+}
+
+func TestValidateArgoCDAgent(t *testing.T) {
+	a := makeTestAgent()
+	assert.NilError(t, validateArgoCDAgent(a))
+
+	a.Spec.Version = "v2.0.5"
+	assert.ErrorContains(t, validateArgoCDAgent(a), "requires Argo CD v2.1.0 or later")
+
+	a = makeTestAgent()
+	a.Spec.Hub.RepoServerCASecret = ""
+	assert.ErrorContains(t, validateArgoCDAgent(a), "spec.hub.repoServerCASecret must be set")
+}
+
+func TestReconcileArgoCDAgent_reconcileClusterRBAC(t *testing.T) {
+	a := makeTestAgent()
+	r := makeTestReconciler(t, fake.NewFakeClientWithScheme(scheme.Scheme), a)
+
+	assert.NilError(t, r.reconcileClusterRole(agentHubComponent, getAgentHubPolicyRules(), a))
+	assert.NilError(t, r.reconcileClusterRoleBinding(agentHubComponent, a))
+
+	role := &rbacv1.ClusterRole{}
+	assert.NilError(t, argoutil.FetchObject(r.client, "", "spoke-argocd-agent-argocd-hub", role))
+	assert.DeepEqual(t, role.Rules[0].Verbs, []string{"get", "list", "watch"})
+
+	// The ClusterRoleBinding is restored when it has been changed.
+	binding := &rbacv1.ClusterRoleBinding{}
+	assert.NilError(t, argoutil.FetchObject(r.client, "", "spoke-argocd-agent-argocd-hub", binding))
+	binding.Subjects = append(binding.Subjects, rbacv1.Subject{Kind: rbacv1.ServiceAccountKind, Name: "other", Namespace: testNamespace})
+	assert.NilError(t, r.client.Update(context.TODO(), binding))
+	assert.NilError(t, r.reconcileClusterRoleBinding(agentHubComponent, a))
+
+	binding = &rbacv1.ClusterRoleBinding{}
+	assert.NilError(t, argoutil.FetchObject(r.client, "", "spoke-argocd-agent-argocd-hub", binding))
+	assert.DeepEqual(t, binding.Subjects, []rbacv1.Subject{{Kind: rbacv1.ServiceAccountKind, Name: "spoke-argocd-hub", Namespace: testNamespace}})
+
+	// The ClusterRoleBinding is recreated when it refers to another ClusterRole.
+	binding.RoleRef.Name = "cluster-admin"
+	assert.NilError(t, r.client.Update(context.TODO(), binding))
+	assert.NilError(t, r.reconcileClusterRoleBinding(agentHubComponent, a))
+
+	binding = &rbacv1.ClusterRoleBinding{}
+	assert.NilError(t, argoutil.FetchObject(r.client, "", "spoke-argocd-agent-argocd-hub", binding))
+	assert.Equal(t, binding.RoleRef.Name, "spoke-argocd-agent-argocd-hub")
+}
+
+func TestReconcileArgoCDAgent_reconcileHubClusterSecret(t *testing.T) {
+	a := makeTestAgent()
+	hub := fake.NewFakeClientWithScheme(scheme.Scheme)
+	r := makeTestReconciler(t, hub, append(makeTestSecrets(a, "token"), a)...)
+
+	registered, err := r.reconcileHubClusterSecret(a)
+	assert.NilError(t, err)
+	assert.Assert(t, registered)
+
+	secret := &corev1.Secret{}
+	assert.NilError(t, argoutil.FetchObject(hub, testHubNamespace, "cluster-spoke", secret))
+	assert.Equal(t, secret.Labels[common.ArgoCDSecretTypeLabel], common.ArgoCDSecretTypeCluster)
+	assert.Equal(t, string(secret.Data["name"]), "spoke")
+	assert.Equal(t, string(secret.Data["server"]), "https://spoke.example.com:6443")
+
+	config := map[string]interface{}{}
+	assert.NilError(t, json.Unmarshal(secret.Data["config"], &config))
+	assert.Equal(t, config["bearerToken"], "token")
+}
+
+func TestReconcileArgoCDAgent_reconcileHubClusterSecret_tokenNotPopulated(t *testing.T) {
+	a := makeTestAgent()
+	hub := fake.NewFakeClientWithScheme(scheme.Scheme)
+	r := makeTestReconciler(t, hub, append(makeTestSecrets(a, ""), a)...)
+
+	registered, err := r.reconcileHubClusterSecret(a)
+	assert.NilError(t, err)
+	assert.Assert(t, !registered)
+	assert.Assert(t, !argoutil.IsObjectFound(hub, testHubNamespace, "cluster-spoke", &corev1.Secret{}))
+}
+
+func TestReconcileArgoCDAgent_deleteHubClusterSecret(t *testing.T) {
+	a := makeTestAgent()
+	a.Spec.Hub.ClusterName = "workload"
+	hub := fake.NewFakeClientWithScheme(scheme.Scheme, newHubClusterSecret(a))
+	r := makeTestReconciler(t, hub, append(makeTestSecrets(a, "token"), a)...)
+
+	assert.NilError(t, r.deleteHubClusterSecret(a))
+	assert.Assert(t, !argoutil.IsObjectFound(hub, testHubNamespace, "cluster-workload", &corev1.Secret{}))
+}
+
+func getTestHubToken(t *testing.T, hub client.Client) string {
+	t.Helper()
+	secret := &corev1.Secret{}
+	assert.NilError(t, argoutil.FetchObject(hub, testHubNamespace, "cluster-spoke", secret))
+	config := map[string]interface{}{}
+	assert.NilError(t, json.Unmarshal(secret.Data["config"], &config))
+	return config["bearerToken"].(string)
+}
+
+func TestReconcileArgoCDAgent_reconcileHubClusterSecret_createToken(t *testing.T) {
+	a := makeTestAgent()
+	objs := makeTestSecrets(a, "token")
+	hub := fake.NewFakeClientWithScheme(scheme.Scheme)
+	r := makeTestReconciler(t, hub, objs[0], a)
+
+	registered, err := r.reconcileHubClusterSecret(a)
+	assert.NilError(t, err)
+	assert.Assert(t, !registered)
+
+	secret := &corev1.Secret{}
+	assert.NilError(t, argoutil.FetchObject(r.client, testNamespace, objs[1].(*corev1.Secret).Name, secret))
+	assert.Equal(t, secret.Type, corev1.SecretTypeServiceAccountToken)
+	assert.Equal(t, secret.Annotations[corev1.ServiceAccountNameKey], "spoke-argocd-hub")
+	assert.Assert(t, metav1.IsControlledBy(secret, a))
+}
+
+func TestReconcileArgoCDAgent_reconcileHubClusterSecret_rotation(t *testing.T) {
+	a := makeTestAgent()
+	a.Spec.Hub.TokenRotationInterval = &metav1.Duration{Duration: time.Hour}
+	objs := makeTestSecrets(a, "old")
+	token := objs[1].(*corev1.Secret)
+	token.CreationTimestamp = metav1.NewTime(time.Now().Add(-30 * time.Minute).Truncate(time.Second))
+	hub := fake.NewFakeClientWithScheme(scheme.Scheme)
+	r := makeTestReconciler(t, hub, append(objs, a)...)
+
+	// The token is kept until the rotation interval has elapsed.
+	registered, err := r.reconcileHubClusterSecret(a)
+	assert.NilError(t, err)
+	assert.Assert(t, registered)
+	assert.Equal(t, getTestHubToken(t, hub), "old")
+	assert.Equal(t, a.Status.LastTokenRotation.Time, token.CreationTimestamp.Time)
+	assert.Equal(t, a.Status.NextTokenRotation.Time, token.CreationTimestamp.Add(time.Hour))
+	assert.Assert(t, a.Status.TokenRevocation == nil)
+
+	// A new token Secret is created once the rotation interval has elapsed, the hub keeps the old token until the
+	// new one has been populated.
+	secret := &corev1.Secret{}
+	assert.NilError(t, argoutil.FetchObject(r.client, testNamespace, token.Name, secret))
+	secret.CreationTimestamp = metav1.NewTime(time.Now().Add(-2 * time.Hour))
+	assert.NilError(t, r.client.Update(context.TODO(), secret))
+
+	registered, err = r.reconcileHubClusterSecret(a)
+	assert.NilError(t, err)
+	assert.Assert(t, registered)
+	assert.Equal(t, getTestHubToken(t, hub), "old")
+	assert.Assert(t, a.Status.NextTokenRotation.After(time.Now().Add(59*time.Minute)))
+
+	secrets := &corev1.SecretList{}
+	assert.NilError(t, r.client.List(context.TODO(), secrets, client.InNamespace(testNamespace), client.MatchingLabels(token.Labels)))
+	assert.Equal(t, len(secrets.Items), 2)
+	next := secrets.Items[0]
+	if next.Name == token.Name {
+		next = secrets.Items[1]
+	}
+	assert.Equal(t, next.Annotations[corev1.ServiceAccountNameKey], "spoke-argocd-hub")
+
+	// The new token is propagated to the hub, and the old token is only revoked after the revocation delay.
+	next.Data = map[string][]byte{corev1.ServiceAccountTokenKey: []byte("new")}
+	assert.NilError(t, r.client.Update(context.TODO(), &next))
+
+	_, err = r.reconcileHubClusterSecret(a)
+	assert.NilError(t, err)
+	assert.Equal(t, getTestHubToken(t, hub), "new")
+	assert.Assert(t, argoutil.IsObjectFound(r.client, testNamespace, token.Name, &corev1.Secret{}))
+	assert.Assert(t, a.Status.TokenRevocation != nil)
+	assert.Assert(t, getTokenRotationDelay(a) <= argoutil.DefaultTokenRevocationDelay)
+
+	assert.NilError(t, argoutil.FetchObject(r.client, testNamespace, next.Name, &next))
+	next.Annotations[common.ArgoCDTokenPropagatedAnnotation] = time.Now().Add(-10 * time.Minute).UTC().Format(time.RFC3339)
+	assert.NilError(t, r.client.Update(context.TODO(), &next))
+
+	_, err = r.reconcileHubClusterSecret(a)
+	assert.NilError(t, err)
+	assert.Assert(t, !argoutil.IsObjectFound(r.client, testNamespace, token.Name, &corev1.Secret{}))
+	assert.Assert(t, a.Status.TokenRevocation == nil)
+}
+
+func TestReconcileArgoCDAgent_reconcileHubClusterSecret_noRotation(t *testing.T) {
+	a := makeTestAgent()
+	a.Status.NextTokenRotation = &metav1.Time{Time: time.Now()}
+	objs := makeTestSecrets(a, "token")
+	objs[1].(*corev1.Secret).CreationTimestamp = metav1.NewTime(time.Now().Add(-24 * time.Hour))
+	hub := fake.NewFakeClientWithScheme(scheme.Scheme)
+	r := makeTestReconciler(t, hub, append(objs, a)...)
+
+	_, err := r.reconcileHubClusterSecret(a)
+	assert.NilError(t, err)
+	secret := &corev1.Secret{}
+	assert.NilError(t, argoutil.FetchObject(r.client, testNamespace, objs[1].(*corev1.Secret).Name, secret))
+	assert.Equal(t, string(secret.Data[corev1.ServiceAccountTokenKey]), "token")
+	assert.Assert(t, a.Status.NextTokenRotation == nil)
+	assert.Equal(t, getTokenRotationDelay(a), time.Duration(0))
+}
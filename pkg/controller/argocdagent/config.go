@@ -0,0 +1,87 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocdagent
+
+import (
+	"context"
+
+	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
+	"github.com/argoproj-labs/argocd-operator/pkg/controller/argoutil"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// appProjectGVK is the GroupVersionKind of the Argo CD AppProject resource.
+var appProjectGVK = schema.GroupVersionKind{Group: "argoproj.io", Version: "v1alpha1", Kind: "AppProject"}
+
+// reconcileConfigMap will ensure the Argo CD ConfigMap read by the Application Controller is present.
+func (r *ReconcileArgoCDAgent) reconcileConfigMap(cr *argoprojv1a1.ArgoCDAgent) error {
+	cm := &corev1.ConfigMap{
+		ObjectMeta: agentObjectMeta(common.ArgoCDConfigMapName, agentControllerComponent, cr),
+	}
+	if argoutil.IsObjectFound(r.client, cm.Namespace, cm.Name, cm) {
+		return nil // ConfigMap found, do nothing
+	}
+
+	if err := controllerutil.SetControllerReference(cr, cm, r.scheme); err != nil {
+		return err
+	}
+	return r.client.Create(context.TODO(), cm)
+}
+
+// reconcileSecret will ensure the Argo CD Secret read by the Application Controller is present.
+func (r *ReconcileArgoCDAgent) reconcileSecret(cr *argoprojv1a1.ArgoCDAgent) error {
+	secret := &corev1.Secret{
+		ObjectMeta: agentObjectMeta(common.ArgoCDSecretName, agentControllerComponent, cr),
+		Type:       corev1.SecretTypeOpaque,
+	}
+	if argoutil.IsObjectFound(r.client, secret.Namespace, secret.Name, secret) {
+		return nil // Secret found, do nothing
+	}
+
+	if err := controllerutil.SetControllerReference(cr, secret, r.scheme); err != nil {
+		return err
+	}
+	return r.client.Create(context.TODO(), secret)
+}
+
+// reconcileDefaultProject will ensure the "default" AppProject is present in the namespace of the ArgoCDAgent.
+// The project is normally created by the Argo CD Server, which is not part of the agent.
+func (r *ReconcileArgoCDAgent) reconcileDefaultProject(cr *argoprojv1a1.ArgoCDAgent) error {
+	project := &unstructured.Unstructured{}
+	project.SetGroupVersionKind(appProjectGVK)
+	err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: cr.Namespace, Name: "default"}, project)
+	if err == nil || !errors.IsNotFound(err) {
+		return err
+	}
+
+	project.SetName("default")
+	project.SetNamespace(cr.Namespace)
+	project.Object["spec"] = map[string]interface{}{
+		"clusterResourceWhitelist": []interface{}{
+			map[string]interface{}{"group": "*", "kind": "*"},
+		},
+		"destinations": []interface{}{
+			map[string]interface{}{"namespace": "*", "server": "*"},
+		},
+		"sourceRepos": []interface{}{"*"},
+	}
+	return r.client.Create(context.TODO(), project)
+}
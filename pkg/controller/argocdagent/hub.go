@@ -0,0 +1,133 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocdagent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
+	"github.com/argoproj-labs/argocd-operator/pkg/controller/argoutil"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// newHubClusterSecret returns the cluster Secret that registers the cluster of the given ArgoCDAgent with the hub.
+func newHubClusterSecret(cr *argoprojv1a1.ArgoCDAgent) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("cluster-%s", getAgentClusterName(cr)),
+			Namespace: cr.Spec.Hub.Namespace,
+			Labels: map[string]string{
+				common.ArgoCDKeyPartOf:       common.ArgoCDAppName,
+				common.ArgoCDSecretTypeLabel: common.ArgoCDSecretTypeCluster,
+			},
+		},
+		Type: corev1.SecretTypeOpaque,
+	}
+}
+
+// getHubClient returns a client for the hub cluster of the given ArgoCDAgent.
+func (r *ReconcileArgoCDAgent) getHubClient(cr *argoprojv1a1.ArgoCDAgent) (client.Client, error) {
+	secret := &corev1.Secret{}
+	if err := argoutil.FetchObject(r.client, cr.Namespace, cr.Spec.Hub.KubeconfigSecret, secret); err != nil {
+		return nil, fmt.Errorf("unable to fetch hub kubeconfig secret %s: %w", cr.Spec.Hub.KubeconfigSecret, err)
+	}
+
+	kubeconfig, ok := secret.Data[common.ArgoCDKeyAgentKubeconfig]
+	if !ok {
+		return nil, fmt.Errorf("hub kubeconfig secret %s has no %s key", cr.Spec.Hub.KubeconfigSecret, common.ArgoCDKeyAgentKubeconfig)
+	}
+	return r.hubClient(kubeconfig)
+}
+
+// reconcileHubClusterSecret will ensure the token for the ServiceAccount used by the hub is present, and rotated when
+// the token rotation interval elapses, and that the cluster Secret with the current token for the given ArgoCDAgent
+// is present on the hub. It returns true when the cluster has been registered with the hub.
+func (r *ReconcileArgoCDAgent) reconcileHubClusterSecret(cr *argoprojv1a1.ArgoCDAgent) (bool, error) {
+	registered := false
+	status, err := r.newServiceAccountTokenRotation(cr).Reconcile(func(token *corev1.Secret) error {
+		if err := r.reconcileHubClusterSecretToken(cr, token); err != nil {
+			return err
+		}
+		registered = true
+		return nil
+	})
+	setTokenRotationStatus(cr, status)
+	return registered, err
+}
+
+// reconcileHubClusterSecretToken will ensure the cluster Secret with the token of the given token Secret is present on
+// the hub. The previous token is only revoked once the cluster Secret has been updated.
+func (r *ReconcileArgoCDAgent) reconcileHubClusterSecretToken(cr *argoprojv1a1.ArgoCDAgent, token *corev1.Secret) error {
+	hub, err := r.getHubClient(cr)
+	if err != nil {
+		return err
+	}
+
+	config, err := json.Marshal(map[string]interface{}{
+		"bearerToken": string(token.Data[corev1.ServiceAccountTokenKey]),
+		"tlsClientConfig": map[string]interface{}{
+			"caData":   token.Data[corev1.ServiceAccountRootCAKey],
+			"insecure": false,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	secret := newHubClusterSecret(cr)
+	secret.Data = map[string][]byte{
+		"config": config,
+		"name":   []byte(getAgentClusterName(cr)),
+		"server": []byte(cr.Spec.Hub.Server),
+	}
+
+	existing := &corev1.Secret{}
+	if err := argoutil.FetchObject(hub, secret.Namespace, secret.Name, existing); err != nil {
+		if !errors.IsNotFound(err) {
+			return err
+		}
+		log.Info(fmt.Sprintf("registering cluster %s with hub namespace %s", getAgentClusterName(cr), secret.Namespace))
+		return hub.Create(context.TODO(), secret)
+	}
+
+	if !reflect.DeepEqual(existing.Data, secret.Data) {
+		existing.Data = secret.Data
+		return hub.Update(context.TODO(), existing)
+	}
+	return nil
+}
+
+// deleteHubClusterSecret will remove the cluster Secret for the given ArgoCDAgent from the hub.
+func (r *ReconcileArgoCDAgent) deleteHubClusterSecret(cr *argoprojv1a1.ArgoCDAgent) error {
+	hub, err := r.getHubClient(cr)
+	if err != nil {
+		// The hub cannot be reached, there is nothing that can be cleaned up there.
+		log.Error(err, "unable to remove cluster from hub", "cluster", getAgentClusterName(cr))
+		return nil
+	}
+
+	secret := newHubClusterSecret(cr)
+	if err := hub.Delete(context.TODO(), secret); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
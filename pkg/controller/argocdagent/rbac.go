@@ -0,0 +1,221 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocdagent
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/pkg/controller/argoutil"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// getAgentControllerPolicyRules returns the policy rules for the Application Controller of an ArgoCDAgent.
+// The controller manages resources across the whole cluster, but is not allowed to escalate its privileges,
+// impersonate other users or access non-resource URLs.
+func getAgentControllerPolicyRules() []v1.PolicyRule {
+	return []v1.PolicyRule{
+		{
+			APIGroups: []string{"*"},
+			Resources: []string{"*"},
+			Verbs:     []string{"get", "list", "watch", "create", "update", "patch", "delete", "deletecollection"},
+		},
+	}
+}
+
+// getAgentHubPolicyRules returns the policy rules for the ServiceAccount whose token is shipped to the hub. The
+// hub only reads the resources of this cluster, the changes are applied by the Application Controller of the agent.
+func getAgentHubPolicyRules() []v1.PolicyRule {
+	return []v1.PolicyRule{
+		{
+			APIGroups: []string{"*"},
+			Resources: []string{"*"},
+			Verbs:     []string{"get", "list", "watch"},
+		},
+	}
+}
+
+// reconcileServiceAccount will ensure the ServiceAccount for the given component of the ArgoCDAgent is present.
+func (r *ReconcileArgoCDAgent) reconcileServiceAccount(component string, cr *argoprojv1a1.ArgoCDAgent) error {
+	sa := &corev1.ServiceAccount{
+		ObjectMeta: agentObjectMeta(nameWithSuffix(component, cr), component, cr),
+	}
+	if argoutil.IsObjectFound(r.client, sa.Namespace, sa.Name, sa) {
+		return nil // ServiceAccount found, do nothing
+	}
+
+	if err := controllerutil.SetControllerReference(cr, sa, r.scheme); err != nil {
+		return err
+	}
+	return r.client.Create(context.TODO(), sa)
+}
+
+// newServiceAccountTokenRotation returns the rotation of the token Secrets for the ServiceAccount used by the hub.
+func (r *ReconcileArgoCDAgent) newServiceAccountTokenRotation(cr *argoprojv1a1.ArgoCDAgent) *argoutil.TokenRotation {
+	meta := agentObjectMeta(nameWithSuffix(agentHubComponent+"-token", cr), agentHubComponent, cr)
+	return &argoutil.TokenRotation{
+		Client:          r.client,
+		Interval:        getTokenRotationInterval(cr),
+		Labels:          meta.Labels,
+		Name:            meta.Name,
+		Namespace:       meta.Namespace,
+		RevocationDelay: argoutil.DefaultTokenRevocationDelay,
+		ServiceAccount:  nameWithSuffix(agentHubComponent, cr),
+		SetOwner: func(secret *corev1.Secret) error {
+			return controllerutil.SetControllerReference(cr, secret, r.scheme)
+		},
+	}
+}
+
+// getTokenRotationInterval returns the interval at which the ServiceAccount token of the given ArgoCDAgent is
+// regenerated, or zero when the token is not rotated.
+func getTokenRotationInterval(cr *argoprojv1a1.ArgoCDAgent) time.Duration {
+	if cr.Spec.Hub.TokenRotationInterval == nil || cr.Spec.Hub.TokenRotationInterval.Duration < 0 {
+		return 0
+	}
+	return cr.Spec.Hub.TokenRotationInterval.Duration
+}
+
+// getTokenRotationDelay returns how long to wait before the ServiceAccount token of the given ArgoCDAgent is due to
+// be regenerated or the previous token is due to be revoked, or zero when neither is pending.
+func getTokenRotationDelay(cr *argoprojv1a1.ArgoCDAgent) time.Duration {
+	var delay time.Duration
+	for _, at := range []*metav1.Time{cr.Status.NextTokenRotation, cr.Status.TokenRevocation} {
+		if at == nil {
+			continue
+		}
+		d := time.Until(at.Time)
+		if d < time.Second {
+			d = time.Second
+		}
+		if delay == 0 || d < delay {
+			delay = d
+		}
+	}
+	return delay
+}
+
+// setTokenRotationStatus will record the given state of the token rotation in the status of the ArgoCDAgent.
+func setTokenRotationStatus(cr *argoprojv1a1.ArgoCDAgent, status *argoutil.TokenRotationStatus) {
+	if status == nil {
+		return
+	}
+	cr.Status.TokenRevocation = status.Revocation
+	if getTokenRotationInterval(cr) == 0 {
+		cr.Status.LastTokenRotation = nil
+		cr.Status.NextTokenRotation = nil
+		return
+	}
+	cr.Status.LastTokenRotation = status.Last
+	cr.Status.NextTokenRotation = status.Next
+}
+
+// reconcileClusterRole will ensure the ClusterRole for the given component of the ArgoCDAgent is present.
+func (r *ReconcileArgoCDAgent) reconcileClusterRole(component string, rules []v1.PolicyRule, cr *argoprojv1a1.ArgoCDAgent) error {
+	role := &v1.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   agentClusterResourceName(component, cr),
+			Labels: argoutil.DefaultLabels(agentClusterResourceName(component, cr)),
+		},
+		Rules: rules,
+	}
+
+	existing := &v1.ClusterRole{}
+	if err := argoutil.FetchObject(r.client, "", role.Name, existing); err != nil {
+		if !errors.IsNotFound(err) {
+			return err
+		}
+		return r.client.Create(context.TODO(), role)
+	}
+
+	if !reflect.DeepEqual(existing.Rules, role.Rules) {
+		existing.Rules = role.Rules
+		return r.client.Update(context.TODO(), existing)
+	}
+	return nil
+}
+
+// reconcileClusterRoleBinding will ensure the ClusterRoleBinding for the given component of the ArgoCDAgent is
+// present and binds the ClusterRole of the component to its ServiceAccount.
+func (r *ReconcileArgoCDAgent) reconcileClusterRoleBinding(component string, cr *argoprojv1a1.ArgoCDAgent) error {
+	binding := &v1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   agentClusterResourceName(component, cr),
+			Labels: argoutil.DefaultLabels(agentClusterResourceName(component, cr)),
+		},
+		RoleRef: v1.RoleRef{
+			APIGroup: v1.GroupName,
+			Kind:     "ClusterRole",
+			Name:     agentClusterResourceName(component, cr),
+		},
+		Subjects: []v1.Subject{{
+			Kind:      v1.ServiceAccountKind,
+			Name:      nameWithSuffix(component, cr),
+			Namespace: cr.Namespace,
+		}},
+	}
+
+	existing := &v1.ClusterRoleBinding{}
+	if err := argoutil.FetchObject(r.client, "", binding.Name, existing); err != nil {
+		if !errors.IsNotFound(err) {
+			return err
+		}
+		return r.client.Create(context.TODO(), binding)
+	}
+
+	if !reflect.DeepEqual(existing.RoleRef, binding.RoleRef) {
+		// The RoleRef of a binding cannot be changed, the binding is recreated instead.
+		if err := r.client.Delete(context.TODO(), existing); err != nil {
+			return err
+		}
+		return r.client.Create(context.TODO(), binding)
+	}
+
+	if !reflect.DeepEqual(existing.Subjects, binding.Subjects) {
+		existing.Subjects = binding.Subjects
+		return r.client.Update(context.TODO(), existing)
+	}
+	return nil
+}
+
+// deleteClusterRBAC will delete the cluster-scoped RBAC resources of the given ArgoCDAgent.
+func (r *ReconcileArgoCDAgent) deleteClusterRBAC(cr *argoprojv1a1.ArgoCDAgent) error {
+	for _, component := range []string{agentControllerComponent, agentHubComponent} {
+		binding := &v1.ClusterRoleBinding{}
+		if err := argoutil.FetchObject(r.client, "", agentClusterResourceName(component, cr), binding); err == nil {
+			if err := r.client.Delete(context.TODO(), binding); err != nil && !errors.IsNotFound(err) {
+				return err
+			}
+		} else if !errors.IsNotFound(err) {
+			return err
+		}
+
+		role := &v1.ClusterRole{}
+		if err := argoutil.FetchObject(r.client, "", agentClusterResourceName(component, cr), role); err == nil {
+			if err := r.client.Delete(context.TODO(), role); err != nil && !errors.IsNotFound(err) {
+				return err
+			}
+		} else if !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
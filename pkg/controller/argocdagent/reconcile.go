@@ -0,0 +1,175 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocdagent
+
+import (
+	"context"
+	"reflect"
+
+	argoproj "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/pkg/controller/argoutil"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	// agentControllerComponent is the component name of the Application Controller of an ArgoCDAgent.
+	agentControllerComponent = "argocd-application-controller"
+
+	// agentHubComponent is the component name of the read-only ServiceAccount used by the hub to access the
+	// cluster of an ArgoCDAgent.
+	agentHubComponent = "argocd-hub"
+
+	// agentMinimumVersion is the first version of Argo CD that can verify the TLS certificate of the hub Repo Server.
+	agentMinimumVersion = "v2.1.0"
+
+	// agentRepoServerTLSPath is the path the Application Controller reads the CA certificate of the Repo Server from.
+	agentRepoServerTLSPath = "/app/config/controller/tls"
+)
+
+// reconcileArgoCDAgentResources will reconcile all ArgoCDAgent resources for the give CR.
+func (r *ReconcileArgoCDAgent) reconcileArgoCDAgentResources(cr *argoprojv1a1.ArgoCDAgent) error {
+	observed := cr.Status.DeepCopy()
+
+	if err := validateArgoCDAgent(cr); err != nil {
+		// The agent cannot be fixed by retrying, wait for the ArgoCDAgent to be updated.
+		log.Error(err, "invalid ArgoCDAgent", "name", cr.Name, "namespace", cr.Namespace)
+		return r.reconcileStatus(cr, observed, "Failed", cr.Status.Registered)
+	}
+
+	if err := r.reconcileServiceAccount(agentControllerComponent, cr); err != nil {
+		return err
+	}
+
+	if err := r.reconcileClusterRole(agentControllerComponent, getAgentControllerPolicyRules(), cr); err != nil {
+		return err
+	}
+
+	if err := r.reconcileClusterRoleBinding(agentControllerComponent, cr); err != nil {
+		return err
+	}
+
+	if err := r.reconcileServiceAccount(agentHubComponent, cr); err != nil {
+		return err
+	}
+
+	if err := r.reconcileClusterRole(agentHubComponent, getAgentHubPolicyRules(), cr); err != nil {
+		return err
+	}
+
+	if err := r.reconcileClusterRoleBinding(agentHubComponent, cr); err != nil {
+		return err
+	}
+
+	if err := r.reconcileConfigMap(cr); err != nil {
+		return err
+	}
+
+	if err := r.reconcileSecret(cr); err != nil {
+		return err
+	}
+
+	if err := r.reconcileDefaultProject(cr); err != nil {
+		return err
+	}
+
+	if err := r.reconcileRedisDeployment(cr); err != nil {
+		return err
+	}
+
+	if err := r.reconcileRedisService(cr); err != nil {
+		return err
+	}
+
+	if err := r.reconcileApplicationControllerStatefulSet(cr); err != nil {
+		return err
+	}
+
+	registered, err := r.reconcileHubClusterSecret(cr)
+	if err != nil {
+		if statusErr := r.reconcileStatus(cr, observed, "Failed", false); statusErr != nil {
+			return statusErr
+		}
+		return err
+	}
+
+	phase := "Pending"
+	ss := newApplicationControllerStatefulSet(cr)
+	if registered && argoutil.IsObjectFound(r.client, ss.Namespace, ss.Name, ss) && ss.Status.ReadyReplicas == *ss.Spec.Replicas {
+		phase = "Available"
+	}
+	return r.reconcileStatus(cr, observed, phase, registered)
+}
+
+// reconcileStatus will ensure that the status of the given ArgoCDAgent is up to date, compared to the observed status.
+func (r *ReconcileArgoCDAgent) reconcileStatus(cr *argoprojv1a1.ArgoCDAgent, observed *argoprojv1a1.ArgoCDAgentStatus, phase string, registered bool) error {
+	cr.Status.Phase = phase
+	cr.Status.Registered = registered
+	if !reflect.DeepEqual(*observed, cr.Status) {
+		return r.client.Status().Update(context.TODO(), cr)
+	}
+	return nil
+}
+
+// deleteAgentResources will delete the resources of the given ArgoCDAgent that are not garbage collected.
+func (r *ReconcileArgoCDAgent) deleteAgentResources(cr *argoprojv1a1.ArgoCDAgent) error {
+	if err := r.deleteHubClusterSecret(cr); err != nil {
+		return err
+	}
+	return r.deleteClusterRBAC(cr)
+}
+
+// watchArgoCDAgentOwnedResource will register a Watch for a reource owned by an ArgoCDAgent.
+func watchArgoCDAgentOwnedResource(c controller.Controller, obj runtime.Object) error {
+	return c.Watch(&source.Kind{Type: obj}, &handler.EnqueueRequestForOwner{
+		IsController: true,
+		OwnerType:    &argoproj.ArgoCDAgent{},
+	})
+}
+
+// watchArgoCDAgentResources will register Watches for each of the supported Resources.
+func watchArgoCDAgentResources(c controller.Controller) error {
+	// Watch for changes to primary resource ArgoCDAgent
+	if err := c.Watch(&source.Kind{Type: &argoproj.ArgoCDAgent{}}, &handler.EnqueueRequestForObject{}); err != nil {
+		return err
+	}
+
+	// Watch for changes to Deployment sub-resources owned by ArgoCDAgent instances.
+	if err := watchArgoCDAgentOwnedResource(c, &appsv1.Deployment{}); err != nil {
+		return err
+	}
+
+	// Watch for changes to Secret sub-resources owned by ArgoCDAgent instances.
+	if err := watchArgoCDAgentOwnedResource(c, &corev1.Secret{}); err != nil {
+		return err
+	}
+
+	// Watch for changes to Service sub-resources owned by ArgoCDAgent instances.
+	if err := watchArgoCDAgentOwnedResource(c, &corev1.Service{}); err != nil {
+		return err
+	}
+
+	// Watch for changes to StatefulSet sub-resources owned by ArgoCDAgent instances.
+	if err := watchArgoCDAgentOwnedResource(c, &appsv1.StatefulSet{}); err != nil {
+		return err
+	}
+
+	return nil
+}
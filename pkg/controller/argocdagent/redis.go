@@ -0,0 +1,136 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocdagent
+
+import (
+	"context"
+	"reflect"
+
+	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
+	"github.com/argoproj-labs/argocd-operator/pkg/controller/argoutil"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// newRedisDeployment returns the desired Redis Deployment for the given ArgoCDAgent.
+func newRedisDeployment(cr *argoprojv1a1.ArgoCDAgent) *appsv1.Deployment {
+	name := nameWithSuffix("redis", cr)
+	deploy := &appsv1.Deployment{
+		ObjectMeta: agentObjectMeta(name, "redis", cr),
+		Spec: appsv1.DeploymentSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					common.ArgoCDKeyName: name,
+				},
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						common.ArgoCDKeyName: name,
+					},
+				},
+			},
+		},
+	}
+
+	podSpec := &deploy.Spec.Template.Spec
+	podSpec.Containers = []corev1.Container{{
+		Args: []string{
+			"--save",
+			"",
+			"--appendonly",
+			"no",
+		},
+		Image:           getAgentRedisImage(cr),
+		ImagePullPolicy: corev1.PullAlways,
+		Name:            "redis",
+		Ports: []corev1.ContainerPort{
+			{
+				ContainerPort: common.ArgoCDDefaultRedisPort,
+			},
+		},
+	}}
+	if cr.Spec.Redis.Resources != nil {
+		podSpec.Containers[0].Resources = *cr.Spec.Redis.Resources
+	}
+	podSpec.AutomountServiceAccountToken = cr.Spec.Redis.AutomountServiceAccountToken
+	if podSpec.AutomountServiceAccountToken == nil {
+		podSpec.AutomountServiceAccountToken = boolPtr(false)
+	}
+	return deploy
+}
+
+// reconcileRedisDeployment will ensure the Deployment resource is present for the Redis server of the agent.
+func (r *ReconcileArgoCDAgent) reconcileRedisDeployment(cr *argoprojv1a1.ArgoCDAgent) error {
+	deploy := newRedisDeployment(cr)
+
+	existing := &appsv1.Deployment{}
+	if argoutil.IsObjectFound(r.client, deploy.Namespace, deploy.Name, existing) {
+		changed := false
+		if existing.Spec.Template.Spec.Containers[0].Image != deploy.Spec.Template.Spec.Containers[0].Image {
+			existing.Spec.Template.Spec.Containers[0].Image = deploy.Spec.Template.Spec.Containers[0].Image
+			changed = true
+		}
+		if !reflect.DeepEqual(existing.Spec.Template.Spec.Containers[0].Resources, deploy.Spec.Template.Spec.Containers[0].Resources) {
+			existing.Spec.Template.Spec.Containers[0].Resources = deploy.Spec.Template.Spec.Containers[0].Resources
+			changed = true
+		}
+		if !reflect.DeepEqual(existing.Spec.Template.Spec.AutomountServiceAccountToken, deploy.Spec.Template.Spec.AutomountServiceAccountToken) {
+			existing.Spec.Template.Spec.AutomountServiceAccountToken = deploy.Spec.Template.Spec.AutomountServiceAccountToken
+			changed = true
+		}
+		if changed {
+			return r.client.Update(context.TODO(), existing)
+		}
+		return nil // Deployment found with nothing to do, move along...
+	}
+
+	if err := controllerutil.SetControllerReference(cr, deploy, r.scheme); err != nil {
+		return err
+	}
+	return r.client.Create(context.TODO(), deploy)
+}
+
+// reconcileRedisService will ensure the Service resource is present for the Redis server of the agent.
+func (r *ReconcileArgoCDAgent) reconcileRedisService(cr *argoprojv1a1.ArgoCDAgent) error {
+	name := nameWithSuffix("redis", cr)
+	svc := &corev1.Service{
+		ObjectMeta: agentObjectMeta(name, "redis", cr),
+	}
+	if argoutil.IsObjectFound(r.client, svc.Namespace, svc.Name, svc) {
+		return nil // Service found, do nothing
+	}
+
+	svc.Spec.Selector = map[string]string{
+		common.ArgoCDKeyName: name,
+	}
+	svc.Spec.Ports = []corev1.ServicePort{
+		{
+			Name:       "tcp-redis",
+			Port:       common.ArgoCDDefaultRedisPort,
+			Protocol:   corev1.ProtocolTCP,
+			TargetPort: intstr.FromInt(common.ArgoCDDefaultRedisPort),
+		},
+	}
+
+	if err := controllerutil.SetControllerReference(cr, svc, r.scheme); err != nil {
+		return err
+	}
+	return r.client.Create(context.TODO(), svc)
+}
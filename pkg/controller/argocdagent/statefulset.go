@@ -0,0 +1,146 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocdagent
+
+import (
+	"context"
+	"reflect"
+
+	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
+	"github.com/argoproj-labs/argocd-operator/pkg/controller/argoutil"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// newApplicationControllerStatefulSet returns the desired Application Controller StatefulSet for the given ArgoCDAgent.
+func newApplicationControllerStatefulSet(cr *argoprojv1a1.ArgoCDAgent) *appsv1.StatefulSet {
+	var replicas int32 = 1
+	name := nameWithSuffix("application-controller", cr)
+	ss := &appsv1.StatefulSet{
+		ObjectMeta: agentObjectMeta(name, agentControllerComponent, cr),
+		Spec: appsv1.StatefulSetSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					common.ArgoCDKeyName: name,
+				},
+			},
+			ServiceName: name,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						common.ArgoCDKeyName: name,
+					},
+				},
+			},
+		},
+	}
+
+	probe := &corev1.Probe{
+		Handler: corev1.Handler{
+			HTTPGet: &corev1.HTTPGetAction{
+				Path: "/healthz",
+				Port: intstr.FromInt(8082),
+			},
+		},
+		InitialDelaySeconds: 5,
+		PeriodSeconds:       10,
+	}
+
+	podSpec := &ss.Spec.Template.Spec
+	podSpec.Containers = []corev1.Container{{
+		Command:         getAgentControllerCommand(cr),
+		Image:           getAgentControllerImage(cr),
+		ImagePullPolicy: corev1.PullAlways,
+		Name:            "argocd-application-controller",
+		LivenessProbe:   probe,
+		Ports: []corev1.ContainerPort{
+			{
+				ContainerPort: 8082,
+			},
+		},
+		ReadinessProbe: probe.DeepCopy(),
+		VolumeMounts: []corev1.VolumeMount{{
+			Name:      "repo-server-tls",
+			MountPath: agentRepoServerTLSPath,
+			ReadOnly:  true,
+		}},
+	}}
+	// The default mode is set explicitly, so that the volume is not changed by the API server defaults.
+	var defaultMode int32 = corev1.SecretVolumeSourceDefaultMode
+	podSpec.Volumes = []corev1.Volume{{
+		Name: "repo-server-tls",
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName:  cr.Spec.Hub.RepoServerCASecret,
+				DefaultMode: &defaultMode,
+				Items: []corev1.KeyToPath{{
+					Key:  corev1.ServiceAccountRootCAKey,
+					Path: corev1.ServiceAccountRootCAKey,
+				}},
+			},
+		},
+	}}
+	if cr.Spec.Controller.Resources != nil {
+		podSpec.Containers[0].Resources = *cr.Spec.Controller.Resources
+	}
+	podSpec.ServiceAccountName = nameWithSuffix(agentControllerComponent, cr)
+	return ss
+}
+
+// reconcileApplicationControllerStatefulSet will ensure the StatefulSet resource is present for the Application Controller of the agent.
+func (r *ReconcileArgoCDAgent) reconcileApplicationControllerStatefulSet(cr *argoprojv1a1.ArgoCDAgent) error {
+	ss := newApplicationControllerStatefulSet(cr)
+
+	existing := &appsv1.StatefulSet{}
+	if argoutil.IsObjectFound(r.client, ss.Namespace, ss.Name, existing) {
+		desired := ss.Spec.Template.Spec.Containers[0]
+		container := &existing.Spec.Template.Spec.Containers[0]
+		changed := false
+		if container.Image != desired.Image {
+			container.Image = desired.Image
+			changed = true
+		}
+		if !reflect.DeepEqual(container.Command, desired.Command) {
+			container.Command = desired.Command
+			changed = true
+		}
+		if !reflect.DeepEqual(container.Resources, desired.Resources) {
+			container.Resources = desired.Resources
+			changed = true
+		}
+		if !reflect.DeepEqual(container.VolumeMounts, desired.VolumeMounts) {
+			container.VolumeMounts = desired.VolumeMounts
+			changed = true
+		}
+		if !reflect.DeepEqual(existing.Spec.Template.Spec.Volumes, ss.Spec.Template.Spec.Volumes) {
+			existing.Spec.Template.Spec.Volumes = ss.Spec.Template.Spec.Volumes
+			changed = true
+		}
+		if changed {
+			return r.client.Update(context.TODO(), existing)
+		}
+		return nil // StatefulSet found with nothing to do, move along...
+	}
+
+	if err := controllerutil.SetControllerReference(cr, ss, r.scheme); err != nil {
+		return err
+	}
+	return r.client.Create(context.TODO(), ss)
+}
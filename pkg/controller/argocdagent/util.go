@@ -0,0 +1,145 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocdagent
+
+import (
+	"fmt"
+
+	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
+	"github.com/argoproj-labs/argocd-operator/pkg/controller/argoutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// agentClusterResourceName returns the name for a cluster-scoped resource of the given component of the ArgoCDAgent.
+func agentClusterResourceName(component string, cr *argoprojv1a1.ArgoCDAgent) string {
+	return fmt.Sprintf("%s-%s-%s", cr.Name, cr.Namespace, component)
+}
+
+// agentObjectMeta returns the ObjectMeta for a namespaced resource of the given ArgoCDAgent.
+func agentObjectMeta(name string, component string, cr *argoprojv1a1.ArgoCDAgent) metav1.ObjectMeta {
+	labels := argoutil.DefaultLabels(name)
+	labels[common.ArgoCDKeyComponent] = component
+	return metav1.ObjectMeta{
+		Name:      name,
+		Namespace: cr.Namespace,
+		Labels:    labels,
+	}
+}
+
+// boolPtr returns a pointer to the given bool value.
+func boolPtr(val bool) *bool {
+	return &val
+}
+
+// getAgentClusterName returns the name of the cluster as registered with the hub.
+func getAgentClusterName(cr *argoprojv1a1.ArgoCDAgent) string {
+	if cr.Spec.Hub.ClusterName != "" {
+		return cr.Spec.Hub.ClusterName
+	}
+	return cr.Name
+}
+
+// getAgentControllerCommand returns the command for the Application Controller of the given ArgoCDAgent.
+func getAgentControllerCommand(cr *argoprojv1a1.ArgoCDAgent) []string {
+	return []string{
+		"argocd-application-controller",
+		"--operation-processors", fmt.Sprint(getAgentOperationProcessors(cr)),
+		"--redis", getAgentRedisAddress(cr),
+		"--repo-server", cr.Spec.Hub.RepoServer,
+		"--repo-server-strict-tls",
+		"--status-processors", fmt.Sprint(getAgentStatusProcessors(cr)),
+	}
+}
+
+// getAgentControllerImage returns the container image for the Application Controller of the given ArgoCDAgent.
+func getAgentControllerImage(cr *argoprojv1a1.ArgoCDAgent) string {
+	img := cr.Spec.Image
+	if len(img) <= 0 {
+		img = common.ArgoCDDefaultArgoImage
+	}
+
+	tag := cr.Spec.Version
+	if len(tag) <= 0 {
+		tag = common.ArgoCDDefaultAgentVersion
+	}
+
+	return argoutil.CombineImageTag(img, tag)
+}
+
+// getAgentOperationProcessors returns the number of operation processors for the given ArgoCDAgent.
+func getAgentOperationProcessors(cr *argoprojv1a1.ArgoCDAgent) int32 {
+	op := common.ArgoCDDefaultServerOperationProcessors
+	if cr.Spec.Controller.Processors.Operation > op {
+		op = cr.Spec.Controller.Processors.Operation
+	}
+	return op
+}
+
+// getAgentRedisAddress returns the address of the Redis server of the given ArgoCDAgent.
+func getAgentRedisAddress(cr *argoprojv1a1.ArgoCDAgent) string {
+	return fmt.Sprintf("%s.%s.svc.cluster.local:%d", nameWithSuffix("redis", cr), cr.Namespace, common.ArgoCDDefaultRedisPort)
+}
+
+// getAgentRedisImage returns the container image for the Redis server of the given ArgoCDAgent.
+func getAgentRedisImage(cr *argoprojv1a1.ArgoCDAgent) string {
+	img := cr.Spec.Redis.Image
+	if len(img) <= 0 {
+		img = common.ArgoCDDefaultRedisImage
+	}
+
+	tag := cr.Spec.Redis.Version
+	if len(tag) <= 0 {
+		tag = common.ArgoCDDefaultRedisVersion
+	}
+
+	return argoutil.CombineImageTag(img, tag)
+}
+
+// getAgentStatusProcessors returns the number of status processors for the given ArgoCDAgent.
+func getAgentStatusProcessors(cr *argoprojv1a1.ArgoCDAgent) int32 {
+	sp := common.ArgoCDDefaultServerStatusProcessors
+	if cr.Spec.Controller.Processors.Status > sp {
+		sp = cr.Spec.Controller.Processors.Status
+	}
+	return sp
+}
+
+// validateArgoCDAgent will return an error when the given ArgoCDAgent cannot be reconciled.
+func validateArgoCDAgent(cr *argoprojv1a1.ArgoCDAgent) error {
+	if cr.Spec.Hub.RepoServerCASecret == "" {
+		return fmt.Errorf("spec.hub.repoServerCASecret must be set, the hub Repo Server is only reached over TLS")
+	}
+	if !argoutil.IsArgoCDVersionAtLeast(getAgentControllerImage(cr), agentMinimumVersion) {
+		return fmt.Errorf("the agent requires Argo CD %s or later to verify the TLS certificate of the hub Repo Server", agentMinimumVersion)
+	}
+	return nil
+}
+
+// nameWithSuffix returns the name of a resource of the given ArgoCDAgent with the given suffix.
+func nameWithSuffix(suffix string, cr *argoprojv1a1.ArgoCDAgent) string {
+	return fmt.Sprintf("%s-%s", cr.Name, suffix)
+}
+
+func removeString(slice []string, s string) []string {
+	var result []string
+	for _, item := range slice {
+		if item == s {
+			continue
+		}
+		result = append(result, item)
+	}
+	return result
+}
@@ -0,0 +1,171 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocdautocreate
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	argoproj "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+var log = logf.Log.WithName("controller_argocdautocreate")
+
+// Add creates a new controller that creates an ArgoCD in each namespace labeled for it, and adds it to the Manager.
+// The controller is only added when a default template is set in the environment of the operator.
+func Add(mgr manager.Manager) error {
+	ref := os.Getenv(common.ArgoCDAutoCreateTemplateEnvName)
+	if ref == "" {
+		return nil // Auto-creation is opt-in
+	}
+	template, err := parseTemplateRef(ref)
+	if err != nil {
+		return err
+	}
+	return add(mgr, newReconciler(mgr, template))
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager, template types.NamespacedName) reconcile.Reconciler {
+	return &ReconcileArgoCDAutoCreate{client: mgr.GetClient(), scheme: mgr.GetScheme(), template: template}
+}
+
+// add adds a new Controller to mgr with r as the reconcile.Reconciler
+func add(mgr manager.Manager, r reconcile.Reconciler) error {
+	// Create a new controller
+	c, err := controller.New("argocdautocreate-controller", mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+
+	// Watch for namespaces labeled for auto-creation
+	if err := c.Watch(&source.Kind{Type: &corev1.Namespace{}}, &handler.EnqueueRequestForObject{}, autoCreateNamespacePredicate()); err != nil {
+		return err
+	}
+
+	// Watch for deleted ArgoCD instances, to create them again while their namespace is labeled
+	return c.Watch(&source.Kind{Type: &argoproj.ArgoCD{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(func(o handler.MapObject) []reconcile.Request {
+			return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: o.Meta.GetNamespace()}}}
+		}),
+	}, predicate.Funcs{
+		CreateFunc:  func(e event.CreateEvent) bool { return false },
+		UpdateFunc:  func(e event.UpdateEvent) bool { return false },
+		DeleteFunc:  func(e event.DeleteEvent) bool { return true },
+		GenericFunc: func(e event.GenericEvent) bool { return false },
+	})
+}
+
+// blank assignment to verify that ReconcileArgoCDAutoCreate implements reconcile.Reconciler
+var _ reconcile.Reconciler = &ReconcileArgoCDAutoCreate{}
+
+// ReconcileArgoCDAutoCreate creates an ArgoCD from a template in each namespace labeled for it
+type ReconcileArgoCDAutoCreate struct {
+	// This client, initialized using mgr.Client() above, is a split client
+	// that reads objects from the cache and writes to the apiserver
+	client client.Client
+	scheme *runtime.Scheme
+
+	// template is the default template ConfigMap, other templates are looked up in its namespace.
+	template types.NamespacedName
+}
+
+// Reconcile creates an ArgoCD in the requested namespace when it is labeled for auto-creation and holds no ArgoCD
+// yet. An ArgoCD that already exists is never modified, so that the team owning the namespace can change it.
+func (r *ReconcileArgoCDAutoCreate) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	reqLogger := log.WithValues("Request.Name", request.Name)
+
+	ns := &corev1.Namespace{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: request.Name}, ns); err != nil {
+		if errors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+	if !isAutoCreateNamespace(ns) || ns.GetDeletionTimestamp() != nil {
+		return reconcile.Result{}, nil
+	}
+
+	argocds := &argoproj.ArgoCDList{}
+	if err := r.client.List(context.TODO(), argocds, client.InNamespace(ns.Name)); err != nil {
+		return reconcile.Result{}, err
+	}
+	if len(argocds.Items) > 0 {
+		return reconcile.Result{}, nil
+	}
+
+	cr, err := r.newArgoCDFromTemplate(ns)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	reqLogger.Info(fmt.Sprintf("creating ArgoCD %s from template %s", cr.Name, cr.Annotations[common.ArgoCDAutoCreateTemplateAnnotation]))
+	if err := r.client.Create(context.TODO(), cr); err != nil && !errors.IsAlreadyExists(err) {
+		return reconcile.Result{}, fmt.Errorf("failed to create ArgoCD %s in namespace %s: %w", cr.Name, ns.Name, err)
+	}
+	return reconcile.Result{}, nil
+}
+
+// parseTemplateRef will return the namespace and name of the template ConfigMap in the given namespace/name reference.
+func parseTemplateRef(ref string) (types.NamespacedName, error) {
+	parts := strings.Split(ref, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return types.NamespacedName{}, fmt.Errorf("invalid value %q for environment variable %s, must be namespace/name",
+			ref, common.ArgoCDAutoCreateTemplateEnvName)
+	}
+	return types.NamespacedName{Namespace: parts[0], Name: parts[1]}, nil
+}
+
+// isAutoCreateNamespace will return true if an ArgoCD should be created in the given namespace.
+func isAutoCreateNamespace(ns *corev1.Namespace) bool {
+	return ns.Labels[common.ArgoCDAutoCreateLabel] == "true"
+}
+
+// autoCreateNamespacePredicate will only let through the events of namespaces labeled for auto-creation.
+func autoCreateNamespacePredicate() predicate.Predicate {
+	return predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			ns, ok := e.Object.(*corev1.Namespace)
+			return ok && isAutoCreateNamespace(ns)
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			ns, ok := e.ObjectNew.(*corev1.Namespace)
+			return ok && isAutoCreateNamespace(ns)
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return false
+		},
+		GenericFunc: func(e event.GenericEvent) bool {
+			ns, ok := e.Object.(*corev1.Namespace)
+			return ok && isAutoCreateNamespace(ns)
+		},
+	}
+}
@@ -0,0 +1,212 @@
+package argocdautocreate
+
+import (
+	"context"
+	"testing"
+
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/argoproj-labs/argocd-operator/pkg/apis"
+	argoprojv1alpha1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
+)
+
+const (
+	testNamespace         = "team-a"
+	testTemplateNamespace = "argocd-operator"
+	testTemplateName      = "argocd-template"
+)
+
+const testTemplate = `
+apiVersion: argoproj.io/v1alpha1
+kind: ArgoCD
+metadata:
+  name: team
+  labels:
+    example.com/team: a
+spec:
+  server:
+    insecure: true
+status:
+  phase: Available
+`
+
+func makeTestNamespace(opts ...func(*corev1.Namespace)) *corev1.Namespace {
+	ns := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   testNamespace,
+			Labels: map[string]string{common.ArgoCDAutoCreateLabel: "true"},
+		},
+	}
+	for _, o := range opts {
+		o(ns)
+	}
+	return ns
+}
+
+func makeTestTemplate(name, data string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: testTemplateNamespace,
+		},
+		Data: map[string]string{common.ArgoCDKeyAutoCreateTemplate: data},
+	}
+}
+
+func makeTestReconciler(t *testing.T, objs ...runtime.Object) *ReconcileArgoCDAutoCreate {
+	s := scheme.Scheme
+	assert.NilError(t, apis.AddToScheme(s))
+
+	return &ReconcileArgoCDAutoCreate{
+		client:   fake.NewFakeClientWithScheme(s, objs...),
+		scheme:   s,
+		template: types.NamespacedName{Namespace: testTemplateNamespace, Name: testTemplateName},
+	}
+}
+
+func reconcileNamespace(t *testing.T, r *ReconcileArgoCDAutoCreate) []argoprojv1alpha1.ArgoCD {
+	_, err := r.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: testNamespace}})
+	assert.NilError(t, err)
+
+	list := &argoprojv1alpha1.ArgoCDList{}
+	assert.NilError(t, r.client.List(context.TODO(), list, client.InNamespace(testNamespace)))
+	return list.Items
+}
+
+func TestReconcileArgoCDAutoCreate_createsFromTemplate(t *testing.T) {
+	r := makeTestReconciler(t, makeTestNamespace(), makeTestTemplate(testTemplateName, testTemplate))
+
+	argocds := reconcileNamespace(t, r)
+	assert.Equal(t, len(argocds), 1)
+	assert.Equal(t, argocds[0].Name, "team")
+	assert.Equal(t, argocds[0].Labels["example.com/team"], "a")
+	assert.Equal(t, argocds[0].Annotations[common.ArgoCDAutoCreateTemplateAnnotation], testTemplateName)
+	assert.Assert(t, argocds[0].Spec.Server.Insecure)
+	assert.Equal(t, argocds[0].Status.Phase, "")
+}
+
+func TestReconcileArgoCDAutoCreate_emptyTemplate(t *testing.T) {
+	r := makeTestReconciler(t, makeTestNamespace(), makeTestTemplate(testTemplateName, ""))
+
+	argocds := reconcileNamespace(t, r)
+	assert.Equal(t, len(argocds), 1)
+	assert.Equal(t, argocds[0].Name, common.ArgoCDAppName)
+}
+
+func TestReconcileArgoCDAutoCreate_selectedTemplate(t *testing.T) {
+	ns := makeTestNamespace(func(ns *corev1.Namespace) {
+		ns.Annotations = map[string]string{common.ArgoCDAutoCreateTemplateAnnotation: "small"}
+	})
+	r := makeTestReconciler(t, ns,
+		makeTestTemplate(testTemplateName, testTemplate),
+		makeTestTemplate("small", "metadata:\n  name: small\n"))
+
+	argocds := reconcileNamespace(t, r)
+	assert.Equal(t, len(argocds), 1)
+	assert.Equal(t, argocds[0].Name, "small")
+}
+
+func TestReconcileArgoCDAutoCreate_skipped(t *testing.T) {
+	existing := &argoprojv1alpha1.ArgoCD{
+		ObjectMeta: metav1.ObjectMeta{Name: "existing", Namespace: testNamespace},
+	}
+
+	tests := []struct {
+		name string
+		objs []runtime.Object
+		want int
+	}{
+		{
+			name: "namespace not labeled",
+			objs: []runtime.Object{makeTestNamespace(func(ns *corev1.Namespace) { ns.Labels = nil })},
+		},
+		{
+			name: "namespace labeled false",
+			objs: []runtime.Object{makeTestNamespace(func(ns *corev1.Namespace) {
+				ns.Labels[common.ArgoCDAutoCreateLabel] = "false"
+			})},
+		},
+		{
+			name: "namespace terminating",
+			objs: []runtime.Object{makeTestNamespace(func(ns *corev1.Namespace) {
+				now := metav1.Now()
+				ns.DeletionTimestamp = &now
+			})},
+		},
+		{
+			name: "namespace not found",
+		},
+		{
+			name: "ArgoCD exists",
+			objs: []runtime.Object{makeTestNamespace(), existing},
+			want: 1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			objs := append(test.objs, makeTestTemplate(testTemplateName, testTemplate))
+			r := makeTestReconciler(t, objs...)
+
+			argocds := reconcileNamespace(t, r)
+			assert.Equal(t, len(argocds), test.want)
+			for _, a := range argocds {
+				assert.Equal(t, a.Name, existing.Name)
+			}
+		})
+	}
+}
+
+func TestReconcileArgoCDAutoCreate_invalidTemplate(t *testing.T) {
+	tests := []struct {
+		name string
+		objs []runtime.Object
+	}{
+		{
+			name: "template not found",
+		},
+		{
+			name: "template key missing",
+			objs: []runtime.Object{&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: testTemplateName, Namespace: testTemplateNamespace},
+			}},
+		},
+		{
+			name: "wrong kind",
+			objs: []runtime.Object{makeTestTemplate(testTemplateName, "kind: ConfigMap\n")},
+		},
+		{
+			name: "malformed manifest",
+			objs: []runtime.Object{makeTestTemplate(testTemplateName, "spec: [\n")},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := makeTestReconciler(t, append(test.objs, makeTestNamespace())...)
+
+			_, err := r.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: testNamespace}})
+			assert.Assert(t, err != nil)
+		})
+	}
+}
+
+func TestParseTemplateRef(t *testing.T) {
+	ref, err := parseTemplateRef("argocd-operator/argocd-template")
+	assert.NilError(t, err)
+	assert.Equal(t, ref, types.NamespacedName{Namespace: "argocd-operator", Name: "argocd-template"})
+
+	for _, invalid := range []string{"argocd-template", "/argocd-template", "argocd-operator/", "a/b/c"} {
+		_, err := parseTemplateRef(invalid)
+		assert.Assert(t, err != nil, invalid)
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocdautocreate
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	argoproj "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
+	"github.com/argoproj-labs/argocd-operator/pkg/controller/argoutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// getTemplateRef will return the template ConfigMap for the given namespace. A namespace may select another template
+// by name, which is looked up in the namespace of the default template so that only the templates provided by the
+// cluster administrators can be used.
+func (r *ReconcileArgoCDAutoCreate) getTemplateRef(ns *corev1.Namespace) types.NamespacedName {
+	ref := r.template
+	if name := ns.Annotations[common.ArgoCDAutoCreateTemplateAnnotation]; name != "" {
+		ref.Name = name
+	}
+	return ref
+}
+
+// newArgoCDFromTemplate will return a new ArgoCD for the given namespace, from the ArgoCD manifest held by its template
+// ConfigMap. Only the name, labels, annotations and spec of the manifest are used.
+func (r *ReconcileArgoCDAutoCreate) newArgoCDFromTemplate(ns *corev1.Namespace) (*argoproj.ArgoCD, error) {
+	ref := r.getTemplateRef(ns)
+	cm := &corev1.ConfigMap{}
+	if err := argoutil.FetchObject(r.client, ref.Namespace, ref.Name, cm); err != nil {
+		return nil, fmt.Errorf("failed to get template %s for namespace %s: %w", ref, ns.Name, err)
+	}
+	data, ok := cm.Data[common.ArgoCDKeyAutoCreateTemplate]
+	if !ok {
+		return nil, fmt.Errorf("template %s has no %s key", ref, common.ArgoCDKeyAutoCreateTemplate)
+	}
+
+	template := &argoproj.ArgoCD{}
+	if err := yaml.NewYAMLOrJSONDecoder(strings.NewReader(data), len(data)+1).Decode(template); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("invalid template %s: %w", ref, err)
+	}
+	if template.Kind != "" && template.Kind != "ArgoCD" {
+		return nil, fmt.Errorf("invalid template %s: kind must be ArgoCD, not %s", ref, template.Kind)
+	}
+
+	name := template.Name
+	if name == "" {
+		name = common.ArgoCDAppName
+	}
+	return &argoproj.ArgoCD{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: ns.Name,
+			Labels:    template.Labels,
+			Annotations: argoutil.AppendStringMap(template.Annotations, map[string]string{
+				common.ArgoCDAutoCreateTemplateAnnotation: ref.Name,
+			}),
+		},
+		Spec: template.Spec,
+	}, nil
+}
@@ -36,12 +36,12 @@ func Add(mgr manager.Manager) error {
 }
 
 // newReconciler returns a new reconcile.Reconciler
-func newReconciler(mgr manager.Manager) reconcile.Reconciler {
-	return &ReconcileArgoCDExport{client: mgr.GetClient(), scheme: mgr.GetScheme()}
+func newReconciler(mgr manager.Manager) *ReconcileArgoCDExport {
+	return &ReconcileArgoCDExport{client: mgr.GetClient(), reader: mgr.GetAPIReader(), scheme: mgr.GetScheme()}
 }
 
 // add adds a new Controller to mgr with r as the reconcile.Reconciler
-func add(mgr manager.Manager, r reconcile.Reconciler) error {
+func add(mgr manager.Manager, r *ReconcileArgoCDExport) error {
 	// Create a new controller
 	c, err := controller.New("argocdexport-controller", mgr, controller.Options{Reconciler: r})
 	if err != nil {
@@ -49,7 +49,7 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 	}
 
 	// Register watches for all controller resources
-	if err := watchArgoCDExportResources(c); err != nil {
+	if err := watchArgoCDExportResources(c, r); err != nil {
 		return err
 	}
 
@@ -64,6 +64,9 @@ type ReconcileArgoCDExport struct {
 	// This client, initialized using mgr.Client() above, is a split client
 	// that reads objects from the cache and writes to the apiserver
 	client client.Client
+	// This reader, initialized using mgr.GetAPIReader() above, reads objects directly from the apiserver
+	// and is used for objects outside of the namespaces watched by the cache.
+	reader client.Reader
 	scheme *runtime.Scheme
 }
 
@@ -95,5 +98,10 @@ func (r *ReconcileArgoCDExport) Reconcile(request reconcile.Request) (reconcile.
 		return reconcile.Result{}, err
 	}
 
+	if isSnapshotPending(export) {
+		// VolumeSnapshots are not watched, check on them again until they are ready to use.
+		return reconcile.Result{RequeueAfter: snapshotRequeueInterval}, nil
+	}
+
 	return reconcile.Result{}, nil
 }
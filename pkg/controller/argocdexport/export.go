@@ -16,13 +16,22 @@ package argocdexport
 
 import (
 	"context"
+	"fmt"
+	"reflect"
+	"strings"
 
 	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
 	argoprojv1alpha1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
 	"github.com/argoproj-labs/argocd-operator/pkg/common"
 	"github.com/argoproj-labs/argocd-operator/pkg/controller/argoutil"
 	"github.com/sethvargo/go-password/password"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
 // generateBackupKey will generate and return the backup key for the export process.
@@ -58,8 +67,122 @@ func (r *ReconcileArgoCDExport) reconcileExport(cr *argoprojv1a1.ArgoCDExport) e
 	return nil
 }
 
+// isExportNamespaceAllowed will return true if the given Secret allows ArgoCDExports in the given namespace to use it.
+func isExportNamespaceAllowed(secret *corev1.Secret, namespace string) bool {
+	for _, ns := range strings.Split(secret.Annotations[common.ArgoCDExportNamespacesAnnotation], ",") {
+		if strings.TrimSpace(ns) == namespace {
+			return true
+		}
+	}
+	return false
+}
+
+// reconcileRemoteExportSecret will ensure that a copy of the storage Secret from another namespace is present in the
+// namespace of the ArgoCDExport. The Secret in the other namespace must explicitly allow the ArgoCDExport namespace.
+func (r *ReconcileArgoCDExport) reconcileRemoteExportSecret(cr *argoprojv1a1.ArgoCDExport) error {
+	remote := &corev1.Secret{}
+	key := types.NamespacedName{Namespace: cr.Spec.Storage.SecretNamespace, Name: cr.Spec.Storage.SecretName}
+	if err := r.reader.Get(context.TODO(), key, remote); err != nil {
+		if errors.IsNotFound(err) {
+			if err := r.deleteRemoteExportSecretCopy(cr); err != nil {
+				return err
+			}
+		}
+		return fmt.Errorf("failed to get the export storage secret %s : %w", key, err)
+	}
+
+	if !isExportNamespaceAllowed(remote, cr.Namespace) {
+		// Access to the Secret has been revoked, or never granted, remove any copy of the credentials.
+		if err := r.deleteRemoteExportSecretCopy(cr); err != nil {
+			return err
+		}
+		return fmt.Errorf("export storage secret %s does not allow exports from namespace %s, see the %s annotation",
+			key, cr.Namespace, common.ArgoCDExportNamespacesAnnotation)
+	}
+
+	data := make(map[string][]byte, len(remote.Data)+1)
+	for k, v := range remote.Data {
+		data[k] = v
+	}
+
+	name := argoutil.FetchStorageSecretName(cr)
+	secret := argoutil.NewSecretWithName(cr.ObjectMeta, name)
+	found := argoutil.IsObjectFound(r.client, cr.Namespace, name, secret)
+
+	if len(data[common.ArgoCDKeyBackupKey]) <= 0 {
+		data[common.ArgoCDKeyBackupKey] = secret.Data[common.ArgoCDKeyBackupKey] // Keep a previously generated backup key.
+	}
+	if len(data[common.ArgoCDKeyBackupKey]) <= 0 {
+		backupKey, err := generateBackupKey()
+		if err != nil {
+			return err
+		}
+		data[common.ArgoCDKeyBackupKey] = backupKey
+	}
+
+	if found {
+		if !reflect.DeepEqual(secret.Data, data) {
+			secret.Data = data
+			return r.client.Update(context.TODO(), secret)
+		}
+		return nil
+	}
+
+	secret.Data = data
+
+	if err := controllerutil.SetControllerReference(cr, secret, r.scheme); err != nil {
+		return err
+	}
+	log.Info(fmt.Sprintf("copying export storage secret %s to namespace %s", key, cr.Namespace))
+	return r.client.Create(context.TODO(), secret)
+}
+
+// deleteRemoteExportSecretCopy will delete the copy of the storage Secret from another namespace that was made for the
+// given ArgoCDExport, if present.
+func (r *ReconcileArgoCDExport) deleteRemoteExportSecretCopy(cr *argoprojv1a1.ArgoCDExport) error {
+	name := argoutil.FetchStorageSecretName(cr)
+	secret := argoutil.NewSecretWithName(cr.ObjectMeta, name)
+	if !argoutil.IsObjectFound(r.client, cr.Namespace, name, secret) || !metav1.IsControlledBy(secret, cr) {
+		return nil
+	}
+
+	log.Info(fmt.Sprintf("deleting copy of export storage secret %s/%s from namespace %s",
+		cr.Spec.Storage.SecretNamespace, cr.Spec.Storage.SecretName, cr.Namespace))
+	if err := r.client.Delete(context.TODO(), secret); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// remoteExportSecretMapper maps a watch event on a storage Secret in another namespace back to the ArgoCDExports that
+// use it.
+func (r *ReconcileArgoCDExport) remoteExportSecretMapper(o handler.MapObject) []reconcile.Request {
+	var result = []reconcile.Request{}
+
+	exports := &argoprojv1a1.ArgoCDExportList{}
+	if err := r.client.List(context.TODO(), exports); err != nil {
+		log.Error(err, "unable to list ArgoCDExports for export storage secret")
+		return result
+	}
+
+	for _, export := range exports.Items {
+		if argoutil.IsStorageSecretRemote(&export) &&
+			export.Spec.Storage.SecretNamespace == o.Meta.GetNamespace() &&
+			export.Spec.Storage.SecretName == o.Meta.GetName() {
+			result = append(result, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: export.Namespace, Name: export.Name},
+			})
+		}
+	}
+	return result
+}
+
 // reconcileExportSecret will ensure that the Secret used for the export process is present.
 func (r *ReconcileArgoCDExport) reconcileExportSecret(cr *argoprojv1a1.ArgoCDExport) error {
+	if argoutil.IsStorageSecretRemote(cr) {
+		return r.reconcileRemoteExportSecret(cr)
+	}
+
 	name := argoutil.FetchStorageSecretName(cr)
 	secret := argoutil.NewSecretWithName(cr.ObjectMeta, name)
 	if argoutil.IsObjectFound(r.client, cr.Namespace, name, secret) {
@@ -0,0 +1,116 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocdexport
+
+import (
+	"context"
+	"testing"
+
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
+	"github.com/argoproj-labs/argocd-operator/pkg/controller/argoutil"
+)
+
+func makeTestRemoteExportSecret() *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "backup-credentials",
+			Namespace: "backups",
+			Annotations: map[string]string{
+				common.ArgoCDExportNamespacesAnnotation: "team-a, " + testNamespace,
+			},
+		},
+		Data: map[string][]byte{
+			"aws.access.key.id": []byte("key"),
+		},
+	}
+}
+
+func withRemoteExportSecret(e *argoprojv1a1.ArgoCDExport) {
+	e.Spec.Snapshot = nil
+	e.Spec.Storage = &argoprojv1a1.ArgoCDExportStorageSpec{
+		Backend:         "aws",
+		SecretName:      "backup-credentials",
+		SecretNamespace: "backups",
+	}
+}
+
+func TestReconcileArgoCDExport_reconcileExportSecret_remote(t *testing.T) {
+	cr := makeTestArgoCDExport(withRemoteExportSecret)
+	remote := makeTestRemoteExportSecret()
+	r := makeTestReconciler(t, cr, remote)
+
+	assert.NilError(t, r.reconcileExportSecret(cr))
+
+	secret := &corev1.Secret{}
+	key := types.NamespacedName{Namespace: testNamespace, Name: argoutil.FetchStorageSecretName(cr)}
+	assert.NilError(t, r.client.Get(context.TODO(), key, secret))
+	assert.DeepEqual(t, secret.Data["aws.access.key.id"], []byte("key"))
+	assert.Assert(t, len(secret.Data[common.ArgoCDKeyBackupKey]) > 0)
+	backupKey := secret.Data[common.ArgoCDKeyBackupKey]
+
+	// The generated backup key is kept when the copy is updated.
+	remote.Data["aws.access.key.id"] = []byte("rotated")
+	assert.NilError(t, r.client.Update(context.TODO(), remote))
+	assert.NilError(t, r.reconcileExportSecret(cr))
+	assert.NilError(t, r.client.Get(context.TODO(), key, secret))
+	assert.DeepEqual(t, secret.Data["aws.access.key.id"], []byte("rotated"))
+	assert.DeepEqual(t, secret.Data[common.ArgoCDKeyBackupKey], backupKey)
+
+	// Revoking access removes the copy.
+	remote.Annotations[common.ArgoCDExportNamespacesAnnotation] = "team-a"
+	assert.NilError(t, r.client.Update(context.TODO(), remote))
+	assert.ErrorContains(t, r.reconcileExportSecret(cr), "does not allow exports from namespace")
+	assert.Assert(t, !argoutil.IsObjectFound(r.client, testNamespace, key.Name, &corev1.Secret{}))
+}
+
+func TestReconcileArgoCDExport_reconcileExportSecret_remoteDeleted(t *testing.T) {
+	cr := makeTestArgoCDExport(withRemoteExportSecret)
+	remote := makeTestRemoteExportSecret()
+	r := makeTestReconciler(t, cr, remote)
+
+	assert.NilError(t, r.reconcileExportSecret(cr))
+	name := argoutil.FetchStorageSecretName(cr)
+	assert.Assert(t, argoutil.IsObjectFound(r.client, testNamespace, name, &corev1.Secret{}))
+
+	assert.NilError(t, r.client.Delete(context.TODO(), remote))
+	assert.ErrorContains(t, r.reconcileExportSecret(cr), "failed to get the export storage secret")
+	assert.Assert(t, !argoutil.IsObjectFound(r.client, testNamespace, name, &corev1.Secret{}))
+}
+
+func TestReconcileArgoCDExport_remoteExportSecretMapper(t *testing.T) {
+	remoteExport := makeTestArgoCDExport(withRemoteExportSecret)
+	localExport := makeTestArgoCDExport(func(e *argoprojv1a1.ArgoCDExport) {
+		e.Name = "local"
+	})
+	remote := makeTestRemoteExportSecret()
+	r := makeTestReconciler(t, remoteExport, localExport, remote)
+
+	got := r.remoteExportSecretMapper(handler.MapObject{Meta: remote, Object: remote})
+	assert.DeepEqual(t, got, []reconcile.Request{{
+		NamespacedName: types.NamespacedName{Namespace: testNamespace, Name: remoteExport.Name},
+	}})
+
+	other := makeTestRemoteExportSecret()
+	other.Name = "other"
+	assert.DeepEqual(t, r.remoteExportSecretMapper(handler.MapObject{Meta: other, Object: other}), []reconcile.Request{})
+}
@@ -73,14 +73,20 @@ func getArgoExportContainerEnv(cr *argoprojv1a1.ArgoCDExport) []corev1.EnvVar {
 
 // getArgoExportContainerImage will return the container image for ArgoCD.
 func getArgoExportContainerImage(cr *argoprojv1a1.ArgoCDExport) string {
+	defaultTag, defaultImg := false, false
 	img := cr.Spec.Image
 	if len(img) <= 0 {
 		img = common.ArgoCDDefaultExportJobImage
+		defaultImg = true
 	}
 
 	tag := cr.Spec.Version
 	if len(tag) <= 0 {
 		tag = common.ArgoCDDefaultExportJobVersion
+		defaultTag = true
+	}
+	if e := argoutil.GetDefaultImage(common.ArgoCDExportJobImageEnvName); e != "" && (defaultTag && defaultImg) {
+		return e
 	}
 
 	return argoutil.CombineImageTag(img, tag)
@@ -204,9 +210,13 @@ func (r *ReconcileArgoCDExport) reconcileCronJob(cr *argoprojv1a1.ArgoCDExport)
 	if argoutil.IsObjectFound(r.client, cr.Namespace, cj.Name, cj) {
 		if *cr.Spec.Schedule != cj.Spec.Schedule {
 			cj.Spec.Schedule = *cr.Spec.Schedule
-			return r.client.Update(context.TODO(), cj)
+			if err := r.client.Update(context.TODO(), cj); err != nil {
+				return err
+			}
 		}
-		return nil
+
+		// Snapshot the volumes once the export data of a scheduled Job has been written.
+		return r.reconcileScheduledVolumeSnapshots(cr, cj)
 	}
 
 	cj.Spec.Schedule = *cr.Spec.Schedule
@@ -231,6 +241,12 @@ func (r *ReconcileArgoCDExport) reconcileJob(cr *argoprojv1a1.ArgoCDExport) erro
 	job := newJob(cr)
 	if argoutil.IsObjectFound(r.client, cr.Namespace, job.Name, job) {
 		if job.Status.Succeeded > 0 && cr.Status.Phase != common.ArgoCDStatusCompleted {
+			// Snapshot the volumes once the export data has been written.
+			ready, err := r.reconcileVolumeSnapshots(cr, job.Name)
+			if err != nil || !ready {
+				return err
+			}
+
 			// Mark status Phase as Complete
 			cr.Status.Phase = common.ArgoCDStatusCompleted
 			return r.client.Status().Update(context.TODO(), cr)
@@ -51,7 +51,7 @@ func watchArgoCDExportOwnedResource(c controller.Controller, obj runtime.Object)
 }
 
 // watchArgoCDExportResources will register Watches for each of the supported Resources.
-func watchArgoCDExportResources(c controller.Controller) error {
+func watchArgoCDExportResources(c controller.Controller, r *ReconcileArgoCDExport) error {
 	// Watch for changes to primary resource ArgoCDExport
 	if err := c.Watch(&source.Kind{Type: &argoproj.ArgoCDExport{}}, &handler.EnqueueRequestForObject{}); err != nil {
 		return err
@@ -77,5 +77,12 @@ func watchArgoCDExportResources(c controller.Controller) error {
 		return err
 	}
 
+	// Watch for changes to storage Secrets in other namespaces, such as the revocation of access to them.
+	if err := c.Watch(&source.Kind{Type: &corev1.Secret{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(r.remoteExportSecretMapper),
+	}); err != nil {
+		return err
+	}
+
 	return nil
 }
@@ -0,0 +1,201 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocdexport
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
+	"github.com/argoproj-labs/argocd-operator/pkg/controller/argoutil"
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1b1 "k8s.io/api/batch/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// snapshotRequeueInterval is how long to wait before checking on VolumeSnapshots that are not yet ready to use.
+const snapshotRequeueInterval = 10 * time.Second
+
+// volumeSnapshotGVK is the GroupVersionKind of the CSI VolumeSnapshot resource.
+var volumeSnapshotGVK = schema.GroupVersionKind{
+	Group:   "snapshot.storage.k8s.io",
+	Version: "v1",
+	Kind:    "VolumeSnapshot",
+}
+
+// getSnapshotClaims will return the names of the PersistentVolumeClaims to snapshot for the given ArgoCDExport.
+func getSnapshotClaims(cr *argoprojv1a1.ArgoCDExport) []string {
+	claims := make([]string, 0)
+	if cr.Spec.Snapshot == nil {
+		return claims
+	}
+
+	if cr.Spec.Storage != nil && strings.ToLower(cr.Spec.Storage.Backend) == common.ArgoCDExportStorageBackendLocal {
+		claims = append(claims, cr.Name) // The export PVC shares the name of the ArgoCDExport.
+	}
+
+	for _, claim := range cr.Spec.Snapshot.Claims {
+		if !containsClaim(claims, claim) {
+			claims = append(claims, claim)
+		}
+	}
+	return claims
+}
+
+// containsClaim returns true if the given claim name is present in the list of claims.
+func containsClaim(claims []string, claim string) bool {
+	for _, c := range claims {
+		if c == claim {
+			return true
+		}
+	}
+	return false
+}
+
+// newVolumeSnapshot returns a new VolumeSnapshot of the given PersistentVolumeClaim, taken after the given export Job
+// of the given ArgoCDExport.
+func newVolumeSnapshot(cr *argoprojv1a1.ArgoCDExport, job string, claim string) *unstructured.Unstructured {
+	snapshot := &unstructured.Unstructured{}
+	snapshot.SetGroupVersionKind(volumeSnapshotGVK)
+	snapshot.SetName(fmt.Sprintf("%s-%s", job, claim))
+	snapshot.SetNamespace(cr.Namespace)
+	snapshot.SetLabels(argoutil.DefaultLabels(cr.Name))
+	return snapshot
+}
+
+// isSnapshotPending returns true if a VolumeSnapshot of the given ArgoCDExport is not yet ready to use and has not failed.
+func isSnapshotPending(cr *argoprojv1a1.ArgoCDExport) bool {
+	if cr.Spec.Snapshot == nil || cr.Status.Phase == common.ArgoCDStatusFailed {
+		return false
+	}
+
+	for _, snapshot := range cr.Status.Snapshots {
+		if !snapshot.ReadyToUse && snapshot.Error == "" {
+			return true
+		}
+	}
+	return false
+}
+
+// getLatestSucceededJob returns the most recent Job of the given CronJob that has succeeded, or nil if there is none.
+func (r *ReconcileArgoCDExport) getLatestSucceededJob(cj *batchv1b1.CronJob) (*batchv1.Job, error) {
+	list := &batchv1.JobList{}
+	if err := r.client.List(context.TODO(), list, client.InNamespace(cj.Namespace)); err != nil {
+		return nil, err
+	}
+
+	jobs := make([]batchv1.Job, 0)
+	for _, job := range list.Items {
+		if job.Status.Succeeded > 0 && metav1.IsControlledBy(&job, cj) {
+			jobs = append(jobs, job)
+		}
+	}
+	if len(jobs) == 0 {
+		return nil, nil
+	}
+
+	// The Jobs of a CronJob are created in order of their schedule.
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].CreationTimestamp.Before(&jobs[j].CreationTimestamp)
+	})
+	return &jobs[len(jobs)-1], nil
+}
+
+// reconcileScheduledVolumeSnapshots will ensure that the PersistentVolumeClaims of the given ArgoCDExport are
+// snapshot after the most recent succeeded Job of the given CronJob.
+func (r *ReconcileArgoCDExport) reconcileScheduledVolumeSnapshots(cr *argoprojv1a1.ArgoCDExport, cj *batchv1b1.CronJob) error {
+	if cr.Spec.Snapshot == nil {
+		return nil
+	}
+
+	job, err := r.getLatestSucceededJob(cj)
+	if err != nil || job == nil {
+		return err
+	}
+
+	_, err = r.reconcileVolumeSnapshots(cr, job.Name)
+	return err
+}
+
+// reconcileVolumeSnapshots will ensure that a VolumeSnapshot is present for each PersistentVolumeClaim of the
+// given ArgoCDExport, taken after the given export Job, and record their state in the ArgoCDExport status. Returns
+// true once every snapshot is ready to use.
+func (r *ReconcileArgoCDExport) reconcileVolumeSnapshots(cr *argoprojv1a1.ArgoCDExport, job string) (bool, error) {
+	var snapshots []argoprojv1a1.ArgoCDExportSnapshotStatus
+	ready := true
+	failed := false
+
+	for _, claim := range getSnapshotClaims(cr) {
+		snapshot := newVolumeSnapshot(cr, job, claim)
+		if !argoutil.IsObjectFound(r.client, cr.Namespace, snapshot.GetName(), snapshot) {
+			spec := map[string]interface{}{
+				"source": map[string]interface{}{
+					"persistentVolumeClaimName": claim,
+				},
+			}
+			if cr.Spec.Snapshot.VolumeSnapshotClassName != "" {
+				spec["volumeSnapshotClassName"] = cr.Spec.Snapshot.VolumeSnapshotClassName
+			}
+			snapshot.Object["spec"] = spec
+
+			if err := controllerutil.SetControllerReference(cr, snapshot, r.scheme); err != nil {
+				return false, err
+			}
+
+			log.Info(fmt.Sprintf("creating volume snapshot %s of claim %s", snapshot.GetName(), claim))
+			if err := r.client.Create(context.TODO(), snapshot); err != nil {
+				return false, err
+			}
+		}
+
+		status := argoprojv1a1.ArgoCDExportSnapshotStatus{
+			ClaimName: claim,
+			Name:      snapshot.GetName(),
+		}
+		status.ReadyToUse, _, _ = unstructured.NestedBool(snapshot.Object, "status", "readyToUse")
+		status.Error, _, _ = unstructured.NestedString(snapshot.Object, "status", "error", "message")
+
+		ready = ready && status.ReadyToUse
+		failed = failed || status.Error != ""
+		snapshots = append(snapshots, status)
+	}
+
+	changed := !reflect.DeepEqual(cr.Status.Snapshots, snapshots)
+	if failed && cr.Status.Phase != common.ArgoCDStatusFailed {
+		cr.Status.Phase = common.ArgoCDStatusFailed
+		changed = true
+	} else if !failed && cr.Status.Phase == common.ArgoCDStatusFailed && cr.Spec.Schedule != nil {
+		// A scheduled export only fails until the snapshots of a later Job succeed.
+		cr.Status.Phase = "Pending"
+		changed = true
+	}
+
+	if changed {
+		cr.Status.Snapshots = snapshots
+		if err := r.client.Status().Update(context.TODO(), cr); err != nil {
+			return false, err
+		}
+	}
+	return ready && !failed, nil
+}
@@ -0,0 +1,209 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocdexport
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1b1 "k8s.io/api/batch/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/argoproj-labs/argocd-operator/pkg/apis"
+	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
+)
+
+const testNamespace = "argocd"
+
+func makeTestReconciler(t *testing.T, objs ...runtime.Object) *ReconcileArgoCDExport {
+	s := scheme.Scheme
+	assert.NilError(t, apis.AddToScheme(s))
+	// The VolumeSnapshots are only read as unstructured objects.
+	s.AddKnownTypeWithName(volumeSnapshotGVK, &unstructured.Unstructured{})
+	s.AddKnownTypeWithName(volumeSnapshotGVK.GroupVersion().WithKind("VolumeSnapshotList"), &unstructured.UnstructuredList{})
+
+	cl := fake.NewFakeClientWithScheme(s, objs...)
+	return &ReconcileArgoCDExport{
+		client: cl,
+		reader: cl,
+		scheme: s,
+	}
+}
+
+func makeTestArgoCDExport(opts ...func(*argoprojv1a1.ArgoCDExport)) *argoprojv1a1.ArgoCDExport {
+	e := &argoprojv1a1.ArgoCDExport{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "export",
+			Namespace: testNamespace,
+		},
+		Spec: argoprojv1a1.ArgoCDExportSpec{
+			Argocd: "argocd",
+			Snapshot: &argoprojv1a1.ArgoCDExportSnapshotSpec{
+				Claims: []string{"keycloak"},
+			},
+			Storage: &argoprojv1a1.ArgoCDExportStorageSpec{
+				Backend: common.ArgoCDExportStorageBackendLocal,
+			},
+		},
+	}
+	for _, o := range opts {
+		o(e)
+	}
+	return e
+}
+
+func getTestVolumeSnapshot(t *testing.T, r *ReconcileArgoCDExport, name string) *unstructured.Unstructured {
+	t.Helper()
+	snapshot := &unstructured.Unstructured{}
+	snapshot.SetGroupVersionKind(volumeSnapshotGVK)
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: name}, snapshot))
+	return snapshot
+}
+
+func setTestVolumeSnapshotStatus(t *testing.T, r *ReconcileArgoCDExport, name string, status map[string]interface{}) {
+	t.Helper()
+	snapshot := getTestVolumeSnapshot(t, r, name)
+	snapshot.Object["status"] = status
+	assert.NilError(t, r.client.Update(context.TODO(), snapshot))
+}
+
+func TestGetSnapshotClaims(t *testing.T) {
+	assert.DeepEqual(t, getSnapshotClaims(makeTestArgoCDExport()), []string{"export", "keycloak"})
+
+	cr := makeTestArgoCDExport(func(e *argoprojv1a1.ArgoCDExport) {
+		e.Spec.Snapshot.Claims = []string{"export", "keycloak"}
+	})
+	assert.DeepEqual(t, getSnapshotClaims(cr), []string{"export", "keycloak"})
+
+	cr = makeTestArgoCDExport(func(e *argoprojv1a1.ArgoCDExport) {
+		e.Spec.Snapshot = nil
+	})
+	assert.DeepEqual(t, getSnapshotClaims(cr), []string{})
+}
+
+func TestReconcileArgoCDExport_reconcileJob_snapshots(t *testing.T) {
+	cr := makeTestArgoCDExport()
+	job := newJob(cr)
+	job.Status.Succeeded = 1
+	r := makeTestReconciler(t, cr, job)
+
+	assert.NilError(t, r.reconcileJob(cr))
+	assert.Equal(t, len(cr.Status.Snapshots), 2)
+	assert.Equal(t, cr.Status.Snapshots[0].Name, "export-export")
+	assert.Equal(t, cr.Status.Snapshots[1].Name, "export-keycloak")
+	assert.Assert(t, cr.Status.Phase != common.ArgoCDStatusCompleted)
+	assert.Assert(t, isSnapshotPending(cr))
+
+	source, _, _ := unstructured.NestedString(getTestVolumeSnapshot(t, r, "export-keycloak").Object, "spec", "source", "persistentVolumeClaimName")
+	assert.Equal(t, source, "keycloak")
+
+	for _, name := range []string{"export-export", "export-keycloak"} {
+		setTestVolumeSnapshotStatus(t, r, name, map[string]interface{}{"readyToUse": true})
+	}
+
+	assert.NilError(t, r.reconcileJob(cr))
+	assert.Equal(t, cr.Status.Phase, common.ArgoCDStatusCompleted)
+	assert.Assert(t, !isSnapshotPending(cr))
+}
+
+func TestReconcileArgoCDExport_reconcileJob_snapshotFailed(t *testing.T) {
+	cr := makeTestArgoCDExport()
+	job := newJob(cr)
+	job.Status.Succeeded = 1
+	r := makeTestReconciler(t, cr, job)
+
+	assert.NilError(t, r.reconcileJob(cr))
+	setTestVolumeSnapshotStatus(t, r, "export-keycloak", map[string]interface{}{
+		"readyToUse": false,
+		"error":      map[string]interface{}{"message": "snapshot failed"},
+	})
+
+	assert.NilError(t, r.reconcileJob(cr))
+	assert.Equal(t, cr.Status.Phase, common.ArgoCDStatusFailed)
+	assert.Equal(t, cr.Status.Snapshots[1].Error, "snapshot failed")
+
+	// The export snapshot is still not ready, but a failed export is no longer requeued.
+	assert.Assert(t, !isSnapshotPending(cr))
+}
+
+func TestReconcileArgoCDExport_reconcileCronJob_snapshots(t *testing.T) {
+	schedule := "0 0 * * *"
+	cr := makeTestArgoCDExport(func(e *argoprojv1a1.ArgoCDExport) {
+		e.Spec.Schedule = &schedule
+	})
+	cj := newCronJob(cr)
+	cj.UID = "cronjob-uid"
+	cj.Spec.Schedule = schedule
+
+	newScheduledJob := func(name string, created time.Time, succeeded int32) *batchv1.Job {
+		job := &batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Namespace:         testNamespace,
+				CreationTimestamp: metav1.NewTime(created),
+			},
+		}
+		controller := true
+		job.OwnerReferences = []metav1.OwnerReference{{
+			APIVersion: batchv1b1.SchemeGroupVersion.String(),
+			Kind:       "CronJob",
+			Name:       cj.Name,
+			UID:        cj.UID,
+			Controller: &controller,
+		}}
+		job.Status.Succeeded = succeeded
+		return job
+	}
+
+	now := time.Now()
+	r := makeTestReconciler(t, cr, cj,
+		newScheduledJob("export-1", now.Add(-2*time.Hour), 1),
+		newScheduledJob("export-2", now.Add(-1*time.Hour), 1),
+		newScheduledJob("export-3", now, 0))
+
+	assert.NilError(t, r.reconcileCronJob(cr))
+	assert.Equal(t, len(cr.Status.Snapshots), 2)
+	assert.Equal(t, cr.Status.Snapshots[0].Name, "export-2-export")
+	assert.Equal(t, cr.Status.Snapshots[1].Name, "export-2-keycloak")
+	assert.Assert(t, isSnapshotPending(cr))
+
+	setTestVolumeSnapshotStatus(t, r, "export-2-keycloak", map[string]interface{}{
+		"readyToUse": false,
+		"error":      map[string]interface{}{"message": "snapshot failed"},
+	})
+	assert.NilError(t, r.reconcileCronJob(cr))
+	assert.Equal(t, cr.Status.Phase, common.ArgoCDStatusFailed)
+	assert.Assert(t, !isSnapshotPending(cr))
+
+	// The snapshots of the next scheduled Job clear the failure.
+	job := &batchv1.Job{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: "export-3"}, job))
+	job.Status.Succeeded = 1
+	assert.NilError(t, r.client.Update(context.TODO(), job))
+
+	assert.NilError(t, r.reconcileCronJob(cr))
+	assert.Equal(t, cr.Status.Snapshots[0].Name, "export-3-export")
+	assert.Assert(t, cr.Status.Phase != common.ArgoCDStatusFailed)
+	assert.Assert(t, isSnapshotPending(cr))
+}
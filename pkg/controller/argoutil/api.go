@@ -50,3 +50,32 @@ func VerifyAPI(group string, version string) (bool, error) {
 	log.Info(fmt.Sprintf("%s/%s API verified", group, version))
 	return true, nil
 }
+
+// VerifyAPIKind will verify that the given kind is served by the given group/version in the cluster.
+func VerifyAPIKind(gvk schema.GroupVersionKind) (bool, error) {
+	cfg, err := config.GetConfig()
+	if err != nil {
+		log.Error(err, "unable to get k8s config")
+		return false, err
+	}
+
+	k8s, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		log.Error(err, "unable to create k8s client")
+		return false, err
+	}
+
+	resources, err := k8s.Discovery().ServerResourcesForGroupVersion(gvk.GroupVersion().String())
+	if err != nil {
+		// error, API not available
+		return false, nil
+	}
+
+	for _, r := range resources.APIResources {
+		if r.Kind == gvk.Kind {
+			log.Info(fmt.Sprintf("%s API verified", gvk.String()))
+			return true, nil
+		}
+	}
+	return false, nil
+}
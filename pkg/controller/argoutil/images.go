@@ -0,0 +1,113 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argoutil
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
+)
+
+var (
+	// defaultImagesLock guards the cached default images.
+	defaultImagesLock sync.Mutex
+
+	// defaultImages are the images last read from the default images ConfigMap, by key.
+	defaultImages map[string]string
+
+	// defaultImagesDir is the directory the cached default images were read from.
+	defaultImagesDir string
+)
+
+// GetDefaultImagesPath will return the directory the default images ConfigMap is mounted at in the operator.
+func GetDefaultImagesPath() string {
+	if path := os.Getenv(common.ArgoCDDefaultImagesPathEnvName); path != "" {
+		return path
+	}
+	return common.ArgoCDDefaultImagesPath
+}
+
+// GetDefaultImage will return the image set for the first of the given names in the default images ConfigMap mounted
+// in the operator, or else in the first of the given environment variables that is set. The keys of the ConfigMap are
+// the names of the environment variables. The ConfigMap is read once and cached, RefreshDefaultImages picks up the
+// changes to a default image without restarting the operator.
+func GetDefaultImage(names ...string) string {
+	images := getDefaultImages()
+	for _, name := range names {
+		if img := images[name]; img != "" {
+			return img
+		}
+	}
+	for _, name := range names {
+		if e := os.Getenv(name); e != "" {
+			return e
+		}
+	}
+	return ""
+}
+
+// getDefaultImages will return the cached default images, reading them when they were not read yet from the current
+// directory of the default images ConfigMap.
+func getDefaultImages() map[string]string {
+	defaultImagesLock.Lock()
+	defer defaultImagesLock.Unlock()
+	if dir := GetDefaultImagesPath(); defaultImages == nil || defaultImagesDir != dir {
+		defaultImages, defaultImagesDir = ReadDefaultImages(), dir
+	}
+	return defaultImages
+}
+
+// RefreshDefaultImages will read the default images ConfigMap mounted in the operator again and return true when the
+// cached default images changed.
+func RefreshDefaultImages() bool {
+	dir := GetDefaultImagesPath()
+	images := ReadDefaultImages()
+
+	defaultImagesLock.Lock()
+	defer defaultImagesLock.Unlock()
+	changed := defaultImages != nil && !reflect.DeepEqual(defaultImages, images)
+	defaultImages, defaultImagesDir = images, dir
+	return changed
+}
+
+// ReadDefaultImages will return the images held by the default images ConfigMap mounted in the operator, by key. No
+// images are returned when the ConfigMap is not mounted.
+func ReadDefaultImages() map[string]string {
+	images := make(map[string]string)
+	dir := GetDefaultImagesPath()
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return images
+	}
+	for _, f := range files {
+		// The files of a mounted ConfigMap are symbolic links to its hidden ..data directory.
+		if strings.HasPrefix(f.Name(), "..") {
+			continue
+		}
+		data, err := ioutil.ReadFile(filepath.Join(dir, f.Name()))
+		if err != nil {
+			continue
+		}
+		if img := strings.TrimSpace(string(data)); img != "" {
+			images[f.Name()] = img
+		}
+	}
+	return images
+}
@@ -0,0 +1,59 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argoutil
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
+)
+
+func TestGetDefaultImage(t *testing.T) {
+	dir, err := ioutil.TempDir("", "default-images")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	defer os.Setenv(common.ArgoCDDefaultImagesPathEnvName, os.Getenv(common.ArgoCDDefaultImagesPathEnvName))
+	os.Setenv(common.ArgoCDDefaultImagesPathEnvName, dir)
+	defer os.Setenv(common.ArgoCDRedisImageEnvName, os.Getenv(common.ArgoCDRedisImageEnvName))
+	os.Setenv(common.ArgoCDRedisImageEnvName, "redis:from-env")
+
+	if img := GetDefaultImage(common.ArgoCDRelatedImageRedisEnvName, common.ArgoCDRedisImageEnvName); img != "redis:from-env" {
+		t.Errorf("GetDefaultImage() = %q, want the image from the environment", img)
+	}
+
+	// The mounted ConfigMap takes precedence over the environment, and is cached until it is refreshed.
+	if err := ioutil.WriteFile(filepath.Join(dir, common.ArgoCDRedisImageEnvName), []byte("redis:from-configmap\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if img := GetDefaultImage(common.ArgoCDRelatedImageRedisEnvName, common.ArgoCDRedisImageEnvName); img != "redis:from-env" {
+		t.Errorf("GetDefaultImage() = %q, want the cached image from the environment", img)
+	}
+	if !RefreshDefaultImages() {
+		t.Error("RefreshDefaultImages() = false, want true for the changed ConfigMap")
+	}
+	if img := GetDefaultImage(common.ArgoCDRelatedImageRedisEnvName, common.ArgoCDRedisImageEnvName); img != "redis:from-configmap" {
+		t.Errorf("GetDefaultImage() = %q, want the image from the ConfigMap", img)
+	}
+
+	if img := GetDefaultImage(common.ArgoCDDexImageEnvName); img != "" {
+		t.Errorf("GetDefaultImage() = %q, want no image", img)
+	}
+}
@@ -26,6 +26,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/version"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -51,6 +52,53 @@ func CombineImageTag(img string, tag string) string {
 	return img // No tag, use default
 }
 
+// GetImageTag will return the tag or digest of the given image, or an empty string when it has neither.
+func GetImageTag(img string) string {
+	if i := strings.Index(img, "@"); i >= 0 {
+		return img[i+1:] // Digest
+	}
+	if i := strings.LastIndex(img, ":"); i > strings.LastIndex(img, "/") {
+		return img[i+1:] // Tag
+	}
+	return ""
+}
+
+// GetArgoCDVersion will return the Argo CD version of the given Argo CD container image, or nil when the version
+// cannot be told from the tag of the image, e.g. for a digest other than the default one.
+func GetArgoCDVersion(img string) *version.Version {
+	tag := GetImageTag(img)
+	if tag == common.ArgoCDDefaultArgoVersion {
+		tag = common.ArgoCDDefaultArgoVersionName
+	}
+	v, err := version.ParseGeneric(tag)
+	if err != nil {
+		return nil
+	}
+	return v
+}
+
+// IsArgoCDVersionAtLeast will return true if the given Argo CD container image runs the given minimum version of
+// Argo CD or a later one. Images of an unknown version are assumed to be recent enough, as they were chosen on purpose.
+func IsArgoCDVersionAtLeast(img string, minimum string) bool {
+	v := GetArgoCDVersion(img)
+	return v == nil || v.AtLeast(version.MustParseGeneric(minimum))
+}
+
+// ReplaceImageRegistry will return the given image with its registry replaced by the given registry. Images
+// without a registry, such as those from Docker Hub, are prefixed with the given registry.
+func ReplaceImageRegistry(img string, registry string) string {
+	registry = strings.TrimSuffix(registry, "/")
+	if registry == "" {
+		return img
+	}
+
+	parts := strings.SplitN(img, "/", 2)
+	if len(parts) == 2 && (strings.ContainsAny(parts[0], ".:") || parts[0] == "localhost") {
+		return fmt.Sprintf("%s/%s", registry, parts[1])
+	}
+	return fmt.Sprintf("%s/%s", registry, img)
+}
+
 // CreateEvent will create a new Kubernetes Event with the given action, message, reason and involved uid.
 func CreateEvent(client client.Client, action string, message string, reason string, meta metav1.ObjectMeta) error {
 	event := newEvent(meta)
@@ -91,12 +139,21 @@ func FetchObject(client client.Client, namespace string, name string, obj runtim
 // FetchStorageSecretName will return the name of the Secret to use for the export process.
 func FetchStorageSecretName(export *argoprojv1a1.ArgoCDExport) string {
 	name := NameWithSuffix(export.ObjectMeta, "export")
-	if export.Spec.Storage != nil && len(export.Spec.Storage.SecretName) > 0 {
+	if export.Spec.Storage != nil && len(export.Spec.Storage.SecretName) > 0 && !IsStorageSecretRemote(export) {
 		name = export.Spec.Storage.SecretName
 	}
 	return name
 }
 
+// IsStorageSecretRemote will return true if the storage Secret for the export process lives in another namespace.
+// The operator keeps a copy of a remote Secret in the namespace of the export, using the default Secret name.
+func IsStorageSecretRemote(export *argoprojv1a1.ArgoCDExport) bool {
+	return export.Spec.Storage != nil &&
+		len(export.Spec.Storage.SecretName) > 0 &&
+		len(export.Spec.Storage.SecretNamespace) > 0 &&
+		export.Spec.Storage.SecretNamespace != export.Namespace
+}
+
 // IsObjectFound will perform a basic check that the given object exists via the Kubernetes API.
 // If an error occurs as part of the check, the function will return false.
 func IsObjectFound(client client.Client, namespace string, name string, obj runtime.Object) bool {
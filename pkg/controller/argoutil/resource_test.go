@@ -19,6 +19,7 @@ import (
 	"testing"
 
 	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -55,3 +56,97 @@ func TestDefaultAnnotations(t *testing.T) {
 		})
 	}
 }
+
+func TestGetImageTag(t *testing.T) {
+	tests := []struct {
+		name string
+		img  string
+		want string
+	}{
+		{"tag", "quay.io/argoproj/argocd:v1.8.1", "v1.8.1"},
+		{"digest", "quay.io/argoproj/argocd@sha256:abc", "sha256:abc"},
+		{"no tag", "quay.io/argoproj/argocd", ""},
+		{"registry port without tag", "localhost:5000/argoproj/argocd", ""},
+		{"registry port with tag", "localhost:5000/argoproj/argocd:latest", "latest"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GetImageTag(tt.img); got != tt.want {
+				t.Errorf("GetImageTag() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReplaceImageRegistry(t *testing.T) {
+	tests := []struct {
+		name     string
+		img      string
+		registry string
+		want     string
+	}{
+		{"no registry", "quay.io/dexidp/dex", "", "quay.io/dexidp/dex"},
+		{"registry host", "quay.io/dexidp/dex", "mirror.example.com", "mirror.example.com/dexidp/dex"},
+		{"registry host with port", "localhost:5000/argoproj/argocd", "mirror.example.com:8443/", "mirror.example.com:8443/argoproj/argocd"},
+		{"docker hub organization", "argoproj/argocd", "mirror.example.com/hub", "mirror.example.com/hub/argoproj/argocd"},
+		{"docker hub library", "redis", "mirror.example.com", "mirror.example.com/redis"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ReplaceImageRegistry(tt.img, tt.registry); got != tt.want {
+				t.Errorf("ReplaceImageRegistry() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFetchStorageSecretName(t *testing.T) {
+	tests := []struct {
+		name    string
+		storage *argoprojv1a1.ArgoCDExportStorageSpec
+		want    string
+	}{
+		{"no storage", nil, "foo-export"},
+		{"secret name", &argoprojv1a1.ArgoCDExportStorageSpec{SecretName: "backup"}, "backup"},
+		{"secret in export namespace", &argoprojv1a1.ArgoCDExportStorageSpec{SecretName: "backup", SecretNamespace: "bar"}, "backup"},
+		{"secret in other namespace", &argoprojv1a1.ArgoCDExportStorageSpec{SecretName: "backup", SecretNamespace: "backups"}, "foo-export"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			export := &argoprojv1a1.ArgoCDExport{
+				ObjectMeta: v1.ObjectMeta{
+					Name:      "foo",
+					Namespace: "bar",
+				},
+				Spec: argoprojv1a1.ArgoCDExportSpec{
+					Storage: tt.storage,
+				},
+			}
+			if got := FetchStorageSecretName(export); got != tt.want {
+				t.Errorf("FetchStorageSecretName() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsArgoCDVersionAtLeast(t *testing.T) {
+	tests := []struct {
+		img  string
+		want bool
+	}{
+		{"argoproj/argocd@" + common.ArgoCDDefaultArgoVersion, false},
+		{"quay.io/argoproj/argocd:v2.7.0", true},
+		{"quay.io/argoproj/argocd:v2.6.15", false},
+		{"quay.io/argoproj/argocd:v2.8.0-rc1", true},
+		{"quay.io/argoproj/argocd:2.10.1", true},
+		{"quay.io/argoproj/argocd:latest", true},
+		{"quay.io/argoproj/argocd@sha256:0123456789abcdef", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.img, func(t *testing.T) {
+			if got := IsArgoCDVersionAtLeast(tt.img, "v2.7.0"); got != tt.want {
+				t.Errorf("IsArgoCDVersionAtLeast() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
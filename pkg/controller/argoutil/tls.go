@@ -23,6 +23,7 @@ import (
 	"errors"
 	"math"
 	"math/big"
+	"net"
 	"time"
 
 	"github.com/argoproj-labs/argocd-operator/pkg/common"
@@ -94,7 +95,14 @@ func NewSelfSignedCACertificate(key *rsa.PrivateKey) (*x509.Certificate, error)
 // NewSignedCertificate signs a certificate using the given private key, CA and returns a signed certificate.
 // The certificate could be used for both client and server auth.
 // The certificate has one-year lease.
-func NewSignedCertificate(cfg *tlsutil.CertConfig, dnsNames []string, key *rsa.PrivateKey, caCert *x509.Certificate, caKey *rsa.PrivateKey) (*x509.Certificate, error) {
+func NewSignedCertificate(cfg *tlsutil.CertConfig, names []string, key *rsa.PrivateKey, caCert *x509.Certificate, caKey *rsa.PrivateKey) (*x509.Certificate, error) {
+	return NewSignedCertificateWithValidity(cfg, names, key, caCert, caKey, common.ArgoCDDuration365Days)
+}
+
+// NewSignedCertificateWithValidity signs a certificate using the given private key, CA and returns a signed
+// certificate that is valid for the given duration. The given names are added as IP address SANs when they are IP
+// addresses, and as DNS name SANs otherwise.
+func NewSignedCertificateWithValidity(cfg *tlsutil.CertConfig, names []string, key *rsa.PrivateKey, caCert *x509.Certificate, caKey *rsa.PrivateKey, validity time.Duration) (*x509.Certificate, error) {
 	serial, err := rand.Int(rand.Reader, new(big.Int).SetInt64(math.MaxInt64))
 	if err != nil {
 		return nil, err
@@ -108,15 +116,25 @@ func NewSignedCertificate(cfg *tlsutil.CertConfig, dnsNames []string, key *rsa.P
 	case tlsutil.ClientAndServingCert:
 		eku = append(eku, x509.ExtKeyUsageClientAuth, x509.ExtKeyUsageServerAuth)
 	}
+	var dnsNames []string
+	var ipAddresses []net.IP
+	for _, name := range names {
+		if ip := net.ParseIP(name); ip != nil {
+			ipAddresses = append(ipAddresses, ip)
+		} else {
+			dnsNames = append(dnsNames, name)
+		}
+	}
 	certTmpl := x509.Certificate{
 		Subject: pkix.Name{
 			CommonName:   cfg.CommonName,
 			Organization: cfg.Organization,
 		},
 		DNSNames:     dnsNames,
+		IPAddresses:  ipAddresses,
 		SerialNumber: serial,
 		NotBefore:    caCert.NotBefore,
-		NotAfter:     time.Now().Add(common.ArgoCDDuration365Days).UTC(),
+		NotAfter:     time.Now().Add(validity).UTC(),
 		KeyUsage:     x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature,
 		ExtKeyUsage:  eku,
 	}
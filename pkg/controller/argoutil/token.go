@@ -0,0 +1,224 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argoutil
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// DefaultTokenRevocationDelay is how long the previous token of a rotated ServiceAccount keeps working after its
+// successor has been propagated, so that the consumers of the token pick up the new one without an outage.
+const DefaultTokenRevocationDelay = 5 * time.Minute
+
+// NewClientFromKubeconfig returns a client for the cluster of the current context of the given kubeconfig data.
+func NewClientFromKubeconfig(kubeconfig []byte) (client.Client, error) {
+	cfg, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	return client.New(cfg, client.Options{})
+}
+
+// TokenRotation rotates the token Secrets of a ServiceAccount without an outage. A new token Secret is created once
+// the rotation interval has elapsed, the newest populated token is propagated to its consumers, and the previous
+// token Secrets are only deleted, which revokes their tokens, once the revocation delay has elapsed after the
+// propagation of their successor.
+type TokenRotation struct {
+	// Client is the client for the cluster of the ServiceAccount.
+	Client client.Client
+
+	// Interval is how often the token is regenerated, the token is not rotated when zero.
+	Interval time.Duration
+
+	// Labels identify the token Secrets of the rotation and are set on the token Secrets that are created.
+	Labels map[string]string
+
+	// Name is the name of the first token Secret, the names of the following token Secrets have a timestamp suffix.
+	Name string
+
+	// Namespace is the namespace of the ServiceAccount.
+	Namespace string
+
+	// RevocationDelay is how long a previous token keeps working after its successor has been propagated.
+	RevocationDelay time.Duration
+
+	// ServiceAccount is the name of the ServiceAccount.
+	ServiceAccount string
+
+	// SetOwner is called with each new token Secret before it is created, when set.
+	SetOwner func(secret *corev1.Secret) error
+}
+
+// TokenRotationStatus is the observed state of a TokenRotation.
+type TokenRotationStatus struct {
+	// Last is the time the current token Secret was created.
+	Last *metav1.Time
+
+	// Next is the time the token is due to be regenerated, nil when the token is not rotated.
+	Next *metav1.Time
+
+	// Revocation is the time the previous tokens are revoked, nil when no revocation is pending.
+	Revocation *metav1.Time
+}
+
+// listTokenSecrets returns the token Secrets of the rotation, oldest first.
+func (t *TokenRotation) listTokenSecrets() ([]corev1.Secret, error) {
+	list := &corev1.SecretList{}
+	if err := t.Client.List(context.TODO(), list, client.InNamespace(t.Namespace), client.MatchingLabels(t.Labels)); err != nil {
+		return nil, err
+	}
+
+	secrets := make([]corev1.Secret, 0, len(list.Items))
+	for _, secret := range list.Items {
+		if secret.Annotations[corev1.ServiceAccountNameKey] == t.ServiceAccount {
+			secrets = append(secrets, secret)
+		}
+	}
+	sort.SliceStable(secrets, func(i, j int) bool {
+		a, b := secrets[i].CreationTimestamp, secrets[j].CreationTimestamp
+		if a.IsZero() != b.IsZero() {
+			return b.IsZero() // A Secret without a creation time has just been created
+		}
+		if !a.Equal(&b) {
+			return a.Before(&b)
+		}
+		return secrets[i].Name < secrets[j].Name
+	})
+	return secrets, nil
+}
+
+// isDue returns true if the given token Secret is due to be regenerated.
+func (t *TokenRotation) isDue(secret *corev1.Secret, now time.Time) bool {
+	if t.Interval <= 0 || secret.CreationTimestamp.IsZero() {
+		return false
+	}
+	return now.Sub(secret.CreationTimestamp.Time) >= t.Interval
+}
+
+// createTokenSecret creates a new token Secret for the ServiceAccount.
+func (t *TokenRotation) createTokenSecret(first bool, now time.Time) (*corev1.Secret, error) {
+	name := t.Name
+	if !first {
+		name = fmt.Sprintf("%s-%d", t.Name, now.Unix())
+	}
+
+	labels := make(map[string]string, len(t.Labels))
+	for k, v := range t.Labels {
+		labels[k] = v
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{
+				corev1.ServiceAccountNameKey: t.ServiceAccount,
+			},
+			Labels:    labels,
+			Name:      name,
+			Namespace: t.Namespace,
+		},
+		Type: corev1.SecretTypeServiceAccountToken,
+	}
+	if t.SetOwner != nil {
+		if err := t.SetOwner(secret); err != nil {
+			return nil, err
+		}
+	}
+
+	log.Info(fmt.Sprintf("creating token secret %s for service account %s in namespace %s", name, t.ServiceAccount, t.Namespace))
+	if err := t.Client.Create(context.TODO(), secret); err != nil {
+		return nil, err
+	}
+	return secret, nil
+}
+
+// Reconcile will ensure that a current token Secret is present for the ServiceAccount, call propagate with the newest
+// token Secret whose token has been populated and revoke the previous tokens once the revocation delay has elapsed.
+func (t *TokenRotation) Reconcile(propagate func(secret *corev1.Secret) error) (*TokenRotationStatus, error) {
+	now := time.Now()
+	secrets, err := t.listTokenSecrets()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(secrets) == 0 || t.isDue(&secrets[len(secrets)-1], now) {
+		secret, err := t.createTokenSecret(len(secrets) == 0, now)
+		if err != nil {
+			return nil, err
+		}
+		secrets = append(secrets, *secret)
+	}
+
+	status := &TokenRotationStatus{}
+	last := secrets[len(secrets)-1].CreationTimestamp
+	if last.IsZero() {
+		last = metav1.NewTime(now)
+	}
+	status.Last = &last
+	if t.Interval > 0 {
+		next := metav1.NewTime(last.Add(t.Interval))
+		status.Next = &next
+	}
+
+	current := -1
+	for i := len(secrets) - 1; i >= 0; i-- {
+		if len(secrets[i].Data[corev1.ServiceAccountTokenKey]) > 0 {
+			current = i
+			break
+		}
+	}
+	if current < 0 {
+		return status, nil // No token populated yet
+	}
+
+	secret := &secrets[current]
+	if err := propagate(secret); err != nil {
+		return status, err
+	}
+
+	propagated, err := time.Parse(time.RFC3339, secret.Annotations[common.ArgoCDTokenPropagatedAnnotation])
+	if err != nil {
+		propagated = now
+		secret.Annotations = AppendStringMap(secret.Annotations, map[string]string{
+			common.ArgoCDTokenPropagatedAnnotation: propagated.UTC().Format(time.RFC3339),
+		})
+		if err := t.Client.Update(context.TODO(), secret); err != nil {
+			return status, err
+		}
+	}
+
+	revocation := propagated.Add(t.RevocationDelay)
+	for i := 0; i < current; i++ {
+		if now.Before(revocation) {
+			at := metav1.NewTime(revocation)
+			status.Revocation = &at
+			continue
+		}
+
+		log.Info(fmt.Sprintf("revoking token secret %s of service account %s in namespace %s", secrets[i].Name, t.ServiceAccount, t.Namespace))
+		if err := t.Client.Delete(context.TODO(), &secrets[i]); err != nil && !apierrors.IsNotFound(err) {
+			return status, err
+		}
+	}
+	return status, nil
+}
@@ -39,6 +39,7 @@ func reconcilerHook(cr *argoprojv1alpha1.ArgoCD, v interface{}, hint string) err
 		} else if o.ObjectMeta.Name == cr.ObjectMeta.Name+"-redis-ha-haproxy" {
 			logv.Info("configuring openshift redis haproxy")
 			o.Spec.Template.Spec.Containers[0].Command = append(getCommandForRedhatRedisHaProxy(), o.Spec.Template.Spec.Containers[0].Command...)
+			setRestrictedSecurityContext(&o.Spec.Template.Spec)
 		}
 	case *[]rbacv1.PolicyRule:
 		if hint == "policyRuleForRedisHa" {
@@ -59,6 +60,7 @@ func reconcilerHook(cr *argoprojv1alpha1.ArgoCD, v interface{}, hint string) err
 			}
 			o.Spec.Template.Spec.InitContainers[0].Args = getArgsForRedhatHaRedisInitContainer()
 			o.Spec.Template.Spec.InitContainers[0].Command = []string{}
+			setRestrictedSecurityContext(&o.Spec.Template.Spec)
 		}
 	case *corev1.Secret:
 		if allowedNamespace(cr.ObjectMeta.Namespace, os.Getenv("ARGOCD_CLUSTER_CONFIG_NAMESPACES")) {
@@ -124,6 +126,34 @@ func getPolicyRuleForApplicationController() []rbacv1.PolicyRule {
 	}
 }
 
+// setRestrictedSecurityContext adjusts the given pod spec to be admitted by the restricted
+// SecurityContextConstraints. The user and group are left to be assigned from the range of the
+// namespace, and all containers run without privilege escalation or additional capabilities.
+func setRestrictedSecurityContext(spec *corev1.PodSpec) {
+	runAsNonRoot := true
+	spec.SecurityContext = &corev1.PodSecurityContext{
+		RunAsNonRoot: &runAsNonRoot,
+	}
+	for i := range spec.InitContainers {
+		spec.InitContainers[i].SecurityContext = getRestrictedContainerSecurityContext()
+	}
+	for i := range spec.Containers {
+		spec.Containers[i].SecurityContext = getRestrictedContainerSecurityContext()
+	}
+}
+
+// getRestrictedContainerSecurityContext returns a container security context that is allowed
+// by the restricted SecurityContextConstraints.
+func getRestrictedContainerSecurityContext() *corev1.SecurityContext {
+	allowPrivilegeEscalation := false
+	return &corev1.SecurityContext{
+		AllowPrivilegeEscalation: &allowPrivilegeEscalation,
+		Capabilities: &corev1.Capabilities{
+			Drop: []corev1.Capability{"ALL"},
+		},
+	}
+}
+
 // For OpenShift, we use a custom build of Redis provided by Red Hat
 // which requires additional args in comparison to stock redis.
 func getArgsForRedhatRedis() []string {
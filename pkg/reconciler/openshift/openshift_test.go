@@ -136,6 +136,7 @@ func TestReconcileArgoCD_reconcileRedisHaProxyDeployment(t *testing.T) {
 	assert.NilError(t, reconcilerHook(a, testDeployment, ""))
 	assert.DeepEqual(t, testDeployment.Spec.Template.Spec.Containers[0].Command, want)
 	assert.Equal(t, 0, len(testDeployment.Spec.Template.Spec.Containers[0].Args))
+	assert.DeepEqual(t, testDeployment.Spec.Template.Spec.Containers[0].SecurityContext, getRestrictedContainerSecurityContext())
 
 	testDeployment = makeTestDeployment()
 	testDeployment.ObjectMeta.Name = a.Name + "-" + "not-redis-ha-haproxy"
@@ -164,6 +165,15 @@ func TestReconcileArgoCD_reconcileRedisHaServerStatefulSet(t *testing.T) {
 	assert.DeepEqual(t, s.Spec.Template.Spec.InitContainers[0].Args, getArgsForRedhatHaRedisInitContainer())
 	assert.Equal(t, 0, len(s.Spec.Template.Spec.InitContainers[0].Command))
 
+	// Check that the pods fit the restricted SecurityContextConstraints
+	assert.Assert(t, s.Spec.Template.Spec.SecurityContext.RunAsUser == nil)
+	assert.Assert(t, s.Spec.Template.Spec.SecurityContext.FSGroup == nil)
+	assert.Assert(t, *s.Spec.Template.Spec.SecurityContext.RunAsNonRoot)
+	assert.DeepEqual(t, s.Spec.Template.Spec.InitContainers[0].SecurityContext, getRestrictedContainerSecurityContext())
+	for _, c := range s.Spec.Template.Spec.Containers {
+		assert.DeepEqual(t, c.SecurityContext, getRestrictedContainerSecurityContext())
+	}
+
 	s = newStatefulSetWithSuffix("not-redis-ha-server", "redis", a)
 
 	want0 := s.Spec.Template.Spec.Containers[0].Args
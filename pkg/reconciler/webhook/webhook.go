@@ -0,0 +1,163 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"time"
+
+	argoproj "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/pkg/controller/argocd"
+)
+
+const (
+	// DefaultTimeout is the default time the webhook is given to answer for each object.
+	DefaultTimeout = 10 * time.Second
+
+	// maxTimeout bounds the time the webhook is given to answer, as the reconciliation of the ArgoCD waits on it.
+	maxTimeout = time.Minute
+)
+
+// ArgoCDReference identifies the ArgoCD an object is generated for.
+type ArgoCDReference struct {
+	// Name is the name of the ArgoCD.
+	Name string `json:"name"`
+
+	// Namespace is the namespace of the ArgoCD.
+	Namespace string `json:"namespace"`
+}
+
+// Request is the body posted to the webhook for each object generated by the operator. Only the reference of the
+// ArgoCD is sent with the object, so that the webhook is not given the rest of its spec.
+type Request struct {
+	// ArgoCD is the reference of the ArgoCD the object is generated for.
+	ArgoCD ArgoCDReference `json:"argocd"`
+
+	// Kind is the kind of the object, e.g. Deployment. It is empty for objects that are not Kubernetes resources, such
+	// as the list of policy rules given with the hint policyRuleForRedisHa.
+	Kind string `json:"kind,omitempty"`
+
+	// Hint tells apart the objects of the same kind.
+	Hint string `json:"hint,omitempty"`
+
+	// Object is the generated object.
+	Object interface{} `json:"object"`
+}
+
+// Response is the body returned by the webhook.
+type Response struct {
+	// Object is the changed object, which replaces the generated object. The generated object is kept when empty.
+	Object json.RawMessage `json:"object,omitempty"`
+}
+
+type webhook struct {
+	url    string
+	client *http.Client
+}
+
+// NewHook will return a reconciler hook that posts each generated object to the webhook at the given https URL, and
+// replaces the object with the one returned by the webhook. The certificate of the webhook is verified with the CA
+// certificates in the given file, or with the CA certificates of the system when no file is given. Each call fails when
+// the webhook does not answer within the given timeout.
+func NewHook(webhookURL, caFile string, timeout time.Duration) (argocd.Hook, error) {
+	if timeout <= 0 || timeout > maxTimeout {
+		return nil, fmt.Errorf("reconciler webhook timeout %s must be positive and at most %s", timeout, maxTimeout)
+	}
+
+	u, err := url.Parse(webhookURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid reconciler webhook URL: %w", err)
+	}
+	if u.Scheme != "https" || u.Host == "" {
+		return nil, fmt.Errorf("reconciler webhook URL %s must be an https URL", webhookURL)
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if caFile != "" {
+		ca, err := ioutil.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read reconciler webhook CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(ca) {
+			return nil, fmt.Errorf("no CA certificates found in reconciler webhook CA file %s", caFile)
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool}
+	}
+
+	w := &webhook{url: u.String(), client: &http.Client{Transport: transport, Timeout: timeout}}
+	return w.hook, nil
+}
+
+func (w *webhook) hook(cr *argoproj.ArgoCD, i interface{}, hint string) error {
+	v := reflect.ValueOf(i)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return fmt.Errorf("reconciler webhook cannot change a %T", i)
+	}
+
+	body, err := json.Marshal(Request{
+		ArgoCD: ArgoCDReference{Name: cr.Name, Namespace: cr.Namespace},
+		Kind:   v.Elem().Type().Name(),
+		Hint:   hint,
+		Object: i,
+	})
+	if err != nil {
+		return err
+	}
+
+	resp, err := w.client.Post(w.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to call reconciler webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read reconciler webhook response: %w", err)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusNoContent:
+		return nil
+	case http.StatusOK:
+	default:
+		return fmt.Errorf("reconciler webhook returned %s: %s", resp.Status, strings.TrimSpace(string(data)))
+	}
+
+	response := Response{}
+	if err := json.Unmarshal(data, &response); err != nil {
+		return fmt.Errorf("invalid reconciler webhook response: %w", err)
+	}
+	if len(response.Object) == 0 || string(response.Object) == "null" {
+		return nil
+	}
+
+	// Decode into a new object, so that the fields removed by the webhook are removed from the generated object too.
+	changed := reflect.New(v.Elem().Type())
+	if err := json.Unmarshal(response.Object, changed.Interface()); err != nil {
+		return fmt.Errorf("invalid object in reconciler webhook response: %w", err)
+	}
+	v.Elem().Set(changed.Elem())
+	return nil
+}
@@ -0,0 +1,147 @@
+package webhook
+
+import (
+	"encoding/json"
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	argoproj "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+)
+
+func makeTestArgoCD() *argoproj.ArgoCD {
+	return &argoproj.ArgoCD{ObjectMeta: metav1.ObjectMeta{Name: "argocd", Namespace: "argocd"}}
+}
+
+func makeTestDeployment() *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "argocd-server", Namespace: "argocd"},
+		Spec: appsv1.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{"example.com/remove": "true"}},
+			},
+		},
+	}
+}
+
+// newTestServer will return a webhook server that answers each request with the given handler, and records the
+// received requests.
+func newTestServer(t *testing.T, handler func(req Request, w http.ResponseWriter)) (*httptest.Server, *[]Request) {
+	received := []Request{}
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := Request{}
+		assert.NilError(t, json.NewDecoder(r.Body).Decode(&req))
+		received = append(received, req)
+		handler(req, w)
+	}))
+	t.Cleanup(server.Close)
+	return server, &received
+}
+
+// writeTestCAFile will write the certificate of the given server to a file, and return the path of the file.
+func writeTestCAFile(t *testing.T, server *httptest.Server) string {
+	caFile := filepath.Join(t.TempDir(), "ca.crt")
+	ca := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	assert.NilError(t, ioutil.WriteFile(caFile, ca, 0600))
+	return caFile
+}
+
+func TestHook_changesObject(t *testing.T) {
+	server, received := newTestServer(t, func(req Request, w http.ResponseWriter) {
+		deploy := makeTestDeployment()
+		deploy.Spec.Template.Annotations = nil
+		deploy.Spec.Template.Spec.NodeSelector = map[string]string{"example.com/pool": "argocd"}
+		assert.NilError(t, json.NewEncoder(w).Encode(map[string]interface{}{"object": deploy}))
+	})
+	hook, err := NewHook(server.URL, writeTestCAFile(t, server), DefaultTimeout)
+	assert.NilError(t, err)
+
+	deploy := makeTestDeployment()
+	assert.NilError(t, hook(makeTestArgoCD(), deploy, "server"))
+
+	assert.Equal(t, len(*received), 1)
+	assert.DeepEqual(t, (*received)[0].ArgoCD, ArgoCDReference{Name: "argocd", Namespace: "argocd"})
+	assert.Equal(t, (*received)[0].Kind, "Deployment")
+	assert.Equal(t, (*received)[0].Hint, "server")
+	assert.DeepEqual(t, deploy.Spec.Template.Spec.NodeSelector, map[string]string{"example.com/pool": "argocd"})
+	assert.Equal(t, len(deploy.Spec.Template.Annotations), 0)
+}
+
+func TestHook_keepsObject(t *testing.T) {
+	for _, handler := range []func(req Request, w http.ResponseWriter){
+		func(req Request, w http.ResponseWriter) { w.WriteHeader(http.StatusNoContent) },
+		func(req Request, w http.ResponseWriter) { w.Write([]byte("{}")) },
+	} {
+		server, _ := newTestServer(t, handler)
+		hook, err := NewHook(server.URL, writeTestCAFile(t, server), DefaultTimeout)
+		assert.NilError(t, err)
+
+		deploy := makeTestDeployment()
+		assert.NilError(t, hook(makeTestArgoCD(), deploy, ""))
+		assert.DeepEqual(t, deploy, makeTestDeployment())
+	}
+}
+
+func TestHook_policyRules(t *testing.T) {
+	server, received := newTestServer(t, func(req Request, w http.ResponseWriter) {
+		rules := []rbacv1.PolicyRule{{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"get"}}}
+		assert.NilError(t, json.NewEncoder(w).Encode(map[string]interface{}{"object": rules}))
+	})
+	hook, err := NewHook(server.URL, writeTestCAFile(t, server), DefaultTimeout)
+	assert.NilError(t, err)
+
+	rules := []rbacv1.PolicyRule{}
+	assert.NilError(t, hook(makeTestArgoCD(), &rules, "policyRuleForRedisHa"))
+	assert.Equal(t, (*received)[0].Kind, "")
+	assert.Equal(t, len(rules), 1)
+	assert.Equal(t, rules[0].Resources[0], "pods")
+}
+
+func TestHook_errors(t *testing.T) {
+	server, _ := newTestServer(t, func(req Request, w http.ResponseWriter) {
+		http.Error(w, "denied", http.StatusForbidden)
+	})
+	hook, err := NewHook(server.URL, writeTestCAFile(t, server), DefaultTimeout)
+	assert.NilError(t, err)
+
+	deploy := makeTestDeployment()
+	assert.ErrorContains(t, hook(makeTestArgoCD(), deploy, ""), "denied")
+	assert.DeepEqual(t, deploy, makeTestDeployment())
+
+	assert.ErrorContains(t, hook(makeTestArgoCD(), *deploy, ""), "cannot change")
+
+	_, err = NewHook(server.URL, "/does/not/exist", DefaultTimeout)
+	assert.Assert(t, err != nil)
+}
+
+func TestNewHook_requiresHTTPS(t *testing.T) {
+	for _, webhookURL := range []string{"http://argocd-customizer.argocd-operator.svc/mutate", "argocd-customizer/mutate", "https:///mutate"} {
+		_, err := NewHook(webhookURL, "", DefaultTimeout)
+		assert.ErrorContains(t, err, "must be an https URL")
+	}
+}
+
+func TestNewHook_timeout(t *testing.T) {
+	server, _ := newTestServer(t, func(req Request, w http.ResponseWriter) {
+		time.Sleep(200 * time.Millisecond)
+		w.WriteHeader(http.StatusNoContent)
+	})
+	hook, err := NewHook(server.URL, writeTestCAFile(t, server), 50*time.Millisecond)
+	assert.NilError(t, err)
+	assert.ErrorContains(t, hook(makeTestArgoCD(), makeTestDeployment(), ""), "failed to call reconciler webhook")
+
+	for _, timeout := range []time.Duration{0, -time.Second, 2 * time.Minute} {
+		_, err := NewHook(server.URL, "", timeout)
+		assert.ErrorContains(t, err, "timeout")
+	}
+}